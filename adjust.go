@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/models"
+	"log"
+	"os"
+	"time"
+)
+
+// runAdjust re-adjusts the candles archived in CANDLE_DIR for splits and dividends announced since they were saved
+// Symbols come from the arguments, or from the filtered stock list when none are given
+func runAdjust(args []string) int {
+	var symbols []string
+	for len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		symbols, args = append(symbols, args[0]), args[1:]
+	}
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.CandleDir == "" {
+		log.Printf("CANDLE_DIR is required to adjust archived candles")
+		return exitConfigError
+	}
+	if len(symbols) == 0 {
+		stockData, err := data.NewStockListLoader().LoadStocksFromPatterns(cfg.StocksFile)
+		if err != nil {
+			log.Println("Failed to load stocks:", err)
+			return exitConfigError
+		}
+		stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+		if err != nil {
+			log.Println("Failed to build stock filter:", err)
+			return exitConfigError
+		}
+		for _, stock := range stockFilter.Apply(stockData).Stocks {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+
+	candleStore := data.NewCandleStore(cfg.CandleDir, cfg.Timeframe)
+	stockFetcher := data.NewStockDataFetcher(cfg.APIKey, cfg.APIURL, cfg.Timeframe)
+	stockFetcher.SetHTTPClient(httpClient(cfg))
+	stockFetcher.SetRetryPolicy(retryPolicy(cfg))
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+	checked, adjusted, failed := 0, 0, 0
+	for _, symbol := range symbols {
+		if _, err := candleStore.Load(symbol); errors.Is(err, os.ErrNotExist) {
+			continue // Nothing archived, so nothing to adjust
+		}
+		checked++
+		if stockFetcher.RequestCount() > 0 && cfg.RequestDelay > 0 {
+			time.Sleep(cfg.RequestDelay) // Respect API limits between symbols
+		}
+		actions, err := stockFetcher.FetchCorporateActions(symbol)
+		if err == nil {
+			var adjustments []models.Adjustment
+			if adjustments, err = candleStore.ApplyCorporateActions(symbol, actions); err == nil && len(adjustments) > 0 {
+				adjusted++
+				for _, adjustment := range adjustments {
+					log.Printf("🔧 %s: applied %s of %s (price factor %.6f)", symbol, adjustment.Kind,
+						adjustment.ExDate.Format("2006-01-02"), adjustment.PriceFactor)
+				}
+			}
+		}
+		if err != nil {
+			failed++
+			log.Printf("⚠️  Could not adjust %s: %v", symbol, err)
+		}
+	}
+
+	log.Printf("✅ Adjusted %d of %d archived symbols (%d failed, %d API requests)", adjusted, checked, failed, stockFetcher.RequestCount())
+	if failed > 0 && failed == checked {
+		return exitProviderError
+	}
+	return exitOK
+}
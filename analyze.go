@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// runAnalyze fetches one symbol and prints every indicator value and the measured outcome of every SAPAN rule for both sides
+// Unlike a scan, the Short side is evaluated even when the Long setup is valid, and rules after a failed one are still checked
+func runAnalyze(args []string) int {
+	symbol, flags := splitSymbol(args)
+	if symbol == "" {
+		fmt.Fprintln(os.Stderr, "Usage: sapan analyze SYMBOL [flags]")
+		return exitConfigError
+	}
+	cfg, code, ok := loadConfig(flags)
+	if !ok {
+		return code
+	}
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return exitConfigError
+	}
+
+	sapanStrategy, stockFetcher := newStrategy(cfg), newProviderFetcher(cfg)
+	if !resolveOutputSize(cfg, sapanStrategy, stockFetcher.Capabilities()) {
+		return exitConfigError
+	}
+	if err := stockFetcher.Capabilities().Check(cfg.Timeframe, cfg.OutputSize); err != nil {
+		log.Printf("Unsupported provider settings: %v", err)
+		return exitConfigError
+	}
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+	ctx, cancel := fetchContext(cfg)
+	defer cancel()
+	candleData, err := stockFetcher.FetchStockData(ctx, symbol, cfg.OutputSize)
+	if err != nil {
+		log.Printf("Failed to fetch data for %s: %v", symbol, err)
+		return exitProviderError
+	}
+	// Drop the still-forming candle so the analysis matches what a scan would see
+	candles := marketCalendar.ClosedCandles(candleData.Candles, time.Now())
+	if len(candles) == 0 {
+		log.Printf("No closed candles returned for %s", symbol)
+		return exitProviderError
+	}
+
+	diagnoses := []strategy.Diagnosis{
+		sapanStrategy.Diagnose(symbol, candles, strategy.LongScenario),
+		sapanStrategy.Diagnose(symbol, candles, strategy.ShortScenario),
+	}
+
+	last := candles[len(candles)-1]
+	fmt.Printf("%s: %d closed %s candles, last close %.4f on %s\n", symbol, len(candles), cfg.Timeframe,
+		last.Close, last.Date.Format("2006-01-02"))
+	if len(diagnoses[0].Rules) == 0 {
+		fmt.Printf("  %s\n", diagnoses[0].Result.ValidationMessage)
+	} else {
+		printIndicators(diagnoses[0].Snapshot, cfg.StochRSILengths)
+		for _, diagnosis := range diagnoses {
+			printDiagnosis(diagnosis)
+		}
+	}
+
+	found := false
+	for _, diagnosis := range diagnoses {
+		result := diagnosis.Result
+		if !result.IsValid {
+			continue
+		}
+		found = true
+		plan := result.TradePlan
+		fmt.Printf("✅ SAPAN %s setup: %s, score %.1f, entry %.4f, stop %.4f, target %.4f (R:R %.2f)\n",
+			diagnosis.Scenario, result.PatternType, result.Score, plan.Entry, plan.Stop, plan.Target, plan.RiskReward())
+	}
+	if !found {
+		fmt.Println("❌ No valid SAPAN setups detected")
+		return exitOK
+	}
+	if cfg.SignalExitCode != 0 {
+		return cfg.SignalExitCode
+	}
+	return exitOK
+}
+
+// printIndicators prints the indicator values the SAPAN rules are decided on
+// lengths are the configured Stochastic RSI lengths: RSI, stochastic, %K smoothing, and %D smoothing
+func printIndicators(snapshot strategy.IndicatorSnapshot, lengths []int) {
+	macd := snapshot.MACD.Last()
+	fmt.Println("Indicators")
+	emas := make([]string, len(snapshot.EMAs))
+	for i, ema := range snapshot.EMAs {
+		emas[i] = models.FormatPrice(ema)
+	}
+	fmt.Printf("  %-28s%s\n", fmt.Sprintf("EMA %s:", strategy.FormatEMAStack(snapshot.EMAPeriods)), strings.Join(emas, " / "))
+	if len(snapshot.RSI) > 0 {
+		fmt.Printf("  %-28s%.2f\n", fmt.Sprintf("RSI (%d):", lengths[0]), snapshot.RSI[len(snapshot.RSI)-1])
+	}
+	fmt.Printf("  %-28sK=%.2f D=%.2f crossover=%t\n", fmt.Sprintf("Stochastic RSI (%d,%d,%d,%d):", lengths[0], lengths[1], lengths[2], lengths[3]),
+		snapshot.StochRSI.K, snapshot.StochRSI.D, snapshot.StochRSI.Crossover)
+	fmt.Printf("  MACD (50,100,9):            MACD=%.4f signal=%.4f histogram=%.4f\n", macd.MACD, macd.Signal, macd.Histogram)
+}
+
+// printDiagnosis prints the measured rules, the detected or nearest pattern, and the levels of one side
+func printDiagnosis(diagnosis strategy.Diagnosis) {
+	fmt.Printf("%s: %s\n", diagnosis.Scenario, diagnosis.Result.ValidationMessage)
+	for _, rule := range diagnosis.Rules {
+		if rule.Passed {
+			fmt.Printf("  ✔ %-8s %s (needs %s)\n", rule.Name, rule.Measured, rule.Needs)
+		} else {
+			fmt.Printf("  ✘ %-8s %s — %s (needs %s)\n", rule.Name, rule.Measured, rule.Gap, rule.Needs)
+		}
+	}
+	if nearest := diagnosis.NearestPattern(); len(nearest.Conditions) > 0 {
+		fmt.Printf("  %s conditions:\n", nearest.Pattern)
+		for _, condition := range nearest.Conditions {
+			fmt.Printf("    %s %-24s %s (needs %s)\n", ruleMark(condition.Passed), condition.Name, condition.Measured, condition.Needs)
+		}
+	}
+	label, plan := "Levels", diagnosis.TradePlan
+	if !diagnosis.Result.IsValid {
+		label = "Levels if it triggered now"
+	}
+	timeStop := ""
+	if plan.TimeStop > 0 {
+		timeStop = fmt.Sprintf(", time stop %d candles", plan.TimeStop)
+	}
+	fmt.Printf("  %s: entry %s, stop %s, target %s (R:R %.2f)%s\n", label, models.FormatPrice(plan.Entry),
+		models.FormatPrice(plan.Stop), models.FormatPrice(plan.Target), plan.RiskReward(), timeStop)
+}
+
+// ruleMark renders a rule outcome the way the scan's rule detail does
+func ruleMark(passed bool) string {
+	if passed {
+		return "✔"
+	}
+	return "✘"
+}
+
+// splitSymbol separates the leading SYMBOL argument from the configuration flags that follow it
+func splitSymbol(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", args
+	}
+	return strings.ToUpper(args[0]), args[1:]
+}
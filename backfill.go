@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"log"
+	"time"
+)
+
+// runBackfill downloads the full candle history of the configured universe into CANDLE_DIR ahead of backtests
+// Symbols already backfilled from the same date are skipped, so a run stopped by the API quota resumes where it ended
+func runBackfill(args []string) int {
+	fromText, args := takeFlag(args, "from")
+	var symbols []string
+	for len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		symbols, args = append(symbols, args[0]), args[1:]
+	}
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.CandleDir == "" {
+		log.Printf("CANDLE_DIR is required to backfill the candle archive")
+		return exitConfigError
+	}
+	var from time.Time
+	if fromText != "" {
+		parsed, err := time.Parse("2006-01-02", fromText)
+		if err != nil {
+			log.Printf("Invalid backfill date %q (expected YYYY-MM-DD)", fromText)
+			return exitConfigError
+		}
+		from = parsed
+	}
+
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return exitConfigError
+	}
+	if len(symbols) == 0 {
+		stockData, err := data.NewStockListLoader().LoadStocksFromPatterns(cfg.StocksFile)
+		if err != nil {
+			log.Println("Failed to load stocks:", err)
+			return exitConfigError
+		}
+		stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+		if err != nil {
+			log.Println("Failed to build stock filter:", err)
+			return exitConfigError
+		}
+		for _, stock := range stockFilter.Apply(stockData).Stocks {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+
+	candleStore := data.NewCandleStore(cfg.CandleDir, cfg.Timeframe)
+	var pending []string
+	for _, symbol := range symbols {
+		if !candleStore.Backfilled(symbol, from) {
+			pending = append(pending, symbol)
+		}
+	}
+	since := "the first listed candle"
+	if !from.IsZero() {
+		since = from.Format("2006-01-02")
+	}
+	log.Printf("📥 Backfilling %d symbols since %s (%d already backfilled)", len(pending), since, len(symbols)-len(pending))
+
+	stockFetcher := newProviderFetcher(cfg)
+	if err := stockFetcher.Capabilities().Check(cfg.Timeframe, 0); err != nil {
+		log.Printf("Unsupported provider settings: %v", err)
+		return exitConfigError
+	}
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+	if remaining := quota.Remaining(); remaining >= 0 && remaining < len(pending) {
+		log.Printf("⚠️  Only %d API requests left today for %d symbols; rerun the backfill tomorrow to finish", remaining, len(pending))
+	}
+
+	backfilled, failed, attempted := 0, 0, 0
+	for _, symbol := range pending {
+		if stockFetcher.RequestCount() > 0 && cfg.RequestDelay > 0 {
+			time.Sleep(cfg.RequestDelay) // Respect API limits between symbols
+		}
+		candleData, err := stockFetcher.FetchHistory(symbol, from)
+		if errors.Is(err, data.ErrQuotaExhausted) {
+			log.Printf("⏸️  API quota used up with %d symbols left; rerun the backfill to resume", len(pending)-attempted)
+			break
+		}
+		attempted++
+		if err == nil {
+			if !data.IsIntradayTimeframe(cfg.Timeframe) {
+				candleData.Candles = marketCalendar.ClosedCandles(candleData.Candles, time.Now())
+			}
+			err = candleStore.SaveHistory(symbol, from, candleData)
+		}
+		if err != nil {
+			failed++
+			log.Printf("⚠️  Could not backfill %s: %v", symbol, err)
+			continue
+		}
+		backfilled++
+		if n := len(candleData.Candles); n > 0 {
+			log.Printf("💾 %s: %d candles from %s", symbol, n, candleData.Candles[0].Date.Format("2006-01-02"))
+		}
+	}
+
+	log.Printf("✅ Backfilled %d of %d symbols (%d failed, %d left, %d API requests)", backfilled, len(pending), failed,
+		len(pending)-attempted, stockFetcher.RequestCount())
+	if len(pending) > 0 && backfilled == 0 {
+		return exitProviderError
+	}
+	return exitOK
+}
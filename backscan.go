@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/backscan"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/models"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runBackscan reports per symbol how many SAPAN setups occurred over the candles archived in CANDLE_DIR and
+// their forward returns; --horizons sets the candles after the signal the returns are measured at (default 5,10,20)
+// It runs offline: symbols without an archive are listed and skipped, not fetched
+func runBackscan(args []string) int {
+	horizonsText, args := takeFlag(args, "horizons")
+	var symbols []string
+	for len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		symbols, args = append(symbols, args[0]), args[1:]
+	}
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.CandleDir == "" {
+		log.Printf("CANDLE_DIR is required to backscan archived candles")
+		return exitConfigError
+	}
+	horizons := backscan.DefaultHorizons
+	if horizonsText != "" {
+		horizons = nil
+		for _, field := range strings.Split(horizonsText, ",") {
+			bars, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || bars < 1 {
+				log.Printf("Invalid backscan horizon %q (expected a positive candle count)", field)
+				return exitConfigError
+			}
+			horizons = append(horizons, bars)
+		}
+	}
+
+	if len(symbols) == 0 {
+		stockData, err := data.NewStockListLoader().LoadStocksFromPatterns(cfg.StocksFile)
+		if err != nil {
+			log.Println("Failed to load stocks:", err)
+			return exitConfigError
+		}
+		stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+		if err != nil {
+			log.Println("Failed to build stock filter:", err)
+			return exitConfigError
+		}
+		for _, stock := range stockFilter.Apply(stockData).Stocks {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+
+	candleStore := data.NewCandleStore(cfg.CandleDir, cfg.Timeframe)
+	history := make(map[string][]models.Candle, len(symbols))
+	var missing []string
+	for _, symbol := range symbols {
+		candleData, err := candleStore.Load(symbol)
+		if errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, symbol)
+			continue
+		}
+		if err != nil {
+			log.Printf("⚠️  Could not load archived candles for %s: %v", symbol, err)
+			continue
+		}
+		history[symbol] = candleData.Candles
+	}
+	if len(missing) > 0 {
+		log.Printf("⚠️  %d symbols have no archived candles and were skipped (run `sapan backfill` first): %s",
+			len(missing), strings.Join(missing, ", "))
+	}
+
+	results := backscan.ScanAll(newStrategy(cfg), history, horizons, cpupool.New(cfg.CPUWorkers))
+	backscan.Print(os.Stdout, output.NewPalette(output.ColorEnabled(cfg.Color)), results)
+	return exitOK
+}
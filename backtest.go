@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/backtest"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"log"
+	"os"
+)
+
+// runBacktest replays the strategy over the configured stocks and prints, writes, and renders the analytics
+// RESULTS_FILE and REPORTS_DIR receive the JSON document and HTML report just as they do for scans
+func runBacktest(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	sapanStrategy, stockFetcher := newStrategy(cfg), newProviderFetcher(cfg)
+	if !resolveOutputSize(cfg, sapanStrategy, stockFetcher.Capabilities()) {
+		return exitConfigError
+	}
+
+	stockData, err := data.NewStockListLoader().LoadStocksFromPatterns(cfg.StocksFile)
+	if err != nil {
+		log.Println("Failed to load stocks:", err)
+		return exitConfigError
+	}
+	stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+	if err != nil {
+		log.Println("Failed to build stock filter:", err)
+		return exitConfigError
+	}
+	stockData = stockFilter.Apply(stockData)
+
+	log.Printf("🧪 Backtesting %d stocks over %d candles each...", len(stockData.Stocks), cfg.OutputSize)
+	if err := stockFetcher.Capabilities().Check(cfg.Timeframe, cfg.OutputSize); err != nil {
+		log.Printf("Unsupported provider settings: %v", err)
+		return exitConfigError
+	}
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+	backtester := backtest.NewBacktester(stockFetcher, sapanStrategy, cfg.OutputSize, cfg.RequestDelay, cfg.BacktestEntryWindow)
+	backtester.SetCPUPool(cpupool.New(cfg.CPUWorkers))
+	trades, failures := backtester.Run(stockData.Stocks)
+	result := backtest.BuildResult(trades, failures, len(stockData.Stocks), cfg.BacktestRiskPercent)
+	if curve := backtest.EquityCurve(trades, cfg.BacktestRiskPercent/100); cfg.BenchmarkSymbol != "" && len(curve) >= 2 {
+		if comparison, err := compareWithBenchmark(cfg, curve); err != nil {
+			log.Printf("⚠️  Could not compare with %s: %v", cfg.BenchmarkSymbol, err)
+		} else {
+			result.Benchmark = &comparison
+		}
+	}
+
+	fmt.Println()
+	if err := result.Print(os.Stdout); err != nil {
+		log.Printf("⚠️  Could not print backtest result: %v", err)
+	}
+	if cfg.ResultsFile != "" {
+		if err := backtest.WriteJSON(cfg.ResultsFile, result); err != nil {
+			log.Printf("⚠️  Could not write backtest result to %s: %v", cfg.ResultsFile, err)
+		}
+	}
+	if cfg.ReportsDir != "" {
+		if path, err := backtest.WriteHTML(cfg.ReportsDir, result, cfg.DisplayLocation); err != nil {
+			log.Printf("⚠️  Could not write backtest report: %v", err)
+		} else {
+			log.Printf("📄 Backtest report written to %s", path)
+		}
+	}
+
+	if len(stockData.Stocks) > 0 && len(failures) == len(stockData.Stocks) {
+		return exitProviderError
+	}
+	return exitOK
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/backtest"
+	"github.com/erhankrygt/sapan/internal/benchmark"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// runBenchmark compares a saved backtest, or the paper-traded outcomes of recorded signals, with the benchmark
+// With a FILE argument the trades of that backtest result are used; otherwise every resolved outcome in SIGNAL_DB_PATH
+func runBenchmark(args []string) int {
+	path := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		path, args = args[0], args[1:]
+	}
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.BenchmarkSymbol == "" {
+		log.Printf("BENCHMARK_SYMBOL is required to compare results with a benchmark")
+		return exitConfigError
+	}
+
+	var trades []backtest.Trade
+	riskPercent := cfg.BacktestRiskPercent
+	source := ""
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Failed to read backtest result: %v", err)
+			return exitConfigError
+		}
+		var result backtest.Result
+		if err := json.Unmarshal(raw, &result); err != nil {
+			log.Printf("Failed to parse backtest result %s: %v", path, err)
+			return exitConfigError
+		}
+		trades, riskPercent, source = result.Trades, result.RiskPercent, "backtest "+path
+	} else {
+		if cfg.SignalDBPath == "" {
+			log.Printf("SIGNAL_DB_PATH is required to benchmark paper-traded signals (or pass a backtest result file)")
+			return exitConfigError
+		}
+		signalStore, err := openSignalStore(cfg)
+		if err != nil {
+			log.Printf("Failed to open signal database: %v", err)
+			return exitFailure
+		}
+		defer signalStore.Close()
+		outcomes, err := signalStore.QueryOutcomes(watcher.SignalQuery{})
+		if err != nil {
+			log.Printf("Failed to load signal outcomes: %v", err)
+			return exitFailure
+		}
+		trades, source = outcomeTrades(outcomes), "paper-traded signals in "+cfg.SignalDBPath
+	}
+
+	curve := backtest.EquityCurve(trades, riskPercent/100)
+	if len(curve) < 2 {
+		log.Printf("No closed trades in %s to compare", source)
+		return exitFailure
+	}
+	comparison, err := compareWithBenchmark(cfg, curve)
+	if err != nil {
+		log.Printf("Failed to compare with %s: %v", cfg.BenchmarkSymbol, err)
+		return exitProviderError
+	}
+
+	fmt.Printf("📊 %s, risking %.2f%% per trade\n\n", source, riskPercent)
+	comparison.Print(os.Stdout)
+	return exitOK
+}
+
+// compareWithBenchmark fetches daily benchmark candles covering the curve and measures the curve against them
+func compareWithBenchmark(cfg *config.Config, curve []benchmark.EquityPoint) (benchmark.Comparison, error) {
+	days := int(time.Since(curve[0].Date).Hours()/24) + 1 // Calendar days cover at least as many trading days
+	stockFetcher := data.NewStockDataFetcher(cfg.APIKey, cfg.APIURL, "daily")
+	stockFetcher.SetHTTPClient(httpClient(cfg))
+	stockFetcher.SetRetryPolicy(retryPolicy(cfg))
+	ctx, cancel := fetchContext(cfg)
+	defer cancel()
+	candleData, err := stockFetcher.FetchStockData(ctx, cfg.BenchmarkSymbol, days)
+	if err != nil {
+		return benchmark.Comparison{}, err
+	}
+	return benchmark.Compare(cfg.BenchmarkSymbol, curve, candleData.Candles)
+}
+
+// outcomeTrades converts resolved signal outcomes into trades so they compound like a backtest
+func outcomeTrades(outcomes []watcher.SignalOutcome) []backtest.Trade {
+	trades := make([]backtest.Trade, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		trades = append(trades, backtest.Trade{
+			Symbol:     outcome.Symbol,
+			Side:       outcome.Side,
+			Pattern:    outcome.Pattern,
+			Sector:     outcome.Sector,
+			Score:      outcome.Score,
+			SignalDate: outcome.CandleDate,
+			EntryDate:  outcome.EntryDate,
+			ExitDate:   outcome.ExitDate,
+			Entry:      outcome.Entry,
+			Stop:       outcome.Stop,
+			Target:     outcome.Target,
+			ExitPrice:  outcome.ExitPrice,
+			Status:     outcome.Status,
+			RMultiple:  outcome.RMultiple,
+			BarsHeld:   outcome.BarsHeld,
+		})
+	}
+	return trades
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// runBlacklistAdd excludes the given symbols from every scan, with an optional --reason
+func runBlacklistAdd(args []string) int {
+	reason, args := takeFlag(args, "reason")
+	return editBlacklist(args, "add", func(blacklist *data.Blacklist, symbol string) bool {
+		return blacklist.Add(symbol, data.BlacklistManual, reason, time.Now())
+	})
+}
+
+// runBlacklistRemove lets the given symbols be scanned again
+func runBlacklistRemove(args []string) int {
+	return editBlacklist(args, "remove", func(blacklist *data.Blacklist, symbol string) bool {
+		return blacklist.Remove(symbol)
+	})
+}
+
+// editBlacklist applies edit to every SYMBOL argument and saves the blacklist when something changed
+func editBlacklist(args []string, verb string, edit func(*data.Blacklist, string) bool) int {
+	symbols, flags := splitSymbols(args)
+	if len(symbols) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: sapan blacklist %s SYMBOL... [flags]\n", verb)
+		return exitConfigError
+	}
+	cfg, code, ok := loadConfig(flags)
+	if !ok {
+		return code
+	}
+	blacklist, err := data.LoadBlacklist(cfg.BlacklistFile)
+	if err != nil {
+		log.Printf("Failed to load blacklist: %v", err)
+		return exitFailure
+	}
+
+	changed := 0
+	for _, symbol := range symbols {
+		if edit(blacklist, symbol) {
+			changed++
+		}
+	}
+	if changed > 0 {
+		if err := blacklist.Save(); err != nil {
+			log.Printf("Failed to save blacklist: %v", err)
+			return exitFailure
+		}
+	}
+	log.Printf("🚫 %s: %d of %d symbols changed, %d blacklisted", cfg.BlacklistFile, changed, len(symbols), len(blacklist.Entries))
+	return exitOK
+}
+
+// runBlacklistList prints the blacklisted symbols with where they came from
+func runBlacklistList(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	blacklist, err := data.LoadBlacklist(cfg.BlacklistFile)
+	if err != nil {
+		log.Printf("Failed to load blacklist: %v", err)
+		return exitFailure
+	}
+	for _, entry := range blacklist.List() {
+		fmt.Printf("%-10s %-6s %s  %s\n", entry.Symbol, entry.Source, entry.AddedAt.Format("2006-01-02"), entry.Reason)
+	}
+	return exitOK
+}
+
+// splitSymbols separates the leading SYMBOL arguments from the configuration flags that follow them
+func splitSymbols(args []string) ([]string, []string) {
+	var symbols []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		symbols, args = append(symbols, strings.ToUpper(args[0])), args[1:]
+	}
+	return symbols, args
+}
+
+// recordRejections counts the symbols the provider rejected in a scan and blacklists the ones that keep failing
+func recordRejections(cfg *config.Config, blacklist *data.Blacklist, summary processor.ProcessingSummary) {
+	scanned := make([]string, 0, len(summary.Results))
+	rejected := make(map[string]string)
+	for _, result := range summary.Results {
+		scanned = append(scanned, result.Symbol)
+		if errors.Is(result.Error, data.ErrSymbolRejected) {
+			rejected[result.Symbol] = result.Error.Error()
+		}
+	}
+	if len(rejected) == 0 && len(blacklist.Rejections) == 0 {
+		return // Nothing to count and nothing to reset, so leave the file alone
+	}
+	for _, entry := range blacklist.RecordScan(scanned, rejected, cfg.BlacklistAutoRejections, time.Now()) {
+		log.Printf("🚫 Blacklisted %s: %s", entry.Symbol, entry.Reason)
+	}
+	if err := blacklist.Save(); err != nil {
+		log.Printf("⚠️  Could not save blacklist: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/erhankrygt/sapan/internal/outcome"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"time"
+)
+
+// runCalibration prints hit rates of tracked signals by score, probability, pattern, sector, and regime
+// --from limits the report to signals detected on or after a date, e.g. after a strategy change
+func runCalibration(args []string) int {
+	fromText, args := takeFlag(args, "from")
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.SignalDBPath == "" {
+		log.Printf("SIGNAL_DB_PATH is required to report on signal outcomes")
+		return exitConfigError
+	}
+	var query watcher.SignalQuery
+	if fromText != "" {
+		from, err := time.Parse("2006-01-02", fromText)
+		if err != nil {
+			log.Printf("Invalid calibration date %q (expected YYYY-MM-DD)", fromText)
+			return exitConfigError
+		}
+		query.From = from
+	}
+
+	signalStore, err := openSignalStore(cfg)
+	if err != nil {
+		log.Printf("Failed to open signal database: %v", err)
+		return exitFailure
+	}
+	defer signalStore.Close()
+
+	outcomes, err := signalStore.QueryOutcomes(query)
+	if err != nil {
+		log.Printf("Failed to load signal outcomes: %v", err)
+		return exitFailure
+	}
+	if len(outcomes) == 0 {
+		log.Printf("No signal outcomes recorded yet; scans record them OUTCOME_TRACKING_DAYS after each signal")
+		return exitOK
+	}
+	outcome.BuildCalibration(outcomes).Print(os.Stdout, output.NewPalette(output.ColorEnabled(cfg.Color)))
+	return exitOK
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/compare"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runCompare diffs two stored runs: setups added and dropped, score changes, and parameter differences
+// Each --run is a run identifier or a date (YYYY-MM-DD) standing for the last run finished that day; the first is the baseline
+func runCompare(args []string) int {
+	runTexts, args := takeFlags(args, "run")
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if len(runTexts) != 2 {
+		log.Printf("compare needs exactly two runs, e.g. --run 12 --run 19 or --run 2024-06-07 --run 2024-06-14")
+		return exitConfigError
+	}
+	if cfg.SignalDBPath == "" {
+		log.Printf("SIGNAL_DB_PATH is required to compare stored runs")
+		return exitConfigError
+	}
+
+	signalStore, err := openSignalStore(cfg)
+	if err != nil {
+		log.Printf("Failed to open signal database: %v", err)
+		return exitFailure
+	}
+	defer signalStore.Close()
+
+	var runs [2]watcher.RunSummary
+	var signals [2][]watcher.Signal
+	for i, text := range runTexts {
+		run, err := findRun(signalStore, text)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("No stored run matches %q", text)
+			return exitConfigError
+		}
+		if err != nil {
+			log.Printf("Failed to load run %q: %v", text, err)
+			return exitFailure
+		}
+		if signals[i], err = signalStore.QuerySignals(watcher.SignalQuery{RunID: run.ID}); err != nil {
+			log.Printf("Failed to load signals of run %d: %v", run.ID, err)
+			return exitFailure
+		}
+		runs[i] = run
+	}
+
+	report := compare.Runs(runs[0], runs[1], signals[0], signals[1])
+	report.Print(os.Stdout, output.NewPalette(output.ColorEnabled(cfg.Color)))
+	return exitOK
+}
+
+// findRun resolves a --run value: a run identifier, or a date standing for the last run finished that day
+func findRun(signalStore *watcher.SQLiteSignalStore, text string) (watcher.RunSummary, error) {
+	if id, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return signalStore.Run(id)
+	}
+	day, err := time.Parse("2006-01-02", text)
+	if err != nil {
+		return watcher.RunSummary{}, fmt.Errorf("expected a run identifier or a YYYY-MM-DD date")
+	}
+	runs, err := signalStore.QueryRuns(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return watcher.RunSummary{}, err
+	}
+	if len(runs) == 0 {
+		return watcher.RunSummary{}, sql.ErrNoRows
+	}
+	return signalStore.Run(runs[0].ID) // Newest first
+}
+
+// runSettings captures the resolved configuration of a scan so later comparisons can show parameter differences
+func runSettings(cfg *config.Config) watcher.RunSettings {
+	settings := make(watcher.RunSettings)
+	for _, setting := range cfg.Settings() {
+		settings[setting.Key] = setting.Value
+	}
+	return settings
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// showConfig prints every resolved setting with its source so users can see why a value is in effect
+// Flags passed after `config show` take part in resolution exactly as they would for a scan
+func showConfig(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+
+	if err := cfg.PrintSettings(os.Stdout); err != nil {
+		log.Printf("Failed to print configuration: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
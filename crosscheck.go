@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/calendar"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/crosscheck"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"sort"
+	"time"
+)
+
+// crossCheckMargin is how many candles beyond CROSS_CHECK_CANDLES are fetched, covering holidays only one provider lists
+const crossCheckMargin = 10
+
+// crossCheckSignals fetches the recent candles of the run's best setups from CROSS_CHECK_PROVIDER and flags setups
+// whose candles disagree with the scanning provider as low-confidence; fetch failures leave a setup unflagged
+func crossCheckSignals(cfg *config.Config, summary processor.ProcessingSummary, watchListManager *watcher.WatchListManager,
+	marketCalendar *calendar.Calendar, logInfo func(string, ...interface{})) {
+	type candidate struct {
+		result processor.ProcessingResult // Processing result holding the analyzed candles
+		side   string                     // Side of the valid setup
+		score  float64                    // Score of the valid setup
+	}
+	var candidates []candidate
+	for _, result := range summary.Results {
+		switch {
+		case result.IsLongValid:
+			candidates = append(candidates, candidate{result: result, side: watcher.LongSide, score: result.LongResult.Score})
+		case result.IsShortValid:
+			candidates = append(candidates, candidate{result: result, side: watcher.ShortSide, score: result.ShortResult.Score})
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > cfg.CrossCheckSample {
+		candidates = candidates[:cfg.CrossCheckSample]
+	}
+
+	checkCfg := *cfg
+	checkCfg.Provider = cfg.CrossCheckProvider
+	checkFetcher := newProviderFetcher(&checkCfg)
+	quota, err := attachQuota(&checkCfg, checkFetcher)
+	if err != nil {
+		log.Printf("⚠️  Skipping the provider cross-check: failed to load API usage: %v", err)
+		return
+	}
+	defer saveQuota(quota)
+
+	flagged := 0
+	for i, setup := range candidates {
+		if i > 0 && cfg.RequestDelay > 0 {
+			time.Sleep(cfg.RequestDelay) // Respect API limits between symbols
+		}
+		candleData, err := fetchWithin(checkFetcher, setup.result.Symbol, cfg.CrossCheckCandles+crossCheckMargin, cfg.FetchTimeout)
+		if err != nil {
+			log.Printf("⚠️  Could not cross-check %s with %s: %v", setup.result.Symbol, cfg.CrossCheckProvider, err)
+			continue
+		}
+		candles := candleData.Candles
+		if !data.IsIntradayTimeframe(cfg.Timeframe) {
+			candles = marketCalendar.ClosedCandles(candles, time.Now())
+		}
+
+		result := crosscheck.Compare(setup.result.Symbol, setup.result.History, candles, cfg.CrossCheckCandles, cfg.CrossCheckTolerance)
+		if result.Agrees() {
+			logInfo("🔍 %s %s: %d candles match %s (checksum %s)", setup.side, result.Symbol, result.Compared,
+				cfg.CrossCheckProvider, result.PrimaryChecksum)
+			continue
+		}
+		flagged++
+		reason := fmt.Sprintf("%s disagrees: %s", cfg.CrossCheckProvider, result.Reason())
+		watchListManager.Annotate(result.Symbol, setup.side, func(entry *watcher.WatchListEntry) {
+			entry.LowConfidence = reason
+		})
+		log.Printf("⚠️  %s %s is low-confidence: %s", setup.side, result.Symbol, reason)
+	}
+	logInfo("🔍 Cross-checked %d setups against %s: %d flagged low-confidence", len(candidates), cfg.CrossCheckProvider, flagged)
+}
+
+// fetchWithin fetches the candles of a symbol within the fetch deadline (0 for none)
+func fetchWithin(fetcher data.Fetcher, symbol string, outputSize int, timeout time.Duration) (models.CandleData, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fetcher.FetchStockData(ctx, symbol, outputSize)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/scheduler"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// runDaemon scans on the configured schedules until interrupted, optionally serving run status over HTTP
+// With MARKETS set every section runs on its own SCHEDULE in this one process, keeping its own watch list,
+// signal history, and notifiers; scans of different sections never overlap, as they share the API quota
+func runDaemon(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	sections, daemons, code, ok := newDaemonGroup(cfg, args)
+	if !ok {
+		return code
+	}
+
+	if cfg.StatusAddr != "" {
+		mux := newStatusMux(sections, daemons)
+		go func() {
+			if err := http.ListenAndServe(cfg.StatusAddr, mux); err != nil {
+				log.Printf("⚠️  Status server stopped: %v", err)
+			}
+		}()
+		log.Printf("🩺 Serving daemon status on http://%s/status", cfg.StatusAddr)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var wg sync.WaitGroup
+	for _, daemon := range daemons {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			daemon.Run(ctx)
+		}()
+	}
+	wg.Wait()
+	log.Printf("👋 SAPAN daemon stopped")
+	return exitOK
+}
+
+// newDaemonGroup loads the configuration of every MARKETS section, or cfg alone without MARKETS, and creates one
+// daemon per configuration; the daemons share a mutex so scans of different sections never overlap
+func newDaemonGroup(cfg *config.Config, args []string) ([]*config.Config, scheduler.Group, int, bool) {
+	sections := []*config.Config{cfg}
+	if len(cfg.Markets) > 0 {
+		sections = sections[:0]
+		for _, section := range cfg.Markets {
+			sectionCfg, err := config.LoadSectionConfig(args, section)
+			if err != nil {
+				log.Printf("Failed to load configuration of market %s: %v", section, err)
+				return nil, nil, exitConfigError, false
+			}
+			sections = append(sections, sectionCfg)
+		}
+	}
+
+	scanMutex := &sync.Mutex{} // Serializes scans of different sections
+	daemons := make(scheduler.Group, 0, len(sections))
+	for _, sectionCfg := range sections {
+		daemon, code, ok := newScanDaemon(sectionCfg, scanMutex)
+		if !ok {
+			return nil, nil, code, false
+		}
+		daemons = append(daemons, daemon)
+	}
+	return sections, daemons, exitOK, true
+}
+
+// newStatusMux routes /status to the daemon of a single configuration, or to every section with /status/<section>
+// reporting one section
+func newStatusMux(sections []*config.Config, daemons scheduler.Group) *http.ServeMux {
+	mux := http.NewServeMux()
+	if len(sections) == 1 && sections[0].Section == "" {
+		mux.Handle("/status", daemons[0])
+		return mux
+	}
+	mux.Handle("/status", daemons)
+	for i, sectionCfg := range sections {
+		mux.Handle("/status/"+sectionCfg.Section, daemons[i])
+	}
+	return mux
+}
+
+// newScanDaemon creates the daemon scanning one configuration on its SCHEDULE; scans hold scanMutex while they run
+func newScanDaemon(cfg *config.Config, scanMutex *sync.Mutex) (*scheduler.Daemon, int, bool) {
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return nil, exitConfigError, false
+	}
+	schedules, err := scheduler.ParseSchedules(cfg.Schedule, marketCalendar)
+	if err != nil {
+		log.Printf("Invalid SCHEDULE: %v", err)
+		return nil, exitConfigError, false
+	}
+
+	daemon := scheduler.NewDaemon(schedules, func() scheduler.RunResult {
+		scanMutex.Lock()
+		defer scanMutex.Unlock()
+		if cfg.Section != "" && cfg.OutputMode.ShowsProgress() {
+			fmt.Printf("\n🌍 Market %s (%s calendar, %s provider)\n\n", cfg.Section, cfg.Market, cfg.Provider)
+		}
+		summary, _, code := scan(cfg, nil, scanHooks{})
+		return scheduler.RunResult{ExitCode: code, Processed: summary.Total, Signals: summary.Valid, Errors: summary.Errors}
+	})
+	if cfg.Section != "" {
+		daemon.SetName(cfg.Section)
+		log.Printf("🕰️  SAPAN daemon scheduled market %s (%s, %s time)", cfg.Section, cfg.Schedule, marketCalendar.Location)
+	} else {
+		log.Printf("🕰️  SAPAN daemon started for the %s market (%s, %s time)", cfg.Market, cfg.Schedule, marketCalendar.Location)
+	}
+	return daemon, exitOK, true
+}
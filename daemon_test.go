@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erhankrygt/sapan/internal/scheduler"
+)
+
+func TestDaemonSchedulesEveryMarketSection(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_API_KEY", "demo")
+	t.Setenv("MARKETS", "us,crypto")
+	t.Setenv("CRYPTO_PROVIDER", "binance")
+	t.Setenv("CRYPTO_SCHEDULE", "0 1 * * *")
+
+	args := []string{"--schedule", "@close+30m"}
+	cfg, _, ok := loadConfig(args)
+	if !ok {
+		t.Fatal("configuration did not load")
+	}
+	sections, daemons, _, ok := newDaemonGroup(cfg, args)
+	if !ok {
+		t.Fatal("daemons were not created")
+	}
+	if len(sections) != 2 || len(daemons) != 2 {
+		t.Fatalf("%d sections and %d daemons, want 2 each", len(sections), len(daemons))
+	}
+	for i, want := range []struct{ name, schedule string }{{"us", "@close+30m"}, {"crypto", "0 1 * * *"}} {
+		status := daemons[i].Status()
+		if status.Name != want.name || len(status.Schedules) != 1 || status.Schedules[0] != want.schedule {
+			t.Errorf("daemon %d = %s on %v, want %s on %s", i, status.Name, status.Schedules, want.name, want.schedule)
+		}
+	}
+
+	mux := newStatusMux(sections, daemons)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var statuses []scheduler.Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &statuses); err != nil || len(statuses) != 2 {
+		t.Errorf("/status = %s, want both sections", recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status/crypto", nil))
+	var status scheduler.Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil || status.Name != "crypto" {
+		t.Errorf("/status/crypto = %s, want the crypto section", recorder.Body.String())
+	}
+}
+
+func TestDaemonServesSingleConfigurationStatus(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_API_KEY", "demo")
+
+	cfg, _, ok := loadConfig(nil)
+	if !ok {
+		t.Fatal("configuration did not load")
+	}
+	sections, daemons, _, ok := newDaemonGroup(cfg, nil)
+	if !ok || len(daemons) != 1 {
+		t.Fatalf("%d daemons, want one for the top-level configuration", len(daemons))
+	}
+
+	recorder := httptest.NewRecorder()
+	newStatusMux(sections, daemons).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var status scheduler.Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &status); err != nil || status.Name != "" {
+		t.Errorf("/status = %s, want the single daemon's status", recorder.Body.String())
+	}
+}
@@ -0,0 +1,212 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// actionsResponse mirrors the Alpha Vantage SPLITS and DIVIDENDS responses
+type actionsResponse struct {
+	Symbol string `json:"symbol"` // Stock ticker symbol
+	Data   []struct {
+		EffectiveDate  string `json:"effective_date"`   // Split ex-date (SPLITS)
+		SplitFactor    string `json:"split_factor"`     // New shares per old share (SPLITS)
+		ExDividendDate string `json:"ex_dividend_date"` // Dividend ex-date (DIVIDENDS)
+		Amount         string `json:"amount"`           // Cash per share (DIVIDENDS)
+	} `json:"data"`
+	Note         string `json:"Note"`          // Rate limit message
+	Information  string `json:"Information"`   // Premium endpoint or quota message
+	ErrorMessage string `json:"Error Message"` // Invalid request message
+}
+
+// FetchCorporateActions looks up the splits and dividends of a symbol (two API requests)
+// Entries with unparsable dates or non-positive values are skipped
+func (f *StockDataFetcher) FetchCorporateActions(symbol string) (models.CorporateActions, error) {
+	actions := models.CorporateActions{Symbol: symbol}
+
+	splits, err := f.fetchActions("SPLITS", symbol)
+	if err != nil {
+		return actions, err
+	}
+	for _, entry := range splits.Data {
+		exDate, dateErr := time.Parse("2006-01-02", entry.EffectiveDate)
+		ratio, ratioErr := strconv.ParseFloat(entry.SplitFactor, 64)
+		if dateErr == nil && ratioErr == nil && ratio > 0 && ratio != 1 {
+			actions.Splits = append(actions.Splits, models.Split{ExDate: exDate, Ratio: ratio})
+		}
+	}
+
+	dividends, err := f.fetchActions("DIVIDENDS", symbol)
+	if err != nil {
+		return actions, err
+	}
+	for _, entry := range dividends.Data {
+		exDate, dateErr := time.Parse("2006-01-02", entry.ExDividendDate)
+		amount, amountErr := strconv.ParseFloat(entry.Amount, 64)
+		if dateErr == nil && amountErr == nil && amount > 0 {
+			actions.Dividends = append(actions.Dividends, models.Dividend{ExDate: exDate, Amount: amount})
+		}
+	}
+	return actions, nil
+}
+
+// fetchActions calls one corporate action endpoint and checks the response for API errors
+func (f *StockDataFetcher) fetchActions(function, symbol string) (actionsResponse, error) {
+	requestURL := fmt.Sprintf("%s?function=%s&symbol=%s&apikey=%s", f.apiURL, function, url.QueryEscape(symbol), f.apiKey)
+
+	if err := f.countRequest(); err != nil {
+		return actionsResponse{}, err
+	}
+	resp, err := f.client.Get(requestURL)
+	if err != nil {
+		return actionsResponse{}, fmt.Errorf("failed to fetch %s: %v", function, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return actionsResponse{}, fmt.Errorf("failed to read %s response: %v", function, err)
+	}
+	var response actionsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return actionsResponse{}, fmt.Errorf("failed to parse %s response: %v", function, err)
+	}
+	switch {
+	case response.Note != "":
+		return actionsResponse{}, fmt.Errorf("API rate limit: %s", response.Note)
+	case response.ErrorMessage != "":
+		return actionsResponse{}, fmt.Errorf("API error: %s", response.ErrorMessage)
+	case response.Data == nil && response.Information != "":
+		return actionsResponse{}, fmt.Errorf("API error: %s", response.Information)
+	}
+	return response, nil
+}
+
+// NewAdjustments converts the corporate actions not yet in applied into adjustments for candles, sorted by ex-date
+// candles must be adjusted for exactly the applied adjustments; a split divides prices by its ratio and
+// multiplies volume by it, a dividend scales prices by 1 - amount / as-traded close of the session before its ex-date
+// Dividends without an earlier candle are left out, since no candle needs them yet
+func NewAdjustments(candles []models.Candle, actions models.CorporateActions, applied []models.Adjustment) []models.Adjustment {
+	var adjustments []models.Adjustment
+	for _, split := range actions.Splits {
+		adjustments = append(adjustments, models.Adjustment{
+			Kind:         models.AdjustmentSplit,
+			ExDate:       split.ExDate,
+			PriceFactor:  1 / split.Ratio,
+			VolumeFactor: split.Ratio,
+		})
+	}
+	for _, dividend := range actions.Dividends {
+		previous := -1
+		for i, candle := range candles {
+			if (models.Adjustment{ExDate: dividend.ExDate}).Applies(candle.Date) {
+				previous = i
+			}
+		}
+		if previous < 0 {
+			continue
+		}
+		// Undo the adjustments already in the candle so the amount is compared with the as-traded close
+		previousClose := candles[previous].Close
+		for _, adjustment := range applied {
+			if adjustment.Applies(candles[previous].Date) {
+				previousClose /= adjustment.PriceFactor
+			}
+		}
+		if previousClose <= dividend.Amount {
+			continue // A dividend worth the whole share price is bad data
+		}
+		adjustments = append(adjustments, models.Adjustment{
+			Kind:         models.AdjustmentDividend,
+			ExDate:       dividend.ExDate,
+			PriceFactor:  1 - dividend.Amount/previousClose,
+			VolumeFactor: 1,
+		})
+	}
+
+	adjustments = missingAdjustments(adjustments, applied)
+	sort.Slice(adjustments, func(i, j int) bool { return adjustments[i].ExDate.Before(adjustments[j].ExDate) })
+	return adjustments
+}
+
+// missingAdjustments returns the adjustments of want whose corporate action is not in have
+func missingAdjustments(want, have []models.Adjustment) []models.Adjustment {
+	var missing []models.Adjustment
+	for _, adjustment := range want {
+		found := false
+		for _, existing := range have {
+			if adjustment.SameAction(existing) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, adjustment)
+		}
+	}
+	return missing
+}
+
+// AdjustCandles returns a copy of candles with every adjustment applied to the sessions before its ex-date
+func AdjustCandles(candles []models.Candle, adjustments []models.Adjustment) []models.Candle {
+	adjusted := make([]models.Candle, len(candles))
+	for i, candle := range candles {
+		for _, adjustment := range adjustments {
+			candle = adjustment.Adjust(candle)
+		}
+		adjusted[i] = candle
+	}
+	return adjusted
+}
+
+// AdjustedPrices returns a copy of candles converted to the adjusted prices of an adjusted series
+// Prices are scaled by each candle's adjusted close over its close, and volume by the splits of later candles,
+// so dividends leave it unchanged; candles without an adjusted close keep their as-traded values
+func AdjustedPrices(candles []models.Candle) []models.Candle {
+	adjusted := make([]models.Candle, len(candles))
+	splitFactor := 1.0 // Product of the split coefficients of the candles after the current one
+	for i := len(candles) - 1; i >= 0; i-- {
+		candle := candles[i]
+		if candle.AdjustedClose > 0 && candle.Close > 0 {
+			factor := candle.AdjustedClose / candle.Close
+			candle.Open *= factor
+			candle.High *= factor
+			candle.Low *= factor
+			candle.Close = candle.AdjustedClose
+			candle.Volume = int64(math.Round(float64(candle.Volume) * splitFactor))
+		}
+		if candle.SplitCoefficient > 0 {
+			splitFactor *= candle.SplitCoefficient
+		}
+		adjusted[i] = candle
+	}
+	return adjusted
+}
+
+// ApplyCorporateActions re-adjusts the stored candles of a symbol for the actions not applied yet
+// The applied adjustments are recorded in the archive, so running it again with the same actions changes nothing
+// It returns the newly applied adjustments; a symbol that was never saved returns an os.ErrNotExist error
+func (s *CandleStore) ApplyCorporateActions(symbol string, actions models.CorporateActions) ([]models.Adjustment, error) {
+	stored, err := s.Load(symbol)
+	if err != nil {
+		return nil, err
+	}
+	adjustments := NewAdjustments(stored.Candles, actions, stored.Adjustments)
+	if len(adjustments) == 0 {
+		return nil, nil
+	}
+
+	stored.Candles = AdjustCandles(stored.Candles, adjustments)
+	stored.Adjustments = append(stored.Adjustments, adjustments...)
+	if err := s.write(symbol, stored); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
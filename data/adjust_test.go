@@ -0,0 +1,111 @@
+package data
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// sessions builds one daily candle per close, starting on 2024-03-04 in the given timezone
+func sessions(location *time.Location, closes ...float64) []models.Candle {
+	candles := make([]models.Candle, len(closes))
+	for i, close := range closes {
+		date := time.Date(2024, 3, 4+i, 0, 0, 0, 0, location)
+		candles[i] = models.Candle{Date: date, Open: close, High: close, Low: close, Close: close, Volume: 1000}
+	}
+	return candles
+}
+
+func day(value string) time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return date
+}
+
+func TestAdjustCandlesForSplit(t *testing.T) {
+	istanbul, _ := time.LoadLocation("Europe/Istanbul")
+	candles := sessions(istanbul, 400, 404, 101)
+	actions := models.CorporateActions{Splits: []models.Split{{ExDate: day("2024-03-06"), Ratio: 4}}}
+
+	adjustments := NewAdjustments(candles, actions, nil)
+	adjusted := AdjustCandles(candles, adjustments)
+	for i, want := range []struct {
+		close  float64
+		volume int64
+	}{{100, 4000}, {101, 4000}, {101, 1000}} {
+		if adjusted[i].Close != want.close || adjusted[i].Volume != want.volume {
+			t.Errorf("candle %d = close %.2f volume %d, want %.2f/%d", i, adjusted[i].Close, adjusted[i].Volume, want.close, want.volume)
+		}
+	}
+	if candles[0].Close != 400 {
+		t.Errorf("AdjustCandles modified its input")
+	}
+}
+
+func TestNewAdjustmentsForDividend(t *testing.T) {
+	candles := sessions(time.UTC, 50, 50, 49)
+	actions := models.CorporateActions{Dividends: []models.Dividend{
+		{ExDate: day("2024-03-06"), Amount: 1},
+		{ExDate: day("2024-03-01"), Amount: 1}, // Before the first candle
+	}}
+
+	adjustments := NewAdjustments(candles, actions, nil)
+	if len(adjustments) != 1 {
+		t.Fatalf("adjustments = %+v, want only the dividend with an earlier candle", adjustments)
+	}
+	if got := adjustments[0].PriceFactor; math.Abs(got-0.98) > 1e-9 {
+		t.Errorf("price factor = %f, want 0.98", got)
+	}
+}
+
+func TestNewAdjustmentsUndoAppliedFactors(t *testing.T) {
+	// The archive already holds a later 2-for-1 split, so the stored 25 was an as-traded 50
+	applied := []models.Adjustment{{Kind: models.AdjustmentSplit, ExDate: day("2024-03-07"), PriceFactor: 0.5, VolumeFactor: 2}}
+	candles := sessions(time.UTC, 25, 25, 24.5, 24.5)
+	actions := models.CorporateActions{
+		Splits:    []models.Split{{ExDate: day("2024-03-07"), Ratio: 2}},
+		Dividends: []models.Dividend{{ExDate: day("2024-03-06"), Amount: 1}},
+	}
+
+	adjustments := NewAdjustments(candles, actions, applied)
+	if len(adjustments) != 1 || adjustments[0].Kind != models.AdjustmentDividend {
+		t.Fatalf("adjustments = %+v, want only the new dividend", adjustments)
+	}
+	if got := adjustments[0].PriceFactor; math.Abs(got-0.98) > 1e-9 {
+		t.Errorf("price factor = %f, want 0.98 against the as-traded close", got)
+	}
+}
+
+func TestCandleStoreCorporateActions(t *testing.T) {
+	store := NewCandleStore(t.TempDir(), "daily")
+	if err := store.Save("ACME", models.CandleData{Candles: sessions(time.UTC, 400, 404)}); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := models.CorporateActions{Symbol: "ACME", Splits: []models.Split{{ExDate: day("2024-03-06"), Ratio: 4}}}
+	applied, err := store.ApplyCorporateActions("ACME", actions)
+	if err != nil || len(applied) != 1 {
+		t.Fatalf("ApplyCorporateActions = %+v, %v; want one adjustment", applied, err)
+	}
+	if again, err := store.ApplyCorporateActions("ACME", actions); err != nil || len(again) != 0 {
+		t.Errorf("second ApplyCorporateActions = %+v, %v; want nothing new", again, err)
+	}
+
+	// The provider's as-traded history overlaps the archive and adds the first session after the split
+	if err := store.Save("ACME", models.CandleData{Candles: sessions(time.UTC, 400, 404, 101)}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Load("ACME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{100, 101, 101} {
+		if stored.Candles[i].Close != want {
+			t.Errorf("stored close %d = %.2f, want %.2f", i, stored.Candles[i].Close, want)
+		}
+	}
+	if len(stored.Adjustments) != 1 {
+		t.Errorf("stored adjustments = %+v, want the split", stored.Adjustments)
+	}
+}
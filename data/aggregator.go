@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// partialCandle is the candle a symbol is building with the times of its first and last trade
+type partialCandle struct {
+	candle models.Candle // Candle built from the trades so far
+	first  time.Time     // Time of the earliest trade, which set the open
+	last   time.Time     // Time of the latest trade, which set the close
+}
+
+// CandleAggregator builds candles from streaming trades, one partial candle per symbol
+// A trade from a later period completes the partial candle and starts the next one (thread-safe)
+type CandleAggregator struct {
+	timeframe string                    // Candle timeframe: daily or an intraday interval such as 5min
+	period    time.Duration             // Length of an intraday candle (0 for daily candles)
+	location  *time.Location            // Exchange timezone candles are dated in
+	partial   map[string]*partialCandle // Candle in progress keyed by upper-case symbol
+	completed map[string]time.Time      // Start of the last completed period keyed by upper-case symbol
+	mutex     sync.Mutex                // Protects partial and completed
+}
+
+// NewCandleAggregator creates an aggregator for daily or intraday candles dated in the exchange timezone
+// Intraday periods are aligned to midnight, so 60min candles start on the hour even in half-hour timezones
+func NewCandleAggregator(timeframe string, location *time.Location) (*CandleAggregator, error) {
+	period := models.TimeframeDuration(timeframe)
+	if period == 0 && timeframe != "daily" {
+		return nil, fmt.Errorf("unsupported streaming timeframe %q (use daily or an intraday interval)", timeframe)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return &CandleAggregator{
+		timeframe: timeframe,                       // Store the timeframe for the candle data
+		period:    period,                          // Store the intraday period length
+		location:  location,                        // Store the exchange timezone
+		partial:   make(map[string]*partialCandle), // Initialize the partial candles
+		completed: make(map[string]time.Time),      // Initialize the completed periods
+	}, nil
+}
+
+// periodStart returns the session date and period start of a trade time
+// Periods follow the wall clock, so daylight saving changes do not shift the candles of the day
+func (a *CandleAggregator) periodStart(t time.Time) (session, start time.Time) {
+	local := t.In(a.location)
+	session = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, a.location)
+	if a.period == 0 {
+		return session, session
+	}
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+	return session, time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, int(clock/a.period*a.period), a.location)
+}
+
+// AddTrade folds a trade into the partial candle of its symbol
+// When the trade opens a new period, the completed candle is returned with ok set
+// Trades from periods before the partial candle, or from periods already completed, arrived too late and are dropped
+func (a *CandleAggregator) AddTrade(trade models.Trade) (completed models.Candle, ok bool) {
+	if trade.Price <= 0 || trade.Size < 0 {
+		return models.Candle{}, false // Bad ticks never reach a candle
+	}
+	session, start := a.periodStart(trade.Time)
+	symbol := strings.ToUpper(trade.Symbol)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if last, done := a.completed[symbol]; done && !start.After(last) {
+		return models.Candle{}, false
+	}
+	current := a.partial[symbol]
+	if current != nil {
+		switch {
+		case start.Before(current.candle.Timestamp):
+			return models.Candle{}, false
+		case start.Equal(current.candle.Timestamp):
+			current.add(trade)
+			return models.Candle{}, false
+		}
+		completed, ok = current.candle, true
+		a.completed[symbol] = current.candle.Timestamp
+	}
+
+	a.partial[symbol] = &partialCandle{
+		candle: models.Candle{
+			Date:      session,
+			Timestamp: start,
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+			Close:     trade.Price,
+			Volume:    trade.Size,
+		},
+		first: trade.Time,
+		last:  trade.Time,
+	}
+	return completed, ok
+}
+
+// add folds a trade of the candle's period into it; trades arriving out of order only move the open or close
+// when they are earlier than the first or later than the last trade seen
+func (p *partialCandle) add(trade models.Trade) {
+	p.candle.High = max(p.candle.High, trade.Price)
+	p.candle.Low = min(p.candle.Low, trade.Price)
+	p.candle.Volume += trade.Size
+	if trade.Time.Before(p.first) {
+		p.first, p.candle.Open = trade.Time, trade.Price
+	}
+	if !trade.Time.Before(p.last) {
+		p.last, p.candle.Close = trade.Time, trade.Price
+	}
+}
+
+// Partial returns the candle a symbol is building (ok is false before its first trade)
+func (a *CandleAggregator) Partial(symbol string) (candle models.Candle, ok bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	current := a.partial[strings.ToUpper(symbol)]
+	if current == nil {
+		return models.Candle{}, false
+	}
+	return current.candle, true
+}
+
+// Flush completes and returns the partial candles whose period ended by now, keyed by upper-case symbol
+// Call it on a timer so quiet symbols still close their candles without waiting for their next trade
+func (a *CandleAggregator) Flush(now time.Time) map[string]models.Candle {
+	_, currentStart := a.periodStart(now)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	completed := make(map[string]models.Candle)
+	for symbol, current := range a.partial {
+		if current.candle.Timestamp.Before(currentStart) {
+			completed[symbol] = current.candle
+			a.completed[symbol] = current.candle.Timestamp
+			delete(a.partial, symbol)
+		}
+	}
+	return completed
+}
+
+// AppendPartial returns candle data extended with the partial candle of a symbol for real-time analysis
+// The partial candle replaces stored candles from its period on; the input is not modified
+func (a *CandleAggregator) AppendPartial(symbol string, candleData models.CandleData) models.CandleData {
+	partial, ok := a.Partial(symbol)
+	if !ok {
+		return candleData
+	}
+	candles := make([]models.Candle, 0, len(candleData.Candles)+1)
+	for _, candle := range candleData.Candles {
+		if candle.Time().Before(partial.Timestamp) {
+			candles = append(candles, candle)
+		}
+	}
+	candles = append(candles, partial)
+	return models.CandleData{Timeframe: a.timeframe, Candles: candles, Adjustments: candleData.Adjustments, Dropped: candleData.Dropped}
+}
@@ -0,0 +1,94 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestCandleAggregatorBuildsIntradayCandles(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	aggregator, err := NewCandleAggregator("5min", newYork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(clock string) time.Time {
+		parsed, _ := time.ParseInLocation("2006-01-02 15:04:05", "2024-03-11 "+clock, newYork)
+		return parsed
+	}
+
+	trades := []models.Trade{
+		{Symbol: "aapl", Time: at("09:30:01"), Price: 170, Size: 100},
+		{Symbol: "AAPL", Time: at("09:31:00"), Price: 172, Size: 50},
+		{Symbol: "AAPL", Time: at("09:30:30"), Price: 169, Size: 10}, // Out of order: moves the low, not the close
+		{Symbol: "AAPL", Time: at("09:34:59"), Price: 171, Size: 40},
+	}
+	for _, trade := range trades {
+		if _, ok := aggregator.AddTrade(trade); ok {
+			t.Fatalf("trade at %s completed a candle", trade.Time.Format("15:04:05"))
+		}
+	}
+
+	completed, ok := aggregator.AddTrade(models.Trade{Symbol: "AAPL", Time: at("09:35:00"), Price: 173, Size: 5})
+	if !ok {
+		t.Fatal("the first trade of the next period did not complete the candle")
+	}
+	want := models.Candle{Date: at("00:00:00"), Timestamp: at("09:30:00"), Open: 170, High: 172, Low: 169, Close: 171, Volume: 200}
+	if !completed.Timestamp.Equal(want.Timestamp) || !completed.Date.Equal(want.Date) ||
+		completed.Open != want.Open || completed.High != want.High || completed.Low != want.Low ||
+		completed.Close != want.Close || completed.Volume != want.Volume {
+		t.Errorf("completed = %+v, want %+v", completed, want)
+	}
+
+	// A late trade from the completed period is dropped instead of reopening it
+	if _, ok := aggregator.AddTrade(models.Trade{Symbol: "AAPL", Time: at("09:33:00"), Price: 150, Size: 1}); ok {
+		t.Error("a late trade completed a candle")
+	}
+	if partial, _ := aggregator.Partial("AAPL"); partial.Low != 173 || partial.Volume != 5 {
+		t.Errorf("partial = %+v, want only the 09:35 trade", partial)
+	}
+}
+
+func TestCandleAggregatorFlush(t *testing.T) {
+	aggregator, err := NewCandleAggregator("60min", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC)
+	aggregator.AddTrade(models.Trade{Symbol: "MSFT", Time: start.Add(10 * time.Minute), Price: 400, Size: 10})
+
+	if flushed := aggregator.Flush(start.Add(59 * time.Minute)); len(flushed) != 0 {
+		t.Errorf("Flush inside the period = %+v, want nothing", flushed)
+	}
+	flushed := aggregator.Flush(start.Add(time.Hour))
+	if candle, ok := flushed["MSFT"]; !ok || candle.Close != 400 || !candle.Timestamp.Equal(start) {
+		t.Errorf("Flush after the period = %+v, want the 14:00 candle", flushed)
+	}
+	if _, ok := aggregator.Partial("MSFT"); ok {
+		t.Error("the flushed candle is still partial")
+	}
+}
+
+func TestCandleAggregatorAppendPartial(t *testing.T) {
+	aggregator, err := NewCandleAggregator("daily", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := models.CandleData{Timeframe: "daily", Candles: sessions(time.UTC, 100, 101, 102)}
+	aggregator.AddTrade(models.Trade{Symbol: "ACME", Time: stored.Candles[2].Date.Add(15 * time.Hour), Price: 105, Size: 7})
+
+	live := aggregator.AppendPartial("acme", stored)
+	if len(live.Candles) != 3 || live.Candles[2].Close != 105 || live.Candles[1].Close != 101 {
+		t.Errorf("AppendPartial = %+v, want the last session replaced by the partial candle", live.Candles)
+	}
+	if stored.Candles[2].Close != 102 {
+		t.Error("AppendPartial modified its input")
+	}
+}
+
+func TestNewCandleAggregatorRejectsWeekly(t *testing.T) {
+	if _, err := NewCandleAggregator("weekly", time.UTC); err == nil {
+		t.Error("NewCandleAggregator accepted weekly candles")
+	}
+}
@@ -0,0 +1,278 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// binanceMaxLimit is the largest number of klines Binance returns per request
+const binanceMaxLimit = 1000
+
+// binanceIntervals maps SAPAN timeframes onto Binance kline intervals
+var binanceIntervals = map[string]string{
+	"daily":   "1d",
+	"weekly":  "1w",
+	"monthly": "1M",
+	"1min":    "1m",
+	"5min":    "5m",
+	"15min":   "15m",
+	"30min":   "30m",
+	"60min":   "1h",
+}
+
+// BinanceFetcher fetches crypto candles from the public Binance klines API, which needs no API key
+// Symbols are trading pairs such as BTCUSDT; candles are dated in UTC like Binance's own candles
+type BinanceFetcher struct {
+	apiURL    string        // Binance REST API base URL
+	timeframe string        // Candle timeframe (daily, weekly, monthly, or an intraday interval)
+	client    *http.Client  // HTTP client pooling connections and bounding every request by the configured timeout
+	retry     RetryPolicy   // Retries of transient failures
+	requests  int64         // Number of API requests made (updated atomically)
+	quota     *QuotaTracker // Daily quota every request is counted against (nil for no quota)
+}
+
+// NewBinanceFetcher creates a Binance fetcher for a timeframe; an empty timeframe defaults to daily candles
+func NewBinanceFetcher(apiURL, timeframe string) *BinanceFetcher {
+	if timeframe == "" {
+		timeframe = "daily"
+	}
+	return &BinanceFetcher{
+		apiURL:    strings.TrimSuffix(apiURL, "/"),  // Store the API URL for constructing requests
+		timeframe: timeframe,                        // Store the timeframe for selecting the kline interval
+		client:    pooledClient(DefaultHTTPTimeout), // Share pooled connections and bound every request
+		retry:     DefaultRetryPolicy(),             // Retry transient failures
+	}
+}
+
+// SetTimeout bounds every HTTP request of the fetcher; 0 leaves requests bounded only by their context
+// The fetcher keeps its connection pool
+func (f *BinanceFetcher) SetTimeout(timeout time.Duration) {
+	f.client = &http.Client{Transport: f.client.Transport, Timeout: timeout}
+}
+
+// SetHTTPClient sends every request through client, typically one made by NewHTTPClient and shared by every fetcher
+// The client's own timeout replaces the fetcher's
+func (f *BinanceFetcher) SetHTTPClient(client *http.Client) {
+	f.client = client
+}
+
+// SetRetryPolicy sets how timeouts, 5xx responses, and rate limits are retried; the default is DefaultRetryPolicy
+func (f *BinanceFetcher) SetRetryPolicy(policy RetryPolicy) {
+	f.retry = policy
+}
+
+// SetQuota counts every API request against a daily quota and refuses requests once it is used up
+func (f *BinanceFetcher) SetQuota(quota *QuotaTracker) {
+	f.quota = quota
+}
+
+// RequestCount returns the number of API requests made so far (thread-safe)
+func (f *BinanceFetcher) RequestCount() int {
+	return int(atomic.LoadInt64(&f.requests))
+}
+
+// FetchStockData fetches the most recent outputSize candles of a trading pair sorted oldest first (0 fetches 1000)
+// Binance serves at most 1000 candles per request, so longer histories are paged backwards and stitched together
+func (f *BinanceFetcher) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	if outputSize <= 0 {
+		outputSize = binanceMaxLimit
+	}
+	var pages [][]models.Candle
+	var end time.Time
+	for remaining := outputSize; remaining > 0; {
+		limit := min(remaining, binanceMaxLimit)
+		candles, err := f.fetchKlines(ctx, symbol, limit, time.Time{}, end)
+		if err != nil {
+			return models.CandleData{}, err
+		}
+		pages = append(pages, candles)
+		if len(candles) < limit {
+			break // The page reached the pair's listing
+		}
+		remaining -= len(candles)
+		end = candles[0].Time().Add(-time.Millisecond) // Continue before the first candle of the page
+	}
+	candles := stitchCandles(pages...)
+	if len(candles) == 0 {
+		return models.CandleData{}, fmt.Errorf("invalid API response: no candles")
+	}
+	if len(candles) > outputSize {
+		candles = candles[len(candles)-outputSize:]
+	}
+	return models.CandleData{Timeframe: f.timeframe, Candles: candles}, nil
+}
+
+// FetchHistory fetches every candle of a trading pair opened since from, paging through 1000 candles per request
+// A zero from starts at the pair's listing
+func (f *BinanceFetcher) FetchHistory(symbol string, from time.Time) (models.CandleData, error) {
+	var pages [][]models.Candle
+	start := from
+	for {
+		candles, err := f.fetchKlines(context.Background(), symbol, binanceMaxLimit, start, time.Time{})
+		if err != nil {
+			return models.CandleData{}, err
+		}
+		pages = append(pages, candles)
+		if len(candles) < binanceMaxLimit {
+			break
+		}
+		start = candles[len(candles)-1].Time().Add(time.Millisecond) // Continue after the last candle of the page
+	}
+	history := stitchCandles(pages...)
+	if len(history) == 0 {
+		return models.CandleData{}, fmt.Errorf("invalid API response: no candles since %s", from.Format("2006-01-02"))
+	}
+	return models.CandleData{Timeframe: f.timeframe, Candles: history}, nil
+}
+
+// fetchKlines requests up to limit klines of a trading pair opened from start or up to end (zero for no bound);
+// without bounds Binance returns the most recent klines
+func (f *BinanceFetcher) fetchKlines(ctx context.Context, symbol string, limit int, start, end time.Time) ([]models.Candle, error) {
+	interval, ok := binanceIntervals[f.timeframe]
+	if !ok {
+		return nil, fmt.Errorf("timeframe %s is not supported by Binance", f.timeframe)
+	}
+	requestURL := fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&limit=%d",
+		f.apiURL, url.QueryEscape(strings.ToUpper(symbol)), interval, limit)
+	if !start.IsZero() {
+		requestURL += "&startTime=" + strconv.FormatInt(start.UnixMilli(), 10)
+	}
+	if !end.IsZero() {
+		requestURL += "&endTime=" + strconv.FormatInt(end.UnixMilli(), 10)
+	}
+
+	var candles []models.Candle
+	err := f.retry.do(ctx, func() error {
+		var err error
+		candles, err = f.requestKlines(ctx, requestURL)
+		return err
+	})
+	return candles, err
+}
+
+// requestKlines makes one klines request; transient failures are marked for retry
+func (f *BinanceFetcher) requestKlines(ctx context.Context, requestURL string) ([]models.Candle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if f.quota != nil {
+		if err := f.quota.Reserve(); err != nil {
+			return nil, err
+		}
+	}
+	atomic.AddInt64(&f.requests, 1)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, requestError(ctx, fmt.Errorf("failed to fetch data: %v", err))
+	}
+	defer resp.Body.Close() // Ensure response body is closed
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError(ctx, fmt.Errorf("failed to read response: %v", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := binanceError(resp.StatusCode, body)
+		if transientStatus(resp.StatusCode) && !errors.Is(err, ErrSymbolRejected) {
+			return nil, transient(err)
+		}
+		return nil, err
+	}
+	return parseKlines(body, f.timeframe)
+}
+
+// binanceError turns a Binance error response into an error, marking unknown symbols as rejected
+func binanceError(status int, body []byte) error {
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.Msg == "" {
+		return fmt.Errorf("API error: HTTP %d", status)
+	}
+	if response.Code == -1121 { // Invalid symbol
+		return fmt.Errorf("API error: %s: %w", response.Msg, ErrSymbolRejected)
+	}
+	if status == http.StatusTooManyRequests || status == http.StatusTeapot {
+		return fmt.Errorf("API rate limit: %s", response.Msg)
+	}
+	return fmt.Errorf("API error: %s", response.Msg)
+}
+
+// parseKlines converts a Binance klines response into candles; an empty response yields no candles
+// Each kline is an array of open time, open, high, low, close, base volume, close time, and quote volume;
+// volume is taken from the quote asset so pairs are comparable in the quote currency. Binance quotes the decimals
+// as strings to keep their precision, while compatible exchanges and mirrors send plain numbers, so both are read
+func parseKlines(body []byte, timeframe string) ([]models.Candle, error) {
+	var klines [][]json.RawMessage
+	if err := json.Unmarshal(body, &klines); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	candles := make([]models.Candle, 0, len(klines))
+	for _, kline := range klines {
+		if len(kline) < 8 {
+			continue // Skip malformed klines
+		}
+		var openTime int64
+		if err := json.Unmarshal(kline[0], &openTime); err != nil {
+			continue
+		}
+		var values [5]float64
+		valid := true
+		for i, index := range []int{1, 2, 3, 4, 7} {
+			value, err := klineNumber(kline[index])
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = value
+		}
+		if !valid || !validPrices(values[0], values[1], values[2], values[3]) || values[4] < 0 {
+			continue // Skip klines that cannot be traded on
+		}
+
+		timestamp := time.UnixMilli(openTime).UTC()
+		candle := models.Candle{
+			Date:      time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, time.UTC),
+			Timestamp: timestamp,
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    int64(values[4]),
+		}
+		if !IsIntradayTimeframe(timeframe) {
+			candle.Timestamp = candle.Date
+		}
+		candles = append(candles, candle)
+	}
+	if len(candles) == 0 && len(klines) > 0 {
+		return nil, fmt.Errorf("invalid API response: none of the %d candles could be parsed", len(klines))
+	}
+	return candles, nil
+}
+
+// klineNumber reads a decimal kline field sent either as a JSON string ("68300.01") or as a JSON number
+func klineNumber(field json.RawMessage) (float64, error) {
+	var text string
+	if err := json.Unmarshal(field, &text); err != nil {
+		var number float64
+		if err := json.Unmarshal(field, &number); err != nil {
+			return 0, err
+		}
+		return number, nil
+	}
+	return strconv.ParseFloat(text, 64)
+}
@@ -0,0 +1,196 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBinanceFetcherParsesKlines(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query(); r.URL.Path != "/api/v3/klines" || got.Get("symbol") != "BTCUSDT" || got.Get("interval") != "1d" || got.Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[
+			[1709942400000, "68300.0", "69000.0", "68000.5", "68900.0", "1200.5", 1710028799999, "82000000.9", 1, "0", "0", "0"],
+			[1710028800000, "68900.0", "70100.0", "68500.0", "70000.0", "900.1", 1710115199999, "63000000.2", 1, "0", "0", "0"]
+		]`))
+	}))
+	defer server.Close()
+
+	candleData, err := NewBinanceFetcher(server.URL, "daily").FetchStockData(context.Background(), "btcusdt", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles := candleData.Candles
+	checkCandles(t, candles)
+	if len(candles) != 2 || !candles[1].Date.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("candles = %+v, want 2 dated up to 2024-03-10 UTC", candles)
+	}
+	if candles[1].Close != 70000 || candles[1].Volume != 63000000 {
+		t.Errorf("last candle close %v volume %d, want 70000 and the quote volume 63000000", candles[1].Close, candles[1].Volume)
+	}
+}
+
+func TestBinanceFetcherRejectsUnknownSymbols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+	}))
+	defer server.Close()
+
+	fetcher := NewBinanceFetcher(server.URL, "daily")
+	if _, err := fetcher.FetchStockData(context.Background(), "NOPE", 100); !errors.Is(err, ErrSymbolRejected) {
+		t.Errorf("error = %v, want ErrSymbolRejected", err)
+	}
+	if fetcher.RequestCount() != 1 {
+		t.Errorf("RequestCount = %d, want 1", fetcher.RequestCount())
+	}
+}
+
+func TestBinanceFetcherTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // Hang until the test ends
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fetcher := NewBinanceFetcher(server.URL, "daily")
+	fetcher.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	fetcher.SetTimeout(50 * time.Millisecond)
+	if _, err := fetcher.FetchStockData(context.Background(), "BTCUSDT", 100); err == nil {
+		t.Error("FetchStockData succeeded past the HTTP timeout")
+	}
+
+	fetcher.SetTimeout(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := fetcher.FetchStockData(ctx, "BTCUSDT", 100); err == nil || ctx.Err() == nil {
+		t.Errorf("FetchStockData = %v, want the context deadline to end the request", err)
+	}
+}
+
+func TestBinanceFetcherPagesHistory(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var startTimes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTimes = append(startTimes, r.URL.Query().Get("startTime"))
+		var first int64
+		fmt.Sscan(r.URL.Query().Get("startTime"), &first)
+		opened := time.UnixMilli(first).UTC().Truncate(24 * time.Hour)
+		if opened.Before(time.UnixMilli(first).UTC()) {
+			opened = opened.Add(24 * time.Hour)
+		}
+		count := binanceMaxLimit
+		if len(startTimes) > 1 {
+			count = 5 // The second page ends the history
+		}
+		w.Write([]byte("["))
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `[%d, "10", "11", "9", "10.5", "1", 0, "100"]`, opened.AddDate(0, 0, i).UnixMilli())
+		}
+		w.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	fetcher := NewBinanceFetcher(server.URL, "daily")
+	candleData, err := fetcher.FetchHistory("BTCUSDT", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles := candleData.Candles
+	if len(candles) != binanceMaxLimit+5 || fetcher.RequestCount() != 2 {
+		t.Fatalf("got %d candles in %d requests, want %d in 2", len(candles), fetcher.RequestCount(), binanceMaxLimit+5)
+	}
+	checkCandles(t, candles)
+	if !candles[0].Date.Equal(start) || !candles[binanceMaxLimit].Date.Equal(start.AddDate(0, 0, binanceMaxLimit)) {
+		t.Errorf("pages are not contiguous: first %s, second page starts %s", candles[0].Date, candles[binanceMaxLimit].Date)
+	}
+	if startTimes[0] != fmt.Sprint(start.UnixMilli()) {
+		t.Errorf("first page startTime = %s, want %d", startTimes[0], start.UnixMilli())
+	}
+}
+
+func TestBinanceFetcherPagesRecentCandles(t *testing.T) {
+	listed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	const listedDays = 1200
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var limit, end int64
+		fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+		last := listed.AddDate(0, 0, listedDays-1)
+		if r.URL.Query().Has("endTime") {
+			fmt.Sscan(r.URL.Query().Get("endTime"), &end)
+			last = time.UnixMilli(end).UTC().Truncate(24 * time.Hour)
+		}
+		first := last.AddDate(0, 0, -int(limit)+1)
+		if first.Before(listed) {
+			first = listed
+		}
+		w.Write([]byte("["))
+		for opened := first; !opened.After(last); opened = opened.AddDate(0, 0, 1) {
+			if opened.After(first) {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `[%d, "10", "11", "9", "10.5", "1", 0, "100"]`, opened.UnixMilli())
+		}
+		w.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		outputSize   int
+		wantCandles  int
+		wantRequests int
+	}{
+		{500, 500, 1},
+		{1100, 1100, 2},
+		{3000, listedDays, 2}, // The second page reaches the listing
+	}
+	for _, test := range tests {
+		fetcher := NewBinanceFetcher(server.URL, "daily")
+		candleData, err := fetcher.FetchStockData(context.Background(), "BTCUSDT", test.outputSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		candles := candleData.Candles
+		if len(candles) != test.wantCandles || fetcher.RequestCount() != test.wantRequests {
+			t.Fatalf("outputSize %d: got %d candles in %d requests, want %d in %d", test.outputSize, len(candles), fetcher.RequestCount(), test.wantCandles, test.wantRequests)
+		}
+		checkCandles(t, candles)
+		for i := 1; i < len(candles); i++ {
+			if !candles[i].Date.Equal(candles[i-1].Date.AddDate(0, 0, 1)) {
+				t.Fatalf("outputSize %d: candle %d opens %s after %s, want consecutive days", test.outputSize, i, candles[i].Date, candles[i-1].Date)
+			}
+		}
+		if want := listed.AddDate(0, 0, listedDays-1); !candles[len(candles)-1].Date.Equal(want) {
+			t.Errorf("outputSize %d: last candle %s, want %s", test.outputSize, candles[len(candles)-1].Date, want)
+		}
+	}
+}
+
+func TestParseKlinesAcceptsNumericFields(t *testing.T) {
+	body := []byte(`[
+		[1709942400000, 68300.0, 69000.0, 68000.5, 68900.0, 1200.5, 1710028799999, 82000000.9],
+		[1710028800000, "68900.0", 70100, "68500.0", 70000, "900.1", 1710115199999, "63000000.2"],
+		[1710115200000, "n/a", "1", "1", "1", "1", 1710201599999, "1"]
+	]`)
+	candles, err := parseKlines(body, "daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("parsed %d candles, want 2 with the malformed kline skipped", len(candles))
+	}
+	checkCandles(t, candles)
+	if candles[0].Open != 68300 || candles[0].Volume != 82000000 || candles[1].High != 70100 || candles[1].Close != 70000 {
+		t.Errorf("candles = %+v, want numeric and string fields read alike", candles)
+	}
+}
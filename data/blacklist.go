@@ -0,0 +1,146 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrSymbolRejected marks fetch errors where the provider does not know the symbol, as opposed to rate limits or outages
+var ErrSymbolRejected = errors.New("symbol rejected by the provider")
+
+// Blacklist entry sources
+const (
+	BlacklistManual = "manual" // Added with `sapan blacklist add`
+	BlacklistAuto   = "auto"   // Added after the provider rejected the symbol in consecutive scans
+)
+
+// BlacklistEntry is one symbol excluded from every scan
+type BlacklistEntry struct {
+	Symbol  string    `json:"symbol"`           // Upper-case ticker symbol
+	Source  string    `json:"source"`           // manual or auto
+	Reason  string    `json:"reason,omitempty"` // Why the symbol was excluded
+	AddedAt time.Time `json:"added_at"`         // Time the symbol was excluded
+}
+
+// Blacklist is the persisted set of symbols every scan skips
+// It also counts consecutive provider rejections so dead symbols can be excluded automatically
+type Blacklist struct {
+	Entries    map[string]BlacklistEntry `json:"entries"`    // Excluded symbols keyed by upper-case symbol
+	Rejections map[string]int            `json:"rejections"` // Consecutive scans the provider rejected a listed symbol in
+	path       string                    // File the blacklist is loaded from and saved to
+}
+
+// LoadBlacklist reads the blacklist at path; a missing file yields an empty blacklist
+func LoadBlacklist(path string) (*Blacklist, error) {
+	blacklist := &Blacklist{
+		Entries:    make(map[string]BlacklistEntry), // Initialize the excluded symbols
+		Rejections: make(map[string]int),            // Initialize the rejection counts
+		path:       path,                            // Store the file location
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return blacklist, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blacklist: %v", err)
+	}
+	if err := json.Unmarshal(raw, blacklist); err != nil {
+		return nil, fmt.Errorf("failed to parse blacklist %s: %v", path, err)
+	}
+	if blacklist.Entries == nil {
+		blacklist.Entries = make(map[string]BlacklistEntry)
+	}
+	if blacklist.Rejections == nil {
+		blacklist.Rejections = make(map[string]int)
+	}
+	return blacklist, nil
+}
+
+// Add excludes a symbol and reports whether it was new; an existing entry keeps its source and reason
+func (b *Blacklist) Add(symbol, source, reason string, now time.Time) bool {
+	key := strings.ToUpper(strings.TrimSpace(symbol))
+	if _, ok := b.Entries[key]; ok || key == "" {
+		return false
+	}
+	b.Entries[key] = BlacklistEntry{Symbol: key, Source: source, Reason: reason, AddedAt: now.UTC()}
+	delete(b.Rejections, key)
+	return true
+}
+
+// Remove lets a symbol be scanned again and reports whether it was excluded
+func (b *Blacklist) Remove(symbol string) bool {
+	key := strings.ToUpper(strings.TrimSpace(symbol))
+	_, ok := b.Entries[key]
+	delete(b.Entries, key)
+	delete(b.Rejections, key)
+	return ok
+}
+
+// Contains reports whether a symbol is excluded
+func (b *Blacklist) Contains(symbol string) bool {
+	_, ok := b.Entries[strings.ToUpper(strings.TrimSpace(symbol))]
+	return ok
+}
+
+// List returns the entries sorted by symbol
+func (b *Blacklist) List() []BlacklistEntry {
+	entries := make([]BlacklistEntry, 0, len(b.Entries))
+	for _, entry := range b.Entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Symbol < entries[j].Symbol })
+	return entries
+}
+
+// Apply returns the stocks that are not excluded
+func (b *Blacklist) Apply(stocks models.StockData) models.StockData {
+	var kept models.StockData
+	for _, stock := range stocks.Stocks {
+		if !b.Contains(stock.Symbol) {
+			kept.Stocks = append(kept.Stocks, stock)
+		}
+	}
+	return kept
+}
+
+// RecordScan updates the rejection counts with the outcome of a scan and excludes symbols rejected in threshold
+// consecutive scans; rejected maps symbols to their error and scanned lists every symbol the scan fetched
+// Symbols fetched without a rejection start over, and a threshold of 0 only records the counts
+func (b *Blacklist) RecordScan(scanned []string, rejected map[string]string, threshold int, now time.Time) []BlacklistEntry {
+	var added []BlacklistEntry
+	for _, symbol := range scanned {
+		key := strings.ToUpper(symbol)
+		reason, ok := rejected[symbol]
+		if !ok {
+			delete(b.Rejections, key)
+			continue
+		}
+		b.Rejections[key]++
+		if threshold > 0 && b.Rejections[key] >= threshold {
+			reason = fmt.Sprintf("rejected by the provider in %d consecutive scans: %s", b.Rejections[key], reason)
+			if b.Add(key, BlacklistAuto, reason, now) {
+				added = append(added, b.Entries[key])
+			}
+		}
+	}
+	return added
+}
+
+// Save writes the blacklist to its file
+func (b *Blacklist) Save() error {
+	raw, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode blacklist: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(b.path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("failed to write blacklist: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestBlacklistAutoEntriesAfterConsecutiveRejections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blacklist.json")
+	blacklist, err := LoadBlacklist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	scanned := []string{"AAPL", "DEAD", "FLAKY"}
+
+	if added := blacklist.RecordScan(scanned, map[string]string{"DEAD": "Invalid API call", "FLAKY": "Invalid API call"}, 2, now); len(added) != 0 {
+		t.Fatalf("first scan added %+v, want nothing yet", added)
+	}
+	added := blacklist.RecordScan(scanned, map[string]string{"DEAD": "Invalid API call"}, 2, now) // FLAKY recovered
+	if len(added) != 1 || added[0].Symbol != "DEAD" || added[0].Source != BlacklistAuto {
+		t.Fatalf("added = %+v, want only DEAD after two consecutive rejections", added)
+	}
+	if added := blacklist.RecordScan([]string{"AAPL", "FLAKY"}, map[string]string{"FLAKY": "Invalid API call"}, 2, now); len(added) != 0 {
+		t.Fatalf("third scan added %+v, want FLAKY to start counting over", added)
+	}
+
+	blacklist.Add("meme", BlacklistManual, "halted", now)
+	if err := blacklist.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadBlacklist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stocks := reloaded.Apply(models.StockData{Stocks: []models.Stock{{Symbol: "AAPL"}, {Symbol: "dead"}, {Symbol: "MEME"}, {Symbol: "FLAKY"}}})
+	if len(stocks.Stocks) != 2 || stocks.Stocks[0].Symbol != "AAPL" || stocks.Stocks[1].Symbol != "FLAKY" {
+		t.Errorf("Apply = %+v, want AAPL and FLAKY", stocks.Stocks)
+	}
+	if reloaded.Rejections["FLAKY"] != 1 {
+		t.Errorf("FLAKY rejections = %d, want 1 carried over to the next scan", reloaded.Rejections["FLAKY"])
+	}
+
+	if !reloaded.Remove("dead") || reloaded.Contains("DEAD") {
+		t.Error("Remove did not let DEAD be scanned again")
+	}
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// supportedTimeframes lists the candle timeframes both providers serve
+var supportedTimeframes = []string{"daily", "weekly", "monthly", "1min", "5min", "15min", "30min", "60min"}
+
+// Capabilities describes what a market data provider can serve, so unsupported settings fail before a scan starts
+type Capabilities struct {
+	Provider          string   // Provider name as configured in PROVIDER
+	Timeframes        []string // Candle timeframes the provider serves
+	MaxHistory        int      // Most candles one candle fetch returns (0 for the full history)
+	PageSize          int      // Most candles one request returns; longer fetches take several requests (0 when one request serves all)
+	SymbolsPerRequest int      // Symbols one candle request covers
+	QuotesPerRequest  int      // Symbols one bulk quote request covers (0 when the provider has no bulk quotes)
+}
+
+// Capabilities returns what Alpha Vantage serves: every timeframe with the full history, and bulk quotes
+func (f *StockDataFetcher) Capabilities() Capabilities {
+	return Capabilities{
+		Provider:          "alphavantage",
+		Timeframes:        supportedTimeframes,
+		SymbolsPerRequest: 1,
+		QuotesPerRequest:  bulkQuoteBatchSize,
+	}
+}
+
+// Capabilities returns what Binance serves: every timeframe with the full history in pages of 1000 candles,
+// and no bulk quotes
+func (f *BinanceFetcher) Capabilities() Capabilities {
+	return Capabilities{
+		Provider:          "binance",
+		Timeframes:        supportedTimeframes,
+		PageSize:          binanceMaxLimit,
+		SymbolsPerRequest: 1,
+	}
+}
+
+// Check returns an error naming the setting the provider cannot serve: the timeframe or the number of candles
+// An outputSize of 0 requests no particular history length
+func (c Capabilities) Check(timeframe string, outputSize int) error {
+	if !slices.Contains(c.Timeframes, timeframe) {
+		return fmt.Errorf("provider %s does not serve %s candles (TIMEFRAME must be one of %s)",
+			c.Provider, timeframe, strings.Join(c.Timeframes, ", "))
+	}
+	if c.MaxHistory > 0 && outputSize > c.MaxHistory {
+		return fmt.Errorf("provider %s serves at most %d candles per symbol, but OUTPUT_SIZE is %d", c.Provider, c.MaxHistory, outputSize)
+	}
+	return nil
+}
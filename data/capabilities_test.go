@@ -0,0 +1,37 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCapabilitiesCheck(t *testing.T) {
+	alphaVantage := NewStockDataFetcher("key", "http://localhost", "daily").Capabilities()
+	binance := NewBinanceFetcher("http://localhost", "daily").Capabilities()
+
+	tests := []struct {
+		name         string
+		capabilities Capabilities
+		timeframe    string
+		outputSize   int
+		wantErr      string
+	}{
+		{"full history", alphaVantage, "daily", 5000, ""},
+		{"within the kline limit", binance, "60min", 1000, ""},
+		{"no particular history", binance, "daily", 0, ""},
+		{"paged beyond the kline limit", binance, "daily", 1500, ""},
+		{"beyond a capped history", Capabilities{Provider: "capped", Timeframes: supportedTimeframes, MaxHistory: 1000}, "daily", 1500, "at most 1000 candles"},
+		{"unknown timeframe", alphaVantage, "2hour", 200, "does not serve 2hour candles"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.capabilities.Check(test.timeframe, test.outputSize)
+			if test.wantErr == "" && err != nil {
+				t.Errorf("Check = %v, want no error", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("Check = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
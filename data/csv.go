@@ -0,0 +1,238 @@
+package data
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// csvFields names the candle fields a CSV layout maps onto columns, in the order of CSVLayout.Columns
+var csvFields = []string{"date", "open", "high", "low", "close", "volume"}
+
+// CSVLayout describes how candle CSV files are named and which columns hold the candle fields
+type CSVLayout struct {
+	FilePattern string    // File name of a symbol; {symbol} and {timeframe} are replaced, e.g. "{symbol}_{timeframe}.csv"
+	Columns     [6]string // Header names of the date, open, high, low, close, and volume columns (matched case-insensitively)
+	DateFormat  string    // Go time layout of the date column, or "unix" / "unixms" for epoch seconds / milliseconds
+	Comma       rune      // Field delimiter
+}
+
+// DefaultCSVLayout returns the layout of common exports: SYMBOL.csv with a Date,Open,High,Low,Close,Volume header
+func DefaultCSVLayout() CSVLayout {
+	return CSVLayout{
+		FilePattern: "{symbol}.csv",
+		Columns:     [6]string{"date", "open", "high", "low", "close", "volume"},
+		DateFormat:  "2006-01-02",
+		Comma:       ',',
+	}
+}
+
+// CSVCandleSource loads candles from per-symbol CSV files in a directory so scans can run fully offline
+// It serves every timeframe the files hold, with no quota; each fetch reads the symbol's file again
+type CSVCandleSource struct {
+	dir       string    // Directory holding the CSV files
+	timeframe string    // Candle timeframe, available to the file pattern
+	layout    CSVLayout // File naming and column layout
+	reads     int64     // Number of files read (updated atomically)
+}
+
+// NewCSVCandleSource creates a CSV candle source over dir; an empty timeframe defaults to daily candles
+func NewCSVCandleSource(dir, timeframe string, layout CSVLayout) *CSVCandleSource {
+	if timeframe == "" {
+		timeframe = "daily"
+	}
+	return &CSVCandleSource{
+		dir:       dir,       // Store the directory the files are read from
+		timeframe: timeframe, // Store the timeframe for file names and candle data
+		layout:    layout,    // Store the file and column layout
+	}
+}
+
+// Capabilities returns what CSV files serve: every timeframe with the full history they hold, and no bulk quotes
+func (s *CSVCandleSource) Capabilities() Capabilities {
+	return Capabilities{
+		Provider:          "csv",
+		Timeframes:        supportedTimeframes,
+		SymbolsPerRequest: 1,
+	}
+}
+
+// SetQuota is a no-op: reading local files costs no API requests
+func (s *CSVCandleSource) SetQuota(quota *QuotaTracker) {}
+
+// SetTimeout is a no-op: local files are read without HTTP requests
+func (s *CSVCandleSource) SetTimeout(timeout time.Duration) {}
+
+// SetHTTPClient is a no-op: local files are read without HTTP requests
+func (s *CSVCandleSource) SetHTTPClient(client *http.Client) {}
+
+// SetRetryPolicy is a no-op: local file reads have no transient failures to retry
+func (s *CSVCandleSource) SetRetryPolicy(policy RetryPolicy) {}
+
+// RequestCount returns the number of files read so far (thread-safe)
+func (s *CSVCandleSource) RequestCount() int {
+	return int(atomic.LoadInt64(&s.reads))
+}
+
+// Path returns the file the candles of a symbol are read from
+func (s *CSVCandleSource) Path(symbol string) string {
+	name := strings.NewReplacer("{symbol}", strings.ToUpper(symbol), "{timeframe}", s.timeframe).Replace(s.layout.FilePattern)
+	return filepath.Join(s.dir, name)
+}
+
+// FetchStockData returns the newest outputSize candles of a symbol sorted oldest first (0 returns the whole file)
+// A symbol without a file fails like a fetch error, without blacklisting it, so files can be added later
+func (s *CSVCandleSource) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	if err := ctx.Err(); err != nil {
+		return models.CandleData{}, err
+	}
+	candles, err := s.read(symbol)
+	if err != nil {
+		return models.CandleData{}, err
+	}
+	if outputSize > 0 && len(candles) > outputSize {
+		candles = candles[len(candles)-outputSize:]
+	}
+	return models.CandleData{Timeframe: s.timeframe, Candles: candles}, nil
+}
+
+// FetchHistory returns every candle of a symbol opened since from; a zero from returns the whole file
+func (s *CSVCandleSource) FetchHistory(symbol string, from time.Time) (models.CandleData, error) {
+	candles, err := s.read(symbol)
+	if err != nil {
+		return models.CandleData{}, err
+	}
+	first := sort.Search(len(candles), func(i int) bool { return !candles[i].Time().Before(from) })
+	if first == len(candles) {
+		return models.CandleData{}, fmt.Errorf("no candles in %s since %s", s.Path(symbol), from.Format("2006-01-02"))
+	}
+	return models.CandleData{Timeframe: s.timeframe, Candles: candles[first:]}, nil
+}
+
+// read parses the CSV file of a symbol into candles sorted oldest first
+func (s *CSVCandleSource) read(symbol string) ([]models.Candle, error) {
+	atomic.AddInt64(&s.reads, 1)
+	path := s.Path(symbol)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no candle file %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open candle file: %v", err)
+	}
+	defer file.Close()
+
+	candles, err := parseCandleCSV(file, s.layout, IsIntradayTimeframe(s.timeframe))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("invalid candle file %s: no candles", path)
+	}
+	return candles, nil
+}
+
+// parseCandleCSV reads candles with a header row naming the layout's columns; rows that cannot be traded on are skipped
+// Candles are sorted oldest first and deduplicated by time, the last row of a period winning
+func parseCandleCSV(r io.Reader, layout CSVLayout, intraday bool) ([]models.Candle, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = layout.Comma
+	reader.FieldsPerRecord = -1 // Tolerate trailing empty columns
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+
+	var index [6]int
+	for i, column := range layout.Columns {
+		index[i] = -1
+		for j, name := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), column) {
+				index[i] = j
+			}
+		}
+		if index[i] < 0 && csvFields[i] != "volume" {
+			return nil, fmt.Errorf("missing %s column %q in header %q", csvFields[i], column, strings.Join(header, string(layout.Comma)))
+		}
+	}
+
+	var rows []models.Candle
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %v", err)
+		}
+		field := func(i int) string {
+			if index[i] < 0 || index[i] >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index[i]])
+		}
+
+		timestamp, err := parseCSVTime(field(0), layout.DateFormat)
+		if err != nil {
+			continue // Skip rows without a readable date, such as footers
+		}
+		var prices [4]float64
+		valid := true
+		for i := range prices {
+			if prices[i], err = strconv.ParseFloat(field(i+1), 64); err != nil {
+				valid = false
+				break
+			}
+		}
+		volume, err := strconv.ParseFloat(field(5), 64)
+		if err != nil {
+			volume = 0 // Volume is optional; some exports leave it out for indices
+		}
+		if !valid || !validPrices(prices[0], prices[1], prices[2], prices[3]) || volume < 0 {
+			continue // Skip rows that cannot be traded on
+		}
+
+		candle := models.Candle{
+			Date:      time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, time.UTC),
+			Timestamp: timestamp,
+			Open:      prices[0],
+			High:      prices[1],
+			Low:       prices[2],
+			Close:     prices[3],
+			Volume:    int64(volume),
+		}
+		if !intraday {
+			candle.Timestamp = candle.Date
+		}
+		rows = append(rows, candle)
+	}
+	return stitchCandles(rows), nil
+}
+
+// parseCSVTime parses a date column in a Go time layout, or as epoch seconds ("unix") or milliseconds ("unixms")
+func parseCSVTime(value, format string) (time.Time, error) {
+	switch format {
+	case "unix", "unixms":
+		epoch, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if format == "unixms" {
+			return time.UnixMilli(epoch).UTC(), nil
+		}
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+	return time.ParseInLocation(format, value, time.UTC)
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCSVCandleSourceReadsDefaultLayout(t *testing.T) {
+	dir := t.TempDir()
+	content := "\ufeffDate,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2024-03-12,11,12,10,11.5,11.5,1200\n" +
+		"2024-03-11,10,11,9,10.5,10.5,1000\n" + // Exports newest first are sorted
+		"2024-03-13,12,11,13,12,12,900\n" + // Low above high
+		"2024-03-14,null,null,null,null,null,0\n" +
+		"2024-03-15,12,13,11.5,12.5,12.5,1500\n"
+	if err := os.WriteFile(filepath.Join(dir, "AAPL.csv"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewCSVCandleSource(dir, "daily", DefaultCSVLayout())
+	candleData, err := source.FetchStockData(context.Background(), "aapl", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles := candleData.Candles
+	checkCandles(t, candles)
+	if len(candles) != 3 || !candles[0].Date.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)) || candles[2].Close != 12.5 {
+		t.Fatalf("candles = %+v, want the 3 tradable rows oldest first", candles)
+	}
+	if candles[1].Volume != 1200 {
+		t.Errorf("volume = %d, want 1200", candles[1].Volume)
+	}
+
+	if candleData, err = source.FetchStockData(context.Background(), "AAPL", 2); err != nil || len(candleData.Candles) != 2 || candleData.Candles[1].Close != 12.5 {
+		t.Errorf("FetchStockData(2) = %+v, %v, want the newest 2 candles", candleData.Candles, err)
+	}
+	if candleData, err = source.FetchHistory("AAPL", time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)); err != nil || len(candleData.Candles) != 2 {
+		t.Errorf("FetchHistory = %+v, %v, want the 2 candles since 2024-03-12", candleData.Candles, err)
+	}
+	if _, err := source.FetchStockData(context.Background(), "MSFT", 0); err == nil {
+		t.Error("FetchStockData of a symbol without a file succeeded")
+	}
+	if source.RequestCount() != 4 {
+		t.Errorf("RequestCount = %d, want 4 file reads", source.RequestCount())
+	}
+}
+
+func TestCSVCandleSourceCustomLayout(t *testing.T) {
+	dir := t.TempDir()
+	content := "time;o;h;l;c\n" +
+		"1710241200000;10;11;9;10.5\n" +
+		"1710244800000;10.5;12;10;11.5\n"
+	if err := os.WriteFile(filepath.Join(dir, "BTCUSDT_60min.txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := CSVLayout{
+		FilePattern: "{symbol}_{timeframe}.txt",
+		Columns:     [6]string{"time", "o", "h", "l", "c", "vol"},
+		DateFormat:  "unixms",
+		Comma:       ';',
+	}
+	candleData, err := NewCSVCandleSource(dir, "60min", layout).FetchStockData(context.Background(), "BTCUSDT", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles := candleData.Candles
+	checkCandles(t, candles)
+	if len(candles) != 2 || !candles[1].Timestamp.Equal(time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC)) || candles[1].Volume != 0 {
+		t.Fatalf("candles = %+v, want 2 hourly candles without volume", candles)
+	}
+
+	layout.Columns[3] = "low"
+	if _, err := NewCSVCandleSource(dir, "60min", layout).FetchStockData(context.Background(), "BTCUSDT", 0); err == nil {
+		t.Error("a layout naming a missing low column was accepted")
+	}
+}
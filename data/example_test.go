@@ -0,0 +1,31 @@
+package data_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/erhankrygt/sapan/data"
+)
+
+func ExampleStockDataFetcher_FetchStockData() {
+	fetcher := data.NewStockDataFetcher("YOUR_API_KEY", "https://www.alphavantage.co/query", "daily")
+	candleData, err := fetcher.FetchStockData(context.Background(), "AAPL", 300)
+	if err != nil {
+		log.Fatal(err)
+	}
+	last := candleData.Candles[len(candleData.Candles)-1]
+	fmt.Printf("%s closed at %.2f on %s\n", "AAPL", last.Close, last.Date.Format("2006-01-02"))
+}
+
+func ExampleStockFilter_Apply() {
+	stocks, err := data.NewStockListLoader().LoadStocksFromPatterns("dist/*.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter, err := data.NewStockFilter([]string{"Technology"}, nil, nil, nil, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(filter.Apply(stocks).Stocks), "technology stocks")
+}
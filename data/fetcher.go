@@ -0,0 +1,411 @@
+// Package data provides data fetching and loading functionality for the SAPAN strategy
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Fetcher retrieves the candle history of a symbol; ctx bounds the fetch so a hung request cannot stall the caller
+// *StockDataFetcher calls Alpha Vantage; distributed scans fetch through remote workers and tests use sapantest.Fetcher
+type Fetcher interface {
+	FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error)
+}
+
+// DefaultHTTPTimeout bounds a single provider HTTP request until SetTimeout configures another limit
+const DefaultHTTPTimeout = 30 * time.Second
+
+// StockDataFetcher handles fetching stock data from external APIs
+// This struct encapsulates the API key and URL, providing methods to fetch historical stock data
+type StockDataFetcher struct {
+	apiKey    string        // Alpha Vantage API key for authentication
+	apiURL    string        // Alpha Vantage API base URL
+	timeframe string        // Candle timeframe (daily, weekly, monthly, or an intraday interval)
+	client    *http.Client  // HTTP client pooling connections and bounding every request by the configured timeout
+	retry     RetryPolicy   // Retries of transient failures
+	requests  int64         // Number of API requests made (updated atomically)
+	quota     *QuotaTracker // Daily quota every request is counted against (nil for no quota)
+	adjusted  bool          // Fetch the split- and dividend-adjusted series
+	adjust    bool          // Convert candles to adjusted prices
+}
+
+// NewStockDataFetcher creates a new stock data fetcher with the provided API key, URL, and timeframe
+// The API key and URL are required for authenticating requests to the Alpha Vantage API
+// An empty timeframe defaults to daily candles
+func NewStockDataFetcher(apiKey, apiURL, timeframe string) *StockDataFetcher {
+	if timeframe == "" {
+		timeframe = "daily"
+	}
+	return &StockDataFetcher{
+		apiKey:    apiKey,                           // Store the API key for use in HTTP requests
+		apiURL:    apiURL,                           // Store the API URL for constructing requests
+		timeframe: timeframe,                        // Store the timeframe for selecting the API function
+		client:    pooledClient(DefaultHTTPTimeout), // Share pooled connections and bound every request
+		retry:     DefaultRetryPolicy(),             // Retry transient failures
+	}
+}
+
+// SetTimeout bounds every HTTP request of the fetcher; 0 leaves requests bounded only by their context
+// The fetcher keeps its connection pool
+func (f *StockDataFetcher) SetTimeout(timeout time.Duration) {
+	f.client = &http.Client{Transport: f.client.Transport, Timeout: timeout}
+}
+
+// SetHTTPClient sends every request through client, typically one made by NewHTTPClient and shared by every fetcher
+// The client's own timeout replaces the fetcher's
+func (f *StockDataFetcher) SetHTTPClient(client *http.Client) {
+	f.client = client
+}
+
+// SetRetryPolicy sets how timeouts, 5xx responses, and rate-limit notes are retried; the default is DefaultRetryPolicy
+func (f *StockDataFetcher) SetRetryPolicy(policy RetryPolicy) {
+	f.retry = policy
+}
+
+// SetAdjusted fetches the split- and dividend-adjusted daily, weekly, and monthly series (a premium endpoint),
+// recording each candle's adjusted close, split coefficient, and dividend amount next to the as-traded prices
+// With prices set the candles are converted to adjusted prices, so indicators do not break around splits
+func (f *StockDataFetcher) SetAdjusted(adjusted, prices bool) {
+	f.adjusted = adjusted || prices
+	f.adjust = prices
+}
+
+// compactOutputSize is the number of candles Alpha Vantage returns for outputsize=compact
+const compactOutputSize = 100
+
+// apiOutputSize maps a requested candle count onto Alpha Vantage's compact/full output size
+// Alpha Vantage only understands "compact" (latest 100 candles) and "full" (entire history)
+func apiOutputSize(outputSize int) string {
+	if outputSize > 0 && outputSize <= compactOutputSize {
+		return "compact"
+	}
+	return "full"
+}
+
+// RequestCount returns the number of API requests made so far (thread-safe)
+// This is used to report quota usage at the end of a run
+func (f *StockDataFetcher) RequestCount() int {
+	return int(atomic.LoadInt64(&f.requests))
+}
+
+// SetQuota counts every API request against a daily quota and refuses requests once it is used up
+func (f *StockDataFetcher) SetQuota(quota *QuotaTracker) {
+	f.quota = quota
+}
+
+// countRequest reserves one request from the quota and counts it, returning ErrQuotaExhausted when none is left
+func (f *StockDataFetcher) countRequest() error {
+	if f.quota != nil {
+		if err := f.quota.Reserve(); err != nil {
+			return err
+		}
+	}
+	atomic.AddInt64(&f.requests, 1)
+	return nil
+}
+
+// timeSeriesFunction returns the Alpha Vantage function and optional interval for the fetcher's timeframe
+// Intraday series have no adjusted variant; Alpha Vantage adjusts them by default
+func (f *StockDataFetcher) timeSeriesFunction() (function, interval string) {
+	suffix := ""
+	if f.adjusted {
+		suffix = "_ADJUSTED"
+	}
+	switch f.timeframe {
+	case "weekly":
+		return "TIME_SERIES_WEEKLY" + suffix, ""
+	case "monthly":
+		return "TIME_SERIES_MONTHLY" + suffix, ""
+	default:
+		if IsIntradayTimeframe(f.timeframe) {
+			return "TIME_SERIES_INTRADAY", f.timeframe
+		}
+		return "TIME_SERIES_DAILY" + suffix, ""
+	}
+}
+
+// IsIntradayTimeframe reports whether a timeframe is an intraday interval such as 5min
+func IsIntradayTimeframe(timeframe string) bool {
+	return models.TimeframeDuration(timeframe) > 0
+}
+
+// FetchStockData fetches historical stock data for a given symbol from Alpha Vantage API
+// This method constructs the API URL, makes the HTTP request, and processes the response
+// Returns CandleData containing the most recent outputSize candles sorted oldest first (all candles when outputSize <= 0)
+func (f *StockDataFetcher) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	// Construct the API URL with the required parameters using the configured base URL
+	function, interval := f.timeSeriesFunction()
+	url := fmt.Sprintf(
+		"%s?function=%s&symbol=%s&outputsize=%s&apikey=%s",
+		f.apiURL, function, symbol, apiOutputSize(outputSize), f.apiKey,
+	)
+	if interval != "" {
+		url += "&interval=" + interval // Intraday series require an explicit interval
+	}
+
+	// Make HTTP GET requests to the Alpha Vantage API, retrying transient failures
+	var candleData models.CandleData
+	err := f.retry.do(ctx, func() error {
+		var err error
+		candleData, err = f.fetch(ctx, url, outputSize)
+		return err
+	})
+	return candleData, err
+}
+
+// fetch makes one request to the Alpha Vantage API; transient failures are marked for retry
+func (f *StockDataFetcher) fetch(ctx context.Context, url string, outputSize int) (models.CandleData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return models.CandleData{}, fmt.Errorf("failed to create request: %v", err)
+	}
+	if err := f.countRequest(); err != nil {
+		return models.CandleData{}, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return models.CandleData{}, requestError(ctx, fmt.Errorf("failed to fetch data: %v", err))
+	}
+	defer resp.Body.Close() // Ensure response body is closed
+
+	// Read the entire response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models.CandleData{}, requestError(ctx, fmt.Errorf("failed to read response: %v", err))
+	}
+	if transientStatus(resp.StatusCode) {
+		return models.CandleData{}, transient(fmt.Errorf("API error: HTTP %d", resp.StatusCode))
+	}
+
+	return f.parseResponse(body, outputSize)
+}
+
+// FetchHistory fetches the full history Alpha Vantage serves for a symbol and drops candles opened before from
+// A zero from keeps every candle; intraday series only reach back about a month
+func (f *StockDataFetcher) FetchHistory(symbol string, from time.Time) (models.CandleData, error) {
+	candleData, err := f.FetchStockData(context.Background(), symbol, 0)
+	if err != nil {
+		return models.CandleData{}, err
+	}
+	first := sort.Search(len(candleData.Candles), func(i int) bool { return !candleData.Candles[i].Time().Before(from) })
+	if first == len(candleData.Candles) {
+		return models.CandleData{}, fmt.Errorf("invalid API response: no candles since %s", from.Format("2006-01-02"))
+	}
+	candleData.Candles = candleData.Candles[first:]
+	return candleData, nil
+}
+
+// parseResponse converts an Alpha Vantage response body into candles
+// Error payloads and series without a single usable candle are reported as errors instead of empty successes
+func (f *StockDataFetcher) parseResponse(body []byte, outputSize int) (models.CandleData, error) {
+	// Parse the JSON response into our CandleResponse structure
+	var avResponse models.CandleResponse
+	if err := json.Unmarshal(body, &avResponse); err != nil {
+		return models.CandleData{}, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	// Non-daily series use a different key (e.g. "Weekly Time Series", "Time Series (5min)")
+	if len(avResponse.TimeSeries) == 0 {
+		if err := decodeAnyTimeSeries(body, &avResponse); err != nil {
+			return models.CandleData{}, fmt.Errorf("failed to parse JSON: %v", err)
+		}
+	}
+
+	// Handle API errors (rate limits, invalid symbols, etc.)
+	if len(avResponse.TimeSeries) == 0 {
+		var errorResp map[string]interface{}
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			// Check for rate limit message
+			if note, ok := errorResp["Note"]; ok {
+				return models.CandleData{}, transient(fmt.Errorf("API rate limit: %v", note))
+			}
+			// Check for premium endpoint or quota message
+			if information, ok := errorResp["Information"]; ok {
+				return models.CandleData{}, fmt.Errorf("API error: %v", information)
+			}
+			// Check for error message
+			if errorMsg, ok := errorResp["Error Message"]; ok {
+				return models.CandleData{}, fmt.Errorf("API error: %v: %w", errorMsg, ErrSymbolRejected)
+			}
+		}
+
+		return models.CandleData{}, fmt.Errorf("invalid API response")
+	}
+
+	// Convert the raw API response to our CandleData structure, dating candles in the exchange timezone
+	candles, dropped := f.convertToCandles(avResponse.TimeSeries, exchangeLocation(body))
+	if len(candles) == 0 {
+		return models.CandleData{}, fmt.Errorf("invalid API response: none of the %d candles could be parsed", len(avResponse.TimeSeries))
+	}
+	if f.adjust {
+		candles = AdjustedPrices(candles) // Before trimming, so splits of dropped candles still scale the volume
+	}
+	if outputSize > 0 && len(candles) > outputSize {
+		candles = candles[len(candles)-outputSize:] // Keep only the most recent candles
+	}
+	for len(dropped) > 0 && dropped[0].Time().Before(candles[0].Time()) {
+		dropped = dropped[1:] // Older than the kept history, so no indicator misses them
+	}
+	return models.CandleData{Timeframe: f.timeframe, Candles: candles, Dropped: dropped}, nil
+}
+
+// decodeAnyTimeSeries locates the time series object in a response regardless of its timeframe-specific key
+func decodeAnyTimeSeries(body []byte, avResponse *models.CandleResponse) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if strings.Contains(key, "Time Series") {
+			return json.Unmarshal(value, &avResponse.TimeSeries)
+		}
+	}
+	return nil // No series present; the caller reports the API error
+}
+
+// exchangeLocation returns the exchange timezone named in the response metadata (e.g. "US/Eastern")
+// The metadata key number differs per series ("5. Time Zone", "6. Time Zone"), so keys are matched by suffix
+// UTC is returned when the metadata is missing or names an unknown zone
+func exchangeLocation(body []byte) *time.Location {
+	var response struct {
+		MetaData map[string]string `json:"Meta Data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return time.UTC
+	}
+	for key, value := range response.MetaData {
+		if strings.HasSuffix(key, "Time Zone") {
+			if location, err := time.LoadLocation(strings.TrimSpace(value)); err == nil {
+				return location
+			}
+		}
+	}
+	return time.UTC
+}
+
+// convertToCandles converts the raw API response to our Candle models
+// This method parses string values from the API response and converts them to proper data types
+// Dates are interpreted in the exchange timezone so session-based checks see the real trading day
+// It also sorts the candles by date in ascending order for proper chronological analysis
+// Bars that parse but cannot have traded are returned separately as dropped, so the caller can report them
+func (f *StockDataFetcher) convertToCandles(timeSeries map[string]models.TimeSeriesEntry, location *time.Location) (candles, dropped []models.Candle) {
+	// Pre-allocate slice with capacity to avoid reallocations
+	candles = make([]models.Candle, 0, len(timeSeries))
+
+	// Iterate through each date in the time series
+	for dateStr, data := range timeSeries {
+		// Parse the date string (format: "2006-01-02", or "2006-01-02 15:04:05" for intraday series)
+		timestamp, err := time.ParseInLocation("2006-01-02", dateStr, location)
+		if err != nil {
+			timestamp, err = time.ParseInLocation("2006-01-02 15:04:05", dateStr, location)
+			if err != nil {
+				continue // Skip invalid dates
+			}
+		}
+		date := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, location) // Session the candle belongs to
+
+		// Parse opening price from string to float64
+		open, err := strconv.ParseFloat(data.Open, 64)
+		if err != nil {
+			continue // Skip if parsing fails
+		}
+
+		// Parse high price from string to float64
+		high, err := strconv.ParseFloat(data.High, 64)
+		if err != nil {
+			continue // Skip if parsing fails
+		}
+
+		// Parse low price from string to float64
+		low, err := strconv.ParseFloat(data.Low, 64)
+		if err != nil {
+			continue // Skip if parsing fails
+		}
+
+		// Parse closing price from string to float64
+		closePrice, err := strconv.ParseFloat(data.Close, 64)
+		if err != nil {
+			continue // Skip if parsing fails
+		}
+
+		// Parse volume from string to int64 (adjusted series report it as "6. volume")
+		volumeText := data.Volume
+		if volumeText == "" {
+			volumeText = data.AdjustedVolume
+		}
+		volume, err := strconv.ParseInt(volumeText, 10, 64)
+		if err != nil {
+			continue // Skip if parsing fails
+		}
+
+		// Create a new Candle with the parsed data
+		candle := models.Candle{
+			Date:      date,       // Trading session date
+			Timestamp: timestamp,  // Start of the period
+			Open:      open,       // Opening price
+			High:      high,       // Highest price
+			Low:       low,        // Lowest price
+			Close:     closePrice, // Closing price
+			Volume:    volume,     // Trading volume
+
+			AdjustedClose:    adjustedValue(data.AdjustedClose),    // Adjusted close (adjusted series only)
+			SplitCoefficient: adjustedValue(data.SplitCoefficient), // Split coefficient (daily adjusted series only)
+			DividendAmount:   adjustedValue(data.DividendAmount),   // Dividend amount (adjusted series only)
+		}
+
+		// Set aside candles that cannot be traded on: non-finite or negative prices, an inverted range, or negative volume
+		if !validPrices(open, high, low, closePrice) || volume < 0 {
+			dropped = append(dropped, candle)
+			continue
+		}
+		candles = append(candles, candle)
+	}
+
+	// Sort candles by date in ascending order (oldest first)
+	// This is crucial for proper technical analysis as indicators depend on chronological order
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Time().Before(candles[j].Time())
+	})
+	sort.Slice(dropped, func(i, j int) bool {
+		return dropped[i].Time().Before(dropped[j].Time())
+	})
+
+	// Drop repeated timestamps (e.g. "2024-01-02" next to "2024-01-02 00:00:00") so every candle is unique
+	unique := candles[:0]
+	for _, candle := range candles {
+		if len(unique) > 0 && unique[len(unique)-1].Time().Equal(candle.Time()) {
+			continue
+		}
+		unique = append(unique, candle)
+	}
+	return unique, dropped
+}
+
+// validPrices reports whether the OHLC values are finite, non-negative, and form a consistent range
+func validPrices(open, high, low, closePrice float64) bool {
+	for _, price := range []float64{open, high, low, closePrice} {
+		if math.IsNaN(price) || math.IsInf(price, 0) || price < 0 {
+			return false
+		}
+	}
+	return low <= high
+}
+
+// adjustedValue parses an optional value of an adjusted series, returning 0 when it is missing, non-finite, or negative
+func adjustedValue(text string) float64 {
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,195 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// timeSeriesEntry is the value type of models.CandleResponse.TimeSeries
+type timeSeriesEntry = models.TimeSeriesEntry
+
+// checkCandles fails the test unless candles are finite, consistent, strictly ascending by time, and dated by session
+func checkCandles(t *testing.T, candles []models.Candle) {
+	t.Helper()
+	for i, candle := range candles {
+		for _, price := range []float64{candle.Open, candle.High, candle.Low, candle.Close} {
+			if math.IsNaN(price) || math.IsInf(price, 0) || price < 0 {
+				t.Fatalf("candle %d has invalid price %v", i, price)
+			}
+		}
+		if candle.Low > candle.High {
+			t.Fatalf("candle %d has low %v above high %v", i, candle.Low, candle.High)
+		}
+		if candle.Volume < 0 {
+			t.Fatalf("candle %d has negative volume %d", i, candle.Volume)
+		}
+		if i > 0 && !candles[i-1].Time().Before(candle.Time()) {
+			t.Fatalf("candle %d (%s) is not after candle %d (%s)", i, candle.Time(), i-1, candles[i-1].Time())
+		}
+		if year, month, day := candle.Time().Date(); !candle.Date.Equal(time.Date(year, month, day, 0, 0, 0, 0, candle.Time().Location())) {
+			t.Fatalf("candle %d has session date %s for timestamp %s", i, candle.Date, candle.Time())
+		}
+	}
+}
+
+func FuzzConvertToCandles(f *testing.F) {
+	f.Add("2024-01-02", "100.5", "101.25", "99.75", "100.9", "123456", "2024-01-03", "101", "102", "100", "101.5", "654321")
+	f.Add("2024-01-02 15:30:00", "10", "11", "9", "10.5", "100", "2024-01-02", "10", "11", "9", "10.5", "100")
+	f.Add("2024-01-02", "NaN", "Inf", "-1", "1e400", "-5", "not-a-date", "", "", "", "", "")
+	f.Add("2024-01-02", "100", "90", "110", "95", "0", "2024-13-45", "1", "1", "1", "1", "1")
+
+	fetcher := NewStockDataFetcher("", "", "daily")
+	f.Fuzz(func(t *testing.T, date1, open1, high1, low1, close1, volume1, date2, open2, high2, low2, close2, volume2 string) {
+		series := map[string]timeSeriesEntry{
+			date1: {Open: open1, High: high1, Low: low1, Close: close1, Volume: volume1},
+			date2: {Open: open2, High: high2, Low: low2, Close: close2, Volume: volume2},
+		}
+		candles, dropped := fetcher.convertToCandles(series, time.UTC)
+		if len(candles)+len(dropped) > len(series) {
+			t.Fatalf("got %d candles and %d dropped bars from %d entries", len(candles), len(dropped), len(series))
+		}
+		checkCandles(t, candles)
+	})
+}
+
+func FuzzParseResponse(f *testing.F) {
+	f.Add([]byte(`{"Meta Data":{"5. Time Zone":"US/Eastern"},"Time Series (Daily)":{"2024-01-02":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100.5","5. volume":"1000"}}}`), 0)
+	f.Add([]byte(`{"Meta Data":{},"Weekly Time Series":{"2024-01-05":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100.5","5. volume":"1000"}}}`), 10)
+	f.Add([]byte(`{"Time Series (5min)":{"2024-01-02 09:35:00":{"1. open":"1","2. high":"1","3. low":"1","4. close":"1","5. volume":"1"}}}`), 1)
+	f.Add([]byte(`{"Time Series (Daily)":{"2024-01-02":{"1. open":"abc","2. high":"","3. low":"","4. close":"","5. volume":""}}}`), 0)
+	f.Add([]byte(`{"Time Series (Daily)":{"bad":{}}}`), 0)
+	f.Add([]byte(`{"Time Series (Daily)":[]}`), 0)
+	f.Add([]byte(`{"Note":"Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`), 0)
+	f.Add([]byte(`{"Information":"This is a premium endpoint."}`), 0)
+	f.Add([]byte(`{"Error Message":"Invalid API call."}`), 0)
+	f.Add([]byte(`{}`), 0)
+	f.Add([]byte(`[]`), 0)
+	f.Add([]byte(`null`), 0)
+	f.Add([]byte(`<html>502 Bad Gateway</html>`), 0)
+
+	fetcher := NewStockDataFetcher("", "", "daily")
+	f.Fuzz(func(t *testing.T, body []byte, outputSize int) {
+		candleData, err := fetcher.parseResponse(body, outputSize)
+		if err != nil {
+			if len(candleData.Candles) != 0 {
+				t.Fatalf("error %q returned together with %d candles", err, len(candleData.Candles))
+			}
+			return
+		}
+		if len(candleData.Candles) == 0 {
+			t.Fatalf("empty candle set returned as success for %q", body)
+		}
+		if outputSize > 0 && len(candleData.Candles) > outputSize {
+			t.Fatalf("got %d candles, want at most %d", len(candleData.Candles), outputSize)
+		}
+		if !json.Valid(body) {
+			t.Fatalf("candles returned for invalid JSON %q", body)
+		}
+		checkCandles(t, candleData.Candles)
+	})
+}
+
+func TestStockDataFetcherRetriesTransientFailures(t *testing.T) {
+	responses := []func(w http.ResponseWriter){
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+		func(w http.ResponseWriter) { fmt.Fprint(w, `{"Note":"Thank you for using Alpha Vantage!"}`) },
+		func(w http.ResponseWriter) {
+			fmt.Fprint(w, `{"Time Series (Daily)":{"2024-01-02":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100.5","5. volume":"1000"}}}`)
+		},
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		responses[min(requests, len(responses)-1)](w)
+		requests++
+	}))
+	defer server.Close()
+
+	fetcher := NewStockDataFetcher("key", server.URL, "daily")
+	fetcher.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	candleData, err := fetcher.FetchStockData(context.Background(), "AAPL", 100)
+	if err != nil || len(candleData.Candles) != 1 {
+		t.Fatalf("FetchStockData = %d candles, %v; want the third attempt to succeed", len(candleData.Candles), err)
+	}
+	if fetcher.RequestCount() != 3 {
+		t.Errorf("RequestCount() = %d, want every attempt counted", fetcher.RequestCount())
+	}
+
+	requests = 0
+	fetcher.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	_, err = fetcher.FetchStockData(context.Background(), "AAPL", 100)
+	if err == nil || !strings.Contains(err.Error(), "API rate limit") || !strings.Contains(err.Error(), "attempt 1: API error: HTTP 503") {
+		t.Errorf("FetchStockData error = %v, want the rate limit with the attempt history", err)
+	}
+}
+
+func TestStockDataFetcherAdjustedSeries(t *testing.T) {
+	// A 2-for-1 split takes effect on 2024-01-04
+	var function string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		function = r.URL.Query().Get("function")
+		fmt.Fprint(w, `{"Time Series (Daily)":{
+			"2024-01-02":{"1. open":"198","2. high":"204","3. low":"196","4. close":"200","5. adjusted close":"100","6. volume":"1000","7. dividend amount":"0.0000","8. split coefficient":"1.0"},
+			"2024-01-03":{"1. open":"200","2. high":"206","3. low":"198","4. close":"202","5. adjusted close":"101","6. volume":"1000","7. dividend amount":"0.5000","8. split coefficient":"1.0"},
+			"2024-01-04":{"1. open":"101","2. high":"103","3. low":"100","4. close":"102","5. adjusted close":"102","6. volume":"1000","7. dividend amount":"0.0000","8. split coefficient":"2.0"}}}`)
+	}))
+	defer server.Close()
+
+	fetcher := NewStockDataFetcher("key", server.URL, "daily")
+	fetcher.SetAdjusted(true, false)
+	candleData, err := fetcher.FetchStockData(context.Background(), "AAPL", 0)
+	if err != nil || len(candleData.Candles) != 3 {
+		t.Fatalf("FetchStockData = %d candles, %v; want 3", len(candleData.Candles), err)
+	}
+	if function != "TIME_SERIES_DAILY_ADJUSTED" {
+		t.Errorf("function = %s, want TIME_SERIES_DAILY_ADJUSTED", function)
+	}
+	first := candleData.Candles[0]
+	if first.Close != 200 || first.AdjustedClose != 100 || first.Volume != 1000 {
+		t.Errorf("as-traded candle = %+v, want close 200 with adjusted close 100", first)
+	}
+	if candleData.Candles[1].DividendAmount != 0.5 || candleData.Candles[2].SplitCoefficient != 2 {
+		t.Errorf("candles = %+v, want the dividend and split recorded", candleData.Candles)
+	}
+
+	fetcher.SetAdjusted(false, true)
+	if candleData, err = fetcher.FetchStockData(context.Background(), "AAPL", 0); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []struct {
+		high, close float64
+		volume      int64
+	}{{102, 100, 2000}, {103, 101, 2000}, {103, 102, 1000}} {
+		if candle := candleData.Candles[i]; candle.High != want.high || candle.Close != want.close || candle.Volume != want.volume {
+			t.Errorf("adjusted candle %d = high %.2f close %.2f volume %d, want %.2f/%.2f/%d",
+				i, candle.High, candle.Close, candle.Volume, want.high, want.close, want.volume)
+		}
+	}
+}
+
+func TestStockDataFetcherDoesNotRetryRejectedSymbols(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"Error Message":"Invalid API call."}`)
+	}))
+	defer server.Close()
+
+	fetcher := NewStockDataFetcher("key", server.URL, "daily")
+	fetcher.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if _, err := fetcher.FetchStockData(context.Background(), "NOPE", 100); !errors.Is(err, ErrSymbolRejected) {
+		t.Errorf("FetchStockData error = %v, want ErrSymbolRejected", err)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests for a rejected symbol, want 1", requests)
+	}
+}
@@ -0,0 +1,110 @@
+// Package data provides data fetching and loading functionality for the SAPAN strategy
+package data
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StockFilter narrows a loaded stock list without editing the list files
+// Sector and symbol comparisons are case-insensitive; empty criteria match everything
+type StockFilter struct {
+	includeSectors map[string]bool // Sectors to keep (empty keeps all sectors)
+	excludeSectors map[string]bool // Sectors to drop
+	includeSymbols map[string]bool // Symbols to keep (empty keeps all symbols)
+	excludeSymbols map[string]bool // Symbols to drop
+	symbolPattern  *regexp.Regexp  // Regular expression tickers must match (nil matches all)
+}
+
+// NewStockFilter creates a stock filter from include/exclude lists and an optional ticker regular expression
+func NewStockFilter(includeSectors, excludeSectors, includeSymbols, excludeSymbols []string, symbolPattern string) (*StockFilter, error) {
+	filter := &StockFilter{
+		includeSectors: toSet(includeSectors),
+		excludeSectors: toSet(excludeSectors),
+		includeSymbols: toSet(includeSymbols),
+		excludeSymbols: toSet(excludeSymbols),
+	}
+	if symbolPattern != "" {
+		pattern, err := regexp.Compile(symbolPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid symbol pattern %q: %v", symbolPattern, err)
+		}
+		filter.symbolPattern = pattern
+	}
+	return filter, nil
+}
+
+// Matches reports whether a stock passes every configured criterion
+// Exclusions win over inclusions, so a symbol can be dropped from an included sector
+func (f *StockFilter) Matches(stock models.Stock) bool {
+	symbol := normalizeKey(stock.Symbol)
+	sector := normalizeKey(stock.Sector)
+
+	if f.excludeSymbols[symbol] || f.excludeSectors[sector] {
+		return false
+	}
+	if len(f.includeSymbols) > 0 && !f.includeSymbols[symbol] {
+		return false
+	}
+	if len(f.includeSectors) > 0 && !f.includeSectors[sector] {
+		return false
+	}
+	if f.symbolPattern != nil && !f.symbolPattern.MatchString(strings.TrimSpace(stock.Symbol)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the stocks that match the filter, preserving their order
+func (f *StockFilter) Apply(stocks models.StockData) models.StockData {
+	var filtered models.StockData
+	for _, stock := range stocks.Stocks {
+		if f.Matches(stock) {
+			filtered.Stocks = append(filtered.Stocks, stock)
+		}
+	}
+	return filtered
+}
+
+// LimitStocks returns the first n stocks of the list (n <= 0 keeps every stock)
+func LimitStocks(stocks models.StockData, n int) models.StockData {
+	if n <= 0 || n >= len(stocks.Stocks) {
+		return stocks
+	}
+	return models.StockData{Stocks: stocks.Stocks[:n]}
+}
+
+// SampleStocks returns n stocks picked at random with the given seed, preserving their order (n <= 0 keeps every stock)
+// The same seed and list always yield the same sample, so a quick run can be repeated after a config change
+func SampleStocks(stocks models.StockData, n int, seed int64) models.StockData {
+	if n <= 0 || n >= len(stocks.Stocks) {
+		return stocks
+	}
+	picked := rand.New(rand.NewSource(seed)).Perm(len(stocks.Stocks))[:n]
+	sort.Ints(picked)
+	sampled := models.StockData{Stocks: make([]models.Stock, 0, n)}
+	for _, i := range picked {
+		sampled.Stocks = append(sampled.Stocks, stocks.Stocks[i])
+	}
+	return sampled
+}
+
+// toSet converts a list of names into a lookup set of normalized keys
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		if key := normalizeKey(value); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+// normalizeKey trims and upper-cases a symbol or sector for case-insensitive comparison
+func normalizeKey(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// stockList returns stocks with the given symbols in order
+func stockList(symbols ...string) models.StockData {
+	var stocks models.StockData
+	for _, symbol := range symbols {
+		stocks.Stocks = append(stocks.Stocks, models.Stock{Symbol: symbol})
+	}
+	return stocks
+}
+
+// symbolsOf returns the symbols of a stock list in order
+func symbolsOf(stocks models.StockData) []string {
+	symbols := make([]string, len(stocks.Stocks))
+	for i, stock := range stocks.Stocks {
+		symbols[i] = stock.Symbol
+	}
+	return symbols
+}
+
+func TestLimitStocksKeepsTheFirstN(t *testing.T) {
+	stocks := stockList("AAPL", "MSFT", "NVDA", "TSLA")
+	if got := symbolsOf(LimitStocks(stocks, 2)); len(got) != 2 || got[0] != "AAPL" || got[1] != "MSFT" {
+		t.Errorf("LimitStocks(2) = %v, want [AAPL MSFT]", got)
+	}
+	if got := LimitStocks(stocks, 0); len(got.Stocks) != 4 {
+		t.Errorf("LimitStocks(0) kept %d stocks, want all 4", len(got.Stocks))
+	}
+	if got := LimitStocks(stocks, 10); len(got.Stocks) != 4 {
+		t.Errorf("LimitStocks(10) kept %d stocks, want all 4", len(got.Stocks))
+	}
+}
+
+func TestSampleStocksIsRepeatableWithASeed(t *testing.T) {
+	stocks := stockList("A", "B", "C", "D", "E", "F", "G", "H", "I", "J")
+	first := symbolsOf(SampleStocks(stocks, 4, 42))
+	second := symbolsOf(SampleStocks(stocks, 4, 42))
+	if len(first) != 4 {
+		t.Fatalf("sample = %v, want 4 stocks", first)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("samples with the same seed differ: %v and %v", first, second)
+		}
+		if i > 0 && first[i-1] >= first[i] {
+			t.Errorf("sample %v is not in list order", first)
+		}
+	}
+	if got := SampleStocks(stocks, 0, 42); len(got.Stocks) != len(stocks.Stocks) {
+		t.Errorf("SampleStocks(0) kept %d stocks, want all %d", len(got.Stocks), len(stocks.Stocks))
+	}
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPClientOptions configures the HTTP client provider requests share
+type HTTPClientOptions struct {
+	Timeout         time.Duration // Timeout of a single request (0 leaves requests bounded only by their context)
+	MaxIdleConns    int           // Keep-alive connections kept open per host; at least the worker count lets every worker reuse one
+	IdleConnTimeout time.Duration // How long an unused connection stays open
+	ProxyURL        string        // Proxy every request goes through (empty uses HTTP_PROXY, HTTPS_PROXY, and NO_PROXY)
+}
+
+// DefaultHTTPClientOptions returns the options fetchers start with: 30s requests and up to 16 pooled connections per host
+func DefaultHTTPClientOptions() HTTPClientOptions {
+	return HTTPClientOptions{Timeout: DefaultHTTPTimeout, MaxIdleConns: 16, IdleConnTimeout: 90 * time.Second}
+}
+
+// sharedTransport is the connection pool of fetchers until SetHTTPClient gives them another client
+var sharedTransport = newTransport(DefaultHTTPClientOptions(), http.ProxyFromEnvironment)
+
+// pooledClient returns a client on the shared connection pool with the given request timeout
+func pooledClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: sharedTransport, Timeout: timeout}
+}
+
+// NewHTTPClient creates a client whose transport keeps connections alive and pools them, so concurrent workers
+// hitting the same host reuse connections instead of opening one per request; fetchers given the client share the pool
+func NewHTTPClient(options HTTPClientOptions) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", options.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: newTransport(options, proxy), Timeout: options.Timeout}, nil
+}
+
+// newTransport clones the default transport, keeping its dial and TLS timeouts, with the options' pool and proxy
+func newTransport(options HTTPClientOptions, proxy func(*http.Request) (*url.URL, error)) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.MaxIdleConns = options.MaxIdleConns
+	transport.MaxIdleConnsPerHost = options.MaxIdleConns // Providers are a handful of hosts, so each may use the whole pool
+	transport.IdleConnTimeout = options.IdleConnTimeout
+	return transport
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// klinesBody is a Binance response with a single daily kline
+const klinesBody = `[[1704153600000,"100","101","99","100.5","10",1704239999999,"1000"]]`
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	var connections int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, klinesBody)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	options := DefaultHTTPClientOptions()
+	options.MaxIdleConns = 4
+	client, err := NewHTTPClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := NewBinanceFetcher(server.URL, "daily")
+	fetcher.SetHTTPClient(client)
+
+	const workers, rounds = 4, 5
+	for round := 0; round < rounds; round++ {
+		var wg sync.WaitGroup
+		for worker := 0; worker < workers; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := fetcher.FetchStockData(context.Background(), "BTCUSDT", 1); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	if got := atomic.LoadInt64(&connections); got > workers {
+		t.Errorf("%d connections for %d requests from %d workers, want at most one per worker", got, workers*rounds, workers)
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // A proxy receives the absolute URL of the request
+		fmt.Fprint(w, klinesBody)
+	}))
+	defer proxy.Close()
+
+	options := DefaultHTTPClientOptions()
+	options.ProxyURL = proxy.URL
+	client, err := NewHTTPClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := NewBinanceFetcher("http://api.binance.invalid", "daily")
+	fetcher.SetHTTPClient(client)
+	if _, err := fetcher.FetchStockData(context.Background(), "BTCUSDT", 1); err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://api.binance.invalid/api/v3/klines?symbol=BTCUSDT&interval=1d&limit=1"; proxied != want {
+		t.Errorf("proxy received %q, want %q", proxied, want)
+	}
+
+	if _, err := NewHTTPClient(HTTPClientOptions{ProxyURL: "proxy:3128"}); err == nil {
+		t.Error("NewHTTPClient accepted a proxy URL without a scheme")
+	}
+}
@@ -0,0 +1,157 @@
+// Package data provides data fetching and loading functionality for the SAPAN strategy
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StockListLoader handles loading stock lists from JSON files
+// This struct provides methods to load stock symbols and metadata from local files
+type StockListLoader struct{}
+
+// NewStockListLoader creates a new stock list loader instance
+// This constructor initializes the loader for reading stock data from files
+func NewStockListLoader() *StockListLoader {
+	return &StockListLoader{}
+}
+
+// LoadStocksFromFile loads stock symbols and metadata from a JSON file
+// This method opens the file, uses a JSON decoder to parse the content, and returns StockData
+// The file should contain a JSON structure with a "Stocks" array containing stock information
+func (l *StockListLoader) LoadStocksFromFile(filename string) (models.StockData, error) {
+	// Open the file for reading
+	file, err := os.Open(filename)
+	if err != nil {
+		return models.StockData{}, err // Return empty StockData and error if file cannot be opened
+	}
+	defer file.Close() // Ensure file is closed when function returns
+
+	// Create a JSON decoder and decode the file content into StockData structure
+	var stocks models.StockData
+	err = json.NewDecoder(file).Decode(&stocks)
+	if err != nil {
+		return models.StockData{}, err // Return empty StockData and error if JSON parsing fails
+	}
+
+	// Return the successfully parsed stock data
+	return stocks, nil
+}
+
+// LoadStocksFromPatterns loads and merges stock lists from a comma-separated list of files or glob patterns
+// Lists are read in order (glob matches alphabetically) and symbols are de-duplicated case-insensitively,
+// keeping the first occurrence so per-sector or per-market lists can overlap safely
+func (l *StockListLoader) LoadStocksFromPatterns(patterns string) (models.StockData, error) {
+	files, err := expandStockFiles(patterns)
+	if err != nil {
+		return models.StockData{}, err
+	}
+
+	var merged models.StockData
+	seen := make(map[string]bool)
+	for _, filename := range files {
+		stocks, err := l.LoadStocksFromFile(filename)
+		if err != nil {
+			return models.StockData{}, fmt.Errorf("failed to load stock list %s: %v", filename, err)
+		}
+		for _, stock := range stocks.Stocks {
+			key := strings.ToUpper(strings.TrimSpace(stock.Symbol))
+			if key == "" || seen[key] {
+				continue // Skip blank symbols and symbols already listed in an earlier file
+			}
+			seen[key] = true
+			merged.Stocks = append(merged.Stocks, stock)
+		}
+	}
+	return merged, nil
+}
+
+// ReadSymbols reads newline-separated symbols, such as a shell pipeline writes to stdin
+// Blank lines and text after a # are ignored, and a line may hold several symbols separated by spaces or commas
+func ReadSymbols(r io.Reader) ([]string, error) {
+	var symbols []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		symbols = append(symbols, strings.FieldsFunc(line, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t' || c == '\r'
+		})...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols: %v", err)
+	}
+	return symbols, nil
+}
+
+// StocksFromSymbols builds a stock list from bare symbols for ad-hoc scans that bypass the stock list files
+// Symbols are upper-cased and de-duplicated, keeping their first position
+func StocksFromSymbols(symbols []string) models.StockData {
+	var stocks models.StockData
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		key := strings.ToUpper(strings.TrimSpace(symbol))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		stocks.Stocks = append(stocks.Stocks, models.Stock{Symbol: key})
+	}
+	return stocks
+}
+
+// expandStockFiles resolves comma-separated paths and glob patterns into a list of unique files
+// A glob that matches nothing is an error so a mistyped pattern never silently empties the scan
+func expandStockFiles(patterns string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid stock list pattern %q: %v", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("stock list pattern %q matched no files", pattern)
+			}
+			sort.Strings(matches)
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no stock list files configured")
+	}
+	return files, nil
+}
+
+// SaveStocksToFile writes a stock list as indented JSON, replacing the file atomically
+func (l *StockListLoader) SaveStocksToFile(filename string, stocks models.StockData) error {
+	data, err := json.MarshalIndent(stocks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stock list: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(filename, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write stock list: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadSymbolsAndStocksFromSymbols(t *testing.T) {
+	input := "aapl\n\n# watch closely\nMSFT, nvda  # megacaps\r\nAAPL\n"
+	symbols, err := ReadSymbols(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(symbols, " "); got != "aapl MSFT nvda AAPL" {
+		t.Errorf("ReadSymbols = %q, want every symbol in order", got)
+	}
+
+	stocks := StocksFromSymbols(symbols).Stocks
+	var got []string
+	for _, stock := range stocks {
+		got = append(got, stock.Symbol)
+	}
+	if strings.Join(got, " ") != "AAPL MSFT NVDA" {
+		t.Errorf("StocksFromSymbols = %v, want AAPL MSFT NVDA", got)
+	}
+}
@@ -0,0 +1,23 @@
+package data
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"sort"
+)
+
+// stitchCandles merges pages of candles fetched in several requests into one history sorted oldest first
+// Pages may overlap at their edges; a candle of a later page replaces the candle of the same period from an earlier one
+func stitchCandles(pages ...[]models.Candle) []models.Candle {
+	byTime := make(map[int64]models.Candle)
+	for _, page := range pages {
+		for _, candle := range page {
+			byTime[candle.Time().UnixNano()] = candle
+		}
+	}
+	candles := make([]models.Candle, 0, len(byTime))
+	for _, candle := range byTime {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Time().Before(candles[j].Time()) })
+	return candles
+}
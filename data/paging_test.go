@@ -0,0 +1,30 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestStitchCandlesDropsOverlap(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candle := func(offset int, close float64) models.Candle {
+		return models.Candle{Date: day.AddDate(0, 0, offset), Timestamp: day.AddDate(0, 0, offset), Open: 1, High: 2, Low: 0.5, Close: close}
+	}
+	newer := []models.Candle{candle(2, 1), candle(3, 1.5)}
+	older := []models.Candle{candle(0, 1), candle(1, 1), candle(2, 1.2)}
+
+	candles := stitchCandles(newer, older)
+	if len(candles) != 4 {
+		t.Fatalf("got %d candles, want 4", len(candles))
+	}
+	for i, c := range candles {
+		if !c.Date.Equal(day.AddDate(0, 0, i)) {
+			t.Errorf("candle %d opens %s, want %s", i, c.Date, day.AddDate(0, 0, i))
+		}
+	}
+	if candles[2].Close != 1.2 {
+		t.Errorf("overlapping candle close = %v, want the later page's 1.2", candles[2].Close)
+	}
+}
@@ -0,0 +1,214 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// overviewResponse mirrors the fields of the Alpha Vantage OVERVIEW response used by SAPAN
+type overviewResponse struct {
+	Symbol               string `json:"Symbol"`               // Stock ticker symbol
+	Name                 string `json:"Name"`                 // Company name
+	Exchange             string `json:"Exchange"`             // Listing exchange
+	Currency             string `json:"Currency"`             // Quote currency
+	Country              string `json:"Country"`              // Listing country
+	Sector               string `json:"Sector"`               // Business sector (upper case)
+	Industry             string `json:"Industry"`             // Industry (upper case)
+	MarketCapitalization string `json:"MarketCapitalization"` // Market capitalization as a decimal string
+	Note                 string `json:"Note"`                 // Rate limit message
+	Information          string `json:"Information"`          // Premium endpoint or quota message
+	ErrorMessage         string `json:"Error Message"`        // Invalid request message
+}
+
+// listingSuffixes maps Yahoo-style symbol suffixes to the listing of non-US markets the provider has no overview for
+var listingSuffixes = map[string]models.Stock{
+	".IS": {Exchange: "BIST", Currency: "TRY", Country: "Turkey"},
+	".L":  {Exchange: "LSE", Currency: "GBP", Country: "United Kingdom"},
+	".DE": {Exchange: "XETRA", Currency: "EUR", Country: "Germany"},
+	".PA": {Exchange: "EURONEXT", Currency: "EUR", Country: "France"},
+	".TO": {Exchange: "TSX", Currency: "CAD", Country: "Canada"},
+}
+
+// FetchProfile looks up the listing metadata of a symbol with the Alpha Vantage OVERVIEW endpoint
+// The returned stock holds whatever the provider knows; ISINs are not published by Alpha Vantage
+func (f *StockDataFetcher) FetchProfile(symbol string) (models.Stock, error) {
+	requestURL := fmt.Sprintf("%s?function=OVERVIEW&symbol=%s&apikey=%s", f.apiURL, url.QueryEscape(symbol), f.apiKey)
+
+	if err := f.countRequest(); err != nil {
+		return models.Stock{}, err
+	}
+	resp, err := f.client.Get(requestURL)
+	if err != nil {
+		return models.Stock{}, fmt.Errorf("failed to fetch profile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models.Stock{}, fmt.Errorf("failed to read profile response: %v", err)
+	}
+	var response overviewResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return models.Stock{}, fmt.Errorf("failed to parse profile response: %v", err)
+	}
+	switch {
+	case response.Note != "":
+		return models.Stock{}, fmt.Errorf("API rate limit: %s", response.Note)
+	case response.ErrorMessage != "":
+		return models.Stock{}, fmt.Errorf("API error: %s", response.ErrorMessage)
+	case response.Symbol == "" && response.Information != "":
+		return models.Stock{}, fmt.Errorf("API error: %s", response.Information)
+	}
+
+	marketCap, _ := strconv.ParseFloat(response.MarketCapitalization, 64)
+	return models.Stock{
+		Symbol:    symbol,
+		Name:      response.Name,
+		Exchange:  response.Exchange,
+		Currency:  response.Currency,
+		Country:   response.Country,
+		Sector:    titleCase(response.Sector),
+		Industry:  titleCase(response.Industry),
+		MarketCap: marketCap,
+	}, nil
+}
+
+// titleCase turns the provider's upper-case sector names ("TECHNOLOGY") into the stock lists' style ("Technology")
+func titleCase(value string) string {
+	words := strings.Fields(strings.ToLower(value))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// StockEnricher fills missing listing metadata of stocks from provider lookups
+// Profiles are cached in a JSON file so every symbol costs at most one API request across runs
+type StockEnricher struct {
+	fetcher      *StockDataFetcher       // Fetcher whose API key pays for the lookups
+	cachePath    string                  // JSON file holding cached profiles (empty keeps them in memory)
+	requestDelay time.Duration           // Delay after each lookup to respect API limits
+	profiles     map[string]models.Stock // Cached profiles keyed by upper-case symbol
+	dirty        bool                    // Whether profiles changed since the cache was loaded
+	mutex        sync.Mutex              // Serializes lookups and cache access
+}
+
+// NewStockEnricher creates an enricher and loads the profile cache at cachePath when it exists
+func NewStockEnricher(fetcher *StockDataFetcher, cachePath string, requestDelay time.Duration) (*StockEnricher, error) {
+	enricher := &StockEnricher{
+		fetcher:      fetcher,                       // Store the fetcher used for lookups
+		cachePath:    cachePath,                     // Store the cache location
+		requestDelay: requestDelay,                  // Store the delay between lookups
+		profiles:     make(map[string]models.Stock), // Initialize the profile cache
+	}
+	if cachePath == "" {
+		return enricher, nil
+	}
+
+	raw, err := os.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return enricher, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile cache: %v", err)
+	}
+	if err := json.Unmarshal(raw, &enricher.profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse profile cache %s: %v", cachePath, err)
+	}
+	return enricher, nil
+}
+
+// Enrich returns the stock with empty exchange, currency, country, ISIN, name, sector, industry, and market cap
+// fields filled from its cached or freshly fetched profile; values from the stock list always win
+// Failed lookups are logged and leave the stock unchanged, so enrichment never blocks a signal
+func (e *StockEnricher) Enrich(stock models.Stock) models.Stock {
+	if stock.HasListing() && stock.Name != "" && stock.Sector != "" {
+		return stock // Nothing the provider could add
+	}
+	return mergeProfile(stock, e.profile(stock.Symbol))
+}
+
+// profile returns the cached profile of a symbol, looking it up on first use
+func (e *StockEnricher) profile(symbol string) models.Stock {
+	key := strings.ToUpper(symbol)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if profile, ok := e.profiles[key]; ok {
+		return profile
+	}
+
+	profile := suffixListing(key)
+	if profile.Exchange == "" {
+		fetched, err := e.fetcher.FetchProfile(symbol)
+		if e.requestDelay > 0 {
+			time.Sleep(e.requestDelay)
+		}
+		if err != nil {
+			log.Printf("⚠️  Could not look up the profile of %s: %v", symbol, err)
+			return models.Stock{} // Not cached, so the next run retries
+		}
+		profile = fetched
+	}
+	profile.Symbol = key
+	e.profiles[key] = profile
+	e.dirty = true
+	return profile
+}
+
+// suffixListing returns the listing implied by a market suffix such as ".IS" (empty when the suffix is unknown)
+func suffixListing(symbol string) models.Stock {
+	if dot := strings.LastIndex(symbol, "."); dot > 0 {
+		return listingSuffixes[symbol[dot:]]
+	}
+	return models.Stock{}
+}
+
+// mergeProfile copies the profile's values into the fields the stock leaves empty
+func mergeProfile(stock, profile models.Stock) models.Stock {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&stock.Name, profile.Name)
+	fill(&stock.Sector, profile.Sector)
+	fill(&stock.Industry, profile.Industry)
+	fill(&stock.Exchange, profile.Exchange)
+	fill(&stock.Currency, profile.Currency)
+	fill(&stock.Country, profile.Country)
+	fill(&stock.ISIN, profile.ISIN)
+	if stock.MarketCap == 0 {
+		stock.MarketCap = profile.MarketCap
+	}
+	return stock
+}
+
+// Save writes newly fetched profiles to the cache file (a no-op when nothing changed or no file is configured)
+func (e *StockEnricher) Save() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.dirty || e.cachePath == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(e.profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profile cache: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(e.cachePath, raw); err != nil {
+		return fmt.Errorf("failed to write profile cache: %v", err)
+	}
+	e.dirty = false
+	return nil
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// newOverviewServer serves a fixed OVERVIEW payload and counts the requests it receives
+func newOverviewServer(t *testing.T, payload string) (*httptest.Server, *int64) {
+	t.Helper()
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		if r.URL.Query().Get("function") != "OVERVIEW" {
+			t.Errorf("function = %q, want OVERVIEW", r.URL.Query().Get("function"))
+		}
+		w.Write([]byte(payload))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestStockEnricherFillsMissingFields(t *testing.T) {
+	server, requests := newOverviewServer(t, `{"Symbol":"AAPL","Name":"Apple Inc","Exchange":"NASDAQ","Currency":"USD",
+		"Country":"USA","Sector":"TECHNOLOGY","Industry":"ELECTRONIC COMPUTERS","MarketCapitalization":"3000000000000"}`)
+	cachePath := filepath.Join(t.TempDir(), "profiles.json")
+
+	enricher, err := NewStockEnricher(NewStockDataFetcher("key", server.URL, "daily"), cachePath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := enricher.Enrich(models.Stock{Symbol: "AAPL", Name: "Apple", Sector: "Tech"})
+	want := models.Stock{Symbol: "AAPL", Name: "Apple", Sector: "Tech", Industry: "Electronic Computers",
+		Exchange: "NASDAQ", Currency: "USD", Country: "USA", MarketCap: 3e12}
+	if got != want {
+		t.Errorf("Enrich = %+v, want %+v", got, want)
+	}
+	if err := enricher.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh enricher answers from the cache without another request
+	cached, err := NewStockEnricher(NewStockDataFetcher("key", server.URL, "daily"), cachePath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cached.Enrich(models.Stock{Symbol: "aapl"}); got.Exchange != "NASDAQ" || got.Currency != "USD" {
+		t.Errorf("cached Enrich = %+v, want NASDAQ/USD", got)
+	}
+	if *requests != 1 {
+		t.Errorf("requests = %d, want 1", *requests)
+	}
+}
+
+func TestStockEnricherUsesMarketSuffix(t *testing.T) {
+	server, requests := newOverviewServer(t, `{}`)
+	enricher, err := NewStockEnricher(NewStockDataFetcher("key", server.URL, "daily"), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := enricher.Enrich(models.Stock{Symbol: "THYAO.IS", Name: "Türk Hava Yolları"})
+	if got.Exchange != "BIST" || got.Currency != "TRY" || got.Country != "Turkey" {
+		t.Errorf("Enrich = %+v, want the BIST listing", got)
+	}
+	if *requests != 0 {
+		t.Errorf("requests = %d, want 0 for a known suffix", *requests)
+	}
+}
+
+func TestStockEnricherDoesNotCacheFailures(t *testing.T) {
+	server, requests := newOverviewServer(t, `{"Note":"Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`)
+	enricher, err := NewStockEnricher(NewStockDataFetcher("key", server.URL, "daily"), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stock := models.Stock{Symbol: "MSFT", Sector: "Technology"}
+	for i := 0; i < 2; i++ {
+		if got := enricher.Enrich(stock); got != stock {
+			t.Errorf("Enrich = %+v, want the stock unchanged", got)
+		}
+	}
+	if *requests != 2 {
+		t.Errorf("requests = %d, want a retry after the failed lookup", *requests)
+	}
+}
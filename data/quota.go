@@ -0,0 +1,134 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExhausted is returned instead of making a request once the daily API quota is used up
+var ErrQuotaExhausted = errors.New("daily API quota exhausted")
+
+// quotaHistoryDays is how many days of usage the quota file keeps
+const quotaHistoryDays = 30
+
+// QuotaTracker counts the API requests made per provider per UTC day and refuses requests beyond the daily quota
+// Usage is persisted, so separate runs on the same day share one budget (thread-safe)
+type QuotaTracker struct {
+	path     string                    // File the usage is loaded from and saved to (empty keeps it in memory)
+	provider string                    // Provider whose requests are counted
+	limit    int                       // Requests allowed per day (0 counts without a limit)
+	usage    map[string]map[string]int // Requests keyed by provider, then by UTC date
+	now      func() time.Time          // Clock, replaced in tests
+	mutex    sync.Mutex                // Protects usage
+}
+
+// NewQuotaTracker loads the recorded usage at path for a provider with a daily limit (0 for no limit)
+func NewQuotaTracker(path, provider string, limit int) (*QuotaTracker, error) {
+	tracker := &QuotaTracker{
+		path:     path,                            // Store the usage file location
+		provider: provider,                        // Store the provider requests are counted for
+		limit:    limit,                           // Store the daily limit
+		usage:    make(map[string]map[string]int), // Initialize the usage history
+		now:      time.Now,                        // Use the wall clock
+	}
+	if path == "" {
+		return tracker, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tracker, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API usage: %v", err)
+	}
+	if err := json.Unmarshal(raw, &tracker.usage); err != nil {
+		return nil, fmt.Errorf("failed to parse API usage %s: %v", path, err)
+	}
+	return tracker, nil
+}
+
+// today returns the UTC date usage is counted under
+func (q *QuotaTracker) today() string {
+	return q.now().UTC().Format("2006-01-02")
+}
+
+// Reserve counts one request, or returns ErrQuotaExhausted without counting when today's quota is used up
+func (q *QuotaTracker) Reserve() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	days := q.usage[q.provider]
+	if days == nil {
+		days = make(map[string]int)
+		q.usage[q.provider] = days
+	}
+	today := q.today()
+	if q.limit > 0 && days[today] >= q.limit {
+		return fmt.Errorf("%w: %d of %d %s requests used today", ErrQuotaExhausted, days[today], q.limit, q.provider)
+	}
+	days[today]++
+	return nil
+}
+
+// Used returns the requests made today
+func (q *QuotaTracker) Used() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.usage[q.provider][q.today()]
+}
+
+// Limit returns the daily quota (0 when requests are only counted)
+func (q *QuotaTracker) Limit() int {
+	return q.limit
+}
+
+// Remaining returns the requests left today, or -1 without a limit
+func (q *QuotaTracker) Remaining() int {
+	if q.limit <= 0 {
+		return -1
+	}
+	return max(q.limit-q.Used(), 0)
+}
+
+// Save writes the usage of the last 30 days to the usage file
+// Only the tracker's own provider is taken from memory; the others are re-read from the file, so trackers of
+// different providers in one run never overwrite each other's counts
+func (q *QuotaTracker) Save() error {
+	if q.path == "" {
+		return nil
+	}
+	usage := make(map[string]map[string]int)
+	if raw, err := os.ReadFile(q.path); err == nil {
+		if err := json.Unmarshal(raw, &usage); err != nil {
+			usage = make(map[string]map[string]int) // An unreadable file is replaced
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read API usage: %v", err)
+	}
+
+	q.mutex.Lock()
+	if days := q.usage[q.provider]; days != nil {
+		usage[q.provider] = days
+	}
+	cutoff := q.now().UTC().AddDate(0, 0, -quotaHistoryDays).Format("2006-01-02")
+	for _, days := range usage {
+		for day := range days {
+			if day < cutoff {
+				delete(days, day)
+			}
+		}
+	}
+	raw, err := json.MarshalIndent(usage, "", "  ")
+	q.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode API usage: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(q.path, append(raw, '\n')); err != nil {
+		return fmt.Errorf("failed to write API usage: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package data
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuotaTrackerStopsAtTheDailyLimitAndResetsNextDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_usage.json")
+	now := time.Date(2024, 3, 11, 23, 0, 0, 0, time.UTC)
+	quota, err := NewQuotaTracker(path, "alphavantage", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if err := quota.Reserve(); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if err := quota.Reserve(); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("third request = %v, want ErrQuotaExhausted", err)
+	}
+	if quota.Used() != 2 || quota.Remaining() != 0 {
+		t.Errorf("used %d, remaining %d; want 2, 0", quota.Used(), quota.Remaining())
+	}
+	if err := quota.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later run on the same day shares the budget, and other providers are counted apart
+	reloaded, err := NewQuotaTracker(path, "alphavantage", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded.now = quota.now
+	if err := reloaded.Reserve(); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("reloaded request = %v, want ErrQuotaExhausted", err)
+	}
+	other, err := NewQuotaTracker(path, "other", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.now = quota.now
+	if err := other.Reserve(); err != nil {
+		t.Errorf("other provider: %v", err)
+	}
+
+	// Saving the other provider keeps the counts of the first
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if merged, err := NewQuotaTracker(path, "alphavantage", 2); err != nil || merged.usage["alphavantage"]["2024-03-11"] != 2 {
+		t.Errorf("after saving another provider, alphavantage used %d (%v), want 2", merged.usage["alphavantage"]["2024-03-11"], err)
+	}
+
+	now = now.Add(2 * time.Hour) // Past midnight UTC
+	if err := reloaded.Reserve(); err != nil || reloaded.Used() != 1 {
+		t.Errorf("next day = %v with %d used, want the quota to start over", err, reloaded.Used())
+	}
+}
+
+func TestQuotaTrackerWithoutLimitOnlyCounts(t *testing.T) {
+	quota, err := NewQuotaTracker("", "alphavantage", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := quota.Reserve(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if quota.Used() != 5 || quota.Remaining() != -1 {
+		t.Errorf("used %d, remaining %d; want 5, -1", quota.Used(), quota.Remaining())
+	}
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bulkQuoteBatchSize is the number of symbols Alpha Vantage accepts per bulk quote request
+const bulkQuoteBatchSize = 100
+
+// bulkQuoteResponse mirrors the REALTIME_BULK_QUOTES response
+type bulkQuoteResponse struct {
+	Data []struct {
+		Symbol        string `json:"symbol"`         // Stock ticker symbol
+		Timestamp     string `json:"timestamp"`      // Quote time (exchange local time)
+		Close         string `json:"close"`          // Latest price
+		Volume        string `json:"volume"`         // Session volume
+		ChangePercent string `json:"change_percent"` // Change from the previous close in percent
+	} `json:"data"` // One item per known symbol
+	Note         string `json:"Note"`          // Rate limit message
+	Information  string `json:"Information"`   // Premium endpoint or quota message
+	ErrorMessage string `json:"Error Message"` // Invalid request message
+}
+
+// FetchQuotes fetches the latest quotes for many symbols using Alpha Vantage bulk quote requests
+// Symbols unknown to the provider are missing from the result rather than reported as errors
+func (f *StockDataFetcher) FetchQuotes(symbols []string) ([]models.Quote, error) {
+	var quotes []models.Quote
+	for start := 0; start < len(symbols); start += bulkQuoteBatchSize {
+		end := min(start+bulkQuoteBatchSize, len(symbols))
+		batch, err := f.fetchQuoteBatch(symbols[start:end])
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, batch...)
+	}
+	return quotes, nil
+}
+
+// fetchQuoteBatch requests one batch of at most bulkQuoteBatchSize symbols
+func (f *StockDataFetcher) fetchQuoteBatch(symbols []string) ([]models.Quote, error) {
+	requestURL := fmt.Sprintf("%s?function=REALTIME_BULK_QUOTES&symbol=%s&apikey=%s",
+		f.apiURL, url.QueryEscape(strings.Join(symbols, ",")), f.apiKey)
+
+	if err := f.countRequest(); err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Get(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch quotes: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quote response: %v", err)
+	}
+	var response bulkQuoteResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse quote response: %v", err)
+	}
+	switch {
+	case response.Note != "":
+		return nil, fmt.Errorf("API rate limit: %s", response.Note)
+	case response.ErrorMessage != "":
+		return nil, fmt.Errorf("API error: %s", response.ErrorMessage)
+	case len(response.Data) == 0 && response.Information != "":
+		return nil, fmt.Errorf("API error: %s", response.Information)
+	}
+
+	quotes := make([]models.Quote, 0, len(response.Data))
+	for _, item := range response.Data {
+		price, err := strconv.ParseFloat(item.Close, 64)
+		if err != nil || price <= 0 {
+			continue // Skip symbols without a usable price
+		}
+		volume, _ := strconv.ParseInt(item.Volume, 10, 64)
+		change, _ := strconv.ParseFloat(strings.TrimSuffix(item.ChangePercent, "%"), 64)
+		quoteTime, _ := time.Parse("2006-01-02 15:04:05.000", item.Timestamp)
+		quotes = append(quotes, models.Quote{
+			Symbol:        strings.ToUpper(item.Symbol),
+			Time:          quoteTime,
+			Price:         price,
+			Volume:        volume,
+			ChangePercent: change,
+		})
+	}
+	return quotes, nil
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how fetchers retry transient failures: network errors and timeouts, 5xx responses, and rate limits
+// Every attempt is a separate request and counts against the API quota
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per request, the first included (1 or less disables retries)
+	BaseDelay   time.Duration // Wait before the first retry; it doubles for every further retry, with random jitter
+}
+
+// DefaultRetryPolicy returns the policy fetchers start with: three attempts, waiting about 1s and then 2s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second}
+}
+
+// transientError marks a failure that may succeed when the request is repeated
+type transientError struct {
+	err error // Underlying failure
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+func (e transientError) Unwrap() error { return e.err }
+
+// transient marks err as worth retrying
+func transient(err error) error {
+	return transientError{err: err}
+}
+
+// transientStatus reports whether an HTTP status is a server error or a rate limit (Binance answers 418 to repeat offenders)
+func transientStatus(status int) bool {
+	return status >= 500 || status == http.StatusTooManyRequests || status == http.StatusTeapot
+}
+
+// requestError marks a failed HTTP round trip as transient unless the caller's context ended it
+func requestError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return transient(err)
+}
+
+// do runs attempt until it succeeds, fails with an error not marked transient, or the policy runs out of attempts
+// An error returned after retries lists the failures of the earlier attempts
+func (p RetryPolicy) do(ctx context.Context, attempt func() error) error {
+	var history []string
+	for n := 1; ; n++ {
+		err := attempt()
+		var retryable transientError
+		if errors.As(err, &retryable) {
+			err = retryable.err
+		}
+		if err == nil {
+			return nil
+		}
+		if retryable.err == nil || n >= p.MaxAttempts {
+			return withHistory(err, history)
+		}
+		history = append(history, fmt.Sprintf("attempt %d: %v", n, err))
+
+		select {
+		case <-ctx.Done():
+			return withHistory(ctx.Err(), history)
+		case <-time.After(p.backoff(n)):
+		}
+	}
+}
+
+// backoff returns the wait after the given failed attempt: BaseDelay doubled per retry, between half and all of it
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay << (attempt - 1)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// withHistory appends the failures of earlier attempts to the final error of a retried request
+func withHistory(err error, history []string) error {
+	if len(history) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w (earlier %s)", err, strings.Join(history, "; "))
+}
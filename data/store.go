@@ -0,0 +1,119 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CandleStore keeps fetched candles on disk, one JSON file per symbol and timeframe
+// Saved candles are merged with the stored history so repeated scans build up a long archive for replays
+type CandleStore struct {
+	dir       string // Directory the candle files are written to
+	timeframe string // Candle timeframe, part of every file name
+}
+
+// NewCandleStore creates a candle store in dir for the given timeframe (empty means daily)
+func NewCandleStore(dir, timeframe string) *CandleStore {
+	if timeframe == "" {
+		timeframe = "daily"
+	}
+	return &CandleStore{
+		dir:       dir,       // Store the archive directory
+		timeframe: timeframe, // Store the timeframe for file names
+	}
+}
+
+// path returns the file holding the candles of a symbol
+func (s *CandleStore) path(symbol string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(strings.ToUpper(symbol))
+	return filepath.Join(s.dir, name+"_"+s.timeframe+".json")
+}
+
+// Load reads the stored candles of a symbol; a symbol that was never saved returns an os.ErrNotExist error
+func (s *CandleStore) Load(symbol string) (models.CandleData, error) {
+	raw, err := os.ReadFile(s.path(symbol))
+	if err != nil {
+		return models.CandleData{}, err
+	}
+	var candleData models.CandleData
+	if err := json.Unmarshal(raw, &candleData); err != nil {
+		return models.CandleData{}, fmt.Errorf("failed to parse stored candles for %s: %v", symbol, err)
+	}
+	return candleData, nil
+}
+
+// Save merges candles into the stored history of a symbol, newer values replacing stored candles of the same period
+// Adjustments recorded on only one side are applied to the other first, so as-traded candles from the provider
+// stay consistent with an archive that corporate actions have already adjusted
+func (s *CandleStore) Save(symbol string, candleData models.CandleData) error {
+	stored, err := s.Load(symbol)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	incoming := candleData.Candles
+	if missing := missingAdjustments(stored.Adjustments, candleData.Adjustments); len(missing) > 0 {
+		incoming = AdjustCandles(incoming, missing)
+	}
+	if missing := missingAdjustments(candleData.Adjustments, stored.Adjustments); len(missing) > 0 {
+		stored.Candles = AdjustCandles(stored.Candles, missing)
+		stored.Adjustments = append(stored.Adjustments, missing...)
+	}
+
+	byDate := make(map[int64]models.Candle, len(stored.Candles)+len(candleData.Candles))
+	for _, candle := range stored.Candles {
+		byDate[candle.Time().Unix()] = candle
+	}
+	for _, candle := range incoming {
+		byDate[candle.Time().Unix()] = candle
+	}
+	merged := models.CandleData{Timeframe: s.timeframe, Candles: make([]models.Candle, 0, len(byDate)), Adjustments: stored.Adjustments,
+		BackfilledFrom: stored.BackfilledFrom}
+	if candleData.BackfilledFrom != nil && !coversHistory(stored.BackfilledFrom, *candleData.BackfilledFrom) {
+		merged.BackfilledFrom = candleData.BackfilledFrom // Keep the widest backfill
+	}
+	for _, candle := range byDate {
+		merged.Candles = append(merged.Candles, candle)
+	}
+	sort.Slice(merged.Candles, func(i, j int) bool { return merged.Candles[i].Time().Before(merged.Candles[j].Time()) })
+	return s.write(symbol, merged)
+}
+
+// SaveHistory merges the full history of a symbol since from into the archive and records the backfill,
+// so an interrupted backfill can resume with the symbols that are still missing
+func (s *CandleStore) SaveHistory(symbol string, from time.Time, candleData models.CandleData) error {
+	candleData.BackfilledFrom = &from
+	return s.Save(symbol, candleData)
+}
+
+// Backfilled reports whether the archive of a symbol already holds its full history since from
+func (s *CandleStore) Backfilled(symbol string, from time.Time) bool {
+	stored, err := s.Load(symbol)
+	return err == nil && coversHistory(stored.BackfilledFrom, from)
+}
+
+// coversHistory reports whether a backfill from backfilledFrom includes every candle since from
+func coversHistory(backfilledFrom *time.Time, from time.Time) bool {
+	if backfilledFrom == nil {
+		return false
+	}
+	return backfilledFrom.IsZero() || (!from.IsZero() && !backfilledFrom.After(from))
+}
+
+// write replaces the stored candles of a symbol
+func (s *CandleStore) write(symbol string, candleData models.CandleData) error {
+	raw, err := json.Marshal(candleData)
+	if err != nil {
+		return fmt.Errorf("failed to encode candles for %s: %v", symbol, err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path(symbol), raw); err != nil {
+		return fmt.Errorf("failed to store candles for %s: %v", symbol, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestCandleStoreBackfill(t *testing.T) {
+	store := NewCandleStore(t.TempDir(), "daily")
+	from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	if store.Backfilled("AAPL", from) {
+		t.Fatal("a symbol that was never saved is not backfilled")
+	}
+
+	// Candles saved by a scan do not count as a backfill
+	if err := store.Save("AAPL", models.CandleData{Candles: sessions(time.UTC, 100, 101)}); err != nil {
+		t.Fatal(err)
+	}
+	if store.Backfilled("AAPL", from) {
+		t.Fatal("scanned candles must not mark the history as backfilled")
+	}
+
+	if err := store.SaveHistory("AAPL", from, models.CandleData{Candles: sessions(time.UTC, 99, 100)}); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Backfilled("AAPL", from) || !store.Backfilled("AAPL", from.AddDate(3, 0, 0)) {
+		t.Error("a backfill from 2015 covers 2015 and later")
+	}
+	if store.Backfilled("AAPL", from.AddDate(-1, 0, 0)) || store.Backfilled("AAPL", time.Time{}) {
+		t.Error("a backfill from 2015 does not cover earlier history")
+	}
+
+	// A later scan keeps the backfill marker, and a narrower backfill does not replace it
+	if err := store.Save("AAPL", models.CandleData{Candles: sessions(time.UTC, 100, 101, 102)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveHistory("AAPL", from.AddDate(5, 0, 0), models.CandleData{Candles: sessions(time.UTC, 100)}); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Backfilled("AAPL", from) {
+		t.Error("saving candles lost the widest backfill")
+	}
+}
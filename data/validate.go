@@ -0,0 +1,129 @@
+package data
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"math"
+	"sort"
+	"time"
+)
+
+// Candle anomaly kinds reported by CheckCandles
+const (
+	AnomalyInvertedRange    = "inverted_range"     // High below low
+	AnomalyNonPositivePrice = "non_positive_price" // Open, high, low, or close at or below zero
+	AnomalyZeroRange        = "zero_range"         // High equal to low, e.g. a placeholder candle of a session without trades
+	AnomalyGap              = "gap"                // Open far from the previous close without a split explaining it
+	AnomalyMissingSessions  = "missing_sessions"   // Trading days the market calendar expects between two candles
+	AnomalyDroppedBar       = "dropped_bar"        // Bar the provider returned with unusable values, left out of the candles
+)
+
+// Anomaly is a suspicious candle found by CheckCandles
+type Anomaly struct {
+	Kind   string    `json:"kind"`   // One of the Anomaly* kinds
+	Date   time.Time `json:"date"`   // Start of the candle's period
+	Detail string    `json:"detail"` // Human-readable description
+}
+
+// String describes the anomaly with its date
+func (a Anomaly) String() string {
+	return a.Date.Format("2006-01-02") + " " + a.Detail
+}
+
+// Impossible reports whether the candle cannot have traded, so indicators computed from it are meaningless
+func (a Anomaly) Impossible() bool {
+	return a.Kind == AnomalyInvertedRange || a.Kind == AnomalyNonPositivePrice || a.Kind == AnomalyDroppedBar
+}
+
+// SessionCalendar tells trading days from closures; *calendar.Calendar implements it
+type SessionCalendar interface {
+	IsTradingDay(t time.Time) bool
+}
+
+// CandleChecks configures CheckCandles
+type CandleChecks struct {
+	Window   int             // Newest candles checked (0 checks every candle)
+	MaxGap   float64         // Largest open-to-previous-close move, as a fraction, before a gap is flagged (0 disables)
+	Calendar SessionCalendar // Calendar missing daily sessions are found with (nil disables the check)
+}
+
+// CheckCandles flags impossible bars, zero-range bars, unexplained gaps, and missing sessions, oldest first
+// Gaps on a candle carrying a split coefficient other than 1 are explained by the split and not flagged
+func CheckCandles(candles []models.Candle, checks CandleChecks) []Anomaly {
+	if checks.Window > 0 && len(candles) > checks.Window {
+		candles = candles[len(candles)-checks.Window:]
+	}
+	var anomalies []Anomaly
+	for i, candle := range candles {
+		add := func(kind, format string, args ...interface{}) {
+			anomalies = append(anomalies, Anomaly{Kind: kind, Date: candle.Time(), Detail: fmt.Sprintf(format, args...)})
+		}
+		switch {
+		case candle.Open <= 0 || candle.High <= 0 || candle.Low <= 0 || candle.Close <= 0:
+			add(AnomalyNonPositivePrice, "has a price at or below zero (O %.2f H %.2f L %.2f C %.2f)", candle.Open, candle.High, candle.Low, candle.Close)
+		case candle.High < candle.Low:
+			add(AnomalyInvertedRange, "has its high %.2f below its low %.2f", candle.High, candle.Low)
+		case candle.High == candle.Low:
+			add(AnomalyZeroRange, "has no range (high and low %.2f)", candle.High)
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := candles[i-1]
+		split := candle.SplitCoefficient > 0 && candle.SplitCoefficient != 1
+		if checks.MaxGap > 0 && previous.Close > 0 && candle.Open > 0 && !split {
+			if move := candle.Open/previous.Close - 1; math.Abs(move) > checks.MaxGap {
+				add(AnomalyGap, "opened %+.1f%% from the previous close %.2f without a split", move*100, previous.Close)
+			}
+		}
+		if checks.Calendar != nil {
+			if missing := missingSessions(checks.Calendar, previous.Date, candle.Date); missing > 0 {
+				add(AnomalyMissingSessions, "follows %d missing trading days after %s", missing, previous.Date.Format("2006-01-02"))
+			}
+		}
+	}
+	return anomalies
+}
+
+// CheckCandleData runs CheckCandles on fetched candles and adds the bars the provider dropped within the checked window
+// A dropped bar leaves a hole in the history the indicators are computed from, so it is reported as impossible
+func CheckCandleData(candleData models.CandleData, checks CandleChecks) []Anomaly {
+	anomalies := CheckCandles(candleData.Candles, checks)
+	if len(candleData.Dropped) == 0 {
+		return anomalies
+	}
+
+	var since time.Time // Start of the checked window
+	if checks.Window > 0 && len(candleData.Candles) > checks.Window {
+		since = candleData.Candles[len(candleData.Candles)-checks.Window].Time()
+	}
+	for _, candle := range candleData.Dropped {
+		if candle.Time().Before(since) {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			Kind: AnomalyDroppedBar,
+			Date: candle.Time(),
+			Detail: fmt.Sprintf("was dropped for unusable values (O %.2f H %.2f L %.2f C %.2f V %d)",
+				candle.Open, candle.High, candle.Low, candle.Close, candle.Volume),
+		})
+	}
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Date.Before(anomalies[j].Date)
+	})
+	return anomalies
+}
+
+// missingSessions counts the trading days strictly between two daily candle dates
+// Days are taken at noon in the candle's timezone, so dates at midnight UTC map onto the right exchange day
+func missingSessions(sessions SessionCalendar, previous, next time.Time) int {
+	missing := 0
+	year, month, day := previous.Date()
+	for date := time.Date(year, month, day+1, 12, 0, 0, 0, previous.Location()); date.Before(next); date = date.AddDate(0, 0, 1) {
+		if sessions.IsTradingDay(date) {
+			missing++
+		}
+	}
+	return missing
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// weekdays is a calendar trading every weekday
+type weekdays struct{}
+
+func (weekdays) IsTradingDay(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+func TestCheckCandlesFlagsAnomalies(t *testing.T) {
+	candle := func(date string, open, high, low, close float64) models.Candle {
+		return models.Candle{Date: day(date), Open: open, High: high, Low: low, Close: close, Volume: 1000}
+	}
+	candles := []models.Candle{
+		candle("2024-03-01", 100, 101, 99, 100), // Friday
+		candle("2024-03-04", 100, 99, 101, 100), // Inverted range
+		candle("2024-03-05", 100, 100, 100, 100),
+		candle("2024-03-06", 0, 101, 99, 100),
+		candle("2024-03-07", 140, 142, 138, 141), // 40% gap
+		candle("2024-03-12", 70, 72, 69, 71),     // Two missing sessions and a split-explained gap
+	}
+	candles[5].SplitCoefficient = 2
+
+	anomalies := CheckCandles(candles, CandleChecks{MaxGap: 0.25, Calendar: weekdays{}})
+	want := []struct {
+		kind string
+		date string
+	}{
+		{AnomalyInvertedRange, "2024-03-04"},
+		{AnomalyZeroRange, "2024-03-05"},
+		{AnomalyNonPositivePrice, "2024-03-06"},
+		{AnomalyGap, "2024-03-07"},
+		{AnomalyMissingSessions, "2024-03-12"},
+	}
+	if len(anomalies) != len(want) {
+		t.Fatalf("anomalies = %v, want %d", anomalies, len(want))
+	}
+	for i, w := range want {
+		if anomalies[i].Kind != w.kind || anomalies[i].Date.Format("2006-01-02") != w.date {
+			t.Errorf("anomaly %d = %s %s, want %s on %s", i, anomalies[i].Kind, anomalies[i], w.kind, w.date)
+		}
+	}
+	if !anomalies[0].Impossible() || !anomalies[2].Impossible() || anomalies[1].Impossible() {
+		t.Errorf("only inverted ranges and non-positive prices are impossible")
+	}
+	if got := anomalies[4].Detail; got != "follows 2 missing trading days after 2024-03-07" {
+		t.Errorf("missing sessions detail = %q", got)
+	}
+
+	// The window leaves older candles unchecked
+	if recent := CheckCandles(candles, CandleChecks{Window: 3, MaxGap: 0.25}); len(recent) != 2 || recent[1].Kind != AnomalyGap {
+		t.Errorf("window of 3 = %v, want the zero price and the gap", recent)
+	}
+}
+
+func TestCheckCandleDataFlagsDroppedBars(t *testing.T) {
+	body := []byte(`{"Time Series (Daily)":{
+		"2024-03-01":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100","5. volume":"1000"},
+		"2024-03-04":{"1. open":"100","2. high":"99","3. low":"101","4. close":"100","5. volume":"1000"},
+		"2024-03-05":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100","5. volume":"-5"},
+		"2024-03-06":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100","5. volume":"1000"},
+		"2024-03-07":{"1. open":"100","2. high":"101","3. low":"99","4. close":"100","5. volume":"1000"}}}`)
+	candleData, err := NewStockDataFetcher("", "", "daily").parseResponse(body, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candleData.Candles) != 3 || len(candleData.Dropped) != 2 {
+		t.Fatalf("%d candles and %d dropped bars, want 3 and 2", len(candleData.Candles), len(candleData.Dropped))
+	}
+
+	anomalies := CheckCandleData(candleData, CandleChecks{})
+	if len(anomalies) != 2 {
+		t.Fatalf("anomalies = %v, want both dropped bars", anomalies)
+	}
+	for i, date := range []string{"2024-03-04", "2024-03-05"} {
+		if anomalies[i].Kind != AnomalyDroppedBar || anomalies[i].Date.Format("2006-01-02") != date || !anomalies[i].Impossible() {
+			t.Errorf("anomaly %d = %s %s, want an impossible dropped bar on %s", i, anomalies[i].Kind, anomalies[i], date)
+		}
+	}
+
+	// Bars dropped before the window are left unchecked, like the candles there
+	if recent := CheckCandleData(candleData, CandleChecks{Window: 2}); len(recent) != 0 {
+		t.Errorf("window of 2 = %v, want no anomalies", recent)
+	}
+	// Trimming the history to the output size also trims the dropped bars
+	if trimmed, err := NewStockDataFetcher("", "", "daily").parseResponse(body, 2); err != nil || len(trimmed.Dropped) != 0 {
+		t.Errorf("output size 2 kept %d dropped bars (err %v), want none", len(trimmed.Dropped), err)
+	}
+}
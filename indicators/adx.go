@@ -0,0 +1,74 @@
+package indicators
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"math"
+)
+
+// ADXCalculator handles Average Directional Index (ADX) calculations
+// ADX measures trend strength regardless of direction; readings above 25 usually indicate a trending market
+type ADXCalculator struct{}
+
+// NewADXCalculator creates a new ADX calculator instance
+func NewADXCalculator() *ADXCalculator {
+	return &ADXCalculator{}
+}
+
+// Calculate calculates the ADX of the candles using Wilder's smoothing over the given period
+// Returns 0 if there are fewer than 2*period+1 candles
+func (a *ADXCalculator) Calculate(candles []models.Candle, period int) float64 {
+	if period <= 0 || len(candles) < 2*period+1 {
+		return 0 // Return 0 if insufficient data
+	}
+
+	// Seed the smoothed true range and directional movements with the sums of the first period
+	var trueRange, plusDM, minusDM float64
+	var adx float64
+	dxCount := 0
+	for i := 1; i < len(candles); i++ {
+		current, previous := candles[i], candles[i-1]
+		upMove := current.High - previous.High
+		downMove := previous.Low - current.Low
+
+		tr := math.Max(current.High-current.Low, math.Max(math.Abs(current.High-previous.Close), math.Abs(current.Low-previous.Close)))
+		pdm, mdm := 0.0, 0.0
+		if upMove > downMove && upMove > 0 {
+			pdm = upMove
+		}
+		if downMove > upMove && downMove > 0 {
+			mdm = downMove
+		}
+
+		if i <= period {
+			trueRange += tr
+			plusDM += pdm
+			minusDM += mdm
+			if i < period {
+				continue
+			}
+		} else {
+			// Wilder's smoothing: keep (period-1)/period of the previous total and add the new value
+			trueRange = trueRange - trueRange/float64(period) + tr
+			plusDM = plusDM - plusDM/float64(period) + pdm
+			minusDM = minusDM - minusDM/float64(period) + mdm
+		}
+
+		dx := 0.0
+		if trueRange > 0 {
+			plusDI := 100 * plusDM / trueRange
+			minusDI := 100 * minusDM / trueRange
+			if sum := plusDI + minusDI; sum > 0 {
+				dx = 100 * math.Abs(plusDI-minusDI) / sum
+			}
+		}
+
+		// The first ADX is the average of the first period DX values; later values are Wilder-smoothed
+		dxCount++
+		if dxCount <= period {
+			adx += dx / float64(period)
+			continue
+		}
+		adx = (adx*float64(period-1) + dx) / float64(period)
+	}
+	return adx
+}
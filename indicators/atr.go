@@ -0,0 +1,36 @@
+package indicators
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"math"
+)
+
+// ATRCalculator handles Average True Range (ATR) calculations
+// ATR measures how far price typically travels in one candle, including gaps from the previous close
+type ATRCalculator struct{}
+
+// NewATRCalculator creates a new ATR calculator instance
+func NewATRCalculator() *ATRCalculator {
+	return &ATRCalculator{}
+}
+
+// Calculate calculates the ATR of the candles using Wilder's smoothing over the given period
+// Returns 0 if there are fewer than period+1 candles
+func (a *ATRCalculator) Calculate(candles []models.Candle, period int) float64 {
+	if period <= 0 || len(candles) < period+1 {
+		return 0 // Return 0 if insufficient data
+	}
+
+	// The first ATR is the average true range of the first period candles; later values are Wilder-smoothed
+	var atr float64
+	for i := 1; i < len(candles); i++ {
+		current, previous := candles[i], candles[i-1]
+		tr := math.Max(current.High-current.Low, math.Max(math.Abs(current.High-previous.Close), math.Abs(current.Low-previous.Close)))
+		if i <= period {
+			atr += tr / float64(period)
+			continue
+		}
+		atr = (atr*float64(period-1) + tr) / float64(period)
+	}
+	return atr
+}
@@ -0,0 +1,27 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestATRCalculate(t *testing.T) {
+	candles := []models.Candle{
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 10, Close: 11}, // True range 2
+		{High: 15, Low: 13, Close: 14}, // Gap up: true range 15 - 11 = 4
+		{High: 14, Low: 8, Close: 9},   // True range 6
+		{High: 10, Low: 9, Close: 9.5}, // True range 1
+	}
+	atr := NewATRCalculator()
+
+	if got := atr.Calculate(candles[:3], 3); got != 0 {
+		t.Errorf("ATR with too few candles = %v, want 0", got)
+	}
+	// Seed (2 + 4 + 6) / 3 = 4, then Wilder-smoothed (4*2 + 1) / 3 = 3
+	if got := atr.Calculate(candles, 3); math.Abs(got-3) > 1e-9 {
+		t.Errorf("ATR = %v, want 3", got)
+	}
+}
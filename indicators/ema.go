@@ -42,6 +42,36 @@ func (e *EMACalculator) Calculate(prices []float64, period int) float64 {
 	return ema
 }
 
+// Series returns the EMA after every price, so Series(prices, period)[i] equals Calculate(prices[:i+1], period)
+// Entries before the first full period are 0; the whole series costs as much as a single Calculate
+func (e *EMACalculator) Series(prices []float64, period int) []float64 {
+	return e.AppendSeries(make([]float64, 0, len(prices)), prices, period)
+}
+
+// AppendSeries appends the EMA after every price to dst and returns the extended slice
+// Pass a reused buffer as dst to avoid allocating in hot loops
+func (e *EMACalculator) AppendSeries(dst []float64, prices []float64, period int) []float64 {
+	offset := len(dst)
+	dst = append(dst, make([]float64, len(prices))...)
+	series := dst[offset:]
+	if period <= 0 || len(prices) < period {
+		return dst
+	}
+
+	multiplier := 2.0 / (float64(period) + 1.0)
+	sum := 0.0
+	for i := 0; i < period; i++ {
+		sum += prices[i]
+	}
+	ema := sum / float64(period)
+	series[period-1] = ema
+	for i := period; i < len(prices); i++ {
+		ema = (prices[i] * multiplier) + (ema * (1 - multiplier))
+		series[i] = ema
+	}
+	return dst
+}
+
 // ValidateTrend validates if EMAs are in uptrend order (20 > 50 > 100 > 200)
 // This method checks if shorter-term EMAs are above longer-term EMAs, indicating an uptrend
 // Used for Long scenario validation in the SAPAN strategy
@@ -0,0 +1,27 @@
+package indicators_test
+
+import (
+	"fmt"
+
+	"github.com/erhankrygt/sapan/indicators"
+)
+
+func ExampleEMACalculator_Calculate() {
+	prices := make([]float64, 60)
+	for i := range prices {
+		prices[i] = 100 + float64(i) // Steady uptrend
+	}
+	ema := indicators.NewEMACalculator()
+	fmt.Printf("EMA(20) %.2f, EMA(50) %.2f\n", ema.Calculate(prices, 20), ema.Calculate(prices, 50))
+	// Output: EMA(20) 149.50, EMA(50) 134.50
+}
+
+func ExampleMACDCalculator_Calculate() {
+	prices := make([]float64, 150)
+	for i := range prices {
+		prices[i] = 100 + float64(i)*0.5
+	}
+	result := indicators.NewMACDCalculator().Calculate(prices, 12, 26, 9)
+	fmt.Printf("MACD %.2f, signal %.2f\n", result.MACD, result.Signal)
+	// Output: MACD 3.50, signal 3.50
+}
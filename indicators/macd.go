@@ -0,0 +1,198 @@
+// Package indicators provides technical analysis indicators for the SAPAN strategy
+package indicators
+
+// MACDCalculator handles Moving Average Convergence Divergence (MACD) calculations
+// MACD is a trend-following momentum indicator that shows the relationship between two EMAs
+type MACDCalculator struct {
+	emaCalculator *EMACalculator // EMA calculator for computing fast and slow EMAs
+}
+
+// NewMACDCalculator creates a new MACD calculator instance
+// This constructor initializes the calculator with an EMA calculator for internal use
+func NewMACDCalculator() *MACDCalculator {
+	return &MACDCalculator{
+		emaCalculator: NewEMACalculator(), // Initialize EMA calculator
+	}
+}
+
+// MACDResult contains the result of MACD calculation
+// This structure holds the MACD line, Signal line, and Histogram values
+type MACDResult struct {
+	MACD      float64 // MACD line (fast EMA - slow EMA)
+	Signal    float64 // Signal line (EMA of MACD line)
+	Histogram float64 // MACD histogram (MACD - Signal)
+}
+
+// Calculate calculates MACD for given prices and periods
+// MACD formula: MACD = Fast EMA - Slow EMA
+// Signal line is typically a 9-period EMA of the MACD line
+// Histogram = MACD - Signal line
+func (m *MACDCalculator) Calculate(prices []float64, fastPeriod, slowPeriod, signalPeriod int) MACDResult {
+	if len(prices) < slowPeriod {
+		return MACDResult{}
+	}
+
+	// Calculate EMAs
+	emaFast := m.emaCalculator.Calculate(prices, fastPeriod)
+	emaSlow := m.emaCalculator.Calculate(prices, slowPeriod)
+	macd := emaFast - emaSlow
+
+	// Calculate MACD line values over time for signal line
+	macdValues := make([]float64, 0)
+	for i := slowPeriod; i < len(prices); i++ {
+		fastEMA := m.emaCalculator.Calculate(prices[:i+1], fastPeriod)
+		slowEMA := m.emaCalculator.Calculate(prices[:i+1], slowPeriod)
+		macdValue := fastEMA - slowEMA
+		macdValues = append(macdValues, macdValue)
+	}
+
+	// Calculate signal line (EMA of MACD values)
+	var signal float64
+	if len(macdValues) >= signalPeriod {
+		signal = m.emaCalculator.Calculate(macdValues, signalPeriod)
+	} else {
+		signal = macd * 0.9 // Fallback
+	}
+
+	histogram := macd - signal
+
+	return MACDResult{
+		MACD:      macd,
+		Signal:    signal,
+		Histogram: histogram,
+	}
+}
+
+// IsBullMarket checks if MACD is in bull market
+// IsBullMarket checks if MACD indicates a bull market
+// Returns true if MACD line is above the Signal line, indicating bullish momentum
+func (m *MACDCalculator) IsBullMarket(prices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
+	result := m.Calculate(prices, fastPeriod, slowPeriod, signalPeriod)
+	return result.MACD > result.Signal // Bull market when MACD > Signal
+}
+
+// MACDSeries holds the MACD result after every price of a series
+// Duration checks walk back through it instead of recalculating MACD for every earlier candle
+type MACDSeries struct {
+	Results    []MACDResult // Results[i] equals Calculate(prices[:i+1], ...); zero before the slow period is filled
+	slowPeriod int          // Slow EMA period, the first prefix length with a MACD value
+}
+
+// Series calculates MACD after every price in a single pass over the prices
+// Every entry matches Calculate on the same prefix exactly, including the signal line fallback of short histories
+func (m *MACDCalculator) Series(prices []float64, fastPeriod, slowPeriod, signalPeriod int) MACDSeries {
+	return m.AppendSeries(nil, prices, fastPeriod, slowPeriod, signalPeriod)
+}
+
+// AppendSeries is Series with the results stored in dst's backing array when it is large enough
+// Pass a reused buffer as dst to avoid allocating in hot loops; the intermediate EMA series come from a pool
+func (m *MACDCalculator) AppendSeries(dst []MACDResult, prices []float64, fastPeriod, slowPeriod, signalPeriod int) MACDSeries {
+	series := MACDSeries{Results: append(dst[:0], make([]MACDResult, len(prices))...), slowPeriod: slowPeriod}
+	if len(prices) < slowPeriod {
+		return series
+	}
+
+	fastBuf, slowBuf, macdBuf, signalBuf := getScratch(len(prices)), getScratch(len(prices)), getScratch(len(prices)), getScratch(len(prices))
+	defer func() {
+		putScratch(fastBuf)
+		putScratch(slowBuf)
+		putScratch(macdBuf)
+		putScratch(signalBuf)
+	}()
+	fast := m.emaCalculator.AppendSeries(*fastBuf, prices, fastPeriod)
+	slow := m.emaCalculator.AppendSeries(*slowBuf, prices, slowPeriod)
+	macdValues := *macdBuf
+	for i := slowPeriod; i < len(prices); i++ {
+		macdValues = append(macdValues, fast[i]-slow[i])
+	}
+	signals := m.emaCalculator.AppendSeries(*signalBuf, macdValues, signalPeriod)
+
+	for i := slowPeriod - 1; i < len(prices); i++ {
+		macd := fast[i] - slow[i]
+		signal := macd * 0.9 // Fallback until the signal line has enough MACD values
+		if count := i + 1 - slowPeriod; count >= signalPeriod {
+			signal = signals[count-1]
+		}
+		series.Results[i] = MACDResult{
+			MACD:      macd,
+			Signal:    signal,
+			Histogram: macd - signal,
+		}
+	}
+	return series
+}
+
+// Last returns the MACD result of the newest price (zero for an empty series)
+func (s MACDSeries) Last() MACDResult {
+	if len(s.Results) == 0 {
+		return MACDResult{}
+	}
+	return s.Results[len(s.Results)-1]
+}
+
+// IsBearMarketAcceptable checks if the bear market at the end of the series lasted 5 candlesticks or fewer
+func (s MACDSeries) IsBearMarketAcceptable() bool {
+	return s.runAcceptable(func(result MACDResult) bool { return result.MACD <= result.Signal })
+}
+
+// IsBullMarketAcceptable checks if the bull market at the end of the series lasted 5 candlesticks or fewer
+func (s MACDSeries) IsBullMarketAcceptable() bool {
+	return s.runAcceptable(func(result MACDResult) bool { return result.MACD >= result.Signal })
+}
+
+// runAcceptable reports whether the market is not in the run described by inRun, or has been for at most 5 candlesticks
+func (s MACDSeries) runAcceptable(inRun func(MACDResult) bool) bool {
+	// Outside the run (strictly on the other side of the signal line), it's acceptable
+	if !inRun(s.Last()) {
+		return true
+	}
+
+	count := 0
+	for j := len(s.Results) - 1; j >= 1 && count < 6; j-- {
+		if j+1 < s.slowPeriod {
+			continue // No MACD value for this point
+		}
+		if !inRun(s.Results[j]) {
+			break
+		}
+		count++
+	}
+
+	// If the run lasted 5 or fewer candlesticks, it's acceptable
+	return count <= 5
+}
+
+// BearRunLength returns how many candlesticks the bear market at the end of the series has lasted (0 outside one)
+func (s MACDSeries) BearRunLength() int {
+	return s.runLength(func(result MACDResult) bool { return result.MACD <= result.Signal })
+}
+
+// BullRunLength returns how many candlesticks the bull market at the end of the series has lasted (0 outside one)
+func (s MACDSeries) BullRunLength() int {
+	return s.runLength(func(result MACDResult) bool { return result.MACD >= result.Signal })
+}
+
+// runLength counts the run described by inRun back from the newest result, the way runAcceptable counts it
+func (s MACDSeries) runLength(inRun func(MACDResult) bool) int {
+	if !inRun(s.Last()) {
+		return 0
+	}
+	count := 0
+	for j := len(s.Results) - 1; j >= 1 && j+1 >= s.slowPeriod; j-- {
+		if !inRun(s.Results[j]) {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// IsBearMarketAcceptable checks if bear market duration is acceptable (≤ 5 candlesticks)
+func (m *MACDCalculator) IsBearMarketAcceptable(prices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
+	return m.Series(prices, fastPeriod, slowPeriod, signalPeriod).IsBearMarketAcceptable()
+}
+
+// IsBullMarketAcceptable checks if bull market duration is acceptable (≤ 5 candlesticks)
+func (m *MACDCalculator) IsBullMarketAcceptable(prices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
+	return m.Series(prices, fastPeriod, slowPeriod, signalPeriod).IsBullMarketAcceptable()
+}
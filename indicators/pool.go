@@ -0,0 +1,26 @@
+// Package indicators provides technical analysis indicators for the SAPAN strategy
+package indicators
+
+import "sync"
+
+// scratchPool recycles the float64 slices indicators use for intermediate series
+// Backtests validate thousands of histories, and reusing these buffers keeps the garbage collector out of the hot path
+var scratchPool = sync.Pool{
+	New: func() any { return new([]float64) },
+}
+
+// getScratch returns an empty slice with room for at least n values from the pool
+// Return it with putScratch once no result refers to it any more
+func getScratch(n int) *[]float64 {
+	buf := scratchPool.Get().(*[]float64)
+	if cap(*buf) < n {
+		*buf = make([]float64, 0, n)
+	}
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// putScratch hands a slice obtained from getScratch back to the pool
+func putScratch(buf *[]float64) {
+	scratchPool.Put(buf)
+}
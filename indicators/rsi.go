@@ -0,0 +1,99 @@
+// Package indicators provides technical analysis indicators for the SAPAN strategy
+package indicators
+
+// RSICalculator handles Relative Strength Index (RSI) calculations
+// RSI is a momentum oscillator that measures the speed and magnitude of price changes
+// It ranges from 0 to 100 and is used to identify overbought and oversold conditions
+type RSICalculator struct{}
+
+// NewRSICalculator creates a new RSI calculator instance
+// This constructor initializes the calculator for performing RSI calculations
+func NewRSICalculator() *RSICalculator {
+	return &RSICalculator{}
+}
+
+// Calculate calculates the Relative Strength Index for given prices and period
+// RSI formula: RSI = 100 - (100 / (1 + RS))
+// where RS = Average Gain / Average Loss
+// Uses Wilder's smoothing method for more accurate RSI calculation
+// Returns 0 if there's insufficient data for the specified period
+func (r *RSICalculator) Calculate(prices []float64, period int) float64 {
+	// Check if we have enough data points for the specified period
+	if len(prices) < period+1 {
+		return 0 // Return 0 if insufficient data
+	}
+
+	// Calculate initial average gain and loss using simple average of the first period's price changes
+	avgGain := 0.0
+	avgLoss := 0.0
+	for i := 1; i <= period; i++ {
+		gain, loss := priceChange(prices[i-1], prices[i])
+		avgGain += gain // Sum gains for initial period
+		avgLoss += loss // Sum losses for initial period
+	}
+	avgGain /= float64(period) // Calculate average gain
+	avgLoss /= float64(period) // Calculate average loss
+
+	// Apply Wilder's smoothing method for more accurate RSI calculation
+	// This method gives more weight to recent data while maintaining stability
+	for i := period + 1; i < len(prices); i++ {
+		gain, loss := priceChange(prices[i-1], prices[i])
+		// Wilder's smoothing: new_avg = (old_avg * (period-1) + new_value) / period
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+	}
+
+	// Handle edge case where average loss is zero (all gains)
+	if avgLoss == 0 {
+		return 100 // RSI is 100 when there are no losses
+	}
+
+	// Calculate Relative Strength (RS) and RSI
+	rs := avgGain / avgLoss       // Relative Strength
+	rsi := 100 - (100 / (1 + rs)) // RSI formula
+
+	return rsi
+}
+
+// AppendSeries appends the RSI after every price from the period-th change on to dst, oldest first
+// The averages are seeded with the simple average of the first period changes and Wilder-smoothed from there,
+// so every value equals Calculate over all prices up to it; pass a reused buffer as dst to avoid allocating
+func (r *RSICalculator) AppendSeries(dst, prices []float64, period int) []float64 {
+	if period <= 0 || len(prices) < period+1 {
+		return dst
+	}
+	avgGain, avgLoss := 0.0, 0.0
+	for i := 1; i <= period; i++ {
+		gain, loss := priceChange(prices[i-1], prices[i])
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+	dst = append(dst, rsiValue(avgGain, avgLoss))
+
+	for i := period + 1; i < len(prices); i++ {
+		gain, loss := priceChange(prices[i-1], prices[i])
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		dst = append(dst, rsiValue(avgGain, avgLoss))
+	}
+	return dst
+}
+
+// rsiValue converts average gain and loss into an RSI; no losses at all give 100
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// priceChange splits the change between two prices into a gain and a loss (both positive, one of them 0)
+func priceChange(previous, current float64) (gain, loss float64) {
+	change := current - previous
+	if change > 0 {
+		return change, 0
+	}
+	return 0, -change
+}
@@ -0,0 +1,137 @@
+package indicators
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomWalk returns n closes of a seeded random walk with occasional trend changes
+func randomWalk(seed int64, n int) []float64 {
+	random := rand.New(rand.NewSource(seed))
+	prices := make([]float64, n)
+	price, drift := 100.0, 0.0
+	for i := range prices {
+		if i%40 == 0 {
+			drift = random.Float64()*0.02 - 0.01
+		}
+		price *= 1 + drift + random.NormFloat64()*0.015
+		prices[i] = price
+	}
+	return prices
+}
+
+func TestEMASeriesMatchesCalculate(t *testing.T) {
+	ema := NewEMACalculator()
+	prices := randomWalk(1, 260)
+	for _, period := range []int{1, 20, 200, 300} {
+		series := ema.Series(prices, period)
+		for i := range prices {
+			if want := ema.Calculate(prices[:i+1], period); series[i] != want {
+				t.Fatalf("period %d: Series[%d] = %v, want %v", period, i, series[i], want)
+			}
+		}
+	}
+}
+
+func TestMACDSeriesMatchesCalculate(t *testing.T) {
+	macd := NewMACDCalculator()
+	prices := randomWalk(2, 240)
+	series := macd.Series(prices, 50, 100, 9)
+	for i := range prices {
+		if want := macd.Calculate(prices[:i+1], 50, 100, 9); series.Results[i] != want {
+			t.Fatalf("Results[%d] = %+v, want %+v", i, series.Results[i], want)
+		}
+	}
+}
+
+// runAcceptableByRecalculation is the duration check done the slow way, recalculating MACD for every earlier candle
+func runAcceptableByRecalculation(m *MACDCalculator, prices []float64, inRun func(MACDResult) bool) bool {
+	if !inRun(m.Calculate(prices, 50, 100, 9)) {
+		return true
+	}
+	count := 0
+	for j := len(prices) - 1; j >= 1 && count < 6; j-- {
+		if j+1 < 100 {
+			continue
+		}
+		if !inRun(m.Calculate(prices[:j+1], 50, 100, 9)) {
+			break
+		}
+		count++
+	}
+	return count <= 5
+}
+
+func TestMACDDurationChecksMatchRecalculation(t *testing.T) {
+	macd := NewMACDCalculator()
+	bear := func(result MACDResult) bool { return result.MACD <= result.Signal }
+	bull := func(result MACDResult) bool { return result.MACD >= result.Signal }
+	for seed := int64(0); seed < 5; seed++ {
+		prices := randomWalk(seed, 220)
+		for end := 0; end <= len(prices); end += 7 {
+			history := prices[:end]
+			if got, want := macd.IsBearMarketAcceptable(history, 50, 100, 9), runAcceptableByRecalculation(macd, history, bear); got != want {
+				t.Errorf("seed %d, %d prices: IsBearMarketAcceptable = %v, want %v", seed, end, got, want)
+			}
+			if got, want := macd.IsBullMarketAcceptable(history, 50, 100, 9), runAcceptableByRecalculation(macd, history, bull); got != want {
+				t.Errorf("seed %d, %d prices: IsBullMarketAcceptable = %v, want %v", seed, end, got, want)
+			}
+		}
+	}
+}
+
+func TestStochasticRSIFromRSIMatchesCalculate(t *testing.T) {
+	stochRSI := NewStochasticRSICalculator()
+	prices := randomWalk(3, 120)
+	for end := 10; end <= len(prices); end++ {
+		want := stochRSI.Calculate(prices[:end], 5, 3, 3)
+		got := stochRSI.CalculateFromRSI(stochRSI.RSISeries(prices[:end], 5), 3, 3)
+		if got != want {
+			t.Fatalf("%d prices: CalculateFromRSI = %+v, want %+v", end, got, want)
+		}
+	}
+}
+
+func TestAppendSeriesReusesDirtyBuffers(t *testing.T) {
+	ema, macd, stochRSI := NewEMACalculator(), NewMACDCalculator(), NewStochasticRSICalculator()
+	first, second := randomWalk(4, 230), randomWalk(5, 210)
+
+	emaBuf := ema.AppendSeries(nil, first, 20)
+	macdBuf := macd.AppendSeries(nil, first, 50, 100, 9).Results
+	rsiBuf := stochRSI.AppendRSISeries(nil, first, 5)
+
+	emaSeries := ema.AppendSeries(emaBuf[:0], second, 20)
+	for i, want := range ema.Series(second, 20) {
+		if emaSeries[i] != want {
+			t.Fatalf("EMA AppendSeries[%d] = %v, want %v", i, emaSeries[i], want)
+		}
+	}
+	macdSeries := macd.AppendSeries(macdBuf, second, 50, 100, 9)
+	for i, want := range macd.Series(second, 50, 100, 9).Results {
+		if macdSeries.Results[i] != want {
+			t.Fatalf("MACD AppendSeries[%d] = %+v, want %+v", i, macdSeries.Results[i], want)
+		}
+	}
+	rsiSeries := stochRSI.AppendRSISeries(rsiBuf[:0], second, 5)
+	for i, want := range stochRSI.RSISeries(second, 5) {
+		if rsiSeries[i] != want {
+			t.Fatalf("AppendRSISeries[%d] = %v, want %v", i, rsiSeries[i], want)
+		}
+	}
+}
+
+func TestMACDRunLengthMatchesDurationChecks(t *testing.T) {
+	macd := NewMACDCalculator()
+	for seed := int64(0); seed < 5; seed++ {
+		prices := randomWalk(seed, 220)
+		for end := 0; end <= len(prices); end += 3 {
+			series := macd.Series(prices[:end], 50, 100, 9)
+			if got, want := series.BearRunLength() <= 5, series.IsBearMarketAcceptable(); got != want {
+				t.Errorf("seed %d, %d prices: BearRunLength %d disagrees with IsBearMarketAcceptable = %v", seed, end, series.BearRunLength(), want)
+			}
+			if got, want := series.BullRunLength() <= 5, series.IsBullMarketAcceptable(); got != want {
+				t.Errorf("seed %d, %d prices: BullRunLength %d disagrees with IsBullMarketAcceptable = %v", seed, end, series.BullRunLength(), want)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package indicators provides technical analysis indicators for the SAPAN strategy
+package indicators
+
+import "math"
+
+// StochasticRSICalculator handles Stochastic RSI calculations
+// Stochastic RSI applies the Stochastic oscillator formula to RSI values instead of prices
+// This creates a more sensitive momentum indicator that oscillates between 0 and 100
+type StochasticRSICalculator struct {
+	rsiCalculator *RSICalculator // RSI calculator for computing RSI values
+}
+
+// NewStochasticRSICalculator creates a new Stochastic RSI calculator instance
+// This constructor initializes the calculator with an RSI calculator for internal use
+func NewStochasticRSICalculator() *StochasticRSICalculator {
+	return &StochasticRSICalculator{
+		rsiCalculator: NewRSICalculator(), // Initialize RSI calculator
+	}
+}
+
+// StochasticRSIParams holds the lengths of a Stochastic RSI, matching the inputs of TradingView's Stoch RSI
+type StochasticRSIParams struct {
+	RSIPeriod   int  // RSI length
+	StochPeriod int  // Stochastic length: RSI values the highest-lowest range is taken over
+	KSmoothing  int  // SMA length smoothing %K (1 leaves %K raw)
+	DSmoothing  int  // SMA length of %D over %K
+	TradingView bool // TradingView parity: a flat RSI range leaves %K undefined (NaN) instead of neutral 50
+
+	CrossoverLookback int // Candles searched back for the latest bullish crossover (0 or 1 checks the newest candle only)
+}
+
+// DefaultStochasticRSIParams returns the standard 14/14/3/3 Stochastic RSI that charting platforms default to
+func DefaultStochasticRSIParams() StochasticRSIParams {
+	return StochasticRSIParams{RSIPeriod: 14, StochPeriod: 14, KSmoothing: 3, DSmoothing: 3}
+}
+
+// MinPrices returns the number of prices needed before the first %D value exists
+func (p StochasticRSIParams) MinPrices() int {
+	return p.RSIPeriod + p.StochPeriod + max(p.KSmoothing, 1) + p.DSmoothing - 2
+}
+
+// StochasticRSIResult contains the result of Stochastic RSI calculation
+// This structure holds the %K and %D lines along with crossover information
+type StochasticRSIResult struct {
+	K         float64 // %K line (fast stochastic of RSI)
+	D         float64 // %D line (smoothed %K, typically 3-period SMA of %K)
+	Crossover bool    // True if %K crossed above %D on the newest candle from below 30 (bullish crossover)
+
+	CrossoverAge  int     // Candles since the latest bullish crossover within the lookback, 1 on the newest candle (0 when none)
+	CrossoverFrom float64 // %K just before that crossover
+}
+
+// Calculate calculates Stochastic RSI with a raw (unsmoothed) %K and returns K, D values and crossover signal
+// This method applies the Stochastic oscillator formula to RSI values
+// Formula: %K = ((RSI - Lowest RSI) / (Highest RSI - Lowest RSI)) * 100
+// %D is typically a 3-period SMA of %K values
+func (s *StochasticRSICalculator) Calculate(prices []float64, rsiPeriod, stochKPeriod, stochDPeriod int) StochasticRSIResult {
+	return s.CalculateWithParams(prices, StochasticRSIParams{RSIPeriod: rsiPeriod, StochPeriod: stochKPeriod, KSmoothing: 1, DSmoothing: stochDPeriod})
+}
+
+// CalculateWithParams calculates Stochastic RSI over a rolling RSI series with the given lengths
+// A zero result is returned until the prices cover params.MinPrices()
+func (s *StochasticRSICalculator) CalculateWithParams(prices []float64, params StochasticRSIParams) StochasticRSIResult {
+	if len(prices) < params.MinPrices() {
+		return StochasticRSIResult{}
+	}
+	rsiBuf := getScratch(len(prices))
+	defer putScratch(rsiBuf)
+	return s.CalculateFromRSIWithParams(s.AppendRSISeries(*rsiBuf, prices, params.RSIPeriod), params)
+}
+
+// RSISeries returns the rolling RSI after every price from the rsiPeriod-th change on, oldest first
+// Each value is Wilder-smoothed over the whole history before it, the way charting platforms plot RSI;
+// the series is len(prices)-rsiPeriod long
+func (s *StochasticRSICalculator) RSISeries(prices []float64, rsiPeriod int) []float64 {
+	return s.AppendRSISeries(make([]float64, 0, max(len(prices)-rsiPeriod, 0)), prices, rsiPeriod)
+}
+
+// AppendRSISeries appends the RSI series of RSISeries to dst and returns the extended slice
+// Pass a reused buffer as dst to avoid allocating in hot loops
+func (s *StochasticRSICalculator) AppendRSISeries(dst, prices []float64, rsiPeriod int) []float64 {
+	return s.rsiCalculator.AppendSeries(dst, prices, rsiPeriod)
+}
+
+// CalculateFromRSI calculates Stochastic RSI with a raw %K from an RSI series such as the one returned by RSISeries
+// Use it when the RSI series is already at hand; Calculate computes the series first
+func (s *StochasticRSICalculator) CalculateFromRSI(rsiValues []float64, stochKPeriod, stochDPeriod int) StochasticRSIResult {
+	return s.CalculateFromRSIWithParams(rsiValues, StochasticRSIParams{StochPeriod: stochKPeriod, KSmoothing: 1, DSmoothing: stochDPeriod})
+}
+
+// CalculateFromRSIWithParams calculates Stochastic RSI from an RSI series with the given stochastic and smoothing lengths
+// %K is the SMA of the raw stochastic over KSmoothing values, %D the SMA of %K over DSmoothing values
+func (s *StochasticRSICalculator) CalculateFromRSIWithParams(rsiValues []float64, params StochasticRSIParams) StochasticRSIResult {
+	kSmoothing := max(params.KSmoothing, 1)
+	if len(rsiValues) < params.StochPeriod+kSmoothing+params.DSmoothing-2 {
+		return StochasticRSIResult{}
+	}
+
+	// Calculate the raw stochastic values into a pooled buffer, as only the last few survive this call
+	rawBuf := getScratch(len(rsiValues))
+	defer putScratch(rawBuf)
+	raw := *rawBuf
+	for i := params.StochPeriod - 1; i < len(rsiValues); i++ {
+		highestRSI, lowestRSI := rsiValues[i], rsiValues[i]
+		for _, rsi := range rsiValues[i-params.StochPeriod+1 : i] {
+			highestRSI = max(highestRSI, rsi)
+			lowestRSI = min(lowestRSI, rsi)
+		}
+
+		switch {
+		case highestRSI != lowestRSI:
+			raw = append(raw, (rsiValues[i]-lowestRSI)/(highestRSI-lowestRSI)*100)
+		case params.TradingView:
+			raw = append(raw, math.NaN()) // TradingView divides by the zero range and plots nothing
+		default:
+			raw = append(raw, 50) // A flat range is neutral, neither oversold nor overbought
+		}
+	}
+
+	// Smooth the stochastic into %K, then average %K into %D
+	kBuf := getScratch(len(raw))
+	defer putScratch(kBuf)
+	stochK := appendSMA(*kBuf, raw, kSmoothing)
+	currentK := stochK[len(stochK)-1]
+	currentD := mean(stochK[len(stochK)-params.DSmoothing:])
+
+	result := StochasticRSIResult{K: currentK, D: currentD}
+
+	// Find the latest crossover within the lookback: K was below D and is now above D
+	for age := 1; age <= max(params.CrossoverLookback, 1); age++ {
+		i := len(stochK) - age // Index of the candle the crossover would complete on
+		if i < params.DSmoothing {
+			break // %D does not exist before the crossover candle
+		}
+		prevK, k := stochK[i-1], stochK[i]
+		if prevK < mean(stochK[i-params.DSmoothing:i]) && k > mean(stochK[i-params.DSmoothing+1:i+1]) {
+			result.CrossoverAge, result.CrossoverFrom = age, prevK
+			break
+		}
+	}
+	// Crossover keeps its classic meaning: on the newest candle, with K coming from below 30
+	result.Crossover = result.CrossedFromBelow(30, 1)
+	return result
+}
+
+// appendSMA appends the simple moving average of every window of period values to dst
+func appendSMA(dst, values []float64, period int) []float64 {
+	for i := period - 1; i < len(values); i++ {
+		dst = append(dst, mean(values[i-period+1:i+1]))
+	}
+	return dst
+}
+
+// mean returns the average of values; a NaN among them makes the average NaN
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// IsOversoldWithCrossover checks if Stochastic RSI is oversold with crossover signal
+// This method is used for Long scenario validation in the SAPAN strategy
+// Returns true if %K is below 30 (oversold) and there's a bullish crossover
+func (s *StochasticRSICalculator) IsOversoldWithCrossover(prices []float64, rsiPeriod, stochKPeriod, stochDPeriod int) bool {
+	return s.Calculate(prices, rsiPeriod, stochKPeriod, stochDPeriod).IsOversoldWithCrossover()
+}
+
+// IsOverboughtWithCrossover checks if Stochastic RSI is overbought with crossover signal
+// This method is used for Short scenario validation in the SAPAN strategy
+// Returns true if %K is above 70 (overbought) and there's a bullish crossover
+func (s *StochasticRSICalculator) IsOverboughtWithCrossover(prices []float64, rsiPeriod, stochKPeriod, stochDPeriod int) bool {
+	return s.Calculate(prices, rsiPeriod, stochKPeriod, stochDPeriod).IsOverboughtWithCrossover()
+}
+
+// CrossedFromBelow reports whether %K crossed above %D within the last lookback candles, coming from below level
+// Only the latest crossover of the lookback the result was calculated with is considered
+func (r StochasticRSIResult) CrossedFromBelow(level float64, lookback int) bool {
+	return r.CrossoverAge > 0 && r.CrossoverAge <= max(lookback, 1) && r.CrossoverFrom < level
+}
+
+// IsOversoldWithCrossover reports whether %K is below 30 (oversold) with a bullish crossover
+func (r StochasticRSIResult) IsOversoldWithCrossover() bool {
+	return r.K < 30 && r.Crossover // Oversold + bullish crossover
+}
+
+// IsOverboughtWithCrossover reports whether %K is above 70 (overbought) with a bullish crossover
+func (r StochasticRSIResult) IsOverboughtWithCrossover() bool {
+	return r.K > 70 && r.Crossover // Overbought + bullish crossover
+}
@@ -0,0 +1,114 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRSISeriesIsRolling(t *testing.T) {
+	rsi := NewRSICalculator()
+	prices := randomWalk(6, 120)
+	series := NewStochasticRSICalculator().RSISeries(prices, 14)
+	if len(series) != len(prices)-14 {
+		t.Fatalf("RSISeries has %d values, want %d", len(series), len(prices)-14)
+	}
+	for i, got := range series {
+		if want := rsi.Calculate(prices[:i+15], 14); got != want {
+			t.Fatalf("RSISeries[%d] = %v, want the Wilder RSI over every earlier price %v", i, got, want)
+		}
+	}
+}
+
+// naiveStochRSI recomputes the smoothed Stochastic RSI of the newest price from its definition
+func naiveStochRSI(rsi []float64, params StochasticRSIParams, end int) (k, d float64) {
+	stoch := func(i int) float64 {
+		window := rsi[i-params.StochPeriod+1 : i+1]
+		lowest, highest := window[0], window[0]
+		for _, value := range window {
+			lowest, highest = math.Min(lowest, value), math.Max(highest, value)
+		}
+		return (rsi[i] - lowest) / (highest - lowest) * 100
+	}
+	smoothK := func(i int) float64 {
+		sum := 0.0
+		for j := i - params.KSmoothing + 1; j <= i; j++ {
+			sum += stoch(j)
+		}
+		return sum / float64(params.KSmoothing)
+	}
+	for j := end - params.DSmoothing + 1; j <= end; j++ {
+		d += smoothK(j)
+	}
+	return smoothK(end), d / float64(params.DSmoothing)
+}
+
+func TestStochasticRSISmoothing(t *testing.T) {
+	stochRSI := NewStochasticRSICalculator()
+	params := DefaultStochasticRSIParams()
+	prices := randomWalk(7, 150)
+	if got := stochRSI.CalculateWithParams(prices[:params.MinPrices()-1], params); got != (StochasticRSIResult{}) {
+		t.Fatalf("result before the warm-up = %+v, want zero", got)
+	}
+	for end := params.MinPrices(); end <= len(prices); end++ {
+		got := stochRSI.CalculateWithParams(prices[:end], params)
+		rsi := stochRSI.RSISeries(prices[:end], params.RSIPeriod)
+		wantK, wantD := naiveStochRSI(rsi, params, len(rsi)-1)
+		if math.Abs(got.K-wantK) > 1e-9 || math.Abs(got.D-wantD) > 1e-9 {
+			t.Fatalf("%d prices: K=%v D=%v, want K=%v D=%v", end, got.K, got.D, wantK, wantD)
+		}
+	}
+}
+
+func TestStochasticRSIFlatRange(t *testing.T) {
+	prices := make([]float64, 60)
+	for i := range prices {
+		prices[i] = 100 + float64(i) // Only gains, so RSI stays at 100
+	}
+	stochRSI := NewStochasticRSICalculator()
+	params := DefaultStochasticRSIParams()
+	if got := stochRSI.CalculateWithParams(prices, params); got.K != 50 || got.D != 50 {
+		t.Errorf("standard mode on a flat RSI: K=%v D=%v, want neutral 50", got.K, got.D)
+	}
+	params.TradingView = true
+	got := stochRSI.CalculateWithParams(prices, params)
+	if !math.IsNaN(got.K) || !math.IsNaN(got.D) || got.IsOversoldWithCrossover() || got.IsOverboughtWithCrossover() {
+		t.Errorf("TradingView mode on a flat RSI: %+v, want undefined K and D that never signal", got)
+	}
+}
+
+func TestStochasticRSICrossoverLookback(t *testing.T) {
+	stochRSI := NewStochasticRSICalculator()
+	params := DefaultStochasticRSIParams()
+	prices := randomWalk(8, 200)
+	var found bool
+	for end := params.MinPrices(); end < len(prices)-3; end++ {
+		newest := stochRSI.CalculateWithParams(prices[:end], params)
+		if newest.CrossoverAge != 1 {
+			continue
+		}
+		found = true
+		params.CrossoverLookback = 3
+		for later := 1; later <= 2; later++ {
+			got := stochRSI.CalculateWithParams(prices[:end+later], params)
+			if got.CrossoverAge == 0 || got.CrossoverAge > later+1 {
+				t.Fatalf("%d prices: CrossoverAge = %d, want the crossover %d candles back or a later one", end+later, got.CrossoverAge, later+1)
+			}
+			if got.CrossoverAge == later+1 && got.CrossoverFrom != newest.CrossoverFrom {
+				t.Fatalf("%d prices: CrossoverFrom = %v, want %v", end+later, got.CrossoverFrom, newest.CrossoverFrom)
+			}
+			if got.CrossoverAge > 1 && got.Crossover {
+				t.Fatalf("%d prices: Crossover set for a crossover %d candles back", end+later, got.CrossoverAge)
+			}
+		}
+		params.CrossoverLookback = 0
+		if got := stochRSI.CalculateWithParams(prices[:end+1], params); got.CrossoverAge > 1 {
+			t.Fatalf("%d prices: CrossoverAge = %d beyond a lookback of one candle", end+1, got.CrossoverAge)
+		}
+		if newest.Crossover != newest.CrossedFromBelow(30, 1) {
+			t.Fatalf("%d prices: Crossover = %v disagrees with CrossedFromBelow(30, 1)", end, newest.Crossover)
+		}
+	}
+	if !found {
+		t.Fatal("the random walk has no crossover to test")
+	}
+}
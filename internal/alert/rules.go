@@ -0,0 +1,127 @@
+// Package alert decides which detected setups are worth a notification
+// User-defined rules match signals by side, score, sector, market cap, and price before they reach the notifiers
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"os"
+	"strings"
+)
+
+// Rule selects the signals one or more notifiers should receive
+// Zero-valued criteria are ignored, so an empty rule matches every signal
+type Rule struct {
+	Name           string   `json:"name"`                      // Rule name used in logs
+	Side           string   `json:"side,omitempty"`            // Restrict to Long or Short setups
+	MinScore       float64  `json:"min_score,omitempty"`       // Minimum setup quality score
+	MinProbability float64  `json:"min_probability,omitempty"` // Minimum predicted success probability (0-1); unscored signals never match
+	Sectors        []string `json:"sectors,omitempty"`         // Allowed sectors (case-insensitive)
+	Industries     []string `json:"industries,omitempty"`      // Allowed industries (case-insensitive)
+	Patterns       []string `json:"patterns,omitempty"`        // Allowed candlestick patterns (case-insensitive)
+	Symbols        []string `json:"symbols,omitempty"`         // Allowed symbols (case-insensitive)
+	MinMarketCap   float64  `json:"min_market_cap,omitempty"`  // Minimum market capitalization; signals with an unknown cap never match
+	MaxMarketCap   float64  `json:"max_market_cap,omitempty"`  // Maximum market capitalization
+	MinPrice       float64  `json:"min_price,omitempty"`       // Minimum closing price
+	MaxPrice       float64  `json:"max_price,omitempty"`       // Maximum closing price
+	Notifiers      []string `json:"notifiers,omitempty"`       // Notifiers the rule routes to (empty means all)
+}
+
+// Matches reports whether a watch list entry satisfies every criterion of the rule
+func (r Rule) Matches(entry watcher.WatchListEntry) bool {
+	switch {
+	case r.Side != "" && !strings.EqualFold(r.Side, entry.Side):
+		return false
+	case entry.Score < r.MinScore:
+		return false
+	case r.MinProbability > 0 && entry.Probability < r.MinProbability:
+		return false
+	case !containsFold(r.Sectors, entry.Sector), !containsFold(r.Industries, entry.Industry):
+		return false
+	case !containsFold(r.Patterns, entry.Pattern), !containsFold(r.Symbols, entry.Symbol):
+		return false
+	case r.MinMarketCap > 0 && entry.MarketCap < r.MinMarketCap:
+		return false
+	case r.MaxMarketCap > 0 && (entry.MarketCap == 0 || entry.MarketCap > r.MaxMarketCap):
+		return false
+	case r.MinPrice > 0 && entry.Close < r.MinPrice:
+		return false
+	case r.MaxPrice > 0 && entry.Close > r.MaxPrice:
+		return false
+	}
+	return true
+}
+
+// routesTo reports whether the rule delivers to the named notifier
+func (r Rule) routesTo(notifier string) bool {
+	return len(r.Notifiers) == 0 || containsFold(r.Notifiers, notifier)
+}
+
+// RuleSet is an ordered list of alert rules; a signal is delivered to a notifier when any rule routing to it matches
+type RuleSet struct {
+	rules []Rule // Configured rules in file order
+}
+
+// NewRuleSet creates a rule set from already-parsed rules
+func NewRuleSet(rules []Rule) *RuleSet {
+	return &RuleSet{rules: rules}
+}
+
+// LoadRules reads a JSON array of rules from a file
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %v", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules %s: %v", path, err)
+	}
+	for i, rule := range rules {
+		if rule.Side != "" && !strings.EqualFold(rule.Side, watcher.LongSide) && !strings.EqualFold(rule.Side, watcher.ShortSide) {
+			return nil, fmt.Errorf("alert rule %d (%s): side must be %s or %s", i+1, rule.Name, watcher.LongSide, watcher.ShortSide)
+		}
+		if rule.MaxMarketCap > 0 && rule.MinMarketCap > rule.MaxMarketCap {
+			return nil, fmt.Errorf("alert rule %d (%s): min_market_cap exceeds max_market_cap", i+1, rule.Name)
+		}
+		if rule.MaxPrice > 0 && rule.MinPrice > rule.MaxPrice {
+			return nil, fmt.Errorf("alert rule %d (%s): min_price exceeds max_price", i+1, rule.Name)
+		}
+	}
+	return NewRuleSet(rules), nil
+}
+
+// Len returns the number of rules
+func (s *RuleSet) Len() int {
+	return len(s.rules)
+}
+
+// Allows reports whether a notifier should receive the signal; use it as the dispatcher's signal filter
+func (s *RuleSet) Allows(notifier string, entry watcher.WatchListEntry) bool {
+	return s.Match(notifier, entry) != nil
+}
+
+// Match returns the first rule routing to the notifier that matches the signal, or nil when none does
+func (s *RuleSet) Match(notifier string, entry watcher.WatchListEntry) *Rule {
+	for i := range s.rules {
+		if s.rules[i].routesTo(notifier) && s.rules[i].Matches(entry) {
+			return &s.rules[i]
+		}
+	}
+	return nil
+}
+
+// containsFold reports whether value is in list, ignoring case; an empty list allows every value
+func containsFold(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Package api exposes SAPAN as an HTTP service
+// This package serves endpoints to trigger scans, follow their progress, and query watch lists, signals, and stocks
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleWatchList returns the persisted watch list, filtered by side, sector, pattern, and min_score
+func (s *Server) handleWatchList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := watcher.EntryFilter{
+		Side:    query.Get("side"),
+		Sector:  query.Get("sector"),
+		Pattern: query.Get("pattern"),
+	}
+	if value := query.Get("min_score"); value != "" {
+		minScore, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid min_score")
+			return
+		}
+		filter.MinScore = minScore
+	}
+
+	watchList := watcher.NewWatchListManager()
+	if err := watchList.Load(s.watchListFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, watchList.Query(filter))
+}
+
+// handleSignals returns signal history from the signal database, filtered by symbol, side, from, to, and limit
+func (s *Server) handleSignals(w http.ResponseWriter, r *http.Request) {
+	if s.signalStore == nil {
+		writeError(w, http.StatusNotImplemented, "signal history requires SIGNAL_DB_PATH")
+		return
+	}
+
+	params := r.URL.Query()
+	query := watcher.SignalQuery{Symbol: strings.ToUpper(params.Get("symbol")), Side: params.Get("side")}
+	var err error
+	if query.From, err = parseTimeParam(params, "from"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.To, err = parseTimeParam(params, "to"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if value := params.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	signals, err := s.signalStore.QuerySignals(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, signals)
+}
+
+// parseTimeParam parses an optional YYYY-MM-DD or RFC 3339 query parameter
+func parseTimeParam(params url.Values, name string) (time.Time, error) {
+	value := params.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + name + ": expected YYYY-MM-DD or RFC 3339")
+	}
+	return t, nil
+}
+
+// handleListStocks returns the configured stock universe
+func (s *Server) handleListStocks(w http.ResponseWriter, r *http.Request) {
+	stocks, err := data.NewStockListLoader().LoadStocksFromPatterns(s.stocksFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stocks)
+}
+
+// handleAddStock adds a stock to the stock list, replacing an existing entry with the same symbol
+func (s *Server) handleAddStock(w http.ResponseWriter, r *http.Request) {
+	var stock models.Stock
+	if err := json.NewDecoder(r.Body).Decode(&stock); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid stock: "+err.Error())
+		return
+	}
+	stock.Symbol = strings.ToUpper(strings.TrimSpace(stock.Symbol))
+	if stock.Symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	err := s.editStocks(func(stocks []models.Stock) []models.Stock {
+		for i := range stocks {
+			if strings.EqualFold(stocks[i].Symbol, stock.Symbol) {
+				stocks[i] = stock
+				return stocks
+			}
+		}
+		return append(stocks, stock)
+	})
+	if err != nil {
+		writeError(w, err.status, err.message)
+		return
+	}
+	writeJSON(w, http.StatusCreated, stock)
+}
+
+// handleRemoveStock removes a stock from the stock list
+func (s *Server) handleRemoveStock(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	found := false
+	err := s.editStocks(func(stocks []models.Stock) []models.Stock {
+		kept := stocks[:0]
+		for _, stock := range stocks {
+			if strings.EqualFold(stock.Symbol, symbol) {
+				found = true
+				continue
+			}
+			kept = append(kept, stock)
+		}
+		return kept
+	})
+	if err != nil {
+		writeError(w, err.status, err.message)
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, strings.ToUpper(symbol)+" is not in the stock list")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiError is an error with the HTTP status it should be reported with
+type apiError struct {
+	status  int    // HTTP status code
+	message string // Error message
+}
+
+// editStocks applies an edit to the stock list file under a lock
+// Only a single stock list file can be edited; merged lists and glob patterns are read-only
+func (s *Server) editStocks(edit func([]models.Stock) []models.Stock) *apiError {
+	if strings.ContainsAny(s.stocksFile, ",*?[") {
+		return &apiError{http.StatusConflict, "the stock list is read-only when STOCKS_FILE names several files or a glob"}
+	}
+
+	s.stocksMutex.Lock()
+	defer s.stocksMutex.Unlock()
+
+	loader := data.NewStockListLoader()
+	stocks, err := loader.LoadStocksFromFile(s.stocksFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return &apiError{http.StatusInternalServerError, err.Error()}
+	}
+	stocks.Stocks = edit(stocks.Stocks)
+	if err := loader.SaveStocksToFile(s.stocksFile, stocks); err != nil {
+		return &apiError{http.StatusInternalServerError, err.Error()}
+	}
+	return nil
+}
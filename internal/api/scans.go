@@ -0,0 +1,143 @@
+// Package api exposes SAPAN as an HTTP service
+// This package serves endpoints to trigger scans, follow their progress, and query watch lists, signals, and stocks
+package api
+
+import (
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/internal/report"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ScanStatus is the response of the scan status endpoint
+type ScanStatus struct {
+	Running    bool               `json:"running"`               // A scan is in progress
+	StartedAt  *time.Time         `json:"started_at,omitempty"`  // Start of the current or last scan
+	FinishedAt *time.Time         `json:"finished_at,omitempty"` // End of the last scan (absent while running)
+	ExitCode   *int               `json:"exit_code,omitempty"`   // Exit code of the last scan (absent while running)
+	Progress   *Progress          `json:"progress,omitempty"`    // Processing progress of the current or last scan
+	Summary    *report.RunSummary `json:"summary,omitempty"`     // Counts of the last completed scan
+}
+
+// Progress reports how far processing has come
+type Progress struct {
+	Total     int     `json:"total"`                     // Stocks to process
+	Processed int     `json:"processed"`                 // Stocks processed so far
+	Valid     int     `json:"valid"`                     // Setups found so far
+	Errors    int     `json:"errors"`                    // Failures so far
+	Percent   float64 `json:"percent"`                   // Completion percentage
+	Elapsed   int     `json:"elapsed_seconds,omitempty"` // Seconds since processing started (absent once the scan finished)
+	ETA       int     `json:"eta_seconds,omitempty"`     // Estimated seconds left at the pace so far (absent until a stock is processed)
+}
+
+// StartScan starts a scan in the background and returns its status, or false when a scan is already running
+func (s *Server) StartScan() (ScanStatus, bool) {
+	s.mutex.Lock()
+	if s.state.running {
+		s.mutex.Unlock()
+		return ScanStatus{}, false
+	}
+	s.state = scanState{running: true, startedAt: time.Now(), result: s.state.result}
+	s.mutex.Unlock()
+
+	go func() {
+		result, code := s.scan(func(p *processor.StockProcessor) {
+			s.mutex.Lock()
+			s.state.processor = p
+			s.mutex.Unlock()
+		})
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.state.running = false
+		s.state.finishedAt = time.Now()
+		s.state.exitCode = code
+		if result != nil {
+			s.state.result = result
+		}
+	}()
+
+	return s.Status(), true
+}
+
+// handleStartScan starts a scan in the background, refusing while another scan runs
+func (s *Server) handleStartScan(w http.ResponseWriter, r *http.Request) {
+	status, ok := s.StartScan()
+	if !ok {
+		writeError(w, http.StatusConflict, "a scan is already running")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+// handleScanStatus reports the state and progress of the current or last scan
+func (s *Server) handleScanStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Status())
+}
+
+// Status returns a snapshot of the scan state (thread-safe)
+func (s *Server) Status() ScanStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status := ScanStatus{Running: s.state.running}
+	if !s.state.startedAt.IsZero() {
+		startedAt := s.state.startedAt
+		status.StartedAt = &startedAt
+	}
+	if !s.state.running && !s.state.finishedAt.IsZero() {
+		finishedAt, exitCode := s.state.finishedAt, s.state.exitCode
+		status.FinishedAt = &finishedAt
+		status.ExitCode = &exitCode
+	}
+	if s.state.processor != nil {
+		if tracker := s.state.processor.Progress(); tracker != nil {
+			processed, valid, errors, percent := tracker.GetProgress()
+			status.Progress = &Progress{Total: int(tracker.Total()), Processed: int(processed), Valid: int(valid), Errors: int(errors), Percent: percent}
+			if s.state.running {
+				status.Progress.Elapsed = int(tracker.Elapsed().Seconds())
+				if eta, ok := tracker.ETA(); ok {
+					status.Progress.ETA = int(eta.Round(time.Second).Seconds())
+				}
+			}
+		}
+	}
+	if s.state.result != nil {
+		summary := s.state.result.Summary
+		status.Summary = &summary
+	}
+	return status
+}
+
+// handleSymbol returns the validation detail of one symbol from the last completed scan
+func (s *Server) handleSymbol(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+
+	s.mutex.RLock()
+	result := s.state.result
+	s.mutex.RUnlock()
+	if result == nil {
+		writeError(w, http.StatusNotFound, "no scan has completed yet")
+		return
+	}
+	for _, item := range result.Symbols {
+		if strings.EqualFold(item.Symbol, symbol) {
+			writeJSON(w, http.StatusOK, item)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, symbol+" was not part of the last scan")
+}
+
+// handleBreadth returns the sector and industry breadth of the last completed scan
+func (s *Server) handleBreadth(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	result := s.state.result
+	s.mutex.RUnlock()
+	if result == nil {
+		writeError(w, http.StatusNotFound, "no scan has completed yet")
+		return
+	}
+	writeJSON(w, http.StatusOK, result.Breadth)
+}
@@ -0,0 +1,97 @@
+// Package api exposes SAPAN as an HTTP service
+// This package serves endpoints to trigger scans, follow their progress, and query watch lists, signals, and stocks
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/internal/report"
+	"github.com/erhankrygt/sapan/watcher"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ScanFunc runs one scan, reporting the processor through started once processing begins
+// It returns the run result (nil when the scan stopped early) and the scan's exit code
+type ScanFunc func(started func(*processor.StockProcessor)) (*report.RunResult, int)
+
+// Server is the HTTP API around the scanner
+// Only one scan runs at a time; the watch list, signal database, and stock list are read from their configured files
+type Server struct {
+	scan          ScanFunc                   // Runs a scan
+	watchListFile string                     // File the watch list is persisted to
+	stocksFile    string                     // Stock list setting (a single file can be edited through the API)
+	signalStore   *watcher.SQLiteSignalStore // Signal database (nil when not configured)
+	token         string                     // Bearer token required on every request (empty disables authentication)
+	state         scanState                  // Status of the current and last scan
+	mutex         sync.RWMutex               // Read-write mutex guarding state
+	stocksMutex   sync.Mutex                 // Serializes stock list edits
+}
+
+// scanState tracks the current and last scan
+type scanState struct {
+	running    bool                      // A scan is in progress
+	startedAt  time.Time                 // Start of the current or last scan
+	finishedAt time.Time                 // End of the last scan
+	exitCode   int                       // Exit code of the last scan
+	processor  *processor.StockProcessor // Processor of the current or last scan (nil before processing starts)
+	result     *report.RunResult         // Result of the last completed scan
+}
+
+// NewServer creates an API server
+func NewServer(scan ScanFunc, watchListFile, stocksFile string, signalStore *watcher.SQLiteSignalStore, token string) *Server {
+	return &Server{
+		scan:          scan,          // Store scan function
+		watchListFile: watchListFile, // Store watch list file
+		stocksFile:    stocksFile,    // Store stock list setting
+		signalStore:   signalStore,   // Store signal database
+		token:         token,         // Store API token
+	}
+}
+
+// Handler returns the HTTP handler with every API route registered
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/scans", s.handleStartScan)
+	mux.HandleFunc("GET /api/scans/status", s.handleScanStatus)
+	mux.HandleFunc("GET /api/scans/progress", s.handleScanProgress)
+	mux.HandleFunc("GET /api/watchlist", s.handleWatchList)
+	mux.HandleFunc("GET /api/signals", s.handleSignals)
+	mux.HandleFunc("GET /api/symbols/{symbol}", s.handleSymbol)
+	mux.HandleFunc("GET /api/breadth", s.handleBreadth)
+	mux.HandleFunc("GET /api/stocks", s.handleListStocks)
+	mux.HandleFunc("POST /api/stocks", s.handleAddStock)
+	mux.HandleFunc("DELETE /api/stocks/{symbol}", s.handleRemoveStock)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes a value as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
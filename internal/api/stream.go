@@ -0,0 +1,82 @@
+// Package api exposes SAPAN as an HTTP service
+// This package serves endpoints to trigger scans, follow their progress, and query watch lists, signals, and stocks
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// progressInterval is how often the progress stream samples the scan state
+const progressInterval = time.Second
+
+// heartbeatInterval keeps idle progress streams open through proxies that close silent connections
+const heartbeatInterval = 15 * time.Second
+
+// Server-sent event names of the progress stream
+const (
+	statusEvent   = "status"   // Scan state when the stream opens
+	progressEvent = "progress" // Scan state every progressInterval while a scan runs
+	finishedEvent = "finished" // Scan state once a scan completes, with its exit code and summary
+)
+
+// handleScanProgress streams the scan status as server-sent events so UIs can show live progress without polling
+// The stream stays open across scans: every scan started later is followed as well, until the client disconnects
+func (s *Server) handleScanProgress(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported by this connection")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep reverse proxies such as nginx from buffering events
+	w.WriteHeader(http.StatusOK)
+
+	status := s.Status()
+	if err := writeEvent(w, statusEvent, status); err != nil {
+		return
+	}
+	flusher.Flush()
+	running, lastWrite := status.Running, time.Now()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		status := s.Status()
+		var err error
+		switch {
+		case status.Running:
+			err = writeEvent(w, progressEvent, status)
+		case running:
+			err = writeEvent(w, finishedEvent, status)
+		case time.Since(lastWrite) >= heartbeatInterval:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n") // Comment lines are ignored by EventSource clients
+		default:
+			continue
+		}
+		if err != nil {
+			return // The client went away
+		}
+		flusher.Flush()
+		running, lastWrite = status.Running, time.Now()
+	}
+}
+
+// writeEvent writes one server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
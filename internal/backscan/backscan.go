@@ -0,0 +1,156 @@
+// Package backscan measures how SAPAN setups played out over the stored candle history of each symbol
+// It counts every setup the strategy would have found and the simple forward returns after it, so symbols that
+// historically respect the pattern can be told apart from those that do not
+package backscan
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// DefaultHorizons are the forward-return horizons in candles measured after every setup
+var DefaultHorizons = []int{5, 10, 20}
+
+// minSamples is the number of setups below which a symbol's statistics are highlighted as unreliable
+const minSamples = 5
+
+// Horizon summarizes the forward returns of a symbol's setups a number of candles after the signal
+// Returns are side-adjusted percentages of the signal close, so a falling price counts as a gain for Short setups
+type Horizon struct {
+	Bars          int     // Candles after the signal candle
+	Samples       int     // Setups with enough later candles to measure the return
+	AverageReturn float64 // Mean side-adjusted return in percent
+	WinRate       float64 // Share of samples with a positive side-adjusted return (0 to 1)
+}
+
+// SymbolStats is the backscan of one symbol
+type SymbolStats struct {
+	Symbol   string    // Stock symbol
+	Candles  int       // Candles of stored history scanned
+	Setups   int       // Valid setups found over the history
+	Long     int       // Long setups among them
+	Short    int       // Short setups among them
+	Horizons []Horizon // Forward returns per horizon, in the order the horizons were given
+}
+
+// Scan validates the strategy on every closed candle of a symbol's history, as a live scan would have on that day,
+// and measures the close-to-close return after each setup; Long keeps its priority over Short
+// No horizons measures DefaultHorizons
+func Scan(sapanStrategy strategy.Validator, symbol string, candles []models.Candle, horizons []int) SymbolStats {
+	if len(horizons) == 0 {
+		horizons = DefaultHorizons
+	}
+	stats := SymbolStats{Symbol: symbol, Candles: len(candles), Horizons: make([]Horizon, len(horizons))}
+	wins := make([]int, len(horizons))
+	for h, bars := range horizons {
+		stats.Horizons[h].Bars = bars
+	}
+
+	for i := strategy.ValidatorMinimumCandles(sapanStrategy) - 1; i < len(candles); i++ {
+		history := candles[:i+1]
+		side := watcher.LongSide
+		if !sapanStrategy.ValidateLongSetup(symbol, history).IsValid {
+			if !sapanStrategy.ValidateShortSetup(symbol, history).IsValid {
+				continue
+			}
+			side = watcher.ShortSide
+		}
+		stats.Setups++
+		if side == watcher.LongSide {
+			stats.Long++
+		} else {
+			stats.Short++
+		}
+
+		for h, bars := range horizons {
+			if i+bars >= len(candles) || candles[i].Close <= 0 {
+				continue // Too recent to measure this horizon
+			}
+			change := (candles[i+bars].Close/candles[i].Close - 1) * 100
+			if side == watcher.ShortSide {
+				change = -change
+			}
+			horizon := &stats.Horizons[h]
+			horizon.Samples++
+			horizon.AverageReturn += change
+			if change > 0 {
+				wins[h]++
+			}
+		}
+	}
+
+	for h := range stats.Horizons {
+		if horizon := &stats.Horizons[h]; horizon.Samples > 0 {
+			horizon.AverageReturn /= float64(horizon.Samples)
+			horizon.WinRate = float64(wins[h]) / float64(horizon.Samples)
+		}
+	}
+	return stats
+}
+
+// ScanAll backscans every symbol on the CPU pool (sequentially when it is nil)
+// The result is sorted by the average return at the last horizon, best first, with unmeasured symbols last
+func ScanAll(sapanStrategy strategy.Validator, history map[string][]models.Candle, horizons []int, cpuPool *cpupool.Pool) []SymbolStats {
+	if len(horizons) == 0 {
+		horizons = DefaultHorizons
+	}
+	symbols := make([]string, 0, len(history))
+	for symbol := range history {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	results := make([]SymbolStats, len(symbols))
+	cpupool.Each(cpuPool, len(symbols), func(i int) {
+		results[i] = Scan(sapanStrategy, symbols[i], history[symbols[i]], horizons)
+	})
+
+	last := len(horizons) - 1
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Horizons[last], results[j].Horizons[last]
+		if (a.Samples > 0) != (b.Samples > 0) {
+			return a.Samples > 0
+		}
+		return a.AverageReturn > b.AverageReturn
+	})
+	return results
+}
+
+// Print renders one row per symbol; symbols with fewer than 5 setups are highlighted in yellow
+func Print(w io.Writer, palette *output.Palette, results []SymbolStats) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No stored history to backscan")
+		return
+	}
+	headers := []string{"Symbol", "Candles", "Setups", "Long", "Short"}
+	for _, horizon := range results[0].Horizons {
+		headers = append(headers, fmt.Sprintf("Avg %d", horizon.Bars), fmt.Sprintf("Win %d", horizon.Bars))
+	}
+
+	fmt.Fprintln(w, palette.Bold("Pattern Backscan:"))
+	table := output.NewTable(palette, headers...)
+	for _, result := range results {
+		color := palette.Plain
+		if result.Setups < minSamples {
+			color = palette.Yellow
+		}
+		cells := []string{result.Symbol, strconv.Itoa(result.Candles), strconv.Itoa(result.Setups), strconv.Itoa(result.Long), strconv.Itoa(result.Short)}
+		for _, horizon := range result.Horizons {
+			if horizon.Samples == 0 {
+				cells = append(cells, "-", "-")
+				continue
+			}
+			cells = append(cells, fmt.Sprintf("%+.2f%%", horizon.AverageReturn), fmt.Sprintf("%.0f%%", horizon.WinRate*100))
+		}
+		table.AddRow(color, cells...)
+	}
+	table.Render(w)
+	fmt.Fprintf(w, "\nReturns are close to close after the signal candle, inverted for Short setups; yellow rows have fewer than %d setups.\n", minSamples)
+}
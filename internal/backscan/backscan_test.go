@@ -0,0 +1,86 @@
+package backscan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+func TestScanMeasuresForwardReturns(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rising := sapantest.NewCandleBuilder(start, 100).Trend(250, 1).Candles()
+	validator := sapantest.NewStrategy().
+		SetLong("UP", sapantest.ValidSetup(strategy.LongPinbarReversal, 110, 105, 120)).
+		SetShort("FADE", sapantest.ValidSetup(strategy.ShortPinbarReversal, 90, 95, 80))
+
+	up := Scan(validator, "UP", rising, []int{5, 100})
+	setups := len(rising) - strategy.MinimumCandles + 1
+	if up.Setups != setups || up.Long != setups || up.Short != 0 {
+		t.Fatalf("UP setups = %d (%d long, %d short), want %d long", up.Setups, up.Long, up.Short, setups)
+	}
+	five := up.Horizons[0]
+	if five.Bars != 5 || five.Samples != setups-5 || five.WinRate != 1 || five.AverageReturn <= 0 {
+		t.Errorf("UP 5-bar horizon = %+v, want %d samples that all gained", five, setups-5)
+	}
+	if up.Horizons[1].Samples != 0 {
+		t.Errorf("UP 100-bar horizon = %+v, want no samples beyond the history", up.Horizons[1])
+	}
+
+	// A Short setup in a rising market loses: the side-adjusted return is negative
+	fade := Scan(validator, "FADE", rising, []int{5})
+	if fade.Short != setups || fade.Horizons[0].WinRate != 0 || fade.Horizons[0].AverageReturn >= 0 {
+		t.Errorf("FADE = %+v, want %d losing Short setups", fade, setups)
+	}
+	if none := Scan(validator, "NONE", rising, nil); none.Setups != 0 || len(none.Horizons) != len(DefaultHorizons) {
+		t.Errorf("NONE = %+v, want no setups over the default horizons", none)
+	}
+}
+
+// fastStrategy is a validator whose indicators need only 50 candles, like a faster EMA stack
+type fastStrategy struct {
+	*sapantest.Strategy
+}
+
+func (fastStrategy) MinimumCandles() int {
+	return 50
+}
+
+func TestScanStartsAtTheValidatorsMinimum(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rising := sapantest.NewCandleBuilder(start, 100).Trend(250, 1).Candles()
+	validator := fastStrategy{sapantest.NewStrategy().SetLong("UP", sapantest.ValidSetup(strategy.LongPinbarReversal, 110, 105, 120))}
+
+	if up := Scan(validator, "UP", rising, []int{5}); up.Setups != len(rising)-50+1 {
+		t.Errorf("UP setups = %d, want one on every candle from the 50th", up.Setups)
+	}
+}
+
+func TestScanAllSortsByReturn(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rising := sapantest.NewCandleBuilder(start, 100).Trend(250, 1).Candles()
+	validator := sapantest.NewStrategy().
+		SetLong("UP", sapantest.ValidSetup(strategy.LongPinbarReversal, 110, 105, 120)).
+		SetShort("FADE", sapantest.ValidSetup(strategy.ShortPinbarReversal, 90, 95, 80))
+	history := map[string][]models.Candle{"FADE": rising, "NONE": rising, "UP": rising}
+
+	results := ScanAll(validator, history, []int{5}, nil)
+	var order []string
+	for _, result := range results {
+		order = append(order, result.Symbol)
+	}
+	if got := strings.Join(order, ","); got != "UP,FADE,NONE" {
+		t.Errorf("order = %s, want UP,FADE,NONE", got)
+	}
+
+	var out bytes.Buffer
+	Print(&out, output.NewPalette(false), results)
+	if !strings.Contains(out.String(), "Avg 5") || !strings.Contains(out.String(), "100%") {
+		t.Errorf("report lacks the 5-candle columns:\n%s", out.String())
+	}
+}
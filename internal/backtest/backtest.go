@@ -0,0 +1,161 @@
+// Package backtest replays the SAPAN strategy over historical candles and measures how its trades performed
+// This package detects setups bar by bar, simulates each trade plan, and aggregates performance analytics
+package backtest
+
+import (
+	"context"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"github.com/erhankrygt/sapan/internal/outcome"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Trade is one simulated SAPAN trade
+type Trade struct {
+	Symbol     string    `json:"symbol"`      // Stock ticker symbol
+	Side       string    `json:"side"`        // Long or Short
+	Pattern    string    `json:"pattern"`     // Candlestick pattern that confirmed the setup
+	Sector     string    `json:"sector"`      // Business sector of the stock
+	Score      float64   `json:"score"`       // Setup quality score from 0 to 100
+	SignalDate time.Time `json:"signal_date"` // Date of the confirmation candle
+	EntryDate  time.Time `json:"entry_date"`  // Date the entry triggered
+	ExitDate   time.Time `json:"exit_date"`   // Date of the exit (or last candle for open trades)
+	Entry      float64   `json:"entry"`       // Planned entry price
+	Stop       float64   `json:"stop"`        // Protective stop-loss price
+	Target     float64   `json:"target"`      // Profit target price
+	ExitPrice  float64   `json:"exit_price"`  // Exit price (or mark price for open trades)
+	Status     string    `json:"status"`      // target, stop, time_stop, or open
+	RMultiple  float64   `json:"r_multiple"`  // Result in multiples of the initial risk
+	BarsHeld   int       `json:"bars_held"`   // Candles between entry and exit
+}
+
+// Closed reports whether the trade reached its stop or target, or was closed by its time stop
+func (t Trade) Closed() bool {
+	return t.Status == watcher.OutcomeTarget || t.Status == watcher.OutcomeStop || t.Status == watcher.OutcomeTimeStop
+}
+
+// Failure records a symbol that could not be backtested
+type Failure struct {
+	Symbol string `json:"symbol"` // Stock ticker symbol
+	Error  string `json:"error"`  // Error message
+}
+
+// Backtester replays the strategy over the full candle history of each stock
+type Backtester struct {
+	stockFetcher  data.Fetcher       // Data fetcher for retrieving historical candles
+	sapanStrategy strategy.Validator // Strategy whose setups are replayed
+	outputSize    int                // Number of candles to request per symbol
+	requestDelay  time.Duration      // Delay between API requests (to respect rate limits)
+	entryWindow   int                // Candles after the signal during which the entry may trigger
+	cpuPool       *cpupool.Pool      // Optional pool replaying stocks while the next ones are fetched (nil replays inline)
+}
+
+// NewBacktester creates a new backtester
+// Setups whose entry does not trigger within entryWindow candles are discarded like expired orders
+func NewBacktester(stockFetcher data.Fetcher, sapanStrategy strategy.Validator, outputSize int, requestDelay time.Duration, entryWindow int) *Backtester {
+	return &Backtester{
+		stockFetcher:  stockFetcher,  // Initialize data fetcher
+		sapanStrategy: sapanStrategy, // Initialize strategy
+		outputSize:    outputSize,    // Set candle count per request
+		requestDelay:  requestDelay,  // Set request delay
+		entryWindow:   entryWindow,   // Set entry window
+	}
+}
+
+// SetCPUPool replays fetched stocks on the pool, so the CPU-bound walk of one stock overlaps the fetches of the next
+// The strategy must be safe for concurrent use
+func (b *Backtester) SetCPUPool(cpuPool *cpupool.Pool) {
+	b.cpuPool = cpuPool
+}
+
+// Run fetches every stock sequentially, backtests each one, and returns the trades sorted by entry date with any failures
+// The result does not depend on the CPU pool: trades are collected per stock and merged in stock order
+func (b *Backtester) Run(stocks []models.Stock) ([]Trade, []Failure) {
+	var failures []Failure
+	perStock := make([][]Trade, len(stocks))
+	var replays sync.WaitGroup
+	for i, stock := range stocks {
+		if i > 0 && b.requestDelay > 0 {
+			time.Sleep(b.requestDelay) // Respect API limits between symbols
+		}
+
+		candleData, err := b.stockFetcher.FetchStockData(context.Background(), stock.Symbol, b.outputSize)
+		if err != nil {
+			log.Printf("Backtest: Failed to fetch data for %s: %v", stock.Symbol, err)
+			failures = append(failures, Failure{Symbol: stock.Symbol, Error: err.Error()})
+			continue
+		}
+		if b.cpuPool == nil {
+			perStock[i] = Replay(b.sapanStrategy, stock, candleData.Candles, b.entryWindow)
+			continue
+		}
+		replays.Add(1)
+		b.cpuPool.Go(func() {
+			defer replays.Done()
+			perStock[i] = Replay(b.sapanStrategy, stock, candleData.Candles, b.entryWindow)
+		})
+	}
+	replays.Wait()
+
+	var trades []Trade
+	for _, stockTrades := range perStock {
+		trades = append(trades, stockTrades...)
+	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].EntryDate.Before(trades[j].EntryDate) })
+	return trades, failures
+}
+
+// Replay walks the candles of one stock, validating the strategy on every closed bar as a live scan would
+// Only one trade per stock is open at a time: the walk resumes after the previous trade exits
+func Replay(sapanStrategy strategy.Validator, stock models.Stock, candles []models.Candle, entryWindow int) []Trade {
+	var trades []Trade
+	for i := strategy.ValidatorMinimumCandles(sapanStrategy) - 1; i < len(candles)-1; i++ {
+		history := candles[:i+1]
+		validation := sapanStrategy.ValidateLongSetup(stock.Symbol, history)
+		side := watcher.LongSide
+		if !validation.IsValid {
+			validation = sapanStrategy.ValidateShortSetup(stock.Symbol, history)
+			side = watcher.ShortSide
+		}
+		if !validation.IsValid {
+			continue
+		}
+
+		plan := validation.TradePlan
+		future := candles[i+1:]
+		sim := outcome.Simulate(side, plan.Entry, plan.Stop, plan.Target, plan.TimeStop, future)
+		if !sim.EntryTriggered || (entryWindow > 0 && len(future) > entryWindow && sim.EntryDate.After(future[entryWindow-1].Time())) {
+			continue // Order expired before price reached the entry
+		}
+
+		trades = append(trades, Trade{
+			Symbol:     stock.Symbol,
+			Side:       side,
+			Pattern:    validation.PatternType.String(),
+			Sector:     stock.Sector,
+			Score:      validation.Score,
+			SignalDate: candles[i].Time(),
+			EntryDate:  sim.EntryDate,
+			ExitDate:   sim.ExitDate,
+			Entry:      plan.Entry,
+			Stop:       plan.Stop,
+			Target:     plan.Target,
+			ExitPrice:  sim.ExitPrice,
+			Status:     sim.Status,
+			RMultiple:  sim.RMultiple,
+			BarsHeld:   sim.BarsHeld,
+		})
+
+		// Skip ahead to the exit candle so trades on the same stock never overlap
+		for i+1 < len(candles) && !candles[i+1].Time().After(sim.ExitDate) {
+			i++
+		}
+	}
+	return trades
+}
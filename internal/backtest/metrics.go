@@ -0,0 +1,210 @@
+// Package backtest replays the SAPAN strategy over historical candles and measures how its trades performed
+// This package detects setups bar by bar, simulates each trade plan, and aggregates performance analytics
+package backtest
+
+import (
+	"github.com/erhankrygt/sapan/internal/benchmark"
+	"math"
+	"sort"
+	"time"
+)
+
+// Metrics are the performance statistics of a set of closed trades
+// Equity figures assume every trade risks a fixed fraction of the current account, so a trade returns riskFraction × R
+type Metrics struct {
+	Trades       int     `json:"trades"`        // Closed trades (open trades are excluded)
+	Wins         int     `json:"wins"`          // Trades with a positive R-multiple
+	Losses       int     `json:"losses"`        // Trades with a zero or negative R-multiple
+	WinRate      float64 `json:"win_rate"`      // Share of winning trades (0-1)
+	ProfitFactor float64 `json:"profit_factor"` // Gross profit divided by gross loss in R (0 when there are no losses)
+	Expectancy   float64 `json:"expectancy"`    // Expected R per trade: winRate × avgWin − lossRate × avgLoss
+	AverageR     float64 `json:"average_r"`     // Mean R-multiple
+	TotalR       float64 `json:"total_r"`       // Sum of R-multiples
+	MaxDrawdown  float64 `json:"max_drawdown"`  // Largest peak-to-trough equity decline (0-1)
+	CAGR         float64 `json:"cagr"`          // Compound annual growth rate of the equity curve
+	Sharpe       float64 `json:"sharpe"`        // Annualized mean over standard deviation of per-trade returns
+	Sortino      float64 `json:"sortino"`       // Annualized mean over downside deviation of per-trade returns
+	FinalEquity  float64 `json:"final_equity"`  // Ending equity as a multiple of the starting equity
+}
+
+// Breakdown pairs a group name, such as a pattern or sector, with its metrics
+type Breakdown struct {
+	Name    string  `json:"name"`    // Group name
+	Metrics Metrics `json:"metrics"` // Metrics of the group's trades
+}
+
+// ComputeMetrics aggregates closed trades, taken in exit order, into performance statistics
+func ComputeMetrics(trades []Trade, riskFraction float64) Metrics {
+	closed := make([]Trade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.Closed() {
+			closed = append(closed, trade)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return closed[i].ExitDate.Before(closed[j].ExitDate) })
+
+	m := Metrics{Trades: len(closed), FinalEquity: 1}
+	if len(closed) == 0 {
+		return m
+	}
+
+	var grossWin, grossLoss, sumReturn, sumSquares, sumDownside float64
+	equity, peak := 1.0, 1.0
+	returns := make([]float64, len(closed))
+	for i, trade := range closed {
+		if trade.RMultiple > 0 {
+			m.Wins++
+			grossWin += trade.RMultiple
+		} else {
+			m.Losses++
+			grossLoss -= trade.RMultiple
+		}
+		m.TotalR += trade.RMultiple
+
+		returns[i] = riskFraction * trade.RMultiple
+		sumReturn += returns[i]
+		if returns[i] < 0 {
+			sumDownside += returns[i] * returns[i]
+		}
+
+		equity *= 1 + returns[i]
+		peak = math.Max(peak, equity)
+		m.MaxDrawdown = math.Max(m.MaxDrawdown, (peak-equity)/peak)
+	}
+
+	n := float64(len(closed))
+	m.WinRate = float64(m.Wins) / n
+	m.AverageR = m.TotalR / n
+	if grossLoss > 0 {
+		m.ProfitFactor = grossWin / grossLoss
+	}
+	if m.Wins > 0 {
+		m.Expectancy += m.WinRate * grossWin / float64(m.Wins)
+	}
+	if m.Losses > 0 {
+		m.Expectancy -= (1 - m.WinRate) * grossLoss / float64(m.Losses)
+	}
+	m.FinalEquity = equity
+
+	// Annualize over the span from the first entry to the last exit
+	first := closed[0].EntryDate
+	for _, trade := range closed {
+		if trade.EntryDate.Before(first) {
+			first = trade.EntryDate
+		}
+	}
+	years := closed[len(closed)-1].ExitDate.Sub(first).Hours() / (24 * 365.25)
+	if years < 1.0/365.25 {
+		years = 1.0 / 365.25
+	}
+	if equity > 0 {
+		m.CAGR = math.Pow(equity, 1/years) - 1
+	} else {
+		m.CAGR = -1
+	}
+
+	mean := sumReturn / n
+	for _, r := range returns {
+		sumSquares += (r - mean) * (r - mean)
+	}
+	annualization := math.Sqrt(n / years) // Trades per year
+	if std := math.Sqrt(sumSquares / n); std > 0 {
+		m.Sharpe = mean / std * annualization
+	}
+	if downside := math.Sqrt(sumDownside / n); downside > 0 {
+		m.Sortino = mean / downside * annualization
+	}
+	return m
+}
+
+// EquityCurve compounds closed trades, taken in exit order, into equity points at each exit date
+// The curve starts at 1 on the earliest entry so a benchmark can be compared over the same span
+func EquityCurve(trades []Trade, riskFraction float64) []benchmark.EquityPoint {
+	closed := make([]Trade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.Closed() {
+			closed = append(closed, trade)
+		}
+	}
+	if len(closed) == 0 {
+		return nil
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return closed[i].ExitDate.Before(closed[j].ExitDate) })
+
+	first := closed[0].EntryDate
+	for _, trade := range closed {
+		if trade.EntryDate.Before(first) {
+			first = trade.EntryDate
+		}
+	}
+	curve := []benchmark.EquityPoint{{Date: first, Equity: 1}}
+	equity := 1.0
+	for _, trade := range closed {
+		equity *= 1 + riskFraction*trade.RMultiple
+		curve = append(curve, benchmark.EquityPoint{Date: trade.ExitDate, Equity: equity})
+	}
+	return curve
+}
+
+// GroupMetrics computes metrics per group key, sorted by group name
+func GroupMetrics(trades []Trade, riskFraction float64, key func(Trade) string) []Breakdown {
+	groups := make(map[string][]Trade)
+	for _, trade := range trades {
+		name := key(trade)
+		if name == "" {
+			name = "Unknown"
+		}
+		groups[name] = append(groups[name], trade)
+	}
+
+	breakdowns := make([]Breakdown, 0, len(groups))
+	for name, group := range groups {
+		breakdowns = append(breakdowns, Breakdown{Name: name, Metrics: ComputeMetrics(group, riskFraction)})
+	}
+	sort.Slice(breakdowns, func(i, j int) bool { return breakdowns[i].Name < breakdowns[j].Name })
+	return breakdowns
+}
+
+// Result is the complete backtest document written as JSON and rendered as HTML
+type Result struct {
+	GeneratedAt time.Time   `json:"generated_at"` // Time the backtest finished
+	RiskPercent float64     `json:"risk_percent"` // Account percentage risked per trade
+	Symbols     int         `json:"symbols"`      // Stocks backtested
+	OpenTrades  int         `json:"open_trades"`  // Trades still open at the end of the data
+	Metrics     Metrics     `json:"metrics"`      // Statistics over every closed trade
+	BySide      []Breakdown `json:"by_side"`      // Statistics per trading side
+	ByPattern   []Breakdown `json:"by_pattern"`   // Statistics per candlestick pattern
+	BySector    []Breakdown `json:"by_sector"`    // Statistics per sector
+	Failures    []Failure   `json:"failures"`     // Symbols that could not be backtested
+	Trades      []Trade     `json:"trades"`       // Every simulated trade sorted by entry date
+
+	Benchmark *benchmark.Comparison `json:"benchmark,omitempty"` // Buy-and-hold comparison (nil when BENCHMARK_SYMBOL is unset or unavailable)
+}
+
+// BuildResult computes overall and per-group analytics for the simulated trades
+func BuildResult(trades []Trade, failures []Failure, symbols int, riskPercent float64) Result {
+	riskFraction := riskPercent / 100
+	result := Result{
+		GeneratedAt: time.Now().UTC(),
+		RiskPercent: riskPercent,
+		Symbols:     symbols,
+		Metrics:     ComputeMetrics(trades, riskFraction),
+		BySide:      GroupMetrics(trades, riskFraction, func(t Trade) string { return t.Side }),
+		ByPattern:   GroupMetrics(trades, riskFraction, func(t Trade) string { return t.Pattern }),
+		BySector:    GroupMetrics(trades, riskFraction, func(t Trade) string { return t.Sector }),
+		Failures:    failures,
+		Trades:      trades,
+	}
+	for _, trade := range trades {
+		if !trade.Closed() {
+			result.OpenTrades++
+		}
+	}
+	if result.Failures == nil {
+		result.Failures = []Failure{} // Encode as [] rather than null for a stable schema
+	}
+	if result.Trades == nil {
+		result.Trades = []Trade{}
+	}
+	return result
+}
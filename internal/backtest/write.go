@@ -0,0 +1,166 @@
+// Package backtest replays the SAPAN strategy over historical candles and measures how its trades performed
+// This package detects setups bar by bar, simulates each trade plan, and aggregates performance analytics
+package backtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"html/template"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteJSON writes the backtest result as indented JSON, replacing the file atomically
+func WriteJSON(path string, result Result) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backtest result: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write backtest result: %v", err)
+	}
+	return nil
+}
+
+// WriteHTML renders a self-contained HTML backtest report into dir and returns the file path
+func WriteHTML(dir string, result Result, location *time.Location) (string, error) {
+	if location == nil {
+		location = time.Local
+	}
+
+	var buffer bytes.Buffer
+	err := htmlReportTemplate.Execute(&buffer, htmlReportData{
+		Result:    result,
+		Generated: result.GeneratedAt.In(location).Format("2006-01-02 15:04:05 MST"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render backtest report: %v", err)
+	}
+
+	path := filepath.Join(dir, "sapan-backtest-"+result.GeneratedAt.In(location).Format("20060102-150405")+".html")
+	if err := fsutil.WriteFileAtomic(path, buffer.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to write backtest report: %v", err)
+	}
+	return path, nil
+}
+
+// Print writes the overall metrics and the per-group breakdowns as aligned tables
+func (r Result) Print(w io.Writer) error {
+	fmt.Fprintf(w, "Backtest: %d symbols, %d closed trades, %d open, risking %.2f%% per trade\n\n",
+		r.Symbols, r.Metrics.Trades, r.OpenTrades, r.RiskPercent)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tTRADES\tWIN RATE\tPROFIT FACTOR\tEXPECTANCY\tAVG R\tMAX DD\tCAGR\tSHARPE\tSORTINO")
+	printMetricsRow(tw, "All", r.Metrics)
+	for _, group := range [][]Breakdown{r.BySide, r.ByPattern, r.BySector} {
+		for _, breakdown := range group {
+			printMetricsRow(tw, breakdown.Name, breakdown.Metrics)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if r.Benchmark != nil {
+		fmt.Fprintln(w)
+		r.Benchmark.Print(w)
+	}
+	return nil
+}
+
+// printMetricsRow writes one labelled metrics row
+func printMetricsRow(w io.Writer, label string, m Metrics) {
+	fmt.Fprintf(w, "%s\t%d\t%.1f%%\t%.2f\t%.2fR\t%.2f\t%.1f%%\t%.1f%%\t%.2f\t%.2f\n",
+		label, m.Trades, m.WinRate*100, m.ProfitFactor, m.Expectancy, m.AverageR,
+		m.MaxDrawdown*100, m.CAGR*100, m.Sharpe, m.Sortino)
+}
+
+// htmlReportData is the view model passed to the HTML template
+type htmlReportData struct {
+	Result
+	Generated string // Formatted generation time in the display timezone
+}
+
+// htmlReportTemplate is the self-contained layout of the backtest report
+var htmlReportTemplate = template.Must(template.New("backtest").Funcs(template.FuncMap{
+	"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SAPAN Backtest {{.Generated}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f3f3f3; }
+.Long { color: #1a7f37; font-weight: bold; }
+.Short { color: #cf222e; font-weight: bold; }
+.target { color: #1a7f37; }
+.stop { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>SAPAN Backtest Report</h1>
+<p>Generated {{.Generated}} &middot; {{.Symbols}} symbols &middot; {{.Metrics.Trades}} closed trades, {{.OpenTrades}} open &middot; {{printf "%.2f" .RiskPercent}}% risked per trade</p>
+
+{{define "metrics"}}<table>
+<thead><tr><th>Group</th><th>Trades</th><th>Win rate</th><th>Profit factor</th><th>Expectancy</th><th>Avg R</th><th>Total R</th><th>Max drawdown</th><th>CAGR</th><th>Sharpe</th><th>Sortino</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Metrics.Trades}}</td><td>{{percent .Metrics.WinRate}}</td><td>{{printf "%.2f" .Metrics.ProfitFactor}}</td><td>{{printf "%.2f" .Metrics.Expectancy}}R</td><td>{{printf "%.2f" .Metrics.AverageR}}</td><td>{{printf "%.1f" .Metrics.TotalR}}</td><td>{{percent .Metrics.MaxDrawdown}}</td><td>{{percent .Metrics.CAGR}}</td><td>{{printf "%.2f" .Metrics.Sharpe}}</td><td>{{printf "%.2f" .Metrics.Sortino}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+
+<h2>Overall</h2>
+<table>
+<tr><td>Win rate</td><td>{{percent .Metrics.WinRate}} ({{.Metrics.Wins}} wins / {{.Metrics.Losses}} losses)</td></tr>
+<tr><td>Profit factor</td><td>{{printf "%.2f" .Metrics.ProfitFactor}}</td></tr>
+<tr><td>Expectancy</td><td>{{printf "%.2f" .Metrics.Expectancy}}R</td></tr>
+<tr><td>Average R-multiple</td><td>{{printf "%.2f" .Metrics.AverageR}}</td></tr>
+<tr><td>Max drawdown</td><td>{{percent .Metrics.MaxDrawdown}}</td></tr>
+<tr><td>CAGR</td><td>{{percent .Metrics.CAGR}}</td></tr>
+<tr><td>Sharpe / Sortino</td><td>{{printf "%.2f" .Metrics.Sharpe}} / {{printf "%.2f" .Metrics.Sortino}}</td></tr>
+<tr><td>Final equity</td><td>{{printf "%.3f" .Metrics.FinalEquity}}×</td></tr>
+</table>
+{{with .Benchmark}}
+<h2>Versus {{.Symbol}} Buy-and-Hold</h2>
+<p>{{.From.Format "2006-01-02"}} to {{.To.Format "2006-01-02"}} &middot; {{.Days}} trading days</p>
+<table>
+<thead><tr><th></th><th>Strategy</th><th>{{.Symbol}}</th></tr></thead>
+<tbody>
+<tr><td>Total return</td><td>{{percent .StrategyReturn}}</td><td>{{percent .BenchmarkReturn}}</td></tr>
+<tr><td>Annualized volatility</td><td>{{percent .StrategyVol}}</td><td>{{percent .BenchmarkVol}}</td></tr>
+<tr><td>Excess return</td><td colspan="2">{{percent .ExcessReturn}}</td></tr>
+<tr><td>Alpha (annualized)</td><td colspan="2">{{percent .Alpha}}</td></tr>
+<tr><td>Beta / correlation</td><td colspan="2">{{printf "%.2f" .Beta}} / {{printf "%.2f" .Correlation}}</td></tr>
+<tr><td>Benchmark max drawdown</td><td colspan="2">{{percent .BenchmarkDrawdown}}</td></tr>
+</tbody>
+</table>
+{{end}}
+
+<h2>By Side</h2>
+{{template "metrics" .BySide}}
+<h2>By Pattern</h2>
+{{template "metrics" .ByPattern}}
+<h2>By Sector</h2>
+{{template "metrics" .BySector}}
+
+<h2>Trades</h2>
+<table>
+<thead><tr><th>Symbol</th><th>Side</th><th>Pattern</th><th>Score</th><th>Entry date</th><th>Exit date</th><th>Entry</th><th>Stop</th><th>Target</th><th>Exit</th><th>Status</th><th>R</th><th>Bars</th></tr></thead>
+<tbody>
+{{range .Trades}}<tr><td>{{.Symbol}}</td><td class="{{.Side}}">{{.Side}}</td><td>{{.Pattern}}</td><td>{{printf "%.1f" .Score}}</td><td>{{.EntryDate.Format "2006-01-02"}}</td><td>{{.ExitDate.Format "2006-01-02"}}</td><td>{{printf "%.2f" .Entry}}</td><td>{{printf "%.2f" .Stop}}</td><td>{{printf "%.2f" .Target}}</td><td>{{printf "%.2f" .ExitPrice}}</td><td class="{{.Status}}">{{.Status}}</td><td>{{printf "%.2f" .RMultiple}}</td><td>{{.BarsHeld}}</td></tr>
+{{end}}</tbody>
+</table>
+{{if .Failures}}
+<h2>Failures</h2>
+<ul>
+{{range .Failures}}<li>{{.Symbol}}: {{.Error}}</li>
+{{end}}</ul>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,134 @@
+// Package benchmark compares a strategy's equity curve with buying and holding an index
+// The comparison answers whether SAPAN adds value over simply owning the benchmark
+package benchmark
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// tradingDaysPerYear annualizes daily alpha and volatility
+const tradingDaysPerYear = 252
+
+// EquityPoint is the strategy's equity, as a multiple of the starting equity, after the given date
+type EquityPoint struct {
+	Date   time.Time `json:"date"`   // Date the equity was reached
+	Equity float64   `json:"equity"` // Equity as a multiple of the starting equity
+}
+
+// Comparison holds the strategy and benchmark performance over the same period
+type Comparison struct {
+	Symbol            string    `json:"symbol"`             // Benchmark ticker symbol
+	From              time.Time `json:"from"`               // First day of the compared period
+	To                time.Time `json:"to"`                 // Last day of the compared period
+	Days              int       `json:"days"`               // Benchmark trading days in the period
+	StrategyReturn    float64   `json:"strategy_return"`    // Total strategy return over the period
+	BenchmarkReturn   float64   `json:"benchmark_return"`   // Total buy-and-hold return of the benchmark
+	ExcessReturn      float64   `json:"excess_return"`      // Strategy return minus benchmark return
+	Alpha             float64   `json:"alpha"`              // Annualized return not explained by benchmark exposure
+	Beta              float64   `json:"beta"`               // Sensitivity of daily strategy returns to the benchmark
+	Correlation       float64   `json:"correlation"`        // Correlation of daily returns
+	StrategyVol       float64   `json:"strategy_vol"`       // Annualized volatility of daily strategy returns
+	BenchmarkVol      float64   `json:"benchmark_vol"`      // Annualized volatility of daily benchmark returns
+	BenchmarkDrawdown float64   `json:"benchmark_drawdown"` // Largest peak-to-trough decline of the benchmark
+}
+
+// Compare measures the equity curve against buy-and-hold of the benchmark candles over the curve's period
+// The strategy equity is carried forward between points, so both series are sampled on benchmark trading days
+func Compare(symbol string, curve []EquityPoint, candles []models.Candle) (Comparison, error) {
+	if len(curve) < 2 {
+		return Comparison{}, fmt.Errorf("the strategy equity curve needs at least two points")
+	}
+	sort.SliceStable(curve, func(i, j int) bool { return curve[i].Date.Before(curve[j].Date) })
+	from, to := curve[0].Date, curve[len(curve)-1].Date
+
+	var window []models.Candle
+	for _, candle := range candles {
+		if !candle.Date.Before(truncateDay(from)) && !candle.Date.After(to) {
+			window = append(window, candle)
+		}
+	}
+	if len(window) < 3 {
+		return Comparison{}, fmt.Errorf("benchmark %s has %d candles between %s and %s; fetch more history",
+			symbol, len(window), from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	// Sample the strategy equity at every benchmark close
+	strategyEquity := make([]float64, len(window))
+	next, equity := 0, curve[0].Equity
+	for i, candle := range window {
+		for next < len(curve) && !truncateDay(curve[next].Date).After(candle.Date) {
+			equity = curve[next].Equity
+			next++
+		}
+		strategyEquity[i] = equity
+	}
+
+	strategyReturns := make([]float64, 0, len(window)-1)
+	benchmarkReturns := make([]float64, 0, len(window)-1)
+	peak := window[0].Close
+	c := Comparison{Symbol: symbol, From: window[0].Date, To: window[len(window)-1].Date, Days: len(window)}
+	for i := 1; i < len(window); i++ {
+		strategyReturns = append(strategyReturns, strategyEquity[i]/strategyEquity[i-1]-1)
+		benchmarkReturns = append(benchmarkReturns, window[i].Close/window[i-1].Close-1)
+		peak = math.Max(peak, window[i].Close)
+		c.BenchmarkDrawdown = math.Max(c.BenchmarkDrawdown, (peak-window[i].Close)/peak)
+	}
+
+	c.StrategyReturn = strategyEquity[len(strategyEquity)-1]/strategyEquity[0] - 1
+	c.BenchmarkReturn = window[len(window)-1].Close/window[0].Close - 1
+	c.ExcessReturn = c.StrategyReturn - c.BenchmarkReturn
+
+	meanS, meanB := mean(strategyReturns), mean(benchmarkReturns)
+	var covariance, varianceS, varianceB float64
+	for i := range strategyReturns {
+		ds, db := strategyReturns[i]-meanS, benchmarkReturns[i]-meanB
+		covariance += ds * db
+		varianceS += ds * ds
+		varianceB += db * db
+	}
+	n := float64(len(strategyReturns))
+	if varianceB > 0 {
+		c.Beta = covariance / varianceB
+	}
+	if varianceS > 0 && varianceB > 0 {
+		c.Correlation = covariance / math.Sqrt(varianceS*varianceB)
+	}
+	c.Alpha = (meanS - c.Beta*meanB) * tradingDaysPerYear
+	c.StrategyVol = math.Sqrt(varianceS/n) * math.Sqrt(tradingDaysPerYear)
+	c.BenchmarkVol = math.Sqrt(varianceB/n) * math.Sqrt(tradingDaysPerYear)
+	return c, nil
+}
+
+// Print writes the comparison as a short plain-text summary
+func (c Comparison) Print(w io.Writer) {
+	fmt.Fprintf(w, "Benchmark: %s buy-and-hold, %s to %s (%d trading days)\n", c.Symbol,
+		c.From.Format("2006-01-02"), c.To.Format("2006-01-02"), c.Days)
+	fmt.Fprintf(w, "  Return      strategy %+.1f%%   benchmark %+.1f%%   excess %+.1f%%\n",
+		c.StrategyReturn*100, c.BenchmarkReturn*100, c.ExcessReturn*100)
+	fmt.Fprintf(w, "  Volatility  strategy %.1f%%    benchmark %.1f%%    benchmark max DD %.1f%%\n",
+		c.StrategyVol*100, c.BenchmarkVol*100, c.BenchmarkDrawdown*100)
+	fmt.Fprintf(w, "  Alpha %+.1f%% per year, beta %.2f, correlation %.2f\n", c.Alpha*100, c.Beta, c.Correlation)
+}
+
+// mean returns the arithmetic mean of the values (0 for none)
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// truncateDay drops the time of day so trade timestamps line up with daily candle dates
+func truncateDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
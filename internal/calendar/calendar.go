@@ -0,0 +1,215 @@
+// Package calendar provides market trading calendars for the SAPAN strategy
+// This package knows each market's sessions and holidays so runs can skip closed days and ignore unfinished candles
+package calendar
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"strings"
+	"time"
+)
+
+// Market identifiers accepted by New
+const (
+	MarketUS     = "us"     // NYSE / NASDAQ
+	MarketBIST   = "bist"   // Borsa Istanbul
+	MarketCrypto = "crypto" // Crypto exchanges trading around the clock
+)
+
+// Candle close alignments accepted by SetCandleClose
+const (
+	CandleCloseExchange = "exchange" // Daily candles close with the session, or at midnight exchange time on 24/7 markets
+	CandleCloseUTC      = "utc"      // Daily candles of 24/7 markets close at midnight UTC
+)
+
+// holidayRule returns the holidays of a market for one year as dates in the market's location
+type holidayRule func(year int) []time.Time
+
+// Calendar describes when a market is open
+// Weekends are closed unless the calendar trades around the clock; holidays come from rules plus configured dates
+type Calendar struct {
+	Name         string          // Market identifier (us, bist, crypto)
+	Location     *time.Location  // Exchange timezone
+	OpenMinutes  int             // Session open as minutes after midnight
+	CloseMinutes int             // Session close as minutes after midnight
+	AlwaysOpen   bool            // Market trades 24/7 with no weekends or holidays
+	candlesUTC   bool            // Daily candles close at midnight UTC instead of midnight exchange time (24/7 markets only)
+	rules        holidayRule     // Recurring holidays (nil when the market has none)
+	extra        map[string]bool // Additional holidays from configuration, keyed by YYYY-MM-DD
+}
+
+// New returns the calendar for a market with additional holidays given as YYYY-MM-DD dates
+// Extra holidays cover closures without fixed rules, such as BIST's religious holidays
+func New(market string, extraHolidays []string) (*Calendar, error) {
+	var cal *Calendar
+	switch strings.ToLower(strings.TrimSpace(market)) {
+	case MarketUS, "":
+		cal = &Calendar{Name: MarketUS, Location: loadLocation("America/New_York"), OpenMinutes: 9*60 + 30, CloseMinutes: 16 * 60, rules: usHolidays}
+	case MarketBIST:
+		cal = &Calendar{Name: MarketBIST, Location: loadLocation("Europe/Istanbul"), OpenMinutes: 10 * 60, CloseMinutes: 18 * 60, rules: bistHolidays}
+	case MarketCrypto:
+		cal = &Calendar{Name: MarketCrypto, Location: time.UTC, CloseMinutes: 24 * 60, AlwaysOpen: true}
+	default:
+		return nil, fmt.Errorf("unknown market %q (expected us, bist, or crypto)", market)
+	}
+
+	cal.extra = make(map[string]bool)
+	for _, holiday := range extraHolidays {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(holiday))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: %v", holiday, err)
+		}
+		cal.extra[date.Format("2006-01-02")] = true
+	}
+	return cal, nil
+}
+
+// SetLocation replaces the exchange timezone, e.g. with the timezone of the crypto exchange candles come from
+// Sessions, holidays, and cron schedules are evaluated in it
+func (c *Calendar) SetLocation(location *time.Location) {
+	c.Location = location
+}
+
+// SetCandleClose chooses when daily candles close: with the exchange day (the default) or at midnight UTC
+// UTC alignment only exists for 24/7 markets, whose candles have no session to close with
+func (c *Calendar) SetCandleClose(alignment string) error {
+	switch strings.ToLower(strings.TrimSpace(alignment)) {
+	case CandleCloseExchange, "":
+		c.candlesUTC = false
+	case CandleCloseUTC:
+		if !c.AlwaysOpen {
+			return fmt.Errorf("candles of the %s market close with its session and cannot be aligned to UTC", c.Name)
+		}
+		c.candlesUTC = true
+	default:
+		return fmt.Errorf("unknown candle close %q (expected exchange or utc)", alignment)
+	}
+	return nil
+}
+
+// candleLocation returns the timezone whose calendar days daily candles cover
+func (c *Calendar) candleLocation() *time.Location {
+	if c.candlesUTC {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// loadLocation loads an IANA timezone, falling back to UTC when the zone database is unavailable
+func loadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsHoliday reports whether the market is closed for a holiday on the given calendar day
+func (c *Calendar) IsHoliday(day time.Time) bool {
+	if c.AlwaysOpen {
+		return false
+	}
+	key := day.Format("2006-01-02")
+	if c.extra[key] {
+		return true
+	}
+	if c.rules == nil {
+		return false
+	}
+
+	for _, holiday := range c.rules(day.Year()) {
+		if holiday.Format("2006-01-02") == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTradingDay reports whether the market holds a session on the day containing t (in the exchange timezone)
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	day := t.In(c.Location)
+	if c.AlwaysOpen {
+		return true
+	}
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	return !c.IsHoliday(day)
+}
+
+// IsOpen reports whether the market is in session at time t
+func (c *Calendar) IsOpen(t time.Time) bool {
+	if !c.IsTradingDay(t) {
+		return false
+	}
+	local := t.In(c.Location)
+	minutes := local.Hour()*60 + local.Minute()
+	return minutes >= c.OpenMinutes && minutes < c.CloseMinutes
+}
+
+// SessionClose returns the time the session on the given calendar date closes
+// Only the year, month, and day of date are used, so naive UTC candle dates map onto the exchange day
+// A 24/7 market's session is its daily candle, closing at the following midnight in the candle timezone
+func (c *Calendar) SessionClose(date time.Time) time.Time {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.candleLocation())
+	return midnight.Add(time.Duration(c.CloseMinutes) * time.Minute)
+}
+
+// NextTradingDay returns the first trading day strictly after the day containing t, at midnight exchange time
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	local := t.In(c.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.Location)
+	for {
+		day = day.AddDate(0, 0, 1)
+		if c.IsTradingDay(day) {
+			return day
+		}
+	}
+}
+
+// IsCandleClosed reports whether the daily candle dated candleDate had finished forming at now
+func (c *Calendar) IsCandleClosed(candleDate, now time.Time) bool {
+	return !now.Before(c.SessionClose(candleDate))
+}
+
+// ClosedCandles drops trailing daily candles whose session had not closed yet at now
+// Providers publish the current day's candle while it is still forming; validating it would repaint signals
+func (c *Calendar) ClosedCandles(candles []models.Candle, now time.Time) []models.Candle {
+	end := len(candles)
+	for end > 0 && !c.IsCandleClosed(candles[end-1].Date, now) {
+		end--
+	}
+	return candles[:end]
+}
+
+// CandlesClosedAfter counts the candles of a timeframe that closed after the candle containing since, up to now
+// Intraday candles only count while the market is open; daily, weekly, and monthly candles close with their last session
+func (c *Calendar) CandlesClosedAfter(timeframe string, since, now time.Time) int {
+	closed := 0
+	if duration := models.TimeframeDuration(timeframe); duration > 0 {
+		for start := since.Truncate(duration).Add(duration); !now.Before(start.Add(duration)); start = start.Add(duration) {
+			if c.IsOpen(start) {
+				closed++
+			}
+		}
+		return closed
+	}
+
+	period := func(t time.Time) string {
+		switch timeframe {
+		case "weekly":
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		case "monthly":
+			return t.Format("2006-01")
+		}
+		return t.Format("2006-01-02")
+	}
+	start := period(since.In(c.Location))
+	for day := c.NextTradingDay(since); !now.Before(c.SessionClose(day)); day = c.NextTradingDay(day) {
+		if period(day) != start && period(c.NextTradingDay(day)) != period(day) {
+			closed++ // Last session of a period other than the one the count started in
+		}
+	}
+	return closed
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+func TestCryptoCandleCloseAlignment(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	saturday := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC) // Naive candle date
+	now := time.Date(2024, 3, 9, 16, 0, 0, 0, time.UTC)     // Sunday 01:00 in Seoul
+	candles := []models.Candle{{Date: saturday.AddDate(0, 0, -1)}, {Date: saturday}}
+
+	crypto, err := New(MarketCrypto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypto.SetLocation(seoul)
+	if !crypto.IsTradingDay(saturday) {
+		t.Fatal("crypto markets trade on Saturdays")
+	}
+	if got := crypto.ClosedCandles(candles, now); len(got) != 2 {
+		t.Errorf("exchange alignment kept %d candles, want the Saturday candle closed at midnight Seoul time", len(got))
+	}
+
+	if err := crypto.SetCandleClose(CandleCloseUTC); err != nil {
+		t.Fatal(err)
+	}
+	if got := crypto.ClosedCandles(candles, now); len(got) != 1 {
+		t.Errorf("UTC alignment kept %d candles, want the Saturday candle still forming until midnight UTC", len(got))
+	}
+}
+
+func TestSessionMarketsCannotAlignCandlesToUTC(t *testing.T) {
+	us, err := New(MarketUS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := us.SetCandleClose(CandleCloseUTC); err == nil {
+		t.Error("SetCandleClose(utc) succeeded for the US market")
+	}
+	if err := us.SetCandleClose("noon"); err == nil {
+		t.Error("SetCandleClose accepted an unknown alignment")
+	}
+}
+
+func TestCandlesClosedAfter(t *testing.T) {
+	us, err := New(MarketUS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill := time.Date(2024, 3, 6, 15, 0, 0, 0, us.Location) // Wednesday afternoon
+	monday := time.Date(2024, 3, 11, 17, 0, 0, 0, us.Location)
+	if got := us.CandlesClosedAfter("daily", fill, monday); got != 3 {
+		t.Errorf("daily candles closed by Monday evening = %d, want 3 (Thursday, Friday, Monday)", got)
+	}
+	if got := us.CandlesClosedAfter("daily", fill, monday.Add(-2*time.Hour)); got != 2 {
+		t.Errorf("daily candles closed by Monday afternoon = %d, want 2", got)
+	}
+	if got := us.CandlesClosedAfter("weekly", fill, monday); got != 0 {
+		t.Errorf("weekly candles closed by Monday = %d, want 0 (the fill week is the entry candle)", got)
+	}
+	if got := us.CandlesClosedAfter("weekly", fill, monday.AddDate(0, 0, 7)); got != 1 {
+		t.Errorf("weekly candles closed a week later = %d, want 1", got)
+	}
+}
@@ -0,0 +1,88 @@
+// Package calendar provides market trading calendars for the SAPAN strategy
+// This package knows each market's sessions and holidays so runs can skip closed days and ignore unfinished candles
+package calendar
+
+import "time"
+
+// usHolidays returns the NYSE full-day holidays for a year
+// Fixed-date holidays falling on a weekend are observed on the nearest weekday
+func usHolidays(year int) []time.Time {
+	holidays := []time.Time{
+		observed(date(year, time.January, 1)),             // New Year's Day
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		easter(year).AddDate(0, 0, -2),                    // Good Friday
+		lastWeekday(year, time.May, time.Monday),          // Memorial Day
+		observed(date(year, time.July, 4)),                // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving Day
+		observed(date(year, time.December, 25)),           // Christmas Day
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observed(date(year, time.June, 19))) // Juneteenth National Independence Day
+	}
+	return holidays
+}
+
+// bistHolidays returns Borsa Istanbul's fixed-date national holidays for a year
+// Ramadan and Sacrifice feasts follow the lunar calendar and must be configured as extra holidays
+func bistHolidays(year int) []time.Time {
+	return []time.Time{
+		date(year, time.January, 1),  // New Year's Day
+		date(year, time.April, 23),   // National Sovereignty and Children's Day
+		date(year, time.May, 1),      // Labour and Solidarity Day
+		date(year, time.May, 19),     // Commemoration of Atatürk, Youth and Sports Day
+		date(year, time.July, 15),    // Democracy and National Unity Day
+		date(year, time.August, 30),  // Victory Day
+		date(year, time.October, 29), // Republic Day
+	}
+}
+
+// date returns midnight UTC of a calendar date; only the date part is compared against holidays
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// observed moves a Saturday holiday to Friday and a Sunday holiday to Monday
+func observed(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// nthWeekday returns the n-th given weekday of a month (n starts at 1)
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	day := date(year, month, 1)
+	offset := (int(weekday) - int(day.Weekday()) + 7) % 7
+	return day.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// lastWeekday returns the last given weekday of a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	day := date(year, month+1, 1).AddDate(0, 0, -1)
+	offset := (int(day.Weekday()) - int(weekday) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// easter returns Western Easter Sunday using the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
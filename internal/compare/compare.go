@@ -0,0 +1,193 @@
+// Package compare diffs two stored scan runs
+// It reports the setups that appeared and disappeared, how the scores of the remaining setups moved, and which
+// settings differed, so week-over-week changes in the market can be told apart from changes in configuration
+package compare
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// scoreTolerance is the smallest score move reported; smaller moves are hidden by the one-decimal display anyway
+const scoreTolerance = 0.05
+
+// unset is shown for a setting one of the runs did not record
+const unset = "(unset)"
+
+// ScoreChange is a setup found by both runs whose score moved
+type ScoreChange struct {
+	Symbol string  // Stock symbol
+	Side   string  // LongSide or ShortSide
+	Before float64 // Score in the baseline run
+	After  float64 // Score in the compared run
+}
+
+// Delta returns the score move from the baseline run to the compared run
+func (c ScoreChange) Delta() float64 {
+	return c.After - c.Before
+}
+
+// SettingChange is a setting whose value differed between the runs
+type SettingChange struct {
+	Key    string // Setting key (environment variable name)
+	Before string // Value in the baseline run
+	After  string // Value in the compared run
+}
+
+// Report is the difference between a baseline run and a later (or alternative) run
+type Report struct {
+	Before    watcher.RunSummary // Baseline run
+	After     watcher.RunSummary // Compared run
+	Added     []watcher.Signal   // Setups found only by the compared run, best score first
+	Dropped   []watcher.Signal   // Setups found only by the baseline run, best score first
+	Changed   []ScoreChange      // Setups found by both whose score moved, largest move first
+	Unchanged int                // Setups found by both with the same score
+	Settings  []SettingChange    // Settings that differed, by key
+}
+
+// Runs compares the signals and settings of two runs; setups are matched on symbol and side
+func Runs(before, after watcher.RunSummary, beforeSignals, afterSignals []watcher.Signal) Report {
+	report := Report{Before: before, After: after}
+	beforeSetups, afterSetups := setups(beforeSignals), setups(afterSignals)
+
+	for key, signal := range afterSetups {
+		previous, ok := beforeSetups[key]
+		switch {
+		case !ok:
+			report.Added = append(report.Added, signal)
+		case math.Abs(signal.Score-previous.Score) >= scoreTolerance:
+			report.Changed = append(report.Changed, ScoreChange{Symbol: signal.Symbol, Side: signal.Side, Before: previous.Score, After: signal.Score})
+		default:
+			report.Unchanged++
+		}
+	}
+	for key, signal := range beforeSetups {
+		if _, ok := afterSetups[key]; !ok {
+			report.Dropped = append(report.Dropped, signal)
+		}
+	}
+	sortByScore(report.Added)
+	sortByScore(report.Dropped)
+	sort.Slice(report.Changed, func(i, j int) bool {
+		a, b := math.Abs(report.Changed[i].Delta()), math.Abs(report.Changed[j].Delta())
+		if a != b {
+			return a > b
+		}
+		return report.Changed[i].Symbol < report.Changed[j].Symbol
+	})
+
+	report.Settings = settingChanges(before.Settings, after.Settings)
+	return report
+}
+
+// setups indexes signals by symbol and side, keeping the first (newest) signal of a setup
+func setups(signals []watcher.Signal) map[string]watcher.Signal {
+	indexed := make(map[string]watcher.Signal, len(signals))
+	for _, signal := range signals {
+		key := signal.Symbol + "|" + signal.Side
+		if _, ok := indexed[key]; !ok {
+			indexed[key] = signal
+		}
+	}
+	return indexed
+}
+
+// sortByScore orders signals by descending score, then symbol
+func sortByScore(signals []watcher.Signal) {
+	sort.Slice(signals, func(i, j int) bool {
+		if signals[i].Score != signals[j].Score {
+			return signals[i].Score > signals[j].Score
+		}
+		return signals[i].Symbol < signals[j].Symbol
+	})
+}
+
+// settingChanges lists the keys whose values differ; nothing is reported when either run has no recorded settings
+func settingChanges(before, after watcher.RunSettings) []SettingChange {
+	if len(before) == 0 || len(after) == 0 {
+		return nil
+	}
+	var changes []SettingChange
+	for key, value := range before {
+		if other, ok := after[key]; !ok || other != value {
+			if !ok {
+				other = unset
+			}
+			changes = append(changes, SettingChange{Key: key, Before: value, After: other})
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, SettingChange{Key: key, Before: unset, After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// Print renders the comparison: run totals, then tables of added, dropped, and rescored setups and changed settings
+func (r Report) Print(w io.Writer, palette *output.Palette) {
+	fmt.Fprintln(w, palette.Bold(fmt.Sprintf("Run Comparison: %s → %s", runLabel(r.Before), runLabel(r.After))))
+	fmt.Fprintf(w, "Setups: %d → %d (long %d → %d, short %d → %d) | processed: %d → %d | errors: %d → %d\n",
+		r.Before.Valid, r.After.Valid, r.Before.LongCount, r.After.LongCount, r.Before.ShortCount, r.After.ShortCount,
+		r.Before.Total, r.After.Total, r.Before.Errors, r.After.Errors)
+	fmt.Fprintf(w, "%d added, %d dropped, %d rescored, %d unchanged\n", len(r.Added), len(r.Dropped), len(r.Changed), r.Unchanged)
+
+	printSignals(w, palette, "Added", r.Added, palette.Green)
+	printSignals(w, palette, "Dropped", r.Dropped, palette.Red)
+	if len(r.Changed) > 0 {
+		table := output.NewTable(palette, "Symbol", "Side", "Before", "After", "Change")
+		for _, change := range r.Changed {
+			color := palette.Green
+			if change.Delta() < 0 {
+				color = palette.Red
+			}
+			table.AddRow(color, change.Symbol, change.Side, formatScore(change.Before), formatScore(change.After),
+				fmt.Sprintf("%+.1f", change.Delta()))
+		}
+		fmt.Fprintf(w, "\n%s\n", palette.Bold("Score changes:"))
+		table.Render(w)
+	}
+
+	switch {
+	case len(r.Before.Settings) == 0 || len(r.After.Settings) == 0:
+		fmt.Fprintln(w, palette.Yellow("\nSettings were not recorded for both runs, so parameter differences are unknown."))
+	case len(r.Settings) == 0:
+		fmt.Fprintln(w, "\nBoth runs used the same settings.")
+	default:
+		table := output.NewTable(palette, "Setting", "Before", "After")
+		for _, change := range r.Settings {
+			table.AddRow(palette.Yellow, change.Key, change.Before, change.After)
+		}
+		fmt.Fprintf(w, "\n%s\n", palette.Bold("Parameter differences:"))
+		table.Render(w)
+	}
+}
+
+// printSignals renders one table of setups, skipped when there are none
+func printSignals(w io.Writer, palette *output.Palette, title string, signals []watcher.Signal, color func(string) string) {
+	if len(signals) == 0 {
+		return
+	}
+	table := output.NewTable(palette, "Symbol", "Side", "Pattern", "Score", "Sector")
+	for _, signal := range signals {
+		table.AddRow(color, signal.Symbol, signal.Side, signal.Pattern, formatScore(signal.Score), signal.Sector)
+	}
+	fmt.Fprintf(w, "\n%s\n", palette.Bold(title+" setups:"))
+	table.Render(w)
+}
+
+// runLabel identifies a run by its identifier and start time
+func runLabel(run watcher.RunSummary) string {
+	return "#" + strconv.FormatInt(run.ID, 10) + " (" + run.StartedAt.Format("2006-01-02 15:04") + ")"
+}
+
+// formatScore renders a score with one decimal
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 1, 64)
+}
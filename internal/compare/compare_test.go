@@ -0,0 +1,80 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func TestRunsDiffsSetupsScoresAndSettings(t *testing.T) {
+	before := watcher.RunSummary{ID: 1, StartedAt: time.Date(2024, 6, 7, 8, 0, 0, 0, time.UTC),
+		Settings: watcher.RunSettings{"WORKERS": "5", "EMA_PERIODS": "20,50,100,200", "MIN_SCORE": "0"}}
+	after := watcher.RunSummary{ID: 2, StartedAt: time.Date(2024, 6, 14, 8, 0, 0, 0, time.UTC),
+		Settings: watcher.RunSettings{"WORKERS": "5", "EMA_PERIODS": "8,21,55,89", "RETRY_ATTEMPTS": "3"}}
+	beforeSignals := []watcher.Signal{
+		{Symbol: "AAPL", Side: watcher.LongSide, Score: 70},
+		{Symbol: "MSFT", Side: watcher.LongSide, Score: 80},
+		{Symbol: "TSLA", Side: watcher.ShortSide, Score: 60},
+		{Symbol: "NVDA", Side: watcher.LongSide, Score: 65},
+	}
+	afterSignals := []watcher.Signal{
+		{Symbol: "AAPL", Side: watcher.LongSide, Score: 75},
+		{Symbol: "MSFT", Side: watcher.LongSide, Score: 60},
+		{Symbol: "TSLA", Side: watcher.LongSide, Score: 55}, // Same symbol on the other side is a different setup
+		{Symbol: "NVDA", Side: watcher.LongSide, Score: 65.01},
+	}
+
+	report := Runs(before, after, beforeSignals, afterSignals)
+	if len(report.Added) != 1 || report.Added[0].Symbol != "TSLA" || report.Added[0].Side != watcher.LongSide {
+		t.Errorf("Added = %+v, want the TSLA Long setup", report.Added)
+	}
+	if len(report.Dropped) != 1 || report.Dropped[0].Symbol != "TSLA" || report.Dropped[0].Side != watcher.ShortSide {
+		t.Errorf("Dropped = %+v, want the TSLA Short setup", report.Dropped)
+	}
+	if len(report.Changed) != 2 || report.Changed[0].Symbol != "MSFT" || report.Changed[0].Delta() != -20 ||
+		report.Changed[1].Symbol != "AAPL" {
+		t.Errorf("Changed = %+v, want MSFT (-20) before AAPL (+5)", report.Changed)
+	}
+	if report.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want NVDA's negligible move counted as unchanged", report.Unchanged)
+	}
+	want := []SettingChange{
+		{Key: "EMA_PERIODS", Before: "20,50,100,200", After: "8,21,55,89"},
+		{Key: "MIN_SCORE", Before: "0", After: unset},
+		{Key: "RETRY_ATTEMPTS", Before: unset, After: "3"},
+	}
+	if len(report.Settings) != len(want) {
+		t.Fatalf("Settings = %+v, want %+v", report.Settings, want)
+	}
+	for i := range want {
+		if report.Settings[i] != want[i] {
+			t.Errorf("Settings[%d] = %+v, want %+v", i, report.Settings[i], want[i])
+		}
+	}
+
+	var buffer bytes.Buffer
+	report.Print(&buffer, output.NewPalette(false))
+	for _, text := range []string{"#1 (2024-06-07 08:00) → #2 (2024-06-14 08:00)", "1 added, 1 dropped, 2 rescored, 1 unchanged", "-20.0", "EMA_PERIODS"} {
+		if !strings.Contains(buffer.String(), text) {
+			t.Errorf("report does not contain %q:\n%s", text, buffer.String())
+		}
+	}
+}
+
+func TestRunsWithoutRecordedSettings(t *testing.T) {
+	before := watcher.RunSummary{ID: 1} // Recorded before runs stored their settings
+	after := watcher.RunSummary{ID: 2, Settings: watcher.RunSettings{"WORKERS": "5"}}
+	report := Runs(before, after, nil, nil)
+	if len(report.Settings) != 0 {
+		t.Errorf("Settings = %+v, want no differences when a run has none recorded", report.Settings)
+	}
+	var buffer bytes.Buffer
+	report.Print(&buffer, output.NewPalette(false))
+	if !strings.Contains(buffer.String(), "Settings were not recorded") {
+		t.Errorf("report does not flag the missing settings:\n%s", buffer.String())
+	}
+}
@@ -0,0 +1,394 @@
+// Package config provides configuration management for the SAPAN strategy application
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configFileEnv names the environment variable that points at an optional JSON configuration file
+const configFileEnv = "SAPAN_CONFIG_FILE"
+
+// flagBinding maps a command-line flag onto the setting key it overrides
+// Setting keys are the environment variable names, so flags, env, and file share one namespace
+// Bindings with a preset are boolean switches that set the key to the preset when given
+type flagBinding struct {
+	name   string // Flag name without leading dashes
+	key    string // Setting key (environment variable name)
+	usage  string // Help text shown by -h
+	preset string // Value assigned by a boolean switch (empty for value flags)
+}
+
+// flagBindings lists every setting that can be overridden from the command line
+var flagBindings = []flagBinding{
+	{"api-url", "ALPHA_VANTAGE_API_URL", "Alpha Vantage API base URL", ""},
+	{"workers", "WORKER_COUNT", "number of concurrent workers", ""},
+	{"cpu-workers", "CPU_WORKERS", "goroutines analyzing candles in backtests and replays (0 uses every core)", ""},
+	{"request-delay", "REQUEST_DELAY_SECONDS", "delay between API requests per worker in seconds", ""},
+	{"http-timeout", "HTTP_TIMEOUT_SECONDS", "timeout of a single provider HTTP request in seconds (0 for none)", ""},
+	{"fetch-timeout", "FETCH_TIMEOUT_SECONDS", "deadline of one symbol's candle fetch in seconds, paging included (0 for none)", ""},
+	{"http-max-idle-conns", "HTTP_MAX_IDLE_CONNS", "keep-alive connections pooled per provider host and shared by every worker", ""},
+	{"http-proxy", "HTTP_PROXY_URL", "proxy provider requests go through (e.g. http://proxy:3128)", ""},
+	{"retry-attempts", "RETRY_ATTEMPTS", "attempts per provider request on timeouts, 5xx responses, and rate limits (1 disables retries)", ""},
+	{"retry-delay", "RETRY_BASE_DELAY_SECONDS", "wait before the first retry in seconds, doubled with jitter for every further retry", ""},
+	{"stocks-file", "STOCKS_FILE", "comma-separated stock list files or globs (e.g. lists/*.json)", ""},
+	{"output-size", "OUTPUT_SIZE", "candles of history to fetch: a count, auto, or per timeframe (e.g. 300,60min=1000)", ""},
+	{"timeframe", "TIMEFRAME", "candle timeframe (daily, weekly, monthly, 1min, 5min, 15min, 30min, 60min)", ""},
+	{"provider", "PROVIDER", "market data provider (alphavantage, binance, csv)", ""},
+	{"adjusted-series", "ADJUSTED_SERIES", "fetch the split- and dividend-adjusted series", "true"},
+	{"adjusted-prices", "ADJUSTED_PRICES", "compute indicators on split- and dividend-adjusted prices", "true"},
+	{"candle-checks", "CANDLE_CHECKS", "what candle anomalies do (warn, skip, off)", ""},
+	{"csv-dir", "CSV_DIR", "directory of per-symbol candle CSV files read by the csv provider", ""},
+	{"watchlist-file", "WATCHLIST_FILE", "JSON file the watch list is persisted to", ""},
+	{"csv-output", "WATCHLIST_CSV_FILE", "CSV file the watch list is exported to", ""},
+	{"journal", "JOURNAL_FILE", "trade journal file taken signals are appended to", ""},
+	{"journal-format", "JOURNAL_FORMAT", "trade journal format (json, tradervue, edgewonk)", ""},
+	{"output", "RESULTS_FILE", "write the full run result as JSON to this file", ""},
+	{"reports-dir", "REPORTS_DIR", "directory for per-run HTML reports", ""},
+	{"candle-dir", "CANDLE_DIR", "directory fetched candles are archived to for replays", ""},
+	{"enrich-metadata", "ENRICH_METADATA", "look up the exchange, currency, and country of stocks with signals", "true"},
+	{"signal-db", "SIGNAL_DB_PATH", "SQLite signal database path", ""},
+	{"stream-notifications", "NOTIFY_STREAMING", "deliver signals to notifiers as soon as they are detected", "true"},
+	{"top", "TOP_SIGNALS", "number of best setups to highlight", ""},
+	{"top-by", "TOP_SIGNALS_BY", "ranking for highlighted setups (score, volume, rr)", ""},
+	{"include-sectors", "INCLUDE_SECTORS", "comma-separated sectors to analyze", ""},
+	{"exclude-sectors", "EXCLUDE_SECTORS", "comma-separated sectors to skip", ""},
+	{"include-symbols", "INCLUDE_SYMBOLS", "comma-separated symbols to analyze", ""},
+	{"exclude-symbols", "EXCLUDE_SYMBOLS", "comma-separated symbols to skip", ""},
+	{"symbol-pattern", "SYMBOL_PATTERN", "regular expression tickers must match", ""},
+	{"limit", "LIMIT", "scan only the first N stocks of the filtered list", ""},
+	{"sample", "SAMPLE", "scan N stocks picked at random from the filtered list", ""},
+	{"seed", "SAMPLE_SEED", "seed of --sample, to repeat the same sample (0 picks a new one)", ""},
+	{"blacklist-file", "BLACKLIST_FILE", "JSON file of symbols every scan skips", ""},
+	{"market", "MARKET", "market calendar (us, bist, crypto)", ""},
+	{"markets", "MARKETS", "comma-separated market sections scanned one after another (e.g. us,crypto)", ""},
+	{"section", "SECTION", "run the command against one market section of MARKETS (e.g. crypto)", ""},
+	{"market-timezone", "MARKET_TIMEZONE", "exchange timezone overriding the market's own (e.g. Asia/Seoul)", ""},
+	{"candle-close", "CANDLE_CLOSE", "when daily crypto candles close (exchange, utc)", ""},
+	{"quiet", "OUTPUT_MODE", "print only the run summary", "quiet"},
+	{"verbose", "OUTPUT_MODE", "print per-rule detail for every symbol", "verbose"},
+	{"signals-only", "OUTPUT_MODE", "print only detected signals", "signals-only"},
+	{"explain", "EXPLAIN", "measure every rule of scanned symbols and show how far failed rules were from passing", "true"},
+	{"explain-symbols", "EXPLAIN_SYMBOLS", "comma-separated symbols to explain (implies --explain)", ""},
+	{"both-sides", "BOTH_SIDES", "validate and report Long and Short for every symbol, with scores", "true"},
+	{"desktop-notify", "DESKTOP_NOTIFICATIONS", "show native desktop notifications for new signals", "true"},
+	{"screen", "SCREEN_ENABLED", "screen the universe with bulk quotes before fetching candles", "true"},
+	{"screen-top", "SCREEN_TOP", "keep only the N best-ranked stocks after the screen", ""},
+	{"ml-scoring", "ML_SCORING", "attach predicted success probabilities to new signals", "true"},
+	{"correlation-threshold", "CORRELATION_THRESHOLD", "flag or trim signals whose returns correlate at least this much (0 disables)", ""},
+	{"cross-check", "CROSS_CHECK_PROVIDER", "second provider the candles of signals are cross-checked against (alphavantage, binance, csv)", ""},
+	{"kafka-brokers", "KAFKA_BROKERS", "comma-separated Kafka brokers signals and run summaries are published to", ""},
+	{"mqtt-broker", "MQTT_BROKER", "MQTT broker URL signals are published to (e.g. tcp://localhost:1883)", ""},
+	{"alert-rules", "ALERT_RULES_FILE", "JSON file of alert rules filtering which signals notifiers receive", ""},
+	{"no-color", "COLOR", "disable colored terminal output", "never"},
+	{"signal-exit-code", "SIGNAL_EXIT_CODE", "exit with this code when signals are found (0 disables)", ""},
+	{"broker", "BROKER", "broker orders are submitted to (alpaca, paper)", ""},
+	{"long-patterns", "LONG_PATTERNS", "comma-separated patterns that validate Long setups (2-candlestick, pinbar, none)", ""},
+	{"short-patterns", "SHORT_PATTERNS", "comma-separated patterns that validate Short setups (2-candlestick, pinbar, none)", ""},
+	{"ema-reference", "EMA_REFERENCE", "EMA reversal tails must pierce (extreme, nearest)", ""},
+	{"time-stop", "TIME_STOP_BARS", "candles after the entry before a trade exits at the close if neither stop nor target was hit (0 disables)", ""},
+	{"ema-periods", "EMA_PERIODS", "comma-separated EMA stack, fastest first, for the trend order and pattern support/resistance (e.g. 8,21,55,89)", ""},
+	{"macd-max-run", "MACD_MAX_RUN", "candles the opposing MACD market may have lasted for a setup", ""},
+	{"stoch-rsi", "STOCH_RSI_LENGTHS", "Stochastic RSI lengths as RSI,stochastic,K,D (e.g. 14,14,3,3)", ""},
+	{"stoch-rsi-mode", "STOCH_RSI_MODE", "Stochastic RSI flat-range handling (standard, tradingview)", ""},
+	{"stoch-oversold", "STOCH_RSI_OVERSOLD", "Stochastic RSI %K level Long setups must be below", ""},
+	{"stoch-overbought", "STOCH_RSI_OVERBOUGHT", "Stochastic RSI %K level Short setups must be above", ""},
+	{"stoch-long-lookback", "STOCH_RSI_LONG_LOOKBACK", "candles back the Stochastic RSI crossover of a Long setup may be", ""},
+	{"stoch-short-lookback", "STOCH_RSI_SHORT_LOOKBACK", "candles back the Stochastic RSI crossover of a Short setup may be", ""},
+	{"order-block-lookback", "ORDER_BLOCK_LOOKBACK", "candles searched back for order block zones (0 disables them)", ""},
+	{"order-block-impulse", "ORDER_BLOCK_IMPULSE_ATR", "smallest move away from an order block, in ATRs, that creates a zone", ""},
+	{"require-zone", "REQUIRE_ZONE_CONFLUENCE", "only accept setups whose reversal touched an order block holding the EMA", "true"},
+	{"pinbar-body-atr", "PINBAR_BODY_ATR", "largest pinbar body in ATRs (0 uses PINBAR_BODY_RATIO)", ""},
+	{"pinbar-wick-atr", "PINBAR_WICK_ATR", "smallest pinbar wick in ATRs (0 uses PINBAR_WICK_RATIO)", ""},
+	{"pierce-atr", "PIERCE_DEPTH_ATR", "smallest depth reversal tails must reach past the EMA, in ATRs", ""},
+	{"confirmation-close", "CONFIRMATION_CLOSE_PERCENT", "share of the reversal range the confirmation candle must close beyond (100 = beyond the reversal high or low)", ""},
+	{"risk-percent", "BACKTEST_RISK_PERCENT", "account percentage risked per backtested trade", ""},
+	{"entry-window", "BACKTEST_ENTRY_WINDOW", "candles a backtested entry order stays active", ""},
+	{"benchmark", "BENCHMARK_SYMBOL", "symbol results are compared against with buy-and-hold (none disables)", ""},
+	{"schedule", "SCHEDULE", "daemon schedules: cron expressions or @close+OFFSET, separated by semicolons", ""},
+	{"status-addr", "STATUS_ADDR", "address the daemon serves /status on (e.g. :8080)", ""},
+	{"monitor-interval", "MONITOR_INTERVAL_SECONDS", "seconds between the quote polls of the price monitor", ""},
+	{"addr", "SERVE_ADDR", "address the API server listens on", ""},
+	{"grpc-addr", "GRPC_ADDR", "address the gRPC API listens on (e.g. :9090)", ""},
+	{"queue-in-flight", "QUEUE_IN_FLIGHT", "fetch jobs a distributed scan keeps outstanding", ""},
+	{"worker-name", "WORKER_NAME", "name a distributed worker reports", ""},
+	{"display-timezone", "DISPLAY_TIMEZONE", "IANA timezone for printed timestamps (e.g. Europe/Istanbul)", ""},
+}
+
+// Setting describes one resolved configuration value and where it came from
+// Settings back the `config show` command so users can see why a value is in effect
+type Setting struct {
+	Key    string // Setting key (environment variable name)
+	Value  string // Resolved value as text (secrets are masked)
+	Source string // Origin of the value: flag, env, config file, secret, or default
+}
+
+// loader resolves setting values with the precedence flags > environment > config file > defaults
+// A loader for a market section first looks for section-prefixed keys (e.g. CRYPTO_PROVIDER), which beat every global value
+type loader struct {
+	flags     map[string]string // Values of flags set on the command line, keyed by setting key
+	file      map[string]string // Values read from the configuration file, keyed by setting key
+	filePath  string            // Path of the configuration file (empty when none was given)
+	flagNames map[string]string // Flag names keyed by setting key, used to describe sources
+	settings  []Setting         // Resolved settings in the order they were loaded
+	section   string            // Upper-case market section whose prefixed keys take precedence (empty for none)
+}
+
+// newLoader parses command-line arguments and the optional configuration file
+// The configuration file is taken from --config, falling back to the SAPAN_CONFIG_FILE environment variable
+func newLoader(args []string) (*loader, error) {
+	fs := flag.NewFlagSet("sapan", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a JSON configuration file (keys are environment variable names)")
+	values := make(map[string]*string, len(flagBindings))
+	switches := make(map[string]*bool)
+	for _, binding := range flagBindings {
+		usage := fmt.Sprintf("%s (overrides %s)", binding.usage, binding.key)
+		if binding.preset != "" {
+			switches[binding.name] = fs.Bool(binding.name, false, usage)
+			continue
+		}
+		values[binding.name] = fs.String(binding.name, "", usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	l := &loader{flags: make(map[string]string), file: make(map[string]string), flagNames: make(map[string]string)}
+
+	// Only flags explicitly set on the command line take part in resolution
+	bindings := make(map[string]flagBinding, len(flagBindings))
+	for _, binding := range flagBindings {
+		bindings[binding.name] = binding
+		l.flagNames[binding.key] = binding.name
+	}
+	fs.Visit(func(f *flag.Flag) {
+		binding, ok := bindings[f.Name]
+		switch {
+		case !ok:
+		case binding.preset != "":
+			if *switches[f.Name] {
+				l.flags[binding.key] = binding.preset
+				l.flagNames[binding.key] = binding.name // Report the switch that was actually given
+			}
+		default:
+			l.flags[binding.key] = *values[f.Name]
+		}
+	})
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv(configFileEnv)
+	}
+	if path != "" {
+		fileValues, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.file = fileValues
+		l.filePath = path
+	}
+
+	return l, nil
+}
+
+// readConfigFile reads a flat JSON object of setting keys to scalar values
+// Numbers and booleans are accepted as-is and converted to their string form
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep integers exact instead of converting through float64
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case []interface{}:
+			parts := make([]string, len(v))
+			for i, item := range v {
+				parts[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(parts, ",") // Lists become comma-separated values
+		case nil:
+			// Explicit null leaves the setting unset
+		default:
+			return nil, fmt.Errorf("unsupported value for %s in config file %s", key, path)
+		}
+	}
+	return values, nil
+}
+
+// lookup returns the highest-precedence value for a setting key together with its source
+func (l *loader) lookup(key string) (string, string, bool) {
+	if value, source, ok := l.sectionLookup(key); ok {
+		return value, source, true
+	}
+	if value, ok := l.flags[key]; ok {
+		return value, "flag --" + l.flagNames[key], true
+	}
+	if value := os.Getenv(key); value != "" {
+		return value, "env " + key, true
+	}
+	if value, ok := l.file[key]; ok && value != "" {
+		return value, "file " + l.filePath, true
+	}
+	return "", "", false
+}
+
+// sectionLookup returns the value of the section-prefixed key from the environment or the config file
+func (l *loader) sectionLookup(key string) (string, string, bool) {
+	if l.section == "" {
+		return "", "", false
+	}
+	sectionKey := l.section + "_" + key
+	if value := os.Getenv(sectionKey); value != "" {
+		return value, "env " + sectionKey, true
+	}
+	if value, ok := l.file[sectionKey]; ok && value != "" {
+		return value, "file " + l.filePath, true
+	}
+	return "", "", false
+}
+
+// resolve looks up a setting, records where its value came from, and reports whether it was set
+func (l *loader) resolve(key, def string) (string, bool) {
+	value, source, ok := l.lookup(key)
+	if !ok {
+		l.record(key, def, "default")
+		return def, false
+	}
+	l.record(key, value, source)
+	return value, true
+}
+
+// record remembers a resolved setting, replacing an earlier record for the same key
+func (l *loader) record(key, value, source string) {
+	for i := range l.settings {
+		if l.settings[i].Key == key {
+			l.settings[i] = Setting{Key: key, Value: value, Source: source}
+			return
+		}
+	}
+	l.settings = append(l.settings, Setting{Key: key, Value: value, Source: source})
+}
+
+// sectionValue resolves a setting from the section-prefixed key only, falling back to the default
+// It keeps section-specific settings such as a section's market from being taken over by the global key
+func (l *loader) sectionValue(key, def string) string {
+	value, source, ok := l.sectionLookup(key)
+	if !ok {
+		l.record(key, def, "section "+strings.ToLower(l.section))
+		return def
+	}
+	l.record(key, value, source)
+	return value
+}
+
+// sectionPath resolves a file setting and, for a section, inserts the section name before the extension
+// so sections keep separate files; paths set through the section-prefixed key and empty paths are kept as-is
+func (l *loader) sectionPath(key, def string) string {
+	path, _ := l.resolve(key, def)
+	if l.section == "" || path == "" {
+		return path
+	}
+	if _, _, ok := l.sectionLookup(key); ok {
+		return path
+	}
+	source := "default"
+	for _, setting := range l.settings {
+		if setting.Key == key {
+			source = setting.Source
+		}
+	}
+	ext := filepath.Ext(path)
+	path = strings.TrimSuffix(path, ext) + "." + strings.ToLower(l.section) + ext
+	l.record(key, path, source+", per section")
+	return path
+}
+
+// stringValue resolves a string setting, falling back to the default
+func (l *loader) stringValue(key, def string) string {
+	value, _ := l.resolve(key, def)
+	return value
+}
+
+// intValue resolves an integer setting, falling back to the default
+func (l *loader) intValue(key string, def int) (int, error) {
+	value, ok := l.resolve(key, strconv.Itoa(def))
+	if !ok {
+		return def, nil
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %v", key, err)
+	}
+	return parsed, nil
+}
+
+// floatValue resolves a decimal setting, falling back to the default
+func (l *loader) floatValue(key string, def float64) (float64, error) {
+	value, ok := l.resolve(key, strconv.FormatFloat(def, 'f', -1, 64))
+	if !ok {
+		return def, nil
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %v", key, err)
+	}
+	return parsed, nil
+}
+
+// boolValue resolves a boolean setting, falling back to the default
+func (l *loader) boolValue(key string, def bool) (bool, error) {
+	value, ok := l.resolve(key, strconv.FormatBool(def))
+	if !ok {
+		return def, nil
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value: %v", key, err)
+	}
+	return parsed, nil
+}
+
+// listValue resolves a comma-separated list setting, dropping blank items
+func (l *loader) listValue(key string) []string {
+	var items []string
+	for _, item := range strings.Split(l.stringValue(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// choiceValue resolves a string setting restricted to a fixed set of values
+func (l *loader) choiceValue(key, def string, choices ...string) (string, error) {
+	value := l.stringValue(key, def)
+	for _, choice := range choices {
+		if value == choice {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %s value: %q (expected one of %s)", key, value, strings.Join(choices, ", "))
+}
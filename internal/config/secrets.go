@@ -0,0 +1,117 @@
+// Package config provides configuration management for the SAPAN strategy application
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// secretsCommandTimeout bounds how long an external secrets command may run
+const secretsCommandTimeout = 10 * time.Second
+
+// CommandSecretsBackend resolves secrets by running a command and reading its standard output
+// The command may contain a {name} placeholder for the secret name; otherwise the name is appended as
+// the last argument. This works with pass, vault, 1Password CLI, cloud CLIs, or any wrapper script.
+type CommandSecretsBackend struct {
+	command []string // Command and arguments, split on whitespace
+}
+
+// NewCommandSecretsBackend creates a secrets backend from a command line such as "pass show sapan/{name}"
+func NewCommandSecretsBackend(commandLine string) *CommandSecretsBackend {
+	return &CommandSecretsBackend{command: strings.Fields(commandLine)}
+}
+
+// Secret runs the configured command for the given secret name and returns its trimmed output
+// The command runs without a shell, so the secret never passes through shell history or expansion
+func (b *CommandSecretsBackend) Secret(name string) (string, error) {
+	if len(b.command) == 0 {
+		return "", nil
+	}
+
+	args := make([]string, 0, len(b.command)+1)
+	replaced := false
+	for _, arg := range b.command[1:] {
+		if strings.Contains(arg, "{name}") {
+			arg = strings.ReplaceAll(arg, "{name}", name)
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+	if !replaced {
+		args = append(args, name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, b.command[0], args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("secrets command failed for %s: %v (%s)", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// secretValue resolves a secret setting without ever accepting it as a command-line flag
+// Resolution order: KEY_FILE env, KEY env, KEY_FILE or KEY in the config file, then the external secrets backend
+// Only a masked form of the value is recorded for `config show`
+func (l *loader) secretValue(key string) (string, error) {
+	value, source, err := l.lookupSecret(key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		l.record(key, "", "unset")
+	} else {
+		l.record(key, maskSecret(value), source)
+	}
+	return value, nil
+}
+
+// lookupSecret returns the highest-precedence secret value for a key together with its source
+func (l *loader) lookupSecret(key string) (string, string, error) {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		value, err := readSecretFile(key, path)
+		return value, "env " + key + "_FILE (" + path + ")", err
+	}
+	if value := os.Getenv(key); value != "" {
+		return value, "env " + key, nil
+	}
+	if path := l.file[key+"_FILE"]; path != "" {
+		value, err := readSecretFile(key, path)
+		return value, "file " + l.filePath + " " + key + "_FILE (" + path + ")", err
+	}
+	if value := l.file[key]; value != "" {
+		return value, "file " + l.filePath, nil
+	}
+
+	if commandLine := l.stringValue("SECRETS_COMMAND", ""); commandLine != "" {
+		value, err := NewCommandSecretsBackend(commandLine).Secret(key)
+		return value, "secrets command", err
+	}
+	return "", "", nil
+}
+
+// maskSecret hides a secret while keeping its last characters so users can tell keys apart
+func maskSecret(value string) string {
+	if len(value) < 12 {
+		return "********"
+	}
+	return "********" + value[len(value)-4:]
+}
+
+// readSecretFile reads a secret from a file such as a Docker secret, trimming the trailing newline
+func readSecretFile(key, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %v", key, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -3,89 +3,1125 @@ package config
 
 import (
 	"fmt"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 )
 
 // Config holds all configuration parameters for the SAPAN strategy application
 // This structure centralizes all configurable settings to make the application easily tunable
 type Config struct {
-	APIKey       string        // Alpha Vantage API key for fetching stock data
-	APIURL       string        // Alpha Vantage API base URL
-	WorkerCount  int           // Number of concurrent workers for processing stocks
-	RequestDelay time.Duration // Delay between API requests per worker (to respect rate limits)
-	StocksFile   string        // Path to the JSON file containing stock symbols to analyze
-	OutputSize   int           // Number of days of historical data to fetch from API
+	APIKey                    string         // Alpha Vantage API key for fetching stock data
+	APIURL                    string         // Alpha Vantage API base URL
+	BinanceAPIURL             string         // Binance REST API base URL used by the binance provider
+	CSVDir                    string         // Directory of per-symbol candle CSV files read by the csv provider
+	CSVFilePattern            string         // File name of a symbol's CSV file with {symbol} and {timeframe} placeholders
+	CSVColumns                []string       // Header names of the date, open, high, low, close, and volume columns
+	CSVDateFormat             string         // Go time layout of the date column, or unix / unixms for epoch timestamps
+	CSVDelimiter              rune           // Field delimiter of the CSV files
+	WorkerCount               int            // Number of concurrent workers for processing stocks
+	CPUWorkers                int            // Goroutines analyzing candles in backtests and replays (0 uses every core)
+	RequestDelay              time.Duration  // Delay between API requests per worker (to respect rate limits)
+	HTTPTimeout               time.Duration  // Timeout of a single provider HTTP request (0 for none)
+	FetchTimeout              time.Duration  // Deadline of one symbol's candle fetch, so a hung request cannot stall a worker (0 for none)
+	HTTPMaxIdleConns          int            // Keep-alive connections pooled per provider host, shared by every worker
+	HTTPProxyURL              string         // Proxy provider requests go through (empty uses HTTP_PROXY and HTTPS_PROXY)
+	RetryAttempts             int            // Attempts per provider request before a transient failure is reported (1 disables retries)
+	RetryBaseDelay            time.Duration  // Wait before the first retry, doubled with jitter for every further retry
+	StocksFile                string         // Comma-separated JSON files or glob patterns listing the stocks to analyze
+	OutputSize                int            // Number of candles of history to fetch per stock for the timeframe (0 sizes it to the strategy's indicators)
+	WatchListFile             string         // Path to the JSON file used to persist the watch list between runs
+	SignalDBPath              string         // Path to the SQLite signal database (empty disables the database)
+	NotifyExisting            bool           // Announce signals already present in the previous watch list
+	WatchListCSVFile          string         // Path of the CSV export written after each run (empty disables the export)
+	JournalFile               string         // Trade journal taken signals are appended to (empty disables the journal)
+	JournalFormat             string         // Trade journal format (json, tradervue, or edgewonk)
+	OutcomeTrackingDays       int            // Days to wait after a signal before recording its outcome (0 disables tracking)
+	TopSignals                int            // Number of best setups to highlight after a run (0 disables the highlight)
+	TopSignalsBy              string         // Ranking criterion for the highlight (score, volume, or rr)
+	Provider                  string         // Market data provider used to fetch candles
+	Timeframe                 string         // Candle timeframe requested from the provider (daily, weekly, monthly, or an intraday interval)
+	AdjustedSeries            bool           // Fetch the split- and dividend-adjusted series, recording adjusted close, splits, and dividends
+	AdjustedPrices            bool           // Compute indicators on adjusted prices instead of as-traded prices (implies AdjustedSeries)
+	IncludeSectors            []string       // Only analyze stocks in these sectors (empty includes all)
+	ExcludeSectors            []string       // Skip stocks in these sectors
+	IncludeSymbols            []string       // Only analyze these symbols (empty includes all)
+	BlacklistFile             string         // JSON file of symbols every scan skips
+	BlacklistAutoRejections   int            // Consecutive provider rejections that blacklist a symbol (0 disables)
+	ExcludeSymbols            []string       // Skip these symbols
+	SymbolPattern             string         // Regular expression tickers must match (empty matches all)
+	Limit                     int            // Scan only the first N stocks of the filtered list (0 scans all)
+	Sample                    int            // Scan N stocks picked at random from the filtered list (0 scans all)
+	SampleSeed                int64          // Seed of the random sample (0 picks a new seed every run)
+	Market                    string         // Market calendar used for sessions and holidays (us, bist, or crypto)
+	Markets                   []string       // Market sections scanned one after another by a multi-market run (empty scans one market)
+	Section                   string         // Market section this configuration was loaded for (empty outside multi-market runs)
+	MarketHolidays            []string       // Additional market holidays as YYYY-MM-DD dates
+	MarketLocation            *time.Location // Exchange timezone overriding the market's own (nil keeps it)
+	CandleClose               string         // When daily candles close: exchange (with the exchange day) or utc (24/7 markets only)
+	SkipClosedDays            bool           // Skip the scan on weekends and market holidays
+	DisplayLocation           *time.Location // Timezone used to render watch list and report timestamps
+	SMTPHost                  string         // SMTP server host for the end-of-run email (empty disables email)
+	SMTPPort                  int            // SMTP server port (465 uses implicit TLS)
+	SMTPUsername              string         // SMTP user name (empty disables authentication)
+	SMTPPassword              string         // SMTP password
+	EmailFrom                 string         // Sender address of the end-of-run email
+	EmailTo                   []string       // Recipients of the end-of-run email
+	APIDailyQuota             int            // Daily API request quota enforced per provider (0 only counts requests)
+	QuotaFile                 string         // JSON file the API requests made per provider per day are counted in
+	WebhookURLs               []string       // URLs receiving signal and run-completion webhooks (empty disables webhooks)
+	WebhookSecret             string         // Shared secret used to sign webhook payloads
+	WebhookMaxRetries         int            // Retries for failed webhook deliveries
+	WebhookTimeout            time.Duration  // Timeout of a single webhook delivery attempt
+	NotifyInterval            time.Duration  // Minimum time between deliveries of a single notifier
+	NotifyMaxRetries          int            // Retries for failed notifier deliveries
+	NotifyStreaming           bool           // Deliver signals to notifiers as soon as they are detected instead of at the end of the run
+	ResultsFile               string         // Path of the JSON run result written after each run (empty disables it)
+	ReportsDir                string         // Directory receiving a self-contained HTML report per run (empty disables reports)
+	CandleDir                 string         // Directory fetched candles are archived to for replays (empty disables the archive)
+	CandleChecks              string         // What candle anomalies do: warn, skip (stocks with impossible bars fail), or off
+	CandleMaxGap              float64        // Largest open-to-previous-close move, as a fraction, before a gap is flagged (0 disables)
+	CandleCheckWindow         int            // Newest candles checked for anomalies (0 checks every candle)
+	OutputMode                output.Mode    // Terminal output mode (normal, quiet, verbose, or signals-only)
+	Explain                   bool           // Measure every rule of scanned symbols and report how far failed rules were from passing
+	ExplainSymbols            []string       // Only explain these symbols (setting any enables explain mode)
+	BothSides                 bool           // Validate Short even when Long is valid and report both sides of every symbol
+	Color                     string         // Terminal colors: auto (only on a terminal), always, or never
+	NtfyServer                string         // ntfy server base URL
+	NtfyTopic                 string         // ntfy topic receiving push notifications (empty disables ntfy)
+	NtfyToken                 string         // ntfy access token for protected topics
+	PushoverToken             string         // Pushover application token (empty disables Pushover)
+	PushoverUser              string         // Pushover user or group key
+	TwilioAccountSID          string         // Twilio account SID (empty disables SMS)
+	TwilioAuthToken           string         // Twilio auth token
+	SMSFrom                   string         // Twilio sender number
+	SMSTo                     []string       // SMS recipient numbers
+	SMSMinScore               int            // Minimum setup score that triggers an SMS
+	SignalExitCode            int            // Exit code returned when a scan finds signals (0 disables)
+	DesktopNotifications      bool           // Show native desktop notifications for new signals
+	KafkaBrokers              []string       // Kafka bootstrap brokers (empty disables Kafka publishing)
+	KafkaSignalTopic          string         // Kafka topic receiving signal records
+	KafkaRunTopic             string         // Kafka topic receiving run records (empty skips run summaries)
+	KafkaUsername             string         // SASL/PLAIN username (empty disables authentication)
+	KafkaPassword             string         // SASL/PLAIN password
+	KafkaTLS                  bool           // Connect to the Kafka brokers over TLS
+	MQTTBroker                string         // MQTT broker URL, e.g. tcp://localhost:1883 (empty disables MQTT publishing)
+	MQTTClientID              string         // MQTT client identifier
+	MQTTUsername              string         // MQTT username (empty connects anonymously)
+	MQTTPassword              string         // MQTT password
+	MQTTTopicPrefix           string         // Prefix of every published MQTT topic
+	MQTTQoS                   int            // MQTT quality of service (0, 1, or 2)
+	MQTTRetain                bool           // Retain the last message of every MQTT topic on the broker
+	LongPatterns              []string       // Reversal patterns that validate Long setups (2-candlestick, pinbar; empty disables Long setups)
+	ShortPatterns             []string       // Reversal patterns that validate Short setups (2-candlestick, pinbar; empty disables Short setups)
+	EMAReference              string         // EMA reversal tails must pierce: extreme (lowest/highest of the stack) or nearest to price
+	EMAPeriods                []int          // Periods of the EMA stack the trend order and pattern rules use, fastest first
+	TimeStopBars              int            // Candles after the entry before a trade plan exits at the close (0 disables)
+	MACDMaxRun                int            // Longest opposing MACD run, in candles, a setup still accepts
+	StochRSILengths           []int          // Stochastic RSI lengths: RSI, stochastic, %K smoothing, and %D smoothing
+	StochRSIMode              string         // Stochastic RSI flat-range handling: standard (50) or tradingview (undefined)
+	StochRSIOversold          float64        // %K level Long setups must be below; Stochastic RSI crossovers must start below it
+	StochRSIOverbought        float64        // %K level Short setups must be above
+	StochRSILongLookback      int            // Candles back the Stochastic RSI crossover of a Long setup may be (1 = newest candle)
+	StochRSIShortLookback     int            // Candles back the Stochastic RSI crossover of a Short setup may be (1 = newest candle)
+	OrderBlockLookback        int            // Candles searched back for order block zones (0 disables them)
+	OrderBlockImpulseATR      float64        // Smallest move away from an order block, in multiples of the 14-period ATR, that creates a zone
+	RequireZoneConfluence     bool           // Only accept setups whose reversal touched an order block holding the EMA support or resistance
+	ConfirmationClosePercent  float64        // Share of the reversal range the confirmation candle must close beyond (100 = beyond the reversal high or low)
+	ConfirmationRisingLows    bool           // Require a higher low (Long) or lower high (Short) on the confirmation candle
+	ConfirmationMaxRangeATR   float64        // Largest confirmation candle range as a multiple of the 14-period ATR (0 disables)
+	PinbarBodyRatio           float64        // Largest pinbar body as a share of its range
+	PinbarWickRatio           float64        // Smallest pinbar wick as a share of its range
+	PinbarBodyATR             float64        // Largest pinbar body in multiples of the 14-period ATR (0 uses PinbarBodyRatio)
+	PinbarWickATR             float64        // Smallest pinbar wick in multiples of the 14-period ATR (0 uses PinbarWickRatio)
+	PierceDepthATR            float64        // Smallest depth reversal tails must reach past the EMA, in multiples of the 14-period ATR (0 accepts any pierce)
+	BacktestRiskPercent       float64        // Account percentage risked per backtested trade
+	BacktestEntryWindow       int            // Candles a backtested entry order stays active (0 = until filled)
+	BenchmarkSymbol           string         // Index or ETF results are compared against with buy-and-hold (empty disables)
+	ExecutionEnabled          bool           // Submit bracket orders for new signals
+	AlpacaKeyID               string         // Alpaca API key ID
+	AlpacaSecretKey           string         // Alpaca API secret key
+	AlpacaPaper               bool           // Trade the Alpaca paper account instead of the live account
+	ExecutionRiskPercent      float64        // Account percentage risked per order
+	ExecutionMaxPositionValue float64        // Maximum notional value per order (0 = no cap)
+	ExecutionMinScore         int            // Minimum setup score that is traded
+	Broker                    string         // Broker orders are submitted to (alpaca, paper)
+	PaperAccountFile          string         // JSON file backing the local paper broker
+	PaperStartingEquity       float64        // Equity of a newly created paper account
+	TimeStopFile              string         // JSON file of submitted orders whose time stop is still pending
+	MaxPositions              int            // Maximum concurrent positions (0 = unlimited)
+	MaxOpenRiskPercent        float64        // Maximum combined open risk as a percentage of equity (0 = unlimited)
+	MaxSectorPercent          float64        // Maximum exposure per sector as a percentage of equity (0 = unlimited)
+	Schedule                  string         // Daemon schedules separated by semicolons
+	StatusAddr                string         // Address the daemon serves its status endpoint on (empty disables it)
+	MonitorInterval           time.Duration  // Time between the quote polls of `sapan monitor`
+	ServeAddr                 string         // Address the API server listens on
+	APIToken                  string         // Bearer token required by the API (empty disables authentication)
+	GRPCAddr                  string         // Address the gRPC API listens on (empty disables it)
+	QueueURL                  string         // Redis URL of the distributed scan queue (empty scans locally)
+	QueuePrefix               string         // Key prefix of the distributed scan queue
+	QueueInFlight             int            // Fetch jobs a coordinator keeps outstanding at once
+	QueueTimeout              time.Duration  // Longest wait for a worker to answer one fetch job
+	WorkerName                string         // Name a distributed worker reports in its replies
+	AlertRulesFile            string         // JSON file of alert rules deciding which signals each notifier receives (empty delivers all)
+	ScreenEnabled             bool           // Screen the universe with bulk quotes before fetching candles
+	ScreenMinPrice            float64        // Minimum quote price for the screen (0 disables)
+	ScreenMaxPrice            float64        // Maximum quote price for the screen (0 disables)
+	ScreenMinAvgVolume        int64          // Minimum rolling average volume for the screen (0 disables)
+	ScreenMinChange           float64        // Minimum absolute percent change from the previous close (0 disables)
+	ScreenTop                 int            // Keep only the N best-ranked stocks after the screen (0 keeps all)
+	ScreenRankBy              string         // Screen ranking: dollar-volume, volume, or change
+	ScreenVolumeFile          string         // JSON file holding the rolling average volumes used by the screen
+	MLScoring                 bool           // Record model features and predicted success probabilities on new signals
+	EnrichMetadata            bool           // Look up the exchange, currency, and country of stocks that produce signals
+	ProfileCacheFile          string         // JSON file caching looked-up stock profiles between runs
+	MLModelFile               string         // JSON file the signal scoring model is trained into and loaded from
+	CorrelationThreshold      float64        // Return correlation at which a weaker signal is flagged or trimmed (0 disables)
+	CorrelationLookback       int            // Number of recent returns compared between signals
+	CorrelationMode           string         // What happens to correlated signals: flag or trim
+	CrossCheckProvider        string         // Second provider the candles of signals are cross-checked against (empty disables)
+	CrossCheckSample          int            // Number of the run's best signals cross-checked
+	CrossCheckCandles         int            // Number of recent candles compared per signal
+	CrossCheckTolerance       float64        // Largest close difference, as a fraction, the providers may have
+
+	settings []Setting // Resolved settings with their sources, secrets masked
 }
 
 // LoadConfig loads configuration from environment variables with fallback defaults
 // This function reads environment variables and provides sensible defaults for missing values
 func LoadConfig() (*Config, error) {
-	config := &Config{}
+	return LoadConfigFromArgs(nil)
+}
+
+// LoadConfigFromArgs loads configuration from command-line flags, environment variables, and an optional JSON file
+// Values are resolved with the precedence flags > environment > config file > defaults, so one-off
+// overrides never require editing the environment
+func LoadConfigFromArgs(args []string) (*Config, error) {
+	l, err := newLoader(args)
+	if err != nil {
+		return nil, err
+	}
+	// SECTION narrows any command to one market section, e.g. to read its watch list or signal history
+	if section := l.stringValue("SECTION", ""); section != "" {
+		return LoadSectionConfig(args, section)
+	}
+	return load(l)
+}
+
+// LoadSectionConfig loads the configuration of one market section of a multi-market run
+// <SECTION>_KEY values override the shared settings, MARKET defaults to the section name, and the watch list,
+// CSV export, run result, and journal files get the section name inserted unless the section sets its own
+// The signal database stays shared, with every row stamped with the section that recorded it
+func LoadSectionConfig(args []string, section string) (*Config, error) {
+	if !sectionPattern.MatchString(section) {
+		return nil, fmt.Errorf("invalid market section %q (use letters and digits, starting with a letter)", section)
+	}
+	l, err := newLoader(args)
+	if err != nil {
+		return nil, err
+	}
+	l.section = strings.ToUpper(section)
+	return load(l)
+}
+
+// sectionPattern matches market section names, which prefix environment variable names
+var sectionPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// load resolves every setting through the loader and validates the result
+func load(l *loader) (*Config, error) {
+	var err error
+	config := &Config{Section: strings.ToLower(l.section)}
+
+	// Load market data provider and candle timeframe (optional, default: Alpha Vantage daily candles)
+	if config.Provider, err = l.choiceValue("PROVIDER", "alphavantage", "alphavantage", "binance", "csv"); err != nil {
+		return nil, err
+	}
+
+	// Load API key (required by Alpha Vantage) from the environment, a *_FILE secret, or the external secrets command
+	if config.APIKey, err = l.secretValue("ALPHA_VANTAGE_API_KEY"); err != nil {
+		return nil, err
+	}
+	if config.APIKey == "" && config.Provider == "alphavantage" {
+		return nil, fmt.Errorf("ALPHA_VANTAGE_API_KEY is required (set it directly, via ALPHA_VANTAGE_API_KEY_FILE, or through SECRETS_COMMAND)")
+	}
+
+	// Load API URLs (optional, default: the public provider URLs)
+	config.APIURL = l.stringValue("ALPHA_VANTAGE_API_URL", "https://www.alphavantage.co/query")
+	config.BinanceAPIURL = l.stringValue("BINANCE_API_URL", "https://api.binance.com")
+
+	// Load the CSV candle layout (required directory for the csv provider, default: SYMBOL.csv with Date,Open,... headers)
+	config.CSVDir = l.stringValue("CSV_DIR", "")
+	if config.CSVDir == "" && config.Provider == "csv" {
+		return nil, fmt.Errorf("CSV_DIR is required by the csv provider")
+	}
+	config.CSVFilePattern = l.stringValue("CSV_FILE_PATTERN", "{symbol}.csv")
+	if !strings.Contains(config.CSVFilePattern, "{symbol}") {
+		return nil, fmt.Errorf("CSV_FILE_PATTERN must contain {symbol}, got %q", config.CSVFilePattern)
+	}
+	if config.CSVColumns = l.listValue("CSV_COLUMNS"); len(config.CSVColumns) == 0 {
+		config.CSVColumns = []string{"date", "open", "high", "low", "close", "volume"}
+	}
+	if len(config.CSVColumns) != 6 {
+		return nil, fmt.Errorf("CSV_COLUMNS must name the date, open, high, low, close, and volume columns, got %d names", len(config.CSVColumns))
+	}
+	config.CSVDateFormat = l.stringValue("CSV_DATE_FORMAT", "2006-01-02")
+	delimiter := []rune(l.stringValue("CSV_DELIMITER", ","))
+	if value := string(delimiter); value == "tab" || value == `\t` {
+		delimiter = []rune{'\t'}
+	}
+	if len(delimiter) != 1 {
+		return nil, fmt.Errorf("CSV_DELIMITER must be a single character, got %q", string(delimiter))
+	}
+	config.CSVDelimiter = delimiter[0]
+
+	if config.Timeframe, err = l.choiceValue("TIMEFRAME", "daily",
+		"daily", "weekly", "monthly", "1min", "5min", "15min", "30min", "60min"); err != nil {
+		return nil, err
+	}
+
+	// Load adjusted series settings (optional, default: as-traded candles)
+	if config.AdjustedSeries, err = l.boolValue("ADJUSTED_SERIES", false); err != nil {
+		return nil, err
+	}
+	if config.AdjustedPrices, err = l.boolValue("ADJUSTED_PRICES", false); err != nil {
+		return nil, err
+	}
+	config.AdjustedSeries = config.AdjustedSeries || config.AdjustedPrices
+	if config.AdjustedSeries && config.Provider != "alphavantage" {
+		return nil, fmt.Errorf("ADJUSTED_SERIES and ADJUSTED_PRICES require the alphavantage provider, got %s", config.Provider)
+	}
+
+	// Load worker count (optional, default: 5)
+	if config.WorkerCount, err = l.intValue("WORKER_COUNT", 5); err != nil {
+		return nil, err
+	}
+
+	// Load CPU worker count (optional, default: every core)
+	if config.CPUWorkers, err = l.intValue("CPU_WORKERS", 0); err != nil {
+		return nil, err
+	}
+	if config.CPUWorkers < 0 {
+		return nil, fmt.Errorf("CPU_WORKERS must be 0 (every core) or positive, got %d", config.CPUWorkers)
+	}
 
-	// Load API key from environment (required)
-	apiKey := os.Getenv("ALPHA_VANTAGE_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("ALPHA_VANTAGE_API_KEY environment variable is required")
+	// Load request delay in seconds (optional, default: 2 seconds)
+	requestDelay, err := l.intValue("REQUEST_DELAY_SECONDS", 2)
+	if err != nil {
+		return nil, err
 	}
-	config.APIKey = apiKey
+	config.RequestDelay = time.Duration(requestDelay) * time.Second
 
-	// Load API URL from environment (optional, default: Alpha Vantage URL)
-	apiURL := os.Getenv("ALPHA_VANTAGE_API_URL")
-	if apiURL != "" {
-		config.APIURL = apiURL
-	} else {
-		config.APIURL = "https://www.alphavantage.co/query" // Default Alpha Vantage URL
+	// Load provider timeouts in seconds (optional, default: 30 seconds per request, 120 seconds per symbol)
+	httpTimeout, err := l.intValue("HTTP_TIMEOUT_SECONDS", 30)
+	if err != nil {
+		return nil, err
+	}
+	fetchTimeout, err := l.intValue("FETCH_TIMEOUT_SECONDS", 120)
+	if err != nil {
+		return nil, err
 	}
+	if httpTimeout < 0 || fetchTimeout < 0 {
+		return nil, fmt.Errorf("HTTP_TIMEOUT_SECONDS and FETCH_TIMEOUT_SECONDS must be 0 (no timeout) or positive")
+	}
+	config.HTTPTimeout = time.Duration(httpTimeout) * time.Second
+	config.FetchTimeout = time.Duration(fetchTimeout) * time.Second
 
-	// Load worker count from environment (optional, default: 5)
-	workerCountStr := os.Getenv("WORKER_COUNT")
-	if workerCountStr != "" {
-		workerCount, err := strconv.Atoi(workerCountStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid WORKER_COUNT value: %v", err)
+	// Load the provider connection pool and proxy (optional, default: 16 pooled connections, proxy from the environment)
+	if config.HTTPMaxIdleConns, err = l.intValue("HTTP_MAX_IDLE_CONNS", 16); err != nil {
+		return nil, err
+	}
+	if config.HTTPMaxIdleConns < 1 {
+		return nil, fmt.Errorf("HTTP_MAX_IDLE_CONNS must be at least 1, got %d", config.HTTPMaxIdleConns)
+	}
+	config.HTTPProxyURL = l.stringValue("HTTP_PROXY_URL", "")
+	if config.HTTPProxyURL != "" {
+		if proxyURL, err := url.Parse(config.HTTPProxyURL); err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid HTTP_PROXY_URL value: %q (expected a URL such as http://proxy:3128)", config.HTTPProxyURL)
 		}
-		config.WorkerCount = workerCount
-	} else {
-		config.WorkerCount = 5 // Default value
 	}
 
-	// Load request delay from environment (optional, default: 2 seconds)
-	requestDelayStr := os.Getenv("REQUEST_DELAY_SECONDS")
-	if requestDelayStr != "" {
-		requestDelay, err := strconv.Atoi(requestDelayStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid REQUEST_DELAY_SECONDS value: %v", err)
+	// Load the retry policy of transient provider failures (optional, default: 3 attempts, 1 second base delay)
+	if config.RetryAttempts, err = l.intValue("RETRY_ATTEMPTS", 3); err != nil {
+		return nil, err
+	}
+	if config.RetryAttempts < 1 {
+		return nil, fmt.Errorf("RETRY_ATTEMPTS must be at least 1, got %d", config.RetryAttempts)
+	}
+	retryDelay, err := l.intValue("RETRY_BASE_DELAY_SECONDS", 1)
+	if err != nil {
+		return nil, err
+	}
+	if retryDelay < 0 {
+		return nil, fmt.Errorf("RETRY_BASE_DELAY_SECONDS must be 0 or positive, got %d", retryDelay)
+	}
+	config.RetryBaseDelay = time.Duration(retryDelay) * time.Second
+
+	// Load stocks file path (optional, default: dist/Stocks.json)
+	config.StocksFile = l.stringValue("STOCKS_FILE", "dist/Stocks.json")
+
+	// Load output size (optional, default: auto, sized to the strategy's indicators)
+	if config.OutputSize, err = outputSizeValue(l, "OUTPUT_SIZE", config.Timeframe); err != nil {
+		return nil, err
+	}
+
+	// Load watch list file path (optional, default: dist/WatchList.json)
+	config.WatchListFile = l.sectionPath("WATCHLIST_FILE", "dist/WatchList.json")
+
+	// Load signal database path (optional, default: disabled); sections share it and keep their rows apart
+	config.SignalDBPath = l.stringValue("SIGNAL_DB_PATH", "")
+
+	// Load notification mode (optional, default: only new signals are announced)
+	if config.NotifyExisting, err = l.boolValue("NOTIFY_EXISTING_SIGNALS", false); err != nil {
+		return nil, err
+	}
+
+	// Load CSV export path (optional, default: disabled)
+	config.WatchListCSVFile = l.sectionPath("WATCHLIST_CSV_FILE", "")
+
+	// Load trade journal settings (optional, default: disabled, JSON lines)
+	config.JournalFile = l.sectionPath("JOURNAL_FILE", "")
+	if config.JournalFormat, err = l.choiceValue("JOURNAL_FORMAT", "json", "json", "tradervue", "edgewonk"); err != nil {
+		return nil, err
+	}
+	if config.JournalFile != "" && config.SignalDBPath == "" {
+		return nil, fmt.Errorf("JOURNAL_FILE requires SIGNAL_DB_PATH, whose run and signal IDs key the journal entries")
+	}
+
+	// Load outcome tracking window (optional, default: 10 days)
+	if config.OutcomeTrackingDays, err = l.intValue("OUTCOME_TRACKING_DAYS", 10); err != nil {
+		return nil, err
+	}
+
+	// Load top-N highlight settings (optional, default: disabled, ranked by score)
+	if config.TopSignals, err = l.intValue("TOP_SIGNALS", 0); err != nil {
+		return nil, err
+	}
+	config.TopSignalsBy = l.stringValue("TOP_SIGNALS_BY", watcher.RankByScore)
+	if err := watcher.ValidateRankBy(config.TopSignalsBy); err != nil {
+		return nil, fmt.Errorf("invalid TOP_SIGNALS_BY value: %v", err)
+	}
+
+	// Load stock list filters (optional, default: analyze every listed stock)
+	config.IncludeSectors = l.listValue("INCLUDE_SECTORS")
+	config.ExcludeSectors = l.listValue("EXCLUDE_SECTORS")
+	config.IncludeSymbols = l.listValue("INCLUDE_SYMBOLS")
+	config.ExcludeSymbols = l.listValue("EXCLUDE_SYMBOLS")
+	config.SymbolPattern = l.stringValue("SYMBOL_PATTERN", "")
+	if _, err := regexp.Compile(config.SymbolPattern); err != nil {
+		return nil, fmt.Errorf("invalid SYMBOL_PATTERN value: %v", err)
+	}
+
+	// Load quick-run subsets (optional, default: scan the whole list)
+	if config.Limit, err = l.intValue("LIMIT", 0); err != nil {
+		return nil, err
+	}
+	if config.Sample, err = l.intValue("SAMPLE", 0); err != nil {
+		return nil, err
+	}
+	sampleSeed, err := l.intValue("SAMPLE_SEED", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.SampleSeed = int64(sampleSeed)
+	if config.Limit < 0 || config.Sample < 0 {
+		return nil, fmt.Errorf("LIMIT and SAMPLE must not be negative")
+	}
+	if config.Limit > 0 && config.Sample > 0 {
+		return nil, fmt.Errorf("LIMIT and SAMPLE cannot be combined; use one of them")
+	}
+
+	// Load market calendar settings (optional, default: US market, closed days skipped)
+	if l.section == "" {
+		if config.Market, err = l.choiceValue("MARKET", "us", "us", "bist", "crypto"); err != nil {
+			return nil, err
+		}
+	} else if config.Market = l.sectionValue("MARKET", config.Section); !slices.Contains([]string{"us", "bist", "crypto"}, config.Market) {
+		return nil, fmt.Errorf("invalid %s_MARKET value: %q (expected one of us, bist, crypto)", l.section, config.Market)
+	}
+	config.MarketHolidays = l.listValue("MARKET_HOLIDAYS")
+	if l.section == "" {
+		seen := make(map[string]bool)
+		for _, section := range l.listValue("MARKETS") {
+			section = strings.ToLower(section)
+			if !sectionPattern.MatchString(section) {
+				return nil, fmt.Errorf("invalid MARKETS entry %q (use letters and digits, starting with a letter)", section)
+			}
+			if seen[section] {
+				return nil, fmt.Errorf("MARKETS lists %s more than once", section)
+			}
+			seen[section] = true
+			config.Markets = append(config.Markets, section)
+		}
+	}
+	if marketTimezone := l.stringValue("MARKET_TIMEZONE", ""); marketTimezone != "" {
+		if config.MarketLocation, err = time.LoadLocation(marketTimezone); err != nil {
+			return nil, fmt.Errorf("invalid MARKET_TIMEZONE value: %v", err)
 		}
-		config.RequestDelay = time.Duration(requestDelay) * time.Second
-	} else {
-		config.RequestDelay = time.Second * 2 // Default value
+	}
+	if config.CandleClose, err = l.choiceValue("CANDLE_CLOSE", "exchange", "exchange", "utc"); err != nil {
+		return nil, err
+	}
+	if config.CandleClose == "utc" && config.Market != "crypto" {
+		return nil, fmt.Errorf("CANDLE_CLOSE=utc only applies to MARKET=crypto; %s candles close with the session", config.Market)
+	}
+	if config.SkipClosedDays, err = l.boolValue("SKIP_CLOSED_DAYS", true); err != nil {
+		return nil, err
+	}
+
+	// Load display timezone (optional, default: the system's local timezone)
+	displayTimezone := l.stringValue("DISPLAY_TIMEZONE", "Local")
+	if config.DisplayLocation, err = time.LoadLocation(displayTimezone); err != nil {
+		return nil, fmt.Errorf("invalid DISPLAY_TIMEZONE value: %v", err)
+	}
+
+	// Load end-of-run email settings (optional, default: disabled)
+	config.SMTPHost = l.stringValue("SMTP_HOST", "")
+	if config.SMTPPort, err = l.intValue("SMTP_PORT", 587); err != nil {
+		return nil, err
+	}
+	config.SMTPUsername = l.stringValue("SMTP_USERNAME", "")
+	if config.SMTPPassword, err = l.secretValue("SMTP_PASSWORD"); err != nil {
+		return nil, err
+	}
+	config.EmailFrom = l.stringValue("EMAIL_FROM", config.SMTPUsername)
+	config.EmailTo = l.listValue("EMAIL_TO")
+	if config.SMTPHost != "" && (len(config.EmailTo) == 0 || config.EmailFrom == "") {
+		return nil, fmt.Errorf("SMTP_HOST requires EMAIL_TO and EMAIL_FROM (or SMTP_USERNAME)")
+	}
+
+	// Load API quota (optional, default: requests are counted without a limit)
+	if config.APIDailyQuota, err = l.intValue("API_DAILY_QUOTA", 0); err != nil {
+		return nil, err
+	}
+	if config.APIDailyQuota < 0 {
+		return nil, fmt.Errorf("API_DAILY_QUOTA must be 0 (no limit) or positive, got %d", config.APIDailyQuota)
+	}
+	config.QuotaFile = l.stringValue("QUOTA_FILE", "api_usage.json")
+
+	// Load webhook settings (optional, default: disabled)
+	config.WebhookURLs = l.listValue("WEBHOOK_URLS")
+	if config.WebhookSecret, err = l.secretValue("WEBHOOK_SECRET"); err != nil {
+		return nil, err
+	}
+	if config.WebhookMaxRetries, err = l.intValue("WEBHOOK_MAX_RETRIES", 3); err != nil {
+		return nil, err
+	}
+	webhookTimeout, err := l.intValue("WEBHOOK_TIMEOUT_SECONDS", 10)
+	if err != nil {
+		return nil, err
+	}
+	config.WebhookTimeout = time.Duration(webhookTimeout) * time.Second
+
+	// Load notifier dispatch settings (optional, default: one delivery per second per notifier, 2 retries)
+	notifyInterval, err := l.intValue("NOTIFY_MIN_INTERVAL_MS", 1000)
+	if err != nil {
+		return nil, err
+	}
+	config.NotifyInterval = time.Duration(notifyInterval) * time.Millisecond
+	if config.NotifyMaxRetries, err = l.intValue("NOTIFY_MAX_RETRIES", 2); err != nil {
+		return nil, err
+	}
+	if config.NotifyStreaming, err = l.boolValue("NOTIFY_STREAMING", false); err != nil {
+		return nil, err
+	}
+
+	// Load JSON run result path (optional, default: disabled)
+	config.ResultsFile = l.sectionPath("RESULTS_FILE", "")
+
+	// Load HTML reports directory (optional, default: disabled)
+	config.ReportsDir = l.stringValue("REPORTS_DIR", "")
+
+	// Load candle archive directory (optional, default: disabled)
+	config.CandleDir = l.stringValue("CANDLE_DIR", "")
+	if config.CandleDir != "" && config.AdjustedPrices {
+		return nil, fmt.Errorf("ADJUSTED_PRICES cannot be combined with CANDLE_DIR, whose as-traded archive sapan adjust re-adjusts")
+	}
+
+	// Load candle anomaly checks (optional, default: warn about the newest 100 candles, gaps beyond 25%)
+	if config.CandleChecks, err = l.choiceValue("CANDLE_CHECKS", "warn", "warn", "skip", "off"); err != nil {
+		return nil, err
+	}
+	maxGap, err := l.floatValue("CANDLE_MAX_GAP_PERCENT", 25)
+	if err != nil {
+		return nil, err
+	}
+	if maxGap < 0 {
+		return nil, fmt.Errorf("CANDLE_MAX_GAP_PERCENT must be 0 (disabled) or positive, got %g", maxGap)
+	}
+	config.CandleMaxGap = maxGap / 100
+	if config.CandleCheckWindow, err = l.intValue("CANDLE_CHECK_WINDOW", 100); err != nil {
+		return nil, err
+	}
+	if config.CandleCheckWindow < 0 {
+		return nil, fmt.Errorf("CANDLE_CHECK_WINDOW must be 0 (every candle) or positive, got %d", config.CandleCheckWindow)
+	}
+
+	// Load blacklist (optional, default: blacklist.json, symbols rejected in 3 consecutive scans are added)
+	config.BlacklistFile = l.stringValue("BLACKLIST_FILE", "blacklist.json")
+	if config.BlacklistAutoRejections, err = l.intValue("BLACKLIST_AUTO_REJECTIONS", 3); err != nil {
+		return nil, err
+	}
+	if config.BlacklistAutoRejections < 0 {
+		return nil, fmt.Errorf("BLACKLIST_AUTO_REJECTIONS must be 0 (disabled) or positive, got %d", config.BlacklistAutoRejections)
+	}
+
+	// Load stock metadata enrichment (optional, default: disabled)
+	if config.EnrichMetadata, err = l.boolValue("ENRICH_METADATA", false); err != nil {
+		return nil, err
+	}
+	config.ProfileCacheFile = l.stringValue("PROFILE_CACHE_FILE", "stock_profiles.json")
+
+	// Load terminal output mode (optional, default: normal)
+	if config.OutputMode, err = output.ParseMode(l.stringValue("OUTPUT_MODE", "normal")); err != nil {
+		return nil, err
+	}
+
+	// Load explain mode (optional, default: disabled)
+	if config.Explain, err = l.boolValue("EXPLAIN", false); err != nil {
+		return nil, err
+	}
+	config.ExplainSymbols = l.listValue("EXPLAIN_SYMBOLS")
+	config.Explain = config.Explain || len(config.ExplainSymbols) > 0
+
+	// Load both-sides reporting (optional, default: Short is skipped when Long is valid)
+	if config.BothSides, err = l.boolValue("BOTH_SIDES", false); err != nil {
+		return nil, err
+	}
+
+	// Load terminal color setting (optional, default: auto-detect)
+	if config.Color, err = l.choiceValue("COLOR", "auto", "auto", "always", "never"); err != nil {
+		return nil, err
+	}
+
+	// Load push notification settings (optional, default: disabled)
+	config.NtfyServer = l.stringValue("NTFY_SERVER", "https://ntfy.sh")
+	config.NtfyTopic = l.stringValue("NTFY_TOPIC", "")
+	if config.NtfyToken, err = l.secretValue("NTFY_TOKEN"); err != nil {
+		return nil, err
+	}
+	if config.PushoverToken, err = l.secretValue("PUSHOVER_TOKEN"); err != nil {
+		return nil, err
+	}
+	if config.PushoverUser, err = l.secretValue("PUSHOVER_USER"); err != nil {
+		return nil, err
+	}
+	if (config.PushoverToken == "") != (config.PushoverUser == "") {
+		return nil, fmt.Errorf("PUSHOVER_TOKEN and PUSHOVER_USER must be set together")
 	}
 
-	// Load stocks file path from environment (optional, default: dist/Stocks.json)
-	stocksFile := os.Getenv("STOCKS_FILE")
-	if stocksFile != "" {
-		config.StocksFile = stocksFile
-	} else {
-		config.StocksFile = "dist/Stocks.json" // Default value
+	// Load SMS settings (optional, default: disabled)
+	if config.TwilioAccountSID, err = l.secretValue("TWILIO_ACCOUNT_SID"); err != nil {
+		return nil, err
+	}
+	if config.TwilioAuthToken, err = l.secretValue("TWILIO_AUTH_TOKEN"); err != nil {
+		return nil, err
+	}
+	config.SMSFrom = l.stringValue("SMS_FROM", "")
+	config.SMSTo = l.listValue("SMS_TO")
+	if config.SMSMinScore, err = l.intValue("SMS_MIN_SCORE", 80); err != nil {
+		return nil, err
+	}
+	if config.TwilioAccountSID != "" && (config.TwilioAuthToken == "" || config.SMSFrom == "" || len(config.SMSTo) == 0) {
+		return nil, fmt.Errorf("TWILIO_ACCOUNT_SID requires TWILIO_AUTH_TOKEN, SMS_FROM, and SMS_TO")
 	}
 
-	// Load output size from environment (optional, default: 200)
-	outputSizeStr := os.Getenv("OUTPUT_SIZE")
-	if outputSizeStr != "" {
-		outputSize, err := strconv.Atoi(outputSizeStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid OUTPUT_SIZE value: %v", err)
+	// Load the exit code used when a scan finds signals (optional, default: disabled)
+	if config.SignalExitCode, err = l.intValue("SIGNAL_EXIT_CODE", 0); err != nil {
+		return nil, err
+	}
+	if config.SignalExitCode < 0 || config.SignalExitCode > 125 || (config.SignalExitCode >= 1 && config.SignalExitCode <= 3) {
+		return nil, fmt.Errorf("SIGNAL_EXIT_CODE must be 0 or between 4 and 125 (1-3 are reserved for failures)")
+	}
+
+	// Load desktop notification toggle (optional, default: disabled)
+	if config.DesktopNotifications, err = l.boolValue("DESKTOP_NOTIFICATIONS", false); err != nil {
+		return nil, err
+	}
+
+	// Load Kafka publishing settings (optional, default: disabled)
+	config.KafkaBrokers = l.listValue("KAFKA_BROKERS")
+	config.KafkaSignalTopic = l.stringValue("KAFKA_SIGNAL_TOPIC", "sapan.signals")
+	config.KafkaRunTopic = l.stringValue("KAFKA_RUN_TOPIC", "sapan.runs")
+	config.KafkaUsername = l.stringValue("KAFKA_USERNAME", "")
+	if config.KafkaPassword, err = l.secretValue("KAFKA_PASSWORD"); err != nil {
+		return nil, err
+	}
+	if config.KafkaTLS, err = l.boolValue("KAFKA_TLS", false); err != nil {
+		return nil, err
+	}
+	if len(config.KafkaBrokers) > 0 && config.KafkaSignalTopic == "" {
+		return nil, fmt.Errorf("KAFKA_SIGNAL_TOPIC must not be empty when KAFKA_BROKERS is set")
+	}
+
+	// Load MQTT publishing settings (optional, default: disabled)
+	config.MQTTBroker = l.stringValue("MQTT_BROKER", "")
+	hostname, _ := os.Hostname()
+	config.MQTTClientID = l.stringValue("MQTT_CLIENT_ID", "sapan-"+hostname)
+	config.MQTTUsername = l.stringValue("MQTT_USERNAME", "")
+	if config.MQTTPassword, err = l.secretValue("MQTT_PASSWORD"); err != nil {
+		return nil, err
+	}
+	config.MQTTTopicPrefix = l.stringValue("MQTT_TOPIC_PREFIX", "sapan")
+	if config.MQTTQoS, err = l.intValue("MQTT_QOS", 1); err != nil {
+		return nil, err
+	}
+	if config.MQTTQoS < 0 || config.MQTTQoS > 2 {
+		return nil, fmt.Errorf("MQTT_QOS must be 0, 1, or 2")
+	}
+	if config.MQTTRetain, err = l.boolValue("MQTT_RETAIN", true); err != nil {
+		return nil, err
+	}
+
+	// Load alert rules file (optional; rules are parsed when the scan starts)
+	config.AlertRulesFile = l.stringValue("ALERT_RULES_FILE", "")
+
+	// Load screener settings (optional, default: disabled)
+	if config.ScreenEnabled, err = l.boolValue("SCREEN_ENABLED", false); err != nil {
+		return nil, err
+	}
+	if config.ScreenMinPrice, err = l.floatValue("SCREEN_MIN_PRICE", 0); err != nil {
+		return nil, err
+	}
+	if config.ScreenMaxPrice, err = l.floatValue("SCREEN_MAX_PRICE", 0); err != nil {
+		return nil, err
+	}
+	if config.ScreenMinPrice < 0 || config.ScreenMaxPrice < 0 || (config.ScreenMaxPrice > 0 && config.ScreenMinPrice > config.ScreenMaxPrice) {
+		return nil, fmt.Errorf("SCREEN_MIN_PRICE and SCREEN_MAX_PRICE must be non-negative with the minimum not above the maximum")
+	}
+	minAvgVolume, err := l.intValue("SCREEN_MIN_AVG_VOLUME", 0)
+	if err != nil {
+		return nil, err
+	}
+	config.ScreenMinAvgVolume = int64(minAvgVolume)
+	if config.ScreenMinChange, err = l.floatValue("SCREEN_MIN_CHANGE_PERCENT", 0); err != nil {
+		return nil, err
+	}
+	if config.ScreenTop, err = l.intValue("SCREEN_TOP", 0); err != nil {
+		return nil, err
+	}
+	if config.ScreenMinAvgVolume < 0 || config.ScreenMinChange < 0 || config.ScreenTop < 0 {
+		return nil, fmt.Errorf("SCREEN_MIN_AVG_VOLUME, SCREEN_MIN_CHANGE_PERCENT, and SCREEN_TOP must not be negative")
+	}
+	if config.ScreenRankBy, err = l.choiceValue("SCREEN_RANK_BY", "dollar-volume", "dollar-volume", "volume", "change"); err != nil {
+		return nil, err
+	}
+	config.ScreenVolumeFile = l.stringValue("SCREEN_VOLUME_FILE", "dist/ScreenVolumes.json")
+
+	// Load machine-learning scoring settings (optional, default: disabled)
+	if config.MLScoring, err = l.boolValue("ML_SCORING", false); err != nil {
+		return nil, err
+	}
+	if config.MLScoring && config.SignalDBPath == "" {
+		return nil, fmt.Errorf("ML_SCORING requires SIGNAL_DB_PATH, where features and outcomes are recorded")
+	}
+	config.MLModelFile = l.sectionPath("ML_MODEL_FILE", "dist/SignalModel.json")
+
+	// Load correlation filtering settings (optional, default: disabled)
+	if config.CorrelationThreshold, err = l.floatValue("CORRELATION_THRESHOLD", 0); err != nil {
+		return nil, err
+	}
+	if config.CorrelationThreshold < 0 || config.CorrelationThreshold > 1 {
+		return nil, fmt.Errorf("CORRELATION_THRESHOLD must be between 0 and 1")
+	}
+	if config.CorrelationLookback, err = l.intValue("CORRELATION_LOOKBACK", 60); err != nil {
+		return nil, err
+	}
+	if config.CorrelationLookback < 10 {
+		return nil, fmt.Errorf("CORRELATION_LOOKBACK must be at least 10 returns")
+	}
+	if config.CorrelationMode, err = l.choiceValue("CORRELATION_MODE", "flag", "flag", "trim"); err != nil {
+		return nil, err
+	}
+
+	// Load provider cross-check settings (optional, default: disabled)
+	if config.CrossCheckProvider, err = l.choiceValue("CROSS_CHECK_PROVIDER", "none", "none", "alphavantage", "binance", "csv"); err != nil {
+		return nil, err
+	}
+	switch config.CrossCheckProvider {
+	case "none":
+		config.CrossCheckProvider = ""
+	case config.Provider:
+		return nil, fmt.Errorf("CROSS_CHECK_PROVIDER must differ from PROVIDER, got %s for both", config.Provider)
+	case "alphavantage":
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("CROSS_CHECK_PROVIDER=alphavantage requires ALPHA_VANTAGE_API_KEY")
+		}
+	case "csv":
+		if config.CSVDir == "" {
+			return nil, fmt.Errorf("CROSS_CHECK_PROVIDER=csv requires CSV_DIR")
 		}
-		config.OutputSize = outputSize
-	} else {
-		config.OutputSize = 200 // Default value
+	}
+	if config.CrossCheckSample, err = l.intValue("CROSS_CHECK_SAMPLE", 5); err != nil {
+		return nil, err
+	}
+	if config.CrossCheckSample < 1 {
+		return nil, fmt.Errorf("CROSS_CHECK_SAMPLE must be at least 1, got %d", config.CrossCheckSample)
+	}
+	if config.CrossCheckCandles, err = l.intValue("CROSS_CHECK_CANDLES", 20); err != nil {
+		return nil, err
+	}
+	if config.CrossCheckCandles < 1 {
+		return nil, fmt.Errorf("CROSS_CHECK_CANDLES must be at least 1, got %d", config.CrossCheckCandles)
+	}
+	tolerance, err := l.floatValue("CROSS_CHECK_TOLERANCE_PERCENT", 2)
+	if err != nil {
+		return nil, err
+	}
+	if tolerance <= 0 || tolerance >= 100 {
+		return nil, fmt.Errorf("CROSS_CHECK_TOLERANCE_PERCENT must be between 0 and 100, got %g", tolerance)
+	}
+	config.CrossCheckTolerance = tolerance / 100
+
+	// Load the enabled reversal patterns per side (optional, default: every pattern)
+	if config.LongPatterns, err = patternsValue(l, "LONG_PATTERNS"); err != nil {
+		return nil, err
+	}
+	if config.ShortPatterns, err = patternsValue(l, "SHORT_PATTERNS"); err != nil {
+		return nil, err
+	}
+
+	// Load the support and resistance EMA of the patterns (optional, default: lowest and highest EMA)
+	if config.EMAReference, err = l.choiceValue("EMA_REFERENCE", "extreme", "extreme", "nearest"); err != nil {
+		return nil, err
+	}
+
+	// Load the EMA stack (optional, default: 20,50,100,200)
+	if config.EMAPeriods, err = emaPeriodsValue(l, "EMA_PERIODS"); err != nil {
+		return nil, err
+	}
+
+	// Load the holding period of trade plans (optional, default: hold until the stop or target)
+	if config.TimeStopBars, err = l.intValue("TIME_STOP_BARS", 0); err != nil {
+		return nil, err
+	}
+	if config.TimeStopBars < 0 {
+		return nil, fmt.Errorf("TIME_STOP_BARS must be 0 (no time stop) or positive, got %d", config.TimeStopBars)
+	}
+
+	// Load the MACD run limit (optional, default: opposing runs of up to 5 candles)
+	if config.MACDMaxRun, err = l.intValue("MACD_MAX_RUN", 5); err != nil {
+		return nil, err
+	}
+	if config.MACDMaxRun < 0 {
+		return nil, fmt.Errorf("MACD_MAX_RUN must be 0 (MACD on the setup's side of its signal line) or positive, got %d", config.MACDMaxRun)
+	}
+
+	// Load the Stochastic RSI lengths and mode (optional, default: 14,14,3,3 in standard mode)
+	if config.StochRSILengths, err = stochRSILengthsValue(l, "STOCH_RSI_LENGTHS"); err != nil {
+		return nil, err
+	}
+	if config.StochRSIMode, err = l.choiceValue("STOCH_RSI_MODE", "standard", "standard", "tradingview"); err != nil {
+		return nil, err
+	}
+
+	// Load the Stochastic RSI thresholds of each scenario (optional, default: 30/70 with the crossover on the newest candle)
+	if config.StochRSIOversold, err = l.floatValue("STOCH_RSI_OVERSOLD", 30); err != nil {
+		return nil, err
+	}
+	if config.StochRSIOverbought, err = l.floatValue("STOCH_RSI_OVERBOUGHT", 70); err != nil {
+		return nil, err
+	}
+	if config.StochRSIOversold <= 0 || config.StochRSIOverbought >= 100 || config.StochRSIOversold > config.StochRSIOverbought {
+		return nil, fmt.Errorf("STOCH_RSI_OVERSOLD and STOCH_RSI_OVERBOUGHT must satisfy 0 < oversold <= overbought < 100, got %g and %g",
+			config.StochRSIOversold, config.StochRSIOverbought)
+	}
+	if config.StochRSILongLookback, err = l.intValue("STOCH_RSI_LONG_LOOKBACK", 1); err != nil {
+		return nil, err
+	}
+	if config.StochRSIShortLookback, err = l.intValue("STOCH_RSI_SHORT_LOOKBACK", 1); err != nil {
+		return nil, err
+	}
+	if config.StochRSILongLookback < 1 || config.StochRSIShortLookback < 1 {
+		return nil, fmt.Errorf("STOCH_RSI_LONG_LOOKBACK and STOCH_RSI_SHORT_LOOKBACK must be at least 1 (the newest candle), got %d and %d",
+			config.StochRSILongLookback, config.StochRSIShortLookback)
+	}
+
+	// Load order block zone detection (optional, default: 2 ATR moves over the last 50 candles, confluence not required)
+	if config.OrderBlockLookback, err = l.intValue("ORDER_BLOCK_LOOKBACK", 50); err != nil {
+		return nil, err
+	}
+	if config.OrderBlockLookback < 0 {
+		return nil, fmt.Errorf("ORDER_BLOCK_LOOKBACK must not be negative, got %d", config.OrderBlockLookback)
+	}
+	if config.OrderBlockImpulseATR, err = l.floatValue("ORDER_BLOCK_IMPULSE_ATR", 2); err != nil {
+		return nil, err
+	}
+	if config.OrderBlockImpulseATR <= 0 {
+		return nil, fmt.Errorf("ORDER_BLOCK_IMPULSE_ATR must be greater than 0, got %g", config.OrderBlockImpulseATR)
+	}
+	if config.RequireZoneConfluence, err = l.boolValue("REQUIRE_ZONE_CONFLUENCE", false); err != nil {
+		return nil, err
+	}
+	if config.RequireZoneConfluence && config.OrderBlockLookback == 0 {
+		return nil, fmt.Errorf("REQUIRE_ZONE_CONFLUENCE needs order block zones, but ORDER_BLOCK_LOOKBACK is 0")
 	}
 
+	// Load confirmation candle rules (optional, default: close beyond the reversal extreme with rising lows)
+	if config.ConfirmationClosePercent, err = l.floatValue("CONFIRMATION_CLOSE_PERCENT", 100); err != nil {
+		return nil, err
+	}
+	if config.ConfirmationClosePercent <= 0 || config.ConfirmationClosePercent > 200 {
+		return nil, fmt.Errorf("CONFIRMATION_CLOSE_PERCENT must be greater than 0 and at most 200, got %g", config.ConfirmationClosePercent)
+	}
+	if config.ConfirmationRisingLows, err = l.boolValue("CONFIRMATION_RISING_LOWS", true); err != nil {
+		return nil, err
+	}
+	if config.ConfirmationMaxRangeATR, err = l.floatValue("CONFIRMATION_MAX_RANGE_ATR", 0); err != nil {
+		return nil, err
+	}
+	if config.ConfirmationMaxRangeATR < 0 {
+		return nil, fmt.Errorf("CONFIRMATION_MAX_RANGE_ATR must be 0 (no limit) or positive, got %g", config.ConfirmationMaxRangeATR)
+	}
+
+	// Load pinbar and tail pierce thresholds (optional, default: body at most 30% and wick at least 60% of the range)
+	if config.PinbarBodyRatio, err = l.floatValue("PINBAR_BODY_RATIO", 0.3); err != nil {
+		return nil, err
+	}
+	if config.PinbarWickRatio, err = l.floatValue("PINBAR_WICK_RATIO", 0.6); err != nil {
+		return nil, err
+	}
+	if config.PinbarBodyRatio <= 0 || config.PinbarWickRatio <= 0 || config.PinbarBodyRatio+config.PinbarWickRatio > 1 {
+		return nil, fmt.Errorf("PINBAR_BODY_RATIO and PINBAR_WICK_RATIO must be positive and add up to at most 1, got %g and %g",
+			config.PinbarBodyRatio, config.PinbarWickRatio)
+	}
+	if config.PinbarBodyATR, err = l.floatValue("PINBAR_BODY_ATR", 0); err != nil {
+		return nil, err
+	}
+	if config.PinbarWickATR, err = l.floatValue("PINBAR_WICK_ATR", 0); err != nil {
+		return nil, err
+	}
+	if config.PierceDepthATR, err = l.floatValue("PIERCE_DEPTH_ATR", 0); err != nil {
+		return nil, err
+	}
+	if config.PinbarBodyATR < 0 || config.PinbarWickATR < 0 || config.PierceDepthATR < 0 {
+		return nil, fmt.Errorf("PINBAR_BODY_ATR, PINBAR_WICK_ATR, and PIERCE_DEPTH_ATR must be 0 (disabled) or positive, got %g, %g, and %g",
+			config.PinbarBodyATR, config.PinbarWickATR, config.PierceDepthATR)
+	}
+
+	// Load backtest settings (used by `sapan backtest`)
+	if config.BacktestRiskPercent, err = l.floatValue("BACKTEST_RISK_PERCENT", 1); err != nil {
+		return nil, err
+	}
+	if config.BacktestRiskPercent <= 0 || config.BacktestRiskPercent > 100 {
+		return nil, fmt.Errorf("BACKTEST_RISK_PERCENT must be greater than 0 and at most 100")
+	}
+	if config.BacktestEntryWindow, err = l.intValue("BACKTEST_ENTRY_WINDOW", 3); err != nil {
+		return nil, err
+	}
+	defaultBenchmark := map[string]string{"us": "SPY", "bist": "XU100.IS", "crypto": "BTC"}[config.Market]
+	config.BenchmarkSymbol = strings.ToUpper(strings.TrimSpace(l.stringValue("BENCHMARK_SYMBOL", defaultBenchmark)))
+	if config.BenchmarkSymbol == "NONE" {
+		config.BenchmarkSymbol = "" // Explicitly disabled
+	}
+
+	// Load order execution settings (optional, default: disabled)
+	if config.ExecutionEnabled, err = l.boolValue("EXECUTION_ENABLED", false); err != nil {
+		return nil, err
+	}
+	if config.AlpacaKeyID, err = l.secretValue("ALPACA_KEY_ID"); err != nil {
+		return nil, err
+	}
+	if config.AlpacaSecretKey, err = l.secretValue("ALPACA_SECRET_KEY"); err != nil {
+		return nil, err
+	}
+	if config.AlpacaPaper, err = l.boolValue("ALPACA_PAPER", true); err != nil {
+		return nil, err
+	}
+	if config.ExecutionRiskPercent, err = l.floatValue("EXECUTION_RISK_PERCENT", 0.5); err != nil {
+		return nil, err
+	}
+	if config.ExecutionMaxPositionValue, err = l.floatValue("EXECUTION_MAX_POSITION_VALUE", 0); err != nil {
+		return nil, err
+	}
+	if config.ExecutionMinScore, err = l.intValue("EXECUTION_MIN_SCORE", 0); err != nil {
+		return nil, err
+	}
+	if config.Broker, err = l.choiceValue("BROKER", "alpaca", "alpaca", "paper"); err != nil {
+		return nil, err
+	}
+	config.PaperAccountFile = l.stringValue("PAPER_ACCOUNT_FILE", "paper_account.json")
+	if config.PaperStartingEquity, err = l.floatValue("PAPER_STARTING_EQUITY", 100000); err != nil {
+		return nil, err
+	}
+	config.TimeStopFile = l.stringValue("TIME_STOP_FILE", "time_stops.json")
+	if config.MaxPositions, err = l.intValue("MAX_POSITIONS", 0); err != nil {
+		return nil, err
+	}
+	if config.MaxOpenRiskPercent, err = l.floatValue("MAX_OPEN_RISK_PERCENT", 0); err != nil {
+		return nil, err
+	}
+	if config.MaxSectorPercent, err = l.floatValue("MAX_SECTOR_PERCENT", 0); err != nil {
+		return nil, err
+	}
+	if config.MaxPositions < 0 || config.MaxOpenRiskPercent < 0 || config.MaxSectorPercent < 0 {
+		return nil, fmt.Errorf("MAX_POSITIONS, MAX_OPEN_RISK_PERCENT, and MAX_SECTOR_PERCENT must not be negative")
+	}
+	if config.ExecutionEnabled {
+		if config.Broker == "alpaca" && (config.AlpacaKeyID == "" || config.AlpacaSecretKey == "") {
+			return nil, fmt.Errorf("the alpaca broker requires ALPACA_KEY_ID and ALPACA_SECRET_KEY")
+		}
+		if config.ExecutionRiskPercent <= 0 || config.ExecutionRiskPercent > 5 {
+			return nil, fmt.Errorf("EXECUTION_RISK_PERCENT must be greater than 0 and at most 5")
+		}
+	}
+
+	// Load daemon settings (used by `sapan daemon`)
+	config.Schedule = l.stringValue("SCHEDULE", "@close+30m")
+	config.StatusAddr = l.stringValue("STATUS_ADDR", "")
+
+	// Load price monitor settings (used by `sapan monitor`)
+	monitorInterval, err := l.intValue("MONITOR_INTERVAL_SECONDS", 60)
+	if err != nil {
+		return nil, err
+	}
+	if monitorInterval < 1 {
+		return nil, fmt.Errorf("MONITOR_INTERVAL_SECONDS must be at least 1, got %d", monitorInterval)
+	}
+	config.MonitorInterval = time.Duration(monitorInterval) * time.Second
+
+	// Load API server settings (used by `sapan serve`)
+	config.ServeAddr = l.stringValue("SERVE_ADDR", ":8080")
+	config.GRPCAddr = l.stringValue("GRPC_ADDR", "")
+	if config.APIToken, err = l.secretValue("API_TOKEN"); err != nil {
+		return nil, err
+	}
+
+	// Load distributed scanning settings (optional, default: local scan)
+	if config.QueueURL, err = l.secretValue("QUEUE_URL"); err != nil {
+		return nil, err
+	}
+	config.QueuePrefix = l.stringValue("QUEUE_PREFIX", "sapan")
+	if config.QueueInFlight, err = l.intValue("QUEUE_IN_FLIGHT", 50); err != nil {
+		return nil, err
+	}
+	if config.QueueInFlight < 1 {
+		return nil, fmt.Errorf("QUEUE_IN_FLIGHT must be at least 1")
+	}
+	queueTimeout, err := l.intValue("QUEUE_TIMEOUT_SECONDS", 300)
+	if err != nil {
+		return nil, err
+	}
+	if queueTimeout < 1 {
+		return nil, fmt.Errorf("QUEUE_TIMEOUT_SECONDS must be at least 1")
+	}
+	config.QueueTimeout = time.Duration(queueTimeout) * time.Second
+	config.WorkerName = l.stringValue("WORKER_NAME", fmt.Sprintf("%s-%d", hostname, os.Getpid()))
+
+	config.settings = l.settings
 	return config, nil
 }
 
+// patternsValue resolves a list of reversal pattern names; "none" disables every pattern of the side
+func patternsValue(l *loader, key string) ([]string, error) {
+	var patterns []string
+	for _, name := range strings.Split(l.stringValue(key, "2-candlestick,pinbar"), ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "", "none":
+		case "2-candlestick", "pinbar":
+			if !slices.Contains(patterns, name) {
+				patterns = append(patterns, name)
+			}
+		default:
+			return nil, fmt.Errorf("invalid %s entry: %q (expected 2-candlestick, pinbar, or none)", key, name)
+		}
+	}
+	return patterns, nil
+}
+
+// outputSizeValue resolves the candle count to fetch for a timeframe, 0 standing for auto
+// The value is a count or "auto" for every timeframe, or comma-separated entries such as "300,60min=1000" where
+// timeframe=size entries override the bare default
+func outputSizeValue(l *loader, key, timeframe string) (int, error) {
+	size, matched := 0, false
+	for _, entry := range strings.Split(l.stringValue(key, "auto"), ",") {
+		name, value, scoped := strings.Cut(strings.TrimSpace(entry), "=")
+		if !scoped {
+			name, value = "", name
+		}
+		parsed := 0
+		if value = strings.TrimSpace(value); value != "auto" {
+			var err error
+			if parsed, err = strconv.Atoi(value); err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid %s entry: %q (expected a positive candle count, auto, or timeframe=count)", key, entry)
+			}
+		}
+		switch name = strings.TrimSpace(name); {
+		case name == timeframe:
+			size, matched = parsed, true
+		case name == "" && !matched:
+			size = parsed
+		}
+	}
+	return size, nil
+}
+
+// stochRSILengthsValue resolves the four Stochastic RSI lengths: RSI, stochastic, %K smoothing, and %D smoothing
+func stochRSILengthsValue(l *loader, key string) ([]int, error) {
+	value := l.stringValue(key, "14,14,3,3")
+	fields := strings.Split(value, ",")
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("invalid %s value: %q (expected RSI,stochastic,K,D lengths such as 14,14,3,3)", key, value)
+	}
+	lengths := make([]int, len(fields))
+	for i, field := range fields {
+		length, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("invalid %s entry: %q (lengths must be positive integers)", key, field)
+		}
+		lengths[i] = length
+	}
+	return lengths, nil
+}
+
+// emaPeriodsValue parses an EMA stack of at least two positive, strictly ascending periods such as 8,21,55,89
+func emaPeriodsValue(l *loader, key string) ([]int, error) {
+	value := l.stringValue(key, "20,50,100,200")
+	fields := strings.Split(value, ",")
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid %s value: %q (expected at least two periods such as 20,50,100,200)", key, value)
+	}
+	periods := make([]int, len(fields))
+	for i, field := range fields {
+		period, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("invalid %s entry: %q (periods must be positive integers)", key, field)
+		}
+		if i > 0 && period <= periods[i-1] {
+			return nil, fmt.Errorf("invalid %s value: %q (periods must be in ascending order, fastest first)", key, value)
+		}
+		periods[i] = period
+	}
+	return periods, nil
+}
+
+// Settings returns every resolved setting with the source it came from, secrets masked
+// This method powers `config show`, answering questions like "why is it using 5 workers"
+func (c *Config) Settings() []Setting {
+	return append([]Setting(nil), c.settings...)
+}
+
+// PrintSettings writes the effective configuration as an aligned table of key, value, and source
+func (c *Config) PrintSettings(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tVALUE\tSOURCE")
+	for _, setting := range c.settings {
+		value := setting.Value
+		if value == "" {
+			value = "(empty)"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", setting.Key, value, setting.Source)
+	}
+	return table.Flush()
+}
+
 // GetOptimalWorkerCount calculates the optimal number of workers based on request delay
 // This method ensures we don't exceed API rate limits while maximizing throughput
 // With 2 second delay, 5 workers = 1 request every 0.4 seconds, which is safe for most APIs
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// TestSectionArgumentLoadsSectionConfig checks that --section loads the section's overrides and per-section files
+// while the signal database stays shared
+func TestSectionArgumentLoadsSectionConfig(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_API_KEY", "demo")
+	t.Setenv("MARKETS", "us,crypto")
+	t.Setenv("CRYPTO_PROVIDER", "binance")
+	t.Setenv("WATCHLIST_FILE", "dist/WatchList.json")
+	t.Setenv("SIGNAL_DB_PATH", "dist/signals.db")
+
+	cfg, err := LoadConfigFromArgs([]string{"--section", "crypto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Section != "crypto" || cfg.Market != "crypto" || cfg.Provider != "binance" {
+		t.Errorf("section %q, market %q, provider %q, want the crypto section on binance", cfg.Section, cfg.Market, cfg.Provider)
+	}
+	if cfg.WatchListFile != "dist/WatchList.crypto.json" {
+		t.Errorf("watch list file %q, want the section name inserted", cfg.WatchListFile)
+	}
+	if cfg.SignalDBPath != "dist/signals.db" {
+		t.Errorf("signal database %q, want the shared dist/signals.db", cfg.SignalDBPath)
+	}
+	if cfg.MLModelFile != "dist/SignalModel.crypto.json" {
+		t.Errorf("model file %q, want the section name inserted", cfg.MLModelFile)
+	}
+
+	top, err := LoadConfigFromArgs(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if top.Section != "" || top.Provider != "alphavantage" || top.WatchListFile != "dist/WatchList.json" {
+		t.Errorf("top-level config took over section settings: section %q, provider %q, watch list %q",
+			top.Section, top.Provider, top.WatchListFile)
+	}
+}
+
+// TestSectionSignalDBPathOverride checks that a section can still keep a signal database of its own
+func TestSectionSignalDBPathOverride(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_API_KEY", "demo")
+	t.Setenv("SIGNAL_DB_PATH", "dist/signals.db")
+	t.Setenv("CRYPTO_SIGNAL_DB_PATH", "dist/crypto.db")
+
+	cfg, err := LoadSectionConfig(nil, "crypto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SignalDBPath != "dist/crypto.db" {
+		t.Errorf("signal database %q, want the section's own dist/crypto.db", cfg.SignalDBPath)
+	}
+	if _, err := LoadSectionConfig(nil, "2fast"); err == nil {
+		t.Error("a section name starting with a digit was accepted")
+	}
+}
+
+// TestTopSignalsByAcceptsWatcherRankings checks TOP_SIGNALS_BY against the rankings the watch list supports
+func TestTopSignalsByAcceptsWatcherRankings(t *testing.T) {
+	t.Setenv("ALPHA_VANTAGE_API_KEY", "demo")
+	for _, by := range []string{watcher.RankByScore, watcher.RankByVolume, watcher.RankByRiskReward} {
+		t.Setenv("TOP_SIGNALS_BY", by)
+		cfg, err := LoadConfigFromArgs(nil)
+		if err != nil {
+			t.Errorf("TOP_SIGNALS_BY=%s was rejected: %v", by, err)
+		} else if cfg.TopSignalsBy != by {
+			t.Errorf("TOP_SIGNALS_BY=%s loaded as %q", by, cfg.TopSignalsBy)
+		}
+	}
+	t.Setenv("TOP_SIGNALS_BY", "price")
+	if _, err := LoadConfigFromArgs(nil); err == nil {
+		t.Error("TOP_SIGNALS_BY=price was accepted")
+	}
+}
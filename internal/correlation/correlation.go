@@ -0,0 +1,96 @@
+// Package correlation spots signals that are likely to move together
+// Signals on the same side whose recent returns are highly correlated add risk without adding diversification
+package correlation
+
+import (
+	"math"
+	"sort"
+)
+
+// Candidate is a signaled symbol with the closing prices used to compute its returns
+type Candidate struct {
+	Symbol string    // Stock ticker symbol
+	Side   string    // Trading side; only candidates on the same side are compared
+	Score  float64   // Setup score; the higher-scoring symbol of a correlated pair is kept
+	Closes []float64 // Closing prices, oldest first
+}
+
+// Match is a candidate that correlates with a stronger candidate already kept
+type Match struct {
+	Candidate
+	CorrelatedWith string  // Symbol of the stronger candidate
+	Correlation    float64 // Pearson correlation of their recent returns
+}
+
+// Screen keeps candidates from the highest score down, flagging each one whose returns over the lookback
+// correlate at or above threshold with a candidate already kept
+func Screen(candidates []Candidate, lookback int, threshold float64) ([]Candidate, []Match) {
+	ordered := make([]Candidate, len(candidates))
+	copy(ordered, candidates)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Score > ordered[j].Score })
+
+	var kept []Candidate
+	var keptReturns [][]float64
+	var flagged []Match
+	for _, candidate := range ordered {
+		returns := Returns(candidate.Closes, lookback)
+		best := Match{Candidate: candidate, Correlation: math.Inf(-1)}
+		for i, other := range kept {
+			if other.Side != candidate.Side {
+				continue
+			}
+			if r := Pearson(returns, keptReturns[i]); r > best.Correlation {
+				best.Correlation = r
+				best.CorrelatedWith = other.Symbol
+			}
+		}
+		if best.CorrelatedWith != "" && best.Correlation >= threshold {
+			flagged = append(flagged, best)
+			continue
+		}
+		kept = append(kept, candidate)
+		keptReturns = append(keptReturns, returns)
+	}
+	return kept, flagged
+}
+
+// Returns converts the last lookback+1 closes into lookback simple returns
+func Returns(closes []float64, lookback int) []float64 {
+	if len(closes) > lookback+1 {
+		closes = closes[len(closes)-lookback-1:]
+	}
+	var returns []float64
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] > 0 {
+			returns = append(returns, closes[i]/closes[i-1]-1)
+		}
+	}
+	return returns
+}
+
+// Pearson returns the correlation of the most recent overlapping values of two series
+// Series shorter than three values or without variance yield 0
+func Pearson(a, b []float64) float64 {
+	n := min(len(a), len(b))
+	if n < 3 {
+		return 0
+	}
+	a, b = a[len(a)-n:], b[len(b)-n:]
+
+	var meanA, meanB float64
+	for i := 0; i < n; i++ {
+		meanA += a[i] / float64(n)
+		meanB += b[i] / float64(n)
+	}
+	var covariance, varianceA, varianceB float64
+	for i := 0; i < n; i++ {
+		da, db := a[i]-meanA, b[i]-meanB
+		covariance += da * db
+		varianceA += da * da
+		varianceB += db * db
+	}
+	if varianceA == 0 || varianceB == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceA*varianceB)
+}
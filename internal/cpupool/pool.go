@@ -0,0 +1,69 @@
+// Package cpupool runs CPU-bound analysis on a bounded set of goroutines
+// The pool is sized by cores, separate from the I/O workers whose count follows API rate limits
+package cpupool
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Pool bounds how many CPU-bound tasks run at once (safe for concurrent use)
+type Pool struct {
+	slots chan struct{}  // One token per running task
+	tasks sync.WaitGroup // Tasks started and not yet finished
+}
+
+// New creates a pool running at most size tasks at once; size <= 0 uses every available core
+func New(size int) *Pool {
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{
+		slots: make(chan struct{}, size), // Initialize the task slots
+	}
+}
+
+// Size returns the number of tasks the pool runs at once
+func (p *Pool) Size() int {
+	return cap(p.slots)
+}
+
+// Go runs task on its own goroutine once a slot is free, blocking the caller while the pool is full
+// Blocking keeps producers such as fetch loops from queuing unbounded work ahead of the analysis
+func (p *Pool) Go(task func()) {
+	p.slots <- struct{}{}
+	p.tasks.Add(1)
+	go func() {
+		defer func() {
+			<-p.slots
+			p.tasks.Done()
+		}()
+		task()
+	}()
+}
+
+// Wait blocks until every task started with Go has finished
+func (p *Pool) Wait() {
+	p.tasks.Wait()
+}
+
+// Each runs task(i) for i in [0, n) on the pool and returns when all of them are done
+// A nil pool runs the tasks in order on the caller's goroutine, so callers need no separate sequential path
+// It must not be called from a task of the same pool, which could wait forever for a slot it holds
+func Each(p *Pool, n int, task func(i int)) {
+	if p == nil {
+		for i := 0; i < n; i++ {
+			task(i)
+		}
+		return
+	}
+	var done sync.WaitGroup
+	done.Add(n)
+	for i := 0; i < n; i++ {
+		p.Go(func() {
+			defer done.Done()
+			task(i)
+		})
+	}
+	done.Wait()
+}
@@ -0,0 +1,51 @@
+package cpupool
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolBoundsConcurrency(t *testing.T) {
+	pool := New(3)
+	var running, peak int64
+	for i := 0; i < 20; i++ {
+		pool.Go(func() {
+			now := atomic.AddInt64(&running, 1)
+			for {
+				seen := atomic.LoadInt64(&peak)
+				if now <= seen || atomic.CompareAndSwapInt64(&peak, seen, now) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+		})
+	}
+	pool.Wait()
+
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+	if running != 0 {
+		t.Errorf("running after Wait = %d, want 0", running)
+	}
+}
+
+func TestEachRunsEveryIndex(t *testing.T) {
+	for _, pool := range []*Pool{nil, New(4)} {
+		results := make([]int, 50)
+		Each(pool, len(results), func(i int) { results[i] = i * i })
+		for i, got := range results {
+			if got != i*i {
+				t.Fatalf("pool size %v: results[%d] = %d, want %d", pool != nil, i, got, i*i)
+			}
+		}
+	}
+}
+
+func TestNewDefaultsToEveryCore(t *testing.T) {
+	if size := New(0).Size(); size < 1 {
+		t.Errorf("New(0).Size() = %d, want at least 1", size)
+	}
+}
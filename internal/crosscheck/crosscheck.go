@@ -0,0 +1,157 @@
+// Package crosscheck compares the recent candles of one market data provider with those of another
+// A checksum of each side shows whether they agree; disagreeing sessions are told apart as split mismatches,
+// stale series, or plain price differences
+package crosscheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"math"
+	"strings"
+	"time"
+)
+
+// Disagreement kinds
+const (
+	PriceMismatch = "price" // Closes differ by more than the tolerance
+	SplitMismatch = "split" // Closes differ by a typical split ratio, so one side is not split-adjusted
+	StaleData     = "stale" // One side lacks the newest sessions of the other
+)
+
+// splitRatios are the share ratios of common forward and reverse splits
+var splitRatios = []float64{2, 3, 4, 5, 8, 10, 15, 20, 1.5}
+
+// Disagreement is one way the two providers' candles differ
+type Disagreement struct {
+	Kind      string    // PriceMismatch, SplitMismatch, or StaleData
+	Date      time.Time // Session the disagreement was found on
+	Primary   float64   // Close of the scanning provider (zero when it lacks the session)
+	Secondary float64   // Close of the checking provider (zero when it lacks the session)
+}
+
+// String describes the disagreement in one line
+func (d Disagreement) String() string {
+	date := d.Date.Format("2006-01-02")
+	switch d.Kind {
+	case SplitMismatch:
+		return fmt.Sprintf("split mismatch on %s (close %.2f vs %.2f)", date, d.Primary, d.Secondary)
+	case StaleData:
+		if d.Primary == 0 {
+			return fmt.Sprintf("stale data: the scanning provider lacks %s", date)
+		}
+		return fmt.Sprintf("stale data: the checking provider lacks %s", date)
+	default:
+		return fmt.Sprintf("price mismatch on %s (close %.2f vs %.2f)", date, d.Primary, d.Secondary)
+	}
+}
+
+// Result is the outcome of cross-checking one symbol
+type Result struct {
+	Symbol            string         // Stock ticker symbol
+	Compared          int            // Sessions both providers served within the window
+	PrimaryChecksum   string         // Checksum of the scanning provider's sessions in common
+	SecondaryChecksum string         // Checksum of the checking provider's sessions in common
+	Disagreements     []Disagreement // Material differences, oldest first (empty when the providers agree)
+}
+
+// Agrees reports whether the providers served the same recent candles
+func (r Result) Agrees() bool {
+	return len(r.Disagreements) == 0
+}
+
+// Reason summarizes the disagreements with the first one of each kind, e.g. for a low-confidence flag
+func (r Result) Reason() string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, disagreement := range r.Disagreements {
+		if !seen[disagreement.Kind] {
+			seen[disagreement.Kind] = true
+			parts = append(parts, disagreement.String())
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Checksum fingerprints candles by session date and OHLC rounded to cents
+// Volume is left out since providers count it differently (consolidated or per exchange)
+func Checksum(candles []models.Candle) string {
+	hash := sha256.New()
+	for _, candle := range candles {
+		fmt.Fprintf(hash, "%s|%.2f|%.2f|%.2f|%.2f\n", candle.Date.Format("2006-01-02"), candle.Open, candle.High, candle.Low, candle.Close)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// Compare checks the newest window candles of primary against the same sessions of secondary
+// Closes further apart than tolerance (a fraction, e.g. 0.02) disagree, and a series whose newest session is
+// older than the other's is stale; sessions are matched by calendar date, so timezones do not matter
+func Compare(symbol string, primary, secondary []models.Candle, window int, tolerance float64) Result {
+	result := Result{Symbol: symbol}
+	if window > 0 && len(primary) > window {
+		primary = primary[len(primary)-window:]
+	}
+	if len(primary) == 0 {
+		return result
+	}
+
+	secondaryByDay := make(map[string]models.Candle, len(secondary))
+	for _, candle := range secondary {
+		secondaryByDay[day(candle.Date)] = candle
+	}
+	var primaryCommon, secondaryCommon []models.Candle
+	for _, candle := range primary {
+		other, ok := secondaryByDay[day(candle.Date)]
+		if !ok {
+			continue
+		}
+		primaryCommon, secondaryCommon = append(primaryCommon, candle), append(secondaryCommon, other)
+	}
+	result.Compared = len(primaryCommon)
+	result.PrimaryChecksum, result.SecondaryChecksum = Checksum(primaryCommon), Checksum(secondaryCommon)
+
+	if result.PrimaryChecksum != result.SecondaryChecksum {
+		for i, candle := range primaryCommon {
+			if kind := classify(candle.Close, secondaryCommon[i].Close, tolerance); kind != "" {
+				result.Disagreements = append(result.Disagreements,
+					Disagreement{Kind: kind, Date: candle.Date, Primary: candle.Close, Secondary: secondaryCommon[i].Close})
+			}
+		}
+	}
+
+	// The newest session of either side must be known to the other
+	newest := primary[len(primary)-1]
+	if _, ok := secondaryByDay[day(newest.Date)]; !ok {
+		result.Disagreements = append(result.Disagreements, Disagreement{Kind: StaleData, Date: newest.Date, Primary: newest.Close})
+	}
+	if len(secondary) > 0 {
+		if latest := secondary[len(secondary)-1]; day(latest.Date) > day(newest.Date) {
+			result.Disagreements = append(result.Disagreements, Disagreement{Kind: StaleData, Date: latest.Date, Secondary: latest.Close})
+		}
+	}
+	return result
+}
+
+// classify returns the kind of disagreement between two closes of one session, or "" when they agree
+func classify(primary, secondary, tolerance float64) string {
+	if primary <= 0 || secondary <= 0 {
+		return PriceMismatch
+	}
+	if math.Abs(primary/secondary-1) <= tolerance {
+		return ""
+	}
+	for _, ratio := range splitRatios {
+		for _, observed := range []float64{primary / secondary, secondary / primary} {
+			if math.Abs(observed/ratio-1) <= 0.02 {
+				return SplitMismatch
+			}
+		}
+	}
+	return PriceMismatch
+}
+
+// day returns the calendar date of a session in its own timezone
+func day(date time.Time) string {
+	return date.Format("2006-01-02")
+}
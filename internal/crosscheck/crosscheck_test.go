@@ -0,0 +1,71 @@
+package crosscheck
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+)
+
+// series builds one daily candle per close starting on 2024-06-03 in the given timezone
+func series(location *time.Location, closes ...float64) []models.Candle {
+	candles := make([]models.Candle, len(closes))
+	for i, close := range closes {
+		date := time.Date(2024, 6, 3+i, 0, 0, 0, 0, location)
+		candles[i] = models.Candle{Date: date, Open: close, High: close + 1, Low: close - 1, Close: close, Volume: int64(1000 * (i + 1))}
+	}
+	return candles
+}
+
+func TestCompareAgreeingProviders(t *testing.T) {
+	eastern, _ := time.LoadLocation("America/New_York")
+	primary := series(eastern, 100, 101, 102, 103)
+	secondary := series(time.UTC, 100, 101, 102, 103)
+	for i := range secondary {
+		secondary[i].Volume *= 3 // Volume is counted differently and does not matter
+	}
+
+	result := Compare("AAPL", primary, secondary, 3, 0.01)
+	if !result.Agrees() || result.Compared != 3 {
+		t.Errorf("result = %+v, want 3 agreeing sessions", result)
+	}
+	if result.PrimaryChecksum != result.SecondaryChecksum || result.PrimaryChecksum == "" {
+		t.Errorf("checksums %q and %q, want equal", result.PrimaryChecksum, result.SecondaryChecksum)
+	}
+}
+
+func TestCompareFindsSplitsPricesAndStaleData(t *testing.T) {
+	primary := series(time.UTC, 400, 404, 101, 102, 103)
+	secondary := series(time.UTC, 100, 101, 101, 110)                               // Adjusted for a 4-for-1 split, one bad close, and a session short
+	secondary = append(secondary, series(time.UTC, 0, 0, 0, 0, 0, 104, 105)[5:]...) // Two sessions newer than the primary
+
+	result := Compare("AAPL", primary, secondary, 0, 0.02)
+	kinds := make(map[string]int)
+	for _, disagreement := range result.Disagreements {
+		kinds[disagreement.Kind]++
+	}
+	if kinds[SplitMismatch] != 2 || kinds[PriceMismatch] != 1 || kinds[StaleData] != 2 {
+		t.Fatalf("disagreements = %+v, want 2 split, 1 price, and 2 stale", result.Disagreements)
+	}
+	reason := result.Reason()
+	for _, text := range []string{"split mismatch on 2024-06-03", "price mismatch on 2024-06-06", "stale data: the checking provider lacks 2024-06-07"} {
+		if !strings.Contains(reason, text) {
+			t.Errorf("reason %q does not contain %q", reason, text)
+		}
+	}
+	if last := result.Disagreements[len(result.Disagreements)-1]; last.String() != "stale data: the scanning provider lacks 2024-06-09" {
+		t.Errorf("last disagreement = %q, want the primary missing the checking provider's newest session", last)
+	}
+	if strings.Count(reason, "split mismatch") != 1 || strings.Count(reason, "stale data") != 1 {
+		t.Errorf("reason %q repeats a kind", reason)
+	}
+}
+
+func TestCompareCheckingProviderBehind(t *testing.T) {
+	primary := series(time.UTC, 100, 101, 102)
+	result := Compare("AAPL", primary, primary[:2], 0, 0.02)
+	if len(result.Disagreements) != 1 || result.Disagreements[0].Kind != StaleData || result.Disagreements[0].Primary != 102 {
+		t.Errorf("disagreements = %+v, want the checking provider missing the newest session", result.Disagreements)
+	}
+}
@@ -0,0 +1,63 @@
+package distributed
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"sync/atomic"
+	"time"
+)
+
+// Coordinator fetches candles through the worker pool instead of calling the data provider itself
+// It satisfies the processor's candle source, so the rest of the scan runs unchanged on the coordinator
+type Coordinator struct {
+	queue     *Queue        // Queue shared with the workers
+	timeframe string        // Candle timeframe requested from the workers
+	timeout   time.Duration // Longest wait for a worker to answer one job
+	runID     string        // Random identifier keeping job IDs unique across coordinators
+	sequence  int64         // Jobs submitted so far (updated atomically)
+}
+
+// NewCoordinator creates a coordinator that waits at most timeout for each symbol
+func NewCoordinator(queue *Queue, timeframe string, timeout time.Duration) *Coordinator {
+	id := make([]byte, 6)
+	rand.Read(id)
+	return &Coordinator{
+		queue:     queue,                  // Store the shared queue
+		timeframe: timeframe,              // Store the timeframe sent with every job
+		timeout:   timeout,                // Store the per-job timeout
+		runID:     hex.EncodeToString(id), // Generate the coordinator identifier
+	}
+}
+
+// FetchStockData submits a fetch job for the symbol and blocks until a worker replies or ctx is done
+func (c *Coordinator) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	jobID := fmt.Sprintf("%s-%d", c.runID, atomic.AddInt64(&c.sequence, 1))
+	job := Job{
+		ID:         jobID,
+		Symbol:     symbol,
+		Timeframe:  c.timeframe,
+		OutputSize: outputSize,
+		ReplyTo:    c.queue.replyKey(jobID),
+		Deadline:   time.Now().Add(c.timeout),
+	}
+
+	if err := c.queue.Submit(ctx, job); err != nil {
+		return models.CandleData{}, err
+	}
+	reply, err := c.queue.Await(ctx, job)
+	if err != nil {
+		return models.CandleData{}, err
+	}
+	if err := reply.Err(); err != nil {
+		return models.CandleData{}, err // Report the worker's fetch error, keeping rejected symbols and quota exhaustion recognizable
+	}
+	return models.CandleData{Candles: reply.Candles}, nil
+}
+
+// Jobs returns the number of jobs submitted so far
+func (c *Coordinator) Jobs() int {
+	return int(atomic.LoadInt64(&c.sequence))
+}
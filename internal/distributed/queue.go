@@ -0,0 +1,181 @@
+// Package distributed splits a scan between a coordinator and remote fetch workers over a Redis queue
+// Workers fetch candles with their own API keys, so a large universe is not limited by one key's rate limit
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/models"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Job asks a worker to fetch the candles of one symbol
+type Job struct {
+	ID         string    `json:"id"`          // Unique job identifier (run ID and sequence number)
+	Symbol     string    `json:"symbol"`      // Stock ticker symbol
+	Timeframe  string    `json:"timeframe"`   // Candle timeframe the coordinator scans
+	OutputSize int       `json:"output_size"` // Number of candles to fetch
+	ReplyTo    string    `json:"reply_to"`    // Redis list the reply is pushed to
+	Deadline   time.Time `json:"deadline"`    // Time after which the coordinator no longer waits for the reply
+}
+
+// Reply carries the candles, or the fetch error, of one job back to the coordinator
+type Reply struct {
+	JobID   string          `json:"job_id"`          // Identifier of the job this reply answers
+	Worker  string          `json:"worker"`          // Name of the worker that fetched the candles
+	Candles []models.Candle `json:"candles"`         // Fetched candles sorted by date
+	Error   string          `json:"error,omitempty"` // Fetch error (empty on success)
+	Kind    string          `json:"kind,omitempty"`  // Kind of the fetch error the coordinator reacts to (empty for other errors)
+}
+
+// Fetch error kinds carried in a reply, since the JSON message keeps only the error text
+const (
+	ErrorKindSymbolRejected = "symbol_rejected" // The provider does not know the symbol
+	ErrorKindQuotaExhausted = "quota_exhausted" // The worker's daily request quota is used up
+)
+
+// SetError records a fetch error in the reply along with its kind
+func (r *Reply) SetError(err error) {
+	r.Error = err.Error()
+	switch {
+	case errors.Is(err, data.ErrSymbolRejected):
+		r.Kind = ErrorKindSymbolRejected
+	case errors.Is(err, data.ErrQuotaExhausted):
+		r.Kind = ErrorKindQuotaExhausted
+	}
+}
+
+// Err rebuilds the worker's fetch error so errors.Is matches the sentinel of its kind; nil on success
+func (r Reply) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+	switch r.Kind {
+	case ErrorKindSymbolRejected:
+		return remoteError{message: r.Error, kind: data.ErrSymbolRejected}
+	case ErrorKindQuotaExhausted:
+		return remoteError{message: r.Error, kind: data.ErrQuotaExhausted}
+	}
+	return errors.New(r.Error)
+}
+
+// remoteError is a worker's fetch error whose text already names its sentinel
+type remoteError struct {
+	message string // Error text reported by the worker
+	kind    error  // Sentinel the error wrapped on the worker
+}
+
+func (e remoteError) Error() string { return e.message }
+
+func (e remoteError) Unwrap() error { return e.kind }
+
+// Queue is the Redis list pair shared by a coordinator and its workers
+// Jobs go through one list consumed by every worker; each job's reply goes to its own short-lived list
+type Queue struct {
+	client *redis.Client // Redis connection pool
+	prefix string        // Key prefix separating independent deployments on one Redis server
+}
+
+// NewQueue connects to the Redis server at url (redis://[user:password@]host:port/db)
+func NewQueue(url, prefix string) (*Queue, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid queue URL: %v", err)
+	}
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to queue: %v", err)
+	}
+	return &Queue{client: client, prefix: prefix}, nil
+}
+
+// jobsKey is the list every worker pops jobs from
+func (q *Queue) jobsKey() string {
+	return q.prefix + ":jobs"
+}
+
+// replyKey is the list the reply to a job is pushed to
+func (q *Queue) replyKey(jobID string) string {
+	return q.prefix + ":reply:" + jobID
+}
+
+// Submit enqueues a job; jobs are consumed in submission order
+func (q *Queue) Submit(ctx context.Context, job Job) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %v", err)
+	}
+	if err := q.client.LPush(ctx, q.jobsKey(), raw).Err(); err != nil {
+		return fmt.Errorf("failed to submit job for %s: %v", job.Symbol, err)
+	}
+	return nil
+}
+
+// Next waits up to wait for the next job; ok is false when none arrived in time
+func (q *Queue) Next(ctx context.Context, wait time.Duration) (job Job, ok bool, err error) {
+	values, err := q.client.BRPop(ctx, wait, q.jobsKey()).Result()
+	if errors.Is(err, redis.Nil) {
+		return Job{}, false, nil
+	}
+	if err != nil {
+		return Job{}, false, fmt.Errorf("failed to receive job: %v", err)
+	}
+	if err := json.Unmarshal([]byte(values[1]), &job); err != nil {
+		return Job{}, false, fmt.Errorf("failed to decode job: %v", err)
+	}
+	return job, true, nil
+}
+
+// Reply pushes the reply to a job; the reply list expires shortly after the job's deadline
+func (q *Queue) Reply(ctx context.Context, job Job, reply Reply) error {
+	raw, err := json.Marshal(reply)
+	if err != nil {
+		return fmt.Errorf("failed to encode reply: %v", err)
+	}
+	pipe := q.client.TxPipeline()
+	pipe.LPush(ctx, job.ReplyTo, raw)
+	pipe.ExpireAt(ctx, job.ReplyTo, job.Deadline.Add(time.Minute))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to reply to job %s: %v", job.ID, err)
+	}
+	return nil
+}
+
+// Await waits for the reply to a job until its deadline
+func (q *Queue) Await(ctx context.Context, job Job) (Reply, error) {
+	wait := time.Until(job.Deadline)
+	if wait <= 0 {
+		return Reply{}, fmt.Errorf("job %s expired before it was awaited", job.ID)
+	}
+	values, err := q.client.BRPop(ctx, wait, job.ReplyTo).Result()
+	if errors.Is(err, redis.Nil) {
+		return Reply{}, fmt.Errorf("no worker answered for %s within %v", job.Symbol, wait.Round(time.Second))
+	}
+	if err != nil {
+		return Reply{}, fmt.Errorf("failed to receive reply for %s: %v", job.Symbol, err)
+	}
+	var reply Reply
+	if err := json.Unmarshal([]byte(values[1]), &reply); err != nil {
+		return Reply{}, fmt.Errorf("failed to decode reply for %s: %v", job.Symbol, err)
+	}
+	return reply, nil
+}
+
+// Pending returns the number of jobs waiting for a worker
+func (q *Queue) Pending(ctx context.Context) (int64, error) {
+	return q.client.LLen(ctx, q.jobsKey()).Result()
+}
+
+// Close releases the Redis connections
+func (q *Queue) Close() error {
+	return q.client.Close()
+}
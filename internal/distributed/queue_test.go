@@ -0,0 +1,52 @@
+package distributed_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/distributed"
+	"testing"
+)
+
+func TestReplyKeepsTheKindOfFetchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"symbol rejected", fmt.Errorf("API error: unknown symbol: %w", data.ErrSymbolRejected), data.ErrSymbolRejected},
+		{"quota exhausted", fmt.Errorf("request quota reached: %w", data.ErrQuotaExhausted), data.ErrQuotaExhausted},
+		{"other", errors.New("connection reset"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := distributed.Reply{JobID: "run-1"}
+			sent.SetError(tt.err)
+			encoded, err := json.Marshal(sent)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var received distributed.Reply
+			if err := json.Unmarshal(encoded, &received); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			got := received.Err()
+			if got == nil || got.Error() != tt.err.Error() {
+				t.Fatalf("Err() = %v, want %q", got, tt.err.Error())
+			}
+			for _, sentinel := range []error{data.ErrSymbolRejected, data.ErrQuotaExhausted} {
+				if want := sentinel == tt.want; errors.Is(got, sentinel) != want {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", got, sentinel, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReplyWithoutErrorHasNoErr(t *testing.T) {
+	if err := (distributed.Reply{JobID: "run-1"}).Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
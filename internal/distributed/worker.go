@@ -0,0 +1,101 @@
+package distributed
+
+import (
+	"context"
+	"github.com/erhankrygt/sapan/data"
+	"log"
+	"sync"
+	"time"
+)
+
+// Worker consumes fetch jobs and answers them with candles from the data provider
+// Each worker fetches with its own API key and paces itself with its own request delay
+type Worker struct {
+	queue          *Queue                            // Queue shared with the coordinator
+	name           string                            // Worker name reported in replies
+	apiKey         string                            // API key of this worker
+	apiURL         string                            // Data provider base URL
+	requestDelay   time.Duration                     // Pause between two fetches
+	adjustedSeries bool                              // Fetch split- and dividend-adjusted series
+	adjustedPrices bool                              // Convert candles to adjusted prices
+	fetchers       map[string]*data.StockDataFetcher // Fetchers per timeframe, created on first use
+	mutex          sync.Mutex                        // Protects fetchers
+}
+
+// NewWorker creates a worker fetching with the given API key
+func NewWorker(queue *Queue, name, apiKey, apiURL string, requestDelay time.Duration) *Worker {
+	return &Worker{
+		queue:        queue,                                   // Store the shared queue
+		name:         name,                                    // Store the worker name
+		apiKey:       apiKey,                                  // Store the API key
+		apiURL:       apiURL,                                  // Store the API URL
+		requestDelay: requestDelay,                            // Store the request delay
+		fetchers:     make(map[string]*data.StockDataFetcher), // Initialize the fetcher cache
+	}
+}
+
+// SetAdjusted makes the worker fetch adjusted series, optionally converted to adjusted prices (see StockDataFetcher.SetAdjusted)
+func (w *Worker) SetAdjusted(series, prices bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.adjustedSeries, w.adjustedPrices = series, prices
+}
+
+// fetcher returns the fetcher for a timeframe
+func (w *Worker) fetcher(timeframe string) *data.StockDataFetcher {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	fetcher, ok := w.fetchers[timeframe]
+	if !ok {
+		fetcher = data.NewStockDataFetcher(w.apiKey, w.apiURL, timeframe)
+		fetcher.SetAdjusted(w.adjustedSeries, w.adjustedPrices)
+		w.fetchers[timeframe] = fetcher
+	}
+	return fetcher
+}
+
+// Run answers jobs until ctx is cancelled and returns the number of jobs answered
+// Jobs whose deadline passed while queued are dropped, since their coordinator has stopped waiting
+func (w *Worker) Run(ctx context.Context) int {
+	answered := 0
+	for ctx.Err() == nil {
+		job, ok, err := w.queue.Next(ctx, 5*time.Second)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Worker %s: %v", w.name, err)
+				time.Sleep(time.Second) // Back off while the queue is unreachable
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if time.Now().After(job.Deadline) {
+			log.Printf("Worker %s: Dropping expired job for %s", w.name, job.Symbol)
+			continue
+		}
+
+		// The coordinator stops waiting at the job's deadline, so the fetch gets no longer than that
+		reply := Reply{JobID: job.ID, Worker: w.name}
+		fetchCtx, cancel := context.WithDeadline(ctx, job.Deadline)
+		candleData, err := w.fetcher(job.Timeframe).FetchStockData(fetchCtx, job.Symbol, job.OutputSize)
+		cancel()
+		if err != nil {
+			reply.SetError(err)
+			log.Printf("Worker %s: Failed to fetch data for %s: %v", w.name, job.Symbol, err)
+		} else {
+			reply.Candles = candleData.Candles
+		}
+		if err := w.queue.Reply(context.Background(), job, reply); err != nil {
+			log.Printf("Worker %s: %v", w.name, err)
+		}
+		answered++
+
+		// Respect this key's rate limit before taking the next job
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.requestDelay):
+		}
+	}
+	return answered
+}
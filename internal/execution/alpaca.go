@@ -0,0 +1,283 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alpaca trading API base URLs
+const (
+	alpacaPaperURL = "https://paper-api.alpaca.markets" // Paper trading account
+	alpacaLiveURL  = "https://api.alpaca.markets"       // Live trading account
+)
+
+// AlpacaClient is a minimal Broker for the Alpaca trading API
+type AlpacaClient struct {
+	baseURL   string       // Trading API base URL (paper or live)
+	keyID     string       // API key ID
+	secretKey string       // API secret key
+	client    *http.Client // HTTP client with a request timeout
+}
+
+// NewAlpacaClient creates an Alpaca client for the paper account, or the live account when paper is false
+func NewAlpacaClient(keyID, secretKey string, paper bool) *AlpacaClient {
+	baseURL := alpacaLiveURL
+	if paper {
+		baseURL = alpacaPaperURL
+	}
+	return &AlpacaClient{
+		baseURL:   baseURL,                                 // Select the trading environment
+		keyID:     keyID,                                   // Store the key ID
+		secretKey: secretKey,                               // Store the secret key
+		client:    &http.Client{Timeout: 15 * time.Second}, // Bound every request
+	}
+}
+
+// alpacaAccount mirrors the fields of GET /v2/account used by SAPAN
+type alpacaAccount struct {
+	Equity      string `json:"equity"`       // Account equity as a decimal string
+	BuyingPower string `json:"buying_power"` // Buying power as a decimal string
+}
+
+// alpacaPosition mirrors the fields of GET /v2/positions used by SAPAN
+type alpacaPosition struct {
+	Symbol        string `json:"symbol"`          // Stock ticker symbol
+	Qty           string `json:"qty"`             // Signed quantity as a decimal string
+	Side          string `json:"side"`            // long or short
+	AvgEntryPrice string `json:"avg_entry_price"` // Average fill price
+	MarketValue   string `json:"market_value"`    // Current value of the position
+}
+
+// alpacaOrder mirrors the fields of the /v2/orders resource used by SAPAN
+type alpacaOrder struct {
+	ID            string        `json:"id,omitempty"`               // Broker order ID (response only)
+	Status        string        `json:"status,omitempty"`           // Broker order status (response only)
+	FilledQty     string        `json:"filled_qty,omitempty"`       // Filled quantity (response only)
+	FilledAvg     string        `json:"filled_avg_price,omitempty"` // Average fill price (response only)
+	FilledAt      *time.Time    `json:"filled_at,omitempty"`        // Time the order filled (response only)
+	ClientOrderID string        `json:"client_order_id"`            // Idempotency key
+	Symbol        string        `json:"symbol"`                     // Stock ticker symbol
+	Qty           string        `json:"qty"`                        // Quantity as a decimal string
+	Side          string        `json:"side"`                       // buy or sell
+	Type          string        `json:"type"`                       // Order type of the entry leg
+	TimeInForce   string        `json:"time_in_force"`              // Order lifetime
+	StopPrice     string        `json:"stop_price,omitempty"`       // Entry trigger price
+	OrderClass    string        `json:"order_class,omitempty"`      // bracket
+	TakeProfit    *alpacaLeg    `json:"take_profit,omitempty"`      // Take-profit leg
+	StopLoss      *alpacaLeg    `json:"stop_loss,omitempty"`        // Stop-loss leg
+	Legs          []alpacaOrder `json:"legs,omitempty"`             // Exit leg orders of a bracket (response only, with nested=true)
+}
+
+// alpacaLeg is one exit leg of a bracket order
+type alpacaLeg struct {
+	LimitPrice string `json:"limit_price,omitempty"` // Take-profit limit price
+	StopPrice  string `json:"stop_price,omitempty"`  // Stop-loss trigger price
+}
+
+// Name returns the broker name used in logs
+func (c *AlpacaClient) Name() string {
+	if c.baseURL == alpacaPaperURL {
+		return "alpaca paper"
+	}
+	return "alpaca LIVE"
+}
+
+// Account returns the account equity and buying power
+func (c *AlpacaClient) Account() (Account, error) {
+	var raw alpacaAccount
+	if err := c.do(http.MethodGet, "/v2/account", nil, &raw); err != nil {
+		return Account{}, fmt.Errorf("failed to load Alpaca account: %v", err)
+	}
+	equity, _ := strconv.ParseFloat(raw.Equity, 64)
+	buyingPower, _ := strconv.ParseFloat(raw.BuyingPower, 64)
+	return Account{Equity: equity, BuyingPower: buyingPower}, nil
+}
+
+// Positions returns the open positions of the account
+func (c *AlpacaClient) Positions() ([]Position, error) {
+	var raw []alpacaPosition
+	if err := c.do(http.MethodGet, "/v2/positions", nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load Alpaca positions: %v", err)
+	}
+
+	positions := make([]Position, 0, len(raw))
+	for _, item := range raw {
+		quantity, _ := strconv.ParseFloat(item.Qty, 64)
+		avgEntry, _ := strconv.ParseFloat(item.AvgEntryPrice, 64)
+		marketValue, _ := strconv.ParseFloat(item.MarketValue, 64)
+		side := watcher.LongSide
+		if item.Side == "short" {
+			side = watcher.ShortSide
+		}
+		positions = append(positions, Position{
+			Symbol:        item.Symbol,
+			Side:          side,
+			Quantity:      int(math.Abs(quantity)),
+			AvgEntryPrice: avgEntry,
+			MarketValue:   math.Abs(marketValue),
+		})
+	}
+	return positions, nil
+}
+
+// SubmitOrder submits a good-till-cancelled stop entry with stop-loss and take-profit legs
+func (c *AlpacaClient) SubmitOrder(order BracketOrder) (Order, error) {
+	request := alpacaOrder{
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Qty:           strconv.Itoa(order.Quantity),
+		Side:          order.Side,
+		Type:          "stop",
+		TimeInForce:   "gtc",
+		StopPrice:     formatPrice(order.EntryStop),
+		OrderClass:    "bracket",
+		TakeProfit:    &alpacaLeg{LimitPrice: formatPrice(order.TakeProfit)},
+		StopLoss:      &alpacaLeg{StopPrice: formatPrice(order.StopLoss)},
+	}
+
+	var response alpacaOrder
+	if err := c.do(http.MethodPost, "/v2/orders", request, &response); err != nil {
+		return Order{}, fmt.Errorf("failed to submit order for %s: %v", order.Symbol, err)
+	}
+	return response.toOrder(), nil
+}
+
+// CancelOrder cancels a working order
+func (c *AlpacaClient) CancelOrder(orderID string) error {
+	if err := c.do(http.MethodDelete, "/v2/orders/"+url.PathEscape(orderID), nil, nil); err != nil {
+		return fmt.Errorf("failed to cancel order %s: %v", orderID, err)
+	}
+	return nil
+}
+
+// OrderStatus returns the current state of an order
+func (c *AlpacaClient) OrderStatus(orderID string) (Order, error) {
+	var response alpacaOrder
+	if err := c.do(http.MethodGet, "/v2/orders/"+url.PathEscape(orderID), nil, &response); err != nil {
+		return Order{}, fmt.Errorf("failed to load order %s: %v", orderID, err)
+	}
+	return response.toOrder(), nil
+}
+
+// FindOrder returns the current state of the order submitted with a client order ID
+func (c *AlpacaClient) FindOrder(clientOrderID string) (Order, error) {
+	var response alpacaOrder
+	path := "/v2/orders:by_client_order_id?client_order_id=" + url.QueryEscape(clientOrderID)
+	if err := c.do(http.MethodGet, path, nil, &response); err != nil {
+		return Order{}, fmt.Errorf("failed to load order %s: %v", clientOrderID, err)
+	}
+	return response.toOrder(), nil
+}
+
+// ClosePosition cancels the open exit legs of a filled bracket order and closes its position at market
+// Alpaca has no time stop, so the executor closes positions this way once their holding period has elapsed
+func (c *AlpacaClient) ClosePosition(orderID string) error {
+	var parent alpacaOrder
+	if err := c.do(http.MethodGet, "/v2/orders/"+url.PathEscape(orderID)+"?nested=true", nil, &parent); err != nil {
+		return fmt.Errorf("failed to load order %s: %v", orderID, err)
+	}
+	for _, leg := range parent.Legs {
+		if alpacaStatus(leg.Status) != OrderAccepted {
+			continue
+		}
+		if err := c.CancelOrder(leg.ID); err != nil {
+			return err // Closing while a leg still holds the shares would be rejected
+		}
+	}
+	if err := c.do(http.MethodDelete, "/v2/positions/"+url.PathEscape(parent.Symbol), nil, nil); err != nil {
+		return fmt.Errorf("failed to close position in %s: %v", parent.Symbol, err)
+	}
+	return nil
+}
+
+// toOrder converts an Alpaca order into the broker-agnostic order type
+func (o alpacaOrder) toOrder() Order {
+	quantity, _ := strconv.ParseFloat(o.Qty, 64)
+	filled, _ := strconv.ParseFloat(o.FilledQty, 64)
+	filledPrice, _ := strconv.ParseFloat(o.FilledAvg, 64)
+	var filledAt time.Time
+	if o.FilledAt != nil {
+		filledAt = o.FilledAt.UTC()
+	}
+	return Order{
+		ID:             o.ID,
+		ClientOrderID:  o.ClientOrderID,
+		Symbol:         o.Symbol,
+		Side:           o.Side,
+		Quantity:       int(quantity),
+		Status:         alpacaStatus(o.Status),
+		FilledQuantity: int(filled),
+		FilledPrice:    filledPrice,
+		FilledAt:       filledAt,
+	}
+}
+
+// alpacaStatus maps Alpaca's order statuses onto the shared Order* values
+func alpacaStatus(status string) string {
+	switch status {
+	case "filled":
+		return OrderFilled
+	case "canceled", "expired", "replaced", "done_for_day":
+		return OrderCanceled
+	case "rejected", "suspended":
+		return OrderRejected
+	}
+	return OrderAccepted // new, accepted, held, pending_new, partially_filled, ...
+}
+
+// do sends an authenticated request and decodes the JSON response into out
+func (c *AlpacaClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("APCA-API-KEY-ID", c.keyID)
+	req.Header.Set("APCA-API-SECRET-KEY", c.secretKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// formatPrice renders a price with the precision Alpaca accepts (two decimals at or above $1, four below)
+func formatPrice(price float64) string {
+	return models.FormatPrice(price)
+}
@@ -0,0 +1,70 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import "time"
+
+// Order status values shared by every broker implementation
+const (
+	OrderAccepted  = "accepted"  // Order is working at the broker
+	OrderFilled    = "filled"    // Entry leg has been filled
+	OrderCanceled  = "canceled"  // Order was canceled before it filled
+	OrderRejected  = "rejected"  // Broker refused the order
+	OrderCompleted = "completed" // Position was closed by the stop-loss or take-profit leg
+	OrderTimedOut  = "timed_out" // Position was closed at the close because its time stop elapsed
+)
+
+// Broker is the trading account the execution, paper-trading, and portfolio modules work against
+// Implementations exist for Alpaca and for a local paper account; tests can supply their own
+type Broker interface {
+	Name() string                                  // Broker name used in logs
+	Account() (Account, error)                     // Current equity and buying power
+	Positions() ([]Position, error)                // Open positions
+	SubmitOrder(order BracketOrder) (Order, error) // Submit a bracket order
+	CancelOrder(orderID string) error              // Cancel a working order
+	OrderStatus(orderID string) (Order, error)     // Current state of an order
+	FindOrder(clientOrderID string) (Order, error) // Current state of the order submitted with a client order ID
+	ClosePosition(orderID string) error            // Cancel the exit legs of a filled order and close its position at market
+}
+
+// Account holds the account figures used for position sizing
+type Account struct {
+	Equity      float64 `json:"equity"`       // Current account equity
+	BuyingPower float64 `json:"buying_power"` // Buying power available for new orders
+}
+
+// Position is an open position at the broker
+type Position struct {
+	Symbol        string  `json:"symbol"`             // Stock ticker symbol
+	Side          string  `json:"side"`               // Long or Short
+	Quantity      int     `json:"quantity"`           // Shares held (always positive)
+	OrderID       string  `json:"order_id,omitempty"` // Order that opened the position (paper broker only)
+	AvgEntryPrice float64 `json:"avg_entry_price"`    // Average fill price
+	MarketValue   float64 `json:"market_value"`       // Current value of the position
+}
+
+// BracketOrder is an entry stop order with attached stop-loss and take-profit legs
+type BracketOrder struct {
+	ClientOrderID string  `json:"client_order_id"`     // Idempotency key; brokers reject a second order with the same ID
+	Symbol        string  `json:"symbol"`              // Stock ticker symbol
+	Side          string  `json:"side"`                // "buy" for Long setups, "sell" for Short setups
+	Quantity      int     `json:"quantity"`            // Whole shares to trade
+	EntryStop     float64 `json:"entry_stop"`          // Entry trigger price
+	StopLoss      float64 `json:"stop_loss"`           // Protective stop-loss price
+	TakeProfit    float64 `json:"take_profit"`         // Profit target price
+	TimeStop      int     `json:"time_stop,omitempty"` // Candles after the fill candle before the position exits at the close (0 = none)
+}
+
+// Order is the broker's view of a submitted order
+type Order struct {
+	ID             string    `json:"id"`                   // Broker order ID
+	ClientOrderID  string    `json:"client_order_id"`      // Idempotency key given at submission
+	Symbol         string    `json:"symbol"`               // Stock ticker symbol
+	Side           string    `json:"side"`                 // buy or sell
+	Quantity       int       `json:"quantity"`             // Shares ordered
+	Status         string    `json:"status"`               // One of the Order* status values
+	FilledQuantity int       `json:"filled_quantity"`      // Shares filled so far
+	FilledPrice    float64   `json:"filled_price"`         // Average fill price (0 when unfilled)
+	FilledAt       time.Time `json:"filled_at,omitempty"`  // Time the entry leg filled (zero when unfilled)
+	ExitPrice      float64   `json:"exit_price,omitempty"` // Price the position was closed at (0 while open; paper broker only)
+}
@@ -0,0 +1,206 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"math"
+	"strings"
+	"time"
+)
+
+// RiskLimits bound the size of every order the executor submits
+type RiskLimits struct {
+	RiskPercent      float64 // Account percentage lost if the stop is hit
+	MaxPositionValue float64 // Maximum notional value per position (0 = no cap)
+	MinScore         float64 // Setups scoring below this threshold are not traded
+}
+
+// Execution records what happened to one signal
+type Execution struct {
+	Symbol   string // Stock ticker symbol
+	Side     string // Long or Short
+	Quantity int    // Shares ordered (0 when skipped)
+	OrderID  string // Broker order ID (empty when skipped or failed)
+	Skipped  string // Reason the signal was not traded (empty when an order was submitted)
+}
+
+// Executor sizes and submits bracket orders for confirmed signals
+type Executor struct {
+	broker    Broker            // Trading account orders are submitted to
+	limits    RiskLimits        // Per-trade risk limits
+	portfolio PortfolioLimits   // Account-wide limits checked before every order
+	sectors   map[string]string // Sector of each known symbol, used for sector exposure
+	timeStops *TimeStopBook     // Book submitted time-stopped orders are recorded in (nil disables time stops)
+}
+
+// NewExecutor creates an executor that trades through the given broker within the given limits
+func NewExecutor(broker Broker, limits RiskLimits) *Executor {
+	return &Executor{
+		broker: broker, // Initialize broker
+		limits: limits, // Set risk limits
+	}
+}
+
+// SetPortfolioLimits sets the account-wide limits and the symbol-to-sector lookup used to enforce them
+func (e *Executor) SetPortfolioLimits(limits PortfolioLimits, sectors map[string]string) {
+	e.portfolio = limits
+	e.sectors = sectors
+}
+
+// SetTimeStopBook records submitted orders with a time stop in book, which ExpireTimeStops works through
+func (e *Executor) SetTimeStopBook(book *TimeStopBook) {
+	e.timeStops = book
+}
+
+// Execute submits one bracket order per signal, sized so a stop-out loses at most RiskPercent of equity
+// Signals that would breach a portfolio limit are skipped; higher-priority signals should come first
+// Orders carry a client order ID derived from symbol, side, and candle date, so re-running a scan never duplicates them
+func (e *Executor) Execute(entries []watcher.WatchListEntry) ([]Execution, error) {
+	account, err := e.broker.Account()
+	if err != nil {
+		return nil, err
+	}
+	positions, err := e.broker.Positions()
+	if err != nil {
+		return nil, err
+	}
+	portfolio := NewPortfolio(account, positions, e.sectors, e.limits.RiskPercent)
+
+	executions := make([]Execution, 0, len(entries))
+	for _, entry := range entries {
+		execution := Execution{Symbol: entry.Symbol, Side: entry.Side}
+
+		quantity, reason := PositionSize(entry, account.Equity, e.limits)
+		if reason != "" {
+			execution.Skipped = reason
+			executions = append(executions, execution)
+			continue
+		}
+		if value := float64(quantity) * entry.Entry; value > account.BuyingPower {
+			execution.Skipped = fmt.Sprintf("position value %.2f exceeds buying power %.2f", value, account.BuyingPower)
+			executions = append(executions, execution)
+			continue
+		}
+
+		if reason := portfolio.Check(entry, quantity, e.portfolio); reason != "" {
+			execution.Skipped = reason
+			executions = append(executions, execution)
+			continue
+		}
+
+		order := BracketOrder{
+			ClientOrderID: clientOrderID(entry),
+			Symbol:        entry.Symbol,
+			Side:          orderSide(entry.Side),
+			Quantity:      quantity,
+			EntryStop:     entry.Entry,
+			StopLoss:      entry.Stop,
+			TakeProfit:    entry.Target,
+			TimeStop:      entry.TimeStop,
+		}
+		submitted, err := e.broker.SubmitOrder(order)
+		if err != nil {
+			execution.Skipped = err.Error()
+			executions = append(executions, execution)
+			continue
+		}
+
+		execution.Quantity = quantity
+		execution.OrderID = submitted.ID
+		if e.timeStops != nil {
+			e.timeStops.Add(TimeStopRecord{
+				ClientOrderID: order.ClientOrderID,
+				Symbol:        entry.Symbol,
+				Side:          entry.Side,
+				TimeStop:      order.TimeStop,
+				SubmittedAt:   time.Now().UTC(),
+			})
+		}
+		account.BuyingPower -= float64(quantity) * entry.Entry // Keep later orders within the remaining buying power
+		portfolio.Add(entry, quantity)
+		executions = append(executions, execution)
+	}
+	return executions, nil
+}
+
+// CandleCounter counts the candles of the scanned timeframe that closed after the candle containing since
+type CandleCounter func(since time.Time) int
+
+// ExpireTimeStops closes the positions of filled orders in the time stop book that reached neither their stop nor
+// their target within their time stop, and returns an execution for every position it closed
+// Brokers have no native time stop, so call it on every run; orders are looked up by the client order ID they were
+// submitted with, so it works after the setup left the watch list. Orders that were canceled, rejected, or closed
+// by their bracket legs leave the book, while orders still working stay in it
+func (e *Executor) ExpireTimeStops(closedAfter CandleCounter) ([]Execution, error) {
+	if e.timeStops == nil {
+		return nil, nil
+	}
+	positions, err := e.broker.Positions()
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]bool, len(positions))
+	for _, position := range positions {
+		open[position.Symbol+"|"+position.Side] = true
+	}
+
+	var closed []Execution
+	for _, record := range e.timeStops.Records() {
+		order, err := e.broker.FindOrder(record.ClientOrderID)
+		if err != nil || order.Status == OrderAccepted {
+			continue // Unknown to the broker for now, or still waiting for its entry
+		}
+		if order.Status != OrderFilled || !open[record.Symbol+"|"+record.Side] {
+			e.timeStops.Remove(record.ClientOrderID) // Never filled, or the position is already closed
+			continue
+		}
+		if order.FilledAt.IsZero() || closedAfter(order.FilledAt) < record.TimeStop {
+			continue
+		}
+		if err := e.broker.ClosePosition(order.ID); err != nil {
+			return closed, err
+		}
+		e.timeStops.Remove(record.ClientOrderID)
+		closed = append(closed, Execution{Symbol: record.Symbol, Side: record.Side, Quantity: order.FilledQuantity, OrderID: order.ID})
+	}
+	return closed, nil
+}
+
+// PositionSize returns the whole-share quantity for a signal, or a reason why it must not be traded
+func PositionSize(entry watcher.WatchListEntry, equity float64, limits RiskLimits) (int, string) {
+	if entry.Score < limits.MinScore {
+		return 0, fmt.Sprintf("score %.1f below minimum %.1f", entry.Score, limits.MinScore)
+	}
+	riskPerShare := math.Abs(entry.Entry - entry.Stop)
+	if entry.Entry <= 0 || riskPerShare == 0 || entry.Target <= 0 {
+		return 0, "signal has no complete trade plan"
+	}
+
+	quantity := math.Floor(equity * limits.RiskPercent / 100 / riskPerShare)
+	if limits.MaxPositionValue > 0 {
+		quantity = math.Min(quantity, math.Floor(limits.MaxPositionValue/entry.Entry))
+	}
+	if quantity < 1 {
+		return 0, "risk limits allow less than one share"
+	}
+	return int(quantity), ""
+}
+
+// clientOrderID derives a stable order ID for a signal, e.g. "sapan-AAPL-long-20240105"
+func clientOrderID(entry watcher.WatchListEntry) string {
+	date := entry.CandleDate
+	if date.IsZero() {
+		date = entry.DetectedAt
+	}
+	return fmt.Sprintf("sapan-%s-%s-%s", entry.Symbol, strings.ToLower(entry.Side), date.Format("20060102"))
+}
+
+// orderSide maps a trading side onto the broker's order side
+func orderSide(side string) string {
+	if side == watcher.ShortSide {
+		return "sell"
+	}
+	return "buy"
+}
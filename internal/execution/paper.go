@@ -0,0 +1,306 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"os"
+	"sync"
+	"time"
+)
+
+// PaperBroker is a local simulated account that records orders and positions in a JSON file
+// It never contacts a real broker, which makes it suitable for dry runs and as a stand-in in tests
+// Filled orders are walked forward with CloseBar, which applies their stop-loss, take-profit, and time stop
+type PaperBroker struct {
+	path  string     // JSON file the account state is persisted to (empty keeps it in memory)
+	state paperState // Current account state
+	mutex sync.Mutex // Mutex guarding the state
+}
+
+// paperState is the persisted form of the paper account
+type paperState struct {
+	Equity    float64                 `json:"equity"`    // Account equity
+	NextID    int                     `json:"next_id"`   // Sequence for generated order IDs
+	Orders    map[string]BracketOrder `json:"orders"`    // Submitted bracket orders keyed by order ID
+	Status    map[string]Order        `json:"status"`    // Order state keyed by order ID
+	Positions []Position              `json:"positions"` // Open positions
+	Held      map[string]int          `json:"held"`      // Candles closed since the fill keyed by order ID, the fill candle included
+}
+
+// NewPaperBroker opens the paper account stored at path, creating one with startingEquity when the file does not exist
+func NewPaperBroker(path string, startingEquity float64) (*PaperBroker, error) {
+	broker := &PaperBroker{
+		path: path,
+		state: paperState{
+			Equity: startingEquity,
+			Orders: make(map[string]BracketOrder),
+			Status: make(map[string]Order),
+			Held:   make(map[string]int),
+		},
+	}
+	if path == "" {
+		return broker, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return broker, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read paper account: %v", err)
+	}
+	if err := json.Unmarshal(data, &broker.state); err != nil {
+		return nil, fmt.Errorf("failed to parse paper account %s: %v", path, err)
+	}
+	if broker.state.Held == nil {
+		broker.state.Held = make(map[string]int) // Accounts written before time stops were supported
+	}
+	return broker, nil
+}
+
+// Name returns the broker name used in logs
+func (b *PaperBroker) Name() string {
+	return "paper"
+}
+
+// Account returns the equity and the buying power left after working orders and open positions
+func (b *PaperBroker) Account() (Account, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	committed := 0.0
+	for id, order := range b.state.Status {
+		if order.Status == OrderAccepted {
+			committed += float64(order.Quantity) * b.state.Orders[id].EntryStop
+		}
+	}
+	for _, position := range b.state.Positions {
+		committed += position.MarketValue
+	}
+	return Account{Equity: b.state.Equity, BuyingPower: b.state.Equity - committed}, nil
+}
+
+// Positions returns the open positions
+func (b *PaperBroker) Positions() ([]Position, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]Position(nil), b.state.Positions...), nil
+}
+
+// SubmitOrder records a working bracket order, rejecting a repeated client order ID like a real broker
+func (b *PaperBroker) SubmitOrder(order BracketOrder) (Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, existing := range b.state.Status {
+		if order.ClientOrderID != "" && existing.ClientOrderID == order.ClientOrderID {
+			return Order{}, fmt.Errorf("client order ID %s already exists", order.ClientOrderID)
+		}
+	}
+
+	b.state.NextID++
+	submitted := Order{
+		ID:            fmt.Sprintf("paper-%d", b.state.NextID),
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Quantity:      order.Quantity,
+		Status:        OrderAccepted,
+	}
+	b.state.Orders[submitted.ID] = order
+	b.state.Status[submitted.ID] = submitted
+	return submitted, b.save()
+}
+
+// CancelOrder cancels a working order
+func (b *PaperBroker) CancelOrder(orderID string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	order, ok := b.state.Status[orderID]
+	if !ok {
+		return fmt.Errorf("order %s not found", orderID)
+	}
+	if order.Status != OrderAccepted {
+		return fmt.Errorf("order %s is %s and cannot be canceled", orderID, order.Status)
+	}
+	order.Status = OrderCanceled
+	b.state.Status[orderID] = order
+	return b.save()
+}
+
+// OrderStatus returns the current state of an order
+func (b *PaperBroker) OrderStatus(orderID string) (Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	order, ok := b.state.Status[orderID]
+	if !ok {
+		return Order{}, fmt.Errorf("order %s not found", orderID)
+	}
+	return order, nil
+}
+
+// FindOrder returns the current state of the order submitted with a client order ID
+func (b *PaperBroker) FindOrder(clientOrderID string) (Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, order := range b.state.Status {
+		if clientOrderID != "" && order.ClientOrderID == clientOrderID {
+			return order, nil
+		}
+	}
+	return Order{}, fmt.Errorf("client order ID %s not found", clientOrderID)
+}
+
+// ClosePosition closes the position of a filled order at its last mark, completing the order
+func (b *PaperBroker) ClosePosition(orderID string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index := b.positionIndex(orderID)
+	if index < 0 {
+		return fmt.Errorf("order %s has no open position", orderID)
+	}
+	position := b.state.Positions[index]
+	b.closePosition(orderID, OrderCompleted, position.MarketValue/float64(position.Quantity))
+	return b.save()
+}
+
+// Fill marks a working order as filled at price and opens the matching position
+func (b *PaperBroker) Fill(orderID string, price float64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	order, ok := b.state.Status[orderID]
+	if !ok || order.Status != OrderAccepted {
+		return fmt.Errorf("order %s is not working", orderID)
+	}
+	order.Status = OrderFilled
+	order.FilledQuantity = order.Quantity
+	order.FilledPrice = price
+	order.FilledAt = time.Now().UTC()
+	b.state.Status[orderID] = order
+	b.state.Held[orderID] = 0
+
+	side := watcher.LongSide
+	if order.Side == "sell" {
+		side = watcher.ShortSide
+	}
+	b.state.Positions = append(b.state.Positions, Position{
+		Symbol:        order.Symbol,
+		Side:          side,
+		Quantity:      order.Quantity,
+		OrderID:       orderID,
+		AvgEntryPrice: price,
+		MarketValue:   float64(order.Quantity) * price,
+	})
+	return b.save()
+}
+
+// CloseBar walks the open positions of symbol through one closed candle, starting with the candle the order filled on
+// A candle reaching the stop-loss or take-profit completes the order at that level, checking the stop first like the
+// outcome tracker; a position still open after the close of the TimeStop-th candle after the fill candle exits at that
+// close. Open positions that survive the candle are marked to its close. The exits settle into the account equity.
+func (b *PaperBroker) CloseBar(symbol string, candle models.Candle) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, position := range append([]Position(nil), b.state.Positions...) {
+		if position.Symbol != symbol || position.OrderID == "" {
+			continue
+		}
+		bracket := b.state.Orders[position.OrderID]
+		held := b.state.Held[position.OrderID]
+		switch {
+		case paperHitsStop(position.Side, bracket.StopLoss, candle):
+			b.closePosition(position.OrderID, OrderCompleted, paperStopFill(position.Side, bracket.StopLoss, candle))
+		case paperHitsTarget(position.Side, bracket.TakeProfit, candle):
+			b.closePosition(position.OrderID, OrderCompleted, bracket.TakeProfit)
+		case bracket.TimeStop > 0 && held >= bracket.TimeStop:
+			b.closePosition(position.OrderID, OrderTimedOut, candle.Close)
+		default:
+			b.state.Held[position.OrderID] = held + 1
+			b.state.Positions[b.positionIndex(position.OrderID)].MarketValue = float64(position.Quantity) * candle.Close
+		}
+	}
+	return b.save()
+}
+
+// positionIndex returns the index of the position opened by an order, or -1; callers must hold the mutex
+func (b *PaperBroker) positionIndex(orderID string) int {
+	for i, position := range b.state.Positions {
+		if position.OrderID == orderID {
+			return i
+		}
+	}
+	return -1
+}
+
+// closePosition removes the position of an order, settles its profit or loss, and moves the order to status
+// Callers must hold the mutex and make sure the position exists
+func (b *PaperBroker) closePosition(orderID, status string, exitPrice float64) {
+	index := b.positionIndex(orderID)
+	position := b.state.Positions[index]
+	profit := (exitPrice - position.AvgEntryPrice) * float64(position.Quantity)
+	if position.Side == watcher.ShortSide {
+		profit = -profit
+	}
+	b.state.Equity += profit
+	b.state.Positions = append(b.state.Positions[:index], b.state.Positions[index+1:]...)
+	delete(b.state.Held, orderID)
+
+	order := b.state.Status[orderID]
+	order.Status = status
+	order.ExitPrice = exitPrice
+	b.state.Status[orderID] = order
+}
+
+// paperHitsStop reports whether a candle reaches the stop-loss of a position
+func paperHitsStop(side string, stop float64, candle models.Candle) bool {
+	if side == watcher.ShortSide {
+		return models.ComparePrices(candle.High, stop) >= 0
+	}
+	return models.ComparePrices(candle.Low, stop) <= 0
+}
+
+// paperHitsTarget reports whether a candle reaches the take-profit of a position
+func paperHitsTarget(side string, target float64, candle models.Candle) bool {
+	if side == watcher.ShortSide {
+		return models.ComparePrices(candle.Low, target) <= 0
+	}
+	return models.ComparePrices(candle.High, target) >= 0
+}
+
+// paperStopFill returns the stop-loss fill, using the open when price gapped through the stop
+func paperStopFill(side string, stop float64, candle models.Candle) float64 {
+	if side == watcher.ShortSide && candle.Open > stop {
+		return candle.Open
+	}
+	if side != watcher.ShortSide && candle.Open < stop {
+		return candle.Open
+	}
+	return stop
+}
+
+// save persists the account state when a path is configured; callers must hold the mutex
+func (b *PaperBroker) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(b.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode paper account: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(b.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write paper account: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package execution_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/execution"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// TestPaperBrokerTimeStop checks that a filled paper order exits at the close of the TimeStop-th candle after the fill
+func TestPaperBrokerTimeStop(t *testing.T) {
+	broker, err := execution.NewPaperBroker("", 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := broker.SubmitOrder(execution.BracketOrder{
+		Symbol: "AAPL", Side: "buy", Quantity: 10, EntryStop: 100, StopLoss: 95, TakeProfit: 110, TimeStop: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.Fill(order.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	quiet := []models.Candle{
+		{Open: 99, High: 101, Low: 98, Close: 100.5}, // Fill candle
+		{Open: 100.5, High: 102, Low: 99, Close: 101},
+		{Open: 101, High: 103, Low: 100, Close: 102},
+	}
+	for i, candle := range quiet {
+		if err := broker.CloseBar("AAPL", candle); err != nil {
+			t.Fatal(err)
+		}
+		status, _ := broker.OrderStatus(order.ID)
+		if i < len(quiet)-1 && status.Status != execution.OrderFilled {
+			t.Fatalf("order %s after candle %d, want it still open", status.Status, i)
+		}
+	}
+
+	status, _ := broker.OrderStatus(order.ID)
+	if status.Status != execution.OrderTimedOut || status.ExitPrice != 102 {
+		t.Errorf("order %s at %.2f, want %s at the 102 close", status.Status, status.ExitPrice, execution.OrderTimedOut)
+	}
+	if positions, _ := broker.Positions(); len(positions) != 0 {
+		t.Errorf("%d positions left open after the time stop", len(positions))
+	}
+	if account, _ := broker.Account(); account.Equity != 10020 {
+		t.Errorf("equity %.2f after the time stop, want 10020", account.Equity)
+	}
+}
+
+// TestPaperBrokerStopBeforeTimeStop checks that the stop-loss still wins over a time stop that has not elapsed
+func TestPaperBrokerStopBeforeTimeStop(t *testing.T) {
+	broker, _ := execution.NewPaperBroker("", 10000)
+	order, _ := broker.SubmitOrder(execution.BracketOrder{
+		Symbol: "AAPL", Side: "sell", Quantity: 10, EntryStop: 100, StopLoss: 105, TakeProfit: 90, TimeStop: 5,
+	})
+	if err := broker.Fill(order.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.CloseBar("AAPL", models.Candle{Open: 101, High: 106, Low: 100, Close: 104}); err != nil {
+		t.Fatal(err)
+	}
+	status, _ := broker.OrderStatus(order.ID)
+	if status.Status != execution.OrderCompleted || status.ExitPrice != 105 {
+		t.Errorf("order %s at %.2f, want %s at the 105 stop", status.Status, status.ExitPrice, execution.OrderCompleted)
+	}
+}
+
+// TestExpireTimeStops checks that the executor closes positions at brokers without a native time stop
+// The setup has left the watch list by the time the position expires, so only the time stop book knows the order
+func TestExpireTimeStops(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "time_stops.json")
+	book, err := execution.OpenTimeStopBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := sapantest.NewBroker(100000)
+	executor := execution.NewExecutor(broker, execution.RiskLimits{RiskPercent: 1})
+	executor.SetTimeStopBook(book)
+	entry := watcher.WatchListEntry{Signal: watcher.Signal{
+		Symbol: "AAPL", Side: watcher.LongSide, Score: 80, Entry: 100, Stop: 95, Target: 110, TimeStop: 3,
+		CandleDate: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC),
+	}}
+	executions, err := executor.Execute([]watcher.WatchListEntry{entry})
+	if err != nil || executions[0].OrderID == "" {
+		t.Fatalf("order not submitted: %+v, %v", executions, err)
+	}
+	if got := broker.Orders()[0].TimeStop; got != 3 {
+		t.Errorf("bracket order time stop = %d, want 3", got)
+	}
+	if err := book.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.SetStatus(executions[0].OrderID, execution.OrderFilled, 100); err != nil {
+		t.Fatal(err)
+	}
+	broker.SetPositions([]execution.Position{{Symbol: "AAPL", Side: watcher.LongSide, Quantity: executions[0].Quantity}})
+
+	// A later run starts from the saved book, with no watch list entry for the order
+	if book, err = execution.OpenTimeStopBook(path); err != nil {
+		t.Fatal(err)
+	}
+	executor = execution.NewExecutor(broker, execution.RiskLimits{})
+	executor.SetTimeStopBook(book)
+	closed, err := executor.ExpireTimeStops(func(time.Time) int { return 2 })
+	if err != nil || len(closed) != 0 {
+		t.Fatalf("closed %+v (%v) with 2 of 3 candles elapsed", closed, err)
+	}
+	closed, err = executor.ExpireTimeStops(func(time.Time) int { return 3 })
+	if err != nil || len(closed) != 1 || closed[0].OrderID != executions[0].OrderID {
+		t.Fatalf("closed %+v (%v) with the time stop elapsed, want the AAPL order", closed, err)
+	}
+	if positions, _ := broker.Positions(); len(positions) != 0 {
+		t.Errorf("%d positions left open after the time stop", len(positions))
+	}
+	if records := book.Records(); len(records) != 0 {
+		t.Errorf("time stop book still holds %+v after the position closed", records)
+	}
+}
+
+// TestExpireTimeStopsForgetsClosedPositions checks that orders closed by their bracket legs or never filled leave the book
+func TestExpireTimeStopsForgetsClosedPositions(t *testing.T) {
+	book, _ := execution.OpenTimeStopBook("")
+	broker := sapantest.NewBroker(100000)
+	executor := execution.NewExecutor(broker, execution.RiskLimits{RiskPercent: 1})
+	executor.SetTimeStopBook(book)
+	var entries []watcher.WatchListEntry
+	for _, symbol := range []string{"AAPL", "MSFT", "TSLA"} {
+		entries = append(entries, watcher.WatchListEntry{Signal: watcher.Signal{
+			Symbol: symbol, Side: watcher.LongSide, Score: 80, Entry: 100, Stop: 95, Target: 110, TimeStop: 3,
+			CandleDate: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC),
+		}})
+	}
+	executions, err := executor.Execute(entries)
+	if err != nil || len(executions) != 3 {
+		t.Fatalf("orders not submitted: %+v, %v", executions, err)
+	}
+	// AAPL filled and its stop closed the position, MSFT was canceled, TSLA is still working
+	if err := broker.SetStatus(executions[0].OrderID, execution.OrderFilled, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.SetStatus(executions[1].OrderID, execution.OrderCanceled, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if closed, err := executor.ExpireTimeStops(func(time.Time) int { return 5 }); err != nil || len(closed) != 0 {
+		t.Fatalf("closed %+v (%v) without open positions", closed, err)
+	}
+	if records := book.Records(); len(records) != 1 || records[0].Symbol != "TSLA" {
+		t.Errorf("time stop book = %+v, want only the working TSLA order", records)
+	}
+}
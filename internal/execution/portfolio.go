@@ -0,0 +1,87 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"math"
+)
+
+// PortfolioLimits bound the account as a whole; zero disables a limit
+type PortfolioLimits struct {
+	MaxPositions       int     // Maximum concurrent positions, counting orders submitted in this run
+	MaxOpenRiskPercent float64 // Maximum combined risk of all positions as a percentage of equity
+	MaxSectorPercent   float64 // Maximum notional exposure per sector as a percentage of equity
+}
+
+// Portfolio is the account snapshot every signal is checked against before it becomes an order
+// Positions carry no stop at the broker, so each one is assumed to risk the per-trade RiskPercent it was sized with
+type Portfolio struct {
+	equity      float64            // Account equity
+	positions   int                // Open positions plus orders accepted in this run
+	openRisk    float64            // Estimated money at risk across all positions
+	sectorValue map[string]float64 // Notional exposure per sector
+	sectors     map[string]string  // Sector of each known symbol
+}
+
+// NewPortfolio builds a snapshot from the broker's account and positions
+// sectors maps symbols to sectors so existing positions count toward sector exposure
+func NewPortfolio(account Account, positions []Position, sectors map[string]string, riskPercent float64) *Portfolio {
+	p := &Portfolio{
+		equity:      account.Equity,
+		positions:   len(positions),
+		sectorValue: make(map[string]float64),
+		sectors:     sectors,
+	}
+	for _, position := range positions {
+		p.openRisk += account.Equity * riskPercent / 100
+		p.sectorValue[p.sectorOf(position.Symbol, "")] += position.MarketValue
+	}
+	return p
+}
+
+// Check returns the reason a new order would breach a portfolio limit, or an empty string when it fits
+func (p *Portfolio) Check(entry watcher.WatchListEntry, quantity int, limits PortfolioLimits) string {
+	if limits.MaxPositions > 0 && p.positions >= limits.MaxPositions {
+		return fmt.Sprintf("%d positions already open (limit %d)", p.positions, limits.MaxPositions)
+	}
+
+	risk := float64(quantity) * math.Abs(entry.Entry-entry.Stop)
+	if limits.MaxOpenRiskPercent > 0 && p.percentOfEquity(p.openRisk+risk) > limits.MaxOpenRiskPercent {
+		return fmt.Sprintf("open risk would reach %.2f%% (limit %.2f%%)", p.percentOfEquity(p.openRisk+risk), limits.MaxOpenRiskPercent)
+	}
+
+	sector := p.sectorOf(entry.Symbol, entry.Sector)
+	exposure := p.sectorValue[sector] + float64(quantity)*entry.Entry
+	if limits.MaxSectorPercent > 0 && p.percentOfEquity(exposure) > limits.MaxSectorPercent {
+		return fmt.Sprintf("%s exposure would reach %.1f%% (limit %.1f%%)", sector, p.percentOfEquity(exposure), limits.MaxSectorPercent)
+	}
+	return ""
+}
+
+// Add records an accepted order so later signals in the same run are checked against it
+func (p *Portfolio) Add(entry watcher.WatchListEntry, quantity int) {
+	p.positions++
+	p.openRisk += float64(quantity) * math.Abs(entry.Entry-entry.Stop)
+	p.sectorValue[p.sectorOf(entry.Symbol, entry.Sector)] += float64(quantity) * entry.Entry
+}
+
+// sectorOf returns the sector of a symbol, preferring the given sector and falling back to the lookup
+func (p *Portfolio) sectorOf(symbol, sector string) string {
+	if sector == "" {
+		sector = p.sectors[symbol]
+	}
+	if sector == "" {
+		return "Unknown"
+	}
+	return sector
+}
+
+// percentOfEquity expresses an amount as a percentage of account equity
+func (p *Portfolio) percentOfEquity(amount float64) float64 {
+	if p.equity <= 0 {
+		return math.Inf(1) // Without equity every exposure breaches a limit
+	}
+	return amount / p.equity * 100
+}
@@ -0,0 +1,97 @@
+// Package execution turns confirmed SAPAN signals into broker orders
+// This package sizes positions from account equity and submits bracket orders with the signal's stop and target
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// TimeStopRecord is a submitted order whose position must exit at the close once its time stop elapses
+type TimeStopRecord struct {
+	ClientOrderID string    `json:"client_order_id"` // Client order ID the order was submitted with
+	Symbol        string    `json:"symbol"`          // Stock ticker symbol
+	Side          string    `json:"side"`            // Long or Short
+	TimeStop      int       `json:"time_stop"`       // Candles after the fill candle before the position exits at the close
+	SubmittedAt   time.Time `json:"submitted_at"`    // Time the order was submitted
+}
+
+// TimeStopBook keeps the time-stopped orders until their positions are closed, in a JSON file between runs
+// Brokers have no native time stop and setups leave the watch list long before their positions close, so the book,
+// not the watch list, decides which positions ExpireTimeStops looks at
+type TimeStopBook struct {
+	path    string           // JSON file the records are persisted to (empty keeps them in memory)
+	records []TimeStopRecord // Orders whose position may still be open, oldest first
+	mutex   sync.Mutex       // Mutex guarding records
+}
+
+// OpenTimeStopBook opens the time stop book stored at path, starting an empty one when the file does not exist
+func OpenTimeStopBook(path string) (*TimeStopBook, error) {
+	book := &TimeStopBook{path: path}
+	if path == "" {
+		return book, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time stops: %v", err)
+	}
+	if err := json.Unmarshal(data, &book.records); err != nil {
+		return nil, fmt.Errorf("failed to parse time stops %s: %v", path, err)
+	}
+	return book, nil
+}
+
+// Add records a submitted order; orders without a time stop are ignored
+func (b *TimeStopBook) Add(record TimeStopRecord) {
+	if record.TimeStop <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.records = append(b.records, record)
+}
+
+// Records returns a copy of the recorded orders, oldest first
+func (b *TimeStopBook) Records() []TimeStopRecord {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]TimeStopRecord(nil), b.records...)
+}
+
+// Remove forgets the order submitted with a client order ID
+func (b *TimeStopBook) Remove(clientOrderID string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	kept := b.records[:0]
+	for _, record := range b.records {
+		if record.ClientOrderID != clientOrderID {
+			kept = append(kept, record)
+		}
+	}
+	b.records = kept
+}
+
+// Save persists the records when a path is configured
+func (b *TimeStopBook) Save() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(b.records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode time stops: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(b.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write time stops: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package grpcapi implements the SAPAN gRPC service defined in proto/sapan/v1/sapan.proto
+// This package converts scanner types to protobuf messages and streams signals to subscribers as they are detected
+package grpcapi
+
+import (
+	"github.com/erhankrygt/sapan/watcher"
+	"sync"
+)
+
+// subscriberBuffer is how many signals a slow stream may fall behind before signals are dropped for it
+const subscriberBuffer = 64
+
+// SignalHub fans detected signals out to every active stream
+// Publishing never blocks a scan: a subscriber whose buffer is full misses the signal
+type SignalHub struct {
+	subscribers map[chan watcher.WatchListEntry]struct{} // Active subscriber channels
+	mutex       sync.RWMutex                             // Read-write mutex guarding subscribers
+}
+
+// NewSignalHub creates an empty signal hub
+func NewSignalHub() *SignalHub {
+	return &SignalHub{subscribers: make(map[chan watcher.WatchListEntry]struct{})}
+}
+
+// Subscribe registers a new subscriber and returns its channel with a function that unsubscribes it
+func (h *SignalHub) Subscribe() (<-chan watcher.WatchListEntry, func()) {
+	ch := make(chan watcher.WatchListEntry, subscriberBuffer)
+	h.mutex.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mutex.Unlock()
+
+	return ch, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish delivers a signal to every subscriber without blocking (thread-safe)
+func (h *SignalHub) Publish(entry watcher.WatchListEntry) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- entry:
+		default: // Subscriber is too slow; drop rather than stall the scan
+		}
+	}
+}
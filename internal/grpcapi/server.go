@@ -0,0 +1,180 @@
+// Package grpcapi implements the SAPAN gRPC service defined in proto/sapan/v1/sapan.proto
+// This package converts scanner types to protobuf messages and streams signals to subscribers as they are detected
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/api"
+	"github.com/erhankrygt/sapan/internal/grpcapi/sapanv1"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultCandleLimit is the number of candles GetCandles returns when the request gives no limit
+const defaultCandleLimit = 200
+
+// Server implements sapanv1.SapanServiceServer on top of the REST API's scan coordinator
+// Sharing the coordinator means scans started over HTTP and gRPC never overlap
+type Server struct {
+	sapanv1.UnimplementedSapanServiceServer
+	scans         *api.Server  // Scan coordinator shared with the REST API
+	hub           *SignalHub   // Live signal feed
+	stockFetcher  data.Fetcher // Data fetcher for GetCandles
+	watchListFile string       // File the watch list is persisted to
+}
+
+// NewServer creates the gRPC service
+func NewServer(scans *api.Server, hub *SignalHub, stockFetcher data.Fetcher, watchListFile string) *Server {
+	return &Server{
+		scans:         scans,         // Store scan coordinator
+		hub:           hub,           // Store signal hub
+		stockFetcher:  stockFetcher,  // Store data fetcher
+		watchListFile: watchListFile, // Store watch list file
+	}
+}
+
+// StartScan starts a scan in the background
+func (s *Server) StartScan(ctx context.Context, req *sapanv1.StartScanRequest) (*sapanv1.RunStatus, error) {
+	scanStatus, ok := s.scans.StartScan()
+	if !ok {
+		return nil, status.Error(codes.AlreadyExists, "a scan is already running")
+	}
+	return toRunStatus(scanStatus), nil
+}
+
+// GetRunStatus returns the state and progress of the current or last scan
+func (s *Server) GetRunStatus(ctx context.Context, req *sapanv1.GetRunStatusRequest) (*sapanv1.RunStatus, error) {
+	return toRunStatus(s.scans.Status()), nil
+}
+
+// ListSignals returns the persisted watch list
+func (s *Server) ListSignals(ctx context.Context, req *sapanv1.ListSignalsRequest) (*sapanv1.ListSignalsResponse, error) {
+	watchList := watcher.NewWatchListManager()
+	if err := watchList.Load(s.watchListFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	entries := watchList.Query(watcher.EntryFilter{Side: req.GetSide(), Sector: req.GetSector(), MinScore: req.GetMinScore()})
+	response := &sapanv1.ListSignalsResponse{Signals: make([]*sapanv1.Signal, 0, len(entries))}
+	for _, entry := range entries {
+		response.Signals = append(response.Signals, toSignal(entry))
+	}
+	return response, nil
+}
+
+// GetCandles returns the latest candles of a symbol
+func (s *Server) GetCandles(ctx context.Context, req *sapanv1.GetCandlesRequest) (*sapanv1.GetCandlesResponse, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(req.GetSymbol()))
+	if symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultCandleLimit
+	}
+
+	candleData, err := s.stockFetcher.FetchStockData(ctx, symbol, limit)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	response := &sapanv1.GetCandlesResponse{Candles: make([]*sapanv1.Candle, 0, len(candleData.Candles))}
+	for _, candle := range candleData.Candles {
+		response.Candles = append(response.Candles, toCandle(candle))
+	}
+	return response, nil
+}
+
+// StreamSignals pushes signals to the client as scans detect them until the client disconnects
+func (s *Server) StreamSignals(req *sapanv1.StreamSignalsRequest, stream sapanv1.SapanService_StreamSignalsServer) error {
+	signals, unsubscribe := s.hub.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case entry := <-signals:
+			if req.GetSide() != "" && entry.Side != req.GetSide() {
+				continue
+			}
+			if entry.Score < req.GetMinScore() {
+				continue
+			}
+			if err := stream.Send(toSignal(entry)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toSignal converts a watch list entry into a protobuf signal
+func toSignal(entry watcher.WatchListEntry) *sapanv1.Signal {
+	return &sapanv1.Signal{
+		Symbol:     entry.Symbol,
+		Side:       entry.Side,
+		Pattern:    entry.Pattern,
+		Score:      entry.Score,
+		Entry:      entry.Entry,
+		Stop:       entry.Stop,
+		Target:     entry.Target,
+		Sector:     entry.Sector,
+		Industry:   entry.Industry,
+		CandleDate: timestamp(entry.CandleDate),
+		DetectedAt: timestamp(entry.DetectedAt),
+		Exchange:   entry.Exchange,
+		Currency:   entry.Currency,
+		Country:    entry.Country,
+		Isin:       entry.ISIN,
+	}
+}
+
+// toCandle converts a candle into its protobuf form
+func toCandle(candle models.Candle) *sapanv1.Candle {
+	return &sapanv1.Candle{
+		Date:   timestamp(candle.Time()),
+		Open:   candle.Open,
+		High:   candle.High,
+		Low:    candle.Low,
+		Close:  candle.Close,
+		Volume: candle.Volume,
+	}
+}
+
+// toRunStatus converts the REST scan status into its protobuf form
+func toRunStatus(scanStatus api.ScanStatus) *sapanv1.RunStatus {
+	runStatus := &sapanv1.RunStatus{Running: scanStatus.Running}
+	if scanStatus.StartedAt != nil {
+		runStatus.StartedAt = timestamp(*scanStatus.StartedAt)
+	}
+	if scanStatus.FinishedAt != nil {
+		runStatus.FinishedAt = timestamp(*scanStatus.FinishedAt)
+	}
+	if scanStatus.ExitCode != nil {
+		runStatus.ExitCode = int32(*scanStatus.ExitCode)
+	}
+	if progress := scanStatus.Progress; progress != nil {
+		runStatus.Total = int32(progress.Total)
+		runStatus.Processed = int32(progress.Processed)
+		runStatus.Valid = int32(progress.Valid)
+		runStatus.Errors = int32(progress.Errors)
+		runStatus.Percent = progress.Percent
+	}
+	return runStatus
+}
+
+// timestamp converts a time into a protobuf timestamp, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
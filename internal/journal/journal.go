@@ -0,0 +1,232 @@
+// Package journal appends taken signals to trading journal files
+// Every row is keyed by the run and signal IDs of the signal database, so journal trades trace back to their scan
+package journal
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Journal formats
+const (
+	FormatJSON      = "json"      // One JSON object per line
+	FormatTradervue = "tradervue" // Columns of Tradervue's generic CSV import, one entry execution per row
+	FormatEdgewonk  = "edgewonk"  // Columns mapped by Edgewonk's custom CSV import, one planned trade per row
+)
+
+// tradervueHeader and edgewonkHeader list the CSV columns of each format in order
+var (
+	tradervueHeader = []string{"Date", "Time", "Symbol", "Quantity", "Price", "Side", "Notes"}
+	edgewonkHeader  = []string{"Trade ID", "Date", "Instrument", "Direction", "Entry", "Stop Loss", "Take Profit",
+		"Position Size", "Setup", "Score"}
+)
+
+// Entry is one taken signal as it is written to the journal
+type Entry struct {
+	ID         string    `json:"id"`                 // Journal key derived from the run and signal IDs (see Key)
+	RunID      int64     `json:"run_id"`             // Identifier of the run that detected the signal
+	SignalID   int64     `json:"signal_id"`          // Identifier of the signal in the signal database
+	Symbol     string    `json:"symbol"`             // Stock ticker symbol
+	Side       string    `json:"side"`               // Long or Short
+	Pattern    string    `json:"pattern"`            // Candlestick pattern that confirmed the setup
+	Score      float64   `json:"score"`              // Setup quality score from 0 to 100
+	DetectedAt time.Time `json:"detected_at"`        // Time the setup was detected
+	Entry      float64   `json:"entry"`              // Entry trigger price
+	Stop       float64   `json:"stop"`               // Stop-loss price
+	Target     float64   `json:"target"`             // Profit target price
+	Quantity   int       `json:"quantity,omitempty"` // Shares ordered (zero when the signal was not executed)
+	Broker     string    `json:"broker,omitempty"`   // Broker the order went to (empty when not executed)
+	OrderID    string    `json:"order_id,omitempty"` // Broker order ID (empty when not executed)
+	Sector     string    `json:"sector,omitempty"`   // Business sector of the stock
+}
+
+// Key returns the journal key of a signal, e.g. "sapan-12-345" for signal 345 of run 12
+func Key(runID, signalID int64) string {
+	return fmt.Sprintf("sapan-%d-%d", runID, signalID)
+}
+
+// NewEntry builds the journal entry of a watch list entry; execution details are filled in by the caller
+func NewEntry(entry watcher.WatchListEntry) Entry {
+	return Entry{
+		ID:         Key(entry.RunID, entry.ID),
+		RunID:      entry.RunID,
+		SignalID:   entry.ID,
+		Symbol:     entry.Symbol,
+		Side:       entry.Side,
+		Pattern:    entry.Pattern,
+		Score:      entry.Score,
+		DetectedAt: entry.DetectedAt,
+		Entry:      entry.Entry,
+		Stop:       entry.Stop,
+		Target:     entry.Target,
+		Sector:     entry.Sector,
+	}
+}
+
+// Append adds entries to the journal file in the given format and returns how many were written
+// A new CSV journal starts with a header row; entries whose key is already in the file are skipped,
+// so re-running the export for the same run never duplicates trades
+func Append(path, format string, entries []Entry) (int, error) {
+	if format != FormatJSON && format != FormatTradervue && format != FormatEdgewonk {
+		return 0, fmt.Errorf("unknown journal format %q", format)
+	}
+	existing, err := readKeys(path, format)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open journal: %v", err)
+	}
+	defer file.Close()
+
+	var fresh []Entry
+	for _, entry := range entries {
+		if !existing[entry.ID] {
+			existing[entry.ID] = true
+			fresh = append(fresh, entry)
+		}
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(file)
+		for _, entry := range fresh {
+			if err := encoder.Encode(entry); err != nil {
+				return 0, fmt.Errorf("failed to write journal entry for %s: %v", entry.Symbol, err)
+			}
+		}
+		return len(fresh), nil
+	}
+
+	writer := csv.NewWriter(file)
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		header := tradervueHeader
+		if format == FormatEdgewonk {
+			header = edgewonkHeader
+		}
+		writer.Write(header)
+	}
+	for _, entry := range fresh {
+		record := tradervueRecord(entry)
+		if format == FormatEdgewonk {
+			record = edgewonkRecord(entry)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write journal: %v", err)
+	}
+	return len(fresh), nil
+}
+
+// readKeys returns the keys of the entries already in the journal file; a missing file has none
+func readKeys(path, format string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+	defer file.Close()
+
+	if format == FormatJSON {
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var entry struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.ID != "" {
+				keys[entry.ID] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read journal: %v", err)
+		}
+		return keys, nil
+	}
+
+	// The key sits in the Notes column of Tradervue journals and the Trade ID column of Edgewonk journals
+	column := len(tradervueHeader) - 1
+	if format == FormatEdgewonk {
+		column = 0
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse journal %s: %v", path, err)
+		}
+		if column < len(record) {
+			keys[record[column]] = true
+		}
+	}
+}
+
+// tradervueRecord converts an entry into a Tradervue row: the entry fill as a Buy (Long) or Short (Short) execution
+func tradervueRecord(entry Entry) []string {
+	side := "Buy"
+	if entry.Side == watcher.ShortSide {
+		side = "Short"
+	}
+	return []string{
+		entry.DetectedAt.Format("01/02/2006"),
+		entry.DetectedAt.Format("15:04:05"),
+		entry.Symbol,
+		quantity(entry.Quantity),
+		price(entry.Entry),
+		side,
+		entry.ID,
+	}
+}
+
+// edgewonkRecord converts an entry into an Edgewonk row with the planned stop and target
+func edgewonkRecord(entry Entry) []string {
+	return []string{
+		entry.ID,
+		entry.DetectedAt.Format("2006-01-02 15:04:05"),
+		entry.Symbol,
+		entry.Side,
+		price(entry.Entry),
+		price(entry.Stop),
+		price(entry.Target),
+		quantity(entry.Quantity),
+		entry.Pattern,
+		strconv.FormatFloat(entry.Score, 'f', 1, 64),
+	}
+}
+
+// price renders a price with four decimals, leaving the cell empty when the level is unknown
+func price(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
+
+// quantity renders an order quantity, leaving the cell empty for signals that were not executed
+func quantity(shares int) string {
+	if shares == 0 {
+		return ""
+	}
+	return strconv.Itoa(shares)
+}
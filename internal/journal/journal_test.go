@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func testEntry(runID, signalID int64, symbol, side string) Entry {
+	signal := watcher.Signal{ID: signalID, RunID: runID, Symbol: symbol, Side: side, Pattern: "Long Pinbar Reversal", Score: 81.5,
+		DetectedAt: time.Date(2024, 3, 8, 21, 30, 0, 0, time.UTC), Entry: 101.25, Stop: 97.5, Target: 108.75}
+	return NewEntry(watcher.WatchListEntry{Signal: signal})
+}
+
+func TestAppendSkipsJournaledSignals(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatTradervue, FormatEdgewonk} {
+		t.Run(format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal")
+			first := testEntry(12, 345, "AAPL", watcher.LongSide)
+			first.Quantity = 40
+
+			if written, err := Append(path, format, []Entry{first}); err != nil || written != 1 {
+				t.Fatalf("first Append = %d, %v; want 1 entry", written, err)
+			}
+			written, err := Append(path, format, []Entry{first, testEntry(13, 350, "TSLA", watcher.ShortSide)})
+			if err != nil || written != 1 {
+				t.Fatalf("second Append = %d, %v; want only the new entry", written, err)
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+			want := 2
+			if format != FormatJSON {
+				want = 3 // Header row
+			}
+			if len(lines) != want {
+				t.Fatalf("journal has %d lines, want %d:\n%s", len(lines), want, raw)
+			}
+			if !strings.Contains(lines[len(lines)-2], "sapan-12-345") || !strings.Contains(lines[len(lines)-1], "sapan-13-350") {
+				t.Errorf("journal rows are not keyed by run and signal ID:\n%s", raw)
+			}
+		})
+	}
+}
+
+func TestTradervueRecord(t *testing.T) {
+	entry := testEntry(12, 345, "TSLA", watcher.ShortSide)
+	entry.Quantity = 40
+	got := strings.Join(tradervueRecord(entry), ",")
+	if want := "03/08/2024,21:30:00,TSLA,40,101.2500,Short,sapan-12-345"; got != want {
+		t.Errorf("tradervueRecord = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+// Package mlscore predicts how likely a SAPAN setup is to reach its target before its stop
+// A logistic regression model is trained on recorded signal outcomes and applied to new signals during scans
+package mlscore
+
+import (
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"math"
+	"strings"
+)
+
+// Feature names stored with every scored signal
+const (
+	FeatureScore          = "score"           // Setup quality score scaled to 0-1
+	FeatureTailRatio      = "tail_ratio"      // Rejection wick share of the reversal candle's range
+	FeaturePinbar         = "pinbar"          // 1 for pinbar reversals, 0 for two-candle reversals
+	FeatureADX            = "adx"             // ADX(14) scaled to 0-1
+	FeatureEMA20Distance  = "ema20_distance"  // Close distance from EMA 20 in the trade direction, as a fraction
+	FeatureEMA50Distance  = "ema50_distance"  // Close distance from EMA 50 in the trade direction, as a fraction
+	FeatureEMA200Distance = "ema200_distance" // Close distance from EMA 200 in the trade direction, as a fraction
+	FeatureRiskReward     = "risk_reward"     // Planned reward-to-risk ratio
+	FeatureSectorStrength = "sector_strength" // Sector momentum in the trade direction
+	FeatureLong           = "long"            // 1 for Long setups, 0 for Short setups
+)
+
+// FeatureNames lists every feature in model order
+var FeatureNames = []string{
+	FeatureScore, FeatureTailRatio, FeaturePinbar, FeatureADX,
+	FeatureEMA20Distance, FeatureEMA50Distance, FeatureEMA200Distance,
+	FeatureRiskReward, FeatureSectorStrength, FeatureLong,
+}
+
+// adxPeriod is the lookback of the ADX feature
+const adxPeriod = 14
+
+// ExtractFeatures describes a validated setup as model features
+// sectorStrength is the sector's recent return (see SectorStrength); it is flipped for Short setups like the EMA distances
+func ExtractFeatures(candles []models.Candle, side string, validation strategy.ValidationResult, sectorStrength float64) watcher.SignalFeatures {
+	features := watcher.SignalFeatures{
+		FeatureScore:          validation.Score / 100,
+		FeatureRiskReward:     validation.TradePlan.RiskReward(),
+		FeatureSectorStrength: sectorStrength,
+	}
+	if len(candles) < 2 {
+		return features
+	}
+
+	direction := 1.0
+	if side == watcher.ShortSide {
+		direction = -1
+		features[FeatureSectorStrength] = -sectorStrength
+	} else {
+		features[FeatureLong] = 1
+	}
+	if strings.Contains(validation.PatternType.String(), "Pinbar") {
+		features[FeaturePinbar] = 1
+	}
+
+	reversal := candles[len(candles)-2]
+	if span := reversal.High - reversal.Low; span > 0 {
+		wick := math.Min(reversal.Open, reversal.Close) - reversal.Low
+		if side == watcher.ShortSide {
+			wick = reversal.High - math.Max(reversal.Open, reversal.Close)
+		}
+		features[FeatureTailRatio] = wick / span
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	last := closes[len(closes)-1]
+	ema := indicators.NewEMACalculator()
+	for name, period := range map[string]int{FeatureEMA20Distance: 20, FeatureEMA50Distance: 50, FeatureEMA200Distance: 200} {
+		if value := ema.Calculate(closes, period); value > 0 {
+			features[name] = direction * (last - value) / value
+		}
+	}
+	features[FeatureADX] = indicators.NewADXCalculator().Calculate(candles, adxPeriod) / 100
+	return features
+}
+
+// momentumCandles is the lookback of the return used for sector strength
+const momentumCandles = 20
+
+// Momentum returns the return over the last 20 candles as a fraction (0 with too little history)
+func Momentum(candles []models.Candle) float64 {
+	if len(candles) <= momentumCandles {
+		return 0
+	}
+	start := candles[len(candles)-1-momentumCandles].Close
+	if start <= 0 {
+		return 0
+	}
+	return candles[len(candles)-1].Close/start - 1
+}
+
+// SectorStrength averages the momentum of the stocks in each sector
+// momentum and sectors are keyed by symbol; stocks without a sector are ignored
+func SectorStrength(momentum map[string]float64, sectors map[string]string) map[string]float64 {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for symbol, value := range momentum {
+		sector := sectors[symbol]
+		if sector == "" {
+			continue
+		}
+		sums[sector] += value
+		counts[sector]++
+	}
+	strength := make(map[string]float64, len(sums))
+	for sector, sum := range sums {
+		strength[sector] = sum / float64(counts[sector])
+	}
+	return strength
+}
@@ -0,0 +1,186 @@
+package mlscore
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/watcher"
+	"math"
+	"os"
+	"time"
+)
+
+// Model is a logistic regression over standardized signal features
+type Model struct {
+	Features  []string        `json:"features"`   // Feature names in weight order
+	Weights   []float64       `json:"weights"`    // Coefficient per feature
+	Bias      float64         `json:"bias"`       // Intercept
+	Means     []float64       `json:"means"`      // Feature means used for standardization
+	Scales    []float64       `json:"scales"`     // Feature standard deviations used for standardization
+	TrainedAt time.Time       `json:"trained_at"` // Time the model was trained
+	Samples   int             `json:"samples"`    // Number of training samples
+	Metrics   TrainingMetrics `json:"metrics"`    // In-sample fit of the model
+}
+
+// Sample is one labelled training example
+type Sample struct {
+	Features watcher.SignalFeatures // Features captured when the signal was detected
+	Success  bool                   // Whether the target was hit before the stop
+}
+
+// TrainingMetrics summarize how well a model fits its training data
+type TrainingMetrics struct {
+	BaseRate float64 `json:"base_rate"` // Share of successful samples
+	Accuracy float64 `json:"accuracy"`  // Share of samples classified correctly at a 0.5 threshold
+	LogLoss  float64 `json:"log_loss"`  // Mean negative log-likelihood
+}
+
+// Training hyperparameters
+const (
+	trainingEpochs       = 2000 // Full-batch gradient descent iterations
+	trainingLearningRate = 0.1  // Step size of each iteration
+	trainingL2           = 0.01 // Ridge penalty that keeps weights small on small data sets
+)
+
+// MinimumSamples is the number of resolved outcomes required before a model is trained
+const MinimumSamples = 30
+
+// Train fits a logistic regression on the samples using full-batch gradient descent
+func Train(samples []Sample) (*Model, error) {
+	if len(samples) < MinimumSamples {
+		return nil, fmt.Errorf("need at least %d resolved signals with features to train, have %d", MinimumSamples, len(samples))
+	}
+
+	names := FeatureNames
+	rows := make([][]float64, len(samples))
+	labels := make([]float64, len(samples))
+	for i, sample := range samples {
+		rows[i] = vector(sample.Features, names)
+		if sample.Success {
+			labels[i] = 1
+		}
+	}
+
+	model := &Model{Features: names, Weights: make([]float64, len(names)), Samples: len(samples), TrainedAt: time.Now().UTC()}
+	model.Means, model.Scales = standardization(rows, len(names))
+	for _, row := range rows {
+		model.standardize(row)
+	}
+
+	n := float64(len(rows))
+	gradient := make([]float64, len(names))
+	for epoch := 0; epoch < trainingEpochs; epoch++ {
+		for j := range gradient {
+			gradient[j] = trainingL2 * model.Weights[j]
+		}
+		biasGradient := 0.0
+		for i, row := range rows {
+			residual := model.linear(row) - labels[i]
+			for j, value := range row {
+				gradient[j] += residual * value / n
+			}
+			biasGradient += residual / n
+		}
+		for j := range model.Weights {
+			model.Weights[j] -= trainingLearningRate * gradient[j]
+		}
+		model.Bias -= trainingLearningRate * biasGradient
+	}
+
+	var successes, correct, logLoss float64
+	for i, row := range rows {
+		p := model.linear(row)
+		successes += labels[i]
+		if (p >= 0.5) == (labels[i] == 1) {
+			correct++
+		}
+		p = math.Min(math.Max(p, 1e-9), 1-1e-9)
+		logLoss -= labels[i]*math.Log(p) + (1-labels[i])*math.Log(1-p)
+	}
+	model.Metrics = TrainingMetrics{BaseRate: successes / n, Accuracy: correct / n, LogLoss: logLoss / n}
+	return model, nil
+}
+
+// Predict returns the probability that a setup with these features reaches its target before its stop
+func (m *Model) Predict(features watcher.SignalFeatures) float64 {
+	row := vector(features, m.Features)
+	m.standardize(row)
+	return m.linear(row)
+}
+
+// linear applies the weights to a standardized row and squashes the result into a probability
+func (m *Model) linear(row []float64) float64 {
+	z := m.Bias
+	for j, value := range row {
+		z += m.Weights[j] * value
+	}
+	return 1 / (1 + math.Exp(-z))
+}
+
+// standardize rescales a row in place to zero mean and unit variance using the training statistics
+func (m *Model) standardize(row []float64) {
+	for j := range row {
+		row[j] = (row[j] - m.Means[j]) / m.Scales[j]
+	}
+}
+
+// vector lays the named features out in order; missing features count as zero
+func vector(features watcher.SignalFeatures, names []string) []float64 {
+	row := make([]float64, len(names))
+	for j, name := range names {
+		row[j] = features[name]
+	}
+	return row
+}
+
+// standardization returns the mean and standard deviation of every column (constant columns get a scale of 1)
+func standardization(rows [][]float64, width int) ([]float64, []float64) {
+	means := make([]float64, width)
+	scales := make([]float64, width)
+	n := float64(len(rows))
+	for _, row := range rows {
+		for j, value := range row {
+			means[j] += value / n
+		}
+	}
+	for _, row := range rows {
+		for j, value := range row {
+			scales[j] += (value - means[j]) * (value - means[j]) / n
+		}
+	}
+	for j := range scales {
+		scales[j] = math.Sqrt(scales[j])
+		if scales[j] < 1e-9 {
+			scales[j] = 1
+		}
+	}
+	return means, scales
+}
+
+// LoadModel reads a model written by Save
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %v", err)
+	}
+	var model Model
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("failed to parse model %s: %v", path, err)
+	}
+	if len(model.Weights) != len(model.Features) || len(model.Means) != len(model.Features) || len(model.Scales) != len(model.Features) {
+		return nil, fmt.Errorf("model %s is inconsistent: %d features, %d weights", path, len(model.Features), len(model.Weights))
+	}
+	return &model, nil
+}
+
+// Save writes the model as indented JSON, replacing the file atomically
+func (m *Model) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode model: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write model: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package mlscore
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"sync"
+)
+
+// Scorer attaches features and, when a model is loaded, a success probability to new signals
+// It also observes every analyzed stock so the run's sector strength can be stored for the next scan
+type Scorer struct {
+	model          *Model             // Trained model (nil only records features)
+	sectorStrength map[string]float64 // Sector strength from the previous run, keyed by sector
+	momentum       map[string]float64 // Momentum of every stock analyzed in this run, keyed by symbol
+	sectors        map[string]string  // Sector of every stock analyzed in this run, keyed by symbol
+	mutex          sync.Mutex         // Mutex guarding momentum and sectors
+}
+
+// NewScorer creates a scorer; model may be nil to record features without predicting
+func NewScorer(model *Model, sectorStrength map[string]float64) *Scorer {
+	return &Scorer{
+		model:          model,
+		sectorStrength: sectorStrength,
+		momentum:       make(map[string]float64),
+		sectors:        make(map[string]string),
+	}
+}
+
+// Observe records the momentum of an analyzed stock (thread-safe)
+func (s *Scorer) Observe(stock models.Stock, candles []models.Candle) {
+	momentum := Momentum(candles)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.momentum[stock.Symbol] = momentum
+	s.sectors[stock.Symbol] = stock.Sector
+}
+
+// ScoreSignal returns the features of a validated setup and its predicted probability (zero without a model)
+func (s *Scorer) ScoreSignal(stock models.Stock, candles []models.Candle, validation strategy.ValidationResult, side string) (watcher.SignalFeatures, float64) {
+	features := ExtractFeatures(candles, side, validation, s.sectorStrength[stock.Sector])
+	if s.model == nil {
+		return features, 0
+	}
+	return features, s.model.Predict(features)
+}
+
+// SectorStrength returns the sector strength observed during this run
+func (s *Scorer) SectorStrength() map[string]float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return SectorStrength(s.momentum, s.sectors)
+}
@@ -0,0 +1,114 @@
+// Package monitor turns the watch list into live alerts
+// It polls quotes for the listed setups and raises one notification per setup when the price crosses its entry trigger,
+// or crosses its stop first and invalidates it
+package monitor
+
+import (
+	"github.com/erhankrygt/sapan/internal/notify"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"strings"
+	"time"
+)
+
+// QuoteSource fetches the latest quotes for many symbols at once
+type QuoteSource interface {
+	FetchQuotes(symbols []string) ([]models.Quote, error)
+}
+
+// Publisher delivers trigger events to the notifiers; *notify.Dispatcher is the production implementation
+type Publisher interface {
+	Publish(event notify.Event)
+}
+
+// setupKey identifies a setup by symbol, side, and entry, so a re-detected setup with new levels is watched again
+type setupKey struct {
+	symbol string  // Stock ticker symbol
+	side   string  // LongSide or ShortSide
+	entry  float64 // Entry trigger price
+}
+
+// Monitor compares quotes with the levels of watch list setups
+// A setup raises at most one trigger: once its entry or stop was crossed it is no longer watched
+type Monitor struct {
+	source    QuoteSource       // Bulk quote provider
+	publisher Publisher         // Receives trigger events
+	fired     map[setupKey]bool // Setups that already raised a trigger
+}
+
+// NewMonitor creates a monitor publishing to the given publisher
+func NewMonitor(source QuoteSource, publisher Publisher) *Monitor {
+	return &Monitor{
+		source:    source,                  // Store the quote provider
+		publisher: publisher,               // Store the publisher
+		fired:     make(map[setupKey]bool), // Initialize the fired setups
+	}
+}
+
+// Check fetches quotes for the setups with a trade plan that have not fired yet and publishes their triggers
+// Setups that left the watch list are forgotten, so the state stays as small as the list
+func (m *Monitor) Check(entries []watcher.WatchListEntry, now time.Time) ([]notify.PriceTrigger, error) {
+	watched := make(map[setupKey]watcher.WatchListEntry)
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Entry <= 0 || entry.Stop <= 0 {
+			continue // No trade plan to watch
+		}
+		key := setupKey{symbol: strings.ToUpper(entry.Symbol), side: entry.Side, entry: entry.Entry}
+		watched[key] = entry
+		if !m.fired[key] && !seen[key.symbol] {
+			seen[key.symbol] = true
+			symbols = append(symbols, key.symbol)
+		}
+	}
+	for key := range m.fired {
+		if _, ok := watched[key]; !ok {
+			delete(m.fired, key)
+		}
+	}
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	quotes, err := m.source.FetchQuotes(symbols)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]float64, len(quotes))
+	for _, quote := range quotes {
+		prices[strings.ToUpper(quote.Symbol)] = quote.Price
+	}
+
+	var triggers []notify.PriceTrigger
+	for key, entry := range watched {
+		price, ok := prices[key.symbol]
+		if m.fired[key] || !ok {
+			continue
+		}
+		trigger, crossed := Evaluate(entry, price)
+		if !crossed {
+			continue
+		}
+		m.fired[key] = true
+		triggers = append(triggers, trigger)
+		m.publisher.Publish(notify.Event{Type: notify.TriggerEvent, Trigger: &trigger, Time: now})
+	}
+	return triggers, nil
+}
+
+// Evaluate reports whether a price crossed the entry trigger or the stop of a setup
+// Long setups trigger at or above the entry and are invalidated at or below the stop; Short setups the other way round
+func Evaluate(entry watcher.WatchListEntry, price float64) (notify.PriceTrigger, bool) {
+	trigger := notify.PriceTrigger{Price: price, Setup: entry}
+	long := entry.Side == watcher.LongSide
+	switch {
+	case long && price >= entry.Entry, !long && price <= entry.Entry:
+		trigger.Kind, trigger.Level = notify.EntryTriggered, entry.Entry
+	case long && price <= entry.Stop, !long && price >= entry.Stop:
+		trigger.Kind, trigger.Level = notify.SetupInvalidated, entry.Stop
+	default:
+		return notify.PriceTrigger{}, false
+	}
+	return trigger, true
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/notify"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// quotes is a quote source serving fixed prices and recording the requested symbols
+type quotes struct {
+	prices    map[string]float64 // Price per symbol
+	requested [][]string         // Symbols of every request
+}
+
+func (q *quotes) FetchQuotes(symbols []string) ([]models.Quote, error) {
+	q.requested = append(q.requested, symbols)
+	var result []models.Quote
+	for _, symbol := range symbols {
+		if price, ok := q.prices[symbol]; ok {
+			result = append(result, models.Quote{Symbol: symbol, Price: price})
+		}
+	}
+	return result, nil
+}
+
+// events records published events
+type events []notify.Event
+
+func (e *events) Publish(event notify.Event) { *e = append(*e, event) }
+
+func setup(symbol, side string, entry, stop float64) watcher.WatchListEntry {
+	return watcher.WatchListEntry{Signal: watcher.Signal{Symbol: symbol, Side: side, Entry: entry, Stop: stop, Target: entry * 1.1}}
+}
+
+func TestCheckFiresEachSetupOnce(t *testing.T) {
+	source := &quotes{prices: map[string]float64{"AAPL": 101, "MSFT": 94, "TSLA": 195, "NVDA": 50}}
+	var published events
+	priceMonitor := NewMonitor(source, &published)
+	entries := []watcher.WatchListEntry{
+		setup("AAPL", watcher.LongSide, 100, 95),                         // Above the entry: triggered
+		setup("MSFT", watcher.LongSide, 100, 95),                         // Below the stop: invalidated
+		setup("TSLA", watcher.ShortSide, 190, 200),                       // Between the levels: still waiting
+		{Signal: watcher.Signal{Symbol: "NVDA", Side: watcher.LongSide}}, // No trade plan
+	}
+
+	triggers, err := priceMonitor.Check(entries, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := make(map[string]notify.TriggerKind)
+	for _, trigger := range triggers {
+		kinds[trigger.Setup.Symbol] = trigger.Kind
+	}
+	if len(kinds) != 2 || kinds["AAPL"] != notify.EntryTriggered || kinds["MSFT"] != notify.SetupInvalidated {
+		t.Fatalf("triggers = %+v, want AAPL triggered and MSFT invalidated", triggers)
+	}
+	if len(published) != 2 || published[0].Type != notify.TriggerEvent || published[0].Trigger == nil {
+		t.Fatalf("published %+v, want two trigger events", published)
+	}
+	if requested := source.requested[0]; len(requested) != 3 {
+		t.Errorf("requested quotes for %v, want the three setups with a trade plan", requested)
+	}
+
+	// Fired setups are not quoted again; the short setup triggers once the price falls through its entry
+	source.prices["TSLA"] = 189
+	triggers, err = priceMonitor.Check(entries, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 || triggers[0].Setup.Symbol != "TSLA" || triggers[0].Kind != notify.EntryTriggered || triggers[0].Level != 190 {
+		t.Errorf("triggers = %+v, want the TSLA Short entry at 190", triggers)
+	}
+	if requested := source.requested[1]; len(requested) != 1 || requested[0] != "TSLA" {
+		t.Errorf("requested quotes for %v, want only TSLA", requested)
+	}
+
+	// A re-detected setup with a new entry is watched again
+	entries[0] = setup("AAPL", watcher.LongSide, 105, 98)
+	source.prices["AAPL"] = 106
+	if triggers, _ = priceMonitor.Check(entries, time.Now()); len(triggers) != 1 || triggers[0].Level != 105 {
+		t.Errorf("triggers = %+v, want the new AAPL entry at 105", triggers)
+	}
+}
@@ -0,0 +1,80 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// DesktopNotifier shows native desktop notifications for new signals during local runs
+// It shells out to the platform's notification tool: osascript on macOS, notify-send on Linux,
+// and PowerShell toast notifications on Windows
+type DesktopNotifier struct {
+	goos string // Target operating system, taken from runtime.GOOS
+}
+
+// NewDesktopNotifier creates a desktop notifier for the current operating system
+func NewDesktopNotifier() *DesktopNotifier {
+	return &DesktopNotifier{goos: runtime.GOOS}
+}
+
+// Name returns the notifier name used in logs
+func (n *DesktopNotifier) Name() string {
+	return "desktop"
+}
+
+// Notify shows a notification for each signal and trigger; run summaries are left to the terminal output
+func (n *DesktopNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		return n.show(signalTitle(*event.Signal), signalMessage(*event.Signal))
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		return n.show(triggerTitle(*event.Trigger), triggerMessage(*event.Trigger))
+	}
+	return nil
+}
+
+// show runs the platform notification command for one message
+func (n *DesktopNotifier) show(title, message string) error {
+	cmd, err := n.command(title, message)
+	if err != nil {
+		return err
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show desktop notification: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// command builds the notification command; text is passed as arguments or escaped string literals, never through a shell
+func (n *DesktopNotifier) command(title, message string) (*exec.Cmd, error) {
+	switch n.goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.Command("osascript", "-e", script), nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return exec.Command("notify-send", "--app-name=SAPAN", title, message), nil
+	case "windows":
+		script := `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode(` + powerShellString(title) + `)) > $null
+$texts.Item(1).AppendChild($template.CreateTextNode(` + powerShellString(message) + `)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('SAPAN').Show([Windows.UI.Notifications.ToastNotification]::new($template))`
+		return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script), nil
+	}
+	return nil, fmt.Errorf("desktop notifications are not supported on %s", n.goos)
+}
+
+// appleScriptString quotes text as an AppleScript string literal
+func appleScriptString(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// powerShellString quotes text as a single-quoted PowerShell string literal
+func powerShellString(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
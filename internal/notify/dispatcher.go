@@ -0,0 +1,225 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// dispatchQueueSize bounds the events waiting for a single notifier; further events are dropped
+const dispatchQueueSize = 256
+
+// dispatchBaseBackoff is the delay before the first retry of a failed delivery; each retry doubles it
+const dispatchBaseBackoff = time.Second
+
+// dispatchTarget is a registered notifier with its own queue, rate limit, and retry budget
+type dispatchTarget struct {
+	notifier   Notifier      // Notifier receiving events
+	interval   time.Duration // Minimum time between deliveries (rate limit)
+	maxRetries int           // Retries after the first failed attempt
+	queue      chan Event    // Events waiting for delivery
+}
+
+// Dispatcher fans notification events out to every registered notifier
+// Each notifier runs in its own goroutine, so a slow or failing channel never delays or breaks the others
+type Dispatcher struct {
+	targets        []*dispatchTarget // Registered notifiers
+	pending        []Event           // Signal events buffered until Flush
+	notifyExisting bool              // Forward re-detections of setups already on the watch list
+	streaming      bool              // Deliver signals as soon as they are detected instead of at Flush
+	filter         SignalFilter      // Decides per notifier which signals are delivered (nil delivers all)
+	started        bool              // Whether the delivery goroutines are running
+	wg             sync.WaitGroup    // Tracks running delivery goroutines
+	mutex          sync.Mutex        // Mutex guarding pending and started
+}
+
+// SignalFilter reports whether the named notifier should receive a signal
+type SignalFilter func(notifier string, entry watcher.WatchListEntry) bool
+
+// NewDispatcher creates an empty dispatcher
+// When notifyExisting is false only setups new to the watch list are forwarded
+func NewDispatcher(notifyExisting bool) *Dispatcher {
+	return &Dispatcher{notifyExisting: notifyExisting}
+}
+
+// Register adds a notifier with its own rate limit (minimum interval between deliveries) and retry budget
+// Notifiers must be registered before Start
+func (d *Dispatcher) Register(notifier Notifier, interval time.Duration, maxRetries int) {
+	d.targets = append(d.targets, &dispatchTarget{
+		notifier:   notifier,
+		interval:   interval,
+		maxRetries: maxRetries,
+		queue:      make(chan Event, dispatchQueueSize),
+	})
+}
+
+// SetSignalFilter installs a filter consulted for every signal and trigger event and notifier; run events are always delivered
+func (d *Dispatcher) SetSignalFilter(filter SignalFilter) {
+	d.filter = filter
+}
+
+// SetStreaming delivers signals the moment they are detected instead of buffering them until Flush
+// Streamed signals go out before the end-of-run annotations and filters, so later changes to them are not announced
+func (d *Dispatcher) SetStreaming(streaming bool) {
+	d.streaming = streaming
+}
+
+// Count returns the number of registered notifiers
+func (d *Dispatcher) Count() int {
+	return len(d.targets)
+}
+
+// Start launches one delivery goroutine per registered notifier
+func (d *Dispatcher) Start() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.started {
+		return
+	}
+	d.started = true
+	for _, target := range d.targets {
+		d.wg.Add(1)
+		go d.run(target)
+	}
+}
+
+// HandleWatchListEvent converts watch list events into signal notifications
+// Pass it to WatchListManager.Subscribe; signals are buffered and delivered by Flush at the end of the run,
+// or published right away when streaming
+func (d *Dispatcher) HandleWatchListEvent(event watcher.WatchListEvent) {
+	// Setups annotated or removed before the flush are announced in their final state, or not at all
+	if event.Type == watcher.EntryAnnotated || event.Type == watcher.EntryRemoved {
+		d.refreshPending(event)
+		return
+	}
+	if event.Type != watcher.EntryAdded && !(event.Type == watcher.EntryUpdated && d.notifyExisting) {
+		return
+	}
+
+	entry := event.Entry
+	signal := Event{Type: SignalEvent, Signal: &entry, Time: event.Time}
+	if d.streaming {
+		d.Publish(signal)
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.pending = append(d.pending, signal)
+}
+
+// refreshPending replaces the buffered copy of an annotated entry and drops a removed one
+func (d *Dispatcher) refreshPending(event watcher.WatchListEvent) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	kept := d.pending[:0]
+	for _, pending := range d.pending {
+		if pending.Signal.Symbol == event.Entry.Symbol && pending.Signal.Side == event.Entry.Side {
+			if event.Type == watcher.EntryRemoved {
+				continue
+			}
+			entry := event.Entry
+			pending.Signal = &entry
+		}
+		kept = append(kept, pending)
+	}
+	d.pending = kept
+}
+
+// Flush publishes the buffered signal events to every notifier
+func (d *Dispatcher) Flush() {
+	d.mutex.Lock()
+	pending := d.pending
+	d.pending = nil
+	d.mutex.Unlock()
+
+	for _, event := range pending {
+		d.Publish(event)
+	}
+}
+
+// Publish queues an event for every notifier without blocking
+// When a notifier's queue is full the event is dropped for that notifier only
+func (d *Dispatcher) Publish(event Event) {
+	for _, target := range d.targets {
+		if d.filter != nil {
+			if event.Type == SignalEvent && event.Signal != nil && !d.filter(target.notifier.Name(), *event.Signal) {
+				continue
+			}
+			if event.Type == TriggerEvent && event.Trigger != nil && !d.filter(target.notifier.Name(), event.Trigger.Setup) {
+				continue
+			}
+		}
+		select {
+		case target.queue <- event:
+		default:
+			log.Printf("⚠️  Notifier %s is backed up; dropped %s event", target.notifier.Name(), event.Type)
+		}
+	}
+}
+
+// Close stops accepting events and waits until every queued event was delivered or given up on
+func (d *Dispatcher) Close() {
+	for _, target := range d.targets {
+		close(target.queue)
+	}
+
+	d.mutex.Lock()
+	started := d.started
+	d.mutex.Unlock()
+	if started {
+		d.wg.Wait()
+	}
+}
+
+// run delivers a notifier's queued events one at a time, honouring its rate limit
+func (d *Dispatcher) run(target *dispatchTarget) {
+	defer d.wg.Done()
+
+	var lastSent time.Time
+	for event := range target.queue {
+		if wait := target.interval - time.Since(lastSent); wait > 0 {
+			time.Sleep(wait)
+		}
+		if err := deliver(target, event); err != nil {
+			log.Printf("⚠️  Notifier %s failed: %v", target.notifier.Name(), err)
+		}
+		lastSent = time.Now()
+	}
+
+	// Release producer connections once the notifier has nothing left to deliver
+	if closer, ok := target.notifier.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("⚠️  Notifier %s did not close cleanly: %v", target.notifier.Name(), err)
+		}
+	}
+}
+
+// deliver sends one event, retrying with exponential backoff
+func deliver(target *dispatchTarget, event Event) error {
+	var err error
+	for attempt := 0; attempt <= target.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(dispatchBaseBackoff << (attempt - 1))
+		}
+		if err = notifySafely(target.notifier, event); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// notifySafely calls a notifier and converts a panic into an error so one notifier cannot crash the run
+func notifySafely(notifier Notifier, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("notifier panicked: %v", r)
+		}
+	}()
+	return notifier.Notify(event)
+}
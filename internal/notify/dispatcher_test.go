@@ -0,0 +1,82 @@
+package notify_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/notify"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// recordingNotifier collects the events delivered to it
+type recordingNotifier struct {
+	events []notify.Event // Delivered events
+	mutex  sync.Mutex     // Mutex guarding events
+}
+
+func (n *recordingNotifier) Name() string {
+	return "recorder"
+}
+
+func (n *recordingNotifier) Notify(event notify.Event) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.events = append(n.events, event)
+	return nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return len(n.events)
+}
+
+func TestDispatcherStreamsSignalsBeforeFlush(t *testing.T) {
+	added := watcher.WatchListEvent{
+		Type:  watcher.EntryAdded,
+		Entry: watcher.WatchListEntry{Signal: watcher.Signal{Symbol: "AAPL", Side: watcher.LongSide}},
+		Time:  time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC),
+	}
+
+	for _, streaming := range []bool{true, false} {
+		notifier := &recordingNotifier{}
+		dispatcher := notify.NewDispatcher(false)
+		dispatcher.SetStreaming(streaming)
+		dispatcher.Register(notifier, 0, 0)
+		dispatcher.Start()
+
+		dispatcher.HandleWatchListEvent(added)
+		// Close waits until everything published so far was delivered, without flushing buffered signals
+		dispatcher.Close()
+
+		want := 0
+		if streaming {
+			want = 1
+		}
+		if got := notifier.count(); got != want {
+			t.Errorf("streaming %t: %d signals delivered before Flush, want %d", streaming, got, want)
+		}
+		if streaming && notifier.events[0].Signal.Symbol != "AAPL" {
+			t.Errorf("streamed signal = %+v, want AAPL", notifier.events[0].Signal)
+		}
+	}
+}
+
+func TestDispatcherFlushesBufferedSignals(t *testing.T) {
+	notifier := &recordingNotifier{}
+	dispatcher := notify.NewDispatcher(false)
+	dispatcher.Register(notifier, 0, 0)
+	dispatcher.Start()
+
+	dispatcher.HandleWatchListEvent(watcher.WatchListEvent{
+		Type:  watcher.EntryAdded,
+		Entry: watcher.WatchListEntry{Signal: watcher.Signal{Symbol: "AAPL", Side: watcher.LongSide}},
+	})
+	dispatcher.Flush()
+	dispatcher.Close()
+
+	if got := notifier.count(); got != 1 {
+		t.Errorf("%d signals delivered after Flush, want 1", got)
+	}
+}
@@ -0,0 +1,180 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpsPort is the port on which SMTP servers expect implicit TLS instead of STARTTLS
+const smtpsPort = 465
+
+// EmailNotifier sends end-of-run HTML reports through an SMTP server
+// Headless scheduled runs rely on it to surface new signals and failures without reading logs
+type EmailNotifier struct {
+	host     string         // SMTP server host name
+	port     int            // SMTP server port (465 uses implicit TLS, others STARTTLS when offered)
+	username string         // SMTP user name (empty disables authentication)
+	password string         // SMTP password
+	from     string         // Sender address
+	to       []string       // Recipient addresses
+	location *time.Location // Timezone used to render report timestamps
+}
+
+// NewEmailNotifier creates an email notifier for the given SMTP server and recipients
+func NewEmailNotifier(host string, port int, username, password, from string, to []string, location *time.Location) *EmailNotifier {
+	if location == nil {
+		location = time.Local
+	}
+	return &EmailNotifier{
+		host:     host,     // Store the SMTP host
+		port:     port,     // Store the SMTP port
+		username: username, // Store the SMTP user name
+		password: password, // Store the SMTP password
+		from:     from,     // Store the sender address
+		to:       to,       // Store the recipients
+		location: location, // Store the display timezone
+	}
+}
+
+// SendRunReport renders the run report as HTML and emails it to every recipient
+func (n *EmailNotifier) SendRunReport(report RunReport) error {
+	body, err := n.renderRunReport(report)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("SAPAN scan: %d new setups, %d errors", len(report.NewSignals), report.Errors)
+	return n.send(subject, body)
+}
+
+// renderRunReport renders the HTML body of the run report email
+func (n *EmailNotifier) renderRunReport(report RunReport) (string, error) {
+	var buffer bytes.Buffer
+	err := runReportTemplate.Execute(&buffer, struct {
+		RunReport
+		Started  string
+		Duration string
+		Quota    string
+	}{
+		RunReport: report,
+		Started:   report.StartedAt.In(n.location).Format("2006-01-02 15:04 MST"),
+		Duration:  report.Duration().Round(time.Second).String(),
+		Quota:     formatQuota(report),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render email report: %v", err)
+	}
+	return buffer.String(), nil
+}
+
+// formatQuota renders API usage such as "18 / 25 (72%)", or just the request count when the quota is unknown
+func formatQuota(report RunReport) string {
+	if report.APIQuota <= 0 {
+		return strconv.Itoa(report.APIRequests)
+	}
+	return fmt.Sprintf("%d / %d (%.0f%%)", report.APIRequests, report.APIQuota, report.QuotaUsage()*100)
+}
+
+// send delivers an HTML message to all recipients
+func (n *EmailNotifier) send(subject, htmlBody string) error {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n\r\n")
+	message.WriteString(htmlBody)
+
+	address := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+
+	if n.port != smtpsPort {
+		// SendMail upgrades the connection with STARTTLS whenever the server offers it
+		if err := smtp.SendMail(address, auth, n.from, n.to, message.Bytes()); err != nil {
+			return fmt.Errorf("failed to send email: %v", err)
+		}
+		return nil
+	}
+
+	conn, err := tls.Dial("tcp", address, &tls.Config{ServerName: n.host})
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %v", err)
+	}
+	defer client.Close()
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate with SMTP server: %v", err)
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	for _, recipient := range n.to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %v", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	if _, err := writer.Write(message.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return client.Quit()
+}
+
+// runReportTemplate is the HTML layout of the end-of-run email
+var runReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>SAPAN Scan Report</h2>
+<p>Started {{.Started}} &middot; took {{.Duration}}</p>
+<table cellpadding="4">
+<tr><td>Processed</td><td>{{.Total}}</td></tr>
+<tr><td>Successful</td><td>{{.Successful}}</td></tr>
+<tr><td>Errors</td><td>{{.Errors}}</td></tr>
+<tr><td>Setups</td><td>{{.Valid}} ({{.LongCount}} long / {{.ShortCount}} short)</td></tr>
+<tr><td>API requests</td><td>{{.Quota}}</td></tr>
+</table>
+
+<h3>New Setups ({{len .NewSignals}})</h3>
+{{if .NewSignals}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Symbol</th><th>Side</th><th>Pattern</th><th>Score</th><th>Entry</th><th>Stop</th><th>Target</th><th>Sector</th><th>Chart</th></tr>
+{{range .NewSignals}}<tr><td>{{.Symbol}}</td><td>{{.Side}}</td><td>{{.Pattern}}</td><td>{{printf "%.1f" .Score}}</td><td>{{printf "%.2f" .Entry}}</td><td>{{printf "%.2f" .Stop}}</td><td>{{printf "%.2f" .Target}}</td><td>{{.Sector}}</td><td>{{with .Charts}}<a href="{{.TradingView}}">TradingView</a>{{if .Yahoo}} &middot; <a href="{{.Yahoo}}">Yahoo</a>{{end}}{{end}}</td></tr>
+{{end}}</table>
+{{else}}<p>No new setups.</p>{{end}}
+
+{{if .Failures}}
+<h3>Errors ({{len .Failures}})</h3>
+<ul>
+{{range .Failures}}<li><b>{{.Symbol}}</b>: {{.Error}}</li>
+{{end}}</ul>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,58 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"strings"
+)
+
+// signalTitle returns a short headline for a signal, e.g. "SAPAN Long AAPL"
+func signalTitle(entry watcher.WatchListEntry) string {
+	return fmt.Sprintf("SAPAN %s %s", entry.Side, entry.Symbol)
+}
+
+// signalMessage returns a compact, plain-text description of a signal suitable for push and SMS messages
+func signalMessage(entry watcher.WatchListEntry) string {
+	var parts []string
+	if entry.Pattern != "" {
+		parts = append(parts, entry.Pattern)
+	}
+	parts = append(parts, fmt.Sprintf("score %.0f", entry.Score))
+	if entry.Probability > 0 {
+		parts = append(parts, fmt.Sprintf("win %.0f%%", entry.Probability*100))
+	}
+	if entry.Entry > 0 {
+		parts = append(parts, fmt.Sprintf("entry %.2f stop %.2f target %.2f", entry.Entry, entry.Stop, entry.Target))
+	}
+	if entry.Sector != "" {
+		parts = append(parts, entry.Sector)
+	}
+	if entry.CorrelatedWith != "" {
+		parts = append(parts, fmt.Sprintf("moves with %s (%.2f)", entry.CorrelatedWith, entry.Correlation))
+	}
+	if entry.LowConfidence != "" {
+		parts = append(parts, "low confidence: "+entry.LowConfidence)
+	}
+	return strings.Join(parts, " | ")
+}
+
+// chartURLs returns the TradingView and Yahoo chart links of a signal (empty when the signal has none)
+func chartURLs(entry watcher.WatchListEntry) (tradingView, yahoo string) {
+	if entry.Charts == nil {
+		return "", ""
+	}
+	return entry.Charts.TradingView, entry.Charts.Yahoo
+}
+
+// runTitle returns the headline of a run summary notification
+func runTitle(report RunReport) string {
+	return fmt.Sprintf("SAPAN scan: %d new setups", len(report.NewSignals))
+}
+
+// runMessage returns a compact, plain-text summary of a finished run
+func runMessage(report RunReport) string {
+	return fmt.Sprintf("%d processed, %d setups (%d long / %d short), %d errors",
+		report.Total, report.Valid, report.LongCount, report.ShortCount, report.Errors)
+}
@@ -0,0 +1,215 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// KafkaSchemaVersion is the version of the record layouts below; it changes only on incompatible changes
+// The Avro schemas in schemas/ describe the same records and must be updated together with them
+const KafkaSchemaVersion = 1
+
+// kafkaWriteTimeout bounds one publish, including broker acknowledgements
+const kafkaWriteTimeout = 10 * time.Second
+
+// KafkaSignalRecord is the JSON value published to the signal topic for every detected setup
+// The record is flat and versioned so consumers do not depend on the internal watch list layout
+type KafkaSignalRecord struct {
+	SchemaVersion  int       `json:"schema_version"`  // Record layout version (KafkaSchemaVersion)
+	Event          string    `json:"event"`           // Always signal.detected
+	SentAt         time.Time `json:"sent_at"`         // Time the record was published
+	Symbol         string    `json:"symbol"`          // Stock ticker symbol
+	Name           string    `json:"name"`            // Full company name
+	Sector         string    `json:"sector"`          // Business sector
+	Industry       string    `json:"industry"`        // Industry within the sector
+	Exchange       string    `json:"exchange"`        // Listing exchange (empty when unknown)
+	Currency       string    `json:"currency"`        // Currency the prices are quoted in (empty when unknown)
+	Country        string    `json:"country"`         // Country of the listing (empty when unknown)
+	ISIN           string    `json:"isin"`            // International Securities Identification Number (empty when unknown)
+	Side           string    `json:"side"`            // Long or Short
+	Pattern        string    `json:"pattern"`         // Candlestick pattern that confirmed the setup
+	Score          float64   `json:"score"`           // Setup quality score from 0 to 100
+	Probability    float64   `json:"probability"`     // Predicted chance of reaching the target (0 when not scored)
+	Entry          float64   `json:"entry"`           // Suggested entry trigger price
+	Stop           float64   `json:"stop"`            // Suggested stop-loss price
+	Target         float64   `json:"target"`          // Suggested profit target price
+	Close          float64   `json:"close"`           // Closing price of the last candle
+	Volume         int64     `json:"volume"`          // Volume of the last candle
+	CandleDate     time.Time `json:"candle_date"`     // Date of the last candle analyzed
+	DetectedAt     time.Time `json:"detected_at"`     // Time the setup was detected
+	FirstDetected  time.Time `json:"first_detected"`  // Time the setup first appeared on the watch list
+	Detections     int       `json:"detections"`      // Number of runs that detected the setup
+	CorrelatedWith string    `json:"correlated_with"` // Stronger setup this one moves with (empty when none)
+	Correlation    float64   `json:"correlation"`     // Correlation of recent returns with CorrelatedWith
+	TradingViewURL string    `json:"tradingview_url"` // TradingView chart of the setup (empty when unknown)
+	YahooURL       string    `json:"yahoo_url"`       // Yahoo Finance chart of the setup (empty when unknown)
+}
+
+// KafkaRunRecord is the JSON value published to the run topic when a scan finishes
+type KafkaRunRecord struct {
+	SchemaVersion int          `json:"schema_version"` // Record layout version (KafkaSchemaVersion)
+	Event         string       `json:"event"`          // Always run.completed
+	SentAt        time.Time    `json:"sent_at"`        // Time the record was published
+	StartedAt     time.Time    `json:"started_at"`     // Time the scan started
+	FinishedAt    time.Time    `json:"finished_at"`    // Time the scan finished
+	Total         int          `json:"total"`          // Stocks processed
+	Successful    int          `json:"successful"`     // Stocks analyzed without errors
+	Errors        int          `json:"errors"`         // Stocks that failed to process
+	Valid         int          `json:"valid"`          // Valid setups found
+	LongCount     int          `json:"long_count"`     // Long setups found
+	ShortCount    int          `json:"short_count"`    // Short setups found
+	NewSignals    []string     `json:"new_signals"`    // "SYMBOL Side" of setups new to the watch list
+	Failures      []RunFailure `json:"failures"`       // Stocks that failed, with their errors
+	APIRequests   int          `json:"api_requests"`   // Market data API requests made during the run
+	APIQuota      int          `json:"api_quota"`      // Daily API request quota (0 when unknown)
+}
+
+// KafkaNotifier publishes signals and run summaries to Kafka topics
+// Signal records are keyed by symbol so every update of a symbol lands in the same partition, in order
+type KafkaNotifier struct {
+	writer      *kafka.Writer // Producer shared by both topics
+	signalTopic string        // Topic receiving signal records
+	runTopic    string        // Topic receiving run records (empty skips run summaries)
+}
+
+// NewKafkaNotifier creates a producer for the given brokers
+// SASL/PLAIN authentication is used when username is set; useTLS encrypts the broker connections
+func NewKafkaNotifier(brokers []string, signalTopic, runTopic, username, password string, useTLS bool) *KafkaNotifier {
+	transport := &kafka.Transport{ClientID: "sapan"}
+	if username != "" {
+		transport.SASL = plain.Mechanism{Username: username, Password: password}
+	}
+	if useTLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &KafkaNotifier{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},    // Partition by key
+			RequiredAcks: kafka.RequireAll, // Wait for every in-sync replica
+			BatchTimeout: 10 * time.Millisecond,
+			Transport:    transport,
+		},
+		signalTopic: signalTopic, // Store the signal topic
+		runTopic:    runTopic,    // Store the run topic
+	}
+}
+
+// Name returns the notifier name used in logs
+func (n *KafkaNotifier) Name() string {
+	return "kafka"
+}
+
+// Notify publishes signal and run events to their topics
+func (n *KafkaNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		return n.publish(n.signalTopic, event.Signal.Symbol, WebhookSignalEvent, NewKafkaSignalRecord(*event.Signal))
+	case event.Type == RunEvent && event.Run != nil && n.runTopic != "":
+		key := strconv.FormatInt(event.Run.StartedAt.Unix(), 10)
+		return n.publish(n.runTopic, key, WebhookRunEvent, NewKafkaRunRecord(*event.Run))
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the broker connections
+func (n *KafkaNotifier) Close() error {
+	return n.writer.Close()
+}
+
+// publish encodes one record and writes it with event and schema headers
+func (n *KafkaNotifier) publish(topic, key, eventName string, record interface{}) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode Kafka record: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaWriteTimeout)
+	defer cancel()
+	err = n.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: "sapan-event", Value: []byte(eventName)},
+			{Key: "sapan-schema-version", Value: []byte(strconv.Itoa(KafkaSchemaVersion))},
+			{Key: "content-type", Value: []byte("application/json")},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to Kafka topic %s: %v", topic, err)
+	}
+	return nil
+}
+
+// NewKafkaSignalRecord flattens a watch list entry into the published signal record
+func NewKafkaSignalRecord(entry watcher.WatchListEntry) KafkaSignalRecord {
+	record := KafkaSignalRecord{
+		SchemaVersion:  KafkaSchemaVersion,
+		Event:          WebhookSignalEvent,
+		SentAt:         time.Now().UTC(),
+		Symbol:         entry.Symbol,
+		Name:           entry.Name,
+		Sector:         entry.Sector,
+		Industry:       entry.Industry,
+		Exchange:       entry.Exchange,
+		Currency:       entry.Currency,
+		Country:        entry.Country,
+		ISIN:           entry.ISIN,
+		Side:           entry.Side,
+		Pattern:        entry.Pattern,
+		Score:          entry.Score,
+		Probability:    entry.Probability,
+		Entry:          entry.Entry,
+		Stop:           entry.Stop,
+		Target:         entry.Target,
+		Close:          entry.Close,
+		Volume:         entry.Volume,
+		CandleDate:     entry.CandleDate,
+		DetectedAt:     entry.DetectedAt,
+		FirstDetected:  entry.FirstDetected,
+		Detections:     entry.Detections,
+		CorrelatedWith: entry.CorrelatedWith,
+		Correlation:    entry.Correlation,
+	}
+	record.TradingViewURL, record.YahooURL = chartURLs(entry)
+	return record
+}
+
+// NewKafkaRunRecord converts a run report into the published run record
+func NewKafkaRunRecord(report RunReport) KafkaRunRecord {
+	record := KafkaRunRecord{
+		SchemaVersion: KafkaSchemaVersion,
+		Event:         WebhookRunEvent,
+		SentAt:        time.Now().UTC(),
+		StartedAt:     report.StartedAt,
+		FinishedAt:    report.FinishedAt,
+		Total:         report.Total,
+		Successful:    report.Successful,
+		Errors:        report.Errors,
+		Valid:         report.Valid,
+		LongCount:     report.LongCount,
+		ShortCount:    report.ShortCount,
+		NewSignals:    make([]string, 0, len(report.NewSignals)),
+		Failures:      report.Failures,
+		APIRequests:   report.APIRequests,
+		APIQuota:      report.APIQuota,
+	}
+	for _, entry := range report.NewSignals {
+		record.NewSignals = append(record.NewSignals, entry.Symbol+" "+entry.Side)
+	}
+	if record.Failures == nil {
+		record.Failures = []RunFailure{} // Encode as [] rather than null for a stable schema
+	}
+	return record
+}
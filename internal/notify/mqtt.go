@@ -0,0 +1,115 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// mqttTimeout bounds connecting to the broker and waiting for a publish acknowledgement
+const mqttTimeout = 10 * time.Second
+
+// MQTTNotifier publishes signals and run summaries to an MQTT broker for home dashboards and Node-RED flows
+// Signals go to <prefix>/signals/<side>/<SYMBOL>, price triggers to <prefix>/triggers/<side>/<SYMBOL>, and run
+// summaries to <prefix>/runs/latest
+type MQTTNotifier struct {
+	options *mqtt.ClientOptions // Connection options (broker, credentials, client ID)
+	prefix  string              // Topic prefix
+	qos     byte                // MQTT quality of service (0, 1, or 2)
+	retain  bool                // Keep the last message of every topic on the broker for new subscribers
+	client  mqtt.Client         // Connected client (nil until the first delivery)
+	mutex   sync.Mutex          // Protects client
+}
+
+// NewMQTTNotifier creates an MQTT notifier; the broker connection is opened on the first delivery
+func NewMQTTNotifier(broker, clientID, username, password, prefix string, qos int, retain bool) *MQTTNotifier {
+	options := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetUsername(username).
+		SetPassword(password).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(true)
+	return &MQTTNotifier{
+		options: options,                         // Store the connection options
+		prefix:  strings.TrimSuffix(prefix, "/"), // Store the prefix without a trailing slash
+		qos:     byte(qos),                       // Store the quality of service
+		retain:  retain,                          // Store the retain flag
+	}
+}
+
+// Name returns the notifier name used in logs
+func (n *MQTTNotifier) Name() string {
+	return "mqtt"
+}
+
+// Notify publishes signal, run, and trigger events to their topics
+func (n *MQTTNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		topic := fmt.Sprintf("%s/signals/%s/%s", n.prefix, strings.ToLower(event.Signal.Side), event.Signal.Symbol)
+		return n.publish(topic, event.Signal)
+	case event.Type == RunEvent && event.Run != nil:
+		return n.publish(n.prefix+"/runs/latest", event.Run)
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		topic := fmt.Sprintf("%s/triggers/%s/%s", n.prefix, strings.ToLower(event.Trigger.Setup.Side), event.Trigger.Setup.Symbol)
+		return n.publish(topic, event.Trigger)
+	}
+	return nil
+}
+
+// Close disconnects from the broker after in-flight messages were sent
+func (n *MQTTNotifier) Close() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.client != nil {
+		n.client.Disconnect(250)
+		n.client = nil
+	}
+	return nil
+}
+
+// connect returns the connected client, connecting on first use
+func (n *MQTTNotifier) connect() (mqtt.Client, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.client != nil {
+		return n.client, nil
+	}
+	client := mqtt.NewClient(n.options)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, fmt.Errorf("timed out connecting to MQTT broker")
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %v", err)
+	}
+	n.client = client
+	return client, nil
+}
+
+// publish encodes a payload as JSON and publishes it, waiting for the broker's acknowledgement
+func (n *MQTTNotifier) publish(topic string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode MQTT payload: %v", err)
+	}
+	client, err := n.connect()
+	if err != nil {
+		return err
+	}
+	token := client.Publish(topic, n.qos, n.retain, body)
+	if !token.WaitTimeout(mqttTimeout) {
+		return fmt.Errorf("timed out publishing to %s", topic)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %v", topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"github.com/erhankrygt/sapan/watcher"
+	"time"
+)
+
+// EventType identifies what a notification event carries
+type EventType string
+
+const (
+	SignalEvent  EventType = "signal"  // A setup was detected
+	RunEvent     EventType = "run"     // A scan finished
+	TriggerEvent EventType = "trigger" // The price of a watch list setup crossed its entry trigger or stop
+)
+
+// Event is a single notification delivered to every registered notifier
+// Exactly one of Signal, Run, and Trigger is set, depending on Type
+type Event struct {
+	Type    EventType               // Kind of event
+	Signal  *watcher.WatchListEntry // Detected setup for signal events
+	Run     *RunReport              // Run summary for run events
+	Trigger *PriceTrigger           // Crossed level for trigger events
+	Time    time.Time               // Time the event was raised
+}
+
+// Notifier delivers notification events to one external channel
+// Implementations ignore event types they do not support and return an error only for failed deliveries
+type Notifier interface {
+	Name() string             // Short name used in logs (e.g. "email", "webhook")
+	Notify(event Event) error // Deliver a single event
+}
+
+// Name returns the notifier name used in logs
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Notify emails run reports; signal events are summarized in the run report instead
+func (n *EmailNotifier) Notify(event Event) error {
+	if event.Type != RunEvent || event.Run == nil {
+		return nil
+	}
+	return n.SendRunReport(*event.Run)
+}
+
+// Name returns the notifier name used in logs
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify posts signal, run, and trigger events to every webhook URL
+func (n *WebhookNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		return n.SendSignal(*event.Signal)
+	case event.Type == RunEvent && event.Run != nil:
+		return n.SendRunReport(*event.Run)
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		return n.SendTrigger(*event.Trigger)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// pushoverAPIURL is the Pushover message endpoint
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// NtfyNotifier publishes signals and run summaries to an ntfy topic (ntfy.sh or a self-hosted server)
+type NtfyNotifier struct {
+	server string       // ntfy server base URL
+	topic  string       // Topic phones subscribe to
+	token  string       // Access token for protected topics (empty for public topics)
+	client *http.Client // HTTP client with a request timeout
+}
+
+// NewNtfyNotifier creates an ntfy notifier for a topic on the given server
+func NewNtfyNotifier(server, topic, token string) *NtfyNotifier {
+	return &NtfyNotifier{
+		server: strings.TrimRight(server, "/"),          // Store the server without a trailing slash
+		topic:  topic,                                   // Store the topic
+		token:  token,                                   // Store the access token
+		client: &http.Client{Timeout: 10 * time.Second}, // Bound every request
+	}
+}
+
+// Name returns the notifier name used in logs
+func (n *NtfyNotifier) Name() string {
+	return "ntfy"
+}
+
+// Notify publishes signal, run, and trigger events as push messages
+func (n *NtfyNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		chart, _ := chartURLs(*event.Signal)
+		return n.publish(signalTitle(*event.Signal), signalMessage(*event.Signal), "high", "chart_with_upwards_trend", chart)
+	case event.Type == RunEvent && event.Run != nil:
+		return n.publish(runTitle(*event.Run), runMessage(*event.Run), "default", "", "")
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		chart, _ := chartURLs(event.Trigger.Setup)
+		return n.publish(triggerTitle(*event.Trigger), triggerMessage(*event.Trigger), "urgent", "rotating_light", chart)
+	}
+	return nil
+}
+
+// publish posts one message to the topic; a click URL opens when the notification is tapped
+func (n *NtfyNotifier) publish(title, message, priority, tags, click string) error {
+	req, err := http.NewRequest(http.MethodPost, n.server+"/"+url.PathEscape(n.topic), strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Priority", priority)
+	if tags != "" {
+		req.Header.Set("Tags", tags)
+	}
+	if click != "" {
+		req.Header.Set("Click", click)
+	}
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return doPush(n.client, req, "ntfy")
+}
+
+// PushoverNotifier sends signals and run summaries through the Pushover API
+type PushoverNotifier struct {
+	appToken string       // Pushover application token
+	userKey  string       // Pushover user or group key receiving the messages
+	client   *http.Client // HTTP client with a request timeout
+}
+
+// NewPushoverNotifier creates a Pushover notifier for the given application token and user key
+func NewPushoverNotifier(appToken, userKey string) *PushoverNotifier {
+	return &PushoverNotifier{
+		appToken: appToken,                                // Store the application token
+		userKey:  userKey,                                 // Store the user key
+		client:   &http.Client{Timeout: 10 * time.Second}, // Bound every request
+	}
+}
+
+// Name returns the notifier name used in logs
+func (n *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+// Notify sends signal, run, and trigger events as Pushover messages
+func (n *PushoverNotifier) Notify(event Event) error {
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		chart, _ := chartURLs(*event.Signal)
+		return n.send(signalTitle(*event.Signal), signalMessage(*event.Signal), 1, chart)
+	case event.Type == RunEvent && event.Run != nil:
+		return n.send(runTitle(*event.Run), runMessage(*event.Run), 0, "")
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		chart, _ := chartURLs(event.Trigger.Setup)
+		return n.send(triggerTitle(*event.Trigger), triggerMessage(*event.Trigger), 1, chart)
+	}
+	return nil
+}
+
+// send posts one message; priority 1 bypasses the user's quiet hours and a chart URL is attached as a link
+func (n *PushoverNotifier) send(title, message string, priority int, chart string) error {
+	form := url.Values{
+		"token":    {n.appToken},
+		"user":     {n.userKey},
+		"title":    {title},
+		"message":  {message},
+		"priority": {fmt.Sprint(priority)},
+	}
+	if chart != "" {
+		form.Set("url", chart)
+		form.Set("url_title", "Open chart")
+	}
+	req, err := http.NewRequest(http.MethodPost, pushoverAPIURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doPush(n.client, req, "pushover")
+}
+
+// doPush performs a push request and converts non-2xx responses into errors
+func doPush(client *http.Client, req *http.Request, service string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %v", service, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s rejected the message: %s %s", service, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"github.com/erhankrygt/sapan/watcher"
+	"time"
+)
+
+// RunReport summarizes a completed scan for end-of-run notifications
+type RunReport struct {
+	StartedAt   time.Time                `json:"started_at"`   // Time the scan started
+	FinishedAt  time.Time                `json:"finished_at"`  // Time the scan finished
+	Total       int                      `json:"total"`        // Number of stocks processed
+	Successful  int                      `json:"successful"`   // Stocks analyzed without errors
+	Errors      int                      `json:"errors"`       // Stocks that failed to process
+	Valid       int                      `json:"valid"`        // Valid SAPAN setups found in this run
+	LongCount   int                      `json:"long_count"`   // Long setups found in this run
+	ShortCount  int                      `json:"short_count"`  // Short setups found in this run
+	NewSignals  []watcher.WatchListEntry `json:"new_signals"`  // Setups that were not on the watch list before this run
+	Failures    []RunFailure             `json:"failures"`     // Stocks that failed, with their errors
+	APIRequests int                      `json:"api_requests"` // Market data API requests made during the run
+	APIQuota    int                      `json:"api_quota"`    // Daily API request quota (0 when unknown)
+}
+
+// RunFailure describes a stock that could not be processed during the run
+type RunFailure struct {
+	Symbol string `json:"symbol"` // Stock symbol that failed
+	Error  string `json:"error"`  // Error message explaining the failure
+}
+
+// Duration returns the wall-clock duration of the run
+func (r RunReport) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// QuotaUsage returns the share of the daily API quota used by the run (0 when the quota is unknown)
+func (r RunReport) QuotaUsage() float64 {
+	if r.APIQuota <= 0 {
+		return 0
+	}
+	return float64(r.APIRequests) / float64(r.APIQuota)
+}
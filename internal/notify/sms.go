@@ -0,0 +1,91 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// twilioAPIURL is the Twilio REST API base URL
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// SMSNotifier texts high-score signals through Twilio so only premium setups interrupt the user
+// Run summaries are never sent by SMS
+type SMSNotifier struct {
+	accountSID string       // Twilio account SID
+	authToken  string       // Twilio auth token
+	from       string       // Twilio sender number in E.164 format
+	to         []string     // Recipient numbers in E.164 format
+	minScore   float64      // Signals scoring below this threshold are not texted
+	client     *http.Client // HTTP client with a request timeout
+}
+
+// NewSMSNotifier creates a Twilio SMS notifier for signals scoring at least minScore
+func NewSMSNotifier(accountSID, authToken, from string, to []string, minScore float64) *SMSNotifier {
+	return &SMSNotifier{
+		accountSID: accountSID,                              // Store the account SID
+		authToken:  authToken,                               // Store the auth token
+		from:       from,                                    // Store the sender number
+		to:         to,                                      // Store the recipients
+		minScore:   minScore,                                // Store the score threshold
+		client:     &http.Client{Timeout: 10 * time.Second}, // Bound every request
+	}
+}
+
+// Name returns the notifier name used in logs
+func (n *SMSNotifier) Name() string {
+	return "sms"
+}
+
+// Notify texts a signal, or a trigger of its levels, to every recipient when the setup's score reaches the threshold
+func (n *SMSNotifier) Notify(event Event) error {
+	var setup watcher.WatchListEntry
+	var body string
+	switch {
+	case event.Type == SignalEvent && event.Signal != nil:
+		setup, body = *event.Signal, signalTitle(*event.Signal)+": "+signalMessage(*event.Signal)
+	case event.Type == TriggerEvent && event.Trigger != nil:
+		setup, body = event.Trigger.Setup, triggerTitle(*event.Trigger)+": "+triggerMessage(*event.Trigger)
+	default:
+		return nil
+	}
+	if setup.Score < n.minScore {
+		return nil
+	}
+
+	if chart, _ := chartURLs(setup); chart != "" {
+		body += " " + chart
+	}
+	var failed []string
+	for _, to := range n.to {
+		if err := n.send(to, body); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// send posts one message to the Twilio Messages resource
+func (n *SMSNotifier) send(to, body string) error {
+	form := url.Values{
+		"From": {n.from},
+		"To":   {to},
+		"Body": {body},
+	}
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPIURL, url.PathEscape(n.accountSID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(n.accountSID, n.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doPush(n.client, req, "twilio")
+}
@@ -0,0 +1,49 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"strings"
+)
+
+// TriggerKind identifies which level of a setup the price crossed
+type TriggerKind string
+
+const (
+	EntryTriggered   TriggerKind = "entry"       // The price reached the entry trigger: the setup is actionable
+	SetupInvalidated TriggerKind = "invalidated" // The price reached the stop before the entry: the setup is void
+)
+
+// PriceTrigger is a watch list setup whose entry trigger or stop was crossed by a live quote
+type PriceTrigger struct {
+	Kind  TriggerKind            `json:"kind"`  // Level that was crossed
+	Level float64                `json:"level"` // Entry trigger or stop price
+	Price float64                `json:"price"` // Quote price that crossed it
+	Setup watcher.WatchListEntry `json:"setup"` // Watch list setup the level belongs to
+}
+
+// triggerTitle returns a short headline for a trigger, e.g. "SAPAN Long AAPL triggered"
+func triggerTitle(trigger PriceTrigger) string {
+	if trigger.Kind == SetupInvalidated {
+		return fmt.Sprintf("%s invalidated", signalTitle(trigger.Setup))
+	}
+	return fmt.Sprintf("%s triggered", signalTitle(trigger.Setup))
+}
+
+// triggerMessage returns a compact, plain-text description of a trigger suitable for push and SMS messages
+func triggerMessage(trigger PriceTrigger) string {
+	setup := trigger.Setup
+	parts := []string{fmt.Sprintf("price %.2f", trigger.Price)}
+	if trigger.Kind == SetupInvalidated {
+		parts = append(parts, fmt.Sprintf("crossed stop %.2f before entry %.2f", trigger.Level, setup.Entry))
+	} else {
+		parts = append(parts, fmt.Sprintf("crossed entry %.2f", trigger.Level), fmt.Sprintf("stop %.2f target %.2f", setup.Stop, setup.Target))
+	}
+	if setup.Pattern != "" {
+		parts = append(parts, setup.Pattern)
+	}
+	parts = append(parts, fmt.Sprintf("score %.0f", setup.Score))
+	return strings.Join(parts, " | ")
+}
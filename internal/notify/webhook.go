@@ -0,0 +1,148 @@
+// Package notify delivers SAPAN signals and run reports to external channels
+// This package formats watch list changes and run summaries for email and other notification targets
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook event names sent in the payload and the X-Sapan-Event header
+const (
+	WebhookSignalEvent  = "signal.detected" // A new setup was added to the watch list
+	WebhookRunEvent     = "run.completed"   // A scan finished
+	WebhookTriggerEvent = "price.triggered" // The price of a watch list setup crossed its entry trigger or stop
+)
+
+// webhookBaseBackoff is the delay before the first retry; each further retry doubles it
+const webhookBaseBackoff = time.Second
+
+// WebhookPayload is the JSON document POSTed to webhook URLs
+// Exactly one of Signal, Run, and Trigger is set, depending on Event
+type WebhookPayload struct {
+	Event   string                  `json:"event"`             // Event name (signal.detected, run.completed, or price.triggered)
+	SentAt  time.Time               `json:"sent_at"`           // Time the payload was built
+	Signal  *watcher.WatchListEntry `json:"signal,omitempty"`  // Detected setup for signal events
+	Run     *RunReport              `json:"run,omitempty"`     // Run summary for run events
+	Trigger *PriceTrigger           `json:"trigger,omitempty"` // Crossed level for trigger events
+}
+
+// WebhookNotifier POSTs signals and run summaries to user-configured URLs
+// Requests carry an HMAC-SHA256 signature so receivers such as Zapier, n8n, or custom services can verify them
+type WebhookNotifier struct {
+	urls       []string     // Destination URLs
+	secret     string       // Shared secret used to sign payloads (empty disables signing)
+	maxRetries int          // Retries after the first attempt for failed deliveries
+	client     *http.Client // HTTP client with the configured timeout
+}
+
+// NewWebhookNotifier creates a webhook notifier for the given URLs
+func NewWebhookNotifier(urls []string, secret string, maxRetries int, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		urls:       urls,                           // Store destination URLs
+		secret:     secret,                         // Store the signing secret
+		maxRetries: maxRetries,                     // Store the retry budget
+		client:     &http.Client{Timeout: timeout}, // Bound every delivery attempt
+	}
+}
+
+// SendSignal posts a detected setup to every webhook URL
+func (n *WebhookNotifier) SendSignal(entry watcher.WatchListEntry) error {
+	return n.post(WebhookPayload{Event: WebhookSignalEvent, SentAt: time.Now().UTC(), Signal: &entry})
+}
+
+// SendRunReport posts the run summary to every webhook URL
+func (n *WebhookNotifier) SendRunReport(report RunReport) error {
+	return n.post(WebhookPayload{Event: WebhookRunEvent, SentAt: time.Now().UTC(), Run: &report})
+}
+
+// SendTrigger posts a crossed entry trigger or stop to every webhook URL
+func (n *WebhookNotifier) SendTrigger(trigger PriceTrigger) error {
+	return n.post(WebhookPayload{Event: WebhookTriggerEvent, SentAt: time.Now().UTC(), Trigger: &trigger})
+}
+
+// post encodes the payload once and delivers it to every URL, returning the first failure
+// A failing URL does not prevent delivery to the others
+func (n *WebhookNotifier) post(payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+
+	var firstErr error
+	for _, url := range n.urls {
+		if err := n.deliver(url, payload.Event, body); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// deliver POSTs a payload to one URL, retrying network errors, 429, and 5xx responses with exponential backoff
+func (n *WebhookNotifier) deliver(url, event string, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= n.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(webhookBaseBackoff << (attempt - 1))
+		}
+
+		retry, err := n.attempt(url, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return fmt.Errorf("failed to deliver webhook to %s: %v", url, lastErr)
+}
+
+// attempt performs a single delivery and reports whether a failure is worth retrying
+func (n *WebhookNotifier) attempt(url, event string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sapan-webhook/1.0")
+	req.Header.Set("X-Sapan-Event", event)
+	req.Header.Set("X-Sapan-Timestamp", timestamp)
+	if n.secret != "" {
+		req.Header.Set("X-Sapan-Signature", "sha256="+SignWebhook(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) // Drain the body so the connection can be reused
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// SignWebhook computes the hex HMAC-SHA256 signature of "<timestamp>.<body>" with the shared secret
+// Receivers recompute it from the X-Sapan-Timestamp header and the raw body; signing the timestamp prevents replays
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,215 @@
+package outcome
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/mlscore"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// minResolved is the number of resolved trades below which a group's hit rate is flagged as unreliable
+const minResolved = 10
+
+// Market regimes signals are grouped by, derived from the ADX recorded with the signal's features
+const (
+	RegimeStrongTrend = "strong trend (ADX 40+)"
+	RegimeTrend       = "trend (ADX 25-40)"
+	RegimeRange       = "range (ADX < 25)"
+	RegimeUnknown     = "unknown (no features)"
+)
+
+// CalibrationGroup holds the outcome statistics of the signals falling into one bucket
+type CalibrationGroup struct {
+	Label     string // Bucket name, e.g. "70-79" or "Technology"
+	Stats            // Statistics of the signals in the bucket
+	AtOrAbove Stats  // Statistics of this bucket and every higher one (score and probability buckets only)
+}
+
+// Calibration breaks recorded outcomes down by the attributes alert thresholds are set on
+// Score and probability buckets are ordered from low to high, the other groupings by signal count
+type Calibration struct {
+	Overall       Stats              // Statistics over every evaluated signal
+	ByScore       []CalibrationGroup // Score deciles (0-9 ... 90-100); empty deciles are omitted
+	ByProbability []CalibrationGroup // Predicted probability deciles of scored signals (empty when none were scored)
+	ByPattern     []CalibrationGroup // Confirming candlestick pattern
+	BySector      []CalibrationGroup // Business sector ("Unknown" when the stock list had none)
+	ByRegime      []CalibrationGroup // Trend regime at detection time (see RegimeOf)
+}
+
+// RegimeOf classifies the trend regime a signal was detected in from the ADX stored with its features
+// Signals recorded without ML_SCORING carry no features and are reported as unknown
+func RegimeOf(signal watcher.Signal) string {
+	adx, ok := signal.Features[mlscore.FeatureADX]
+	switch {
+	case !ok:
+		return RegimeUnknown
+	case adx >= 0.40:
+		return RegimeStrongTrend
+	case adx >= 0.25:
+		return RegimeTrend
+	default:
+		return RegimeRange
+	}
+}
+
+// BuildCalibration groups recorded outcomes by score decile, probability decile, pattern, sector, and regime
+func BuildCalibration(outcomes []watcher.SignalOutcome) Calibration {
+	var calibration Calibration
+	scores := make(map[int]*Stats)
+	probabilities := make(map[int]*Stats)
+	patterns := make(map[string]*Stats)
+	sectors := make(map[string]*Stats)
+	regimes := make(map[string]*Stats)
+
+	for _, outcome := range outcomes {
+		calibration.Overall.add(outcome)
+		addTo(scores, decile(outcome.Score/100), outcome)
+		if outcome.Probability > 0 {
+			addTo(probabilities, decile(outcome.Probability), outcome)
+		}
+		addTo(patterns, labelOr(outcome.Pattern, "Unknown"), outcome)
+		addTo(sectors, labelOr(outcome.Sector, "Unknown"), outcome)
+		addTo(regimes, RegimeOf(outcome.Signal), outcome)
+	}
+
+	calibration.ByScore = decileGroups(scores, 1)
+	calibration.ByProbability = decileGroups(probabilities, 0.1)
+	calibration.ByPattern = labelGroups(patterns)
+	calibration.BySector = labelGroups(sectors)
+	calibration.ByRegime = labelGroups(regimes)
+	return calibration
+}
+
+// addTo folds an outcome into the statistics of a bucket, creating the bucket on first use
+func addTo[K comparable](buckets map[K]*Stats, key K, outcome watcher.SignalOutcome) {
+	stats, ok := buckets[key]
+	if !ok {
+		stats = &Stats{}
+		buckets[key] = stats
+	}
+	stats.add(outcome)
+}
+
+// decile returns the decile (0-9) of a value between 0 and 1, putting 1 itself into the top decile
+func decile(value float64) int {
+	return max(0, min(9, int(value*10)))
+}
+
+// labelOr returns label, or fallback when label is empty
+func labelOr(label, fallback string) string {
+	if label == "" {
+		return fallback
+	}
+	return label
+}
+
+// decileGroups turns decile buckets into groups ordered from low to high with cumulative at-or-above statistics
+// unit scales the decile edges for the labels: 1 labels scores 0-9 ... 90-100, 0.1 labels probabilities 0.0-0.1
+func decileGroups(buckets map[int]*Stats, unit float64) []CalibrationGroup {
+	var groups []CalibrationGroup
+	var atOrAbove Stats
+	for d := 9; d >= 0; d-- {
+		stats, ok := buckets[d]
+		if !ok {
+			continue
+		}
+		atOrAbove.merge(*stats)
+		groups = append(groups, CalibrationGroup{Label: decileLabel(d, unit), Stats: *stats, AtOrAbove: atOrAbove})
+	}
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	return groups
+}
+
+// decileLabel names a decile bucket, e.g. "70-79" for scores or "0.7-0.8" for probabilities
+func decileLabel(d int, unit float64) string {
+	if unit == 1 {
+		if d == 9 {
+			return "90-100"
+		}
+		return fmt.Sprintf("%d-%d", d*10, d*10+9)
+	}
+	return fmt.Sprintf("%.1f-%.1f", float64(d)*unit, float64(d+1)*unit)
+}
+
+// labelGroups turns named buckets into groups ordered by signal count, then by name
+func labelGroups(buckets map[string]*Stats) []CalibrationGroup {
+	groups := make([]CalibrationGroup, 0, len(buckets))
+	for label, stats := range buckets {
+		groups = append(groups, CalibrationGroup{Label: label, Stats: *stats})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Signals != groups[j].Signals {
+			return groups[i].Signals > groups[j].Signals
+		}
+		return groups[i].Label < groups[j].Label
+	})
+	return groups
+}
+
+// merge adds the counts of other to the statistics
+func (s *Stats) merge(other Stats) {
+	s.Signals += other.Signals
+	s.Triggered += other.Triggered
+	s.Targets += other.Targets
+	s.Stops += other.Stops
+	s.TimeStops += other.TimeStops
+	s.Open += other.Open
+	s.NotTriggered += other.NotTriggered
+	s.TotalR += other.TotalR
+}
+
+// Resolved returns the number of trades that hit their target or stop
+func (s Stats) Resolved() int {
+	return s.Targets + s.Stops
+}
+
+// Print renders one table per grouping; groups with fewer than 10 resolved trades are highlighted in yellow
+func (c Calibration) Print(w io.Writer, palette *output.Palette) {
+	fmt.Fprintln(w, palette.Bold("Signal Calibration Report:"))
+	fmt.Fprintf(w, "%d signals, %d resolved, hit rate %.0f%%, avg R %.2f\n",
+		c.Overall.Signals, c.Overall.Resolved(), c.Overall.HitRate()*100, c.Overall.AverageR())
+
+	printGroups(w, palette, "Score", c.ByScore, true)
+	if len(c.ByProbability) > 0 {
+		printGroups(w, palette, "Probability", c.ByProbability, true)
+	}
+	printGroups(w, palette, "Pattern", c.ByPattern, false)
+	printGroups(w, palette, "Sector", c.BySector, false)
+	printGroups(w, palette, "Regime", c.ByRegime, false)
+
+	fmt.Fprintf(w, "\nHit rate counts resolved trades only; yellow rows have fewer than %d and are not reliable yet.\n", minResolved)
+}
+
+// printGroups renders the groups of one attribute, with cumulative at-or-above columns for ordered buckets
+func printGroups(w io.Writer, palette *output.Palette, title string, groups []CalibrationGroup, cumulative bool) {
+	headers := []string{title, "Signals", "Triggered", "Resolved", "Hit rate", "Avg R"}
+	if cumulative {
+		headers = append(headers, "Hit rate at or above", "Resolved at or above")
+	}
+	table := output.NewTable(palette, headers...)
+	for _, group := range groups {
+		color := palette.Plain
+		if group.Resolved() < minResolved {
+			color = palette.Yellow
+		}
+		cells := []string{
+			group.Label,
+			strconv.Itoa(group.Signals),
+			fmt.Sprintf("%.0f%%", group.TriggerRate()*100),
+			strconv.Itoa(group.Resolved()),
+			fmt.Sprintf("%.0f%%", group.HitRate()*100),
+			fmt.Sprintf("%.2f", group.AverageR()),
+		}
+		if cumulative {
+			cells = append(cells, fmt.Sprintf("%.0f%%", group.AtOrAbove.HitRate()*100), strconv.Itoa(group.AtOrAbove.Resolved()))
+		}
+		table.AddRow(color, cells...)
+	}
+	fmt.Fprintln(w)
+	table.Render(w)
+}
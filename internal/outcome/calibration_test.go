@@ -0,0 +1,74 @@
+package outcome
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/internal/mlscore"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// resolved returns an outcome that hit its target or stop
+func resolved(score float64, pattern, sector string, adx float64, target bool) watcher.SignalOutcome {
+	outcome := watcher.SignalOutcome{
+		Signal:         watcher.Signal{Score: score, Pattern: pattern, Sector: sector},
+		Status:         watcher.OutcomeStop,
+		EntryTriggered: true,
+		RMultiple:      -1,
+	}
+	if adx >= 0 {
+		outcome.Features = watcher.SignalFeatures{mlscore.FeatureADX: adx}
+	}
+	if target {
+		outcome.Status, outcome.RMultiple = watcher.OutcomeTarget, 2
+	}
+	return outcome
+}
+
+func TestBuildCalibrationGroupsOutcomes(t *testing.T) {
+	outcomes := []watcher.SignalOutcome{
+		resolved(95, "Pinbar", "Technology", 0.45, true),
+		resolved(100, "Pinbar", "Technology", 0.30, true),
+		resolved(72, "Engulfing", "Energy", 0.30, false),
+		resolved(75, "Pinbar", "", 0.10, true),
+		resolved(40, "Engulfing", "Energy", -1, false),
+	}
+	calibration := BuildCalibration(outcomes)
+
+	if calibration.Overall.Signals != 5 || calibration.Overall.Targets != 3 {
+		t.Fatalf("overall = %+v, want 5 signals with 3 targets", calibration.Overall)
+	}
+	var labels []string
+	for _, group := range calibration.ByScore {
+		labels = append(labels, group.Label)
+	}
+	if len(labels) != 3 || labels[0] != "40-49" || labels[1] != "70-79" || labels[2] != "90-100" {
+		t.Fatalf("score deciles = %v, want [40-49 70-79 90-100]", labels)
+	}
+	if top := calibration.ByScore[2]; top.Signals != 2 || top.HitRate() != 1 {
+		t.Errorf("90-100 decile = %+v, want 2 signals hitting their target", top.Stats)
+	}
+	if seventies := calibration.ByScore[1]; seventies.AtOrAbove.Resolved() != 4 || seventies.AtOrAbove.Targets != 3 {
+		t.Errorf("70+ cumulative = %+v, want 3 of 4 resolved", seventies.AtOrAbove)
+	}
+	if len(calibration.ByProbability) != 0 {
+		t.Errorf("probability deciles = %+v, want none without scored signals", calibration.ByProbability)
+	}
+
+	if first := calibration.ByPattern[0]; first.Label != "Pinbar" || first.Signals != 3 {
+		t.Errorf("largest pattern group = %s with %d signals, want Pinbar with 3", first.Label, first.Signals)
+	}
+	sectors := make(map[string]int)
+	for _, group := range calibration.BySector {
+		sectors[group.Label] = group.Signals
+	}
+	if sectors["Technology"] != 2 || sectors["Energy"] != 2 || sectors["Unknown"] != 1 {
+		t.Errorf("sectors = %v, want Technology 2, Energy 2, Unknown 1", sectors)
+	}
+	regimes := make(map[string]int)
+	for _, group := range calibration.ByRegime {
+		regimes[group.Label] = group.Signals
+	}
+	if regimes[RegimeStrongTrend] != 1 || regimes[RegimeTrend] != 2 || regimes[RegimeRange] != 1 || regimes[RegimeUnknown] != 1 {
+		t.Errorf("regimes = %v, want 1 strong trend, 2 trend, 1 range, 1 unknown", regimes)
+	}
+}
@@ -0,0 +1,102 @@
+// Package outcome tracks what happened to SAPAN signals after they were detected
+// This package replays subsequent candles against each signal's trade plan and reports empirical hit rates
+package outcome
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// Stats aggregates outcomes for a group of signals
+type Stats struct {
+	Signals      int     // Number of evaluated signals
+	Triggered    int     // Signals whose entry was reached
+	Targets      int     // Trades that hit the target first
+	Stops        int     // Trades that hit the stop first
+	TimeStops    int     // Trades closed by their time stop before reaching either level
+	Open         int     // Trades still running at evaluation time
+	NotTriggered int     // Signals whose entry was never reached
+	TotalR       float64 // Sum of R-multiples over triggered trades
+}
+
+// add folds a single outcome into the statistics
+func (s *Stats) add(outcome watcher.SignalOutcome) {
+	s.Signals++
+	switch outcome.Status {
+	case watcher.OutcomeTarget:
+		s.Targets++
+	case watcher.OutcomeStop:
+		s.Stops++
+	case watcher.OutcomeTimeStop:
+		s.TimeStops++
+	case watcher.OutcomeOpen:
+		s.Open++
+	default:
+		s.NotTriggered++
+	}
+	if outcome.EntryTriggered {
+		s.Triggered++
+		s.TotalR += outcome.RMultiple
+	}
+}
+
+// HitRate returns the share of resolved trades that reached the target (0 when none resolved)
+func (s Stats) HitRate() float64 {
+	resolved := s.Targets + s.Stops
+	if resolved == 0 {
+		return 0
+	}
+	return float64(s.Targets) / float64(resolved)
+}
+
+// TriggerRate returns the share of signals whose entry was reached
+func (s Stats) TriggerRate() float64 {
+	if s.Signals == 0 {
+		return 0
+	}
+	return float64(s.Triggered) / float64(s.Signals)
+}
+
+// AverageR returns the mean R-multiple over triggered trades
+func (s Stats) AverageR() float64 {
+	if s.Triggered == 0 {
+		return 0
+	}
+	return s.TotalR / float64(s.Triggered)
+}
+
+// Report is the empirical hit-rate report for the strategy
+type Report struct {
+	Overall Stats            // Statistics over every evaluated signal
+	BySide  map[string]Stats // Statistics per trading side
+}
+
+// BuildReport aggregates recorded outcomes into a hit-rate report
+func BuildReport(outcomes []watcher.SignalOutcome) Report {
+	report := Report{BySide: make(map[string]Stats)}
+	for _, outcome := range outcomes {
+		report.Overall.add(outcome)
+		sideStats := report.BySide[outcome.Side]
+		sideStats.add(outcome)
+		report.BySide[outcome.Side] = sideStats
+	}
+	return report
+}
+
+// Print displays the report with overall and per-side statistics
+func (r Report) Print() {
+	fmt.Println("Signal Outcome Report:")
+	printStats("All", r.Overall)
+	for _, side := range []string{watcher.LongSide, watcher.ShortSide} {
+		if stats, ok := r.BySide[side]; ok {
+			printStats(side, stats)
+		}
+	}
+}
+
+// printStats prints a single labelled statistics line
+func printStats(label string, stats Stats) {
+	fmt.Printf("  %-5s signals: %d | triggered: %.0f%% | hit rate: %.0f%% (%d targets / %d stops) | time stops: %d | open: %d | avg R: %.2f\n",
+		label, stats.Signals, stats.TriggerRate()*100, stats.HitRate()*100,
+		stats.Targets, stats.Stops, stats.TimeStops, stats.Open, stats.AverageR())
+}
@@ -0,0 +1,131 @@
+// Package outcome tracks what happened to SAPAN signals after they were detected
+// This package replays subsequent candles against each signal's trade plan and reports empirical hit rates
+package outcome
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"time"
+)
+
+// Simulation is the result of replaying candles against a trade plan
+type Simulation struct {
+	Status         string    // One of the watcher.Outcome* statuses
+	EntryTriggered bool      // Whether price reached the entry level
+	EntryDate      time.Time // Date of the candle that triggered the entry
+	ExitDate       time.Time // Date of the exit candle (or last candle when still open)
+	ExitPrice      float64   // Exit price (or mark price when still open)
+	RMultiple      float64   // Result in multiples of the initial risk
+	BarsHeld       int       // Candles between entry and exit
+}
+
+// Simulate replays candles against an entry/stop/target plan for the given side
+// Candles must be sorted by date and start after the signal candle. The entry fills at the trigger price
+// or at the open when price gaps through it. When stop and target fall inside the same candle the stop
+// is assumed to have been hit first, which keeps the statistics conservative. A positive timeStop exits
+// at the close of the timeStop-th candle after the entry candle when neither level was hit by then.
+func Simulate(side string, entry, stop, target float64, timeStop int, candles []models.Candle) Simulation {
+	risk := entry - stop
+	if side == watcher.ShortSide {
+		risk = stop - entry
+	}
+	if risk <= 0 {
+		return Simulation{Status: watcher.OutcomeNotTriggered} // Invalid plan can never be traded
+	}
+
+	sim := Simulation{Status: watcher.OutcomeNotTriggered}
+	fillPrice := entry
+	entryIndex := -1
+
+	for i, candle := range candles {
+		if entryIndex < 0 {
+			if !reachesEntry(side, entry, candle) {
+				continue
+			}
+			entryIndex = i
+			fillPrice = gapAdjustedFill(side, entry, candle)
+			sim.EntryTriggered = true
+			sim.EntryDate = candle.Time()
+		}
+
+		// Check the protective stop first so ambiguous candles count as losses
+		if hitsStop(side, stop, candle) {
+			return closeSimulation(sim, side, watcher.OutcomeStop, stopFill(side, stop, candle), fillPrice, risk, candle.Time(), i-entryIndex)
+		}
+		if hitsTarget(side, target, candle) {
+			return closeSimulation(sim, side, watcher.OutcomeTarget, target, fillPrice, risk, candle.Time(), i-entryIndex)
+		}
+		if timeStop > 0 && i-entryIndex >= timeStop {
+			return closeSimulation(sim, side, watcher.OutcomeTimeStop, candle.Close, fillPrice, risk, candle.Time(), i-entryIndex)
+		}
+	}
+
+	if entryIndex < 0 || len(candles) == 0 {
+		return sim // Entry never triggered in the available candles
+	}
+
+	// Trade is still running: mark it to the last close
+	last := candles[len(candles)-1]
+	return closeSimulation(sim, side, watcher.OutcomeOpen, last.Close, fillPrice, risk, last.Time(), len(candles)-1-entryIndex)
+}
+
+// closeSimulation fills in the exit fields of a simulation
+func closeSimulation(sim Simulation, side, status string, exitPrice, fillPrice, risk float64, exitDate time.Time, bars int) Simulation {
+	sim.Status = status
+	sim.ExitPrice = exitPrice
+	sim.ExitDate = exitDate
+	sim.BarsHeld = bars
+	if side == watcher.ShortSide {
+		sim.RMultiple = (fillPrice - exitPrice) / risk
+	} else {
+		sim.RMultiple = (exitPrice - fillPrice) / risk
+	}
+	return sim
+}
+
+// reachesEntry reports whether a candle trades through the entry level
+// Levels are compared at tick precision, so a candle touching the level to the cent counts as reaching it
+func reachesEntry(side string, entry float64, candle models.Candle) bool {
+	if side == watcher.ShortSide {
+		return models.ComparePrices(candle.Low, entry) <= 0
+	}
+	return models.ComparePrices(candle.High, entry) >= 0
+}
+
+// gapAdjustedFill returns the realistic fill for a stop entry, using the open when price gapped past the level
+func gapAdjustedFill(side string, entry float64, candle models.Candle) float64 {
+	if side == watcher.ShortSide && candle.Open < entry {
+		return candle.Open
+	}
+	if side != watcher.ShortSide && candle.Open > entry {
+		return candle.Open
+	}
+	return entry
+}
+
+// hitsStop reports whether a candle reaches the stop level
+func hitsStop(side string, stop float64, candle models.Candle) bool {
+	if side == watcher.ShortSide {
+		return models.ComparePrices(candle.High, stop) >= 0
+	}
+	return models.ComparePrices(candle.Low, stop) <= 0
+}
+
+// stopFill returns the stop fill price, using the open when price gapped through the stop
+func stopFill(side string, stop float64, candle models.Candle) float64 {
+	if side == watcher.ShortSide && candle.Open > stop {
+		return candle.Open
+	}
+	if side != watcher.ShortSide && candle.Open < stop {
+		return candle.Open
+	}
+	return stop
+}
+
+// hitsTarget reports whether a candle reaches the target level
+func hitsTarget(side string, target float64, candle models.Candle) bool {
+	if side == watcher.ShortSide {
+		return models.ComparePrices(candle.Low, target) <= 0
+	}
+	return models.ComparePrices(candle.High, target) >= 0
+}
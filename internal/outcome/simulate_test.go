@@ -0,0 +1,41 @@
+package outcome
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func TestSimulateTimeStop(t *testing.T) {
+	// The entry at 101 triggers on the first candle, then price drifts between the stop at 95 and the target at 113
+	candles := sapantest.NewCandleBuilder(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), 100).
+		Candle(100, 102, 99, 101.5).
+		Candle(101.5, 103, 100, 102).
+		Candle(102, 104, 101, 103).
+		Candle(103, 104, 102, 102.5).
+		Candles()
+
+	held := Simulate(watcher.LongSide, 101, 95, 113, 0, candles)
+	if held.Status != watcher.OutcomeOpen || held.BarsHeld != 3 {
+		t.Errorf("without a time stop = %+v, want an open trade after 3 candles", held)
+	}
+
+	timed := Simulate(watcher.LongSide, 101, 95, 113, 2, candles)
+	if timed.Status != watcher.OutcomeTimeStop || timed.BarsHeld != 2 || timed.ExitPrice != 103 {
+		t.Fatalf("with a 2-candle time stop = %+v, want an exit at the close of 103 two candles after the entry", timed)
+	}
+	if want := (103.0 - 101) / 6; timed.RMultiple != want {
+		t.Errorf("R-multiple = %v, want %v", timed.RMultiple, want)
+	}
+	if !timed.ExitDate.Equal(candles[2].Time()) {
+		t.Errorf("exit date = %v, want %v", timed.ExitDate, candles[2].Time())
+	}
+
+	// Reaching a level on the last allowed candle still counts as a target or stop
+	candles[2].High = 114
+	if sim := Simulate(watcher.LongSide, 101, 95, 113, 2, candles); sim.Status != watcher.OutcomeTarget {
+		t.Errorf("target on the time stop candle = %+v, want the target", sim)
+	}
+}
@@ -0,0 +1,97 @@
+// Package outcome tracks what happened to SAPAN signals after they were detected
+// This package replays subsequent candles against each signal's trade plan and reports empirical hit rates
+package outcome
+
+import (
+	"context"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"time"
+)
+
+// Tracker is the follow-up job that evaluates signals once their tracking window has elapsed
+// It fetches the candles that followed each signal and records the resulting outcome in the signal database
+type Tracker struct {
+	store        *watcher.SQLiteSignalStore // Signal database holding signals and outcomes
+	stockFetcher data.Fetcher               // Data fetcher for retrieving subsequent candles
+	trackingDays int                        // Calendar days to wait after a signal before evaluating it
+	outputSize   int                        // Number of candles to request per symbol
+	requestDelay time.Duration              // Delay between API requests (to respect rate limits)
+}
+
+// NewTracker creates a new outcome tracker
+// Signals are evaluated trackingDays calendar days after they were detected
+func NewTracker(store *watcher.SQLiteSignalStore, stockFetcher data.Fetcher, trackingDays, outputSize int, requestDelay time.Duration) *Tracker {
+	return &Tracker{
+		store:        store,        // Initialize signal database
+		stockFetcher: stockFetcher, // Initialize data fetcher
+		trackingDays: trackingDays, // Set tracking window
+		outputSize:   outputSize,   // Set candle count per request
+		requestDelay: requestDelay, // Set request delay
+	}
+}
+
+// Run evaluates every signal whose tracking window ended before now and records its outcome
+// Candles are fetched once per symbol; failures are logged and the signal is retried on the next run
+func (t *Tracker) Run(now time.Time) (int, error) {
+	cutoff := now.AddDate(0, 0, -t.trackingDays)
+	signals, err := t.store.SignalsAwaitingOutcome(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	candleCache := make(map[string][]models.Candle)
+	recorded := 0
+	for _, signal := range signals {
+		candles, cached := candleCache[signal.Symbol]
+		if !cached {
+			candleData, err := t.stockFetcher.FetchStockData(context.Background(), signal.Symbol, t.outputSize)
+			if err != nil {
+				log.Printf("Outcome: Failed to fetch data for %s: %v", signal.Symbol, err)
+				continue
+			}
+			candles = candleData.Candles
+			candleCache[signal.Symbol] = candles
+
+			if t.requestDelay > 0 {
+				time.Sleep(t.requestDelay) // Respect API limits between symbols
+			}
+		}
+
+		outcome := Evaluate(signal, candles, now)
+		if err := t.store.RecordOutcome(outcome); err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+
+	return recorded, nil
+}
+
+// Evaluate computes the outcome of a signal from the candles that followed its signal candle
+func Evaluate(signal watcher.Signal, candles []models.Candle, now time.Time) watcher.SignalOutcome {
+	sim := Simulate(signal.Side, signal.Entry, signal.Stop, signal.Target, signal.TimeStop, candlesAfter(candles, signal.CandleDate))
+	return watcher.SignalOutcome{
+		Signal:         signal,
+		Status:         sim.Status,
+		EntryTriggered: sim.EntryTriggered,
+		EntryDate:      sim.EntryDate,
+		ExitDate:       sim.ExitDate,
+		ExitPrice:      sim.ExitPrice,
+		RMultiple:      sim.RMultiple,
+		BarsHeld:       sim.BarsHeld,
+		EvaluatedAt:    now.UTC(),
+	}
+}
+
+// candlesAfter returns the candles dated strictly after the given date
+func candlesAfter(candles []models.Candle, date time.Time) []models.Candle {
+	for i, candle := range candles {
+		if candle.Date.After(date) {
+			return candles[i:]
+		}
+	}
+	return nil
+}
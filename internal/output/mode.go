@@ -0,0 +1,62 @@
+// Package output controls how the SAPAN application presents results on the terminal
+// This package defines output modes so the tool behaves well both interactively and under cron
+package output
+
+import "fmt"
+
+// Mode selects how much the application prints during and after a scan
+type Mode int
+
+const (
+	Normal      Mode = iota // Progress, per-stock results, and final tables
+	Quiet                   // Only the run summary (warnings and errors still go to stderr)
+	Verbose                 // Normal output plus per-rule detail for every symbol
+	SignalsOnly             // Only detected signals, one per line, for piping into other tools
+)
+
+// modeNames maps configuration values onto modes
+var modeNames = map[string]Mode{
+	"normal":       Normal,
+	"quiet":        Quiet,
+	"verbose":      Verbose,
+	"signals-only": SignalsOnly,
+}
+
+// ParseMode converts a configuration value (normal, quiet, verbose, signals-only) into a Mode
+func ParseMode(name string) (Mode, error) {
+	mode, ok := modeNames[name]
+	if !ok {
+		return Normal, fmt.Errorf("unknown output mode %q (expected normal, quiet, verbose, or signals-only)", name)
+	}
+	return mode, nil
+}
+
+// String returns the configuration name of the mode
+func (m Mode) String() string {
+	for name, mode := range modeNames {
+		if mode == m {
+			return name
+		}
+	}
+	return "normal"
+}
+
+// ShowsProgress reports whether progress, per-stock results, and informational logs are printed
+func (m Mode) ShowsProgress() bool {
+	return m == Normal || m == Verbose
+}
+
+// ShowsSummary reports whether the run summary is printed
+func (m Mode) ShowsSummary() bool {
+	return m != SignalsOnly
+}
+
+// ShowsSignals reports whether detected signals are announced as they are found
+func (m Mode) ShowsSignals() bool {
+	return m != Quiet
+}
+
+// ShowsRuleDetail reports whether per-rule validation detail is printed for every symbol
+func (m Mode) ShowsRuleDetail() bool {
+	return m == Verbose
+}
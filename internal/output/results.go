@@ -0,0 +1,53 @@
+// Package output controls how the SAPAN application presents results on the terminal
+// This package defines output modes so the tool behaves well both interactively and under cron
+package output
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"strconv"
+	"time"
+)
+
+// PrintWatchList renders the watch list as a color-coded table (Long green, Short red)
+// Detection times are shown in the given display timezone
+func PrintWatchList(w io.Writer, palette *Palette, entries []watcher.WatchListEntry, location *time.Location) {
+	fmt.Fprintln(w, palette.Bold("Watch List:"))
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "  No valid SAPAN setups found")
+		return
+	}
+	if location == nil {
+		location = time.Local
+	}
+
+	table := NewTable(palette, "Side", "Symbol", "Pattern", "Score", "Entry", "Stop", "Target", "R:R", "Detected", "Sector")
+	for _, entry := range entries {
+		color := palette.Green
+		if entry.Side == watcher.ShortSide {
+			color = palette.Red
+		}
+		table.AddRow(color,
+			entry.Side,
+			entry.Symbol,
+			entry.Pattern,
+			strconv.FormatFloat(entry.Score, 'f', 1, 64),
+			formatLevel(entry.Entry),
+			formatLevel(entry.Stop),
+			formatLevel(entry.Target),
+			strconv.FormatFloat(entry.RiskReward(), 'f', 1, 64),
+			entry.DetectedAt.In(location).Format("2006-01-02 15:04 MST"),
+			entry.Sector,
+		)
+	}
+	table.Render(w)
+}
+
+// formatLevel renders a price level, leaving the cell empty when the level is unknown
+func formatLevel(price float64) string {
+	if price == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
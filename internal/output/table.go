@@ -0,0 +1,132 @@
+// Package output controls how the SAPAN application presents results on the terminal
+// This package defines output modes so the tool behaves well both interactively and under cron
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used for colored output
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// Palette applies terminal colors, or returns text unchanged when colors are disabled
+type Palette struct {
+	enabled bool // Whether ANSI colors are emitted
+}
+
+// NewPalette creates a palette that colors text only when enabled is true
+func NewPalette(enabled bool) *Palette {
+	return &Palette{enabled: enabled}
+}
+
+// ColorEnabled resolves a color setting (auto, always, never) for standard output
+// In auto mode colors are used only on a terminal and never when the NO_COLOR convention is set
+func ColorEnabled(setting string) bool {
+	switch setting {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Green colors text green (Long setups)
+func (p *Palette) Green(text string) string { return p.wrap(ansiGreen, text) }
+
+// Red colors text red (Short setups)
+func (p *Palette) Red(text string) string { return p.wrap(ansiRed, text) }
+
+// Yellow colors text yellow (errors and warnings)
+func (p *Palette) Yellow(text string) string { return p.wrap(ansiYellow, text) }
+
+// Bold renders text in bold (headers)
+func (p *Palette) Bold(text string) string { return p.wrap(ansiBold, text) }
+
+// Plain returns text unchanged; it is used for rows without a color
+func (p *Palette) Plain(text string) string { return text }
+
+// wrap surrounds text with an ANSI code when colors are enabled
+func (p *Palette) wrap(code, text string) string {
+	if p == nil || !p.enabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Table renders rows as aligned columns with optional per-row colors
+// Column widths are computed from the plain text, so colors never break the alignment
+type Table struct {
+	headers []string              // Column headers
+	rows    [][]string            // Cell text per row
+	colors  []func(string) string // Color applied to each row's cells
+	palette *Palette              // Palette used for headers
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(palette *Palette, headers ...string) *Table {
+	return &Table{headers: headers, palette: palette}
+}
+
+// AddRow appends a row; color is applied to every cell (use Palette.Plain for none)
+func (t *Table) AddRow(color func(string) string, cells ...string) {
+	t.rows = append(t.rows, cells)
+	t.colors = append(t.colors, color)
+}
+
+// Len returns the number of rows in the table
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the table with two spaces between columns and a rule under the header
+func (t *Table) Render(w io.Writer) {
+	widths := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
+			}
+		}
+	}
+
+	rule := make([]string, len(widths))
+	for i, width := range widths {
+		rule[i] = strings.Repeat("─", width)
+	}
+
+	fmt.Fprintln(w, "  "+t.palette.Bold(t.line(t.headers, widths)))
+	fmt.Fprintln(w, "  "+strings.Join(rule, "  "))
+	for i, row := range t.rows {
+		fmt.Fprintln(w, "  "+t.colors[i](t.line(row, widths)))
+	}
+}
+
+// line pads each cell to its column width and joins the cells
+func (t *Table) line(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = cell
+		if i < len(cells)-1 && i < len(widths) {
+			padded[i] += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+	}
+	return strings.Join(padded, "  ")
+}
@@ -3,34 +3,84 @@
 package processor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/calendar"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
 	"log"
-	"sapan/internal/data"
-	"sapan/internal/strategy"
-	"sapan/internal/watcher"
-	"sapan/models"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // StockProcessor handles concurrent stock processing with worker pools
 // This struct manages parallel processing of multiple stocks using goroutines and channels
 type StockProcessor struct {
-	stockFetcher     *data.StockDataFetcher    // Data fetcher for retrieving stock information
-	sapanStrategy    *strategy.SAPANStrategy   // SAPAN strategy for validation
-	watchListManager *watcher.WatchListManager // Watch list manager for storing results
-	workerCount      int                       // Number of concurrent workers
-	requestDelay     time.Duration             // Delay between API requests per worker
+	stockFetcher     data.Fetcher                    // Data fetcher for retrieving stock information
+	sapanStrategy    strategy.Validator              // SAPAN strategy for validation
+	watchListManager watcher.WatchList               // Watch list manager for storing results
+	workerCount      int                             // Number of concurrent workers
+	requestDelay     time.Duration                   // Delay between API requests per worker
+	fetchTimeout     time.Duration                   // Deadline of one stock's fetch, so a hung request cannot stall a worker (0 for none)
+	outputSize       int                             // Number of candles to fetch per stock
+	marketCalendar   *calendar.Calendar              // Market calendar used to drop unfinished candles (nil keeps all candles)
+	outputMode       output.Mode                     // Controls progress, per-stock, and summary output
+	progress         atomic.Pointer[ProgressTracker] // Tracker of the run in progress (nil before the first run)
+	scorer           SignalScorer                    // Optional scorer attaching features and probabilities to signals
+	candleStore      *data.CandleStore               // Optional archive the closed candles of every stock are saved to
+	enricher         StockEnricher                   // Optional lookup filling in listing metadata before signals are recorded
+	chartProvider    string                          // Provider chart links are built for (empty records signals without links)
+	chartTimeframe   string                          // Timeframe chart links open the chart at
+	explain          bool                            // Measure every rule of the explained symbols
+	explainSymbols   map[string]bool                 // Upper-case symbols explained (empty explains every symbol)
+	bothSides        bool                            // Evaluate Short even when Long is valid and report both sides
+	quota            QuotaMeter                      // Optional daily API quota shown in the progress display
+	quotaExhausted   atomic.Bool                     // Set once a fetch hit the quota; the rest of the run is skipped
+	candleChecks     *data.CandleChecks              // Optional anomaly checks run on the closed candles of every stock
+	skipImpossible   bool                            // Fail stocks whose candles hold impossible bars instead of validating them
+}
+
+// QuotaMeter reports the API requests used today against the daily quota; *data.QuotaTracker implements it
+type QuotaMeter interface {
+	Used() int  // Requests made today
+	Limit() int // Requests allowed per day (0 for no limit)
+}
+
+// Diagnoser measures every rule of a validation; *strategy.SAPANStrategy implements it
+// Explain mode only works with validators that also implement Diagnoser
+type Diagnoser interface {
+	Diagnose(symbol string, candles []models.Candle, scenario strategy.ScenarioType) strategy.Diagnosis
+}
+
+// StockEnricher fills in missing stock metadata such as the exchange, currency, and ISIN
+// Implementations must be safe for concurrent use by multiple workers
+type StockEnricher interface {
+	Enrich(stock models.Stock) models.Stock
+}
+
+// SignalScorer enriches signals with model features and a predicted success probability
+// Implementations must be safe for concurrent use by multiple workers
+type SignalScorer interface {
+	Observe(stock models.Stock, candles []models.Candle) // Called with the closed candles of every analyzed stock
+	ScoreSignal(stock models.Stock, candles []models.Candle, validation strategy.ValidationResult, side string) (watcher.SignalFeatures, float64)
 }
 
 // NewStockProcessor creates a new stock processor instance
 // This constructor initializes the processor with all required dependencies and configuration
 func NewStockProcessor(
-	stockFetcher *data.StockDataFetcher,
-	sapanStrategy *strategy.SAPANStrategy,
-	watchListManager *watcher.WatchListManager,
+	stockFetcher data.Fetcher,
+	sapanStrategy strategy.Validator,
+	watchListManager watcher.WatchList,
 	workerCount int,
 	requestDelay time.Duration,
+	outputSize int,
 ) *StockProcessor {
 	return &StockProcessor{
 		stockFetcher:     stockFetcher,     // Initialize data fetcher
@@ -38,34 +88,156 @@ func NewStockProcessor(
 		watchListManager: watchListManager, // Initialize watch list manager
 		workerCount:      workerCount,      // Set worker count
 		requestDelay:     requestDelay,     // Set request delay
+		outputSize:       outputSize,       // Set candle count per request
 	}
 }
 
+// SetMarketCalendar makes the processor ignore candles whose session has not closed yet
+// Use it for daily, weekly, and monthly candles; intraday bars are not aligned to sessions
+func (p *StockProcessor) SetMarketCalendar(marketCalendar *calendar.Calendar) {
+	p.marketCalendar = marketCalendar
+}
+
+// SetSignalScorer makes the processor score every recorded signal
+func (p *StockProcessor) SetSignalScorer(scorer SignalScorer) {
+	p.scorer = scorer
+}
+
+// SetCandleStore makes the processor archive the closed candles of every fetched stock for later replays
+func (p *StockProcessor) SetCandleStore(candleStore *data.CandleStore) {
+	p.candleStore = candleStore
+}
+
+// SetCandleChecks checks the closed candles of every stock for anomalies and attaches them to its result
+// With skipImpossible set, stocks with impossible bars fail instead of being validated on bad data
+func (p *StockProcessor) SetCandleChecks(checks data.CandleChecks, skipImpossible bool) {
+	p.candleChecks = &checks
+	p.skipImpossible = skipImpossible
+}
+
+// SetStockEnricher makes the processor look up the listing metadata of every stock that produces a signal
+func (p *StockProcessor) SetStockEnricher(enricher StockEnricher) {
+	p.enricher = enricher
+}
+
+// SetChartLinks attaches TradingView and Yahoo chart links to every recorded signal
+// provider and timeframe select the exchange prefix and candle interval of the links
+func (p *StockProcessor) SetChartLinks(provider, timeframe string) {
+	p.chartProvider, p.chartTimeframe = provider, timeframe
+}
+
+// SetExplain makes the processor measure every rule of the given symbols, or of every symbol when none are given
+// The measured rules are attached to the processing results and printed unless the output mode hides per-stock results
+func (p *StockProcessor) SetExplain(symbols []string) {
+	p.explain = true
+	p.explainSymbols = make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		p.explainSymbols[strings.ToUpper(symbol)] = true
+	}
+}
+
+// minimumCandles returns the candles the validator needs before a setup can be validated
+func (p *StockProcessor) minimumCandles() int {
+	return strategy.ValidatorMinimumCandles(p.sapanStrategy)
+}
+
+// explains reports whether the rules of a symbol are measured
+func (p *StockProcessor) explains(symbol string) bool {
+	return p.explain && (len(p.explainSymbols) == 0 || p.explainSymbols[strings.ToUpper(symbol)])
+}
+
+// SetBothSides makes the processor validate and report both scenarios of every stock instead of skipping Short
+// when Long is valid; Long keeps priority for the signal, so every stock still records at most one setup
+func (p *StockProcessor) SetBothSides(enabled bool) {
+	p.bothSides = enabled
+}
+
+// SetFetchTimeout gives every stock's fetch a deadline; a fetch still running after timeout fails the stock
+func (p *StockProcessor) SetFetchTimeout(timeout time.Duration) {
+	p.fetchTimeout = timeout
+}
+
+// SetQuota shows the API quota in the progress display
+// Whether or not a quota is set, the run stops fetching once the fetcher reports data.ErrQuotaExhausted
+func (p *StockProcessor) SetQuota(quota QuotaMeter) {
+	p.quota = quota
+}
+
+// SetOutputMode selects how much the processor prints while working
+func (p *StockProcessor) SetOutputMode(mode output.Mode) {
+	p.outputMode = mode
+}
+
 // ProcessingResult contains the result of processing a single stock
 // This structure holds all information about the processing outcome for a single stock
 type ProcessingResult struct {
-	Symbol       string // Stock symbol that was processed
-	Success      bool   // Whether the processing was successful (no errors)
-	Error        error  // Error that occurred during processing (if any)
-	IsValid      bool   // Whether any valid SAPAN setup was found
-	IsLongValid  bool   // Whether a valid Long setup was found
-	IsShortValid bool   // Whether a valid Short setup was found
-	Message      string // Detailed message about the processing result
-	Processed    bool   // Whether the stock was actually processed
+	Symbol       string                    // Stock symbol that was processed
+	Sector       string                    // Business sector from the stock list
+	Industry     string                    // Industry from the stock list
+	Success      bool                      // Whether the processing was successful (no errors)
+	Error        error                     // Error that occurred during processing (if any)
+	IsValid      bool                      // Whether any valid SAPAN setup was found
+	IsLongValid  bool                      // Whether a valid Long setup was found
+	IsShortValid bool                      // Whether a valid Short setup was found
+	ShortChecked bool                      // Whether the Short side was validated (always in both-sides mode)
+	Message      string                    // Detailed message about the processing result
+	Processed    bool                      // Whether the stock was actually processed (false when skipped after the quota ran out)
+	LongResult   strategy.ValidationResult // Long validation detail
+	ShortResult  strategy.ValidationResult // Short validation detail (only evaluated when Long is not valid, unless in both-sides mode)
+	LongRules    []strategy.RuleCheck      // Measured Long rules (explain mode only)
+	ShortRules   []strategy.RuleCheck      // Measured Short rules (explain mode only, when Short was evaluated)
+	Candles      int                       // Number of closed candles analyzed
+	Anomalies    []data.Anomaly            // Suspicious candles found by the candle checks, oldest first
+	Closes       []float64                 // Closing prices of the analyzed candles (valid setups only)
+	History      []models.Candle           // Analyzed candles, kept for provider cross-checks (valid setups only)
+	Duration     time.Duration             // Time spent fetching and analyzing the stock
+}
+
+// ProcessingSummary contains the aggregated counts of a processing run
+// Long and Short counts are mutually exclusive, so LongCount + ShortCount equals Valid
+type ProcessingSummary struct {
+	Total          int                 // Number of stocks processed
+	Skipped        int                 // Stocks not fetched because the API quota ran out
+	Anomalous      int                 // Stocks whose candles had anomalies
+	SkippedSymbols []string            // Symbols of the skipped stocks, sorted
+	Successful     int                 // Stocks analyzed without errors
+	Errors         int                 // Stocks that failed to process
+	Valid          int                 // Valid SAPAN setups found
+	LongCount      int                 // Long setups found
+	ShortCount     int                 // Short setups found
+	Failures       []ProcessingFailure // Stocks that failed, sorted by symbol
+	Results        []ProcessingResult  // Per-stock results, sorted by symbol
+}
+
+// ProcessingFailure describes a stock that could not be processed
+type ProcessingFailure struct {
+	Symbol string // Stock symbol that failed
+	Error  string // Error message explaining the failure
+}
+
+// Progress returns the tracker of the current or most recent run, or nil before processing starts (thread-safe)
+func (p *StockProcessor) Progress() *ProgressTracker {
+	return p.progress.Load()
 }
 
 // ProcessStocksConcurrently processes multiple stocks concurrently using worker pools
 // This method creates channels, starts workers, and coordinates the processing of all stocks
-func (p *StockProcessor) ProcessStocksConcurrently(stocks []models.Stock) {
+// Returns the aggregated counts of the run
+func (p *StockProcessor) ProcessStocksConcurrently(stocks []models.Stock) ProcessingSummary {
 	// Create channels for communication
 	stockChan := make(chan models.Stock, len(stocks))
 	resultChan := make(chan ProcessingResult, len(stocks))
 
 	// Create progress tracker
 	progressTracker := NewProgressTracker(len(stocks))
+	progressTracker.quota = p.quota
+	p.progress.Store(progressTracker)
+	p.quotaExhausted.Store(false)
 
 	// Start progress monitor
-	go p.monitorProgress(progressTracker)
+	if p.outputMode.ShowsProgress() {
+		go p.monitorProgress(progressTracker)
+	}
 
 	// Start workers
 	var wg sync.WaitGroup
@@ -89,7 +261,7 @@ func (p *StockProcessor) ProcessStocksConcurrently(stocks []models.Stock) {
 	}()
 
 	// Collect results
-	p.collectResults(resultChan, progressTracker)
+	return p.collectResults(resultChan, progressTracker)
 }
 
 // worker processes stocks from the input channel
@@ -97,10 +269,21 @@ func (p *StockProcessor) worker(workerID int, stockChan <-chan models.Stock, res
 	defer wg.Done()
 
 	for stock := range stockChan {
+		// Skip the rest of the run without fetching once the quota is used up
+		if p.quotaExhausted.Load() {
+			resultChan <- ProcessingResult{Symbol: stock.Symbol}
+			progressTracker.UpdateSkipped()
+			continue
+		}
+
 		result := p.processStock(stock)
 		resultChan <- result
 
 		// Update progress
+		if !result.Processed {
+			progressTracker.UpdateSkipped()
+			continue
+		}
 		progressTracker.UpdateProgress(result.Success, result.IsValid)
 
 		// Add delay between requests to respect API limits
@@ -111,45 +294,116 @@ func (p *StockProcessor) worker(workerID int, stockChan <-chan models.Stock, res
 }
 
 // processStock processes a single stock
-func (p *StockProcessor) processStock(stock models.Stock) ProcessingResult {
-	result := ProcessingResult{
+func (p *StockProcessor) processStock(stock models.Stock) (result ProcessingResult) {
+	startTime := time.Now()
+	defer func() { result.Duration = time.Since(startTime) }()
+
+	result = ProcessingResult{
 		Symbol:    stock.Symbol,
+		Sector:    stock.Sector,
+		Industry:  stock.Industry,
 		Processed: true,
 	}
 
-	// Fetch stock data
-	candleData, err := p.stockFetcher.FetchStockData(stock.Symbol, 200)
+	// Fetch stock data within the fetch deadline
+	ctx := context.Background()
+	if p.fetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.fetchTimeout)
+		defer cancel()
+	}
+	candleData, err := p.stockFetcher.FetchStockData(ctx, stock.Symbol, p.outputSize)
+	if errors.Is(err, data.ErrQuotaExhausted) {
+		// Only the first worker to hit the quota reports it; the stock counts as skipped, not failed
+		if p.quotaExhausted.CompareAndSwap(false, true) && p.outputMode.ShowsProgress() {
+			log.Printf("⚠️  %v; skipping the remaining stocks", err)
+		}
+		result.Processed = false
+		return result
+	}
 	if err != nil {
 		result.Error = err
 		result.Success = false
-		log.Printf("Worker: Failed to fetch data for %s: %v", stock.Symbol, err)
+		if p.outputMode.ShowsProgress() {
+			log.Printf("Worker: Failed to fetch data for %s: %v", stock.Symbol, err)
+		}
 		return result
 	}
 
+	// Drop the still-forming candle so signals never repaint during the session
+	if p.marketCalendar != nil {
+		candleData.Candles = p.marketCalendar.ClosedCandles(candleData.Candles, time.Now())
+	}
+	if p.candleStore != nil {
+		if err := p.candleStore.Save(stock.Symbol, candleData); err != nil {
+			log.Printf("Worker: Failed to archive candles for %s: %v", stock.Symbol, err)
+		}
+	}
+	// Say why a stock cannot be validated instead of leaving only an "Insufficient data" result
+	if minimum := p.minimumCandles(); len(candleData.Candles) < minimum {
+		log.Printf("⚠️  Worker: Provider returned %d candles for %s; the indicators need at least %d (listed recently or history too short)",
+			len(candleData.Candles), stock.Symbol, minimum)
+	}
+	// Surface impossible bars, gaps, and missing sessions instead of silently validating on them
+	if p.candleChecks != nil {
+		result.Anomalies = data.CheckCandleData(candleData, *p.candleChecks)
+		if len(result.Anomalies) > 0 {
+			log.Printf("⚠️  Worker: %d candle anomalies in %s: %s", len(result.Anomalies), stock.Symbol, describeAnomalies(result.Anomalies))
+		}
+		for _, anomaly := range result.Anomalies {
+			if p.skipImpossible && anomaly.Impossible() {
+				result.Error = fmt.Errorf("impossible candle on %s", anomaly)
+				result.Success = false
+				return result
+			}
+		}
+	}
+	if p.scorer != nil {
+		p.scorer.Observe(stock, candleData.Candles)
+	}
+
 	// Validate SAPAN Long strategy first (priority)
 	longResult := p.sapanStrategy.ValidateLongSetup(stock.Symbol, candleData.Candles)
 
-	// Validate SAPAN Short strategy only if Long is not valid
+	// Validate SAPAN Short strategy only if Long is not valid, or always when both sides are reported
 	var shortResult strategy.ValidationResult
-	if !longResult.IsValid {
+	result.ShortChecked = !longResult.IsValid || p.bothSides
+	if result.ShortChecked {
 		shortResult = p.sapanStrategy.ValidateShortSetup(stock.Symbol, candleData.Candles)
 	}
 
 	// Set results based on priority (Long has priority over Short)
+	result.LongResult = longResult
+	result.ShortResult = shortResult
+	result.Candles = len(candleData.Candles)
 	result.IsLongValid = longResult.IsValid
 	result.IsShortValid = !longResult.IsValid && shortResult.IsValid
 	result.Success = true
 	result.IsValid = longResult.IsValid || shortResult.IsValid
+	if diagnoser, ok := p.sapanStrategy.(Diagnoser); ok && p.explains(stock.Symbol) {
+		result.LongRules = diagnoser.Diagnose(stock.Symbol, candleData.Candles, strategy.LongScenario).Rules
+		if result.ShortChecked {
+			result.ShortRules = diagnoser.Diagnose(stock.Symbol, candleData.Candles, strategy.ShortScenario).Rules
+		}
+	}
+
+	if result.IsValid {
+		result.Closes = make([]float64, len(candleData.Candles))
+		for i, candle := range candleData.Candles {
+			result.Closes[i] = candle.Close
+		}
+		result.History = candleData.Candles
+	}
 
 	// Create message based on selected scenario
 	if longResult.IsValid {
 		result.Message = longResult.ValidationMessage
 		// Add to Long watch list only
-		p.watchListManager.AddToLongWatchList(stock.Symbol)
+		p.recordSignal(stock, candleData.Candles, longResult, watcher.LongSide)
 	} else if shortResult.IsValid {
 		result.Message = shortResult.ValidationMessage
 		// Add to Short watch list only
-		p.watchListManager.AddToShortWatchList(stock.Symbol)
+		p.recordSignal(stock, candleData.Candles, shortResult, watcher.ShortSide)
 	} else {
 		result.Message = "No valid SAPAN setups detected"
 	}
@@ -157,17 +411,92 @@ func (p *StockProcessor) processStock(stock models.Stock) ProcessingResult {
 	return result
 }
 
+// recordSignal builds a watcher signal from the validation result and records it
+// Failures to persist the signal are logged but never abort processing of the stock
+func (p *StockProcessor) recordSignal(stock models.Stock, candles []models.Candle, validation strategy.ValidationResult, side string) {
+	if p.enricher != nil {
+		stock = p.enricher.Enrich(stock)
+	}
+	signal := watcher.Signal{
+		Symbol:            stock.Symbol,
+		Name:              stock.Name,
+		Sector:            stock.Sector,
+		Industry:          stock.Industry,
+		MarketCap:         stock.MarketCap,
+		Exchange:          stock.Exchange,
+		Currency:          stock.Currency,
+		Country:           stock.Country,
+		ISIN:              stock.ISIN,
+		Side:              side,
+		Pattern:           validation.PatternType.String(),
+		Score:             validation.Score,
+		Entry:             validation.TradePlan.Entry,
+		Stop:              validation.TradePlan.Stop,
+		Target:            validation.TradePlan.Target,
+		TimeStop:          validation.TradePlan.TimeStop,
+		EMATrendValid:     validation.EMATrendValid,
+		StochasticValid:   validation.StochasticValid,
+		MACDValid:         validation.MACDValid,
+		PatternValid:      validation.PatternValid,
+		StochRSIThreshold: validation.StochRSIThreshold,
+		StochRSILookback:  validation.StochRSILookback,
+		EMAStack:          strategy.FormatEMAStack(validation.EMAPeriods),
+		ValidationMessage: validation.ValidationMessage,
+	}
+	if p.scorer != nil {
+		signal.Features, signal.Probability = p.scorer.ScoreSignal(stock, candles, validation, side)
+	}
+	if zone := validation.Zone; zone != nil {
+		signal.Zone = &watcher.SignalZone{
+			Kind:       zone.Kind.String(),
+			Low:        zone.Low,
+			High:       zone.High,
+			Date:       zone.Date,
+			Impulse:    zone.Impulse,
+			Confluence: validation.ZoneConfluence,
+		}
+	}
+	if p.chartProvider != "" {
+		links := watcher.NewChartLinks(stock.Symbol, stock.Exchange, p.chartProvider, p.chartTimeframe)
+		signal.Charts = &links
+	}
+	if len(candles) > 0 {
+		lastCandle := candles[len(candles)-1]
+		signal.CandleDate = lastCandle.Time()
+		signal.Close = lastCandle.Close
+		signal.Volume = lastCandle.Volume
+	}
+
+	if err := p.watchListManager.RecordSignal(signal); err != nil {
+		log.Printf("Worker: Failed to record signal for %s: %v", stock.Symbol, err)
+	}
+}
+
 // collectResults collects and processes results from workers
-func (p *StockProcessor) collectResults(resultChan <-chan ProcessingResult, progressTracker *ProgressTracker) {
+func (p *StockProcessor) collectResults(resultChan <-chan ProcessingResult, progressTracker *ProgressTracker) ProcessingSummary {
 	successCount := 0
 	errorCount := 0
+	var skipped []string
 	validCount := 0
 	longCount := 0
 	shortCount := 0
+	anomalous := 0
+	var failures []ProcessingFailure
+	var results []ProcessingResult
 
-	log.Println("Processing results...")
+	if p.outputMode.ShowsProgress() {
+		log.Println("Processing results...")
+	}
 
 	for result := range resultChan {
+		if !result.Processed {
+			skipped = append(skipped, result.Symbol)
+			continue
+		}
+		results = append(results, result)
+		if len(result.Anomalies) > 0 {
+			anomalous++
+		}
 		if result.Success {
 			successCount++
 			if result.IsValid {
@@ -181,32 +510,126 @@ func (p *StockProcessor) collectResults(resultChan <-chan ProcessingResult, prog
 			}
 		} else {
 			errorCount++
+			failures = append(failures, ProcessingFailure{Symbol: result.Symbol, Error: fmt.Sprint(result.Error)})
 		}
 
 		// Log detailed results
+		if !p.outputMode.ShowsProgress() {
+			continue
+		}
 		if result.Success {
 			if result.IsValid {
 				log.Printf("✅ %s: %s", result.Symbol, result.Message)
 			} else {
 				log.Printf("❌ %s: %s", result.Symbol, result.Message)
 			}
+			if p.outputMode.ShowsRuleDetail() || p.bothSides {
+				logRuleDetail(result)
+			}
+			logExplanation(result)
 		} else {
 			log.Printf("⚠️  %s: Error - %v", result.Symbol, result.Error)
 		}
 	}
 
 	// Print final progress
-	fmt.Println() // New line after progress indicator
-
-	// Print summary (Long and Short are mutually exclusive)
-	log.Printf("\n📊 Processing Summary:")
-	log.Printf("   Total processed: %d", successCount+errorCount)
-	log.Printf("   Successful: %d", successCount)
-	log.Printf("   Errors: %d", errorCount)
-	log.Printf("   Valid SAPAN setups: %d", validCount)
-	log.Printf("   Long setups: %d", longCount)
-	log.Printf("   Short setups: %d", shortCount)
-	log.Printf("   Note: Each stock can only be either Long OR Short (mutually exclusive)")
+	if p.outputMode.ShowsProgress() {
+		fmt.Println() // New line after progress indicator
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Symbol < failures[j].Symbol })
+	sort.Strings(skipped)
+	sort.Slice(results, func(i, j int) bool { return results[i].Symbol < results[j].Symbol })
+
+	return ProcessingSummary{
+		Total:          successCount + errorCount,
+		Skipped:        len(skipped),
+		SkippedSymbols: skipped,
+		Anomalous:      anomalous,
+		Successful:     successCount,
+		Errors:         errorCount,
+		Valid:          validCount,
+		LongCount:      longCount,
+		ShortCount:     shortCount,
+		Failures:       failures,
+		Results:        results,
+	}
+}
+
+// describeAnomalies lists the first few anomalies of a stock on one line
+func describeAnomalies(anomalies []data.Anomaly) string {
+	const shown = 3
+	parts := make([]string, 0, shown+1)
+	for i, anomaly := range anomalies {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(anomalies)-shown))
+			break
+		}
+		parts = append(parts, anomaly.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// logRuleDetail prints the per-rule outcome and score of both sides for a processed stock
+// The Short side is only shown when it was evaluated (Long has priority unless both sides are reported)
+func logRuleDetail(result ProcessingResult) {
+	log.Printf("     Long:  %s%s", FormatRules(result.LongResult), formatScore(result.LongResult))
+	if result.ShortChecked {
+		log.Printf("     Short: %s%s", FormatRules(result.ShortResult), formatScore(result.ShortResult))
+	}
+}
+
+// formatScore renders the score of a valid setup as " | score 72.5", and nothing for a rejected one
+func formatScore(validation strategy.ValidationResult) string {
+	if !validation.IsValid {
+		return ""
+	}
+	return fmt.Sprintf(" | score %.1f", validation.Score)
+}
+
+// logExplanation prints the measured rules of a stock processed in explain mode, with the gap of every failed rule
+func logExplanation(result ProcessingResult) {
+	for _, side := range []struct {
+		name  string
+		rules []strategy.RuleCheck
+	}{{"Long", result.LongRules}, {"Short", result.ShortRules}} {
+		for _, rule := range side.rules {
+			if rule.Passed {
+				log.Printf("     %-5s ✔ %-7s %s", side.name, rule.Name, rule.Measured)
+			} else {
+				log.Printf("     %-5s ✘ %-7s %s — %s (needs %s)", side.name, rule.Name, rule.Measured, rule.Gap, rule.Needs)
+			}
+		}
+	}
+}
+
+// FormatRules renders rule outcomes such as "EMA ✔ | Stoch ✘ | MACD - | Pattern - | <message>"
+// Rules after the first failure are not evaluated and shown as "-"
+func FormatRules(validation strategy.ValidationResult) string {
+	rules := []struct {
+		name   string
+		passed bool
+	}{
+		{"EMA", validation.EMATrendValid},
+		{"Stoch", validation.StochasticValid},
+		{"MACD", validation.MACDValid},
+		{"Pattern", validation.PatternValid},
+	}
+
+	var parts []string
+	failed := false
+	for _, rule := range rules {
+		mark := "✔"
+		switch {
+		case failed:
+			mark = "-"
+		case !rule.passed:
+			mark = "✘"
+			failed = true
+		}
+		parts = append(parts, rule.name+" "+mark)
+	}
+	return strings.Join(append(parts, validation.ValidationMessage), " | ")
 }
 
 // monitorProgress monitors and displays progress
@@ -0,0 +1,249 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func TestProcessStocksConcurrently(t *testing.T) {
+	candles := sapantest.Uptrend(250)
+	fetcher := sapantest.NewFetcher().
+		SetCandles("LONG", candles).
+		SetCandles("SHORT", candles).
+		SetCandles("NONE", candles).
+		SetError("FAIL", errors.New("rate limited"))
+	validator := sapantest.NewStrategy().
+		SetLong("LONG", sapantest.ValidSetup(strategy.Long2CandlestickReversal, 110, 105, 120)).
+		SetLong("SHORT", strategy.ValidationResult{}).
+		SetShort("SHORT", sapantest.ValidSetup(strategy.ShortPinbarReversal, 90, 95, 80))
+	watchList := sapantest.NewWatchList()
+
+	p := processor.NewStockProcessor(fetcher, validator, watchList, 2, 0, 200)
+	p.SetOutputMode(output.Quiet)
+	stocks := []models.Stock{
+		sapantest.Stock("LONG"),
+		sapantest.Stock("SHORT"),
+		sapantest.Stock("NONE"),
+		sapantest.Stock("FAIL"),
+	}
+	summary := p.ProcessStocksConcurrently(stocks)
+
+	if summary.Total != 4 || summary.Successful != 3 || summary.Errors != 1 {
+		t.Fatalf("summary = %d total, %d successful, %d errors; want 4, 3, 1", summary.Total, summary.Successful, summary.Errors)
+	}
+	if summary.LongCount != 1 || summary.ShortCount != 1 {
+		t.Errorf("setups = %d long, %d short; want 1, 1", summary.LongCount, summary.ShortCount)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].Symbol != "FAIL" {
+		t.Errorf("failures = %+v; want FAIL", summary.Failures)
+	}
+	if got := fetcher.Calls("LONG"); got != 1 {
+		t.Errorf("LONG fetched %d times; want 1", got)
+	}
+
+	signals := watchList.Signals()
+	if len(signals) != 2 {
+		t.Fatalf("recorded %d signals; want 2", len(signals))
+	}
+	long, ok := watchList.GetEntry("LONG", watcher.LongSide)
+	if !ok {
+		t.Fatal("LONG missing from the Long watch list")
+	}
+	last := candles[len(candles)-1]
+	if long.Entry != 110 || long.Stop != 105 || long.Target != 120 {
+		t.Errorf("LONG plan = %.2f/%.2f/%.2f; want 110/105/120", long.Entry, long.Stop, long.Target)
+	}
+	if !long.CandleDate.Equal(last.Date) || long.Close != last.Close {
+		t.Errorf("LONG candle = %s %.2f; want %s %.2f", long.CandleDate, long.Close, last.Date, last.Close)
+	}
+	if _, ok := watchList.GetEntry("SHORT", watcher.ShortSide); !ok {
+		t.Error("SHORT missing from the Short watch list")
+	}
+}
+
+func TestProcessStocksConcurrentlyRecordError(t *testing.T) {
+	fetcher := sapantest.NewFetcher().SetCandles("LONG", sapantest.Uptrend(250))
+	validator := sapantest.NewStrategy().
+		SetLong("LONG", sapantest.ValidSetup(strategy.LongPinbarReversal, 110, 105, 120))
+	watchList := sapantest.NewWatchList()
+	watchList.SetError(errors.New("disk full"))
+
+	p := processor.NewStockProcessor(fetcher, validator, watchList, 1, 0, 200)
+	p.SetOutputMode(output.Quiet)
+	summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("LONG")})
+
+	if summary.Errors != 0 || summary.LongCount != 1 {
+		t.Errorf("summary = %d errors, %d long; a failed recording must not fail the stock", summary.Errors, summary.LongCount)
+	}
+	if watchList.GetCount() != 0 {
+		t.Errorf("watch list holds %d entries; want 0", watchList.GetCount())
+	}
+}
+
+func TestProcessStocksConcurrentlyExplain(t *testing.T) {
+	fetcher := sapantest.NewFetcher().
+		SetCandles("AAPL", sapantest.Uptrend(250)).
+		SetCandles("MSFT", sapantest.Uptrend(250))
+
+	p := processor.NewStockProcessor(fetcher, strategy.NewSAPANStrategy(), sapantest.NewWatchList(), 2, 0, 200)
+	p.SetOutputMode(output.Quiet)
+	p.SetExplain([]string{"aapl"})
+	summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("AAPL"), sapantest.Stock("MSFT")})
+
+	for _, result := range summary.Results {
+		switch result.Symbol {
+		case "AAPL":
+			if len(result.LongRules) != 4 {
+				t.Fatalf("AAPL Long rules = %+v; want all four measured", result.LongRules)
+			}
+			for _, rule := range append(result.LongRules, result.ShortRules...) {
+				if !rule.Passed && rule.Gap == "" {
+					t.Errorf("AAPL %s failed without a gap: %+v", rule.Name, rule)
+				}
+			}
+		case "MSFT":
+			if result.LongRules != nil || result.ShortRules != nil {
+				t.Errorf("MSFT was explained outside the filter: %+v", result.LongRules)
+			}
+		}
+	}
+}
+
+func TestProcessStocksConcurrentlyBothSides(t *testing.T) {
+	fetcher := sapantest.NewFetcher().SetCandles("BOTH", sapantest.Uptrend(250))
+	validator := sapantest.NewStrategy().
+		SetLong("BOTH", sapantest.ValidSetup(strategy.LongPinbarReversal, 110, 105, 120)).
+		SetShort("BOTH", sapantest.ValidSetup(strategy.ShortPinbarReversal, 90, 95, 80))
+
+	for _, bothSides := range []bool{false, true} {
+		watchList := sapantest.NewWatchList()
+		p := processor.NewStockProcessor(fetcher, validator, watchList, 1, 0, 200)
+		p.SetOutputMode(output.Quiet)
+		p.SetBothSides(bothSides)
+		summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("BOTH")})
+
+		result := summary.Results[0]
+		if result.ShortChecked != bothSides || result.ShortResult.IsValid != bothSides {
+			t.Errorf("bothSides=%v: Short checked %v, valid %v", bothSides, result.ShortChecked, result.ShortResult.IsValid)
+		}
+		if !result.IsLongValid || result.IsShortValid || summary.LongCount != 1 || summary.ShortCount != 0 {
+			t.Errorf("bothSides=%v: Long must keep priority, got %d long, %d short", bothSides, summary.LongCount, summary.ShortCount)
+		}
+		if len(watchList.Signals()) != 1 {
+			t.Errorf("bothSides=%v: recorded %d signals; want 1", bothSides, len(watchList.Signals()))
+		}
+	}
+}
+
+func TestProcessStocksConcurrentlyStopsAtQuota(t *testing.T) {
+	fetcher := sapantest.NewFetcher().
+		SetCandles("AAPL", sapantest.Uptrend(250)).
+		SetError("MSFT", fmt.Errorf("%w: 25 of 25 requests used today", data.ErrQuotaExhausted)).
+		SetCandles("NVDA", sapantest.Uptrend(250))
+
+	p := processor.NewStockProcessor(fetcher, sapantest.NewStrategy(), sapantest.NewWatchList(), 1, 0, 200)
+	p.SetOutputMode(output.Quiet)
+	summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("AAPL"), sapantest.Stock("MSFT"), sapantest.Stock("NVDA")})
+
+	if summary.Total != 1 || summary.Errors != 0 || summary.Skipped != 2 {
+		t.Fatalf("summary = %d total, %d errors, %d skipped; want 1, 0, 2", summary.Total, summary.Errors, summary.Skipped)
+	}
+	if len(summary.SkippedSymbols) != 2 || summary.SkippedSymbols[0] != "MSFT" || summary.SkippedSymbols[1] != "NVDA" {
+		t.Errorf("skipped = %v; want [MSFT NVDA]", summary.SkippedSymbols)
+	}
+	if got := fetcher.Calls("NVDA"); got != 0 {
+		t.Errorf("NVDA fetched %d times after the quota ran out; want 0", got)
+	}
+	if progress := p.Progress(); !progress.IsComplete() || progress.Skipped() != 2 {
+		t.Errorf("progress skipped %d, complete %v; want 2, true", progress.Skipped(), progress.IsComplete())
+	}
+}
+
+// hangingFetcher blocks every fetch until its context is done, like a provider that never answers
+type hangingFetcher struct{}
+
+func (hangingFetcher) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	<-ctx.Done()
+	return models.CandleData{}, ctx.Err()
+}
+
+func TestProcessStocksConcurrentlyFetchTimeout(t *testing.T) {
+	p := processor.NewStockProcessor(hangingFetcher{}, sapantest.NewStrategy(), sapantest.NewWatchList(), 1, 0, 200)
+	p.SetOutputMode(output.Quiet)
+	p.SetFetchTimeout(20 * time.Millisecond)
+	summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("AAPL"), sapantest.Stock("MSFT")})
+
+	if summary.Total != 2 || summary.Errors != 2 {
+		t.Fatalf("summary = %d total, %d errors; want both hung fetches to fail", summary.Total, summary.Errors)
+	}
+	if !errors.Is(summary.Results[0].Error, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", summary.Results[0].Error)
+	}
+}
+
+// droppingFetcher returns an uptrend whose provider dropped one unusable bar, like Alpha Vantage does
+type droppingFetcher struct{}
+
+func (droppingFetcher) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	candles := sapantest.Uptrend(250)
+	dropped := candles[240]
+	dropped.High, dropped.Low = dropped.Low, dropped.High // Inverted range
+	return models.CandleData{Candles: append(candles[:240:240], candles[241:]...), Dropped: []models.Candle{dropped}}, nil
+}
+
+func TestProcessStocksConcurrentlyReportsDroppedBars(t *testing.T) {
+	for _, skip := range []bool{false, true} {
+		p := processor.NewStockProcessor(droppingFetcher{}, sapantest.NewStrategy(), sapantest.NewWatchList(), 1, 0, 200)
+		p.SetOutputMode(output.Quiet)
+		p.SetCandleChecks(data.CandleChecks{Window: 50}, skip)
+		summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("AAPL")})
+
+		result := summary.Results[0]
+		if summary.Anomalous != 1 || len(result.Anomalies) != 1 || result.Anomalies[0].Kind != data.AnomalyDroppedBar {
+			t.Errorf("skip=%v: anomalies = %v, want the dropped bar", skip, result.Anomalies)
+		}
+		if result.Success == skip {
+			t.Errorf("skip=%v: success = %v, want the dropped bar to fail the stock only when skipping", skip, result.Success)
+		}
+	}
+}
+
+func TestProcessStocksConcurrentlyCandleChecks(t *testing.T) {
+	bad := sapantest.Uptrend(250)
+	bad[240].High, bad[240].Low = bad[240].Low, bad[240].High // Inverted range
+	flat := sapantest.Uptrend(250)
+	flat[245].High = flat[245].Low
+	fetcher := sapantest.NewFetcher().SetCandles("BAD", bad).SetCandles("FLAT", flat).SetCandles("GOOD", sapantest.Uptrend(250))
+
+	for _, skip := range []bool{false, true} {
+		p := processor.NewStockProcessor(fetcher, sapantest.NewStrategy(), sapantest.NewWatchList(), 1, 0, 200)
+		p.SetOutputMode(output.Quiet)
+		p.SetCandleChecks(data.CandleChecks{Window: 50}, skip)
+		summary := p.ProcessStocksConcurrently([]models.Stock{sapantest.Stock("BAD"), sapantest.Stock("FLAT"), sapantest.Stock("GOOD")})
+
+		if summary.Anomalous != 2 {
+			t.Errorf("skip=%v: %d stocks with anomalies, want BAD and FLAT", skip, summary.Anomalous)
+		}
+		badResult, flatResult := summary.Results[0], summary.Results[1]
+		if len(badResult.Anomalies) != 1 || badResult.Anomalies[0].Kind != data.AnomalyInvertedRange {
+			t.Errorf("skip=%v: BAD anomalies = %v, want the inverted range", skip, badResult.Anomalies)
+		}
+		if badResult.Success == skip {
+			t.Errorf("skip=%v: BAD success = %v, want impossible bars to fail only when skipping", skip, badResult.Success)
+		}
+		if !flatResult.Success || len(flatResult.Anomalies) != 1 || flatResult.Anomalies[0].Kind != data.AnomalyZeroRange {
+			t.Errorf("skip=%v: FLAT = %+v, want a zero-range warning without failing", skip, flatResult)
+		}
+	}
+}
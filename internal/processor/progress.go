@@ -11,11 +11,13 @@ import (
 // ProgressTracker tracks progress of concurrent processing
 // This struct provides thread-safe progress tracking using atomic operations
 type ProgressTracker struct {
-	total     int32     // Total number of items to process
-	processed int32     // Number of items processed so far
-	valid     int32     // Number of valid SAPAN setups found
-	errors    int32     // Number of errors encountered
-	startTime time.Time // Start time for calculating elapsed time
+	total     int32      // Total number of items to process
+	processed int32      // Number of items processed so far
+	valid     int32      // Number of valid SAPAN setups found
+	errors    int32      // Number of errors encountered
+	skipped   int32      // Number of items skipped because the API quota ran out
+	startTime time.Time  // Start time for calculating elapsed time
+	quota     QuotaMeter // Optional API quota shown next to the counters
 }
 
 // NewProgressTracker creates a new progress tracker instance
@@ -43,6 +45,17 @@ func (p *ProgressTracker) UpdateProgress(success, valid bool) {
 	}
 }
 
+// UpdateSkipped counts an item that was skipped without processing it (thread-safe)
+func (p *ProgressTracker) UpdateSkipped() {
+	atomic.AddInt32(&p.processed, 1) // Skipped items still complete the run
+	atomic.AddInt32(&p.skipped, 1)   // Increment skipped count
+}
+
+// Skipped returns the number of items skipped because the API quota ran out (thread-safe)
+func (p *ProgressTracker) Skipped() int32 {
+	return atomic.LoadInt32(&p.skipped)
+}
+
 // GetProgress returns current progress information atomically
 // This method provides thread-safe access to progress counters and calculates percentage
 func (p *ProgressTracker) GetProgress() (processed, valid, errors int32, percentage float64) {
@@ -64,8 +77,45 @@ func (p *ProgressTracker) PrintProgress() {
 	processed, valid, errors, percentage := p.GetProgress()
 	elapsed := time.Since(p.startTime) // Calculate elapsed time
 
-	fmt.Printf("\r🔄 Progress: %d/%d (%.1f%%) | ✅ Valid: %d | ❌ Errors: %d | ⏱️  %v",
-		processed, p.total, percentage, valid, errors, elapsed.Round(time.Second))
+	fmt.Printf("\r🔄 Progress: %d/%d (%.1f%%) | ✅ Valid: %d | ❌ Errors: %d | ⏱️  %v%s",
+		processed, p.total, percentage, valid, errors, elapsed.Round(time.Second), p.quotaStatus())
+}
+
+// quotaStatus returns the quota part of the progress line, empty when no quota is tracked
+func (p *ProgressTracker) quotaStatus() string {
+	status := ""
+	if p.quota != nil {
+		used, limit := p.quota.Used(), p.quota.Limit()
+		if limit > 0 {
+			status = fmt.Sprintf(" | 🔑 Quota: %d/%d (%d left)", used, limit, max(limit-used, 0))
+		} else {
+			status = fmt.Sprintf(" | 🔑 Requests today: %d", used)
+		}
+	}
+	if skipped := p.Skipped(); skipped > 0 {
+		status += fmt.Sprintf(" | ⏭️  Skipped: %d", skipped)
+	}
+	return status
+}
+
+// Elapsed returns the time since processing started
+func (p *ProgressTracker) Elapsed() time.Duration {
+	return time.Since(p.startTime)
+}
+
+// ETA estimates the time left from the average pace so far; it reports false until the first item is processed
+func (p *ProgressTracker) ETA() (time.Duration, bool) {
+	processed := atomic.LoadInt32(&p.processed)
+	if processed == 0 {
+		return 0, false
+	}
+	remaining := max(p.total-processed, 0)
+	return p.Elapsed() / time.Duration(processed) * time.Duration(remaining), true
+}
+
+// Total returns the number of items to process
+func (p *ProgressTracker) Total() int32 {
+	return p.total
 }
 
 // IsComplete checks if processing is complete
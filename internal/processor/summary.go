@@ -0,0 +1,42 @@
+// Package processor provides concurrent stock processing functionality for the SAPAN strategy
+// This package handles parallel processing of multiple stocks with worker pools and progress tracking
+package processor
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/output"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Print renders the processing summary as a table, highlighting errors in yellow
+// Long and Short counts are mutually exclusive: each stock is either Long OR Short
+func (s ProcessingSummary) Print(w io.Writer, palette *output.Palette, duration time.Duration) {
+	fmt.Fprintln(w, palette.Bold("Processing Summary:"))
+
+	errorColor := palette.Plain
+	if s.Errors > 0 {
+		errorColor = palette.Yellow
+	}
+
+	table := output.NewTable(palette, "Metric", "Value")
+	table.AddRow(palette.Plain, "Total processed", strconv.Itoa(s.Total))
+	table.AddRow(palette.Plain, "Successful", strconv.Itoa(s.Successful))
+	table.AddRow(errorColor, "Errors", strconv.Itoa(s.Errors))
+	if s.Skipped > 0 {
+		table.AddRow(palette.Yellow, "Skipped (API quota)", strconv.Itoa(s.Skipped))
+	}
+	if s.Anomalous > 0 {
+		table.AddRow(palette.Yellow, "Candle anomalies", strconv.Itoa(s.Anomalous))
+	}
+	table.AddRow(palette.Plain, "Valid SAPAN setups", strconv.Itoa(s.Valid))
+	table.AddRow(palette.Green, "Long setups", strconv.Itoa(s.LongCount))
+	table.AddRow(palette.Red, "Short setups", strconv.Itoa(s.ShortCount))
+	table.AddRow(palette.Plain, "Duration", duration.Round(time.Second).String())
+	table.Render(w)
+
+	for _, failure := range s.Failures {
+		fmt.Fprintln(w, "  "+palette.Yellow(fmt.Sprintf("⚠️  %s: %s", failure.Symbol, failure.Error)))
+	}
+}
@@ -0,0 +1,237 @@
+// Package replay steps the scanner day by day over stored candles
+// Each replayed day reproduces the watch list a scan run after that session's close would have produced
+package replay
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/correlation"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
+	"sort"
+	"time"
+)
+
+// Series is a stock with its complete stored candle history, oldest first
+type Series struct {
+	Stock   models.Stock    // Stock metadata from the stock list
+	Candles []models.Candle // Stored candles sorted by date
+}
+
+// Entry is one setup on a replayed watch list
+type Entry struct {
+	Symbol         string    `json:"symbol"`                    // Stock ticker symbol
+	Side           string    `json:"side"`                      // Trading side (Long or Short)
+	Pattern        string    `json:"pattern"`                   // Candlestick pattern that confirmed the setup
+	Score          float64   `json:"score"`                     // Setup quality score from 0 to 100
+	CandleDate     time.Time `json:"candle_date"`               // Date of the last candle the setup was detected on
+	Entry          float64   `json:"entry"`                     // Suggested entry trigger price
+	Stop           float64   `json:"stop"`                      // Suggested stop-loss price
+	Target         float64   `json:"target"`                    // Suggested profit target price
+	CorrelatedWith string    `json:"correlated_with,omitempty"` // Stronger setup this one moves with (flag mode only)
+	Correlation    float64   `json:"correlation,omitempty"`     // Correlation of recent returns with CorrelatedWith
+}
+
+// Day is the watch list of one replayed session together with its changes against the previous session
+type Day struct {
+	Date    time.Time           `json:"date"`    // Session the scan ran after
+	Entries []Entry             `json:"entries"` // Setups on the watch list, sorted by side and symbol
+	Added   []watcher.DiffEntry `json:"added"`   // Setups that were not listed the previous session
+	Removed []watcher.DiffEntry `json:"removed"` // Setups listed the previous session but not detected again
+}
+
+// Replayer re-runs the scanner's per-stock decisions over historical sessions
+type Replayer struct {
+	sapanStrategy        strategy.Validator // SAPAN strategy for validation
+	history              int                // Candles a scan sees per stock (OUTPUT_SIZE)
+	correlationThreshold float64            // Correlation at which weaker setups are flagged or trimmed (0 disables)
+	correlationLookback  int                // Returns compared between setups
+	correlationTrim      bool               // Drop correlated setups instead of flagging them
+	cpuPool              *cpupool.Pool      // Optional pool validating the stocks of a day in parallel (nil validates inline)
+}
+
+// NewReplayer creates a replayer that gives the strategy at most history candles per stock, like a scan
+func NewReplayer(sapanStrategy strategy.Validator, history int) *Replayer {
+	return &Replayer{
+		sapanStrategy: sapanStrategy, // Initialize strategy
+		history:       history,       // Set candle count per stock
+	}
+}
+
+// SetCorrelation applies the scan's correlation filtering to every replayed day
+func (r *Replayer) SetCorrelation(threshold float64, lookback int, trim bool) {
+	r.correlationThreshold = threshold
+	r.correlationLookback = lookback
+	r.correlationTrim = trim
+}
+
+// SetCPUPool validates the stocks of each replayed day in parallel on the pool
+// The strategy must be safe for concurrent use; the replayed days are the same as without a pool
+func (r *Replayer) SetCPUPool(cpuPool *cpupool.Pool) {
+	r.cpuPool = cpuPool
+}
+
+// Sessions returns the distinct candle dates of all series between from and to, inclusive and in order
+func Sessions(series []Series, from, to time.Time) []time.Time {
+	seen := make(map[int64]bool)
+	var sessions []time.Time
+	for _, s := range series {
+		for _, candle := range s.Candles {
+			if candle.Date.Before(from) || candle.Date.After(to) || seen[candle.Date.Unix()] {
+				continue
+			}
+			seen[candle.Date.Unix()] = true
+			sessions = append(sessions, candle.Date)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Before(sessions[j]) })
+	return sessions
+}
+
+// Run replays every session in order and returns the watch list of each one
+func (r *Replayer) Run(series []Series, sessions []time.Time) []Day {
+	days := make([]Day, 0, len(sessions))
+	previous := map[watcher.DiffEntry]bool{}
+	for _, session := range sessions {
+		day := r.replayDay(series, session)
+
+		current := make(map[watcher.DiffEntry]bool, len(day.Entries))
+		for _, entry := range day.Entries {
+			key := watcher.DiffEntry{Symbol: entry.Symbol, Side: entry.Side}
+			current[key] = true
+			if !previous[key] {
+				day.Added = append(day.Added, key)
+			}
+		}
+		for key := range previous {
+			if !current[key] {
+				day.Removed = append(day.Removed, key)
+			}
+		}
+		sortDiffEntries(day.Removed)
+
+		days = append(days, day)
+		previous = current
+	}
+	return days
+}
+
+// decision is the validation of one stock on one replayed day
+type decision struct {
+	candles    []models.Candle           // Candles the scan would have seen
+	side       string                    // Side of the setup (empty when neither side is valid)
+	validation strategy.ValidationResult // Validation of that side
+}
+
+// decide validates one stock on the candles available at the close of session
+// Long setups take priority over short setups, exactly as in a live scan
+func (r *Replayer) decide(s Series, session time.Time) decision {
+	end := sort.Search(len(s.Candles), func(i int) bool { return s.Candles[i].Date.After(session) })
+	start := 0
+	if r.history > 0 && end > r.history {
+		start = end - r.history
+	}
+	candles := s.Candles[start:end]
+	if len(candles) < strategy.ValidatorMinimumCandles(r.sapanStrategy) {
+		return decision{}
+	}
+
+	validation := r.sapanStrategy.ValidateLongSetup(s.Stock.Symbol, candles)
+	side := watcher.LongSide
+	if !validation.IsValid {
+		validation = r.sapanStrategy.ValidateShortSetup(s.Stock.Symbol, candles)
+		side = watcher.ShortSide
+	}
+	if !validation.IsValid {
+		return decision{}
+	}
+	return decision{candles: candles, side: side, validation: validation}
+}
+
+// replayDay evaluates every stock on the candles available at the close of session
+// Stocks are validated on the CPU pool when one is set and assembled in series order, so the day is deterministic
+func (r *Replayer) replayDay(series []Series, session time.Time) Day {
+	day := Day{Date: session, Entries: []Entry{}, Added: []watcher.DiffEntry{}, Removed: []watcher.DiffEntry{}}
+	decisions := make([]decision, len(series))
+	cpupool.Each(r.cpuPool, len(series), func(i int) {
+		decisions[i] = r.decide(series[i], session)
+	})
+
+	var candidates []correlation.Candidate
+	for i, s := range series {
+		candles, side, validation := decisions[i].candles, decisions[i].side, decisions[i].validation
+		if side == "" {
+			continue
+		}
+
+		day.Entries = append(day.Entries, Entry{
+			Symbol:     s.Stock.Symbol,
+			Side:       side,
+			Pattern:    validation.PatternType.String(),
+			Score:      validation.Score,
+			CandleDate: candles[len(candles)-1].Date,
+			Entry:      validation.TradePlan.Entry,
+			Stop:       validation.TradePlan.Stop,
+			Target:     validation.TradePlan.Target,
+		})
+		closes := make([]float64, len(candles))
+		for i, candle := range candles {
+			closes[i] = candle.Close
+		}
+		candidates = append(candidates, correlation.Candidate{Symbol: s.Stock.Symbol, Side: side, Score: validation.Score, Closes: closes})
+	}
+
+	if r.correlationThreshold > 0 {
+		_, flagged := correlation.Screen(candidates, r.correlationLookback, r.correlationThreshold)
+		matches := make(map[watcher.DiffEntry]correlation.Match, len(flagged))
+		for _, match := range flagged {
+			matches[watcher.DiffEntry{Symbol: match.Symbol, Side: match.Side}] = match
+		}
+		kept := day.Entries[:0]
+		for _, entry := range day.Entries {
+			match, ok := matches[watcher.DiffEntry{Symbol: entry.Symbol, Side: entry.Side}]
+			if ok && r.correlationTrim {
+				continue
+			}
+			if ok {
+				entry.CorrelatedWith, entry.Correlation = match.CorrelatedWith, match.Correlation
+			}
+			kept = append(kept, entry)
+		}
+		day.Entries = kept
+	}
+
+	sort.Slice(day.Entries, func(i, j int) bool {
+		if day.Entries[i].Side != day.Entries[j].Side {
+			return day.Entries[i].Side == watcher.LongSide
+		}
+		return day.Entries[i].Symbol < day.Entries[j].Symbol
+	})
+	return day
+}
+
+// sortDiffEntries orders diff entries by side and symbol for stable output
+func sortDiffEntries(entries []watcher.DiffEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Side != entries[j].Side {
+			return entries[i].Side == watcher.LongSide
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+}
+
+// Print writes one line per replayed day with the watch list size and the setups that came and went
+func Print(w io.Writer, days []Day) {
+	for _, day := range days {
+		fmt.Fprintf(w, "%s  %3d listed", day.Date.Format("2006-01-02"), len(day.Entries))
+		for _, entry := range day.Added {
+			fmt.Fprintf(w, "  +%s %s", entry.Side, entry.Symbol)
+		}
+		for _, entry := range day.Removed {
+			fmt.Fprintf(w, "  -%s %s", entry.Side, entry.Symbol)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"github.com/erhankrygt/sapan/watcher"
+	"sort"
+)
+
+// unknownGroup names the group of stocks whose stock list entry has no sector or industry
+const unknownGroup = "Unknown"
+
+// Breadth summarizes a run by sector and by industry, a market-breadth view of the scanned universe
+type Breadth struct {
+	Sectors    []BreadthGroup `json:"sectors"`    // One group per sector, largest first
+	Industries []BreadthGroup `json:"industries"` // One group per industry, largest first
+}
+
+// BreadthGroup holds the setups and EMA trends of the stocks of one sector or industry
+type BreadthGroup struct {
+	Name             string  `json:"name"`              // Sector or industry ("Unknown" when the stock list has none)
+	Sector           string  `json:"sector,omitempty"`  // Sector the industry belongs to (industry groups only)
+	Stocks           int     `json:"stocks"`            // Stocks of the group in the run
+	Analyzed         int     `json:"analyzed"`          // Stocks analyzed without errors
+	Long             int     `json:"long"`              // Long setups found
+	Short            int     `json:"short"`             // Short setups found
+	AverageScore     float64 `json:"average_score"`     // Average score of the group's setups (0 when there are none)
+	UptrendPercent   float64 `json:"uptrend_percent"`   // Share of analyzed stocks with EMAs in uptrend order (0-100)
+	DowntrendPercent float64 `json:"downtrend_percent"` // Share of analyzed stocks with EMAs in downtrend order (0-100)
+
+	totalScore float64 // Sum of the setup scores, averaged once every stock is counted
+	uptrend    int     // Analyzed stocks in uptrend order
+	downtrend  int     // Analyzed stocks in downtrend order
+}
+
+// BuildBreadth groups the symbol results of a run by sector and by industry
+// Stocks that failed count towards Stocks only; the trend shares are taken over the analyzed stocks
+func BuildBreadth(symbols []SymbolResult) Breadth {
+	sectors := make(map[string]*BreadthGroup)
+	industries := make(map[[2]string]*BreadthGroup)
+	for _, symbol := range symbols {
+		sector, industry := labelOr(symbol.Sector), labelOr(symbol.Industry)
+		sectorGroup, ok := sectors[sector]
+		if !ok {
+			sectorGroup = &BreadthGroup{Name: sector}
+			sectors[sector] = sectorGroup
+		}
+		industryGroup, ok := industries[[2]string{sector, industry}]
+		if !ok {
+			industryGroup = &BreadthGroup{Name: industry, Sector: sector}
+			industries[[2]string{sector, industry}] = industryGroup
+		}
+		sectorGroup.add(symbol)
+		industryGroup.add(symbol)
+	}
+
+	breadth := Breadth{
+		Sectors:    make([]BreadthGroup, 0, len(sectors)),
+		Industries: make([]BreadthGroup, 0, len(industries)),
+	}
+	for _, group := range sectors {
+		breadth.Sectors = append(breadth.Sectors, group.finish())
+	}
+	for _, group := range industries {
+		breadth.Industries = append(breadth.Industries, group.finish())
+	}
+	sortGroups(breadth.Sectors)
+	sortGroups(breadth.Industries)
+	return breadth
+}
+
+// add counts one symbol result towards the group
+func (g *BreadthGroup) add(symbol SymbolResult) {
+	g.Stocks++
+	if symbol.Status == StatusError || symbol.Long == nil {
+		return
+	}
+	g.Analyzed++
+	if symbol.Long.EMATrend {
+		g.uptrend++
+	}
+	if symbol.Short != nil && symbol.Short.EMATrend {
+		g.downtrend++
+	}
+	switch symbol.Side {
+	case watcher.LongSide:
+		g.Long++
+		g.totalScore += symbol.Long.Score
+	case watcher.ShortSide:
+		g.Short++
+		g.totalScore += symbol.Short.Score
+	}
+}
+
+// finish derives the averages and shares from the counts
+func (g *BreadthGroup) finish() BreadthGroup {
+	if setups := g.Long + g.Short; setups > 0 {
+		g.AverageScore = g.totalScore / float64(setups)
+	}
+	if g.Analyzed > 0 {
+		g.UptrendPercent = float64(g.uptrend) / float64(g.Analyzed) * 100
+		g.DowntrendPercent = float64(g.downtrend) / float64(g.Analyzed) * 100
+	}
+	return *g
+}
+
+// sortGroups orders groups by stock count, then by sector and name
+func sortGroups(groups []BreadthGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Stocks != groups[j].Stocks {
+			return groups[i].Stocks > groups[j].Stocks
+		}
+		if groups[i].Sector != groups[j].Sector {
+			return groups[i].Sector < groups[j].Sector
+		}
+		return groups[i].Name < groups[j].Name
+	})
+}
+
+// labelOr returns label, or "Unknown" when the stock list left it empty
+func labelOr(label string) string {
+	if label == "" {
+		return unknownGroup
+	}
+	return label
+}
@@ -0,0 +1,186 @@
+// Package report builds machine- and human-readable reports of SAPAN scan runs
+// This package turns processing results and the watch list into stable documents for downstream automation
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"html/template"
+	"path/filepath"
+	"time"
+)
+
+// htmlReportLayout is the timestamp layout used in report file names so files sort chronologically
+const htmlReportLayout = "20060102-150405"
+
+// WriteHTML renders a self-contained HTML report of the run into dir and returns the file path
+// The report embeds its styles and sorting script, so it can be opened offline or mailed as an attachment
+func WriteHTML(dir string, result RunResult, location *time.Location) (string, error) {
+	if location == nil {
+		location = time.Local
+	}
+
+	var buffer bytes.Buffer
+	err := htmlReportTemplate.Execute(&buffer, htmlReportData{
+		RunResult: result,
+		Started:   result.StartedAt.In(location).Format("2006-01-02 15:04:05 MST"),
+		Duration:  (time.Duration(result.DurationMS) * time.Millisecond).Round(time.Second).String(),
+		Rules:     ruleBreakdown(result.Symbols),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %v", err)
+	}
+
+	path := filepath.Join(dir, "sapan-report-"+result.StartedAt.In(location).Format(htmlReportLayout)+".html")
+	if err := fsutil.WriteFileAtomic(path, buffer.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to write HTML report: %v", err)
+	}
+	return path, nil
+}
+
+// htmlReportData is the view model passed to the HTML template
+type htmlReportData struct {
+	RunResult
+	Started  string     // Formatted start time in the display timezone
+	Duration string     // Formatted run duration
+	Rules    []ruleStat // How many analyzed stocks passed each rule
+}
+
+// ruleStat counts how many analyzed stocks passed a rule on each side
+type ruleStat struct {
+	Name       string // Rule name
+	LongPass   int    // Stocks passing the rule on the Long side
+	ShortPass  int    // Stocks passing the rule on the Short side
+	LongTotal  int    // Stocks evaluated on the Long side
+	ShortTotal int    // Stocks evaluated on the Short side
+}
+
+// ruleBreakdown counts rule passes across all analyzed symbols
+// Rules are evaluated in order and stop at the first failure, so later rules see fewer stocks
+func ruleBreakdown(symbols []SymbolResult) []ruleStat {
+	stats := []ruleStat{{Name: "EMA trend"}, {Name: "Stochastic RSI"}, {Name: "MACD"}, {Name: "Pattern"}}
+	count := func(rule *RuleResult, pass func(stat *ruleStat), total func(stat *ruleStat)) {
+		if rule == nil {
+			return
+		}
+		passed := []bool{rule.EMATrend, rule.Stochastic, rule.MACD, rule.Pattern}
+		for i := range stats {
+			total(&stats[i])
+			if passed[i] {
+				pass(&stats[i])
+			}
+		}
+	}
+	for _, symbol := range symbols {
+		count(symbol.Long, func(s *ruleStat) { s.LongPass++ }, func(s *ruleStat) { s.LongTotal++ })
+		count(symbol.Short, func(s *ruleStat) { s.ShortPass++ }, func(s *ruleStat) { s.ShortTotal++ })
+	}
+	return stats
+}
+
+// htmlReportTemplate is the self-contained layout of the HTML report
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"check": func(ok bool) string {
+		if ok {
+			return "✔"
+		}
+		return "✘"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>SAPAN Report {{.Started}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th.sortable { cursor: pointer; background: #f3f3f3; }
+th.sortable:after { content: " ⇅"; color: #999; }
+.Long { color: #1a7f37; font-weight: bold; }
+.Short { color: #cf222e; font-weight: bold; }
+.error { color: #9a6700; }
+.stats td:first-child { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>SAPAN Scan Report</h1>
+<p>Started {{.Started}} &middot; took {{.Duration}}</p>
+
+<h2>Run Statistics</h2>
+<table class="stats">
+<tr><td>Processed</td><td>{{.Summary.Total}}</td></tr>
+<tr><td>Successful</td><td>{{.Summary.Successful}}</td></tr>
+<tr><td>Errors</td><td>{{.Summary.Errors}}</td></tr>
+<tr><td>Setups</td><td>{{.Summary.Valid}} ({{.Summary.LongCount}} long / {{.Summary.ShortCount}} short)</td></tr>
+<tr><td>New since previous run</td><td>{{len .Changes.New}}</td></tr>
+<tr><td>Disappeared since previous run</td><td>{{len .Changes.Disappeared}}</td></tr>
+</table>
+
+<h2>Signals</h2>
+<table class="sortable">
+<thead><tr><th class="sortable">Symbol</th><th class="sortable">Side</th><th class="sortable">Pattern</th><th class="sortable">Score</th><th class="sortable">Entry</th><th class="sortable">Stop</th><th class="sortable">Target</th><th class="sortable">R:R</th><th class="sortable">Sector</th><th class="sortable">Exchange</th><th class="sortable">Candle</th><th>Chart</th></tr></thead>
+<tbody>
+{{range .WatchList.Long}}<tr><td>{{.Symbol}}</td><td class="Long">{{.Side}}</td><td>{{.Pattern}}</td><td>{{printf "%.1f" .Score}}</td><td>{{printf "%.2f" .Entry}}</td><td>{{printf "%.2f" .Stop}}</td><td>{{printf "%.2f" .Target}}</td><td>{{printf "%.1f" .RiskReward}}</td><td>{{.Sector}}</td><td>{{.Exchange}} {{.Currency}}</td><td>{{.CandleDate.Format "2006-01-02"}}</td><td>{{with .Charts}}<a href="{{.TradingView}}">TradingView</a>{{if .Yahoo}} &middot; <a href="{{.Yahoo}}">Yahoo</a>{{end}}{{end}}</td></tr>
+{{end}}{{range .WatchList.Short}}<tr><td>{{.Symbol}}</td><td class="Short">{{.Side}}</td><td>{{.Pattern}}</td><td>{{printf "%.1f" .Score}}</td><td>{{printf "%.2f" .Entry}}</td><td>{{printf "%.2f" .Stop}}</td><td>{{printf "%.2f" .Target}}</td><td>{{printf "%.1f" .RiskReward}}</td><td>{{.Sector}}</td><td>{{.Exchange}} {{.Currency}}</td><td>{{.CandleDate.Format "2006-01-02"}}</td><td>{{with .Charts}}<a href="{{.TradingView}}">TradingView</a>{{if .Yahoo}} &middot; <a href="{{.Yahoo}}">Yahoo</a>{{end}}{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Rule Breakdown</h2>
+<table>
+<thead><tr><th>Rule</th><th>Long passed</th><th>Short passed</th></tr></thead>
+<tbody>
+{{range .Rules}}<tr><td>{{.Name}}</td><td>{{.LongPass}} / {{.LongTotal}}</td><td>{{.ShortPass}} / {{.ShortTotal}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Sector Breadth</h2>
+<table class="sortable">
+<thead><tr><th class="sortable">Sector</th><th class="sortable">Stocks</th><th class="sortable">Analyzed</th><th class="sortable">Long</th><th class="sortable">Short</th><th class="sortable">Avg score</th><th class="sortable">Uptrend %</th><th class="sortable">Downtrend %</th></tr></thead>
+<tbody>
+{{range .Breadth.Sectors}}<tr><td>{{.Name}}</td><td>{{.Stocks}}</td><td>{{.Analyzed}}</td><td>{{.Long}}</td><td>{{.Short}}</td><td>{{printf "%.1f" .AverageScore}}</td><td>{{printf "%.0f" .UptrendPercent}}</td><td>{{printf "%.0f" .DowntrendPercent}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Industry Breadth</h2>
+<table class="sortable">
+<thead><tr><th class="sortable">Industry</th><th class="sortable">Sector</th><th class="sortable">Stocks</th><th class="sortable">Analyzed</th><th class="sortable">Long</th><th class="sortable">Short</th><th class="sortable">Avg score</th><th class="sortable">Uptrend %</th><th class="sortable">Downtrend %</th></tr></thead>
+<tbody>
+{{range .Breadth.Industries}}<tr><td>{{.Name}}</td><td>{{.Sector}}</td><td>{{.Stocks}}</td><td>{{.Analyzed}}</td><td>{{.Long}}</td><td>{{.Short}}</td><td>{{printf "%.1f" .AverageScore}}</td><td>{{printf "%.0f" .UptrendPercent}}</td><td>{{printf "%.0f" .DowntrendPercent}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Symbols</h2>
+<table class="sortable">
+<thead><tr><th class="sortable">Symbol</th><th class="sortable">Status</th><th class="sortable">Long EMA</th><th class="sortable">Long Stoch</th><th class="sortable">Long MACD</th><th class="sortable">Long Pattern</th><th class="sortable">Short EMA</th><th class="sortable">Short Stoch</th><th class="sortable">Short MACD</th><th class="sortable">Short Pattern</th><th class="sortable">Time (ms)</th><th>Message</th></tr></thead>
+<tbody>
+{{range .Symbols}}<tr><td>{{.Symbol}}</td><td{{if eq .Status "error"}} class="error"{{else if .Side}} class="{{.Side}}"{{end}}>{{.Status}}</td>
+{{with .Long}}<td>{{check .EMATrend}}</td><td>{{check .Stochastic}}</td><td>{{check .MACD}}</td><td>{{check .Pattern}}</td>{{else}}<td></td><td></td><td></td><td></td>{{end}}
+{{with .Short}}<td>{{check .EMATrend}}</td><td>{{check .Stochastic}}</td><td>{{check .MACD}}</td><td>{{check .Pattern}}</td>{{else}}<td></td><td></td><td></td><td></td>{{end}}
+<td>{{.DurationMS}}</td><td>{{if .Error}}{{.Error}}{{else}}{{.Message}}{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th.sortable").forEach(function (header, column) {
+    var ascending = true;
+    header.addEventListener("click", function () {
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].textContent, y = b.cells[column].textContent;
+        var nx = parseFloat(x), ny = parseFloat(y);
+        var order = (!isNaN(nx) && !isNaN(ny)) ? nx - ny : x.localeCompare(y);
+        return ascending ? order : -order;
+      });
+      ascending = !ascending;
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
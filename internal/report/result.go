@@ -0,0 +1,202 @@
+// Package report builds machine- and human-readable reports of SAPAN scan runs
+// This package turns processing results and the watch list into stable documents for downstream automation
+package report
+
+import (
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"time"
+)
+
+// SchemaVersion is bumped whenever a field of RunResult is renamed, removed, or changes meaning
+// Adding fields does not change the version
+const SchemaVersion = 1
+
+// Symbol status values reported in SymbolResult.Status
+const (
+	StatusSignal  = "signal"   // A valid setup was found
+	StatusNoSetup = "no_setup" // The stock was analyzed but no setup was found
+	StatusError   = "error"    // The stock could not be processed
+)
+
+// RunResult is the complete, stable-schema result of a scan run
+type RunResult struct {
+	SchemaVersion int            `json:"schema_version"` // Version of this document's schema
+	StartedAt     time.Time      `json:"started_at"`     // Time the scan started
+	FinishedAt    time.Time      `json:"finished_at"`    // Time the scan finished
+	DurationMS    int64          `json:"duration_ms"`    // Wall-clock duration of the scan in milliseconds
+	Summary       RunSummary     `json:"summary"`        // Aggregated counts
+	Symbols       []SymbolResult `json:"symbols"`        // Per-symbol outcome and rule detail, sorted by symbol
+	WatchList     WatchList      `json:"watchlist"`      // Watch list after the run
+	Changes       Changes        `json:"changes"`        // Watch list changes since the previous run
+	Breadth       Breadth        `json:"breadth"`        // Setups and EMA trends by sector and industry
+}
+
+// RunSummary holds the aggregated counts of a run
+type RunSummary struct {
+	Total      int `json:"total"`       // Number of stocks processed
+	Successful int `json:"successful"`  // Stocks analyzed without errors
+	Errors     int `json:"errors"`      // Stocks that failed to process
+	Valid      int `json:"valid"`       // Valid SAPAN setups found
+	LongCount  int `json:"long_count"`  // Long setups found
+	ShortCount int `json:"short_count"` // Short setups found
+}
+
+// SymbolResult describes what happened to one stock during the run
+type SymbolResult struct {
+	Symbol     string      `json:"symbol"`             // Stock ticker symbol
+	Sector     string      `json:"sector,omitempty"`   // Business sector from the stock list
+	Industry   string      `json:"industry,omitempty"` // Industry from the stock list
+	Status     string      `json:"status"`             // signal, no_setup, or error
+	Side       string      `json:"side,omitempty"`     // Side of the setup when Status is signal
+	Error      string      `json:"error,omitempty"`    // Error message when Status is error
+	Message    string      `json:"message"`            // Human-readable outcome
+	Candles    int         `json:"candles"`            // Closed candles analyzed
+	DurationMS int64       `json:"duration_ms"`        // Time spent fetching and analyzing the stock
+	Long       *RuleResult `json:"long,omitempty"`     // Long rule detail (absent on errors)
+	Short      *RuleResult `json:"short,omitempty"`    // Short rule detail (absent on errors, and when Long was valid unless both sides are reported)
+}
+
+// RuleResult is the per-rule breakdown of one side's validation
+type RuleResult struct {
+	Valid       bool    `json:"valid"`           // All rules passed
+	EMATrend    bool    `json:"ema_trend"`       // EMA trend order rule
+	Stochastic  bool    `json:"stochastic"`      // Stochastic RSI rule
+	MACD        bool    `json:"macd"`            // MACD rule
+	Pattern     bool    `json:"pattern"`         // Candlestick pattern rule
+	PatternType string  `json:"pattern_type"`    // Detected pattern name
+	Message     string  `json:"message"`         // Validation message (first failing rule, or success)
+	Score       float64 `json:"score,omitempty"` // Setup quality score for valid setups
+	Rules       []Rule  `json:"rules,omitempty"` // Measured rules with the gap of failed ones (explain mode only)
+}
+
+// Rule is one measured rule of an explained validation
+type Rule struct {
+	Name     string `json:"name"`          // Rule name: EMA, Stoch, MACD, or Pattern
+	Passed   bool   `json:"passed"`        // Whether the rule holds
+	Measured string `json:"measured"`      // Values the rule was decided on
+	Needs    string `json:"needs"`         // What the rule requires to pass
+	Gap      string `json:"gap,omitempty"` // How far a failed rule is from passing
+}
+
+// WatchList holds the watch list entries by side, newest first
+type WatchList struct {
+	Long  []watcher.WatchListEntry `json:"long"`  // Long setups
+	Short []watcher.WatchListEntry `json:"short"` // Short setups
+}
+
+// Changes lists watch list changes since the previous run
+type Changes struct {
+	New         []watcher.DiffEntry `json:"new"`         // Setups detected for the first time
+	Persisted   []watcher.DiffEntry `json:"persisted"`   // Setups detected again
+	Disappeared []watcher.DiffEntry `json:"disappeared"` // Setups no longer detected
+}
+
+// BuildRunResult assembles the run result from the processing summary, the watch list, and its diff
+func BuildRunResult(startedAt, finishedAt time.Time, summary processor.ProcessingSummary,
+	watchList *watcher.WatchListManager, diff watcher.WatchListDiff) RunResult {
+	result := RunResult{
+		SchemaVersion: SchemaVersion,
+		StartedAt:     startedAt.UTC(),
+		FinishedAt:    finishedAt.UTC(),
+		DurationMS:    finishedAt.Sub(startedAt).Milliseconds(),
+		Summary: RunSummary{
+			Total:      summary.Total,
+			Successful: summary.Successful,
+			Errors:     summary.Errors,
+			Valid:      summary.Valid,
+			LongCount:  summary.LongCount,
+			ShortCount: summary.ShortCount,
+		},
+		Symbols: make([]SymbolResult, 0, len(summary.Results)),
+		WatchList: WatchList{
+			Long:  nonNilEntries(watchList.GetEntriesBySide(watcher.LongSide)),
+			Short: nonNilEntries(watchList.GetEntriesBySide(watcher.ShortSide)),
+		},
+		Changes: Changes{
+			New:         nonNilDiff(diff.New),
+			Persisted:   nonNilDiff(diff.Persisted),
+			Disappeared: nonNilDiff(diff.Disappeared),
+		},
+	}
+
+	for _, processed := range summary.Results {
+		result.Symbols = append(result.Symbols, symbolResult(processed))
+	}
+	result.Breadth = BuildBreadth(result.Symbols)
+	return result
+}
+
+// symbolResult converts a processing result into its report form
+func symbolResult(processed processor.ProcessingResult) SymbolResult {
+	symbol := SymbolResult{
+		Symbol:     processed.Symbol,
+		Sector:     processed.Sector,
+		Industry:   processed.Industry,
+		Message:    processed.Message,
+		Candles:    processed.Candles,
+		DurationMS: processed.Duration.Milliseconds(),
+	}
+
+	if !processed.Success {
+		symbol.Status = StatusError
+		symbol.Error = processed.Error.Error()
+		symbol.Message = "Failed to process stock"
+		return symbol
+	}
+
+	symbol.Status = StatusNoSetup
+	switch {
+	case processed.IsLongValid:
+		symbol.Status, symbol.Side = StatusSignal, watcher.LongSide
+	case processed.IsShortValid:
+		symbol.Status, symbol.Side = StatusSignal, watcher.ShortSide
+	}
+
+	symbol.Long = ruleResult(processed.LongResult, processed.LongRules)
+	if processed.ShortChecked {
+		symbol.Short = ruleResult(processed.ShortResult, processed.ShortRules)
+	}
+	return symbol
+}
+
+// ruleResult converts a strategy validation result and its measured rules into their report form
+func ruleResult(validation strategy.ValidationResult, rules []strategy.RuleCheck) *RuleResult {
+	result := &RuleResult{
+		Valid:       validation.IsValid,
+		EMATrend:    validation.EMATrendValid,
+		Stochastic:  validation.StochasticValid,
+		MACD:        validation.MACDValid,
+		Pattern:     validation.PatternValid,
+		PatternType: validation.PatternType.String(),
+		Message:     validation.ValidationMessage,
+		Score:       validation.Score,
+	}
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, Rule{
+			Name:     rule.Name,
+			Passed:   rule.Passed,
+			Measured: rule.Measured,
+			Needs:    rule.Needs,
+			Gap:      rule.Gap,
+		})
+	}
+	return result
+}
+
+// nonNilEntries returns an empty slice instead of nil so the JSON always contains an array
+func nonNilEntries(entries []watcher.WatchListEntry) []watcher.WatchListEntry {
+	if entries == nil {
+		return []watcher.WatchListEntry{}
+	}
+	return entries
+}
+
+// nonNilDiff returns an empty slice instead of nil so the JSON always contains an array
+func nonNilDiff(entries []watcher.DiffEntry) []watcher.DiffEntry {
+	if entries == nil {
+		return []watcher.DiffEntry{}
+	}
+	return entries
+}
@@ -0,0 +1,21 @@
+// Package report builds machine- and human-readable reports of SAPAN scan runs
+// This package turns processing results and the watch list into stable documents for downstream automation
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+)
+
+// WriteJSON writes the run result as indented JSON, replacing the file atomically
+func WriteJSON(path string, result RunResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run result: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run result: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package scheduler runs SAPAN scans repeatedly from a long-running daemon
+// This package parses cron and market-close schedules and tracks the status of scheduled runs
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Daemon states reported by Status
+const (
+	StateIdle    = "idle"    // Waiting for the next scheduled run
+	StateRunning = "running" // A scan is in progress
+	StateStopped = "stopped" // The daemon has shut down
+)
+
+// RunResult is what a scheduled job reports back when it finishes
+type RunResult struct {
+	ExitCode  int `json:"exit_code"` // Exit code the scan would have returned as a one-shot run
+	Processed int `json:"processed"` // Stocks processed
+	Signals   int `json:"signals"`   // Valid setups found
+	Errors    int `json:"errors"`    // Stocks that failed to process
+}
+
+// RunRecord describes one scheduled run
+type RunRecord struct {
+	StartedAt  time.Time  `json:"started_at"`            // Time the run started
+	FinishedAt *time.Time `json:"finished_at,omitempty"` // Time the run finished (absent while running)
+	Result     *RunResult `json:"result,omitempty"`      // Outcome of the run (absent while running)
+}
+
+// Status is a snapshot of the daemon served on the status endpoint
+type Status struct {
+	Name      string     `json:"name,omitempty"`     // Market section the daemon scans (empty for a single configuration)
+	State     string     `json:"state"`              // idle, running, or stopped
+	Schedules []string   `json:"schedules"`          // Configured schedule expressions
+	NextRun   *time.Time `json:"next_run,omitempty"` // Time of the next scheduled run
+	Current   *RunRecord `json:"current,omitempty"`  // Run in progress
+	LastRun   *RunRecord `json:"last_run,omitempty"` // Most recently finished run
+	Runs      int        `json:"runs"`               // Runs completed since the daemon started
+}
+
+// Daemon triggers a job on its schedules until its context is cancelled
+// Runs never overlap: a run that is due while another is in progress waits for the next slot
+type Daemon struct {
+	name      string           // Market section shown in logs and the status (empty for a single configuration)
+	schedules []Schedule       // Schedules deciding when the job runs
+	job       func() RunResult // Scan to run
+	now       func() time.Time // Clock, replaceable for tests
+	status    Status           // Current status
+	mutex     sync.RWMutex     // Read-write mutex guarding status
+}
+
+// NewDaemon creates a daemon that runs job on the given schedules
+func NewDaemon(schedules []Schedule, job func() RunResult) *Daemon {
+	expressions := make([]string, len(schedules))
+	for i, schedule := range schedules {
+		expressions[i] = schedule.String()
+	}
+	return &Daemon{
+		schedules: schedules,                                        // Store schedules
+		job:       job,                                              // Store job
+		now:       time.Now,                                         // Use the wall clock
+		status:    Status{State: StateIdle, Schedules: expressions}, // Start idle
+	}
+}
+
+// SetName names the market section the daemon scans, so daemons sharing one process can be told apart
+func (d *Daemon) SetName(name string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.name = name
+	d.status.Name = name
+}
+
+// Run waits for each scheduled time and runs the job, returning when ctx is cancelled
+func (d *Daemon) Run(ctx context.Context) {
+	defer d.setState(StateStopped)
+	for {
+		next := NextRun(d.schedules, d.now())
+		if next.IsZero() {
+			log.Printf("⚠️  No schedule can fire again; stopping the daemon")
+			return
+		}
+		d.mutex.Lock()
+		d.status.NextRun = &next
+		d.mutex.Unlock()
+		if d.name != "" {
+			log.Printf("⏰ Next %s scan at %s", d.name, next.Format("2006-01-02 15:04:05 MST"))
+		} else {
+			log.Printf("⏰ Next scan at %s", next.Format("2006-01-02 15:04:05 MST"))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		d.runOnce()
+	}
+}
+
+// runOnce runs the job and records its outcome
+func (d *Daemon) runOnce() {
+	record := &RunRecord{StartedAt: d.now()}
+	d.mutex.Lock()
+	d.status.State = StateRunning
+	d.status.Current = record
+	d.status.NextRun = nil
+	d.mutex.Unlock()
+
+	result := d.job()
+
+	finished := d.now()
+	d.mutex.Lock()
+	d.status.State = StateIdle
+	d.status.Current = nil
+	d.status.LastRun = &RunRecord{StartedAt: record.StartedAt, FinishedAt: &finished, Result: &result}
+	d.status.Runs++
+	d.mutex.Unlock()
+}
+
+// setState changes the reported daemon state
+func (d *Daemon) setState(state string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.status.State = state
+	d.status.NextRun = nil
+}
+
+// Status returns a snapshot of the daemon (thread-safe)
+func (d *Daemon) Status() Status {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.status
+}
+
+// ServeHTTP serves the status snapshot as JSON
+func (d *Daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d.Status()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Group serves the status snapshots of several daemons running in one process as a JSON array
+type Group []*Daemon
+
+// ServeHTTP serves the status snapshot of every daemon in the group
+func (g Group) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]Status, len(g))
+	for i, daemon := range g {
+		statuses[i] = daemon.Status()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(statuses); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
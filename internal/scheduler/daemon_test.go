@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erhankrygt/sapan/internal/calendar"
+)
+
+func TestGroupServesEverySectionStatus(t *testing.T) {
+	crypto, err := calendar.New(calendar.MarketCrypto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedules, err := ParseSchedules("@close+30m", crypto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := func() RunResult { return RunResult{} }
+	us, cryptoDaemon := NewDaemon(schedules, job), NewDaemon(schedules, job)
+	us.SetName("us")
+	cryptoDaemon.SetName("crypto")
+
+	recorder := httptest.NewRecorder()
+	Group{us, cryptoDaemon}.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want 200", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("content type = %q, want application/json", contentType)
+	}
+	var statuses []Status
+	if err := json.Unmarshal(recorder.Body.Bytes(), &statuses); err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != "us" || statuses[1].Name != "crypto" {
+		t.Fatalf("statuses = %+v, want us then crypto", statuses)
+	}
+	for _, status := range statuses {
+		if status.State != StateIdle || len(status.Schedules) != 1 || status.Schedules[0] != "@close+30m" {
+			t.Errorf("%s status = %+v, want idle on @close+30m", status.Name, status)
+		}
+	}
+}
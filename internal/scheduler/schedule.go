@@ -0,0 +1,222 @@
+// Package scheduler runs SAPAN scans repeatedly from a long-running daemon
+// This package parses cron and market-close schedules and tracks the status of scheduled runs
+package scheduler
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/calendar"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs next
+type Schedule interface {
+	Next(after time.Time) time.Time // First run time strictly after the given time
+	String() string                 // Expression the schedule was parsed from
+}
+
+// ParseSchedule parses a five-field cron expression ("30 16 * * 1-5") or a market-close offset ("@close+30m")
+// Cron fields are evaluated in the market's timezone; close offsets only fire on the calendar's trading days
+func ParseSchedule(expr string, cal *calendar.Calendar) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@close") {
+		return parseCloseSchedule(expr, cal)
+	}
+	return parseCronSchedule(expr, cal.Location)
+}
+
+// ParseSchedules parses a semicolon-separated list of schedules, e.g. "@close+30m; 0 12 * * 1-5"
+func ParseSchedules(exprs string, cal *calendar.Calendar) ([]Schedule, error) {
+	var schedules []Schedule
+	for _, expr := range strings.Split(exprs, ";") {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		schedule, err := ParseSchedule(expr, cal)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	if len(schedules) == 0 {
+		return nil, fmt.Errorf("no schedule given")
+	}
+	return schedules, nil
+}
+
+// NextRun returns the earliest next run time across schedules (zero when no schedule can fire again)
+func NextRun(schedules []Schedule, after time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range schedules {
+		candidate := schedule.Next(after)
+		if candidate.IsZero() {
+			continue
+		}
+		if next.IsZero() || candidate.Before(next) {
+			next = candidate
+		}
+	}
+	return next
+}
+
+// closeSchedule fires a fixed offset after the close of every trading session
+type closeSchedule struct {
+	expr   string             // Original expression
+	offset time.Duration      // Delay after the session close
+	cal    *calendar.Calendar // Market calendar deciding trading days and close times
+}
+
+// parseCloseSchedule parses "@close", "@close+30m", or "@close-15m"
+func parseCloseSchedule(expr string, cal *calendar.Calendar) (Schedule, error) {
+	rest := strings.TrimPrefix(expr, "@close")
+	var offset time.Duration
+	if rest != "" {
+		var err error
+		if offset, err = time.ParseDuration(rest); err != nil {
+			return nil, fmt.Errorf("invalid close offset in %q: %v", expr, err)
+		}
+	}
+	return &closeSchedule{expr: expr, offset: offset, cal: cal}, nil
+}
+
+// Next returns the first session close plus offset after the given time
+func (s *closeSchedule) Next(after time.Time) time.Time {
+	local := after.In(s.cal.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.cal.Location).AddDate(0, 0, -1)
+	for {
+		if s.cal.IsTradingDay(day) {
+			if run := s.cal.SessionClose(day).Add(s.offset); run.After(after) {
+				return run
+			}
+		}
+		day = s.cal.NextTradingDay(day)
+	}
+}
+
+// String returns the original expression
+func (s *closeSchedule) String() string {
+	return s.expr
+}
+
+// cronSchedule is a standard five-field cron schedule evaluated in a fixed timezone
+type cronSchedule struct {
+	expr     string         // Original expression
+	location *time.Location // Timezone the fields are evaluated in
+	minutes  [60]bool       // Allowed minutes
+	hours    [24]bool       // Allowed hours
+	days     [32]bool       // Allowed days of the month
+	months   [13]bool       // Allowed months
+	weekdays [7]bool        // Allowed weekdays (0 = Sunday)
+	anyDay   bool           // Day-of-month field was "*"
+	anyWeek  bool           // Day-of-week field was "*"
+}
+
+// parseCronSchedule parses "minute hour day-of-month month day-of-week" with *, lists, ranges, and steps
+func parseCronSchedule(expr string, location *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields", expr)
+	}
+
+	s := &cronSchedule{expr: expr, location: location, anyDay: fields[2] == "*", anyWeek: fields[4] == "*"}
+	specs := []struct {
+		field    string
+		min, max int
+		set      func(int)
+	}{
+		{fields[0], 0, 59, func(v int) { s.minutes[v] = true }},
+		{fields[1], 0, 23, func(v int) { s.hours[v] = true }},
+		{fields[2], 1, 31, func(v int) { s.days[v] = true }},
+		{fields[3], 1, 12, func(v int) { s.months[v] = true }},
+		{fields[4], 0, 7, func(v int) { s.weekdays[v%7] = true }}, // 7 is Sunday as well
+	}
+	for _, spec := range specs {
+		if err := parseCronField(spec.field, spec.min, spec.max, spec.set); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %v", expr, err)
+		}
+	}
+	return s, nil
+}
+
+// parseCronField expands one cron field such as "*/15", "1-5", or "0,30" into allowed values
+func parseCronField(field string, min, max int, set func(int)) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+
+		low, high := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			low, err1 = strconv.Atoi(bounds[0])
+			high, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			low, high = value, value
+		}
+		if low < min || high > max || low > high {
+			return fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := low; v <= high; v += step {
+			set(v)
+		}
+	}
+	return nil
+}
+
+// Next returns the first matching minute strictly after the given time
+// Like cron, a restricted day-of-month and day-of-week match when either one matches
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0) // Impossible schedules such as Feb 30 give up instead of looping forever
+	for t.Before(limit) {
+		if !s.months[t.Month()] || !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location).AddDate(0, 0, 1)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.location).Add(time.Hour)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day-of-month / day-of-week rule
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	day, week := s.days[t.Day()], s.weekdays[t.Weekday()]
+	switch {
+	case s.anyDay && s.anyWeek:
+		return true
+	case s.anyDay:
+		return week
+	case s.anyWeek:
+		return day
+	}
+	return day || week
+}
+
+// String returns the original expression
+func (s *cronSchedule) String() string {
+	return s.expr
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/internal/calendar"
+)
+
+func TestCloseScheduleRunsCryptoScansOnWeekends(t *testing.T) {
+	crypto, err := calendar.New(calendar.MarketCrypto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := ParseSchedule("@close+30m", crypto)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC) // Friday evening
+	for _, want := range []time.Time{
+		time.Date(2024, 3, 9, 0, 30, 0, 0, time.UTC),  // Friday's candle, closed at midnight UTC
+		time.Date(2024, 3, 10, 0, 30, 0, 0, time.UTC), // Saturday's candle
+		time.Date(2024, 3, 11, 0, 30, 0, 0, time.UTC), // Sunday's candle
+	} {
+		if next = schedule.Next(next); !next.Equal(want) {
+			t.Fatalf("next run = %s, want %s", next, want)
+		}
+	}
+}
+
+func TestCloseScheduleFollowsCryptoCandleAlignment(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	crypto, err := calendar.New(calendar.MarketCrypto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypto.SetLocation(seoul)
+	schedule, err := ParseSchedule("@close", crypto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC) // Saturday 09:00 in Seoul
+
+	if got, want := schedule.Next(after), time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("exchange alignment: next run = %s, want midnight Seoul time (%s)", got, want)
+	}
+	if err := crypto.SetCandleClose(calendar.CandleCloseUTC); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := schedule.Next(after), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("UTC alignment: next run = %s, want midnight UTC (%s)", got, want)
+	}
+}
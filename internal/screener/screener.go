@@ -0,0 +1,142 @@
+// Package screener trims the stock universe with cheap bulk quotes before full candle analysis
+// Each screened stock costs a fraction of an API call, so large universes only pay for candles on liquid candidates
+package screener
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Ranking orders the stocks that pass the screen before the top N are kept
+const (
+	RankDollarVolume = "dollar-volume" // Price times average volume (most liquid first)
+	RankVolume       = "volume"        // Average volume
+	RankChange       = "change"        // Absolute percent change from the previous close (biggest movers first)
+)
+
+// QuoteSource fetches the latest quotes for many symbols at once
+type QuoteSource interface {
+	FetchQuotes(symbols []string) ([]models.Quote, error)
+}
+
+// Criteria are the screen thresholds; zero values disable a criterion
+type Criteria struct {
+	MinPrice     float64 // Minimum price
+	MaxPrice     float64 // Maximum price
+	MinAvgVolume int64   // Minimum average session volume
+	MinChange    float64 // Minimum absolute percent change from the previous close
+	Top          int     // Keep only the best N stocks by RankBy (0 keeps all that pass)
+	RankBy       string  // Ranking applied before trimming to Top
+}
+
+// Result describes the outcome of a screen
+type Result struct {
+	Stocks   []models.Stock // Stocks kept for full analysis, in rank order
+	Screened int            // Stocks that had a quote and were evaluated
+	Missing  int            // Stocks without a quote; they are kept so a data gap never hides a setup
+	Rejected int            // Stocks that failed a threshold
+	Trimmed  int            // Stocks that passed but fell outside the top N
+}
+
+// Screener filters and ranks stocks from bulk quotes and a rolling average volume
+type Screener struct {
+	source   QuoteSource    // Bulk quote provider
+	criteria Criteria       // Screen thresholds and ranking
+	volumes  *VolumeHistory // Rolling average volumes (nil uses the latest session volume)
+}
+
+// NewScreener creates a screener; volumes may be nil when no history file is configured
+func NewScreener(source QuoteSource, criteria Criteria, volumes *VolumeHistory) *Screener {
+	if criteria.RankBy == "" {
+		criteria.RankBy = RankDollarVolume
+	}
+	return &Screener{source: source, criteria: criteria, volumes: volumes}
+}
+
+// candidate is a quoted stock with the figures used for filtering and ranking
+type candidate struct {
+	stock     models.Stock // Stock from the universe
+	quote     models.Quote // Latest quote
+	avgVolume float64      // Rolling average volume
+}
+
+// Screen fetches quotes for the stocks and returns those worth a full analysis
+func (s *Screener) Screen(stocks []models.Stock) (Result, error) {
+	symbols := make([]string, len(stocks))
+	for i, stock := range stocks {
+		symbols[i] = strings.ToUpper(strings.TrimSpace(stock.Symbol))
+	}
+	quotes, err := s.source.FetchQuotes(symbols)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to screen stocks: %v", err)
+	}
+	bySymbol := make(map[string]models.Quote, len(quotes))
+	for _, quote := range quotes {
+		bySymbol[quote.Symbol] = quote
+	}
+
+	var result Result
+	var missing []models.Stock
+	var passed []candidate
+	for i, stock := range stocks {
+		quote, ok := bySymbol[symbols[i]]
+		if !ok {
+			missing = append(missing, stock)
+			continue
+		}
+		result.Screened++
+		avgVolume := float64(quote.Volume)
+		if s.volumes != nil {
+			avgVolume = s.volumes.Update(quote)
+		}
+		c := candidate{stock: stock, quote: quote, avgVolume: avgVolume}
+		if !s.passes(c) {
+			result.Rejected++
+			continue
+		}
+		passed = append(passed, c)
+	}
+
+	sort.SliceStable(passed, func(i, j int) bool { return s.rank(passed[i]) > s.rank(passed[j]) })
+	if s.criteria.Top > 0 && len(passed) > s.criteria.Top {
+		result.Trimmed = len(passed) - s.criteria.Top
+		passed = passed[:s.criteria.Top]
+	}
+
+	for _, c := range passed {
+		result.Stocks = append(result.Stocks, c.stock)
+	}
+	result.Missing = len(missing)
+	result.Stocks = append(result.Stocks, missing...)
+	return result, nil
+}
+
+// passes reports whether a candidate meets every threshold
+func (s *Screener) passes(c candidate) bool {
+	criteria := s.criteria
+	switch {
+	case criteria.MinPrice > 0 && c.quote.Price < criteria.MinPrice:
+		return false
+	case criteria.MaxPrice > 0 && c.quote.Price > criteria.MaxPrice:
+		return false
+	case criteria.MinAvgVolume > 0 && c.avgVolume < float64(criteria.MinAvgVolume):
+		return false
+	case criteria.MinChange > 0 && math.Abs(c.quote.ChangePercent) < criteria.MinChange:
+		return false
+	}
+	return true
+}
+
+// rank returns the sort key of a candidate for the configured ranking (higher ranks first)
+func (s *Screener) rank(c candidate) float64 {
+	switch s.criteria.RankBy {
+	case RankVolume:
+		return c.avgVolume
+	case RankChange:
+		return math.Abs(c.quote.ChangePercent)
+	}
+	return c.quote.Price * c.avgVolume
+}
@@ -0,0 +1,89 @@
+package screener
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/models"
+	"os"
+	"sync"
+	"time"
+)
+
+// averageSessions is the number of sessions the rolling average volume approximates
+const averageSessions = 20
+
+// volumeRecord is the rolling average volume of one symbol
+type volumeRecord struct {
+	Average  float64 `json:"average"`   // Rolling average session volume
+	Sessions int     `json:"sessions"`  // Sessions folded into the average (capped at averageSessions)
+	LastDate string  `json:"last_date"` // Session date of the last folded quote (YYYY-MM-DD)
+}
+
+// VolumeHistory keeps a rolling average volume per symbol built from successive bulk quotes
+// Bulk quotes only carry the current session's volume, so the average accumulates over runs
+type VolumeHistory struct {
+	records map[string]volumeRecord // Rolling averages keyed by symbol
+	mutex   sync.Mutex              // Mutex guarding records
+}
+
+// NewVolumeHistory creates an empty volume history
+func NewVolumeHistory() *VolumeHistory {
+	return &VolumeHistory{records: make(map[string]volumeRecord)}
+}
+
+// LoadVolumeHistory reads a volume history file; a missing file starts an empty history
+func LoadVolumeHistory(path string) (*VolumeHistory, error) {
+	history := NewVolumeHistory()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volume history: %v", err)
+	}
+	if err := json.Unmarshal(data, &history.records); err != nil {
+		return nil, fmt.Errorf("failed to parse volume history %s: %v", path, err)
+	}
+	return history, nil
+}
+
+// Update folds a quote's volume into the symbol's average once per session and returns the average
+// The first sessions are a plain mean; later ones move the average like a 20-session exponential average
+func (h *VolumeHistory) Update(quote models.Quote) float64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	quoteTime := quote.Time
+	if quoteTime.IsZero() {
+		quoteTime = time.Now()
+	}
+	date := quoteTime.Format("2006-01-02")
+
+	record := h.records[quote.Symbol]
+	if record.LastDate == date {
+		return record.Average // A second run in the same session must not count its volume twice
+	}
+	if record.Sessions < averageSessions {
+		record.Sessions++
+	}
+	record.Average += (float64(quote.Volume) - record.Average) / float64(record.Sessions)
+	record.LastDate = date
+	h.records[quote.Symbol] = record
+	return record.Average
+}
+
+// Save writes the volume history atomically
+func (h *VolumeHistory) Save(path string) error {
+	h.mutex.Lock()
+	data, err := json.MarshalIndent(h.records, "", "  ")
+	h.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode volume history: %v", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write volume history: %v", err)
+	}
+	return nil
+}
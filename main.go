@@ -3,61 +3,1001 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/internal/alert"
+	"github.com/erhankrygt/sapan/internal/calendar"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/correlation"
+	"github.com/erhankrygt/sapan/internal/distributed"
+	"github.com/erhankrygt/sapan/internal/execution"
+	"github.com/erhankrygt/sapan/internal/journal"
+	"github.com/erhankrygt/sapan/internal/mlscore"
+	"github.com/erhankrygt/sapan/internal/notify"
+	"github.com/erhankrygt/sapan/internal/outcome"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/internal/report"
+	"github.com/erhankrygt/sapan/internal/screener"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"github.com/erhankrygt/sapan/watcher"
+	"io"
 	"log"
-	"sapan/internal/config"
-	"sapan/internal/data"
-	"sapan/internal/processor"
-	"sapan/internal/strategy"
-	"sapan/internal/watcher"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
+	_ "time/tzdata" // Embed the timezone database so exchange and display zones resolve in minimal containers
+)
+
+// Process exit codes so shell automation can react to the outcome of a scan
+const (
+	exitOK            = 0 // Scan completed (no signals, or signal exit code disabled)
+	exitFailure       = 1 // Runtime failure such as an unreadable signal database
+	exitConfigError   = 2 // Invalid configuration, flags, stock lists, or filters
+	exitProviderError = 3 // Every symbol failed, which points at the data provider or API key
 )
 
 // main is the entry point of the SAPAN trading strategy application
-// This function initializes all components, loads stock data, and processes stocks concurrently
+// This function dispatches the subcommand and exits with a code describing its outcome
 func main() {
-	// Load configuration from environment variables
-	cfg, err := config.LoadConfig()
+	os.Exit(run(os.Args[1:]))
+}
+
+// command is one `sapan` subcommand; nested commands such as `config show` are listed by their full path
+type command struct {
+	path  []string                // Words naming the command (e.g. "watchlist", "export")
+	args  string                  // Positional arguments shown in the usage text
+	about string                  // One-line description shown in the usage text
+	run   func(args []string) int // Runs the command with the arguments after its path
+}
+
+// commands lists every subcommand in the order they are shown by `sapan help`
+var commands = []command{
+	{[]string{"scan"}, "[SYMBOL... | -] [flags]", "scan the stock list, the given symbols, or symbols from stdin once", runScan},
+	{[]string{"analyze"}, "SYMBOL [flags]", "print the rule-by-rule analysis of one symbol", runAnalyze},
+	{[]string{"backtest"}, "[flags]", "replay the strategy over historical candles", runBacktest},
+	{[]string{"replay"}, "[--from DATE] [--to DATE] [flags]", "replay the scanner day by day over archived candles", runReplay},
+	{[]string{"backscan"}, "[--horizons 5,10,20] [SYMBOL...] [flags]", "count past setups per symbol and their forward returns", runBackscan},
+	{[]string{"backfill"}, "[--from DATE] [SYMBOL...] [flags]", "download the full candle history into CANDLE_DIR", runBackfill},
+	{[]string{"adjust"}, "[SYMBOL...] [flags]", "re-adjust archived candles for new splits and dividends", runAdjust},
+	{[]string{"benchmark"}, "[BACKTEST_JSON] [flags]", "compare backtest or paper-traded results with buy-and-hold", runBenchmark},
+	{[]string{"daemon"}, "[flags]", "scan on the configured schedules until interrupted", runDaemon},
+	{[]string{"worker"}, "[flags]", "fetch candles for distributed scans from QUEUE_URL", runWorker},
+	{[]string{"serve"}, "[flags]", "serve the REST and gRPC APIs", runServer},
+	{[]string{"monitor"}, "[flags]", "notify when watch list prices cross an entry trigger or stop", runMonitor},
+	{[]string{"blacklist", "add"}, "SYMBOL... [--reason TEXT] [flags]", "exclude symbols from every scan", runBlacklistAdd},
+	{[]string{"blacklist", "remove"}, "SYMBOL... [flags]", "let blacklisted symbols be scanned again", runBlacklistRemove},
+	{[]string{"blacklist", "list"}, "[flags]", "print the blacklisted symbols", runBlacklistList},
+	{[]string{"watchlist", "export"}, "[--format csv|json] [FILE] [flags]", "export the persisted watch list", runWatchListExport},
+	{[]string{"compare"}, "--run A --run B [flags]", "diff the setups, scores, and settings of two stored runs", runCompare},
+	{[]string{"outcomes", "calibration"}, "[--from DATE] [flags]", "report hit rates by score, pattern, sector, and regime", runCalibration},
+	{[]string{"ml", "train"}, "[flags]", "train the signal scoring model on recorded outcomes", runTrainModel},
+	{[]string{"config", "show"}, "[flags]", "print every resolved setting with its source", showConfig},
+}
+
+// run dispatches the subcommand named by args and returns the exit code
+// A bare invocation, or one starting with a flag, runs a scan so existing cron jobs keep working
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runScan(args)
+	}
+	if args[0] == "help" {
+		printUsage(os.Stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if len(args) >= len(cmd.path) && slices.Equal(args[:len(cmd.path)], cmd.path) {
+			return cmd.run(args[len(cmd.path):])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "sapan: unknown command %q\n\n", strings.Join(args[:min(len(args), 2)], " "))
+	printUsage(os.Stderr)
+	return exitConfigError
+}
+
+// printUsage lists the subcommands; flags are shared by every command and listed by `sapan scan -h`
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: sapan <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", strings.Join(cmd.path, " "), cmd.args, cmd.about)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command accepts the configuration flags listed by `sapan scan -h`.")
+}
+
+// loadConfig resolves the configuration for a command from flags, environment, and the config file
+// When ok is false the command should return code right away (help was printed or the configuration is invalid)
+func loadConfig(args []string) (cfg *config.Config, code int, ok bool) {
+	cfg, err := config.LoadConfigFromArgs(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil, exitOK, false // Usage was printed by the flag parser
+	}
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		log.Printf("Failed to load configuration: %v", err)
+		return nil, exitConfigError, false
+	}
+	return cfg, exitOK, true
+}
+
+// runScan runs a single scan and returns its exit code
+// Leading SYMBOL arguments, or newline-separated symbols read from stdin when the argument is "-", replace the stock list
+func runScan(args []string) int {
+	var symbols []string
+	fromStdin := false
+	for len(args) > 0 && (args[0] == "-" || !strings.HasPrefix(args[0], "-")) {
+		if args[0] == "-" {
+			fromStdin = true
+		} else {
+			symbols = append(symbols, args[0])
+		}
+		args = args[1:]
+	}
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if fromStdin {
+		stdinSymbols, err := data.ReadSymbols(os.Stdin)
+		if err != nil {
+			log.Printf("Failed to read symbols from stdin: %v", err)
+			return exitConfigError
+		}
+		symbols = append(symbols, stdinSymbols...)
+		if len(symbols) == 0 {
+			log.Printf("No symbols were given on stdin")
+			return exitConfigError
+		}
+	}
+
+	// Scan every configured market in turn unless ad-hoc symbols narrow the run to one list
+	if len(cfg.Markets) > 0 && len(symbols) == 0 {
+		code = runMarkets(cfg, args)
+	} else {
+		_, _, code = scan(cfg, symbols, scanHooks{})
+	}
+	return code
+}
+
+// scanHooks lets long-running modes observe a scan; nil hooks are skipped
+type scanHooks struct {
+	started func(*processor.StockProcessor) // Receives the processor once processing begins
+	signal  func(watcher.WatchListEntry)    // Receives every setup as soon as it is detected
+}
+
+// scan runs one complete scan with the given configuration and returns its summary, result document, and exit code
+// Ad-hoc symbols replace the stock list files, filters, and screen; nil scans the configured stock list
+func scan(cfg *config.Config, symbols []string, hooks scanHooks) (processor.ProcessingSummary, *report.RunResult, int) {
+	// Informational output is suppressed in quiet and signals-only modes; warnings are always logged
+	logInfo := func(format string, args ...interface{}) {
+		if cfg.OutputMode.ShowsProgress() {
+			log.Printf(format, args...)
+		}
+	}
+
+	// Skip the scan entirely when the market holds no session today
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+	if cfg.SkipClosedDays && !marketCalendar.IsTradingDay(time.Now()) {
+		logInfo("📅 The %s market is closed today; next session on %s", cfg.Market,
+			marketCalendar.NextTradingDay(time.Now()).Format("2006-01-02"))
+		return processor.ProcessingSummary{}, nil, exitOK
 	}
 
 	// Initialize all required components using dependency injection
-	stockFetcher := data.NewStockDataFetcher(cfg.APIKey, cfg.APIURL) // Initialize data fetcher with API key and URL
-	stockLoader := data.NewStockListLoader()                         // Initialize stock list loader
-	watchListManager := watcher.NewWatchListManager()                // Initialize watch list manager
-	sapanStrategy := strategy.NewSAPANStrategy()                     // Initialize SAPAN strategy
+	stockFetcher := newProviderFetcher(cfg)           // Initialize data fetcher of the configured provider
+	stockLoader := data.NewStockListLoader()          // Initialize stock list loader
+	watchListManager := watcher.NewWatchListManager() // Initialize watch list manager
+	watchListManager.SetDisplayLocation(cfg.DisplayLocation)
+	sapanStrategy := newStrategy(cfg) // Initialize SAPAN strategy with the configured rules
+
+	// The indicators need a minimum history; fewer candles would mark every stock as insufficient data
+	if !resolveOutputSize(cfg, sapanStrategy, stockFetcher.Capabilities()) {
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+
+	// Fail before fetching anything when the provider cannot serve the requested timeframe or history
+	if err := stockFetcher.Capabilities().Check(cfg.Timeframe, cfg.OutputSize); err != nil {
+		log.Printf("Unsupported provider settings: %v", err)
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+
+	// Count every API request against today's quota so the scan stops before the provider starts refusing requests
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+	defer saveQuota(quota)
+	if quota.Limit() > 0 {
+		logInfo("🔑 API quota: %d of %d %s requests left today", quota.Remaining(), quota.Limit(), cfg.Provider)
+	}
+
+	// Open the optional signal database so every signal is recorded with full metadata
+	var signalStore *watcher.SQLiteSignalStore
+	if cfg.SignalDBPath != "" {
+		signalStore, err = openSignalStore(cfg)
+		if err != nil {
+			log.Printf("Failed to open signal database: %v", err)
+			return processor.ProcessingSummary{}, nil, exitFailure
+		}
+		defer signalStore.Close()
+		watchListManager.SetSignalStore(signalStore)
+	}
+
+	// Restore the watch list persisted by previous runs and keep a copy to diff against
+	if err := watchListManager.Load(cfg.WatchListFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("⚠️  Could not restore watch list from %s: %v", cfg.WatchListFile, err)
+	}
+	previousWatchList := watchListManager.Clone()
+
+	// Announce detected setups; re-detections of listed setups only when configured
+	watchListManager.Subscribe(func(event watcher.WatchListEvent) {
+		if !cfg.OutputMode.ShowsSignals() {
+			return
+		}
+		if event.Type == watcher.EntryAdded || (event.Type == watcher.EntryUpdated && cfg.NotifyExisting) {
+			if cfg.OutputMode == output.SignalsOnly {
+				// One tab-separated line per signal so the output can be piped into other tools
+				fmt.Printf("%s\t%s\t%s\t%.1f\n", event.Entry.Side, event.Entry.Symbol, event.Entry.Pattern, event.Entry.Score)
+				return
+			}
+			fmt.Printf("✅ SAPAN %s Setup detected for %s\n", event.Entry.Side, event.Entry.Symbol)
+		}
+	})
+
+	// Stream setups to API subscribers as they are detected
+	if hooks.signal != nil {
+		watchListManager.Subscribe(func(event watcher.WatchListEvent) {
+			if event.Type == watcher.EntryAdded || event.Type == watcher.EntryUpdated {
+				hooks.signal(event.Entry)
+			}
+		})
+	}
+
+	// Fan new setups and the run summary out to the configured notifiers
+	dispatcher, err := newDispatcher(cfg, logInfo)
+	if err != nil {
+		log.Printf("Failed to load alert rules: %v", err)
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+	if dispatcher.Count() > 0 {
+		watchListManager.Subscribe(dispatcher.HandleWatchListEvent)
+		dispatcher.Start()
+	}
+
+	var stockData models.StockData
+	if len(symbols) > 0 {
+		// Scan exactly the symbols asked for; the stock list files, filters, and screen do not apply
+		stockData = data.StocksFromSymbols(symbols)
+	} else {
+		// Load stock list
+		logInfo("📈 Loading stock list...")
+		if stockData, err = stockLoader.LoadStocksFromPatterns(cfg.StocksFile); err != nil {
+			log.Println("Failed to load stocks:", err)
+			return processor.ProcessingSummary{}, nil, exitConfigError
+		}
 
-	// Load stock list
-	log.Println("📈 Loading stock list...")
-	stockData, err := stockLoader.LoadStocksFromFile(cfg.StocksFile)
+		// Narrow the list with the configured sector and symbol filters
+		stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+		if err != nil {
+			log.Println("Failed to build stock filter:", err)
+			return processor.ProcessingSummary{}, nil, exitConfigError
+		}
+		loadedCount := len(stockData.Stocks)
+		stockData = stockFilter.Apply(stockData)
+		if skipped := loadedCount - len(stockData.Stocks); skipped > 0 {
+			logInfo("🔎 Filters skipped %d of %d stocks", skipped, loadedCount)
+		}
+	}
+
+	// Drop blacklisted symbols, including ad-hoc ones, so dead tickers never cost an API call
+	blacklist, err := data.LoadBlacklist(cfg.BlacklistFile)
 	if err != nil {
-		log.Fatal("Failed to load stocks:", err)
+		log.Printf("Failed to load blacklist: %v", err)
+		return processor.ProcessingSummary{}, nil, exitConfigError
+	}
+	listedCount := len(stockData.Stocks)
+	stockData = blacklist.Apply(stockData)
+	if skipped := listedCount - len(stockData.Stocks); skipped > 0 {
+		logInfo("🚫 Blacklist skipped %d of %d stocks", skipped, listedCount)
 	}
 
-	log.Printf("📊 Loaded %d stocks for analysis", len(stockData.Stocks))
+	if len(symbols) == 0 {
+		// Cut the list down for quick runs before the screen spends any quote calls on it
+		stockData = subsetStocks(cfg, stockData, logInfo)
+
+		// Trim the universe with bulk quotes so candles are only fetched for liquid candidates
+		if cfg.ScreenEnabled && len(stockData.Stocks) > 0 {
+			if quoteSource, ok := stockFetcher.(screener.QuoteSource); ok {
+				stockData.Stocks = screenStocks(cfg, quoteSource, stockData.Stocks, logInfo)
+			} else {
+				log.Printf("⚠️  The screen needs bulk quotes, which the %s provider does not offer; scanning every stock", cfg.Provider)
+			}
+		}
+	}
+
+	logInfo("📊 Loaded %d stocks for analysis", len(stockData.Stocks))
+
+	// Fetch through the distributed workers when a queue is configured; they pace their own API keys
+	var candleSource data.Fetcher = stockFetcher
+	workerCount, requestDelay := cfg.GetOptimalWorkerCount(), cfg.RequestDelay
+	if cfg.QueueURL != "" {
+		queue, err := distributed.NewQueue(cfg.QueueURL, cfg.QueuePrefix)
+		if err != nil {
+			log.Printf("Failed to open distributed scan queue: %v", err)
+			return processor.ProcessingSummary{}, nil, exitConfigError
+		}
+		defer queue.Close()
+		candleSource = distributed.NewCoordinator(queue, cfg.Timeframe, cfg.QueueTimeout)
+		workerCount, requestDelay = cfg.QueueInFlight, 0
+		logInfo("🛰️  Distributing candle fetches to workers on queue %s", cfg.QueuePrefix)
+	}
 
 	// Create concurrent processor
 	stockProcessor := processor.NewStockProcessor(
-		stockFetcher,
+		candleSource,
 		sapanStrategy,
 		watchListManager,
-		cfg.GetOptimalWorkerCount(),
-		cfg.RequestDelay,
+		workerCount,
+		requestDelay,
+		cfg.OutputSize,
 	)
+	stockProcessor.SetOutputMode(cfg.OutputMode)
+	stockProcessor.SetQuota(quota)
+	stockProcessor.SetChartLinks(cfg.Provider, cfg.Timeframe)
+	if cfg.Explain {
+		stockProcessor.SetExplain(cfg.ExplainSymbols)
+	}
+	stockProcessor.SetBothSides(cfg.BothSides)
+	stockProcessor.SetFetchTimeout(cfg.FetchTimeout)
+	if cfg.CandleDir != "" {
+		stockProcessor.SetCandleStore(data.NewCandleStore(cfg.CandleDir, cfg.Timeframe))
+	}
+	if !data.IsIntradayTimeframe(cfg.Timeframe) {
+		stockProcessor.SetMarketCalendar(marketCalendar)
+	}
+	if cfg.CandleChecks != "off" {
+		checks := data.CandleChecks{Window: cfg.CandleCheckWindow, MaxGap: cfg.CandleMaxGap}
+		if cfg.Timeframe == "daily" {
+			checks.Calendar = marketCalendar // Missing sessions only make sense for one candle per trading day
+		}
+		stockProcessor.SetCandleChecks(checks, cfg.CandleChecks == "skip")
+	}
+	var enricher *data.StockEnricher
+	if alphaVantage, ok := stockFetcher.(*data.StockDataFetcher); cfg.EnrichMetadata && !ok {
+		log.Printf("⚠️  Stock metadata enrichment needs Alpha Vantage profiles and is skipped for the %s provider", cfg.Provider)
+	} else if cfg.EnrichMetadata {
+		if enricher, err = data.NewStockEnricher(alphaVantage, cfg.ProfileCacheFile, cfg.RequestDelay); err != nil {
+			log.Printf("⚠️  Stock metadata enrichment disabled: %v", err)
+		} else {
+			stockProcessor.SetStockEnricher(enricher)
+		}
+	}
+	var scorer *mlscore.Scorer
+	if cfg.MLScoring {
+		scorer = newScorer(cfg, signalStore)
+		stockProcessor.SetSignalScorer(scorer)
+	}
 
 	// Process stocks concurrently
-	log.Printf("🚀 Starting concurrent processing with %d workers...", cfg.GetOptimalWorkerCount())
+	logInfo("🚀 Starting concurrent processing with %d workers...", workerCount)
 	startTime := time.Now()
 
-	stockProcessor.ProcessStocksConcurrently(stockData.Stocks)
+	var runID int64
+	if signalStore != nil {
+		if runID, err = signalStore.StartRun(startTime, runSettings(cfg)); err != nil {
+			log.Printf("⚠️  Could not record run start: %v", err)
+		}
+		watchListManager.SetRunID(runID)
+	}
+
+	if hooks.started != nil {
+		hooks.started(stockProcessor)
+	}
+	summary := stockProcessor.ProcessStocksConcurrently(stockData.Stocks)
+	recordRejections(cfg, blacklist, summary)
+	if enricher != nil {
+		if err := enricher.Save(); err != nil {
+			log.Printf("⚠️  Could not save stock profile cache: %v", err)
+		}
+	}
+	if scorer != nil {
+		// The next scan scores its signals against the sector strength observed in this one
+		if err := signalStore.SaveSectorStrength(scorer.SectorStrength(), time.Now()); err != nil {
+			log.Printf("⚠️  Could not save sector strength: %v", err)
+		}
+	}
+
+	if cfg.CorrelationThreshold > 0 {
+		filterCorrelatedSignals(cfg, summary, watchListManager, logInfo)
+	}
+	if cfg.CrossCheckProvider != "" {
+		crossCheckSignals(cfg, summary, watchListManager, marketCalendar, logInfo)
+	}
 
 	processingTime := time.Since(startTime)
-	log.Printf("⏱️  Total processing time: %v", processingTime)
+	logInfo("⏱️  Total processing time: %v", processingTime)
 
-	// Print final results
-	log.Println("\n🎯 Final Results:")
-	watchListManager.PrintWatchList()
+	palette := output.NewPalette(output.ColorEnabled(cfg.Color))
+	if cfg.OutputMode.ShowsSummary() {
+		fmt.Println()
+		summary.Print(os.Stdout, palette, processingTime)
+	}
+
+	// Store the run summary alongside its signals for trend reporting
+	if signalStore != nil && runID != 0 {
+		err := signalStore.FinishRun(watcher.RunSummary{
+			ID:          runID,
+			StartedAt:   startTime,
+			FinishedAt:  time.Now(),
+			Total:       summary.Total,
+			Successful:  summary.Successful,
+			Errors:      summary.Errors,
+			Valid:       summary.Valid,
+			LongCount:   summary.LongCount,
+			ShortCount:  summary.ShortCount,
+			DurationSec: processingTime.Seconds(),
+		})
+		if err != nil {
+			log.Printf("⚠️  Could not record run summary: %v", err)
+		}
+	}
+
+	// Report changes against the previous run and drop setups that were analyzed again but not detected
+	watchListDiff := watcher.Diff(previousWatchList, watchListManager, startTime)
+	watchListDiff.Disappeared = keepAnalyzed(watchListDiff.Disappeared, summary.Results)
+	for _, entry := range watchListDiff.Disappeared {
+		watchListManager.Remove(entry.Symbol, entry.Side)
+	}
+	if cfg.OutputMode.ShowsProgress() {
+		log.Println("\n🔁 Watch List Diff:")
+		watchListDiff.Print()
+
+		// Print final results
+		log.Println("\n🎯 Final Results:")
+		entries := append(watchListManager.GetEntriesBySide(watcher.LongSide), watchListManager.GetEntriesBySide(watcher.ShortSide)...)
+		output.PrintWatchList(os.Stdout, palette, entries, cfg.DisplayLocation)
+		fmt.Println()
+		watchListManager.PrintGroupedWatchList()
+
+		// Highlight only the highest-quality setups when the scan produced many hits
+		if cfg.TopSignals > 0 && watchListManager.GetCount() > 0 {
+			fmt.Printf("\nTop %d Setups by %s:\n", cfg.TopSignals, cfg.TopSignalsBy)
+			for i, entry := range watchListManager.TopN(cfg.TopSignals, cfg.TopSignalsBy) {
+				fmt.Printf("  %d. %-6s %-5s score %.1f | R:R %.1f | volume %d\n",
+					i+1, entry.Symbol, entry.Side, entry.Score, entry.RiskReward(), entry.Volume)
+			}
+		}
+	}
+
+	// Persist the watch list so signals survive across runs
+	if err := watchListManager.Save(cfg.WatchListFile); err != nil {
+		log.Printf("⚠️  Could not save watch list to %s: %v", cfg.WatchListFile, err)
+	}
+
+	// Write the full run result for downstream automation and the HTML report when requested
+	runResult := report.BuildRunResult(startTime, time.Now(), summary, watchListManager, watchListDiff)
+	if cfg.ResultsFile != "" {
+		if err := report.WriteJSON(cfg.ResultsFile, runResult); err != nil {
+			log.Printf("⚠️  Could not write run result to %s: %v", cfg.ResultsFile, err)
+		}
+	}
+	if cfg.ReportsDir != "" {
+		if path, err := report.WriteHTML(cfg.ReportsDir, runResult, cfg.DisplayLocation); err != nil {
+			log.Printf("⚠️  Could not write HTML report: %v", err)
+		} else {
+			logInfo("📄 HTML report written to %s", path)
+		}
+	}
 
-	log.Println("\n✅ SAPAN Strategy analysis completed!")
-	time.Sleep(time.Minute * 1)
+	// Export the watch list for spreadsheets and trading journals when requested
+	if cfg.WatchListCSVFile != "" {
+		if err := watchListManager.ExportCSV(cfg.WatchListCSVFile); err != nil {
+			log.Printf("⚠️  Could not export watch list to %s: %v", cfg.WatchListCSVFile, err)
+		}
+	}
+
+	// Submit bracket orders for new setups when execution is explicitly enabled
+	var executions []execution.Execution
+	if cfg.ExecutionEnabled {
+		timeStops, err := execution.OpenTimeStopBook(cfg.TimeStopFile)
+		if err != nil {
+			log.Printf("⚠️  Could not load time stops, new orders are tracked from now on: %v", err)
+			timeStops, _ = execution.OpenTimeStopBook("")
+		}
+		executions = executeSignals(cfg, runNewSignals(watchListDiff, watchListManager), stockData.Stocks, timeStops)
+		expireTimeStops(cfg, timeStops, marketCalendar)
+		if err := timeStops.Save(); err != nil {
+			log.Printf("⚠️  Could not save time stops to %s: %v", cfg.TimeStopFile, err)
+		}
+	}
+
+	// Append the signals taken this run to the trade journal
+	if cfg.JournalFile != "" {
+		journalSignals(cfg, runNewSignals(watchListDiff, watchListManager), executions, logInfo)
+	}
+
+	// Deliver buffered signals and the run summary to every notifier, then wait for delivery
+	runReport := buildRunReport(startTime, summary, watchListDiff, watchListManager, stockFetcher.RequestCount(), cfg.APIDailyQuota)
+	if dispatcher.Count() > 0 {
+		dispatcher.Flush()
+		dispatcher.Publish(notify.Event{Type: notify.RunEvent, Run: &runReport, Time: time.Now().UTC()})
+		dispatcher.Close()
+		logInfo("📣 Notified %d channels", dispatcher.Count())
+	}
+
+	// Record outcomes of past signals whose tracking window has elapsed
+	if signalStore != nil && cfg.OutcomeTrackingDays > 0 {
+		tracker := outcome.NewTracker(signalStore, stockFetcher, cfg.OutcomeTrackingDays, cfg.OutputSize, cfg.RequestDelay)
+		recorded, err := tracker.Run(time.Now())
+		if err != nil {
+			log.Printf("⚠️  Outcome tracking failed: %v", err)
+		} else {
+			logInfo("📐 Recorded %d signal outcomes", recorded)
+		}
+
+		outcomes, err := signalStore.QueryOutcomes(watcher.SignalQuery{})
+		if err != nil {
+			log.Printf("⚠️  Could not load signal outcomes: %v", err)
+		} else if len(outcomes) > 0 && cfg.OutputMode.ShowsProgress() {
+			outcome.BuildReport(outcomes).Print()
+		}
+	}
+
+	// Show how signal counts and error rates developed over the last weeks
+	if signalStore != nil && cfg.OutputMode.ShowsProgress() {
+		weeks, err := signalStore.WeeklyRunStats(time.Now().AddDate(0, 0, -8*7))
+		if err != nil {
+			log.Printf("⚠️  Could not load run history: %v", err)
+		} else {
+			watcher.PrintWeeklyRunStats(weeks)
+		}
+	}
+
+	logInfo("\n✅ SAPAN Strategy analysis completed!")
+
+	// A scan where nothing succeeded is a provider failure, not an empty result
+	if summary.Total == 0 && summary.Skipped > 0 {
+		log.Printf("❌ The API quota was used up before any of the %d symbols was scanned", summary.Skipped)
+		return summary, &runResult, exitProviderError
+	}
+	if summary.Total > 0 && summary.Successful == 0 {
+		log.Printf("❌ All %d symbols failed; check the data provider and API key", summary.Total)
+		return summary, &runResult, exitProviderError
+	}
+	if cfg.SignalExitCode != 0 && summary.Valid > 0 {
+		return summary, &runResult, cfg.SignalExitCode
+	}
+	return summary, &runResult, exitOK
+}
+
+// newMarketCalendar builds the configured market calendar with its timezone override and candle close alignment
+func newMarketCalendar(cfg *config.Config) (*calendar.Calendar, error) {
+	marketCalendar, err := calendar.New(cfg.Market, cfg.MarketHolidays)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MarketLocation != nil {
+		marketCalendar.SetLocation(cfg.MarketLocation)
+	}
+	if err := marketCalendar.SetCandleClose(cfg.CandleClose); err != nil {
+		return nil, err
+	}
+	return marketCalendar, nil
+}
+
+// newStrategy builds the SAPAN strategy with the configured patterns, support and resistance EMA, and confirmation rules
+func newStrategy(cfg *config.Config) *strategy.SAPANStrategy {
+	sapanStrategy := strategy.NewSAPANStrategy()
+	for name, patterns := range map[string][2]strategy.PatternType{
+		"2-candlestick": {strategy.Long2CandlestickReversal, strategy.Short2CandlestickReversal},
+		"pinbar":        {strategy.LongPinbarReversal, strategy.ShortPinbarReversal},
+	} {
+		sapanStrategy.SetPatternEnabled(patterns[0], slices.Contains(cfg.LongPatterns, name))
+		sapanStrategy.SetPatternEnabled(patterns[1], slices.Contains(cfg.ShortPatterns, name))
+	}
+	if cfg.EMAReference == "nearest" {
+		sapanStrategy.SetEMAReference(strategy.NearestEMA)
+	}
+	sapanStrategy.SetEMAPeriods(cfg.EMAPeriods)
+	sapanStrategy.SetTimeStop(cfg.TimeStopBars)
+	sapanStrategy.SetMaxMACDRun(cfg.MACDMaxRun)
+	sapanStrategy.SetStochasticRSIParams(indicators.StochasticRSIParams{
+		RSIPeriod:   cfg.StochRSILengths[0],
+		StochPeriod: cfg.StochRSILengths[1],
+		KSmoothing:  cfg.StochRSILengths[2],
+		DSmoothing:  cfg.StochRSILengths[3],
+		TradingView: cfg.StochRSIMode == "tradingview",
+	})
+	sapanStrategy.SetStochRSIThresholds(strategy.StochRSIThresholds{
+		Oversold:      cfg.StochRSIOversold,
+		Overbought:    cfg.StochRSIOverbought,
+		LongLookback:  cfg.StochRSILongLookback,
+		ShortLookback: cfg.StochRSIShortLookback,
+	})
+	orderBlocks := strategy.DefaultOrderBlockRules()
+	orderBlocks.Lookback, orderBlocks.ImpulseATR = cfg.OrderBlockLookback, cfg.OrderBlockImpulseATR
+	orderBlocks.RequireConfluence = cfg.RequireZoneConfluence
+	sapanStrategy.SetOrderBlockRules(orderBlocks)
+	sapanStrategy.SetConfirmationRules(strategy.ConfirmationRules{
+		ClosePercent:      cfg.ConfirmationClosePercent,
+		RequireRisingLows: cfg.ConfirmationRisingLows,
+		MaxRangeATR:       cfg.ConfirmationMaxRangeATR,
+	})
+	sapanStrategy.SetPatternThresholds(strategy.PatternThresholds{
+		BodyRatio: cfg.PinbarBodyRatio,
+		WickRatio: cfg.PinbarWickRatio,
+		BodyATR:   cfg.PinbarBodyATR,
+		WickATR:   cfg.PinbarWickATR,
+		PierceATR: cfg.PierceDepthATR,
+	})
+	return sapanStrategy
+}
+
+// resolveOutputSize replaces an automatic OUTPUT_SIZE with the candles the strategy's indicators need to warm up,
+// capped at the provider's history limit, and warns when a history is too short for the indicators to settle
+// or has to be paged over several requests. It reports false when the size leaves fewer candles than the strategy's
+// EMA stack and indicators need to validate setups on
+func resolveOutputSize(cfg *config.Config, sapanStrategy *strategy.SAPANStrategy, capabilities data.Capabilities) bool {
+	required, minimum := sapanStrategy.RequiredCandles(), sapanStrategy.MinimumCandles()
+	if cfg.OutputSize == 0 {
+		cfg.OutputSize = required
+		if capabilities.MaxHistory > 0 && cfg.OutputSize > capabilities.MaxHistory {
+			log.Printf("⚠️  Provider %s serves at most %d candles; the indicators warm up over %d", capabilities.Provider, capabilities.MaxHistory, required)
+			cfg.OutputSize = capabilities.MaxHistory
+		}
+	} else if cfg.OutputSize < required && cfg.OutputSize >= minimum {
+		log.Printf("⚠️  OUTPUT_SIZE %d is below the %d candles the indicators warm up over; early values may differ from charting platforms",
+			cfg.OutputSize, required)
+	}
+	if cfg.OutputSize < minimum {
+		log.Printf("OUTPUT_SIZE must be at least %d candles for the SAPAN indicators (got %d)", minimum, cfg.OutputSize)
+		return false
+	}
+	if capabilities.PageSize > 0 && cfg.OutputSize > capabilities.PageSize {
+		pages := (cfg.OutputSize + capabilities.PageSize - 1) / capabilities.PageSize
+		log.Printf("📄 Provider %s serves %d candles per request; each symbol takes up to %d requests", capabilities.Provider, capabilities.PageSize, pages)
+	}
+	return true
+}
+
+// fetchContext returns the context one symbol's candle fetch runs in, bounded by FETCH_TIMEOUT_SECONDS
+func fetchContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	if cfg.FetchTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), cfg.FetchTimeout)
+}
+
+// subsetStocks applies --limit or --sample so a config change can be tried on a slice of the universe
+// A random sample logs its seed, so the same stocks can be scanned again with --seed
+func subsetStocks(cfg *config.Config, stockData models.StockData, logInfo func(string, ...interface{})) models.StockData {
+	listedCount := len(stockData.Stocks)
+	switch {
+	case cfg.Limit > 0 && cfg.Limit < listedCount:
+		logInfo("✂️  Limited the scan to the first %d of %d stocks", cfg.Limit, listedCount)
+		return data.LimitStocks(stockData, cfg.Limit)
+	case cfg.Sample > 0 && cfg.Sample < listedCount:
+		seed := cfg.SampleSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		logInfo("🎲 Sampled %d of %d stocks (--seed %d repeats this sample)", cfg.Sample, listedCount, seed)
+		return data.SampleStocks(stockData, cfg.Sample, seed)
+	}
+	return stockData
+}
+
+// screenStocks runs the bulk-quote screen and returns the stocks worth a full analysis
+// A failed screen is logged and the full universe is analyzed, so a quote outage never skips a scan
+func screenStocks(cfg *config.Config, source screener.QuoteSource, stocks []models.Stock, logInfo func(string, ...interface{})) []models.Stock {
+	volumes, err := screener.LoadVolumeHistory(cfg.ScreenVolumeFile)
+	if err != nil {
+		log.Printf("⚠️  Could not load screen volume history, averages restart: %v", err)
+		volumes = screener.NewVolumeHistory()
+	}
+	criteria := screener.Criteria{
+		MinPrice:     cfg.ScreenMinPrice,
+		MaxPrice:     cfg.ScreenMaxPrice,
+		MinAvgVolume: cfg.ScreenMinAvgVolume,
+		MinChange:    cfg.ScreenMinChange,
+		Top:          cfg.ScreenTop,
+		RankBy:       cfg.ScreenRankBy,
+	}
+	result, err := screener.NewScreener(source, criteria, volumes).Screen(stocks)
+	if err != nil {
+		log.Printf("⚠️  Screener failed, analyzing all %d stocks: %v", len(stocks), err)
+		return stocks
+	}
+	if err := volumes.Save(cfg.ScreenVolumeFile); err != nil {
+		log.Printf("⚠️  Could not save screen volume history: %v", err)
+	}
+
+	logInfo("🧮 Screener kept %d of %d stocks (%d rejected, %d trimmed, %d without a quote)",
+		len(result.Stocks), len(stocks), result.Rejected, result.Trimmed, result.Missing)
+	return result.Stocks
+}
+
+// newScorer loads the scoring model and the previous run's sector strength
+// Without a trained model the scorer still records features, so training data accumulates from the first scan
+func newScorer(cfg *config.Config, signalStore *watcher.SQLiteSignalStore) *mlscore.Scorer {
+	var model *mlscore.Model
+	if _, err := os.Stat(cfg.MLModelFile); errors.Is(err, os.ErrNotExist) {
+		log.Printf("⚠️  No signal model at %s yet; recording features only (train with `sapan ml train`)", cfg.MLModelFile)
+	} else if model, err = mlscore.LoadModel(cfg.MLModelFile); err != nil {
+		log.Printf("⚠️  Could not load signal model, recording features only: %v", err)
+	}
+	sectorStrength, err := signalStore.SectorStrength()
+	if err != nil {
+		log.Printf("⚠️  Could not load sector strength: %v", err)
+	}
+	return mlscore.NewScorer(model, sectorStrength)
+}
+
+// filterCorrelatedSignals flags or removes setups of this run that move with a stronger setup on the same side
+func filterCorrelatedSignals(cfg *config.Config, summary processor.ProcessingSummary, watchListManager *watcher.WatchListManager,
+	logInfo func(string, ...interface{})) {
+	var candidates []correlation.Candidate
+	for _, result := range summary.Results {
+		switch {
+		case result.IsLongValid:
+			candidates = append(candidates, correlation.Candidate{Symbol: result.Symbol, Side: watcher.LongSide, Score: result.LongResult.Score, Closes: result.Closes})
+		case result.IsShortValid:
+			candidates = append(candidates, correlation.Candidate{Symbol: result.Symbol, Side: watcher.ShortSide, Score: result.ShortResult.Score, Closes: result.Closes})
+		}
+	}
+
+	_, flagged := correlation.Screen(candidates, cfg.CorrelationLookback, cfg.CorrelationThreshold)
+	for _, match := range flagged {
+		if cfg.CorrelationMode == "trim" {
+			watchListManager.Remove(match.Symbol, match.Side)
+			logInfo("🔗 Trimmed %s %s: returns correlate %.2f with %s", match.Side, match.Symbol, match.Correlation, match.CorrelatedWith)
+			continue
+		}
+		watchListManager.Annotate(match.Symbol, match.Side, func(entry *watcher.WatchListEntry) {
+			entry.CorrelatedWith = match.CorrelatedWith
+			entry.Correlation = match.Correlation
+		})
+		logInfo("🔗 %s %s correlates %.2f with %s", match.Side, match.Symbol, match.Correlation, match.CorrelatedWith)
+	}
+}
+
+// buildRunReport collects the run summary, new setups, and API usage for end-of-run notifications
+func buildRunReport(startTime time.Time, summary processor.ProcessingSummary, diff watcher.WatchListDiff,
+	watchListManager *watcher.WatchListManager, apiRequests, apiQuota int) notify.RunReport {
+	runReport := notify.RunReport{
+		StartedAt:   startTime,
+		FinishedAt:  time.Now(),
+		Total:       summary.Total,
+		Successful:  summary.Successful,
+		Errors:      summary.Errors,
+		Valid:       summary.Valid,
+		LongCount:   summary.LongCount,
+		ShortCount:  summary.ShortCount,
+		APIRequests: apiRequests,
+		APIQuota:    apiQuota,
+	}
+	runReport.NewSignals = runNewSignals(diff, watchListManager)
+	for _, failure := range summary.Failures {
+		runReport.Failures = append(runReport.Failures, notify.RunFailure{Symbol: failure.Symbol, Error: failure.Error})
+	}
+	return runReport
+}
+
+// keepAnalyzed keeps the disappeared setups of symbols that were successfully analyzed this run
+// A stock that failed to fetch or process, was skipped because the quota ran out, or was left out of an ad-hoc
+// symbol list, --limit, or --sample says nothing about its setup, so a narrowed run never empties the watch list
+func keepAnalyzed(disappeared []watcher.DiffEntry, results []processor.ProcessingResult) []watcher.DiffEntry {
+	analyzed := make(map[string]bool, len(results))
+	for _, result := range results {
+		if result.Success {
+			analyzed[result.Symbol] = true
+		}
+	}
+	kept := disappeared[:0]
+	for _, entry := range disappeared {
+		if analyzed[entry.Symbol] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// runNewSignals returns the watch list entries of setups that first appeared in this run
+func runNewSignals(diff watcher.WatchListDiff, watchListManager *watcher.WatchListManager) []watcher.WatchListEntry {
+	var entries []watcher.WatchListEntry
+	for _, item := range diff.New {
+		if entry, ok := watchListManager.GetEntry(item.Symbol, item.Side); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// executeSignals submits risk-sized bracket orders for new setups and logs the outcome of each
+// The highest-scoring setups go first so portfolio limits turn away the weaker ones
+func executeSignals(cfg *config.Config, entries []watcher.WatchListEntry, stocks []models.Stock,
+	timeStops *execution.TimeStopBook) []execution.Execution {
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+
+	broker, err := newBroker(cfg)
+	if err != nil {
+		log.Printf("⚠️  Could not open broker: %v", err)
+		return nil
+	}
+	executor := execution.NewExecutor(broker, execution.RiskLimits{
+		RiskPercent:      cfg.ExecutionRiskPercent,
+		MaxPositionValue: cfg.ExecutionMaxPositionValue,
+		MinScore:         float64(cfg.ExecutionMinScore),
+	})
+	sectors := make(map[string]string, len(stocks))
+	for _, stock := range stocks {
+		sectors[stock.Symbol] = stock.Sector
+	}
+	executor.SetPortfolioLimits(execution.PortfolioLimits{
+		MaxPositions:       cfg.MaxPositions,
+		MaxOpenRiskPercent: cfg.MaxOpenRiskPercent,
+		MaxSectorPercent:   cfg.MaxSectorPercent,
+	}, sectors)
+	executor.SetTimeStopBook(timeStops)
+
+	executions, err := executor.Execute(entries)
+	if err != nil {
+		log.Printf("⚠️  Order execution failed: %v", err)
+		return nil
+	}
+	for _, result := range executions {
+		if result.OrderID == "" {
+			log.Printf("⏭️  %s %s not traded: %s", result.Side, result.Symbol, result.Skipped)
+			continue
+		}
+		log.Printf("💼 Submitted %s bracket order for %d %s (%s, order %s)", result.Side, result.Quantity, result.Symbol, broker.Name(), result.OrderID)
+	}
+	return executions
+}
+
+// expireTimeStops closes the positions whose time stop elapsed without reaching the stop or target
+// Broker bracket orders have no time stop, so every run counts the candles closed since each fill on the market calendar
+// for the orders recorded in timeStops
+func expireTimeStops(cfg *config.Config, timeStops *execution.TimeStopBook, marketCalendar *calendar.Calendar) {
+	broker, err := newBroker(cfg)
+	if err != nil {
+		log.Printf("⚠️  Could not open broker: %v", err)
+		return
+	}
+	now := time.Now()
+	executor := execution.NewExecutor(broker, execution.RiskLimits{})
+	executor.SetTimeStopBook(timeStops)
+	closed, err := executor.ExpireTimeStops(func(since time.Time) int {
+		return marketCalendar.CandlesClosedAfter(cfg.Timeframe, since, now)
+	})
+	for _, result := range closed {
+		log.Printf("⏱️  Closed %s %s after its time stop (%s, order %s)", result.Side, result.Symbol, broker.Name(), result.OrderID)
+	}
+	if err != nil {
+		log.Printf("⚠️  Could not close an expired position: %v", err)
+	}
+}
+
+// journalSignals appends the signals taken this run to the trade journal
+// With execution enabled only setups an order was submitted for are taken; otherwise every new setup is
+func journalSignals(cfg *config.Config, entries []watcher.WatchListEntry, executions []execution.Execution,
+	logInfo func(string, ...interface{})) {
+	orders := make(map[string]execution.Execution, len(executions))
+	for _, result := range executions {
+		if result.OrderID != "" {
+			orders[result.Symbol+"|"+result.Side] = result
+		}
+	}
+
+	var journalEntries []journal.Entry
+	for _, entry := range entries {
+		journalEntry := journal.NewEntry(entry)
+		if cfg.ExecutionEnabled {
+			order, ok := orders[entry.Symbol+"|"+entry.Side]
+			if !ok {
+				continue // Not taken
+			}
+			journalEntry.Quantity, journalEntry.OrderID, journalEntry.Broker = order.Quantity, order.OrderID, cfg.Broker
+		}
+		journalEntries = append(journalEntries, journalEntry)
+	}
+	if len(journalEntries) == 0 {
+		return
+	}
+
+	written, err := journal.Append(cfg.JournalFile, cfg.JournalFormat, journalEntries)
+	if err != nil {
+		log.Printf("⚠️  Could not append to trade journal %s: %v", cfg.JournalFile, err)
+		return
+	}
+	logInfo("📓 Journaled %d signals to %s", written, cfg.JournalFile)
+}
+
+// openSignalStore opens the signal database scoped to the configuration's market section
+// Sections of a multi-market run share SIGNAL_DB_PATH, so each only sees its own signals, outcomes, and runs
+func openSignalStore(cfg *config.Config) (*watcher.SQLiteSignalStore, error) {
+	signalStore, err := watcher.OpenSQLiteSignalStore(cfg.SignalDBPath)
+	if err != nil {
+		return nil, err
+	}
+	signalStore.SetSection(cfg.Section)
+	return signalStore, nil
+}
+
+// newBroker opens the configured broker account
+func newBroker(cfg *config.Config) (execution.Broker, error) {
+	if cfg.Broker == "paper" {
+		return execution.NewPaperBroker(cfg.PaperAccountFile, cfg.PaperStartingEquity)
+	}
+	return execution.NewAlpacaClient(cfg.AlpacaKeyID, cfg.AlpacaSecretKey, cfg.AlpacaPaper), nil
+}
+
+// newDispatcher registers every configured notifier, with the alert rules deciding which signals each receives
+// Only a failure to load the alert rules is reported; notifiers connect on their first delivery
+func newDispatcher(cfg *config.Config, logInfo func(string, ...interface{})) (*notify.Dispatcher, error) {
+	dispatcher := notify.NewDispatcher(cfg.NotifyExisting)
+	dispatcher.SetStreaming(cfg.NotifyStreaming)
+	if len(cfg.WebhookURLs) > 0 {
+		// Webhooks retry per URL with status-aware backoff, so the dispatcher does not retry them again
+		webhookNotifier := notify.NewWebhookNotifier(cfg.WebhookURLs, cfg.WebhookSecret, cfg.WebhookMaxRetries, cfg.WebhookTimeout)
+		dispatcher.Register(webhookNotifier, cfg.NotifyInterval, 0)
+	}
+	if cfg.SMTPHost != "" {
+		emailNotifier := notify.NewEmailNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword,
+			cfg.EmailFrom, cfg.EmailTo, cfg.DisplayLocation)
+		dispatcher.Register(emailNotifier, cfg.NotifyInterval, cfg.NotifyMaxRetries)
+	}
+	if cfg.NtfyTopic != "" {
+		dispatcher.Register(notify.NewNtfyNotifier(cfg.NtfyServer, cfg.NtfyTopic, cfg.NtfyToken), cfg.NotifyInterval, cfg.NotifyMaxRetries)
+	}
+	if cfg.PushoverToken != "" {
+		dispatcher.Register(notify.NewPushoverNotifier(cfg.PushoverToken, cfg.PushoverUser), cfg.NotifyInterval, cfg.NotifyMaxRetries)
+	}
+	if cfg.TwilioAccountSID != "" {
+		smsNotifier := notify.NewSMSNotifier(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.SMSFrom, cfg.SMSTo, float64(cfg.SMSMinScore))
+		dispatcher.Register(smsNotifier, cfg.NotifyInterval, cfg.NotifyMaxRetries)
+	}
+	if cfg.DesktopNotifications {
+		dispatcher.Register(notify.NewDesktopNotifier(), cfg.NotifyInterval, 0)
+	}
+	if len(cfg.KafkaBrokers) > 0 {
+		kafkaNotifier := notify.NewKafkaNotifier(cfg.KafkaBrokers, cfg.KafkaSignalTopic, cfg.KafkaRunTopic,
+			cfg.KafkaUsername, cfg.KafkaPassword, cfg.KafkaTLS)
+		dispatcher.Register(kafkaNotifier, 0, cfg.NotifyMaxRetries)
+	}
+	if cfg.MQTTBroker != "" {
+		mqttNotifier := notify.NewMQTTNotifier(cfg.MQTTBroker, cfg.MQTTClientID, cfg.MQTTUsername, cfg.MQTTPassword,
+			cfg.MQTTTopicPrefix, cfg.MQTTQoS, cfg.MQTTRetain)
+		dispatcher.Register(mqttNotifier, 0, cfg.NotifyMaxRetries)
+	}
+	if cfg.AlertRulesFile != "" {
+		rules, err := alert.LoadRules(cfg.AlertRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		dispatcher.SetSignalFilter(rules.Allows)
+		logInfo("🔔 Loaded %d alert rules from %s", rules.Len(), cfg.AlertRulesFile)
+	}
+	return dispatcher, nil
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/config"
+	"github.com/erhankrygt/sapan/internal/output"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/models"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// providerFetcher is a candle fetcher of one market data provider whose requests count against the API quota
+type providerFetcher interface {
+	data.Fetcher
+	FetchHistory(symbol string, from time.Time) (models.CandleData, error) // Fetches every candle opened since a date
+	Capabilities() data.Capabilities                                       // Describes the timeframes and history served
+
+	SetQuota(quota *data.QuotaTracker)      // Counts every request against a daily quota
+	SetHTTPClient(client *http.Client)      // Sends every request through a pooled client
+	SetRetryPolicy(policy data.RetryPolicy) // Retries transient failures
+	RequestCount() int                      // Returns the number of requests made so far
+}
+
+// newProviderFetcher creates the candle fetcher of the configured provider with the pooled HTTP client and retry policy applied
+func newProviderFetcher(cfg *config.Config) providerFetcher {
+	var fetcher providerFetcher
+	switch cfg.Provider {
+	case "binance":
+		fetcher = data.NewBinanceFetcher(cfg.BinanceAPIURL, cfg.Timeframe)
+	case "csv":
+		layout := data.DefaultCSVLayout()
+		layout.FilePattern, layout.DateFormat, layout.Comma = cfg.CSVFilePattern, cfg.CSVDateFormat, cfg.CSVDelimiter
+		copy(layout.Columns[:], cfg.CSVColumns)
+		fetcher = data.NewCSVCandleSource(cfg.CSVDir, cfg.Timeframe, layout)
+	default:
+		stockFetcher := data.NewStockDataFetcher(cfg.APIKey, cfg.APIURL, cfg.Timeframe)
+		stockFetcher.SetAdjusted(cfg.AdjustedSeries, cfg.AdjustedPrices)
+		fetcher = stockFetcher
+	}
+	fetcher.SetHTTPClient(httpClient(cfg))
+	fetcher.SetRetryPolicy(retryPolicy(cfg))
+	return fetcher
+}
+
+// httpClient returns a client pooling connections by HTTP_MAX_IDLE_CONNS, with HTTP_TIMEOUT_SECONDS and HTTP_PROXY_URL
+// The proxy URL is validated when the configuration loads, so a failure here only falls back to the default transport
+func httpClient(cfg *config.Config) *http.Client {
+	options := data.DefaultHTTPClientOptions()
+	options.Timeout, options.MaxIdleConns, options.ProxyURL = cfg.HTTPTimeout, cfg.HTTPMaxIdleConns, cfg.HTTPProxyURL
+	client, err := data.NewHTTPClient(options)
+	if err != nil {
+		log.Printf("⚠️  %v; using the default HTTP transport", err)
+		return &http.Client{Timeout: cfg.HTTPTimeout}
+	}
+	return client
+}
+
+// retryPolicy returns the retry policy of RETRY_ATTEMPTS and RETRY_BASE_DELAY_SECONDS
+func retryPolicy(cfg *config.Config) data.RetryPolicy {
+	return data.RetryPolicy{MaxAttempts: cfg.RetryAttempts, BaseDelay: cfg.RetryBaseDelay}
+}
+
+// marketRun is the outcome of scanning one market section
+type marketRun struct {
+	section  string                      // Section name from MARKETS
+	cfg      *config.Config              // Section configuration (nil when it could not be loaded)
+	summary  processor.ProcessingSummary // Processing summary of the section's scan
+	exitCode int                         // Exit code of the section's scan
+}
+
+// runMarkets scans every section listed in MARKETS one after another, each with its own provider, calendar,
+// and watch list, then prints a combined summary; failures take precedence over the signal exit code
+func runMarkets(cfg *config.Config, args []string) int {
+	runs := make([]marketRun, 0, len(cfg.Markets))
+	code := exitOK
+	for _, section := range cfg.Markets {
+		sectionCfg, err := config.LoadSectionConfig(args, section)
+		if err != nil {
+			log.Printf("Failed to load configuration of market %s: %v", section, err)
+			runs = append(runs, marketRun{section: section, exitCode: exitConfigError})
+			code = combineExitCodes(code, exitConfigError)
+			continue
+		}
+
+		if sectionCfg.OutputMode.ShowsProgress() {
+			fmt.Printf("\n🌍 Market %s (%s calendar, %s provider)\n\n", section, sectionCfg.Market, sectionCfg.Provider)
+		}
+		summary, _, sectionCode := scan(sectionCfg, nil, scanHooks{})
+		runs = append(runs, marketRun{section: section, cfg: sectionCfg, summary: summary, exitCode: sectionCode})
+		code = combineExitCodes(code, sectionCode)
+	}
+
+	if cfg.OutputMode.ShowsSummary() {
+		fmt.Println()
+		printMarketRuns(output.NewPalette(output.ColorEnabled(cfg.Color)), runs)
+	}
+	return code
+}
+
+// combineExitCodes merges the exit code of another section into the run's exit code
+// The first failure wins, a failure replaces the signal exit code, and the signal exit code replaces success
+func combineExitCodes(current, next int) int {
+	isFailure := func(code int) bool { return code >= exitFailure && code <= exitProviderError }
+	if isFailure(current) || (current != exitOK && !isFailure(next)) {
+		return current
+	}
+	return next
+}
+
+// printMarketRuns renders one summary row per market section; sections that failed are highlighted in red
+func printMarketRuns(palette *output.Palette, runs []marketRun) {
+	fmt.Println(palette.Bold("Markets Summary:"))
+	table := output.NewTable(palette, "Market", "Calendar", "Provider", "Processed", "Successful", "Errors", "Skipped", "Long", "Short", "Exit code")
+	for _, run := range runs {
+		color := palette.Plain
+		if run.exitCode >= exitFailure && run.exitCode <= exitProviderError {
+			color = palette.Red
+		}
+		calendarName, provider := "-", "-"
+		if run.cfg != nil {
+			calendarName, provider = run.cfg.Market, run.cfg.Provider
+		}
+		table.AddRow(color, run.section, calendarName, provider,
+			strconv.Itoa(run.summary.Total),
+			strconv.Itoa(run.summary.Successful),
+			strconv.Itoa(run.summary.Errors),
+			strconv.Itoa(run.summary.Skipped),
+			strconv.Itoa(run.summary.LongCount),
+			strconv.Itoa(run.summary.ShortCount),
+			strconv.Itoa(run.exitCode))
+	}
+	table.Render(os.Stdout)
+}
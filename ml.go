@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/mlscore"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+// runTrainModel trains the signal scoring model on every resolved outcome in the signal database
+// Only signals that hit their target or stop and carry features recorded with ML_SCORING count as samples
+func runTrainModel(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.SignalDBPath == "" {
+		log.Printf("SIGNAL_DB_PATH is required to train the signal model")
+		return exitConfigError
+	}
+	signalStore, err := openSignalStore(cfg)
+	if err != nil {
+		log.Printf("Failed to open signal database: %v", err)
+		return exitFailure
+	}
+	defer signalStore.Close()
+
+	outcomes, err := signalStore.QueryOutcomes(watcher.SignalQuery{})
+	if err != nil {
+		log.Printf("Failed to load signal outcomes: %v", err)
+		return exitFailure
+	}
+	var samples []mlscore.Sample
+	for _, outcome := range outcomes {
+		if len(outcome.Features) == 0 || (outcome.Status != watcher.OutcomeTarget && outcome.Status != watcher.OutcomeStop) {
+			continue
+		}
+		samples = append(samples, mlscore.Sample{Features: outcome.Features, Success: outcome.Status == watcher.OutcomeTarget})
+	}
+
+	model, err := mlscore.Train(samples)
+	if err != nil {
+		log.Printf("Failed to train signal model: %v", err)
+		return exitFailure
+	}
+	if err := model.Save(cfg.MLModelFile); err != nil {
+		log.Printf("Failed to save signal model: %v", err)
+		return exitFailure
+	}
+
+	fmt.Printf("🧠 Trained on %d resolved signals (%.1f%% hit their target)\n", model.Samples, model.Metrics.BaseRate*100)
+	fmt.Printf("   Accuracy %.1f%%, log loss %.3f (in-sample)\n\n", model.Metrics.Accuracy*100, model.Metrics.LogLoss)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FEATURE\tWEIGHT")
+	for i, name := range model.Features {
+		fmt.Fprintf(tw, "%s\t%+.3f\n", name, model.Weights[i])
+	}
+	tw.Flush()
+	log.Printf("💾 Signal model written to %s", cfg.MLModelFile)
+	return exitOK
+}
@@ -1,23 +1,93 @@
 // Package models contains data structures for stock and candlestick data
 package models
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // Candle represents a single candlestick with OHLCV data
 // This structure stores price and volume information for a specific time period
 type Candle struct {
-	Date   time.Time `json:"date"`   // Trading date for this candlestick
-	Open   float64   `json:"open"`   // Opening price at the start of the period
-	High   float64   `json:"high"`   // Highest price reached during the period
-	Low    float64   `json:"low"`    // Lowest price reached during the period
-	Close  float64   `json:"close"`  // Closing price at the end of the period
-	Volume int64     `json:"volume"` // Total volume traded during the period
+	Date      time.Time `json:"date"`                // Trading session date (midnight in the exchange timezone)
+	Timestamp time.Time `json:"timestamp,omitempty"` // Start of the period including the time of day (equals Date for daily and longer candles)
+	Open      float64   `json:"open"`                // Opening price at the start of the period
+	High      float64   `json:"high"`                // Highest price reached during the period
+	Low       float64   `json:"low"`                 // Lowest price reached during the period
+	Close     float64   `json:"close"`               // Closing price at the end of the period
+	Volume    int64     `json:"volume"`              // Total volume traded during the period
+
+	// Fields of adjusted series, zero when the provider served as-traded candles only
+	AdjustedClose    float64 `json:"adjusted_close,omitempty"`    // Close adjusted for every later split and dividend
+	SplitCoefficient float64 `json:"split_coefficient,omitempty"` // New shares per old share of a split taking effect this period (1 without one)
+	DividendAmount   float64 `json:"dividend_amount,omitempty"`   // Cash dividend per share going ex this period
+}
+
+// Time returns the start of the candle's period, falling back to Date for candles stored without a timestamp
+// Use it wherever intraday candles must stay distinct; Date groups them by session
+func (c Candle) Time() time.Time {
+	if c.Timestamp.IsZero() {
+		return c.Date
+	}
+	return c.Timestamp
+}
+
+// IsBullish reports whether the candle closed above its open
+func (c Candle) IsBullish() bool {
+	return c.Close > c.Open
+}
+
+// IsBearish reports whether the candle closed below its open
+func (c Candle) IsBearish() bool {
+	return c.Close < c.Open
+}
+
+// Body returns the distance between open and close (always positive)
+func (c Candle) Body() float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+// Range returns the distance between high and low
+func (c Candle) Range() float64 {
+	return c.High - c.Low
+}
+
+// UpperWick returns the distance between the high and the top of the body
+func (c Candle) UpperWick() float64 {
+	return c.High - math.Max(c.Open, c.Close)
+}
+
+// LowerWick returns the distance between the bottom of the body and the low
+func (c Candle) LowerWick() float64 {
+	return math.Min(c.Open, c.Close) - c.Low
 }
 
 // CandleData represents a collection of candlesticks for analysis
 // This structure is used to store multiple candlesticks for a single stock
 type CandleData struct {
-	Candles []Candle `json:"Candles"` // Array of candlesticks sorted by date (ascending)
+	Timeframe      string       `json:"timeframe,omitempty"`       // Timeframe of every candle (daily, weekly, monthly, or an intraday interval such as 5min)
+	Candles        []Candle     `json:"Candles"`                   // Array of candlesticks sorted by time (ascending)
+	Adjustments    []Adjustment `json:"adjustments,omitempty"`     // Corporate actions already applied to the prices (empty for as-traded candles)
+	BackfilledFrom *time.Time   `json:"backfilled_from,omitempty"` // Date the archived history was backfilled from (nil when never backfilled, zero for the full history)
+	Dropped        []Candle     `json:"-"`                         // Bars the provider returned that cannot have traded (e.g. an inverted range), left out of Candles
+}
+
+// TimeframeDuration returns the length of one candle of an intraday timeframe such as 5min or 60min
+// Daily, weekly, and monthly candles follow the market calendar instead, so they return 0
+func TimeframeDuration(timeframe string) time.Duration {
+	switch timeframe {
+	case "1min":
+		return time.Minute
+	case "5min":
+		return 5 * time.Minute
+	case "15min":
+		return 15 * time.Minute
+	case "30min":
+		return 30 * time.Minute
+	case "60min":
+		return time.Hour
+	}
+	return 0
 }
 
 // CandleResponse represents the raw API response from Alpha Vantage
@@ -34,11 +104,19 @@ type CandleResponse struct {
 
 	// TimeSeries contains the actual OHLCV data as strings from the API
 	// Keys are date strings, values contain the price and volume data
-	TimeSeries map[string]struct {
-		Open   string `json:"1. open"`   // Opening price as string
-		High   string `json:"2. high"`   // High price as string
-		Low    string `json:"3. low"`    // Low price as string
-		Close  string `json:"4. close"`  // Close price as string
-		Volume string `json:"5. volume"` // Volume as string
-	} `json:"Time Series (Daily)"`
+	TimeSeries map[string]TimeSeriesEntry `json:"Time Series (Daily)"`
+}
+
+// TimeSeriesEntry is one period of an Alpha Vantage time series with every value as a string
+// Adjusted series move volume to "6. volume" to make room for the adjusted close
+type TimeSeriesEntry struct {
+	Open             string `json:"1. open"`              // Opening price as string
+	High             string `json:"2. high"`              // High price as string
+	Low              string `json:"3. low"`               // Low price as string
+	Close            string `json:"4. close"`             // Close price as string
+	Volume           string `json:"5. volume"`            // Volume as string (as-traded series)
+	AdjustedClose    string `json:"5. adjusted close"`    // Adjusted close as string (adjusted series)
+	AdjustedVolume   string `json:"6. volume"`            // Volume as string (adjusted series)
+	DividendAmount   string `json:"7. dividend amount"`   // Dividend per share as string (adjusted series)
+	SplitCoefficient string `json:"8. split coefficient"` // Split coefficient as string (daily adjusted series)
 }
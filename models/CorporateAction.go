@@ -0,0 +1,70 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Split is a stock split that takes effect at the open of its ex-date
+type Split struct {
+	ExDate time.Time `json:"ex_date"` // First session trading at the new share count
+	Ratio  float64   `json:"ratio"`   // New shares per old share (4 for a 4-for-1 split, 0.1 for a 1-for-10 reverse split)
+}
+
+// Dividend is a cash dividend; buyers from the ex-date on no longer receive it
+type Dividend struct {
+	ExDate time.Time `json:"ex_date"` // First session trading without the dividend
+	Amount float64   `json:"amount"`  // Cash paid per share in the listing currency
+}
+
+// CorporateActions holds the splits and dividends of one stock
+type CorporateActions struct {
+	Symbol    string     `json:"symbol"`              // Stock ticker symbol
+	Splits    []Split    `json:"splits,omitempty"`    // Stock splits, in any order
+	Dividends []Dividend `json:"dividends,omitempty"` // Cash dividends, in any order
+}
+
+// Adjustment kinds recorded on applied adjustments
+const (
+	AdjustmentSplit    = "split"    // Adjustment for a stock split
+	AdjustmentDividend = "dividend" // Adjustment for a cash dividend
+)
+
+// Adjustment is a corporate action reduced to the factors applied to every candle before its ex-date
+// Candle archives record the adjustments baked into their prices so the same action is never applied twice
+type Adjustment struct {
+	Kind         string    `json:"kind"`          // AdjustmentSplit or AdjustmentDividend
+	ExDate       time.Time `json:"ex_date"`       // Candles of earlier sessions are adjusted
+	PriceFactor  float64   `json:"price_factor"`  // Multiplier applied to open, high, low, and close
+	VolumeFactor float64   `json:"volume_factor"` // Multiplier applied to volume
+}
+
+// Applies reports whether the adjustment changes a candle of the given session
+// Dates are compared by calendar day so sessions dated in the exchange timezone match ex-dates in any timezone
+func (a Adjustment) Applies(session time.Time) bool {
+	return calendarDay(session).Before(calendarDay(a.ExDate))
+}
+
+// calendarDay returns the calendar date of t in its own timezone as midnight UTC
+func calendarDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Adjust returns the candle with the adjustment applied (unchanged on or after the ex-date)
+func (a Adjustment) Adjust(candle Candle) Candle {
+	if !a.Applies(candle.Date) {
+		return candle
+	}
+	candle.Open *= a.PriceFactor
+	candle.High *= a.PriceFactor
+	candle.Low *= a.PriceFactor
+	candle.Close *= a.PriceFactor
+	candle.Volume = int64(math.Round(float64(candle.Volume) * a.VolumeFactor))
+	return candle
+}
+
+// SameAction reports whether two adjustments stem from the same corporate action
+func (a Adjustment) SameAction(other Adjustment) bool {
+	return a.Kind == other.Kind && calendarDay(a.ExDate).Equal(calendarDay(other.ExDate))
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Quote is the latest price snapshot of a stock from a bulk quote request or a streaming feed
+// Quotes are cheap to fetch in batches and feed the screener before full candle analysis
+type Quote struct {
+	Symbol        string    // Stock ticker symbol
+	Time          time.Time // Time of the quote (zero when the provider omits it)
+	Price         float64   // Latest traded or closing price
+	Volume        int64     // Volume traded in the current or last session
+	ChangePercent float64   // Change from the previous close in percent
+	Bid           float64   // Best bid price (zero when the provider only reports the last price)
+	Ask           float64   // Best ask price (zero when the provider only reports the last price)
+	BidSize       int64     // Shares offered at the bid
+	AskSize       int64     // Shares offered at the ask
+}
+
+// Mid returns the midpoint of the bid and ask, falling back to the last price when either side is missing
+func (q Quote) Mid() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return q.Price
+	}
+	return (q.Bid + q.Ask) / 2
+}
+
+// Spread returns the distance between the ask and the bid (zero when either side is missing)
+func (q Quote) Spread() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return 0
+	}
+	return q.Ask - q.Bid
+}
+
+// Trade is a single execution from a streaming feed
+// Trades are aggregated into candles for real-time intraday analysis
+type Trade struct {
+	Symbol string    // Stock ticker symbol
+	Time   time.Time // Time of the execution
+	Price  float64   // Execution price
+	Size   int64     // Shares traded
+}
@@ -4,10 +4,20 @@ package models
 // Stock represents a single stock with its basic information
 // This structure is used to store stock metadata from the stocks.json file
 type Stock struct {
-	Symbol   string `json:"symbol"`   // Stock ticker symbol (e.g., "AAPL", "GOOGL")
-	Name     string `json:"name"`     // Full company name
-	Sector   string `json:"sector"`   // Business sector (e.g., "Technology", "Healthcare")
-	Industry string `json:"industry"` // Specific industry within the sector
+	Symbol    string  `json:"symbol"`               // Stock ticker symbol (e.g., "AAPL", "GOOGL")
+	Name      string  `json:"name"`                 // Full company name
+	Sector    string  `json:"sector"`               // Business sector (e.g., "Technology", "Healthcare")
+	Industry  string  `json:"industry"`             // Specific industry within the sector
+	MarketCap float64 `json:"market_cap,omitempty"` // Market capitalization in the listing currency (optional)
+	Exchange  string  `json:"exchange,omitempty"`   // Listing exchange (e.g., "NASDAQ", "BIST")
+	Currency  string  `json:"currency,omitempty"`   // ISO 4217 currency prices are quoted in (e.g., "USD", "TRY")
+	Country   string  `json:"country,omitempty"`    // Country of the listing (e.g., "USA", "Turkey")
+	ISIN      string  `json:"isin,omitempty"`       // International Securities Identification Number
+}
+
+// HasListing reports whether the exchange, currency, and country of the stock are all known
+func (s Stock) HasListing() bool {
+	return s.Exchange != "" && s.Currency != "" && s.Country != ""
 }
 
 // StockData represents a collection of stocks
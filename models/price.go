@@ -0,0 +1,71 @@
+package models
+
+import (
+	"math"
+	"strconv"
+)
+
+// Price precision used by US equity venues and brokers: whole cents at or above $1, hundredths of a cent below
+const (
+	centDecimals      = 2 // Decimals of prices at or above $1
+	subDollarDecimals = 4 // Decimals of prices below $1
+)
+
+// PriceDecimals returns the number of decimals a price is quoted with
+func PriceDecimals(price float64) int {
+	if math.Abs(price) >= 1 {
+		return centDecimals
+	}
+	return subDollarDecimals
+}
+
+// TickSize returns the smallest price increment at the given price level
+func TickSize(price float64) float64 {
+	return math.Pow10(-PriceDecimals(price))
+}
+
+// RoundPrice rounds a price to the nearest tick
+// The result is the same float64 a broker or parser produces for the decimal string, so equality checks are exact
+func RoundPrice(price float64) float64 {
+	return roundTo(price, math.Round)
+}
+
+// RoundPriceUp rounds a price up to the next tick (buy stops, short stop-losses)
+func RoundPriceUp(price float64) float64 {
+	return roundTo(price, func(scaled float64) float64 { return math.Ceil(scaled - priceEpsilon) })
+}
+
+// RoundPriceDown rounds a price down to the previous tick (sell stops, long stop-losses)
+func RoundPriceDown(price float64) float64 {
+	return roundTo(price, func(scaled float64) float64 { return math.Floor(scaled + priceEpsilon) })
+}
+
+// ComparePrices compares two prices at tick precision, returning -1, 0, or 1
+// Prices that round to the same tick are equal, so float noise never decides a rule
+func ComparePrices(a, b float64) int {
+	a, b = RoundPrice(a), RoundPrice(b)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// FormatPrice renders a price rounded to its tick with the matching number of decimals
+func FormatPrice(price float64) string {
+	return strconv.FormatFloat(RoundPrice(price), 'f', PriceDecimals(price), 64)
+}
+
+// priceEpsilon absorbs float error in scaled prices (e.g. 100.1 * 100 = 10009.999999999998) before ceil and floor
+const priceEpsilon = 1e-6
+
+// roundTo scales a price to whole ticks, applies round, and scales back by division for an exact decimal result
+func roundTo(price float64, round func(float64) float64) float64 {
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return price
+	}
+	scale := math.Pow10(PriceDecimals(price))
+	return round(price*scale) / scale
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRoundPrice(t *testing.T) {
+	tests := []struct {
+		price             float64
+		nearest, up, down string
+	}{
+		{100.1, "100.10", "100.10", "100.10"},
+		{100.105, "100.11", "100.11", "100.10"},
+		{100.1 + 0.2 - 0.2, "100.10", "100.10", "100.10"}, // float noise must not move the tick
+		{99.999, "100.00", "100.00", "99.99"},
+		{0.12345, "0.1235", "0.1235", "0.1234"},
+		{0.5, "0.5000", "0.5000", "0.5000"},
+	}
+	for _, tt := range tests {
+		if got := FormatPrice(tt.price); got != tt.nearest {
+			t.Errorf("FormatPrice(%v) = %s, want %s", tt.price, got, tt.nearest)
+		}
+		if got := strconv.FormatFloat(RoundPriceUp(tt.price), 'f', PriceDecimals(tt.price), 64); got != tt.up {
+			t.Errorf("RoundPriceUp(%v) = %s, want %s", tt.price, got, tt.up)
+		}
+		if got := strconv.FormatFloat(RoundPriceDown(tt.price), 'f', PriceDecimals(tt.price), 64); got != tt.down {
+			t.Errorf("RoundPriceDown(%v) = %s, want %s", tt.price, got, tt.down)
+		}
+	}
+}
+
+func TestRoundPriceMatchesParsedDecimal(t *testing.T) {
+	for _, text := range []string{"100.13", "17.07", "0.0301", "4321.99"} {
+		parsed, _ := strconv.ParseFloat(text, 64)
+		if got := RoundPrice(parsed * 3 / 3); got != parsed {
+			t.Errorf("RoundPrice(%s) = %v, want the parsed value %v", text, got, parsed)
+		}
+	}
+}
+
+func TestComparePrices(t *testing.T) {
+	if got := ComparePrices(0.1+0.2, 0.3); got != 0 {
+		t.Errorf("ComparePrices(0.1+0.2, 0.3) = %d, want 0", got)
+	}
+	if got := ComparePrices(100.01, 100.00); got != 1 {
+		t.Errorf("ComparePrices(100.01, 100.00) = %d, want 1", got)
+	}
+	if got := ComparePrices(100.004, 100.00); got != 0 {
+		t.Errorf("ComparePrices(100.004, 100.00) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/erhankrygt/sapan/internal/monitor"
+	"github.com/erhankrygt/sapan/internal/notify"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runMonitor polls quotes for the watch list setups and notifies when a price crosses an entry trigger or a stop
+// The watch list file is re-read on every poll, so setups found by scans running alongside are picked up
+func runMonitor(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	stockFetcher := newProviderFetcher(cfg)
+	quoteSource, ok := stockFetcher.(monitor.QuoteSource)
+	if !ok {
+		log.Printf("The monitor needs bulk quotes, which the %s provider does not offer", cfg.Provider)
+		return exitConfigError
+	}
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return exitConfigError
+	}
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+
+	dispatcher, err := newDispatcher(cfg, log.Printf)
+	if err != nil {
+		log.Printf("Failed to load alert rules: %v", err)
+		return exitConfigError
+	}
+	if dispatcher.Count() == 0 {
+		log.Printf("⚠️  No notifier is configured; triggers are only logged")
+	}
+	dispatcher.Start()
+	defer dispatcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	priceMonitor := monitor.NewMonitor(quoteSource, dispatcher)
+	log.Printf("👀 Monitoring %s setups every %s while the %s market is open", cfg.WatchListFile, cfg.MonitorInterval, cfg.Market)
+
+	ticker := time.NewTicker(cfg.MonitorInterval)
+	defer ticker.Stop()
+	for {
+		if now := time.Now(); marketCalendar.IsOpen(now) {
+			pollWatchList(cfg.WatchListFile, priceMonitor, now)
+			saveQuota(quota)
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("👋 SAPAN monitor stopped")
+			return exitOK
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollWatchList checks the persisted setups against the latest quotes once; failures are logged and retried next poll
+func pollWatchList(path string, priceMonitor *monitor.Monitor, now time.Time) {
+	watchListManager := watcher.NewWatchListManager()
+	if err := watchListManager.Load(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("⚠️  Could not read watch list from %s: %v", path, err)
+		return
+	}
+	triggers, err := priceMonitor.Check(watchListManager.GetEntries(), now)
+	if err != nil {
+		log.Printf("⚠️  Could not fetch quotes: %v", err)
+		return
+	}
+	for _, trigger := range triggers {
+		if trigger.Kind == notify.SetupInvalidated {
+			log.Printf("❌ %s %s invalidated: %.2f crossed the stop at %.2f", trigger.Setup.Side, trigger.Setup.Symbol, trigger.Price, trigger.Level)
+			continue
+		}
+		log.Printf("🎯 %s %s triggered: %.2f crossed the entry at %.2f", trigger.Setup.Side, trigger.Setup.Symbol, trigger.Price, trigger.Level)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/config"
+	"log"
+)
+
+// attachQuota counts the fetcher's requests against today's API quota of the configured provider
+// The returned tracker must be saved with saveQuota once the command is done so later runs share the budget
+func attachQuota(cfg *config.Config, stockFetcher providerFetcher) (*data.QuotaTracker, error) {
+	quota, err := data.NewQuotaTracker(cfg.QuotaFile, cfg.Provider, cfg.APIDailyQuota)
+	if err != nil {
+		return nil, err
+	}
+	stockFetcher.SetQuota(quota)
+	return quota, nil
+}
+
+// saveQuota persists the API usage; failures are only logged since the requests were already made
+func saveQuota(quota *data.QuotaTracker) {
+	if err := quota.Save(); err != nil {
+		log.Printf("⚠️  Could not save API usage: %v", err)
+	}
+}
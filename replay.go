@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/cpupool"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/internal/replay"
+	"log"
+	"os"
+	"time"
+)
+
+// runReplay steps the scanner day by day over the candles archived in CANDLE_DIR
+// Symbols missing from the archive are fetched once and archived, so later replays run offline
+func runReplay(args []string) int {
+	fromText, args := takeFlag(args, "from")
+	toText, args := takeFlag(args, "to")
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.CandleDir == "" {
+		log.Printf("CANDLE_DIR is required to replay archived candles")
+		return exitConfigError
+	}
+	var from, to time.Time
+	for _, date := range []struct {
+		text   string
+		target *time.Time
+	}{{fromText, &from}, {toText, &to}} {
+		if date.text == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", date.text)
+		if err != nil {
+			log.Printf("Invalid replay date %q (expected YYYY-MM-DD)", date.text)
+			return exitConfigError
+		}
+		*date.target = parsed
+	}
+
+	marketCalendar, err := newMarketCalendar(cfg)
+	if err != nil {
+		log.Printf("Failed to load market calendar: %v", err)
+		return exitConfigError
+	}
+	stockData, err := data.NewStockListLoader().LoadStocksFromPatterns(cfg.StocksFile)
+	if err != nil {
+		log.Println("Failed to load stocks:", err)
+		return exitConfigError
+	}
+	stockFilter, err := data.NewStockFilter(cfg.IncludeSectors, cfg.ExcludeSectors, cfg.IncludeSymbols, cfg.ExcludeSymbols, cfg.SymbolPattern)
+	if err != nil {
+		log.Println("Failed to build stock filter:", err)
+		return exitConfigError
+	}
+	stockData = stockFilter.Apply(stockData)
+
+	candleStore := data.NewCandleStore(cfg.CandleDir, cfg.Timeframe)
+	sapanStrategy, stockFetcher := newStrategy(cfg), newProviderFetcher(cfg)
+	if !resolveOutputSize(cfg, sapanStrategy, stockFetcher.Capabilities()) {
+		return exitConfigError
+	}
+	if err := stockFetcher.Capabilities().Check(cfg.Timeframe, cfg.OutputSize); err != nil {
+		log.Printf("Unsupported provider settings: %v", err)
+		return exitConfigError
+	}
+	quota, err := attachQuota(cfg, stockFetcher)
+	if err != nil {
+		log.Printf("Failed to load API usage: %v", err)
+		return exitConfigError
+	}
+	defer saveQuota(quota)
+	series := make([]replay.Series, 0, len(stockData.Stocks))
+	latest := time.Time{}
+	for _, stock := range stockData.Stocks {
+		candleData, err := candleStore.Load(stock.Symbol)
+		if errors.Is(err, os.ErrNotExist) {
+			if stockFetcher.RequestCount() > 0 && cfg.RequestDelay > 0 {
+				time.Sleep(cfg.RequestDelay) // Respect API limits between symbols
+			}
+			ctx, cancel := fetchContext(cfg)
+			candleData, err = stockFetcher.FetchStockData(ctx, stock.Symbol, cfg.OutputSize)
+			cancel()
+			if err == nil {
+				if !data.IsIntradayTimeframe(cfg.Timeframe) {
+					candleData.Candles = marketCalendar.ClosedCandles(candleData.Candles, time.Now())
+				}
+				err = candleStore.Save(stock.Symbol, candleData)
+			}
+		}
+		if err != nil {
+			log.Printf("⚠️  Skipping %s: %v", stock.Symbol, err)
+			continue
+		}
+		if n := len(candleData.Candles); n > 0 && candleData.Candles[n-1].Date.After(latest) {
+			latest = candleData.Candles[n-1].Date
+		}
+		series = append(series, replay.Series{Stock: stock, Candles: candleData.Candles})
+	}
+	if len(series) == 0 {
+		log.Printf("No archived candles to replay")
+		return exitProviderError
+	}
+
+	// Default to the last month of archived sessions
+	if to.IsZero() {
+		to = latest
+	} else {
+		to = to.Add(24*time.Hour - time.Nanosecond) // Include every candle of the final day
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, -1, 0)
+	}
+	sessions := replay.Sessions(series, from, to)
+	log.Printf("⏪ Replaying %d sessions from %s to %s over %d stocks...", len(sessions),
+		from.Format("2006-01-02"), to.Format("2006-01-02"), len(series))
+
+	replayer := replay.NewReplayer(sapanStrategy, cfg.OutputSize)
+	replayer.SetCPUPool(cpupool.New(cfg.CPUWorkers))
+	if cfg.CorrelationThreshold > 0 {
+		replayer.SetCorrelation(cfg.CorrelationThreshold, cfg.CorrelationLookback, cfg.CorrelationMode == "trim")
+	}
+	days := replayer.Run(series, sessions)
+	replay.Print(os.Stdout, days)
+
+	if cfg.ResultsFile != "" {
+		raw, err := json.MarshalIndent(days, "", "  ")
+		if err == nil {
+			err = fsutil.WriteFileAtomic(cfg.ResultsFile, append(raw, '\n'))
+		}
+		if err != nil {
+			log.Printf("⚠️  Could not write replay result to %s: %v", cfg.ResultsFile, err)
+		}
+	}
+	return exitOK
+}
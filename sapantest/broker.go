@@ -0,0 +1,166 @@
+package sapantest
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/execution"
+	"sync"
+	"time"
+)
+
+var _ execution.Broker = (*Broker)(nil)
+
+// Broker is an in-memory execution.Broker that accepts every order and keeps it for assertions
+// Orders stay accepted until a test moves them with SetStatus
+type Broker struct {
+	account   execution.Account        // Account figures returned by Account
+	positions []execution.Position     // Positions returned by Positions
+	orders    []execution.Order        // Submitted orders, in submission order
+	brackets  []execution.BracketOrder // Bracket orders as submitted
+	err       error                    // Error returned by SubmitOrder (nil accepts orders)
+	mutex     sync.Mutex               // Guards every field
+}
+
+// NewBroker creates a broker with the given account equity and matching buying power
+func NewBroker(equity float64) *Broker {
+	return &Broker{account: execution.Account{Equity: equity, BuyingPower: equity}}
+}
+
+// SetPositions sets the open positions reported by the broker
+func (b *Broker) SetPositions(positions []execution.Position) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.positions = positions
+}
+
+// SetError makes SubmitOrder reject every order with err
+func (b *Broker) SetError(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.err = err
+}
+
+// Name returns the broker name used in logs
+func (b *Broker) Name() string {
+	return "fake"
+}
+
+// Account returns the configured account figures
+func (b *Broker) Account() (execution.Account, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.account, nil
+}
+
+// Positions returns the configured open positions
+func (b *Broker) Positions() ([]execution.Position, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]execution.Position(nil), b.positions...), nil
+}
+
+// SubmitOrder accepts the order, rejecting duplicate client order IDs like a real broker
+func (b *Broker) SubmitOrder(order execution.BracketOrder) (execution.Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.err != nil {
+		return execution.Order{}, b.err
+	}
+	for _, existing := range b.orders {
+		if order.ClientOrderID != "" && existing.ClientOrderID == order.ClientOrderID {
+			return execution.Order{}, fmt.Errorf("client order ID %s already exists", order.ClientOrderID)
+		}
+	}
+
+	submitted := execution.Order{
+		ID:            fmt.Sprintf("fake-%d", len(b.orders)+1),
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Quantity:      order.Quantity,
+		Status:        execution.OrderAccepted,
+	}
+	b.orders = append(b.orders, submitted)
+	b.brackets = append(b.brackets, order)
+	return submitted, nil
+}
+
+// CancelOrder marks a working order canceled
+func (b *Broker) CancelOrder(orderID string) error {
+	return b.SetStatus(orderID, execution.OrderCanceled, 0)
+}
+
+// OrderStatus returns the current state of an order
+func (b *Broker) OrderStatus(orderID string) (execution.Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, order := range b.orders {
+		if order.ID == orderID {
+			return order, nil
+		}
+	}
+	return execution.Order{}, fmt.Errorf("order %s not found", orderID)
+}
+
+// FindOrder returns the current state of the order submitted with a client order ID
+func (b *Broker) FindOrder(clientOrderID string) (execution.Order, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, order := range b.orders {
+		if clientOrderID != "" && order.ClientOrderID == clientOrderID {
+			return order, nil
+		}
+	}
+	return execution.Order{}, fmt.Errorf("client order ID %s not found", clientOrderID)
+}
+
+// ClosePosition completes a filled order and drops the open position of its symbol
+func (b *Broker) ClosePosition(orderID string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for i := range b.orders {
+		if b.orders[i].ID != orderID {
+			continue
+		}
+		if b.orders[i].Status != execution.OrderFilled {
+			return fmt.Errorf("order %s is %s and has no open position", orderID, b.orders[i].Status)
+		}
+		b.orders[i].Status = execution.OrderCompleted
+		positions := b.positions[:0]
+		for _, position := range b.positions {
+			if position.Symbol != b.orders[i].Symbol {
+				positions = append(positions, position)
+			}
+		}
+		b.positions = positions
+		return nil
+	}
+	return fmt.Errorf("order %s not found", orderID)
+}
+
+// SetStatus moves an order to status, filling it completely at price for filled and completed orders
+func (b *Broker) SetStatus(orderID, status string, price float64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for i := range b.orders {
+		if b.orders[i].ID != orderID {
+			continue
+		}
+		b.orders[i].Status = status
+		if status == execution.OrderFilled || status == execution.OrderCompleted {
+			b.orders[i].FilledQuantity = b.orders[i].Quantity
+			b.orders[i].FilledPrice = price
+			if b.orders[i].FilledAt.IsZero() {
+				b.orders[i].FilledAt = time.Now().UTC()
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("order %s not found", orderID)
+}
+
+// Orders returns the bracket orders submitted so far, in submission order
+func (b *Broker) Orders() []execution.BracketOrder {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]execution.BracketOrder(nil), b.brackets...)
+}
@@ -0,0 +1,120 @@
+// Package sapantest provides test doubles and candle fixtures for code built on SAPAN
+// The fakes satisfy the fetcher, strategy, watch list, notifier, and broker interfaces without calling live APIs
+package sapantest
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"math"
+	"time"
+)
+
+// CandleBuilder assembles a daily candle series one segment at a time
+// Dates advance one weekday per candle so fixtures line up with trading sessions
+type CandleBuilder struct {
+	candles []models.Candle // Candles built so far, oldest first
+	date    time.Time       // Date of the next candle
+	close   float64         // Close of the last candle (the open of the next one)
+	volume  int64           // Volume stamped on generated candles
+}
+
+// NewCandleBuilder starts a series on the first weekday at or after start, opening at price
+func NewCandleBuilder(start time.Time, price float64) *CandleBuilder {
+	return &CandleBuilder{
+		date:   nextWeekday(start), // Skip a weekend start date
+		close:  price,              // First candle opens at the starting price
+		volume: 1_000_000,          // Default volume for generated candles
+	}
+}
+
+// Volume sets the volume of the candles generated after this call
+func (b *CandleBuilder) Volume(volume int64) *CandleBuilder {
+	b.volume = volume
+	return b
+}
+
+// Candle appends a candle with explicit prices
+func (b *CandleBuilder) Candle(open, high, low, close float64) *CandleBuilder {
+	b.candles = append(b.candles, models.Candle{
+		Date:      b.date,
+		Timestamp: b.date,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    b.volume,
+	})
+	b.date = nextWeekday(b.date.AddDate(0, 0, 1))
+	b.close = close
+	return b
+}
+
+// Trend appends n candles whose closes move by change per candle (negative for a downtrend)
+func (b *CandleBuilder) Trend(n int, change float64) *CandleBuilder {
+	for i := 0; i < n; i++ {
+		b.step(b.close + change)
+	}
+	return b
+}
+
+// Flat appends n candles closing at the current price
+func (b *CandleBuilder) Flat(n int) *CandleBuilder {
+	return b.Trend(n, 0)
+}
+
+// Wave appends n candles drifting by drift per candle with a sine swing of amplitude over period candles
+// The swings give the oscillators regular pullbacks, which plain trends never produce
+func (b *CandleBuilder) Wave(n int, drift, amplitude, period float64) *CandleBuilder {
+	base := b.close
+	for i := 1; i <= n; i++ {
+		b.step(base + drift*float64(i) + amplitude*math.Sin(2*math.Pi*float64(i)/period))
+	}
+	return b
+}
+
+// step appends a candle opening at the previous close with a one percent range around the body
+func (b *CandleBuilder) step(close float64) {
+	open := b.close
+	wick := math.Max(open, close) * 0.005
+	b.Candle(open, math.Max(open, close)+wick, math.Min(open, close)-wick, close)
+}
+
+// Candles returns a copy of the series built so far
+func (b *CandleBuilder) Candles() []models.Candle {
+	return append([]models.Candle(nil), b.candles...)
+}
+
+// Data returns the series wrapped as fetcher output
+func (b *CandleBuilder) Data() models.CandleData {
+	return models.CandleData{Candles: b.Candles()}
+}
+
+// Uptrend returns n daily candles rising from 100 with regular pullbacks, enough history for every indicator when n >= 200
+func Uptrend(n int) []models.Candle {
+	return NewCandleBuilder(fixtureStart, 100).Wave(n, 0.3, 4, 38).Candles()
+}
+
+// Downtrend returns n daily candles falling from 300 with regular rallies
+func Downtrend(n int) []models.Candle {
+	return NewCandleBuilder(fixtureStart, 300).Wave(n, -0.3, 4, 38).Candles()
+}
+
+// Stock returns a stock list entry for symbol with placeholder name and sector
+func Stock(symbol string) models.Stock {
+	return models.Stock{
+		Symbol:   symbol,
+		Name:     symbol + " Inc.",
+		Sector:   "Technology",
+		Industry: "Software",
+	}
+}
+
+// fixtureStart is the first date of the canned Uptrend and Downtrend series
+var fixtureStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// nextWeekday returns t, or the following Monday when t falls on a weekend
+func nextWeekday(t time.Time) time.Time {
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package sapantest_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erhankrygt/sapan/sapantest"
+)
+
+func ExampleCandleBuilder() {
+	candles := sapantest.NewCandleBuilder(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 100).
+		Trend(3, 2).
+		Candle(106, 107, 101, 102).
+		Candles()
+	for _, candle := range candles {
+		fmt.Printf("%s %s %.2f\n", candle.Date.Format("2006-01-02"), candle.Date.Weekday(), candle.Close)
+	}
+	// Output:
+	// 2024-01-05 Friday 102.00
+	// 2024-01-08 Monday 104.00
+	// 2024-01-09 Tuesday 106.00
+	// 2024-01-10 Wednesday 102.00
+}
+
+func ExampleFetcher() {
+	fetcher := sapantest.NewFetcher().SetCandles("AAPL", sapantest.Uptrend(300))
+	candleData, err := fetcher.FetchStockData(context.Background(), "AAPL", 250)
+	fmt.Println(len(candleData.Candles), err)
+	_, err = fetcher.FetchStockData(context.Background(), "MSFT", 250)
+	fmt.Println(err)
+	// Output:
+	// 250 <nil>
+	// no candles for MSFT
+}
@@ -0,0 +1,75 @@
+package sapantest
+
+import (
+	"context"
+	"fmt"
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/models"
+	"sync"
+)
+
+var _ data.Fetcher = (*Fetcher)(nil)
+
+// Fetcher is an in-memory data.Fetcher serving canned candles per symbol
+// It is safe for concurrent use by processor workers
+type Fetcher struct {
+	candles map[string][]models.Candle // Candles served per symbol
+	errors  map[string]error           // Errors returned per symbol
+	calls   map[string]int             // Number of fetches per symbol
+	mutex   sync.Mutex                 // Guards every map
+}
+
+// NewFetcher creates a fetcher with no candles; unknown symbols return an error
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		candles: make(map[string][]models.Candle), // Initialize the candle map
+		errors:  make(map[string]error),           // Initialize the error map
+		calls:   make(map[string]int),             // Initialize the call counter
+	}
+}
+
+// SetCandles makes the fetcher serve candles for symbol
+func (f *Fetcher) SetCandles(symbol string, candles []models.Candle) *Fetcher {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.candles[symbol] = candles
+	return f
+}
+
+// SetError makes every fetch of symbol fail with err
+func (f *Fetcher) SetError(symbol string, err error) *Fetcher {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.errors[symbol] = err
+	return f
+}
+
+// FetchStockData returns the newest outputSize candles of symbol (all of them when outputSize is not positive)
+// A done ctx fails the fetch with its error, as a cancelled HTTP request would
+func (f *Fetcher) FetchStockData(ctx context.Context, symbol string, outputSize int) (models.CandleData, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls[symbol]++
+
+	if err := ctx.Err(); err != nil {
+		return models.CandleData{}, err
+	}
+	if err := f.errors[symbol]; err != nil {
+		return models.CandleData{}, err
+	}
+	candles, ok := f.candles[symbol]
+	if !ok {
+		return models.CandleData{}, fmt.Errorf("no candles for %s", symbol)
+	}
+	if outputSize > 0 && len(candles) > outputSize {
+		candles = candles[len(candles)-outputSize:]
+	}
+	return models.CandleData{Candles: append([]models.Candle(nil), candles...)}, nil
+}
+
+// Calls returns how many times symbol was fetched
+func (f *Fetcher) Calls(symbol string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls[symbol]
+}
@@ -0,0 +1,57 @@
+package sapantest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Golden compares got, encoded as indented JSON, against the golden file at path
+// With update set the file is rewritten instead; tests usually wire update to their own -update flag
+func Golden(t testing.TB, path string, got interface{}, update bool) {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false) // Keep validation messages like "20 > 50" readable
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(got); err != nil {
+		t.Fatalf("failed to encode golden value for %s: %v", path, err)
+	}
+	encoded := buffer.Bytes()
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, encoded, 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run the test with -update to create it): %v", err)
+	}
+	if bytes.Equal(want, encoded) {
+		return
+	}
+
+	wantLines := bytes.Split(want, []byte("\n"))
+	gotLines := bytes.Split(encoded, []byte("\n"))
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine []byte
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if !bytes.Equal(wantLine, gotLine) {
+			t.Fatalf("%s differs at line %d:\n  want: %s\n   got: %s\nrerun with -update if the change is intended", path, i+1, bytes.TrimSpace(wantLine), bytes.TrimSpace(gotLine))
+		}
+	}
+}
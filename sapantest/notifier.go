@@ -0,0 +1,51 @@
+package sapantest
+
+import (
+	"github.com/erhankrygt/sapan/internal/notify"
+	"sync"
+)
+
+var _ notify.Notifier = (*Notifier)(nil)
+
+// Notifier is a notify.Notifier that keeps every delivered event
+type Notifier struct {
+	name   string         // Name reported to the dispatcher
+	events []notify.Event // Delivered events, in delivery order
+	err    error          // Error returned by Notify (nil delivers normally)
+	mutex  sync.Mutex     // Guards events and err
+}
+
+// NewNotifier creates a recording notifier reporting name in logs
+func NewNotifier(name string) *Notifier {
+	return &Notifier{name: name}
+}
+
+// SetError makes Notify fail with err without recording the event
+func (n *Notifier) SetError(err error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.err = err
+}
+
+// Name returns the notifier name used in logs
+func (n *Notifier) Name() string {
+	return n.name
+}
+
+// Notify records the event
+func (n *Notifier) Notify(event notify.Event) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+// Events returns a copy of every delivered event
+func (n *Notifier) Events() []notify.Event {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]notify.Event(nil), n.events...)
+}
@@ -0,0 +1,78 @@
+package sapantest
+
+import (
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+	"sync"
+)
+
+var _ strategy.Validator = (*Strategy)(nil)
+
+// Strategy is a strategy.Validator returning scripted results per symbol
+// Symbols without a scripted result fail validation, so tests only script the setups they expect
+type Strategy struct {
+	long  map[string]strategy.ValidationResult // Long results per symbol
+	short map[string]strategy.ValidationResult // Short results per symbol
+	mutex sync.RWMutex                         // Guards both maps
+}
+
+// NewStrategy creates a strategy that rejects every symbol until results are scripted
+func NewStrategy() *Strategy {
+	return &Strategy{
+		long:  make(map[string]strategy.ValidationResult), // Initialize the Long results
+		short: make(map[string]strategy.ValidationResult), // Initialize the Short results
+	}
+}
+
+// SetLong scripts the Long validation result of symbol
+func (s *Strategy) SetLong(symbol string, result strategy.ValidationResult) *Strategy {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result.Symbol = symbol
+	s.long[symbol] = result
+	return s
+}
+
+// SetShort scripts the Short validation result of symbol
+func (s *Strategy) SetShort(symbol string, result strategy.ValidationResult) *Strategy {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result.Symbol = symbol
+	s.short[symbol] = result
+	return s
+}
+
+// ValidateLongSetup returns the scripted Long result of symbol
+func (s *Strategy) ValidateLongSetup(symbol string, candles []models.Candle) strategy.ValidationResult {
+	return s.result(s.long, symbol)
+}
+
+// ValidateShortSetup returns the scripted Short result of symbol
+func (s *Strategy) ValidateShortSetup(symbol string, candles []models.Candle) strategy.ValidationResult {
+	return s.result(s.short, symbol)
+}
+
+// result looks up a scripted result, falling back to a rejection
+func (s *Strategy) result(results map[string]strategy.ValidationResult, symbol string) strategy.ValidationResult {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if result, ok := results[symbol]; ok {
+		return result
+	}
+	return strategy.ValidationResult{Symbol: symbol, ValidationMessage: "no scripted setup"}
+}
+
+// ValidSetup returns a passing validation result with the given pattern, trade plan, and a score of 75
+func ValidSetup(pattern strategy.PatternType, entry, stop, target float64) strategy.ValidationResult {
+	return strategy.ValidationResult{
+		IsValid:           true,
+		EMATrendValid:     true,
+		StochasticValid:   true,
+		MACDValid:         true,
+		PatternValid:      true,
+		PatternType:       pattern,
+		ValidationMessage: "scripted setup",
+		Score:             75,
+		TradePlan:         strategy.TradePlan{Entry: entry, Stop: stop, Target: target},
+	}
+}
@@ -0,0 +1,48 @@
+package sapantest
+
+import (
+	"github.com/erhankrygt/sapan/watcher"
+	"sync"
+)
+
+var _ watcher.WatchList = (*WatchList)(nil)
+
+// WatchList is a watcher.WatchList that keeps every recorded signal for assertions
+// Entries are deduplicated by the embedded WatchListManager exactly like a real scan
+type WatchList struct {
+	*watcher.WatchListManager                  // Real in-memory watch list serving the entries
+	signals                   []watcher.Signal // Every recorded signal, in recording order
+	err                       error            // Error returned by RecordSignal (nil records normally)
+	mutex                     sync.Mutex       // Guards signals and err
+}
+
+// NewWatchList creates an empty recording watch list
+func NewWatchList() *WatchList {
+	return &WatchList{WatchListManager: watcher.NewWatchListManager()}
+}
+
+// SetError makes RecordSignal fail with err without recording the signal
+func (w *WatchList) SetError(err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.err = err
+}
+
+// RecordSignal keeps the signal and adds it to the embedded watch list
+func (w *WatchList) RecordSignal(signal watcher.Signal) error {
+	w.mutex.Lock()
+	if w.err != nil {
+		defer w.mutex.Unlock()
+		return w.err
+	}
+	w.signals = append(w.signals, signal)
+	w.mutex.Unlock()
+	return w.WatchListManager.RecordSignal(signal)
+}
+
+// Signals returns a copy of every recorded signal
+func (w *WatchList) Signals() []watcher.Signal {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]watcher.Signal(nil), w.signals...)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+// setupScanDir prepares a quiet offline scan of CSV candles in a temporary working directory
+// Every symbol gets a listed Long setup and the stock list names every symbol; only symbols with candles can be fetched
+func setupScanDir(t *testing.T, listed []string, withCandles ...string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("PROVIDER", "csv")
+	t.Setenv("CSV_DIR", "candles")
+	t.Setenv("SKIP_CLOSED_DAYS", "false")
+	t.Setenv("OUTPUT_MODE", "quiet")
+	t.Setenv("REQUEST_DELAY_SECONDS", "0")
+	t.Setenv("STOCKS_FILE", "stocks.json")
+	t.Setenv("WATCHLIST_FILE", "watchlist.json")
+
+	if err := os.MkdirAll("candles", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, symbol := range withCandles {
+		writeCandleCSV(t, filepath.Join("candles", symbol+".csv"), sapantest.Uptrend(300))
+	}
+
+	stocks := `{"Stocks":[`
+	watchList := watcher.NewWatchListManager()
+	for i, symbol := range listed {
+		if i > 0 {
+			stocks += ","
+		}
+		stocks += fmt.Sprintf(`{"symbol":%q,"name":%q,"sector":"Technology"}`, symbol, symbol)
+		watchList.RecordSignal(watcher.Signal{Symbol: symbol, Side: watcher.LongSide, Score: 80, Entry: 100, Stop: 95, Target: 110})
+	}
+	if err := os.WriteFile("stocks.json", []byte(stocks+"]}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := watchList.Save("watchlist.json"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeCandleCSV writes candles in the csv provider's default layout
+func writeCandleCSV(t *testing.T, path string, candles []models.Candle) {
+	t.Helper()
+	content := "date,open,high,low,close,volume\n"
+	for _, candle := range candles {
+		content += fmt.Sprintf("%s,%.4f,%.4f,%.4f,%.4f,%d\n", candle.Date.Format("2006-01-02"),
+			candle.Open, candle.High, candle.Low, candle.Close, candle.Volume)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// savedWatchList returns the symbols of the watch list the scan persisted
+func savedWatchList(t *testing.T) map[string]bool {
+	t.Helper()
+	watchList := watcher.NewWatchListManager()
+	if err := watchList.Load("watchlist.json"); err != nil {
+		t.Fatal(err)
+	}
+	symbols := make(map[string]bool)
+	for _, entry := range watchList.GetEntries() {
+		symbols[entry.Symbol] = true
+	}
+	return symbols
+}
+
+func TestScanKeepsSetupsOfStocksThatFailedToFetch(t *testing.T) {
+	setupScanDir(t, []string{"AAPL", "MSFT"}, "AAPL") // MSFT has no candle file, so its fetch fails
+
+	cfg, _, ok := loadConfig(nil)
+	if !ok {
+		t.Fatal("configuration did not load")
+	}
+	summary, _, _ := scan(cfg, nil, scanHooks{})
+	if summary.Total != 2 || summary.Errors != 1 {
+		t.Fatalf("summary = %d total, %d errors; want MSFT to fail", summary.Total, summary.Errors)
+	}
+
+	saved := savedWatchList(t)
+	if !saved["MSFT"] {
+		t.Error("the setup of MSFT, which failed to fetch, was dropped from the watch list")
+	}
+	if saved["AAPL"] {
+		t.Error("the setup of AAPL, analyzed without detecting it, stayed on the watch list")
+	}
+}
+
+func TestScanOfAdHocSymbolsKeepsOtherSetups(t *testing.T) {
+	setupScanDir(t, []string{"AAPL", "MSFT", "TSLA"}, "AAPL", "MSFT", "TSLA")
+
+	cfg, _, ok := loadConfig(nil)
+	if !ok {
+		t.Fatal("configuration did not load")
+	}
+	summary, _, _ := scan(cfg, []string{"AAPL"}, scanHooks{})
+	if summary.Total != 1 {
+		t.Fatalf("%d stocks scanned, want only AAPL", summary.Total)
+	}
+
+	saved := savedWatchList(t)
+	if !saved["MSFT"] || !saved["TSLA"] {
+		t.Errorf("watch list = %v, want the setups of MSFT and TSLA, which were not scanned, kept", saved)
+	}
+	if saved["AAPL"] {
+		t.Error("the setup of AAPL, scanned without detecting it, stayed on the watch list")
+	}
+}
+
+func TestScanOfSubsetKeepsOtherSetups(t *testing.T) {
+	for _, flag := range []string{"--limit", "--sample"} {
+		setupScanDir(t, []string{"AAPL", "MSFT", "TSLA"}, "AAPL", "MSFT", "TSLA")
+
+		cfg, _, ok := loadConfig([]string{flag, "1"})
+		if !ok {
+			t.Fatal("configuration did not load")
+		}
+		summary, _, _ := scan(cfg, nil, scanHooks{})
+		if summary.Total != 1 || summary.Successful != 1 {
+			t.Fatalf("%s 1: %d stocks scanned, %d successfully; want one", flag, summary.Total, summary.Successful)
+		}
+
+		saved := savedWatchList(t)
+		if len(saved) != 2 || saved[summary.Results[0].Symbol] {
+			t.Errorf("%s 1: watch list = %v, want only the setup of the scanned %s dropped", flag, saved, summary.Results[0].Symbol)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/internal/api"
+	"github.com/erhankrygt/sapan/internal/grpcapi"
+	"github.com/erhankrygt/sapan/internal/grpcapi/sapanv1"
+	"github.com/erhankrygt/sapan/internal/processor"
+	"github.com/erhankrygt/sapan/internal/report"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+)
+
+// runServer serves the REST API until the listener fails
+func runServer(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+
+	var signalStore *watcher.SQLiteSignalStore
+	if cfg.SignalDBPath != "" {
+		var err error
+		if signalStore, err = openSignalStore(cfg); err != nil {
+			log.Printf("Failed to open signal database: %v", err)
+			return exitFailure
+		}
+		defer signalStore.Close()
+	}
+
+	hub := grpcapi.NewSignalHub()
+	server := api.NewServer(func(started func(*processor.StockProcessor)) (*report.RunResult, int) {
+		_, result, code := scan(cfg, nil, scanHooks{started: started, signal: hub.Publish})
+		return result, code
+	}, cfg.WatchListFile, cfg.StocksFile, signalStore, cfg.APIToken)
+
+	// The gRPC service shares the scan coordinator, so scans started over either API never overlap
+	if cfg.GRPCAddr != "" {
+		listener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Printf("Failed to listen on %s: %v", cfg.GRPCAddr, err)
+			return exitFailure
+		}
+		grpcServer := grpc.NewServer(grpcapi.ServerOptions(cfg.APIToken)...)
+		stockFetcher := data.NewStockDataFetcher(cfg.APIKey, cfg.APIURL, cfg.Timeframe)
+		stockFetcher.SetAdjusted(cfg.AdjustedSeries, cfg.AdjustedPrices)
+		stockFetcher.SetHTTPClient(httpClient(cfg))
+		stockFetcher.SetRetryPolicy(retryPolicy(cfg))
+		sapanv1.RegisterSapanServiceServer(grpcServer, grpcapi.NewServer(server, hub, stockFetcher, cfg.WatchListFile))
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("⚠️  gRPC server stopped: %v", err)
+			}
+		}()
+		log.Printf("🔌 Serving the SAPAN gRPC API on %s", cfg.GRPCAddr)
+	}
+
+	if cfg.APIToken == "" {
+		log.Printf("⚠️  API_TOKEN is not set; the API accepts unauthenticated requests")
+	}
+	log.Printf("🌐 Serving the SAPAN API on %s", cfg.ServeAddr)
+	if err := http.ListenAndServe(cfg.ServeAddr, server.Handler()); err != nil {
+		log.Printf("API server stopped: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
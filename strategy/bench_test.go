@@ -0,0 +1,28 @@
+package strategy_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// BenchmarkValidateSetups validates both sides of each fixture's full history, as a scan does per symbol
+func BenchmarkValidateSetups(b *testing.B) {
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+	for _, symbol := range []string{"UPTREND", "DOWNTREND", "SIDEWAYS"} {
+		candleData, err := store.Load(symbol)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sapanStrategy := strategy.NewSAPANStrategy()
+		b.Run(symbol, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sapanStrategy.ValidateLongSetup(symbol, candleData.Candles)
+				sapanStrategy.ValidateShortSetup(symbol, candleData.Candles)
+			}
+		})
+	}
+}
@@ -0,0 +1,525 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import (
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+)
+
+// confirmationATRPeriod is the ATR period the confirmation candle's range is compared against
+const confirmationATRPeriod = 14
+
+// ConfirmationRules tunes how strictly the candle after a reversal or pinbar confirms it
+// The zero value is not useful; start from DefaultConfirmationRules, which is the classic SAPAN rule
+type ConfirmationRules struct {
+	ClosePercent      float64 // Share of the reversal range, from its low (Long) or high (Short), the close must pass; 100 means beyond the reversal high or low
+	RequireRisingLows bool    // Require a higher low (Long) or lower high (Short) than the reversal candle
+	MaxRangeATR       float64 // Largest confirmation candle range as a multiple of the 14-period ATR (0 disables)
+}
+
+// DefaultConfirmationRules returns the classic rule: a close beyond the reversal extreme with rising lows or falling highs
+func DefaultConfirmationRules() ConfirmationRules {
+	return ConfirmationRules{ClosePercent: 100, RequireRisingLows: true}
+}
+
+// PatternThresholds sets the size thresholds of pinbars and reversal tails
+// Ratios compare a candle with its own range; the ATR multiples, when set, replace them so pattern sizes are measured
+// against the symbol's volatility instead. Start from DefaultPatternThresholds, which is the classic SAPAN shape
+type PatternThresholds struct {
+	BodyRatio float64 // Largest pinbar body as a share of the pinbar's range
+	WickRatio float64 // Smallest pinbar wick (lower for Long, upper for Short) as a share of the pinbar's range
+	BodyATR   float64 // Largest pinbar body in multiples of the 14-period ATR (0 uses BodyRatio)
+	WickATR   float64 // Smallest pinbar wick in multiples of the 14-period ATR (0 uses WickRatio)
+	PierceATR float64 // Smallest depth reversal tails must reach past the support or resistance EMA, in ATRs (0 accepts any pierce)
+}
+
+// DefaultPatternThresholds returns the classic pinbar shape (body at most 30% and wick at least 60% of the range)
+// with any pierce of the EMA accepted
+func DefaultPatternThresholds() PatternThresholds {
+	return PatternThresholds{BodyRatio: 0.3, WickRatio: 0.6}
+}
+
+// usesATR reports whether any threshold is measured in ATRs
+func (t PatternThresholds) usesATR() bool {
+	return t.BodyATR > 0 || t.WickATR > 0 || t.PierceATR > 0
+}
+
+// EMAReference selects which EMA of the stack reversal tails must pierce as support or resistance
+type EMAReference int
+
+const (
+	ExtremeEMA EMAReference = iota // Lowest EMA as support and highest EMA as resistance (classic SAPAN)
+	NearestEMA                     // EMA closest to the reversal candle's close, so pullbacks in strong trends qualify
+)
+
+// String returns the name of the EMA reference ("extreme" or "nearest")
+func (r EMAReference) String() string {
+	if r == NearestEMA {
+		return "nearest"
+	}
+	return "extreme"
+}
+
+// CandlestickPatternDetector handles candlestick pattern detection for the SAPAN strategy
+// This struct provides methods to detect various reversal patterns including 2-candlestick and pinbar patterns
+type CandlestickPatternDetector struct {
+	confirmation  ConfirmationRules         // Rules the confirmation candle is judged by
+	thresholds    PatternThresholds         // Pinbar shape and tail pierce thresholds
+	emaReference  EMAReference              // EMA used as support and resistance
+	disabled      map[PatternType]bool      // Patterns DetectAllPatterns skips
+	atrCalculator *indicators.ATRCalculator // ATR calculator for the confirmation range limit and ATR thresholds
+}
+
+// NewCandlestickPatternDetector creates a new candlestick pattern detector instance
+// This constructor initializes the detector for identifying trading patterns
+func NewCandlestickPatternDetector() *CandlestickPatternDetector {
+	return &CandlestickPatternDetector{
+		confirmation:  DefaultConfirmationRules(),    // Initialize the classic confirmation rules
+		thresholds:    DefaultPatternThresholds(),    // Initialize the classic pinbar shape
+		disabled:      make(map[PatternType]bool),    // Initialize with every pattern enabled
+		atrCalculator: indicators.NewATRCalculator(), // Initialize ATR calculator
+	}
+}
+
+// SetConfirmationRules changes how strictly confirmation candles are judged
+func (c *CandlestickPatternDetector) SetConfirmationRules(rules ConfirmationRules) {
+	c.confirmation = rules
+}
+
+// SetPatternThresholds changes the pinbar shape and tail pierce thresholds
+func (c *CandlestickPatternDetector) SetPatternThresholds(thresholds PatternThresholds) {
+	c.thresholds = thresholds
+}
+
+// SetPatternEnabled enables or disables one reversal pattern; every pattern is enabled by default
+// Disabled patterns are never reported by DetectAllPatterns, so they cannot validate a setup
+func (c *CandlestickPatternDetector) SetPatternEnabled(pattern PatternType, enabled bool) {
+	if enabled {
+		delete(c.disabled, pattern)
+		return
+	}
+	c.disabled[pattern] = true
+}
+
+// PatternEnabled reports whether a reversal pattern is detected
+func (c *CandlestickPatternDetector) PatternEnabled(pattern PatternType) bool {
+	return !c.disabled[pattern]
+}
+
+// SetEMAReference selects the EMA used as support and resistance; the default is ExtremeEMA
+func (c *CandlestickPatternDetector) SetEMAReference(reference EMAReference) {
+	c.emaReference = reference
+}
+
+// PatternType represents the type of pattern detected by the pattern detector
+// This enum helps identify which specific pattern was found during analysis
+type PatternType int
+
+const (
+	NoPattern                 PatternType = iota // No valid pattern detected
+	Long2CandlestickReversal                     // 2-candlestick bullish reversal pattern
+	Short2CandlestickReversal                    // 2-candlestick bearish reversal pattern
+	LongPinbarReversal                           // Bullish pinbar reversal pattern
+	ShortPinbarReversal                          // Bearish pinbar reversal pattern
+)
+
+// String returns a human-readable name for the pattern type
+// This is used when patterns are logged, stored, or exported
+func (p PatternType) String() string {
+	switch p {
+	case Long2CandlestickReversal:
+		return "Long 2-Candlestick Reversal"
+	case Short2CandlestickReversal:
+		return "Short 2-Candlestick Reversal"
+	case LongPinbarReversal:
+		return "Long Pinbar Reversal"
+	case ShortPinbarReversal:
+		return "Short Pinbar Reversal"
+	default:
+		return "No Pattern"
+	}
+}
+
+// DetectPatterns detects all possible patterns against the EMAs of a precomputed indicator snapshot
+func (c *CandlestickPatternDetector) DetectPatterns(candles []models.Candle, snapshot IndicatorSnapshot) PatternType {
+	return c.DetectAllPatterns(candles, snapshot.EMAs...)
+}
+
+// DetectAllPatterns detects all enabled patterns (long and short, 1 and 2 candlestick) against an EMA stack, fastest first
+func (c *CandlestickPatternDetector) DetectAllPatterns(candles []models.Candle, emas ...float64) PatternType {
+	if len(candles) < 3 || len(emas) == 0 {
+		return NoPattern
+	}
+
+	// Check for 2-candlestick patterns first
+	if c.PatternEnabled(Long2CandlestickReversal) && c.DetectLong2CandlestickReversal(candles, emas...) {
+		return Long2CandlestickReversal
+	}
+
+	if c.PatternEnabled(Short2CandlestickReversal) && c.DetectShort2CandlestickReversal(candles, emas...) {
+		return Short2CandlestickReversal
+	}
+
+	// Check for 1-candlestick pinbar patterns
+	if c.PatternEnabled(LongPinbarReversal) && c.DetectLongPinbarReversal(candles, emas...) {
+		return LongPinbarReversal
+	}
+
+	if c.PatternEnabled(ShortPinbarReversal) && c.DetectShortPinbarReversal(candles, emas...) {
+		return ShortPinbarReversal
+	}
+
+	return NoPattern
+}
+
+// DetectLong2CandlestickReversal detects long 2-candlestick reversal pattern
+func (c *CandlestickPatternDetector) DetectLong2CandlestickReversal(candles []models.Candle, emas ...float64) bool {
+	if len(candles) < 3 || len(emas) == 0 {
+		return false
+	}
+
+	// Get the last 3 candles (we need at least 2 bearish + 1 bullish confirmation)
+	lastCandle := candles[len(candles)-1]   // Confirmation candle
+	secondCandle := candles[len(candles)-2] // Reversal candle
+	firstCandle := candles[len(candles)-3]  // Previous bear candle
+
+	// Rule A: Reversal candle body should be above EMA support
+	if !c.isReversalBodyAboveSupport(secondCandle, emas) {
+		return false
+	}
+
+	// Rule B: Reversal candle tail should pierce EMA support and previous bear candle low
+	if !c.isTailPiercingSupport(secondCandle, firstCandle, c.patternATR(candles), emas) {
+		return false
+	}
+
+	// Rule C: After reversal candle, we need rising lows and bullish confirmation
+	if !c.isBullishConfirmation(lastCandle, secondCandle, c.confirmationATR(candles)) {
+		return false
+	}
+
+	return true
+}
+
+// DetectShort2CandlestickReversal detects short 2-candlestick reversal pattern
+func (c *CandlestickPatternDetector) DetectShort2CandlestickReversal(candles []models.Candle, emas ...float64) bool {
+	if len(candles) < 3 || len(emas) == 0 {
+		return false
+	}
+
+	// Get the last 3 candles (we need at least 2 bullish + 1 bearish confirmation)
+	lastCandle := candles[len(candles)-1]   // Confirmation candle
+	secondCandle := candles[len(candles)-2] // Reversal candle
+	firstCandle := candles[len(candles)-3]  // Previous bull candle
+
+	// Rule A: Reversal candle body should be below EMA resistance
+	if !c.isReversalBodyBelowResistance(secondCandle, emas) {
+		return false
+	}
+
+	// Rule B: Reversal candle tail should pierce EMA resistance and previous bull candle high
+	if !c.isTailPiercingResistance(secondCandle, firstCandle, c.patternATR(candles), emas) {
+		return false
+	}
+
+	// Rule C: After reversal candle, we need falling highs and bearish confirmation
+	if !c.isBearishConfirmation(lastCandle, secondCandle, c.confirmationATR(candles)) {
+		return false
+	}
+
+	return true
+}
+
+// DetectLongPinbarReversal detects long pinbar reversal pattern
+func (c *CandlestickPatternDetector) DetectLongPinbarReversal(candles []models.Candle, emas ...float64) bool {
+	if len(candles) < 3 || len(emas) == 0 {
+		return false
+	}
+
+	// Get the last 2 candles (pinbar + confirmation)
+	pinbar := candles[len(candles)-2]       // Pinbar candle
+	confirmation := candles[len(candles)-1] // Confirmation candle
+
+	// Check if it's a bullish pinbar (small body, long lower wick)
+	atr := c.patternATR(candles)
+	if !c.isBullishPinbar(pinbar, atr) {
+		return false
+	}
+
+	// Rule A: Pinbar body should be above EMA support
+	emaSupport := c.supportEMA(pinbar, emas)
+	pinbarBody := (pinbar.Open + pinbar.Close) / 2
+	if pinbarBody <= emaSupport {
+		return false
+	}
+
+	// Rule B: Pinbar tail should pierce EMA support
+	if !c.isPierceDeepEnough(emaSupport-pinbar.Low, atr) {
+		return false
+	}
+
+	// Rule C: Confirmation candle should be bullish and close above pinbar high
+	if !c.isBullishConfirmation(confirmation, pinbar, c.confirmationATR(candles)) {
+		return false
+	}
+
+	return true
+}
+
+// DetectShortPinbarReversal detects short pinbar reversal pattern
+func (c *CandlestickPatternDetector) DetectShortPinbarReversal(candles []models.Candle, emas ...float64) bool {
+	if len(candles) < 3 || len(emas) == 0 {
+		return false
+	}
+
+	// Get the last 2 candles (pinbar + confirmation)
+	pinbar := candles[len(candles)-2]       // Pinbar candle
+	confirmation := candles[len(candles)-1] // Confirmation candle
+
+	// Check if it's a bearish pinbar (small body, long upper wick)
+	atr := c.patternATR(candles)
+	if !c.isBearishPinbar(pinbar, atr) {
+		return false
+	}
+
+	// Rule A: Pinbar body should be below EMA resistance
+	emaResistance := c.resistanceEMA(pinbar, emas)
+	pinbarBody := (pinbar.Open + pinbar.Close) / 2
+	if pinbarBody >= emaResistance {
+		return false
+	}
+
+	// Rule B: Pinbar tail should pierce EMA resistance
+	if !c.isPierceDeepEnough(pinbar.High-emaResistance, atr) {
+		return false
+	}
+
+	// Rule C: Confirmation candle should be bearish and close below pinbar low
+	if !c.isBearishConfirmation(confirmation, pinbar, c.confirmationATR(candles)) {
+		return false
+	}
+
+	return true
+}
+
+// isReversalBodyAboveSupport checks if reversal candle body is above EMA support
+func (c *CandlestickPatternDetector) isReversalBodyAboveSupport(candle models.Candle, emas []float64) bool {
+	// We use the lowest (or nearest) EMA as support level
+	emaSupport := c.supportEMA(candle, emas)
+
+	// Check if reversal candle body is above EMA support
+	reversalBody := (candle.Open + candle.Close) / 2
+	return reversalBody > emaSupport
+}
+
+// isTailPiercingSupport checks if tail pierces support levels
+func (c *CandlestickPatternDetector) isTailPiercingSupport(reversalCandle, previousCandle models.Candle, atr float64, emas []float64) bool {
+	emaSupport := c.supportEMA(reversalCandle, emas)
+	reversalLow := reversalCandle.Low
+	previousBearLow := previousCandle.Low
+
+	// Tail should pierce both EMA support and previous bear candle low (compared at tick precision)
+	return c.isPierceDeepEnough(emaSupport-reversalLow, atr) && models.ComparePrices(reversalLow, previousBearLow) < 0
+}
+
+// isPierceDeepEnough checks whether a tail reaching depth past the EMA pierces it by the configured ATR multiple
+func (c *CandlestickPatternDetector) isPierceDeepEnough(depth, atr float64) bool {
+	return depth > 0 && depth >= c.thresholds.PierceATR*atr
+}
+
+// patternATR returns the ATR of the candles before the confirmation candle, or 0 when no threshold uses it
+func (c *CandlestickPatternDetector) patternATR(candles []models.Candle) float64 {
+	if !c.thresholds.usesATR() {
+		return 0
+	}
+	return c.atrCalculator.Calculate(candles[:len(candles)-1], confirmationATRPeriod)
+}
+
+// confirmationATR returns the ATR of the candles before the confirmation candle, or 0 when the range limit is off
+func (c *CandlestickPatternDetector) confirmationATR(candles []models.Candle) float64 {
+	if c.confirmation.MaxRangeATR <= 0 {
+		return 0
+	}
+	return c.atrCalculator.Calculate(candles[:len(candles)-1], confirmationATRPeriod)
+}
+
+// bullishCloseLevel returns the price a bullish confirmation must close above
+func (c *CandlestickPatternDetector) bullishCloseLevel(reversalCandle models.Candle) float64 {
+	if c.confirmation.ClosePercent == 100 {
+		return reversalCandle.High
+	}
+	return reversalCandle.Low + reversalCandle.Range()*c.confirmation.ClosePercent/100
+}
+
+// bearishCloseLevel returns the price a bearish confirmation must close below
+func (c *CandlestickPatternDetector) bearishCloseLevel(reversalCandle models.Candle) float64 {
+	if c.confirmation.ClosePercent == 100 {
+		return reversalCandle.Low
+	}
+	return reversalCandle.High - reversalCandle.Range()*c.confirmation.ClosePercent/100
+}
+
+// isConfirmationRangeAllowed checks the confirmation candle's range against the ATR limit
+// A missing ATR (too little history) does not reject the candle
+func (c *CandlestickPatternDetector) isConfirmationRangeAllowed(confirmationCandle models.Candle, atr float64) bool {
+	return c.confirmation.MaxRangeATR <= 0 || atr <= 0 || confirmationCandle.Range() <= c.confirmation.MaxRangeATR*atr
+}
+
+// isBullishConfirmation checks for bullish confirmation pattern under the configured confirmation rules
+func (c *CandlestickPatternDetector) isBullishConfirmation(confirmationCandle, reversalCandle models.Candle, atr float64) bool {
+	// Confirmation candle should close above reversal candle high (or the configured share of its range)
+	if models.ComparePrices(confirmationCandle.Close, c.bullishCloseLevel(reversalCandle)) <= 0 {
+		return false
+	}
+
+	// Additional check: Confirmation candle should be bullish (green)
+	if models.ComparePrices(confirmationCandle.Close, confirmationCandle.Open) <= 0 {
+		return false
+	}
+
+	// Overextended confirmation candles leave too wide a stop
+	if !c.isConfirmationRangeAllowed(confirmationCandle, atr) {
+		return false
+	}
+
+	// Check for rising lows (confirmation candle low should be higher than reversal candle low)
+	return !c.confirmation.RequireRisingLows || models.ComparePrices(confirmationCandle.Low, reversalCandle.Low) > 0
+}
+
+// supportEMA returns the EMA a reversal candle is measured against as support
+func (c *CandlestickPatternDetector) supportEMA(reversalCandle models.Candle, emas []float64) float64 {
+	if c.emaReference == NearestEMA {
+		return c.getNearestEMA(reversalCandle.Close, emas)
+	}
+	return c.getLowestEMA(emas)
+}
+
+// resistanceEMA returns the EMA a reversal candle is measured against as resistance
+func (c *CandlestickPatternDetector) resistanceEMA(reversalCandle models.Candle, emas []float64) float64 {
+	if c.emaReference == NearestEMA {
+		return c.getNearestEMA(reversalCandle.Close, emas)
+	}
+	return c.getHighestEMA(emas)
+}
+
+// getNearestEMA returns the EMA value closest to price, preferring the faster EMA on ties
+func (c *CandlestickPatternDetector) getNearestEMA(price float64, emas []float64) float64 {
+	nearest := emas[0]
+	for _, ema := range emas[1:] {
+		if abs(ema-price) < abs(nearest-price) {
+			nearest = ema
+		}
+	}
+	return nearest
+}
+
+// getLowestEMA returns the lowest EMA value
+func (c *CandlestickPatternDetector) getLowestEMA(emas []float64) float64 {
+	emaSupport := emas[0]
+	for _, ema := range emas[1:] {
+		emaSupport = min(emaSupport, ema)
+	}
+	return emaSupport
+}
+
+// getHighestEMA returns the highest EMA value
+func (c *CandlestickPatternDetector) getHighestEMA(emas []float64) float64 {
+	emaResistance := emas[0]
+	for _, ema := range emas[1:] {
+		emaResistance = max(emaResistance, ema)
+	}
+	return emaResistance
+}
+
+// isReversalBodyBelowResistance checks if reversal candle body is below EMA resistance
+func (c *CandlestickPatternDetector) isReversalBodyBelowResistance(candle models.Candle, emas []float64) bool {
+	emaResistance := c.resistanceEMA(candle, emas)
+	reversalBody := (candle.Open + candle.Close) / 2
+	return reversalBody < emaResistance
+}
+
+// isTailPiercingResistance checks if tail pierces resistance levels
+func (c *CandlestickPatternDetector) isTailPiercingResistance(reversalCandle, previousCandle models.Candle, atr float64, emas []float64) bool {
+	emaResistance := c.resistanceEMA(reversalCandle, emas)
+	reversalHigh := reversalCandle.High
+	previousBullHigh := previousCandle.High
+
+	// Tail should pierce both EMA resistance and previous bull candle high (compared at tick precision)
+	return c.isPierceDeepEnough(reversalHigh-emaResistance, atr) && models.ComparePrices(reversalHigh, previousBullHigh) > 0
+}
+
+// isBearishConfirmation checks for bearish confirmation pattern under the configured confirmation rules
+func (c *CandlestickPatternDetector) isBearishConfirmation(confirmationCandle, reversalCandle models.Candle, atr float64) bool {
+	// Confirmation candle should close below reversal candle low (or the configured share of its range)
+	if models.ComparePrices(confirmationCandle.Close, c.bearishCloseLevel(reversalCandle)) >= 0 {
+		return false
+	}
+
+	// Additional check: Confirmation candle should be bearish (red)
+	if models.ComparePrices(confirmationCandle.Close, confirmationCandle.Open) >= 0 {
+		return false
+	}
+
+	// Overextended confirmation candles leave too wide a stop
+	if !c.isConfirmationRangeAllowed(confirmationCandle, atr) {
+		return false
+	}
+
+	// Check for falling highs (confirmation candle high should be lower than reversal candle high)
+	return !c.confirmation.RequireRisingLows || models.ComparePrices(confirmationCandle.High, reversalCandle.High) < 0
+}
+
+// isBullishPinbar checks if candle is a bullish pinbar
+func (c *CandlestickPatternDetector) isBullishPinbar(candle models.Candle, atr float64) bool {
+	return c.isPinbarShape(candle, candle.LowerWick(), atr)
+}
+
+// isBearishPinbar checks if candle is a bearish pinbar
+func (c *CandlestickPatternDetector) isBearishPinbar(candle models.Candle, atr float64) bool {
+	return c.isPinbarShape(candle, candle.UpperWick(), atr)
+}
+
+// isPinbarShape checks for a small body and a long wick, measured in ATRs where configured and as shares of the range
+// otherwise; the ratios also apply while too few candles leave the ATR at 0
+func (c *CandlestickPatternDetector) isPinbarShape(candle models.Candle, wick, atr float64) bool {
+	t := c.thresholds
+	totalRange := candle.Range()
+
+	// Small body relative to the ATR or the total range
+	if t.BodyATR > 0 && atr > 0 {
+		if candle.Body() > t.BodyATR*atr {
+			return false
+		}
+	} else if candle.Body()/totalRange > t.BodyRatio {
+		return false
+	}
+
+	// Long wick relative to the ATR or the total range
+	if t.WickATR > 0 && atr > 0 {
+		return wick >= t.WickATR*atr
+	}
+	return wick/totalRange >= t.WickRatio
+}
+
+// Helper functions
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
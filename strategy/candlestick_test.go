@@ -0,0 +1,45 @@
+package strategy_test
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// pullback is a long 2-candlestick reversal off the 20 EMA in a strong uptrend, far above the 200 EMA
+var pullback = []models.Candle{
+	{Open: 124, High: 125, Low: 119.5, Close: 121}, // Bear candle
+	{Open: 121, High: 123, Low: 118.5, Close: 122}, // Reversal: tail pierces the 20 EMA at 120
+	{Open: 122, High: 124, Low: 120, Close: 123.5}, // Confirmation
+}
+
+func TestNearestEMAReference(t *testing.T) {
+	candles := pullback
+	ema20, ema50, ema100, ema200 := 120.0, 115.0, 110.0, 100.0
+	detector := strategy.NewCandlestickPatternDetector()
+
+	if detector.DetectLong2CandlestickReversal(candles, ema20, ema50, ema100, ema200) {
+		t.Error("extreme reference: reversal detected without piercing the 200 EMA")
+	}
+	detector.SetEMAReference(strategy.NearestEMA)
+	if !detector.DetectLong2CandlestickReversal(candles, ema20, ema50, ema100, ema200) {
+		t.Error("nearest reference: reversal off the 20 EMA not detected")
+	}
+}
+
+func TestDisabledPatternsAreNotDetected(t *testing.T) {
+	detector := strategy.NewCandlestickPatternDetector()
+	detector.SetEMAReference(strategy.NearestEMA)
+	if got := detector.DetectAllPatterns(pullback, 120, 115, 110, 100); got != strategy.Long2CandlestickReversal {
+		t.Fatalf("DetectAllPatterns = %v, want %v", got, strategy.Long2CandlestickReversal)
+	}
+
+	detector.SetPatternEnabled(strategy.Long2CandlestickReversal, false)
+	if got := detector.DetectAllPatterns(pullback, 120, 115, 110, 100); got != strategy.NoPattern {
+		t.Errorf("DetectAllPatterns with the pattern disabled = %v, want %v", got, strategy.NoPattern)
+	}
+	if detector.PatternEnabled(strategy.Long2CandlestickReversal) || !detector.PatternEnabled(strategy.LongPinbarReversal) {
+		t.Error("only the 2-candlestick reversal should be disabled")
+	}
+}
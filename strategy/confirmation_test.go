@@ -0,0 +1,57 @@
+package strategy_test
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// longReversal returns quiet candles followed by a bear candle and a reversal whose tail pierces support at 100
+func longReversal(confirmation models.Candle) []models.Candle {
+	var candles []models.Candle
+	for i := 0; i < 14; i++ {
+		candles = append(candles, models.Candle{Open: 103, High: 103.5, Low: 102.5, Close: 103})
+	}
+	return append(candles,
+		models.Candle{Open: 105, High: 106, Low: 101, Close: 102}, // Bear candle
+		models.Candle{Open: 102, High: 104, Low: 99, Close: 103},  // Reversal: body above 100, tail below it
+		confirmation,
+	)
+}
+
+func TestConfirmationRules(t *testing.T) {
+	belowHigh := models.Candle{Open: 102.5, High: 104.5, Low: 101, Close: 103.8} // Closes at 96% of the reversal range
+	lowerLow := models.Candle{Open: 101, High: 105, Low: 98.5, Close: 104.5}     // Closes above the high but undercuts the low
+	wide := models.Candle{Open: 100, High: 110, Low: 99.5, Close: 109}           // Closes far above the high with a huge range
+	partial := func(percent float64) strategy.ConfirmationRules {
+		rules := strategy.DefaultConfirmationRules()
+		rules.ClosePercent = percent
+		return rules
+	}
+
+	tests := []struct {
+		name         string
+		confirmation models.Candle
+		rules        strategy.ConfirmationRules
+		want         bool
+	}{
+		{"default requires a close above the high", belowHigh, strategy.DefaultConfirmationRules(), false},
+		{"close above 90% of the range", belowHigh, partial(90), true},
+		{"close below 98% of the range", belowHigh, partial(98), false},
+		{"default requires rising lows", lowerLow, strategy.DefaultConfirmationRules(), false},
+		{"rising lows optional", lowerLow, strategy.ConfirmationRules{ClosePercent: 100}, true},
+		{"no range limit by default", wide, strategy.DefaultConfirmationRules(), true},
+		{"range above the ATR limit", wide, strategy.ConfirmationRules{ClosePercent: 100, RequireRisingLows: true, MaxRangeATR: 3}, false},
+		{"range within the ATR limit", wide, strategy.ConfirmationRules{ClosePercent: 100, RequireRisingLows: true, MaxRangeATR: 10}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := strategy.NewCandlestickPatternDetector()
+			detector.SetConfirmationRules(test.rules)
+			if got := detector.DetectLong2CandlestickReversal(longReversal(test.confirmation), 110, 108, 104, 100); got != test.want {
+				t.Errorf("DetectLong2CandlestickReversal = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
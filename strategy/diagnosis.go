@@ -0,0 +1,416 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/models"
+	"strings"
+)
+
+// RuleCheck is the outcome of one rule or pattern condition together with the values it measured
+type RuleCheck struct {
+	Name     string // Rule or condition name, e.g. "Stoch" or "Tail pierces support"
+	Passed   bool   // Whether the rule holds on the newest candles
+	Measured string // Values the rule was decided on, e.g. "K=34.20 D=41.05, no crossover"
+	Needs    string // What the rule requires to pass, e.g. "K < 30 with a bullish crossover"
+	Gap      string // How far a failed rule is from passing, e.g. "K 4.20 above 30" (empty when it passed)
+}
+
+// PatternCheck holds the conditions of one reversal pattern evaluated on the newest candles
+type PatternCheck struct {
+	Pattern    PatternType // Pattern the conditions belong to
+	Conditions []RuleCheck // Every condition of the pattern, in the order the detector checks them
+}
+
+// Matched reports whether every condition of the pattern holds
+func (p PatternCheck) Matched() bool {
+	return p.PassedCount() == len(p.Conditions)
+}
+
+// PassedCount returns how many conditions of the pattern hold
+func (p PatternCheck) PassedCount() int {
+	passed := 0
+	for _, condition := range p.Conditions {
+		if condition.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// Diagnosis explains a validation: every rule is evaluated, even after one fails, with the numbers behind it
+// Use it to answer why a symbol did or did not signal; ValidateLongSetup and ValidateShortSetup stay the fast path
+type Diagnosis struct {
+	Scenario  ScenarioType      // Side the diagnosis was made for
+	Result    ValidationResult  // Validation result exactly as a scan computes it
+	Snapshot  IndicatorSnapshot // Indicator values of the candle history
+	Rules     []RuleCheck       // EMA, Stoch, MACD, and Pattern outcomes
+	Patterns  []PatternCheck    // Conditions of the side's reversal patterns, 2-candlestick first
+	TradePlan TradePlan         // Levels the setup would use if it triggered on the newest candles
+}
+
+// NearestPattern returns the side's reversal pattern with the most conditions met (the matched one if any)
+func (d Diagnosis) NearestPattern() PatternCheck {
+	var nearest PatternCheck
+	for i, check := range d.Patterns {
+		if i == 0 || check.PassedCount() > nearest.PassedCount() {
+			nearest = check
+		}
+	}
+	return nearest
+}
+
+// Diagnose validates one side of a candle history and measures every rule and pattern condition
+func (s *SAPANStrategy) Diagnose(symbol string, candles []models.Candle, scenario ScenarioType) Diagnosis {
+	diagnosis := Diagnosis{
+		Scenario: scenario,
+		Result:   s.validateSetup(symbol, candles, scenario),
+	}
+	if len(candles) < s.MinimumCandles() {
+		return diagnosis
+	}
+
+	snapshot := s.Snapshot(candles)
+	diagnosis.Snapshot = snapshot
+	diagnosis.Rules = []RuleCheck{s.checkEMA(snapshot, scenario), s.checkStochasticRSI(snapshot, scenario), s.checkMACD(snapshot, scenario)}
+	diagnosis.Patterns = s.patternDetector.CheckPatterns(candles, snapshot, scenario)
+	diagnosis.Rules = append(diagnosis.Rules, checkPattern(s.patternDetector.DetectPatterns(candles, snapshot), diagnosis.Patterns, diagnosis.NearestPattern(), scenario))
+	diagnosis.TradePlan = buildTradePlan(candles, scenario)
+	diagnosis.TradePlan.TimeStop = s.timeStop
+	return diagnosis
+}
+
+// checkEMA measures the EMA order rule of a side
+func (s *SAPANStrategy) checkEMA(snapshot IndicatorSnapshot, scenario ScenarioType) RuleCheck {
+	check := RuleCheck{
+		Name:     "EMA",
+		Measured: measuredEMAs(snapshot),
+	}
+	if scenario == LongScenario {
+		check.Passed, check.Needs = s.validateEMATrend(snapshot), emaOrder(snapshot.EMAPeriods, " > ")
+	} else {
+		check.Passed, check.Needs = s.validateEMADowntrend(snapshot), emaOrder(snapshot.EMAPeriods, " < ")
+	}
+	if !check.Passed {
+		check.Gap = emaOrderGap(snapshot, scenario)
+	}
+	return check
+}
+
+// emaOrderGap lists the EMA pairs out of a side's order with how far apart they are, as a share of the slower EMA
+func emaOrderGap(snapshot IndicatorSnapshot, scenario ScenarioType) string {
+	var gaps []string
+	for i := 0; i+1 < len(snapshot.EMAs); i++ {
+		fast, slow := snapshot.EMAs[i], snapshot.EMAs[i+1]
+		ordered, relation := fast > slow, "below"
+		if scenario == ShortScenario {
+			ordered, relation = fast < slow, "above"
+		}
+		if !ordered && slow != 0 {
+			gaps = append(gaps, fmt.Sprintf("%d %s %d by %.2f%%", snapshot.EMAPeriods[i], relation, snapshot.EMAPeriods[i+1],
+				abs(fast-slow)/slow*100))
+		}
+	}
+	return strings.Join(gaps, ", ")
+}
+
+// measuredEMAs lists every EMA of the stack as period=value
+func measuredEMAs(snapshot IndicatorSnapshot) string {
+	values := make([]string, len(snapshot.EMAs))
+	for i, ema := range snapshot.EMAs {
+		values[i] = fmt.Sprintf("%d=%s", snapshot.EMAPeriods[i], models.FormatPrice(ema))
+	}
+	return strings.Join(values, " ")
+}
+
+// checkStochasticRSI measures the Stochastic RSI rule of a side
+func (s *SAPANStrategy) checkStochasticRSI(snapshot IndicatorSnapshot, scenario ScenarioType) RuleCheck {
+	threshold, lookback := s.stochRSIThreshold(scenario)
+	crossed := snapshot.StochRSI.CrossedFromBelow(s.stochRSIThresholds.Oversold, lookback)
+	crossover := "no crossover"
+	if crossed {
+		crossover = "bullish crossover"
+	}
+	check := RuleCheck{
+		Name:     "Stoch",
+		Measured: fmt.Sprintf("K=%.2f D=%.2f, %s", snapshot.StochRSI.K, snapshot.StochRSI.D, crossover),
+	}
+	var gaps []string
+	k := snapshot.StochRSI.K
+	if scenario == LongScenario {
+		check.Passed, check.Needs = s.validateStochasticRSILong(snapshot), fmt.Sprintf("K < %g with a bullish crossover", threshold)
+		if k >= threshold {
+			gaps = append(gaps, fmt.Sprintf("K %.2f above %g", k-threshold, threshold))
+		}
+	} else {
+		check.Passed, check.Needs = s.validateStochasticRSIShort(snapshot), fmt.Sprintf("K > %g with a bullish crossover", threshold)
+		if k <= threshold {
+			gaps = append(gaps, fmt.Sprintf("K %.2f below %g", threshold-k, threshold))
+		}
+	}
+	if lookback > 1 {
+		check.Needs += fmt.Sprintf(" in the last %d candles", lookback)
+	}
+	if !crossed {
+		gaps = append(gaps, "no bullish crossover")
+	}
+	if !check.Passed {
+		check.Gap = strings.Join(gaps, ", ")
+	}
+	return check
+}
+
+// checkMACD measures the MACD rule of a side, including how long the opposing market has lasted
+func (s *SAPANStrategy) checkMACD(snapshot IndicatorSnapshot, scenario ScenarioType) RuleCheck {
+	last := snapshot.MACD.Last()
+	check := RuleCheck{Name: "MACD"}
+	measured := fmt.Sprintf("MACD=%.4f signal=%.4f histogram=%.4f", last.MACD, last.Signal, last.Histogram)
+	if scenario == LongScenario {
+		run := snapshot.MACD.BearRunLength()
+		check.Passed = s.validateMACDLong(snapshot)
+		check.Measured = fmt.Sprintf("%s, bear run %d candles", measured, run)
+		check.Needs = fmt.Sprintf("bull market or a bear run of at most %d candles", s.maxMACDRun)
+		if !check.Passed {
+			check.Gap = fmt.Sprintf("bear run %d candles too long", run-s.maxMACDRun)
+		}
+	} else {
+		run := snapshot.MACD.BullRunLength()
+		check.Passed = s.validateMACDShort(snapshot)
+		check.Measured = fmt.Sprintf("%s, bull run %d candles", measured, run)
+		check.Needs = fmt.Sprintf("bear market or a bull run of at most %d candles", s.maxMACDRun)
+		if !check.Passed {
+			check.Gap = fmt.Sprintf("bull run %d candles too long", run-s.maxMACDRun)
+		}
+	}
+	return check
+}
+
+// checkPattern reports the pattern rule of a side from the detected pattern, the side's enabled patterns, and the nearest of them
+func checkPattern(detected PatternType, enabled []PatternCheck, nearest PatternCheck, scenario ScenarioType) RuleCheck {
+	check := RuleCheck{Name: "Pattern", Measured: detected.String()}
+	if scenario == LongScenario {
+		check.Passed = detected == Long2CandlestickReversal || detected == LongPinbarReversal
+		check.Needs = "Long 2-Candlestick or Long Pinbar Reversal"
+	} else {
+		check.Passed = detected == Short2CandlestickReversal || detected == ShortPinbarReversal
+		check.Needs = "Short 2-Candlestick or Short Pinbar Reversal"
+	}
+	switch len(enabled) {
+	case 0:
+		check.Needs = fmt.Sprintf("an enabled %s pattern", scenario)
+	case 1:
+		check.Needs = enabled[0].Pattern.String()
+	}
+	switch {
+	case check.Passed:
+	case len(enabled) == 0:
+		check.Gap = fmt.Sprintf("every %s pattern is disabled", scenario)
+	case nearest.Matched():
+		// The detector reports the first match across both sides, so an opposite pattern can hide this one
+		check.Measured = fmt.Sprintf("%s; %s also matches but is checked later", check.Measured, nearest.Pattern)
+		check.Gap = fmt.Sprintf("%s detected first", detected)
+	default:
+		check.Measured = fmt.Sprintf("%s; nearest %s meets %d of %d conditions", check.Measured, nearest.Pattern,
+			nearest.PassedCount(), len(nearest.Conditions))
+		var failed []string
+		for _, condition := range nearest.Conditions {
+			if !condition.Passed {
+				failed = append(failed, strings.ToLower(condition.Name))
+			}
+		}
+		check.Gap = fmt.Sprintf("%s lacks %s", nearest.Pattern, strings.Join(failed, ", "))
+	}
+	return check
+}
+
+// CheckPatterns evaluates every condition of a side's enabled 2-candlestick and pinbar reversals on the newest candles
+// A pattern whose conditions all pass is the one the matching Detect method finds
+func (c *CandlestickPatternDetector) CheckPatterns(candles []models.Candle, snapshot IndicatorSnapshot, scenario ScenarioType) []PatternCheck {
+	var enabled []PatternCheck
+	for _, check := range c.checkAllPatterns(candles, snapshot, scenario) {
+		if c.PatternEnabled(check.Pattern) {
+			enabled = append(enabled, check)
+		}
+	}
+	return enabled
+}
+
+// checkAllPatterns evaluates the conditions of a side's reversal patterns, whether enabled or not
+func (c *CandlestickPatternDetector) checkAllPatterns(candles []models.Candle, snapshot IndicatorSnapshot, scenario ScenarioType) []PatternCheck {
+	if len(candles) < 3 || len(snapshot.EMAs) == 0 {
+		return nil
+	}
+	emas := snapshot.EMAs
+	confirmation := candles[len(candles)-1] // Confirmation candle
+	reversal := candles[len(candles)-2]     // Reversal or pinbar candle
+	previous := candles[len(candles)-3]     // Candle before the reversal
+	body := (reversal.Open + reversal.Close) / 2
+	atr := c.patternATR(candles)
+	price := models.FormatPrice
+
+	if scenario == LongScenario {
+		support, emaName := c.supportEMA(reversal, emas), c.referenceName("lowest")
+		bodyAbove := RuleCheck{
+			Name:     "Body above support",
+			Passed:   c.isReversalBodyAboveSupport(reversal, emas),
+			Measured: fmt.Sprintf("body midpoint %s, support %s", price(body), price(support)),
+			Needs:    "body midpoint above the " + emaName,
+		}
+		confirmed := c.checkConfirmation(confirmation, reversal, c.confirmationATR(candles), scenario)
+		return []PatternCheck{
+			{Pattern: Long2CandlestickReversal, Conditions: []RuleCheck{
+				bodyAbove,
+				{
+					Name:     "Tail pierces support",
+					Passed:   c.isTailPiercingSupport(reversal, previous, atr, emas),
+					Measured: fmt.Sprintf("low %s, support %s, previous low %s", price(reversal.Low), price(support), price(previous.Low)),
+					Needs:    "low " + c.pierceNeeds("below", atr) + " the " + emaName + " and the previous low",
+				},
+				confirmed,
+			}},
+			{Pattern: LongPinbarReversal, Conditions: []RuleCheck{
+				c.checkPinbarShape(reversal, reversal.LowerWick(), atr, "lower"),
+				{
+					Name:     "Body above support",
+					Passed:   body > support,
+					Measured: bodyAbove.Measured,
+					Needs:    bodyAbove.Needs,
+				},
+				{
+					Name:     "Tail pierces support",
+					Passed:   c.isPierceDeepEnough(support-reversal.Low, atr),
+					Measured: fmt.Sprintf("low %s, support %s", price(reversal.Low), price(support)),
+					Needs:    "low " + c.pierceNeeds("below", atr) + " the " + emaName,
+				},
+				confirmed,
+			}},
+		}
+	}
+
+	resistance, emaName := c.resistanceEMA(reversal, emas), c.referenceName("highest")
+	bodyBelow := RuleCheck{
+		Name:     "Body below resistance",
+		Passed:   c.isReversalBodyBelowResistance(reversal, emas),
+		Measured: fmt.Sprintf("body midpoint %s, resistance %s", price(body), price(resistance)),
+		Needs:    "body midpoint below the " + emaName,
+	}
+	confirmed := c.checkConfirmation(confirmation, reversal, c.confirmationATR(candles), scenario)
+	return []PatternCheck{
+		{Pattern: Short2CandlestickReversal, Conditions: []RuleCheck{
+			bodyBelow,
+			{
+				Name:     "Tail pierces resistance",
+				Passed:   c.isTailPiercingResistance(reversal, previous, atr, emas),
+				Measured: fmt.Sprintf("high %s, resistance %s, previous high %s", price(reversal.High), price(resistance), price(previous.High)),
+				Needs:    "high " + c.pierceNeeds("above", atr) + " the " + emaName + " and the previous high",
+			},
+			confirmed,
+		}},
+		{Pattern: ShortPinbarReversal, Conditions: []RuleCheck{
+			c.checkPinbarShape(reversal, reversal.UpperWick(), atr, "upper"),
+			{
+				Name:     "Body below resistance",
+				Passed:   body < resistance,
+				Measured: bodyBelow.Measured,
+				Needs:    bodyBelow.Needs,
+			},
+			{
+				Name:     "Tail pierces resistance",
+				Passed:   c.isPierceDeepEnough(reversal.High-resistance, atr),
+				Measured: fmt.Sprintf("high %s, resistance %s", price(reversal.High), price(resistance)),
+				Needs:    "high " + c.pierceNeeds("above", atr) + " the " + emaName,
+			},
+			confirmed,
+		}},
+	}
+}
+
+// referenceName names the EMA support or resistance is measured against, e.g. "lowest EMA" or "nearest EMA"
+func (c *CandlestickPatternDetector) referenceName(extreme string) string {
+	if c.emaReference == NearestEMA {
+		return "nearest EMA"
+	}
+	return extreme + " EMA"
+}
+
+// pierceNeeds describes how far past the EMA a tail must reach, e.g. "below" or "at least 0.5 ATR (1.20) below"
+func (c *CandlestickPatternDetector) pierceNeeds(direction string, atr float64) string {
+	if c.thresholds.PierceATR <= 0 {
+		return direction
+	}
+	return fmt.Sprintf("at least %g ATR (%s) %s", c.thresholds.PierceATR, models.FormatPrice(c.thresholds.PierceATR*atr), direction)
+}
+
+// checkPinbarShape measures the body and wick of a pinbar candidate against the pinbar thresholds
+func (c *CandlestickPatternDetector) checkPinbarShape(candle models.Candle, wick, atr float64, side string) RuleCheck {
+	passed := c.isBullishPinbar(candle, atr)
+	if side == "upper" {
+		passed = c.isBearishPinbar(candle, atr)
+	}
+	t := c.thresholds
+	var bodyShare, wickShare float64
+	if totalRange := candle.Range(); totalRange > 0 {
+		bodyShare, wickShare = candle.Body()/totalRange*100, wick/totalRange*100
+	}
+	measured := fmt.Sprintf("body %.0f%% of range, %s wick %.0f%%", bodyShare, side, wickShare)
+	bodyNeeds := fmt.Sprintf("body at most %.0f%%", t.BodyRatio*100)
+	wickNeeds := fmt.Sprintf("%s wick at least %.0f%%", side, t.WickRatio*100)
+	if t.BodyATR > 0 && atr > 0 {
+		bodyNeeds = fmt.Sprintf("body at most %g ATR (%s)", t.BodyATR, models.FormatPrice(t.BodyATR*atr))
+	}
+	if t.WickATR > 0 && atr > 0 {
+		wickNeeds = fmt.Sprintf("%s wick at least %g ATR (%s)", side, t.WickATR, models.FormatPrice(t.WickATR*atr))
+	}
+	if (t.BodyATR > 0 || t.WickATR > 0) && atr > 0 {
+		measured += fmt.Sprintf(", body %s, wick %s, ATR %s", models.FormatPrice(candle.Body()), models.FormatPrice(wick), models.FormatPrice(atr))
+	}
+	return RuleCheck{
+		Name:     "Pinbar shape",
+		Passed:   passed,
+		Measured: measured,
+		Needs:    bodyNeeds + ", " + wickNeeds,
+	}
+}
+
+// checkConfirmation measures the confirmation candle of a side against the configured confirmation rules
+func (c *CandlestickPatternDetector) checkConfirmation(confirmation, reversal models.Candle, atr float64, scenario ScenarioType) RuleCheck {
+	price := models.FormatPrice
+	rules := c.confirmation
+	check := RuleCheck{Name: "Bullish confirmation"}
+	closeLevel, levelName, needs := c.bullishCloseLevel(reversal), "high", "green close above the reversal high"
+	if scenario == ShortScenario {
+		check.Name = "Bearish confirmation"
+		closeLevel, levelName, needs = c.bearishCloseLevel(reversal), "low", "red close below the reversal low"
+	}
+	if rules.ClosePercent != 100 {
+		// A partial close is measured into the reversal range from the opposite extreme
+		levelName = "level"
+		needs = fmt.Sprintf("green close above %.0f%% of the reversal range from its low", rules.ClosePercent)
+		if scenario == ShortScenario {
+			needs = fmt.Sprintf("red close below %.0f%% of the reversal range from its high", rules.ClosePercent)
+		}
+	}
+
+	if scenario == LongScenario {
+		check.Passed = c.isBullishConfirmation(confirmation, reversal, atr)
+		check.Measured = fmt.Sprintf("close %s vs %s %s, open %s, low %s vs low %s", price(confirmation.Close), levelName, price(closeLevel),
+			price(confirmation.Open), price(confirmation.Low), price(reversal.Low))
+		if rules.RequireRisingLows {
+			needs += " with a higher low"
+		}
+	} else {
+		check.Passed = c.isBearishConfirmation(confirmation, reversal, atr)
+		check.Measured = fmt.Sprintf("close %s vs %s %s, open %s, high %s vs high %s", price(confirmation.Close), levelName, price(closeLevel),
+			price(confirmation.Open), price(confirmation.High), price(reversal.High))
+		if rules.RequireRisingLows {
+			needs += " with a lower high"
+		}
+	}
+	if rules.MaxRangeATR > 0 {
+		check.Measured += fmt.Sprintf(", range %s vs ATR %s", price(confirmation.Range()), price(atr))
+		needs += fmt.Sprintf(", range at most %.1fx ATR", rules.MaxRangeATR)
+	}
+	check.Needs = needs
+	return check
+}
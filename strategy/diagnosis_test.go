@@ -0,0 +1,64 @@
+package strategy_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// TestDiagnoseAgreesWithValidation walks the fixtures and checks that the diagnosis reaches the validation's verdicts
+func TestDiagnoseAgreesWithValidation(t *testing.T) {
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+	sapanStrategy := strategy.NewSAPANStrategy()
+	detector := strategy.NewCandlestickPatternDetector()
+
+	for _, symbol := range []string{"UPTREND", "DOWNTREND", "SIDEWAYS", "PINBAR"} {
+		candleData, err := store.Load(symbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for end := strategy.MinimumCandles; end <= len(candleData.Candles); end++ {
+			history := candleData.Candles[:end]
+			for _, scenario := range []strategy.ScenarioType{strategy.LongScenario, strategy.ShortScenario} {
+				diagnosis := sapanStrategy.Diagnose(symbol, history, scenario)
+				result := diagnosis.Result
+				if len(diagnosis.Rules) != 4 {
+					t.Fatalf("%s %s at %d: %d rules, want 4", symbol, scenario, end, len(diagnosis.Rules))
+				}
+				passed := true
+				for i, want := range []bool{result.EMATrendValid, result.StochasticValid, result.MACDValid, result.PatternValid} {
+					rule := diagnosis.Rules[i]
+					passed = passed && rule.Passed
+					// The validation stops at the first failed rule, so only the rules it reached can be compared
+					if (want || passed) && rule.Passed != want {
+						t.Errorf("%s %s at %d: %s passed = %v, validation says %v", symbol, scenario, end, rule.Name, rule.Passed, want)
+					}
+				}
+				if passed != result.IsValid {
+					t.Errorf("%s %s at %d: all rules passed = %v, IsValid = %v", symbol, scenario, end, passed, result.IsValid)
+				}
+
+				snapshot := diagnosis.Snapshot
+				emas := snapshot.EMAs
+				for _, check := range diagnosis.Patterns {
+					var want bool
+					switch check.Pattern {
+					case strategy.Long2CandlestickReversal:
+						want = detector.DetectLong2CandlestickReversal(history, emas...)
+					case strategy.LongPinbarReversal:
+						want = detector.DetectLongPinbarReversal(history, emas...)
+					case strategy.Short2CandlestickReversal:
+						want = detector.DetectShort2CandlestickReversal(history, emas...)
+					case strategy.ShortPinbarReversal:
+						want = detector.DetectShortPinbarReversal(history, emas...)
+					}
+					if check.Matched() != want {
+						t.Errorf("%s %s at %d: %s conditions matched = %v, detector says %v", symbol, scenario, end, check.Pattern, check.Matched(), want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package strategy_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// TestEMAPeriods checks that a custom EMA stack drives the snapshot, the trend rule, and the validation message
+func TestEMAPeriods(t *testing.T) {
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+	candleData, err := store.Load("UPTREND")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calculator := indicators.NewEMACalculator()
+	periods := []int{8, 21, 55, 89}
+	sapanStrategy := strategy.NewSAPANStrategy()
+	sapanStrategy.SetEMAPeriods(periods)
+
+	checked := 0
+	for end := strategy.MinimumCandles; end <= len(candleData.Candles); end++ {
+		history := candleData.Candles[:end]
+		snapshot := sapanStrategy.Snapshot(history)
+		if len(snapshot.EMAs) != len(periods) {
+			t.Fatalf("at %d: %d EMAs for a stack of %d periods", end, len(snapshot.EMAs), len(periods))
+		}
+		uptrend := true
+		for i, period := range periods {
+			if want := calculator.Calculate(snapshot.Closes, period); snapshot.EMAs[i] != want {
+				t.Fatalf("at %d: EMA %d = %v, want %v", end, period, snapshot.EMAs[i], want)
+			}
+			if i > 0 && snapshot.EMAs[i-1] <= snapshot.EMAs[i] {
+				uptrend = false
+			}
+		}
+
+		result := sapanStrategy.ValidateLongSetup("UPTREND", history)
+		if result.EMATrendValid != uptrend {
+			t.Errorf("at %d: EMA trend valid = %v, stack order says %v", end, result.EMATrendValid, uptrend)
+		}
+		if !uptrend && !strings.Contains(result.ValidationMessage, "8 > 21 > 55 > 89") {
+			t.Errorf("at %d: validation message %q does not name the stack", end, result.ValidationMessage)
+		}
+		if strategy.FormatEMAStack(result.EMAPeriods) != "8/21/55/89" {
+			t.Errorf("at %d: result stack = %v, want %v", end, result.EMAPeriods, periods)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("the fixture is too short to exercise the EMA stack")
+	}
+}
+
+// TestSetEMAPeriodsRejectsInvalidStacks checks that unordered or too short stacks keep the current stack
+func TestSetEMAPeriodsRejectsInvalidStacks(t *testing.T) {
+	sapanStrategy := strategy.NewSAPANStrategy()
+	for _, periods := range [][]int{nil, {20}, {50, 20}, {20, 20, 50}, {0, 50}} {
+		sapanStrategy.SetEMAPeriods(periods)
+		if got := strategy.FormatEMAStack(sapanStrategy.EMAPeriods()); got != "20/50/100/200" {
+			t.Errorf("SetEMAPeriods(%v) changed the stack to %s", periods, got)
+		}
+	}
+
+	sapanStrategy.SetEMAPeriods([]int{50, 150, 300})
+	if got, want := sapanStrategy.RequiredCandles(), 300+strategy.WarmupCandles; got != want {
+		t.Errorf("RequiredCandles() = %d with a 300 EMA, want %d", got, want)
+	}
+}
+
+// TestMinimumCandlesFollowsTheStack checks that a fast EMA stack validates histories shorter than 200 candles
+func TestMinimumCandlesFollowsTheStack(t *testing.T) {
+	sapanStrategy := strategy.NewSAPANStrategy()
+	if got := sapanStrategy.MinimumCandles(); got != strategy.MinimumCandles {
+		t.Errorf("MinimumCandles() = %d with the default stack, want %d", got, strategy.MinimumCandles)
+	}
+
+	sapanStrategy.SetEMAPeriods([]int{9, 21, 50})
+	minimum := sapanStrategy.MinimumCandles()
+	if minimum >= strategy.MinimumCandles {
+		t.Fatalf("MinimumCandles() = %d with a 9/21/50 stack, want fewer than %d", minimum, strategy.MinimumCandles)
+	}
+	candles := trendingCandles(minimum)
+	if result := sapanStrategy.ValidateLongSetup("FAST", candles); result.ValidationMessage == "Insufficient data for analysis" {
+		t.Errorf("a %d-candle history was rejected although the 9/21/50 stack needs only %d", len(candles), minimum)
+	}
+	if result := sapanStrategy.ValidateLongSetup("FAST", candles[:minimum-1]); result.ValidationMessage != "Insufficient data for analysis" {
+		t.Errorf("a %d-candle history was validated: %q", minimum-1, result.ValidationMessage)
+	}
+}
@@ -0,0 +1,48 @@
+package strategy_test
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// trendingCandles builds a rising series with a regular pullback, enough history for every indicator
+func trendingCandles(n int) []models.Candle {
+	candles := make([]models.Candle, n)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range candles {
+		close := 100 + float64(i)*0.3 + 4*math.Sin(float64(i)/6)
+		candles[i] = models.Candle{
+			Date:   start.AddDate(0, 0, i),
+			Open:   close - 0.5,
+			High:   close + 1,
+			Low:    close - 1,
+			Close:  close,
+			Volume: 1_000_000,
+		}
+	}
+	return candles
+}
+
+func ExampleSAPANStrategy_ValidateLongSetup() {
+	sapan := strategy.NewSAPANStrategy()
+	result := sapan.ValidateLongSetup("DEMO", trendingCandles(strategy.MinimumCandles+50))
+
+	fmt.Println("valid:", result.IsValid)
+	fmt.Println("EMA trend:", result.EMATrendValid)
+	fmt.Println("Stochastic RSI:", result.StochasticValid)
+	fmt.Println("MACD:", result.MACDValid)
+	fmt.Println("pattern:", result.PatternValid)
+	if result.IsValid {
+		fmt.Printf("entry %.2f, stop %.2f, target %.2f\n", result.TradePlan.Entry, result.TradePlan.Stop, result.TradePlan.Target)
+	}
+	// Output:
+	// valid: false
+	// EMA trend: true
+	// Stochastic RSI: false
+	// MACD: false
+	// pattern: false
+}
@@ -0,0 +1,192 @@
+package strategy_test
+
+import (
+	"flag"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/sapantest"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// fixtureThresholds overrides the Stochastic RSI thresholds of fixtures whose decisions cannot occur under the defaults
+// With 14/14/3/3, %K moves at most a third of its range per candle, so a Short crossover from oversold reaches
+// overbought a few candles later at the earliest; RALLY checks Short setups with a five-candle crossover lookback
+var fixtureThresholds = map[string]strategy.StochRSIThresholds{
+	"RALLY": {Oversold: 30, Overbought: 70, LongLookback: 1, ShortLookback: 5},
+}
+
+// goldenSetup is a valid setup found while walking a fixture
+type goldenSetup struct {
+	Date       string           `json:"date"`       // Date of the confirmation candle
+	Side       string           `json:"side"`       // long or short
+	Pattern    string           `json:"pattern"`    // Detected reversal pattern
+	Score      float64          `json:"score"`      // Setup quality score
+	Entry      float64          `json:"entry"`      // Trade plan entry
+	Stop       float64          `json:"stop"`       // Trade plan stop
+	Target     float64          `json:"target"`     // Trade plan target
+	Indicators goldenIndicators `json:"indicators"` // Indicator values on the confirmation candle
+}
+
+// goldenIndicators holds the indicator values the strategy decides on, so rewrites that shift them show up in the diff
+type goldenIndicators struct {
+	EMA20  float64 `json:"ema20"`   // 20-period EMA
+	EMA50  float64 `json:"ema50"`   // 50-period EMA
+	EMA100 float64 `json:"ema100"`  // 100-period EMA
+	EMA200 float64 `json:"ema200"`  // 200-period EMA
+	StochK float64 `json:"stoch_k"` // Stochastic RSI %K (14, 14, 3, 3)
+	StochD float64 `json:"stoch_d"` // Stochastic RSI %D (14, 14, 3, 3)
+	MACD   float64 `json:"macd"`    // MACD line (50, 100, 9)
+	Signal float64 `json:"signal"`  // MACD signal line
+}
+
+// goldenDecision is the validation result of the newest candle for one side
+type goldenDecision struct {
+	Valid      bool   `json:"valid"`      // Overall result
+	EMATrend   bool   `json:"ema_trend"`  // EMA order check
+	Stochastic bool   `json:"stochastic"` // Stochastic RSI check
+	MACD       bool   `json:"macd"`       // MACD check
+	Pattern    string `json:"pattern"`    // Detected pattern
+	Message    string `json:"message"`    // Validation message
+}
+
+// goldenSnapshot is the structured result of running the strategy over every candle of a fixture
+type goldenSnapshot struct {
+	Candles    int                       `json:"candles"`    // Candles in the fixture
+	Indicators goldenIndicators          `json:"indicators"` // Indicator values on the newest candle
+	Long       goldenDecision            `json:"long"`       // Long decision on the newest candle
+	Short      goldenDecision            `json:"short"`      // Short decision on the newest candle
+	Setups     []goldenSetup             `json:"setups"`     // Every valid setup, oldest first
+	Rejections map[string]map[string]int `json:"rejections"` // Rejection messages counted per side
+}
+
+// TestGolden walks each committed fixture candle by candle and compares the decisions with testdata/golden
+// Add a fixture by copying a file from CANDLE_DIR into testdata/candles and running go test -update
+func TestGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "candles", "*_daily.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no candle fixtures found: %v", err)
+	}
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+
+	// Regenerated golden files must keep exercising both sides and both pattern families
+	covered := map[string]bool{}
+	for _, path := range paths {
+		symbol := strings.TrimSuffix(filepath.Base(path), "_daily.json")
+		t.Run(symbol, func(t *testing.T) {
+			candleData, err := store.Load(symbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshot := runSnapshot(symbol, candleData.Candles)
+			for _, setup := range snapshot.Setups {
+				covered[setup.Side] = true
+				covered["pinbar"] = covered["pinbar"] || strings.Contains(setup.Pattern, "Pinbar")
+			}
+			sapantest.Golden(t, filepath.Join("testdata", "golden", symbol+".json"), snapshot, *update)
+		})
+	}
+	for _, want := range []string{"long", "short", "pinbar"} {
+		if !covered[want] {
+			t.Errorf("no fixture produces a %s setup; add or adjust a fixture before accepting the golden files", want)
+		}
+	}
+}
+
+// runSnapshot validates both sides at every candle from the first one with enough history
+func runSnapshot(symbol string, candles []models.Candle) goldenSnapshot {
+	sapanStrategy := strategy.NewSAPANStrategy()
+	if thresholds, ok := fixtureThresholds[symbol]; ok {
+		sapanStrategy.SetStochRSIThresholds(thresholds)
+	}
+	snapshot := goldenSnapshot{
+		Candles:    len(candles),
+		Setups:     []goldenSetup{},
+		Rejections: map[string]map[string]int{"long": {}, "short": {}},
+	}
+
+	for end := 1; end <= len(candles); end++ {
+		history := candles[:end]
+		long := sapanStrategy.ValidateLongSetup(symbol, history)
+		short := sapanStrategy.ValidateShortSetup(symbol, history)
+		if end == len(candles) {
+			snapshot.Long = decision(long)
+			snapshot.Short = decision(short)
+			snapshot.Indicators = indicatorValues(history)
+		}
+		if end < strategy.MinimumCandles {
+			continue
+		}
+		for side, result := range map[string]strategy.ValidationResult{"long": long, "short": short} {
+			if !result.IsValid {
+				snapshot.Rejections[side][result.ValidationMessage]++
+			}
+		}
+		if long.IsValid {
+			snapshot.Setups = append(snapshot.Setups, setup(history, "long", long))
+		}
+		if short.IsValid {
+			snapshot.Setups = append(snapshot.Setups, setup(history, "short", short))
+		}
+	}
+	return snapshot
+}
+
+// decision captures the individual checks of a validation result
+func decision(result strategy.ValidationResult) goldenDecision {
+	return goldenDecision{
+		Valid:      result.IsValid,
+		EMATrend:   result.EMATrendValid,
+		Stochastic: result.StochasticValid,
+		MACD:       result.MACDValid,
+		Pattern:    result.PatternType.String(),
+		Message:    result.ValidationMessage,
+	}
+}
+
+// setup captures a valid setup, rounding prices so the golden files do not depend on float formatting noise
+func setup(history []models.Candle, side string, result strategy.ValidationResult) goldenSetup {
+	return goldenSetup{
+		Date:       history[len(history)-1].Date.Format("2006-01-02"),
+		Side:       side,
+		Pattern:    result.PatternType.String(),
+		Score:      round(result.Score),
+		Entry:      round(result.TradePlan.Entry),
+		Stop:       round(result.TradePlan.Stop),
+		Target:     round(result.TradePlan.Target),
+		Indicators: indicatorValues(history),
+	}
+}
+
+// indicatorValues computes the strategy's indicators on the newest candle of history
+func indicatorValues(history []models.Candle) goldenIndicators {
+	closes := make([]float64, len(history))
+	for i, candle := range history {
+		closes[i] = candle.Close
+	}
+	ema := indicators.NewEMACalculator()
+	stochastic := indicators.NewStochasticRSICalculator().CalculateWithParams(closes, indicators.DefaultStochasticRSIParams())
+	macd := indicators.NewMACDCalculator().Calculate(closes, 50, 100, 9)
+	return goldenIndicators{
+		EMA20:  round(ema.Calculate(closes, 20)),
+		EMA50:  round(ema.Calculate(closes, 50)),
+		EMA100: round(ema.Calculate(closes, 100)),
+		EMA200: round(ema.Calculate(closes, 200)),
+		StochK: round(stochastic.K),
+		StochD: round(stochastic.D),
+		MACD:   round(macd.MACD),
+		Signal: round(macd.Signal),
+	}
+}
+
+// round keeps four decimals
+func round(x float64) float64 {
+	return math.Round(x*10000) / 10000
+}
@@ -0,0 +1,56 @@
+package strategy_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// TestMACDRunLimit checks that results expose the MACD runs and that the MACD rule applies the configured limit
+func TestMACDRunLimit(t *testing.T) {
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+	strict, lenient := strategy.NewSAPANStrategy(), strategy.NewSAPANStrategy()
+	strict.SetMaxMACDRun(0)
+	lenient.SetMaxMACDRun(1000)
+
+	reached, differed := 0, 0
+	for _, symbol := range []string{"UPTREND", "DOWNTREND", "SIDEWAYS", "PINBAR"} {
+		candleData, err := store.Load(symbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for end := strategy.MinimumCandles; end <= len(candleData.Candles); end++ {
+			history := candleData.Candles[:end]
+			for _, scenario := range []strategy.ScenarioType{strategy.LongScenario, strategy.ShortScenario} {
+				strictResult, lenientResult := strict.ValidateLongSetup(symbol, history), lenient.ValidateLongSetup(symbol, history)
+				opposing := strictResult.MACDBearRun
+				if scenario == strategy.ShortScenario {
+					strictResult, lenientResult = strict.ValidateShortSetup(symbol, history), lenient.ValidateShortSetup(symbol, history)
+					opposing = strictResult.MACDBullRun
+				}
+				if strictResult.MACDBullRun == 0 && strictResult.MACDBearRun == 0 {
+					t.Fatalf("%s at %d: neither MACD run is counted", symbol, end)
+				}
+				if !strictResult.StochasticValid {
+					continue // The MACD rule was not reached
+				}
+				reached++
+				if !lenientResult.MACDValid {
+					t.Errorf("%s %s at %d: MACD rejected with a limit of 1000 candles", symbol, scenario, end)
+				}
+				if strictResult.MACDValid != (opposing == 0) {
+					t.Errorf("%s %s at %d: MACD valid = %v with an opposing run of %d and a limit of 0",
+						symbol, scenario, end, strictResult.MACDValid, opposing)
+				}
+				if strictResult.MACDValid != lenientResult.MACDValid {
+					differed++
+				}
+			}
+		}
+	}
+	if reached == 0 || differed == 0 {
+		t.Fatalf("the fixtures never exercise the MACD limit (%d reached, %d differed)", reached, differed)
+	}
+}
@@ -0,0 +1,416 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import (
+	"fmt"
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+	"strconv"
+	"strings"
+)
+
+// MinimumCandles is the number of candles the default indicators need before a setup can be validated
+// The 200-period EMA is the longest lookback; MACD (50/100/9) and Stochastic RSI need fewer bars
+// A strategy with a faster EMA stack needs fewer candles; see (*SAPANStrategy).MinimumCandles
+const MinimumCandles = 200
+
+// WarmupCandles is the history fetched beyond the longest indicator lookback so the EMAs settle before the newest candle
+const WarmupCandles = 50
+
+// DefaultEMAPeriods returns the classic SAPAN EMA stack, fastest first
+func DefaultEMAPeriods() []int {
+	return []int{20, 50, 100, 200}
+}
+
+// FormatEMAStack returns the periods of an EMA stack as text, e.g. "20/50/100/200"
+func FormatEMAStack(periods []int) string {
+	return emaOrder(periods, "/")
+}
+
+// emaOrder joins the periods of an EMA stack with a separator
+func emaOrder(periods []int, separator string) string {
+	parts := make([]string, len(periods))
+	for i, period := range periods {
+		parts[i] = strconv.Itoa(period)
+	}
+	return strings.Join(parts, separator)
+}
+
+// DefaultMaxMACDRun is the longest opposing MACD run, in candlesticks, a setup accepts by default
+const DefaultMaxMACDRun = 5
+
+// StochRSIThresholds holds the Stochastic RSI levels and crossover lookbacks of the Long and Short scenarios
+// Both scenarios need a bullish crossover of %K over %D that started below the oversold level
+type StochRSIThresholds struct {
+	Oversold      float64 // Long setups need %K below this level, and crossovers must start below it
+	Overbought    float64 // Short setups need %K above this level
+	LongLookback  int     // Candles back the crossover of a Long setup may be (1 = newest candle only)
+	ShortLookback int     // Candles back the crossover of a Short setup may be (1 = newest candle only)
+}
+
+// DefaultStochRSIThresholds returns the classic 30/70 levels with the crossover on the newest candle
+func DefaultStochRSIThresholds() StochRSIThresholds {
+	return StochRSIThresholds{Oversold: 30, Overbought: 70, LongLookback: 1, ShortLookback: 1}
+}
+
+// Validator checks candle histories for Long and Short setups
+// *SAPANStrategy is the production implementation; tests can script results with sapantest.Strategy
+type Validator interface {
+	ValidateLongSetup(symbol string, candles []models.Candle) ValidationResult
+	ValidateShortSetup(symbol string, candles []models.Candle) ValidationResult
+}
+
+// CandleRequirer reports how many candles a validation needs; *SAPANStrategy implements it
+type CandleRequirer interface {
+	MinimumCandles() int
+}
+
+// ValidatorMinimumCandles returns the candles a validator needs before a setup can be validated
+// Validators that do not implement CandleRequirer are assumed to need MinimumCandles
+func ValidatorMinimumCandles(validator Validator) int {
+	if requirer, ok := validator.(CandleRequirer); ok {
+		return requirer.MinimumCandles()
+	}
+	return MinimumCandles
+}
+
+// SAPANStrategy implements the SAPAN trading strategy with both Long and Short scenarios
+// This struct orchestrates all technical indicators and pattern detection to validate trading setups
+type SAPANStrategy struct {
+	emaCalculator           *indicators.EMACalculator           // EMA calculator for trend analysis
+	stochasticRSICalculator *indicators.StochasticRSICalculator // Stochastic RSI calculator for momentum analysis
+	macdCalculator          *indicators.MACDCalculator          // MACD calculator for trend confirmation
+	patternDetector         *CandlestickPatternDetector         // Pattern detector for candlestick analysis
+	maxMACDRun              int                                 // Longest opposing MACD run, in candles, a setup still accepts
+	stochRSIParams          indicators.StochasticRSIParams      // Lengths and mode of the Stochastic RSI
+	stochRSIThresholds      StochRSIThresholds                  // Stochastic RSI levels and crossover lookbacks per scenario
+	orderBlocks             OrderBlockRules                     // Order block detection and zone confluence rules
+	emaPeriods              []int                               // Periods of the EMA stack, fastest first
+	timeStop                int                                 // Holding period of trade plans in candles (0 disables the time stop)
+}
+
+// NewSAPANStrategy creates a new SAPAN strategy instance with all required calculators
+// This constructor initializes all technical indicators and pattern detectors
+func NewSAPANStrategy() *SAPANStrategy {
+	return &SAPANStrategy{
+		emaCalculator:           indicators.NewEMACalculator(),           // Initialize EMA calculator
+		stochasticRSICalculator: indicators.NewStochasticRSICalculator(), // Initialize Stochastic RSI calculator
+		macdCalculator:          indicators.NewMACDCalculator(),          // Initialize MACD calculator
+		patternDetector:         NewCandlestickPatternDetector(),         // Initialize pattern detector
+		maxMACDRun:              DefaultMaxMACDRun,                       // Accept opposing MACD runs of up to 5 candles
+		stochRSIParams:          indicators.DefaultStochasticRSIParams(), // Use the standard 14/14/3/3 Stochastic RSI
+		stochRSIThresholds:      DefaultStochRSIThresholds(),             // Use the classic 30/70 levels
+		orderBlocks:             DefaultOrderBlockRules(),                // Detect zones without requiring confluence
+		emaPeriods:              DefaultEMAPeriods(),                     // Use the classic 20/50/100/200 EMA stack
+	}
+}
+
+// RequiredCandles returns the candles the configured indicators need to warm up: the longest lookback of the EMAs,
+// MACD, Stochastic RSI, and order block search plus WarmupCandles
+func (s *SAPANStrategy) RequiredCandles() int {
+	longest := s.MinimumCandles()
+	if lookback := s.orderBlocks.Lookback + orderBlockATRPeriod + 2; lookback > longest {
+		longest = lookback
+	}
+	return longest + WarmupCandles
+}
+
+// MinimumCandles returns the candles a setup needs: the longest lookback of the EMA stack, MACD, and Stochastic RSI
+// The default stack needs the package's MinimumCandles; a faster stack, e.g. for crypto pairs, needs fewer
+func (s *SAPANStrategy) MinimumCandles() int {
+	longest := s.emaPeriods[len(s.emaPeriods)-1]
+	for _, lookback := range []int{macdSlow + macdSignal, s.stochRSIParams.MinPrices()} {
+		if lookback > longest {
+			longest = lookback
+		}
+	}
+	return longest
+}
+
+// EMAPeriods returns the periods of the EMA stack, fastest first
+func (s *SAPANStrategy) EMAPeriods() []int {
+	return s.emaPeriods
+}
+
+// SetConfirmationRules changes how strictly the candle after a reversal or pinbar must confirm it
+// Set the rules before validating; the default is DefaultConfirmationRules
+func (s *SAPANStrategy) SetConfirmationRules(rules ConfirmationRules) {
+	s.patternDetector.SetConfirmationRules(rules)
+}
+
+// SetPatternThresholds changes the pinbar shape and tail pierce thresholds, as ratios or ATR multiples
+// Set the thresholds before validating; the default is DefaultPatternThresholds
+func (s *SAPANStrategy) SetPatternThresholds(thresholds PatternThresholds) {
+	s.patternDetector.SetPatternThresholds(thresholds)
+}
+
+// SetPatternEnabled enables or disables one reversal pattern; a side whose patterns are all disabled never validates
+func (s *SAPANStrategy) SetPatternEnabled(pattern PatternType, enabled bool) {
+	s.patternDetector.SetPatternEnabled(pattern, enabled)
+}
+
+// SetEMAReference selects the EMA reversal tails must pierce as support or resistance; the default is ExtremeEMA
+func (s *SAPANStrategy) SetEMAReference(reference EMAReference) {
+	s.patternDetector.SetEMAReference(reference)
+}
+
+// SetEMAPeriods sets the EMA stack the trend order rule and pattern support and resistance use; the default is DefaultEMAPeriods
+// Periods must be positive and strictly ascending, with at least two of them; other stacks are ignored
+func (s *SAPANStrategy) SetEMAPeriods(periods []int) {
+	if len(periods) < 2 {
+		return
+	}
+	for i, period := range periods {
+		if period <= 0 || (i > 0 && period <= periods[i-1]) {
+			return
+		}
+	}
+	s.emaPeriods = append([]int(nil), periods...)
+}
+
+// SetTimeStop sets the holding period, in candles after the entry candle, stamped on every trade plan
+// A trade that reached neither its stop nor its target by then exits at the close; 0 (the default) disables the time stop
+func (s *SAPANStrategy) SetTimeStop(candles int) {
+	if candles < 0 {
+		candles = 0
+	}
+	s.timeStop = candles
+}
+
+// SetMaxMACDRun sets how many candles the opposing MACD market (bear for Long, bull for Short) may have lasted
+// 0 requires MACD to be on the setup's side of its signal line; the default is DefaultMaxMACDRun
+func (s *SAPANStrategy) SetMaxMACDRun(candles int) {
+	s.maxMACDRun = candles
+}
+
+// SetStochasticRSIParams sets the lengths and mode of the Stochastic RSI; the default is 14/14/3/3
+// Every length must be positive and the RSI period small enough to leave room for the stochastic within MinimumCandles
+func (s *SAPANStrategy) SetStochasticRSIParams(params indicators.StochasticRSIParams) {
+	s.stochRSIParams = params
+}
+
+// SetStochRSIThresholds sets the Stochastic RSI levels and crossover lookbacks; the default is DefaultStochRSIThresholds
+func (s *SAPANStrategy) SetStochRSIThresholds(thresholds StochRSIThresholds) {
+	s.stochRSIThresholds = thresholds
+}
+
+// SetOrderBlockRules sets how order block zones are detected and whether setups need zone confluence
+// The default is DefaultOrderBlockRules; a zero Lookback disables zones
+func (s *SAPANStrategy) SetOrderBlockRules(rules OrderBlockRules) {
+	s.orderBlocks = rules
+}
+
+// ValidationResult contains the result of strategy validation for a single stock
+// This structure holds all validation results and provides detailed feedback about the analysis
+type ValidationResult struct {
+	IsValid           bool        // Overall validation result (true if all conditions are met)
+	EMATrendValid     bool        // EMA trend validation result
+	StochasticValid   bool        // Stochastic RSI validation result
+	MACDValid         bool        // MACD validation result
+	MACDBullRun       int         // Candles since MACD crossed above its signal line (0 while below it)
+	MACDBearRun       int         // Candles since MACD crossed below its signal line (0 while above it)
+	StochRSIThreshold float64     // %K level the Stochastic RSI rule was checked against (oversold for Long, overbought for Short)
+	StochRSILookback  int         // Candles back the Stochastic RSI crossover was allowed to be
+	PatternValid      bool        // Candlestick pattern validation result
+	PatternType       PatternType // Type of pattern detected (if any)
+	Zone              *Zone       // Order block the reversal candle traded into (nil when it touched none)
+	ZoneConfluence    bool        // Zone holds the EMA the pattern used as support or resistance
+	Symbol            string      // Stock symbol being analyzed
+	ValidationMessage string      // Detailed message explaining the validation result
+	EMAPeriods        []int       // Periods of the EMA stack the trend and pattern rules used, fastest first
+	Score             float64     // Setup quality score from 0 to 100 (only set for valid setups)
+	TradePlan         TradePlan   // Suggested entry, stop, and target levels (only set for valid setups)
+}
+
+// ScenarioType represents the type of trading scenario being validated
+// This enum helps distinguish between Long and Short trading setups
+type ScenarioType int
+
+const (
+	LongScenario  ScenarioType = iota // Long (bullish) trading scenario
+	ShortScenario                     // Short (bearish) trading scenario
+)
+
+// String returns the name of the scenario ("Long" or "Short")
+func (s ScenarioType) String() string {
+	if s == ShortScenario {
+		return "Short"
+	}
+	return "Long"
+}
+
+// ValidateLongSetup validates if the given stock data meets SAPAN long setup criteria
+// This method checks all conditions required for a bullish (long) trading setup
+// Returns ValidationResult with detailed information about the validation
+// Note: Long scenario has priority over Short scenario
+func (s *SAPANStrategy) ValidateLongSetup(symbol string, candles []models.Candle) ValidationResult {
+	return s.validateSetup(symbol, candles, LongScenario)
+}
+
+// ValidateShortSetup validates if the given stock data meets SAPAN short setup criteria
+// This method checks all conditions required for a bearish (short) trading setup
+// Returns ValidationResult with detailed information about the validation
+// Note: Short scenario is only considered if Long scenario is not valid
+func (s *SAPANStrategy) ValidateShortSetup(symbol string, candles []models.Candle) ValidationResult {
+	return s.validateSetup(symbol, candles, ShortScenario)
+}
+
+// validateSetup validates setup for both long and short scenarios
+// This is the core validation method that orchestrates all technical analysis checks
+// It computes the indicators once, then validates EMA trends, Stochastic RSI, MACD, and candlestick patterns
+func (s *SAPANStrategy) validateSetup(symbol string, candles []models.Candle, scenario ScenarioType) ValidationResult {
+	result := ValidationResult{
+		Symbol:     symbol,
+		EMAPeriods: s.emaPeriods,
+	}
+
+	if len(candles) < s.MinimumCandles() {
+		result.ValidationMessage = "Insufficient data for analysis"
+		return result
+	}
+	buffers := snapshotPool.Get().(*snapshotBuffers)
+	defer snapshotPool.Put(buffers)
+	snapshot := s.snapshot(candles, buffers)
+	result.MACDBullRun, result.MACDBearRun = snapshot.MACD.BullRunLength(), snapshot.MACD.BearRunLength()
+	result.StochRSIThreshold, result.StochRSILookback = s.stochRSIThreshold(scenario)
+
+	// Validate EMA trend based on scenario
+	if scenario == LongScenario {
+		result.EMATrendValid = s.validateEMATrend(snapshot)
+		if !result.EMATrendValid {
+			result.ValidationMessage = fmt.Sprintf("EMA trend not in uptrend order (%s)", emaOrder(s.emaPeriods, " > "))
+			return result
+		}
+	} else {
+		result.EMATrendValid = s.validateEMADowntrend(snapshot)
+		if !result.EMATrendValid {
+			result.ValidationMessage = fmt.Sprintf("EMA trend not in downtrend order (%s)", emaOrder(s.emaPeriods, " < "))
+			return result
+		}
+	}
+
+	// Validate Stochastic RSI based on scenario
+	if scenario == LongScenario {
+		result.StochasticValid = s.validateStochasticRSILong(snapshot)
+		if !result.StochasticValid {
+			result.ValidationMessage = "Stochastic RSI not in oversold region with crossover"
+			return result
+		}
+	} else {
+		result.StochasticValid = s.validateStochasticRSIShort(snapshot)
+		if !result.StochasticValid {
+			result.ValidationMessage = "Stochastic RSI not in overbought region with crossover"
+			return result
+		}
+	}
+
+	// Validate MACD based on scenario
+	if scenario == LongScenario {
+		result.MACDValid = s.validateMACDLong(snapshot)
+		if !result.MACDValid {
+			result.ValidationMessage = fmt.Sprintf("MACD not in bull market or bear market exceeds %d candlesticks", s.maxMACDRun)
+			return result
+		}
+	} else {
+		result.MACDValid = s.validateMACDShort(snapshot)
+		if !result.MACDValid {
+			result.ValidationMessage = fmt.Sprintf("MACD not in bear market or bull market exceeds %d candlesticks", s.maxMACDRun)
+			return result
+		}
+	}
+
+	// Validate candlestick pattern
+	result.PatternType = s.patternDetector.DetectPatterns(candles, snapshot)
+
+	if scenario == LongScenario {
+		result.PatternValid = (result.PatternType == Long2CandlestickReversal || result.PatternType == LongPinbarReversal)
+		if !result.PatternValid {
+			result.ValidationMessage = "Long reversal pattern not detected"
+			return result
+		}
+	} else {
+		result.PatternValid = (result.PatternType == Short2CandlestickReversal || result.PatternType == ShortPinbarReversal)
+		if !result.PatternValid {
+			result.ValidationMessage = "Short reversal pattern not detected"
+			return result
+		}
+	}
+
+	// Check the reversal against order block zones
+	result.Zone, result.ZoneConfluence = s.touchedZone(candles, snapshot, scenario)
+	if s.orderBlocks.RequireConfluence && !result.ZoneConfluence {
+		result.ValidationMessage = fmt.Sprintf("%s reversal not at an order block holding the EMA", scenario)
+		return result
+	}
+
+	result.IsValid = true
+	result.TradePlan = buildTradePlan(candles, scenario)
+	result.TradePlan.TimeStop = s.timeStop
+	result.Score = scoreSetup(candles, scenario, snapshot, s.stochRSIThresholds)
+	if scenario == LongScenario {
+		result.ValidationMessage = "All SAPAN long strategy conditions met"
+	} else {
+		result.ValidationMessage = "All SAPAN short strategy conditions met"
+	}
+	return result
+}
+
+// validateEMATrend validates EMA trend according to SAPAN rules for Long scenario
+// Checks if EMAs are in uptrend order, fastest above slowest: 20 > 50 > 100 > 200 by default
+func (s *SAPANStrategy) validateEMATrend(snapshot IndicatorSnapshot) bool {
+	return snapshot.EMAUptrend()
+}
+
+// validateEMADowntrend validates EMA downtrend according to SAPAN rules for Short scenario
+// Checks if EMAs are in downtrend order, fastest below slowest: 20 < 50 < 100 < 200 by default
+func (s *SAPANStrategy) validateEMADowntrend(snapshot IndicatorSnapshot) bool {
+	return snapshot.EMADowntrend()
+}
+
+// validateStochasticRSILong validates Stochastic RSI for long scenario
+// Checks if Stochastic RSI is oversold (below the oversold level) with a bullish crossover within the Long lookback
+func (s *SAPANStrategy) validateStochasticRSILong(snapshot IndicatorSnapshot) bool {
+	t := s.stochRSIThresholds
+	return snapshot.StochRSI.K < t.Oversold && snapshot.StochRSI.CrossedFromBelow(t.Oversold, t.LongLookback)
+}
+
+// validateStochasticRSIShort validates Stochastic RSI for short scenario
+// Checks if Stochastic RSI is overbought (above the overbought level) with a bullish crossover within the Short lookback
+func (s *SAPANStrategy) validateStochasticRSIShort(snapshot IndicatorSnapshot) bool {
+	t := s.stochRSIThresholds
+	return snapshot.StochRSI.K > t.Overbought && snapshot.StochRSI.CrossedFromBelow(t.Oversold, t.ShortLookback)
+}
+
+// stochRSIThreshold returns the %K level and crossover lookback the Stochastic RSI rule of a scenario uses
+func (s *SAPANStrategy) stochRSIThreshold(scenario ScenarioType) (float64, int) {
+	threshold, lookback := s.stochRSIThresholds.Overbought, s.stochRSIThresholds.ShortLookback
+	if scenario == LongScenario {
+		threshold, lookback = s.stochRSIThresholds.Oversold, s.stochRSIThresholds.LongLookback
+	}
+	if lookback < 1 {
+		lookback = 1 // The crossover must at least complete on the newest candle
+	}
+	return threshold, lookback
+}
+
+// validateMACDLong validates MACD for long scenario
+// Checks if in bull market OR bear market has lasted at most maxMACDRun candlesticks
+func (s *SAPANStrategy) validateMACDLong(snapshot IndicatorSnapshot) bool {
+	return snapshot.MACD.BearRunLength() <= s.maxMACDRun
+}
+
+// validateMACDShort validates MACD for short scenario
+// Checks if in bear market OR bull market has lasted at most maxMACDRun candlesticks
+func (s *SAPANStrategy) validateMACDShort(snapshot IndicatorSnapshot) bool {
+	return snapshot.MACD.BullRunLength() <= s.maxMACDRun
+}
+
+// appendClosingPrices appends the closing prices of candles to dst for technical analysis
+// This helper method converts candle data to a slice of closing prices for indicator calculations
+func (s *SAPANStrategy) appendClosingPrices(dst []float64, candles []models.Candle) []float64 {
+	for _, candle := range candles {
+		dst = append(dst, candle.Close) // Extract closing price from each candle
+	}
+	return dst
+}
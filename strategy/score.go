@@ -0,0 +1,59 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import "github.com/erhankrygt/sapan/models"
+
+// Score weights for the individual quality components (they sum to 1)
+const (
+	tailWeight         = 0.3 // Weight of the rejection tail on the reversal candle
+	confirmationWeight = 0.3 // Weight of how decisively the confirmation candle closed
+	trendWeight        = 0.2 // Weight of the fastest/slowest EMA spread
+	momentumWeight     = 0.2 // Weight of how deep Stochastic RSI %K sits in its zone
+)
+
+// scoreSetup rates a validated setup from 0 to 100 so signals can be ranked against each other
+// The score only compares setups that already passed every rule; it is not a probability of success
+func scoreSetup(candles []models.Candle, scenario ScenarioType, snapshot IndicatorSnapshot, thresholds StochRSIThresholds) float64 {
+	if len(candles) < 2 {
+		return 0
+	}
+
+	reversal := candles[len(candles)-2]     // Reversal or pinbar candle
+	confirmation := candles[len(candles)-1] // Confirmation candle
+	reversalRange := reversal.Range()
+	if reversalRange <= 0 || confirmation.Close <= 0 {
+		return 0
+	}
+
+	fastest, slowest, stochK := snapshot.FastestEMA(), snapshot.SlowestEMA(), snapshot.StochRSI.K
+	var tail, follow, trend, momentum float64
+	if scenario == LongScenario {
+		tail = reversal.LowerWick() / reversalRange                     // Lower wick share
+		follow = (confirmation.Close - reversal.High) / reversalRange   // Close beyond reversal high
+		trend = (fastest - slowest) / confirmation.Close * 10           // 10% spread scores fully
+		momentum = (thresholds.Oversold - stochK) / thresholds.Oversold // Deeper oversold scores higher
+	} else {
+		tail = reversal.UpperWick() / reversalRange                                 // Upper wick share
+		follow = (reversal.Low - confirmation.Close) / reversalRange                // Close beyond reversal low
+		trend = (slowest - fastest) / confirmation.Close * 10                       // 10% spread scores fully
+		momentum = (stochK - thresholds.Overbought) / (100 - thresholds.Overbought) // Deeper overbought scores higher
+	}
+
+	score := tailWeight*clamp01(tail) +
+		confirmationWeight*clamp01(follow) +
+		trendWeight*clamp01(trend) +
+		momentumWeight*clamp01(momentum)
+	return score * 100
+}
+
+// clamp01 limits a value to the [0, 1] range
+func clamp01(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
@@ -0,0 +1,109 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import (
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+	"sync"
+)
+
+// Indicator parameters of the SAPAN rules
+const (
+	macdFast   = 50  // Fast EMA period of MACD
+	macdSlow   = 100 // Slow EMA period of MACD
+	macdSignal = 9   // Signal line period of MACD
+)
+
+// IndicatorSnapshot holds every indicator value the SAPAN rules read for one candle history
+// It is computed once per validation and shared by the rules, the pattern detector, and the score
+type IndicatorSnapshot struct {
+	Closes     []float64                      // Closing prices the indicators were computed from
+	EMAPeriods []int                          // Periods of the EMA stack, fastest first (20/50/100/200 by default)
+	EMAs       []float64                      // EMA of the newest close for each period of EMAPeriods
+	RSI        []float64                      // Rolling RSI series Stochastic RSI ranges over (14-period by default)
+	StochRSI   indicators.StochasticRSIResult // Stochastic RSI (14, 14, 3, 3 by default) of the newest close
+	MACD       indicators.MACDSeries          // MACD (50, 100, 9) after every close
+}
+
+// snapshotBuffers holds the slices a snapshot's series are stored in so validations can reuse them
+type snapshotBuffers struct {
+	closes []float64               // Backing array of Closes
+	emas   []float64               // Backing array of EMAs
+	rsi    []float64               // Backing array of RSI
+	macd   []indicators.MACDResult // Backing array of MACD.Results
+}
+
+// snapshotPool recycles snapshot buffers between validations, which never let a snapshot outlive them
+var snapshotPool = sync.Pool{
+	New: func() any { return new(snapshotBuffers) },
+}
+
+// Snapshot computes the indicators of a candle history in one pass per indicator
+// The returned series are freshly allocated and safe to keep
+func (s *SAPANStrategy) Snapshot(candles []models.Candle) IndicatorSnapshot {
+	return s.snapshot(candles, &snapshotBuffers{})
+}
+
+// snapshot computes a snapshot with its series stored in buffers, growing them as needed
+// The snapshot refers to the buffers, so it must not be used after they go back to the pool
+func (s *SAPANStrategy) snapshot(candles []models.Candle, buffers *snapshotBuffers) IndicatorSnapshot {
+	closes := s.appendClosingPrices(buffers.closes[:0], candles)
+	emas := buffers.emas[:0]
+	for _, period := range s.emaPeriods {
+		emas = append(emas, s.emaCalculator.Calculate(closes, period))
+	}
+	snapshot := IndicatorSnapshot{
+		Closes:     closes,
+		EMAPeriods: s.emaPeriods,
+		EMAs:       emas,
+		RSI:        s.stochasticRSICalculator.AppendRSISeries(buffers.rsi[:0], closes, s.stochRSIParams.RSIPeriod),
+		MACD:       s.macdCalculator.AppendSeries(buffers.macd, closes, macdFast, macdSlow, macdSignal),
+	}
+	buffers.closes, buffers.emas, buffers.rsi, buffers.macd = snapshot.Closes, snapshot.EMAs, snapshot.RSI, snapshot.MACD.Results
+	if len(closes) >= s.stochRSIParams.MinPrices() {
+		params := s.stochRSIParams
+		params.CrossoverLookback = s.stochRSIThresholds.LongLookback
+		if s.stochRSIThresholds.ShortLookback > params.CrossoverLookback {
+			params.CrossoverLookback = s.stochRSIThresholds.ShortLookback // Search far enough back for both scenarios
+		}
+		snapshot.StochRSI = s.stochasticRSICalculator.CalculateFromRSIWithParams(snapshot.RSI, params)
+	}
+	return snapshot
+}
+
+// EMAUptrend reports whether every EMA of the stack is above the next slower one (20 > 50 > 100 > 200 by default)
+func (i IndicatorSnapshot) EMAUptrend() bool {
+	for k := 1; k < len(i.EMAs); k++ {
+		if i.EMAs[k-1] <= i.EMAs[k] {
+			return false
+		}
+	}
+	return len(i.EMAs) > 0
+}
+
+// EMADowntrend reports whether every EMA of the stack is below the next slower one (20 < 50 < 100 < 200 by default)
+func (i IndicatorSnapshot) EMADowntrend() bool {
+	for k := 1; k < len(i.EMAs); k++ {
+		if i.EMAs[k-1] >= i.EMAs[k] {
+			return false
+		}
+	}
+	return len(i.EMAs) > 0
+}
+
+// FastestEMA returns the EMA of the shortest period of the stack (0 for an empty stack)
+func (i IndicatorSnapshot) FastestEMA() float64 {
+	if len(i.EMAs) == 0 {
+		return 0
+	}
+	return i.EMAs[0]
+}
+
+// SlowestEMA returns the EMA of the longest period of the stack (0 for an empty stack)
+func (i IndicatorSnapshot) SlowestEMA() float64 {
+	if len(i.EMAs) == 0 {
+		return 0
+	}
+	return i.EMAs[len(i.EMAs)-1]
+}
@@ -0,0 +1,70 @@
+package strategy_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/erhankrygt/sapan/data"
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// TestStochRSIThresholds checks that results record the levels they were checked against and that wider
+// thresholds and lookbacks accept every setup the defaults accept, and more
+func TestStochRSIThresholds(t *testing.T) {
+	store := data.NewCandleStore(filepath.Join("testdata", "candles"), "daily")
+	strict, lenient := strategy.NewSAPANStrategy(), strategy.NewSAPANStrategy()
+	lenient.SetStochRSIThresholds(strategy.StochRSIThresholds{Oversold: 50, Overbought: 50, LongLookback: 5, ShortLookback: 3})
+
+	widened := 0
+	for _, symbol := range []string{"UPTREND", "DOWNTREND", "SIDEWAYS", "PINBAR", "PULLBACK"} {
+		candleData, err := store.Load(symbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for end := strategy.MinimumCandles; end <= len(candleData.Candles); end++ {
+			history := candleData.Candles[:end]
+			for _, scenario := range []strategy.ScenarioType{strategy.LongScenario, strategy.ShortScenario} {
+				strictResult, lenientResult := strict.ValidateLongSetup(symbol, history), lenient.ValidateLongSetup(symbol, history)
+				wantStrict, wantLenient := 30.0, 5
+				if scenario == strategy.ShortScenario {
+					strictResult, lenientResult = strict.ValidateShortSetup(symbol, history), lenient.ValidateShortSetup(symbol, history)
+					wantStrict, wantLenient = 70, 3
+				}
+				if strictResult.StochRSIThreshold != wantStrict || strictResult.StochRSILookback != 1 {
+					t.Fatalf("%s %s at %d: default thresholds recorded as %v/%d, want %v/1", symbol, scenario, end,
+						strictResult.StochRSIThreshold, strictResult.StochRSILookback, wantStrict)
+				}
+				if lenientResult.StochRSIThreshold != 50 || lenientResult.StochRSILookback != wantLenient {
+					t.Fatalf("%s %s at %d: thresholds recorded as %v/%d, want 50/%d", symbol, scenario, end,
+						lenientResult.StochRSIThreshold, lenientResult.StochRSILookback, wantLenient)
+				}
+				if !strictResult.EMATrendValid {
+					continue // The Stochastic RSI rule was not reached
+				}
+				if strictResult.StochasticValid && !lenientResult.StochasticValid {
+					t.Errorf("%s %s at %d: wider thresholds rejected a setup the defaults accept", symbol, scenario, end)
+				}
+				if lenientResult.StochasticValid && !strictResult.StochasticValid {
+					widened++
+				}
+			}
+		}
+	}
+	if widened == 0 {
+		t.Fatal("the fixtures never exercise the Stochastic RSI thresholds")
+	}
+}
+
+// TestRequiredCandles checks that the requested history grows with the longest configured lookback
+func TestRequiredCandles(t *testing.T) {
+	sapanStrategy := strategy.NewSAPANStrategy()
+	if got, want := sapanStrategy.RequiredCandles(), strategy.MinimumCandles+strategy.WarmupCandles; got != want {
+		t.Errorf("RequiredCandles() = %d with the defaults, want %d", got, want)
+	}
+
+	sapanStrategy.SetStochasticRSIParams(indicators.StochasticRSIParams{RSIPeriod: 150, StochPeriod: 100, KSmoothing: 3, DSmoothing: 3})
+	if got, want := sapanStrategy.RequiredCandles(), 254+strategy.WarmupCandles; got != want {
+		t.Errorf("RequiredCandles() = %d with a 150/100/3/3 Stochastic RSI, want %d", got, want)
+	}
+}
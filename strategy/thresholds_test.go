@@ -0,0 +1,68 @@
+package strategy_test
+
+import (
+	"testing"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// longPinbar returns quiet candles with a true range of 1 followed by a pinbar candidate piercing support at 100
+// and a confirmation candle
+func longPinbar(pinbar models.Candle) []models.Candle {
+	var candles []models.Candle
+	for i := 0; i < 14; i++ {
+		candles = append(candles, models.Candle{Open: 100.5, High: 101, Low: 100, Close: 100.5})
+	}
+	return append(candles, pinbar, models.Candle{Open: 100.9, High: 102, Low: 100, Close: 101.8})
+}
+
+func TestPatternThresholds(t *testing.T) {
+	classic := models.Candle{Open: 100.5, High: 101, Low: 97, Close: 100.8} // Body 8% and lower wick 88% of the range
+	stubby := models.Candle{Open: 99.3, High: 101, Low: 97, Close: 100.8}   // Body 38% and lower wick 58% of the range
+	withATR := func(body, wick, pierce float64) strategy.PatternThresholds {
+		thresholds := strategy.DefaultPatternThresholds()
+		thresholds.BodyATR, thresholds.WickATR, thresholds.PierceATR = body, wick, pierce
+		return thresholds
+	}
+
+	tests := []struct {
+		name       string
+		pinbar     models.Candle
+		thresholds strategy.PatternThresholds
+		want       bool
+	}{
+		{"classic pinbar by default", classic, strategy.DefaultPatternThresholds(), true},
+		{"stubby candle fails the ratios", stubby, strategy.DefaultPatternThresholds(), false},
+		{"stubby candle passes ATR thresholds", stubby, withATR(2, 1.5, 0), true},
+		{"body above the ATR limit", classic, withATR(0.1, 0, 0), false},
+		{"wick below the ATR minimum", classic, withATR(0, 5, 0), false},
+		{"pierce within the ATR depth", classic, withATR(0, 0, 1), true},
+		{"pierce short of the ATR depth", classic, withATR(0, 0, 4), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := strategy.NewCandlestickPatternDetector()
+			detector.SetPatternThresholds(test.thresholds)
+			if got := detector.DetectLongPinbarReversal(longPinbar(test.pinbar), 110, 108, 104, 100); got != test.want {
+				t.Errorf("DetectLongPinbarReversal = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReversalPierceDepth(t *testing.T) {
+	confirmation := models.Candle{Open: 103, High: 105, Low: 100, Close: 104.5}
+	for _, test := range []struct {
+		pierceATR float64
+		want      bool
+	}{{0, true}, {0.2, true}, {3, false}} {
+		detector := strategy.NewCandlestickPatternDetector()
+		thresholds := strategy.DefaultPatternThresholds()
+		thresholds.PierceATR = test.pierceATR
+		detector.SetPatternThresholds(thresholds)
+		if got := detector.DetectLong2CandlestickReversal(longReversal(confirmation), 110, 108, 104, 100); got != test.want {
+			t.Errorf("pierce depth %g ATR: DetectLong2CandlestickReversal = %v, want %v", test.pierceATR, got, test.want)
+		}
+	}
+}
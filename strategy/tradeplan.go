@@ -0,0 +1,62 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import "github.com/erhankrygt/sapan/models"
+
+// defaultRewardMultiple is the reward-to-risk ratio used to place the profit target
+const defaultRewardMultiple = 2.0
+
+// TradePlan contains the price levels suggested for a validated setup
+// Entry is a stop order beyond the confirmation candle, Stop sits beyond the reversal candle's tail
+type TradePlan struct {
+	Entry    float64 // Entry trigger price (buy stop for Long, sell stop for Short)
+	Stop     float64 // Protective stop-loss price
+	Target   float64 // Profit target price
+	TimeStop int     // Candles after the entry candle before the trade exits at the close (0 holds until stop or target)
+}
+
+// Risk returns the distance between entry and stop (always positive for a valid plan)
+func (p TradePlan) Risk() float64 {
+	return abs(p.Entry - p.Stop)
+}
+
+// RiskReward returns the reward-to-risk ratio of the plan (0 if the plan has no risk)
+func (p TradePlan) RiskReward() float64 {
+	risk := p.Risk()
+	if risk == 0 {
+		return 0
+	}
+	return abs(p.Target-p.Entry) / risk
+}
+
+// buildTradePlan derives entry, stop, and target levels from the last two candles
+// The second-to-last candle is the reversal (or pinbar) candle and the last one is the confirmation candle
+func buildTradePlan(candles []models.Candle, scenario ScenarioType) TradePlan {
+	if len(candles) < 2 {
+		return TradePlan{}
+	}
+
+	reversal := candles[len(candles)-2]     // Reversal or pinbar candle
+	confirmation := candles[len(candles)-1] // Confirmation candle
+
+	// Levels are rounded to the price tick so they match what a broker accepts; entries and stops round
+	// away from the candle so the break and the protection are never tighter than the candles imply
+	if scenario == LongScenario {
+		entry := models.RoundPriceUp(confirmation.High)                    // Enter on a break of the confirmation high
+		stop := models.RoundPriceDown(min(reversal.Low, confirmation.Low)) // Protect below the reversal tail
+		return TradePlan{
+			Entry:  entry,
+			Stop:   stop,
+			Target: models.RoundPrice(entry + (entry-stop)*defaultRewardMultiple),
+		}
+	}
+
+	entry := models.RoundPriceDown(confirmation.Low)                   // Enter on a break of the confirmation low
+	stop := models.RoundPriceUp(max(reversal.High, confirmation.High)) // Protect above the reversal tail
+	return TradePlan{
+		Entry:  entry,
+		Stop:   stop,
+		Target: models.RoundPrice(entry - (stop-entry)*defaultRewardMultiple),
+	}
+}
@@ -0,0 +1,135 @@
+// Package strategy provides the core SAPAN trading strategy implementation
+// This package contains the main strategy logic, pattern detection, and validation methods
+package strategy
+
+import (
+	"github.com/erhankrygt/sapan/indicators"
+	"github.com/erhankrygt/sapan/models"
+	"time"
+)
+
+// orderBlockATRPeriod is the ATR period impulsive moves are measured against
+const orderBlockATRPeriod = 14
+
+// ZoneKind tells demand zones (bullish order blocks) from supply zones (bearish order blocks)
+type ZoneKind int
+
+const (
+	DemandZone ZoneKind = iota // Last bearish candle before an impulsive move up
+	SupplyZone                 // Last bullish candle before an impulsive move down
+)
+
+// String returns the name of the zone kind ("Demand" or "Supply")
+func (k ZoneKind) String() string {
+	if k == SupplyZone {
+		return "Supply"
+	}
+	return "Demand"
+}
+
+// Zone is an order block: the range of the candle an impulsive move started from
+// Price returning to the zone is expected to meet the orders left behind by the move
+type Zone struct {
+	Kind    ZoneKind  // Demand or supply
+	Date    time.Time // Time of the origin candle
+	Low     float64   // Low of the origin candle
+	High    float64   // High of the origin candle
+	Impulse float64   // Size of the move away from the zone in multiples of the 14-period ATR
+	Age     int       // Candles between the origin candle and the newest candle
+}
+
+// Contains reports whether a price lies within the zone
+func (z Zone) Contains(price float64) bool {
+	return price >= z.Low && price <= z.High
+}
+
+// OrderBlockRules tunes order block detection and whether setups need zone confluence
+// The zero value disables detection; start from DefaultOrderBlockRules
+type OrderBlockRules struct {
+	Lookback          int     // Candles searched back for origin candles (0 disables detection)
+	ImpulseATR        float64 // Smallest move, in multiples of the 14-period ATR, that counts as impulsive
+	ImpulseCandles    int     // Candles the impulsive move may take to cover that distance
+	RequireConfluence bool    // Reject setups whose reversal tail did not touch a zone holding the EMA support or resistance
+}
+
+// DefaultOrderBlockRules returns detection over the last 50 candles of moves of 2 ATR within 3 candles, without
+// requiring confluence
+func DefaultOrderBlockRules() OrderBlockRules {
+	return OrderBlockRules{Lookback: 50, ImpulseATR: 2, ImpulseCandles: 3}
+}
+
+// DetectOrderBlocks finds the zones of the last rules.Lookback candles that price has not closed through since, newest first
+// An origin candle is a bearish (demand) or bullish (supply) candle followed directly by a candle of the other color,
+// after which the close moves at least rules.ImpulseATR ATRs beyond the origin candle within rules.ImpulseCandles
+func DetectOrderBlocks(candles []models.Candle, rules OrderBlockRules) []Zone {
+	if rules.Lookback <= 0 || rules.ImpulseCandles <= 0 {
+		return nil
+	}
+	atr := indicators.NewATRCalculator().Calculate(candles, orderBlockATRPeriod)
+	if atr <= 0 {
+		return nil
+	}
+
+	var zones []Zone
+	first := len(candles) - rules.Lookback
+	if first < 0 {
+		first = 0
+	}
+	for i := len(candles) - 2; i >= first; i-- {
+		origin, next := candles[i], candles[i+1]
+		end := i + rules.ImpulseCandles
+		if end >= len(candles) {
+			end = len(candles) - 1
+		}
+		zone := Zone{Date: origin.Time(), Low: origin.Low, High: origin.High, Age: len(candles) - 1 - i}
+		switch {
+		case origin.IsBearish() && next.IsBullish():
+			zone.Kind = DemandZone
+			for _, candle := range candles[i+1 : end+1] {
+				zone.Impulse = max(zone.Impulse, (candle.Close-origin.High)/atr)
+			}
+		case origin.IsBullish() && next.IsBearish():
+			zone.Kind = SupplyZone
+			for _, candle := range candles[i+1 : end+1] {
+				zone.Impulse = max(zone.Impulse, (origin.Low-candle.Close)/atr)
+			}
+		default:
+			continue
+		}
+		if zone.Impulse >= rules.ImpulseATR && !closedThrough(zone, candles[i+1:]) {
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+// closedThrough reports whether any candle closed beyond the far side of a zone, which invalidates it
+func closedThrough(zone Zone, candles []models.Candle) bool {
+	for _, candle := range candles {
+		if (zone.Kind == DemandZone && candle.Close < zone.Low) || (zone.Kind == SupplyZone && candle.Close > zone.High) {
+			return true
+		}
+	}
+	return false
+}
+
+// touchedZone returns the newest zone of the setup's side whose range the reversal candle traded into, and whether
+// the zone holds the EMA the pattern used as support or resistance
+// Zones are detected on the candles before the reversal candle so the setup itself cannot create or break them
+func (s *SAPANStrategy) touchedZone(candles []models.Candle, snapshot IndicatorSnapshot, scenario ScenarioType) (*Zone, bool) {
+	if len(candles) < 3 {
+		return nil, false
+	}
+	reversal := candles[len(candles)-2]
+	kind, level := DemandZone, s.patternDetector.supportEMA(reversal, snapshot.EMAs)
+	if scenario == ShortScenario {
+		kind, level = SupplyZone, s.patternDetector.resistanceEMA(reversal, snapshot.EMAs)
+	}
+	for _, zone := range DetectOrderBlocks(candles[:len(candles)-2], s.orderBlocks) {
+		if zone.Kind == kind && reversal.Low <= zone.High && reversal.High >= zone.Low {
+			zone.Age += 2 // Count the reversal and confirmation candles
+			return &zone, zone.Contains(level)
+		}
+	}
+	return nil, false
+}
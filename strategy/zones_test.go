@@ -0,0 +1,82 @@
+package strategy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/models"
+	"github.com/erhankrygt/sapan/strategy"
+)
+
+// rallyCandles returns a quiet range of alternating candles followed by a bearish origin candle and a rally away from it
+func rallyCandles() []models.Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var candles []models.Candle
+	add := func(open, high, low, close float64) {
+		candles = append(candles, models.Candle{Date: start.AddDate(0, 0, len(candles)), Open: open, High: high, Low: low, Close: close})
+	}
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			add(100, 101, 99.5, 100.5)
+		} else {
+			add(100.5, 101, 99.5, 100)
+		}
+	}
+	add(100.5, 100.8, 99.5, 99.8) // Origin candle
+	add(100, 103.2, 99.9, 103)
+	add(103, 106.2, 102.9, 106)
+	return candles
+}
+
+// TestDetectOrderBlocks checks that the origin of an impulsive move becomes a zone until price closes through it
+func TestDetectOrderBlocks(t *testing.T) {
+	candles := rallyCandles()
+	rules := strategy.DefaultOrderBlockRules()
+
+	zones := strategy.DetectOrderBlocks(candles, rules)
+	if len(zones) != 1 {
+		t.Fatalf("found %d zones, want 1: %+v", len(zones), zones)
+	}
+	zone := zones[0]
+	if zone.Kind != strategy.DemandZone || zone.Low != 99.5 || zone.High != 100.8 || zone.Age != 2 {
+		t.Errorf("zone = %+v, want a demand zone from 99.5 to 100.8 aged 2 candles", zone)
+	}
+	if zone.Impulse < rules.ImpulseATR {
+		t.Errorf("impulse = %.2f ATR, want at least %.2f", zone.Impulse, rules.ImpulseATR)
+	}
+	if !zone.Contains(100) || zone.Contains(101) {
+		t.Errorf("Contains does not match the zone range %.2f-%.2f", zone.Low, zone.High)
+	}
+
+	rules.ImpulseATR = 10
+	if zones := strategy.DetectOrderBlocks(candles, rules); len(zones) != 0 {
+		t.Errorf("found %d zones with a 10 ATR impulse, want none", len(zones))
+	}
+
+	retest := append(rallyCandles(), models.Candle{Date: candles[len(candles)-1].Date.AddDate(0, 0, 1), Open: 104, High: 104.5, Low: 100.2, Close: 100.5})
+	if zones := strategy.DetectOrderBlocks(retest, strategy.DefaultOrderBlockRules()); len(zones) != 1 {
+		t.Errorf("found %d zones after a retest that closed inside the zone, want 1", len(zones))
+	}
+	broken := append(rallyCandles(), models.Candle{Date: candles[len(candles)-1].Date.AddDate(0, 0, 1), Open: 104, High: 104.5, Low: 98.8, Close: 99})
+	if zones := strategy.DetectOrderBlocks(broken, strategy.DefaultOrderBlockRules()); len(zones) != 0 {
+		t.Errorf("found %d zones after a close below the zone, want none", len(zones))
+	}
+
+	if zones := strategy.DetectOrderBlocks(candles, strategy.OrderBlockRules{}); zones != nil {
+		t.Errorf("found %d zones with detection disabled, want none", len(zones))
+	}
+}
+
+// TestDetectSupplyZone checks that supply zones mirror demand zones
+func TestDetectSupplyZone(t *testing.T) {
+	candles := rallyCandles()
+	for i := range candles {
+		c := &candles[i]
+		c.Open, c.High, c.Low, c.Close = 200-c.Open, 200-c.Low, 200-c.High, 200-c.Close
+	}
+
+	zones := strategy.DetectOrderBlocks(candles, strategy.DefaultOrderBlockRules())
+	if len(zones) != 1 || zones[0].Kind != strategy.SupplyZone || zones[0].Low != 99.2 || zones[0].High != 100.5 {
+		t.Fatalf("zones = %+v, want one supply zone from 99.2 to 100.5", zones)
+	}
+}
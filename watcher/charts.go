@@ -0,0 +1,83 @@
+package watcher
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ChartLinks holds deep links that open the chart of a signal on charting sites
+type ChartLinks struct {
+	TradingView string `json:"tradingview"`     // TradingView chart with the exchange prefix and the candle interval of the scan
+	Yahoo       string `json:"yahoo,omitempty"` // Yahoo Finance chart (empty for crypto pairs, which Yahoo lists under other names)
+}
+
+// tradingViewExchanges maps listing exchanges onto TradingView exchange prefixes
+var tradingViewExchanges = map[string]string{
+	"NASDAQ":    "NASDAQ",
+	"NYSE":      "NYSE",
+	"NYSE ARCA": "AMEX",
+	"NYSEARCA":  "AMEX",
+	"NYSE MKT":  "AMEX",
+	"AMEX":      "AMEX",
+	"BATS":      "CBOE",
+	"BIST":      "BIST",
+	"LSE":       "LSE",
+	"XETRA":     "XETR",
+	"EURONEXT":  "EURONEXT",
+	"TSX":       "TSX",
+}
+
+// tradingViewSuffixes maps Yahoo-style market suffixes onto TradingView exchange prefixes
+var tradingViewSuffixes = map[string]string{
+	".IS": "BIST",
+	".L":  "LSE",
+	".DE": "XETR",
+	".PA": "EURONEXT",
+	".TO": "TSX",
+}
+
+// tradingViewIntervals maps SAPAN timeframes onto TradingView chart intervals
+var tradingViewIntervals = map[string]string{
+	"daily":   "D",
+	"weekly":  "W",
+	"monthly": "M",
+	"1min":    "1",
+	"5min":    "5",
+	"15min":   "15",
+	"30min":   "30",
+	"60min":   "60",
+}
+
+// NewChartLinks builds the chart links of a symbol scanned from provider at timeframe
+// Yahoo-style suffixes such as ".IS" are kept for Yahoo and turned into the exchange prefix on TradingView;
+// an unknown exchange leaves the prefix out so TradingView picks the primary listing
+func NewChartLinks(symbol, exchange, provider, timeframe string) ChartLinks {
+	symbol = strings.ToUpper(symbol)
+	ticker, prefix := symbol, tradingViewExchanges[strings.ToUpper(exchange)]
+	if dot := strings.LastIndex(symbol, "."); dot > 0 {
+		if suffixPrefix, ok := tradingViewSuffixes[symbol[dot:]]; ok {
+			ticker = symbol[:dot]
+			if prefix == "" {
+				prefix = suffixPrefix
+			}
+		}
+	}
+	if provider == "binance" {
+		prefix = "BINANCE"
+	}
+	if prefix != "" {
+		ticker = prefix + ":" + ticker
+	}
+
+	interval, ok := tradingViewIntervals[timeframe]
+	if !ok {
+		interval = "D"
+	}
+	links := ChartLinks{
+		TradingView: "https://www.tradingview.com/chart/?symbol=" + url.QueryEscape(ticker) + "&interval=" + interval,
+	}
+	if provider != "binance" {
+		links.Yahoo = "https://finance.yahoo.com/chart/" + url.PathEscape(symbol)
+	}
+	return links
+}
@@ -0,0 +1,129 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DiffEntry identifies a single watch list item in a diff
+type DiffEntry struct {
+	Symbol string // Stock ticker symbol
+	Side   string // Trading side (LongSide or ShortSide)
+}
+
+// WatchListDiff describes how the watch list changed between the previous run and the current one
+// New signals are the ones worth notifying about; persisted and disappeared signals are informational
+type WatchListDiff struct {
+	New         []DiffEntry // Detected in this run but absent from the previous watch list
+	Persisted   []DiffEntry // Present in the previous watch list and detected again in this run
+	Disappeared []DiffEntry // Present in the previous watch list but not detected in this run
+}
+
+// Clone returns an independent copy of the watch list (thread-safe)
+// This is used to keep the previous run's state around while the current run updates the manager
+func (w *WatchListManager) Clone() *WatchListManager {
+	clone := NewWatchListManager()
+	for _, entry := range w.GetEntries() {
+		entry := entry
+		clone.entries[entryKey{symbol: entry.Symbol, side: entry.Side}] = &entry
+	}
+	return clone
+}
+
+// Diff compares the previous watch list with the signals detected since runStart in the current one
+// Entries are reported per symbol and side, sorted alphabetically for stable output
+func Diff(previous, current *WatchListManager, runStart time.Time) WatchListDiff {
+	before := make(map[DiffEntry]bool)
+	for _, entry := range previous.GetEntries() {
+		before[DiffEntry{Symbol: entry.Symbol, Side: entry.Side}] = true
+	}
+
+	detected := make(map[DiffEntry]bool)
+	for _, entry := range current.GetEntries() {
+		if !entry.DetectedAt.Before(runStart) {
+			detected[DiffEntry{Symbol: entry.Symbol, Side: entry.Side}] = true
+		}
+	}
+
+	var diff WatchListDiff
+	for entry := range detected {
+		if before[entry] {
+			diff.Persisted = append(diff.Persisted, entry)
+		} else {
+			diff.New = append(diff.New, entry)
+		}
+	}
+	for entry := range before {
+		if !detected[entry] {
+			diff.Disappeared = append(diff.Disappeared, entry)
+		}
+	}
+
+	sortDiffEntries(diff.New)
+	sortDiffEntries(diff.Persisted)
+	sortDiffEntries(diff.Disappeared)
+	return diff
+}
+
+// Remove deletes the watch list entry for the given symbol and side (thread-safe)
+// Subscribers receive an EntryRemoved event when an entry was actually removed
+func (w *WatchListManager) Remove(symbol, side string) {
+	w.mutex.Lock()
+	key := entryKey{symbol: symbol, side: side}
+	entry, exists := w.entries[key]
+	if exists {
+		delete(w.entries, key)
+	}
+	w.mutex.Unlock()
+
+	if exists {
+		w.publish(EntryRemoved, *entry)
+	}
+}
+
+// Annotate applies update to the entry for the given symbol and side (thread-safe)
+// Subscribers receive an EntryAnnotated event; it returns false when no such entry exists
+func (w *WatchListManager) Annotate(symbol, side string, update func(*WatchListEntry)) bool {
+	w.mutex.Lock()
+	entry, exists := w.entries[entryKey{symbol: symbol, side: side}]
+	var snapshot WatchListEntry
+	if exists {
+		update(entry)
+		snapshot = *entry
+	}
+	w.mutex.Unlock()
+
+	if exists {
+		w.publish(EntryAnnotated, snapshot)
+	}
+	return exists
+}
+
+// Print displays the diff grouped into new, persisted, and disappeared signals
+func (d WatchListDiff) Print() {
+	fmt.Println("Watch List Changes Since Previous Run:")
+	printDiffSection("New", d.New)
+	printDiffSection("Persisted", d.Persisted)
+	printDiffSection("Disappeared", d.Disappeared)
+}
+
+// printDiffSection prints a single labelled section of a diff
+func printDiffSection(label string, entries []DiffEntry) {
+	fmt.Printf("  %s (%d):\n", label, len(entries))
+	for _, entry := range entries {
+		fmt.Printf("    %s %s\n", entry.Side, entry.Symbol)
+	}
+}
+
+// sortDiffEntries orders diff entries by side and then symbol
+func sortDiffEntries(entries []DiffEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Side != entries[j].Side {
+			return entries[i].Side < entries[j].Side
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+}
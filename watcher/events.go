@@ -0,0 +1,80 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import "time"
+
+// WatchListEventType describes what happened to a watch list entry
+type WatchListEventType int
+
+const (
+	EntryAdded     WatchListEventType = iota // A setup appeared on the watch list for the first time
+	EntryUpdated                             // A listed setup was detected again
+	EntryRemoved                             // A setup was removed from the watch list
+	EntryAnnotated                           // A listed setup gained metadata after detection (e.g. a correlation flag)
+)
+
+// String returns a human-readable name for the event type
+func (t WatchListEventType) String() string {
+	switch t {
+	case EntryAdded:
+		return "added"
+	case EntryUpdated:
+		return "updated"
+	case EntryRemoved:
+		return "removed"
+	case EntryAnnotated:
+		return "annotated"
+	default:
+		return "unknown"
+	}
+}
+
+// WatchListEvent is delivered to subscribers whenever the watch list changes
+type WatchListEvent struct {
+	Type  WatchListEventType // What happened to the entry
+	Entry WatchListEntry     // Copy of the entry after the change (before removal for EntryRemoved)
+	Time  time.Time          // Time the change happened
+}
+
+// subscription pairs a subscriber callback with the identifier used to unsubscribe it
+type subscription struct {
+	id      int                  // Identifier returned to the subscriber
+	handler func(WatchListEvent) // Callback invoked for every event
+}
+
+// Subscribe registers a callback invoked for every watch list change (thread-safe)
+// Callbacks run synchronously on the goroutine that changed the list, possibly from several workers at once,
+// so they must be quick and safe for concurrent use. The returned function removes the subscription.
+func (w *WatchListManager) Subscribe(handler func(WatchListEvent)) func() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.nextSubscriberID++
+	id := w.nextSubscriberID
+	w.subscribers = append(w.subscribers, subscription{id: id, handler: handler})
+
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		for i, sub := range w.subscribers {
+			if sub.id == id {
+				w.subscribers = append(w.subscribers[:i:i], w.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// publish delivers an event to every subscriber
+// It must be called without holding the mutex so subscribers can query the watch list
+func (w *WatchListManager) publish(eventType WatchListEventType, entry WatchListEntry) {
+	w.mutex.RLock()
+	subscribers := w.subscribers
+	w.mutex.RUnlock()
+
+	event := WatchListEvent{Type: eventType, Entry: entry, Time: time.Now().UTC()}
+	for _, sub := range subscribers {
+		sub.handler(event)
+	}
+}
@@ -0,0 +1,37 @@
+package watcher_test
+
+import (
+	"fmt"
+
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func ExampleWatchListManager_RecordSignal() {
+	watchList := watcher.NewWatchListManager()
+	watchList.Subscribe(func(event watcher.WatchListEvent) {
+		fmt.Println(event.Type, event.Entry.Side, event.Entry.Symbol)
+	})
+
+	watchList.RecordSignal(watcher.Signal{Symbol: "AAPL", Side: watcher.LongSide, Score: 82, Entry: 190.5, Stop: 185, Target: 201.5})
+	watchList.RecordSignal(watcher.Signal{Symbol: "AAPL", Side: watcher.LongSide, Score: 85, Entry: 191, Stop: 185, Target: 203})
+
+	entry, _ := watchList.GetEntry("AAPL", watcher.LongSide)
+	fmt.Printf("%d entry, detected %d times, R:R %.1f\n", watchList.GetCount(), entry.Detections, entry.RiskReward())
+	// Output:
+	// added Long AAPL
+	// updated Long AAPL
+	// 1 entry, detected 2 times, R:R 2.0
+}
+
+func ExampleNewChartLinks() {
+	stock := watcher.NewChartLinks("THYAO.IS", "BIST", "alphavantage", "daily")
+	fmt.Println(stock.TradingView)
+	fmt.Println(stock.Yahoo)
+
+	pair := watcher.NewChartLinks("btcusdt", "", "binance", "60min")
+	fmt.Println(pair.TradingView, pair.Yahoo == "")
+	// Output:
+	// https://www.tradingview.com/chart/?symbol=BIST%3ATHYAO&interval=D
+	// https://finance.yahoo.com/chart/THYAO.IS
+	// https://www.tradingview.com/chart/?symbol=BINANCE%3ABTCUSDT&interval=60 true
+}
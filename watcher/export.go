@@ -0,0 +1,84 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"io"
+	"strconv"
+)
+
+// csvHeader lists the columns written by ExportCSV in order
+// Sector and industry follow the trade levels so spreadsheets can pivot the export by group; listing columns come last
+var csvHeader = []string{"symbol", "side", "pattern", "score", "entry", "stop", "target", "date", "sector", "industry",
+	"exchange", "currency", "country", "isin"}
+
+// ExportCSV writes the watch list to a spreadsheet-friendly CSV file (thread-safe)
+// Each row holds one setup with its pattern, score, and trade levels, ready to import into a trading journal
+func (w *WatchListManager) ExportCSV(path string) error {
+	var buffer bytes.Buffer
+	if err := w.WriteCSV(&buffer); err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(path, buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to export watch list: %v", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the watch list as CSV with a header row to out (thread-safe)
+func (w *WatchListManager) WriteCSV(out io.Writer) error {
+	writer := csv.NewWriter(out)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+	for _, entry := range w.GetEntries() {
+		if err := writer.Write(csvRecord(entry)); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %v", entry.Symbol, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to encode CSV: %v", err)
+	}
+	return nil
+}
+
+// csvRecord converts a watch list entry into a CSV row matching csvHeader
+// The date column is the setup's candle date, falling back to the detection date when unknown
+func csvRecord(entry WatchListEntry) []string {
+	date := entry.CandleDate
+	if date.IsZero() {
+		date = entry.DetectedAt
+	}
+
+	return []string{
+		entry.Symbol,
+		entry.Side,
+		entry.Pattern,
+		strconv.FormatFloat(entry.Score, 'f', 1, 64),
+		formatPrice(entry.Entry),
+		formatPrice(entry.Stop),
+		formatPrice(entry.Target),
+		date.Format("2006-01-02"),
+		entry.Sector,
+		entry.Industry,
+		entry.Exchange,
+		entry.Currency,
+		entry.Country,
+		entry.ISIN,
+	}
+}
+
+// formatPrice renders a price with four decimals, leaving the cell empty when the level is unknown
+func formatPrice(price float64) string {
+	if price == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(price, 'f', 4, 64)
+}
@@ -0,0 +1,122 @@
+package watcher
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SignalFeatures holds the numeric features describing a setup at detection time, keyed by feature name
+// They are stored with the signal so a scoring model can be trained on them once outcomes are known
+type SignalFeatures map[string]float64
+
+// Value encodes the features as JSON for the database (empty features are stored as an empty string)
+func (f SignalFeatures) Value() (driver.Value, error) {
+	if len(f) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(map[string]float64(f))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan decodes features stored by Value
+func (f *SignalFeatures) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported features value %T", src)
+	}
+	if len(data) == 0 {
+		*f = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]float64)(f))
+}
+
+// sqliteSectorSchema creates the table holding the latest strength of every sector per market section
+const sqliteSectorSchema = `
+CREATE TABLE IF NOT EXISTS sector_strength (
+	section    TEXT NOT NULL DEFAULT '',
+	sector     TEXT NOT NULL,
+	strength   REAL NOT NULL,
+	updated_at TEXT NOT NULL,
+	PRIMARY KEY (section, sector)
+);
+`
+
+// migrateSectorStrength rebuilds a sector_strength table keyed by sector alone, created before sections shared the
+// database, so every section keeps its own strengths; the existing rows belong to the empty section
+func migrateSectorStrength(db *sql.DB) error {
+	exists, err := sqliteColumnExists(db, "sector_strength", "section")
+	if err != nil || exists {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op after a successful commit
+	for _, statement := range []string{
+		`ALTER TABLE sector_strength RENAME TO sector_strength_unsectioned`,
+		sqliteSectorSchema,
+		`INSERT INTO sector_strength (sector, strength, updated_at) SELECT sector, strength, updated_at FROM sector_strength_unsectioned`,
+		`DROP TABLE sector_strength_unsectioned`,
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SaveSectorStrength replaces the stored strength of the given sectors in the store's section
+func (s *SQLiteSignalStore) SaveSectorStrength(strength map[string]float64, updatedAt time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save sector strength: %v", err)
+	}
+	defer tx.Rollback() // No-op after a successful commit
+
+	for sector, value := range strength {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO sector_strength (section, sector, strength, updated_at) VALUES (?, ?, ?, ?)`,
+			s.section, sector, value, formatSQLiteTime(updatedAt)); err != nil {
+			return fmt.Errorf("failed to save strength of %s: %v", sector, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save sector strength: %v", err)
+	}
+	return nil
+}
+
+// SectorStrength returns the stored strength of every sector in the store's section
+func (s *SQLiteSignalStore) SectorStrength() (map[string]float64, error) {
+	rows, err := s.db.Query(`SELECT sector, strength FROM sector_strength WHERE section = ?`, s.section)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sector strength: %v", err)
+	}
+	defer rows.Close()
+
+	strength := make(map[string]float64)
+	for rows.Next() {
+		var sector string
+		var value float64
+		if err := rows.Scan(&sector, &value); err != nil {
+			return nil, fmt.Errorf("failed to read sector strength: %v", err)
+		}
+		strength[sector] = value
+	}
+	return strength, rows.Err()
+}
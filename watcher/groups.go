@@ -0,0 +1,111 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// unknownGroup labels entries whose sector or industry metadata is missing
+const unknownGroup = "Unknown"
+
+// EntryGroup holds the watch list entries that share a sector or industry
+type EntryGroup struct {
+	Name       string           // Sector or industry name
+	LongCount  int              // Number of Long setups in the group
+	ShortCount int              // Number of Short setups in the group
+	Entries    []WatchListEntry // Entries in the group, best score first
+}
+
+// GroupBySector groups the watch list by sector, largest group first (thread-safe)
+func (w *WatchListManager) GroupBySector() []EntryGroup {
+	return groupEntries(w.GetEntries(), func(entry WatchListEntry) string { return entry.Sector })
+}
+
+// GroupByIndustry groups the watch list by industry, largest group first (thread-safe)
+func (w *WatchListManager) GroupByIndustry() []EntryGroup {
+	return groupEntries(w.GetEntries(), func(entry WatchListEntry) string { return entry.Industry })
+}
+
+// groupEntries buckets entries by the key returned from keyOf
+// Groups are ordered by size, then alphabetically; entries inside a group are ordered by score
+func groupEntries(entries []WatchListEntry, keyOf func(WatchListEntry) string) []EntryGroup {
+	index := make(map[string]*EntryGroup)
+	for _, entry := range entries {
+		name := strings.TrimSpace(keyOf(entry))
+		if name == "" {
+			name = unknownGroup
+		}
+
+		group, exists := index[name]
+		if !exists {
+			group = &EntryGroup{Name: name}
+			index[name] = group
+		}
+		group.Entries = append(group.Entries, entry)
+		if entry.Side == LongSide {
+			group.LongCount++
+		} else {
+			group.ShortCount++
+		}
+	}
+
+	groups := make([]EntryGroup, 0, len(index))
+	for _, group := range index {
+		group.Entries = TopEntries(group.Entries, 0, RankByScore)
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Entries) != len(groups[j].Entries) {
+			return len(groups[i].Entries) > len(groups[j].Entries)
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// PrintGroupedWatchList prints a per-sector summary followed by the setups in each sector (thread-safe)
+// The summary reads like "3 Longs in Technology, 2 in Energy" so sector concentration is visible at a glance
+func (w *WatchListManager) PrintGroupedWatchList() {
+	groups := w.GroupBySector()
+
+	fmt.Println("Watch List by Sector:")
+	if len(groups) == 0 {
+		fmt.Println("  No valid SAPAN setups found")
+		return
+	}
+
+	fmt.Printf("  Longs: %s\n", sideSummary(groups, LongSide))
+	fmt.Printf("  Shorts: %s\n", sideSummary(groups, ShortSide))
+
+	for _, group := range groups {
+		fmt.Printf("\n  %s (%d long / %d short):\n", group.Name, group.LongCount, group.ShortCount)
+		for _, entry := range group.Entries {
+			industry := entry.Industry
+			if industry == "" {
+				industry = unknownGroup
+			}
+			fmt.Printf("    %-5s %-6s score %.1f | %s\n", entry.Side, entry.Symbol, entry.Score, industry)
+		}
+	}
+}
+
+// sideSummary renders "3 in Technology, 2 in Energy" for one side, or "none" when the side is empty
+func sideSummary(groups []EntryGroup, side string) string {
+	var parts []string
+	for _, group := range groups {
+		count := group.LongCount
+		if side == ShortSide {
+			count = group.ShortCount
+		}
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d in %s", count, group.Name))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,132 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Outcome statuses recorded for tracked signals
+const (
+	OutcomeTarget       = "target"        // Entry triggered and the target was hit before the stop
+	OutcomeStop         = "stop"          // Entry triggered and the stop was hit before the target
+	OutcomeOpen         = "open"          // Entry triggered but neither level was hit in the tracking window
+	OutcomeTimeStop     = "time_stop"     // Entry triggered and neither level was hit within the plan's time stop
+	OutcomeNotTriggered = "not_triggered" // Price never reached the entry level in the tracking window
+)
+
+// SignalOutcome records what happened after a stored signal was detected
+// The embedded Signal carries the original setup so outcomes can be grouped by pattern, sector, or score
+type SignalOutcome struct {
+	Signal                   // The signal this outcome belongs to (Signal.ID links the two)
+	Status         string    // One of the Outcome* statuses
+	EntryTriggered bool      // Whether price reached the entry level
+	EntryDate      time.Time // Date of the candle that triggered the entry
+	ExitDate       time.Time // Date of the candle that hit the stop or target (or the last candle if open)
+	ExitPrice      float64   // Price the trade exited at (or was marked at if open)
+	RMultiple      float64   // Result expressed in multiples of the initial risk
+	BarsHeld       int       // Number of candles between entry and exit
+	EvaluatedAt    time.Time // Time the outcome was computed
+}
+
+// sqliteOutcomeSchema creates the outcome table linked one-to-one with signals
+const sqliteOutcomeSchema = `
+CREATE TABLE IF NOT EXISTS signal_outcomes (
+	signal_id       INTEGER PRIMARY KEY REFERENCES signals (id),
+	status          TEXT    NOT NULL,
+	entry_triggered INTEGER NOT NULL DEFAULT 0,
+	entry_date      TEXT    NOT NULL DEFAULT '',
+	exit_date       TEXT    NOT NULL DEFAULT '',
+	exit_price      REAL    NOT NULL DEFAULT 0,
+	r_multiple      REAL    NOT NULL DEFAULT 0,
+	bars_held       INTEGER NOT NULL DEFAULT 0,
+	evaluated_at    TEXT    NOT NULL
+);
+`
+
+// RecordOutcome stores (or replaces) the outcome of a signal
+func (s *SQLiteSignalStore) RecordOutcome(outcome SignalOutcome) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO signal_outcomes (signal_id, status, entry_triggered, entry_date, exit_date,
+			exit_price, r_multiple, bars_held, evaluated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		outcome.ID, outcome.Status, outcome.EntryTriggered,
+		formatOptionalSQLiteTime(outcome.EntryDate), formatOptionalSQLiteTime(outcome.ExitDate),
+		outcome.ExitPrice, outcome.RMultiple, outcome.BarsHeld, formatSQLiteTime(outcome.EvaluatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record outcome for signal %d: %v", outcome.ID, err)
+	}
+	return nil
+}
+
+// SignalsAwaitingOutcome returns stored signals detected before the cutoff that have no outcome yet
+func (s *SQLiteSignalStore) SignalsAwaitingOutcome(detectedBefore time.Time) ([]Signal, error) {
+	rows, err := s.db.Query(`SELECT `+sqliteSignalColumns("s")+`
+		FROM signals s LEFT JOIN signal_outcomes o ON o.signal_id = s.id
+		WHERE o.signal_id IS NULL AND s.detected_at < ? AND s.section = ?
+		ORDER BY s.detected_at, s.id`, formatSQLiteTime(detectedBefore), s.section)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending signals: %v", err)
+	}
+	defer rows.Close()
+
+	var signals []Signal
+	for rows.Next() {
+		signal, err := scanSignal(rows)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, signal)
+	}
+	return signals, rows.Err()
+}
+
+// QueryOutcomes returns recorded outcomes joined with their signals, filtered like QuerySignals
+func (s *SQLiteSignalStore) QueryOutcomes(query SignalQuery) ([]SignalOutcome, error) {
+	conditions, args := s.signalConditions(query, "s.")
+
+	statement := `SELECT ` + sqliteSignalColumns("s") + `,
+		o.status, o.entry_triggered, o.entry_date, o.exit_date, o.exit_price, o.r_multiple, o.bars_held, o.evaluated_at
+		FROM signals s JOIN signal_outcomes o ON o.signal_id = s.id
+		WHERE ` + strings.Join(conditions, " AND ")
+	statement += " ORDER BY s.detected_at DESC, s.id DESC"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outcomes: %v", err)
+	}
+	defer rows.Close()
+
+	var outcomes []SignalOutcome
+	for rows.Next() {
+		var outcome SignalOutcome
+		var detectedAt, candleDate, entryDate, exitDate, evaluatedAt string
+		if err := rows.Scan(append(signalScanTargets(&outcome.Signal, &detectedAt, &candleDate),
+			&outcome.Status, &outcome.EntryTriggered, &entryDate, &exitDate,
+			&outcome.ExitPrice, &outcome.RMultiple, &outcome.BarsHeld, &evaluatedAt)...); err != nil {
+			return nil, fmt.Errorf("failed to read outcome: %v", err)
+		}
+		outcome.DetectedAt = parseSQLiteTime(detectedAt)
+		outcome.CandleDate = parseSQLiteTime(candleDate)
+		outcome.EntryDate = parseSQLiteTime(entryDate)
+		outcome.ExitDate = parseSQLiteTime(exitDate)
+		outcome.EvaluatedAt = parseSQLiteTime(evaluatedAt)
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, rows.Err()
+}
+
+// formatOptionalSQLiteTime formats a timestamp, storing an empty string for the zero time
+func formatOptionalSQLiteTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatSQLiteTime(t)
+}
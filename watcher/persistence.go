@@ -0,0 +1,90 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"os"
+	"time"
+)
+
+// watchListSnapshot is the on-disk representation of the watch list
+// Long and Short maps are only read, to restore files written before entries were deduplicated
+type watchListSnapshot struct {
+	SavedAt time.Time            `json:"saved_at"`        // Time the snapshot was written
+	Entries []WatchListEntry     `json:"entries"`         // Deduplicated entries, newest first
+	Long    map[time.Time]string `json:"long,omitempty"`  // Legacy Long setups keyed by detection timestamp
+	Short   map[time.Time]string `json:"short,omitempty"` // Legacy Short setups keyed by detection timestamp
+}
+
+// Save writes the current watch list to a JSON file (thread-safe)
+// The file is written atomically so a crash mid-write never leaves a corrupted watch list behind
+func (w *WatchListManager) Save(path string) error {
+	snapshot := watchListSnapshot{
+		SavedAt: time.Now().UTC(),
+		Entries: w.GetEntries(),
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watch list: %v", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to save watch list: %v", err)
+	}
+	return nil
+}
+
+// Load replaces the current watch list with the contents of a JSON file (thread-safe)
+// The returned error wraps os.ErrNotExist when the file is missing, so callers can treat a first run gracefully
+func (w *WatchListManager) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read watch list: %w", err)
+	}
+
+	var snapshot watchListSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse watch list: %v", err)
+	}
+
+	entries := make(map[entryKey]*WatchListEntry, len(snapshot.Entries))
+	for i := range snapshot.Entries {
+		entry := snapshot.Entries[i]
+		entries[entryKey{symbol: entry.Symbol, side: entry.Side}] = &entry // Restore each entry
+	}
+	restoreLegacyList(entries, snapshot.Long, LongSide)
+	restoreLegacyList(entries, snapshot.Short, ShortSide)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.entries = entries
+	return nil
+}
+
+// restoreLegacyList folds a legacy timestamp-keyed list into deduplicated entries
+// Repeated symbols collapse into one entry spanning their first and last detection
+func restoreLegacyList(entries map[entryKey]*WatchListEntry, list map[time.Time]string, side string) {
+	for timestamp, symbol := range list {
+		key := entryKey{symbol: symbol, side: side}
+		entry, exists := entries[key]
+		if !exists {
+			entries[key] = &WatchListEntry{
+				Signal:        Signal{Symbol: symbol, Side: side, DetectedAt: timestamp},
+				FirstDetected: timestamp,
+				Detections:    1,
+			}
+			continue
+		}
+
+		entry.Detections++
+		if timestamp.After(entry.DetectedAt) {
+			entry.DetectedAt = timestamp
+		}
+		if timestamp.Before(entry.FirstDetected) {
+			entry.FirstDetected = timestamp
+		}
+	}
+}
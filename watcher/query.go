@@ -0,0 +1,74 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"strings"
+	"time"
+)
+
+// EntryFilter selects watch list entries for reports, notifiers, and the API
+// Zero-valued fields are ignored, so an empty filter matches every entry
+type EntryFilter struct {
+	Side     string    // Restrict to LongSide or ShortSide
+	Sector   string    // Restrict to a sector (case-insensitive)
+	Pattern  string    // Restrict to a pattern name (case-insensitive)
+	From     time.Time // Inclusive lower bound on the last detection time
+	To       time.Time // Exclusive upper bound on the last detection time
+	MinScore float64   // Minimum setup score
+}
+
+// Matches reports whether an entry satisfies every condition of the filter
+func (f EntryFilter) Matches(entry WatchListEntry) bool {
+	if f.Side != "" && entry.Side != f.Side {
+		return false
+	}
+	if f.Sector != "" && !strings.EqualFold(entry.Sector, f.Sector) {
+		return false
+	}
+	if f.Pattern != "" && !strings.EqualFold(entry.Pattern, f.Pattern) {
+		return false
+	}
+	if !f.From.IsZero() && entry.DetectedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.DetectedAt.Before(f.To) {
+		return false
+	}
+	return entry.Score >= f.MinScore
+}
+
+// Query returns copies of the entries matching the filter, newest first (thread-safe)
+func (w *WatchListManager) Query(filter EntryFilter) []WatchListEntry {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	result := make([]WatchListEntry, 0)
+	for _, entry := range w.entries {
+		if filter.Matches(*entry) {
+			result = append(result, *entry)
+		}
+	}
+	sortEntriesNewestFirst(result)
+	return result
+}
+
+// GetEntriesBySector returns the entries for a sector, newest first (thread-safe)
+func (w *WatchListManager) GetEntriesBySector(sector string) []WatchListEntry {
+	return w.Query(EntryFilter{Sector: sector})
+}
+
+// GetEntriesByPattern returns the entries confirmed by a pattern, newest first (thread-safe)
+func (w *WatchListManager) GetEntriesByPattern(pattern string) []WatchListEntry {
+	return w.Query(EntryFilter{Pattern: pattern})
+}
+
+// GetEntriesBetween returns the entries last detected in [from, to), newest first (thread-safe)
+func (w *WatchListManager) GetEntriesBetween(from, to time.Time) []WatchListEntry {
+	return w.Query(EntryFilter{From: from, To: to})
+}
+
+// GetEntriesWithMinScore returns the entries scoring at least minScore, newest first (thread-safe)
+func (w *WatchListManager) GetEntriesWithMinScore(minScore float64) []WatchListEntry {
+	return w.Query(EntryFilter{MinScore: minScore})
+}
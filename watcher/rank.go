@@ -0,0 +1,76 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Ranking criteria accepted by TopN
+const (
+	RankByScore      = "score"  // Highest setup score first
+	RankByVolume     = "volume" // Highest signal-candle volume first
+	RankByRiskReward = "rr"     // Highest reward-to-risk ratio first
+)
+
+// RiskReward returns the reward-to-risk ratio of the signal's trade levels (0 when levels are unknown)
+func (s Signal) RiskReward() float64 {
+	risk := math.Abs(s.Entry - s.Stop)
+	if risk == 0 {
+		return 0
+	}
+	return math.Abs(s.Target-s.Entry) / risk
+}
+
+// ValidateRankBy checks that a ranking criterion is supported
+func ValidateRankBy(by string) error {
+	switch by {
+	case RankByScore, RankByVolume, RankByRiskReward:
+		return nil
+	default:
+		return fmt.Errorf("unknown ranking %q (expected %s, %s, or %s)", by, RankByScore, RankByVolume, RankByRiskReward)
+	}
+}
+
+// TopN returns the n best entries of the watch list ranked by the given criterion (thread-safe)
+// A non-positive n returns every entry in ranked order
+func (w *WatchListManager) TopN(n int, by string) []WatchListEntry {
+	return TopEntries(w.GetEntries(), n, by)
+}
+
+// TopEntries ranks a slice of entries by the given criterion and keeps the n best
+// The input slice is not modified; ties are broken by score and then symbol for stable output
+func TopEntries(entries []WatchListEntry, n int, by string) []WatchListEntry {
+	ranked := make([]WatchListEntry, len(entries))
+	copy(ranked, entries)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := rankValue(ranked[i], by), rankValue(ranked[j], by)
+		if a != b {
+			return a > b
+		}
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Symbol < ranked[j].Symbol
+	})
+
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// rankValue extracts the value an entry is ranked by
+func rankValue(entry WatchListEntry, by string) float64 {
+	switch by {
+	case RankByVolume:
+		return float64(entry.Volume)
+	case RankByRiskReward:
+		return entry.RiskReward()
+	default:
+		return entry.Score
+	}
+}
@@ -0,0 +1,226 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RunSummary describes a single scan run
+// Summaries are stored alongside signals so trends can be reported without external tooling
+type RunSummary struct {
+	ID          int64       // Database identifier
+	StartedAt   time.Time   // Time the scan started
+	FinishedAt  time.Time   // Time the scan finished (zero while running)
+	Total       int         // Number of stocks processed
+	Successful  int         // Stocks analyzed without errors
+	Errors      int         // Stocks that failed to process
+	Valid       int         // Valid SAPAN setups found
+	LongCount   int         // Long setups found
+	ShortCount  int         // Short setups found
+	DurationSec float64     // Wall-clock duration of the run in seconds
+	Settings    RunSettings // Configuration the run used (loaded by Run only)
+}
+
+// RunSettings holds the resolved configuration of a run, keyed by setting name, with secrets masked
+// They are stored with the run so two runs can be compared on their parameters as well as their signals
+type RunSettings map[string]string
+
+// Value encodes the settings as JSON for the database (empty settings are stored as an empty string)
+func (r RunSettings) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(map[string]string(r))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan decodes settings stored by Value
+func (r *RunSettings) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported settings value %T", src)
+	}
+	if len(data) == 0 {
+		*r = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]string)(r))
+}
+
+// ErrorRate returns the share of processed stocks that failed
+func (r RunSummary) ErrorRate() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Total)
+}
+
+// WeeklyRunStats aggregates run summaries by calendar week (weeks start on Monday)
+type WeeklyRunStats struct {
+	WeekStart   time.Time // Monday of the week
+	Runs        int       // Number of finished runs in the week
+	Total       int       // Stocks processed across those runs
+	Errors      int       // Errors across those runs
+	Valid       int       // Setups found across those runs
+	LongCount   int       // Long setups found across those runs
+	ShortCount  int       // Short setups found across those runs
+	DurationSec float64   // Total run time in seconds
+}
+
+// ErrorRate returns the share of processed stocks that failed during the week
+func (w WeeklyRunStats) ErrorRate() float64 {
+	if w.Total == 0 {
+		return 0
+	}
+	return float64(w.Errors) / float64(w.Total)
+}
+
+// sqliteRunSchema creates the table holding per-run summaries
+const sqliteRunSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at   TEXT    NOT NULL,
+	finished_at  TEXT    NOT NULL DEFAULT '',
+	total        INTEGER NOT NULL DEFAULT 0,
+	successful   INTEGER NOT NULL DEFAULT 0,
+	errors       INTEGER NOT NULL DEFAULT 0,
+	valid        INTEGER NOT NULL DEFAULT 0,
+	long_count   INTEGER NOT NULL DEFAULT 0,
+	short_count  INTEGER NOT NULL DEFAULT 0,
+	duration_sec REAL    NOT NULL DEFAULT 0,
+	settings     TEXT    NOT NULL DEFAULT '',
+	section      TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_runs_started ON runs (started_at);
+`
+
+// StartRun records the start of a scan with the settings it runs with and returns the run identifier used to tag its signals
+func (s *SQLiteSignalStore) StartRun(startedAt time.Time, settings RunSettings) (int64, error) {
+	res, err := s.db.Exec(`INSERT INTO runs (started_at, settings, section) VALUES (?, ?, ?)`,
+		formatSQLiteTime(startedAt), settings, s.section)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record run start: %v", err)
+	}
+	return res.LastInsertId()
+}
+
+// FinishRun stores the final counts and duration of a run started with StartRun
+func (s *SQLiteSignalStore) FinishRun(summary RunSummary) error {
+	_, err := s.db.Exec(`
+		UPDATE runs SET finished_at = ?, total = ?, successful = ?, errors = ?, valid = ?,
+			long_count = ?, short_count = ?, duration_sec = ?
+		WHERE id = ?`,
+		formatSQLiteTime(summary.FinishedAt), summary.Total, summary.Successful, summary.Errors, summary.Valid,
+		summary.LongCount, summary.ShortCount, summary.DurationSec, summary.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record run %d: %v", summary.ID, err)
+	}
+	return nil
+}
+
+// QueryRuns returns finished runs started in [from, to), newest first (zero bounds are ignored)
+func (s *SQLiteSignalStore) QueryRuns(from, to time.Time) ([]RunSummary, error) {
+	rows, err := s.db.Query(`
+		SELECT id, started_at, finished_at, total, successful, errors, valid, long_count, short_count, duration_sec
+		FROM runs
+		WHERE finished_at != '' AND section = ? AND (? = '' OR started_at >= ?) AND (? = '' OR started_at < ?)
+		ORDER BY started_at DESC, id DESC`, s.section,
+		formatOptionalSQLiteTime(from), formatOptionalSQLiteTime(from),
+		formatOptionalSQLiteTime(to), formatOptionalSQLiteTime(to),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %v", err)
+	}
+	defer rows.Close()
+
+	var runs []RunSummary
+	for rows.Next() {
+		var run RunSummary
+		var startedAt, finishedAt string
+		if err := rows.Scan(&run.ID, &startedAt, &finishedAt, &run.Total, &run.Successful, &run.Errors,
+			&run.Valid, &run.LongCount, &run.ShortCount, &run.DurationSec); err != nil {
+			return nil, fmt.Errorf("failed to read run: %v", err)
+		}
+		run.StartedAt = parseSQLiteTime(startedAt)
+		run.FinishedAt = parseSQLiteTime(finishedAt)
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Run returns a single run of the store's section with its settings; sql.ErrNoRows is wrapped when the section
+// has no run with the identifier
+func (s *SQLiteSignalStore) Run(id int64) (RunSummary, error) {
+	run := RunSummary{ID: id}
+	var startedAt, finishedAt string
+	err := s.db.QueryRow(`
+		SELECT started_at, finished_at, total, successful, errors, valid, long_count, short_count, duration_sec, settings
+		FROM runs WHERE id = ? AND section = ?`, id, s.section,
+	).Scan(&startedAt, &finishedAt, &run.Total, &run.Successful, &run.Errors,
+		&run.Valid, &run.LongCount, &run.ShortCount, &run.DurationSec, &run.Settings)
+	if err != nil {
+		return RunSummary{}, fmt.Errorf("failed to load run %d: %w", id, err)
+	}
+	run.StartedAt = parseSQLiteTime(startedAt)
+	run.FinishedAt = parseSQLiteTime(finishedAt)
+	return run, nil
+}
+
+// WeeklyRunStats aggregates finished runs started since the given time by calendar week, oldest first
+func (s *SQLiteSignalStore) WeeklyRunStats(since time.Time) ([]WeeklyRunStats, error) {
+	rows, err := s.db.Query(`
+		SELECT date(started_at, 'weekday 0', '-6 days') AS week_start, COUNT(*), SUM(total), SUM(errors),
+			SUM(valid), SUM(long_count), SUM(short_count), SUM(duration_sec)
+		FROM runs
+		WHERE finished_at != '' AND section = ? AND started_at >= ?
+		GROUP BY week_start
+		ORDER BY week_start`, s.section, formatSQLiteTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate runs: %v", err)
+	}
+	defer rows.Close()
+
+	var weeks []WeeklyRunStats
+	for rows.Next() {
+		var week WeeklyRunStats
+		var weekStart string
+		if err := rows.Scan(&weekStart, &week.Runs, &week.Total, &week.Errors, &week.Valid,
+			&week.LongCount, &week.ShortCount, &week.DurationSec); err != nil {
+			return nil, fmt.Errorf("failed to read weekly run stats: %v", err)
+		}
+		week.WeekStart, _ = time.Parse("2006-01-02", weekStart)
+		weeks = append(weeks, week)
+	}
+	return weeks, rows.Err()
+}
+
+// PrintWeeklyRunStats displays weekly run aggregates as a compact table
+func PrintWeeklyRunStats(weeks []WeeklyRunStats) {
+	fmt.Println("Run History by Week:")
+	if len(weeks) == 0 {
+		fmt.Println("  No finished runs recorded")
+		return
+	}
+	for _, week := range weeks {
+		fmt.Printf("  %s | runs: %d | processed: %d | errors: %.1f%% | setups: %d (%d long / %d short) | time: %s\n",
+			week.WeekStart.Format("2006-01-02"), week.Runs, week.Total, week.ErrorRate()*100,
+			week.Valid, week.LongCount, week.ShortCount,
+			(time.Duration(week.DurationSec) * time.Second).String())
+	}
+}
@@ -0,0 +1,73 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import "time"
+
+// Trading sides recorded on signals
+// These mirror the Long and Short scenarios of the SAPAN strategy
+const (
+	LongSide  = "Long"  // Bullish setup
+	ShortSide = "Short" // Bearish setup
+)
+
+// Signal represents a single detected SAPAN setup with its full metadata
+// This structure is what gets recorded in the signal database for historical queries
+type Signal struct {
+	ID                int64          `json:"id,omitempty"`                 // Database identifier (zero until the signal is stored)
+	RunID             int64          `json:"run_id,omitempty"`             // Identifier of the run that detected the signal
+	Symbol            string         `json:"symbol"`                       // Stock ticker symbol
+	Name              string         `json:"name,omitempty"`               // Full company name
+	Sector            string         `json:"sector,omitempty"`             // Business sector of the stock
+	Industry          string         `json:"industry,omitempty"`           // Specific industry within the sector
+	MarketCap         float64        `json:"market_cap,omitempty"`         // Market capitalization from the stock list (zero when unknown)
+	Exchange          string         `json:"exchange,omitempty"`           // Listing exchange of the stock
+	Currency          string         `json:"currency,omitempty"`           // Currency the prices are quoted in
+	Country           string         `json:"country,omitempty"`            // Country of the listing
+	ISIN              string         `json:"isin,omitempty"`               // International Securities Identification Number
+	Side              string         `json:"side"`                         // Trading side (LongSide or ShortSide)
+	Pattern           string         `json:"pattern,omitempty"`            // Candlestick pattern that confirmed the setup
+	DetectedAt        time.Time      `json:"detected_at"`                  // Time the setup was detected
+	CandleDate        time.Time      `json:"candle_date"`                  // Date of the last candle used for the analysis
+	Close             float64        `json:"close"`                        // Closing price of the last candle
+	Volume            int64          `json:"volume"`                       // Volume of the last candle
+	Score             float64        `json:"score"`                        // Setup quality score from 0 to 100
+	Entry             float64        `json:"entry"`                        // Suggested entry trigger price
+	Stop              float64        `json:"stop"`                         // Suggested stop-loss price
+	Target            float64        `json:"target"`                       // Suggested profit target price
+	TimeStop          int            `json:"time_stop,omitempty"`          // Candles after the entry before the trade exits at the close (0 = none)
+	EMATrendValid     bool           `json:"ema_trend_valid"`              // EMA trend validation result
+	StochasticValid   bool           `json:"stochastic_valid"`             // Stochastic RSI validation result
+	MACDValid         bool           `json:"macd_valid"`                   // MACD validation result
+	PatternValid      bool           `json:"pattern_valid"`                // Candlestick pattern validation result
+	StochRSIThreshold float64        `json:"stoch_threshold,omitempty"`    // %K level the Stochastic RSI rule used (oversold for Long, overbought for Short)
+	StochRSILookback  int            `json:"stoch_lookback,omitempty"`     // Candles back the Stochastic RSI crossover was allowed to be
+	Zone              *SignalZone    `json:"zone,omitempty"`               // Order block the reversal candle traded into (nil when it touched none)
+	EMAStack          string         `json:"ema_stack,omitempty"`          // Periods of the EMA stack the setup was validated on, e.g. "20/50/100/200"
+	ValidationMessage string         `json:"validation_message,omitempty"` // Validation message produced by the strategy
+	Probability       float64        `json:"probability,omitempty"`        // Predicted chance of reaching the target before the stop (zero when not scored)
+	Features          SignalFeatures `json:"features,omitempty"`           // Model features captured at detection time
+	CorrelatedWith    string         `json:"correlated_with,omitempty"`    // Stronger signal of the same run whose returns move with this one
+	Correlation       float64        `json:"correlation,omitempty"`        // Correlation of recent returns with CorrelatedWith
+	LowConfidence     string         `json:"low_confidence,omitempty"`     // Why the candles behind the setup are doubtful, e.g. a provider cross-check (empty when trusted)
+	Charts            *ChartLinks    `json:"charts,omitempty"`             // Chart deep links (not stored in the signal database)
+}
+
+// SignalQuery describes a historical signal lookup
+// Zero-valued fields are ignored, so an empty query returns every stored signal
+type SignalQuery struct {
+	Symbol string    // Restrict to a single symbol
+	Side   string    // Restrict to LongSide or ShortSide
+	RunID  int64     // Restrict to the signals of a single run
+	From   time.Time // Inclusive lower bound on DetectedAt
+	To     time.Time // Exclusive upper bound on DetectedAt
+	Limit  int       // Maximum number of signals to return (0 = unlimited)
+}
+
+// SignalStore persists signals beyond the lifetime of a single run
+// Implementations must be safe for concurrent use by multiple workers
+type SignalStore interface {
+	RecordSignal(signal Signal) (int64, error)        // Store a signal and return its identifier
+	QuerySignals(query SignalQuery) ([]Signal, error) // Retrieve stored signals, newest first
+	Close() error                                     // Release the underlying resources
+}
@@ -0,0 +1,316 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver registered as "sqlite"
+)
+
+// sqliteTimeLayout is a fixed-width UTC layout so stored timestamps sort and compare lexicographically
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+// sqliteSchema creates the signal table and the indexes used by historical queries
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS signals (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol            TEXT    NOT NULL,
+	name              TEXT    NOT NULL DEFAULT '',
+	sector            TEXT    NOT NULL DEFAULT '',
+	industry          TEXT    NOT NULL DEFAULT '',
+	side              TEXT    NOT NULL,
+	pattern           TEXT    NOT NULL DEFAULT '',
+	detected_at       TEXT    NOT NULL,
+	candle_date       TEXT    NOT NULL,
+	close             REAL    NOT NULL DEFAULT 0,
+	volume            INTEGER NOT NULL DEFAULT 0,
+	score             REAL    NOT NULL DEFAULT 0,
+	entry             REAL    NOT NULL DEFAULT 0,
+	stop              REAL    NOT NULL DEFAULT 0,
+	target            REAL    NOT NULL DEFAULT 0,
+	run_id            INTEGER NOT NULL DEFAULT 0,
+	ema_trend_valid   INTEGER NOT NULL DEFAULT 0,
+	stochastic_valid  INTEGER NOT NULL DEFAULT 0,
+	macd_valid        INTEGER NOT NULL DEFAULT 0,
+	pattern_valid     INTEGER NOT NULL DEFAULT 0,
+	message           TEXT    NOT NULL DEFAULT '',
+	market_cap        REAL    NOT NULL DEFAULT 0,
+	probability       REAL    NOT NULL DEFAULT 0,
+	features          TEXT    NOT NULL DEFAULT '',
+	exchange          TEXT    NOT NULL DEFAULT '',
+	currency          TEXT    NOT NULL DEFAULT '',
+	country           TEXT    NOT NULL DEFAULT '',
+	isin              TEXT    NOT NULL DEFAULT '',
+	stoch_threshold   REAL    NOT NULL DEFAULT 0,
+	stoch_lookback    INTEGER NOT NULL DEFAULT 0,
+	zone              TEXT    NOT NULL DEFAULT '',
+	ema_stack         TEXT    NOT NULL DEFAULT '',
+	time_stop         INTEGER NOT NULL DEFAULT 0,
+	section           TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_signals_symbol_detected ON signals (symbol, detected_at);
+CREATE INDEX IF NOT EXISTS idx_signals_side_detected ON signals (side, detected_at);
+`
+
+// sqliteAddedColumns lists columns added after the first release of the schema
+// Databases created by older versions are upgraded in place by adding any missing column
+var sqliteAddedColumns = []struct {
+	table      string // Table the column belongs to
+	name       string // Column name
+	definition string // Column type and default
+}{
+	{"signals", "score", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "entry", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "stop", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "target", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "run_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"signals", "market_cap", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "probability", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "features", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "exchange", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "currency", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "country", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "isin", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "stoch_threshold", "REAL NOT NULL DEFAULT 0"},
+	{"signals", "stoch_lookback", "INTEGER NOT NULL DEFAULT 0"},
+	{"signals", "zone", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "ema_stack", "TEXT NOT NULL DEFAULT ''"},
+	{"signals", "time_stop", "INTEGER NOT NULL DEFAULT 0"},
+	{"signals", "section", "TEXT NOT NULL DEFAULT ''"},
+	{"runs", "settings", "TEXT NOT NULL DEFAULT ''"},
+	{"runs", "section", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// SQLiteSignalStore records signals in a local SQLite database
+// This store enables historical queries such as "all Long signals for AAPL in 2024"
+// Market sections of a multi-market run share one database; each section only sees its own signals and runs
+type SQLiteSignalStore struct {
+	db      *sql.DB // Underlying database handle
+	section string  // Market section rows are stamped with and filtered by (empty outside multi-market runs)
+}
+
+// OpenSQLiteSignalStore opens (or creates) the SQLite signal database at the given path
+// The schema is created on first use, so an empty path on disk is a valid starting point
+func OpenSQLiteSignalStore(path string) (*SQLiteSignalStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signal database: %v", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite allows a single writer, so serialize access through one connection
+
+	if _, err := db.Exec(sqliteSchema + sqliteOutcomeSchema + sqliteRunSchema + sqliteSectorSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize signal database: %v", err)
+	}
+	if err := migrateSQLiteSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to upgrade signal database: %v", err)
+	}
+	if err := migrateSectorStrength(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to upgrade signal database: %v", err)
+	}
+
+	return &SQLiteSignalStore{db: db}, nil
+}
+
+// SetSection scopes the store to one market section: signals and runs recorded from now on are stamped with it,
+// and queries only return rows of the same section. Rows recorded without a section stay with the empty section
+// Call it right after opening the store, before it is shared
+func (s *SQLiteSignalStore) SetSection(section string) {
+	s.section = section
+}
+
+// migrateSQLiteSchema adds columns that are missing from databases created by older versions
+func migrateSQLiteSchema(db *sql.DB) error {
+	for _, column := range sqliteAddedColumns {
+		exists, err := sqliteColumnExists(db, column.table, column.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		statement := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", column.table, column.name, column.definition)
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqliteColumnExists reports whether a table already has the named column
+func sqliteColumnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// RecordSignal inserts a signal and returns its database identifier
+func (s *SQLiteSignalStore) RecordSignal(signal Signal) (int64, error) {
+	zone, err := zoneValue(signal.Zone)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode zone of %s: %v", signal.Symbol, err)
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO signals (symbol, name, sector, industry, side, pattern, detected_at, candle_date,
+			close, volume, score, entry, stop, target, run_id,
+			ema_trend_valid, stochastic_valid, macd_valid, pattern_valid, message, market_cap,
+			probability, features, exchange, currency, country, isin, stoch_threshold, stoch_lookback, zone,
+			ema_stack, time_stop, section)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		signal.Symbol, signal.Name, signal.Sector, signal.Industry, signal.Side, signal.Pattern,
+		formatSQLiteTime(signal.DetectedAt), formatSQLiteTime(signal.CandleDate),
+		signal.Close, signal.Volume, signal.Score, signal.Entry, signal.Stop, signal.Target, signal.RunID,
+		signal.EMATrendValid, signal.StochasticValid, signal.MACDValid, signal.PatternValid,
+		signal.ValidationMessage, signal.MarketCap, signal.Probability, signal.Features,
+		signal.Exchange, signal.Currency, signal.Country, signal.ISIN, signal.StochRSIThreshold, signal.StochRSILookback,
+		zone, signal.EMAStack, signal.TimeStop, s.section,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record signal for %s: %v", signal.Symbol, err)
+	}
+	return res.LastInsertId()
+}
+
+// QuerySignals returns stored signals matching the query, newest first
+func (s *SQLiteSignalStore) QuerySignals(query SignalQuery) ([]Signal, error) {
+	conditions, args := s.signalConditions(query, "")
+
+	statement := `SELECT ` + sqliteSignalColumns("") + ` FROM signals WHERE ` + strings.Join(conditions, " AND ")
+	statement += " ORDER BY detected_at DESC, id DESC"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signals: %v", err)
+	}
+	defer rows.Close()
+
+	var signals []Signal
+	for rows.Next() {
+		signal, err := scanSignal(rows)
+		if err != nil {
+			return nil, err
+		}
+		signals = append(signals, signal)
+	}
+	return signals, rows.Err()
+}
+
+// signalConditions converts a query into SQL conditions and arguments, always restricted to the store's section
+// The prefix qualifies column names (e.g. "s.") when the signals table is joined
+func (s *SQLiteSignalStore) signalConditions(query SignalQuery, prefix string) ([]string, []interface{}) {
+	conditions := []string{prefix + "section = ?"}
+	args := []interface{}{s.section}
+
+	if query.Symbol != "" {
+		conditions = append(conditions, prefix+"symbol = ?")
+		args = append(args, query.Symbol)
+	}
+	if query.Side != "" {
+		conditions = append(conditions, prefix+"side = ?")
+		args = append(args, query.Side)
+	}
+	if query.RunID != 0 {
+		conditions = append(conditions, prefix+"run_id = ?")
+		args = append(args, query.RunID)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, prefix+"detected_at >= ?")
+		args = append(args, formatSQLiteTime(query.From))
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, prefix+"detected_at < ?")
+		args = append(args, formatSQLiteTime(query.To))
+	}
+	return conditions, args
+}
+
+// signalColumnNames lists the signal columns in the order scanned by signalScanTargets
+var signalColumnNames = []string{
+	"id", "symbol", "name", "sector", "industry", "side", "pattern", "detected_at", "candle_date",
+	"close", "volume", "score", "entry", "stop", "target", "run_id",
+	"ema_trend_valid", "stochastic_valid", "macd_valid", "pattern_valid", "message", "market_cap",
+	"probability", "features", "exchange", "currency", "country", "isin", "stoch_threshold", "stoch_lookback",
+	"zone", "ema_stack", "time_stop",
+}
+
+// sqliteSignalColumns returns the comma-separated signal column list, qualified by a table alias if given
+func sqliteSignalColumns(alias string) string {
+	if alias == "" {
+		return strings.Join(signalColumnNames, ", ")
+	}
+	qualified := make([]string, len(signalColumnNames))
+	for i, name := range signalColumnNames {
+		qualified[i] = alias + "." + name
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// signalScanTargets returns scan destinations matching signalColumnNames
+// Timestamps are scanned into strings and must be parsed by the caller
+func signalScanTargets(signal *Signal, detectedAt, candleDate *string) []interface{} {
+	return []interface{}{
+		&signal.ID, &signal.Symbol, &signal.Name, &signal.Sector, &signal.Industry, &signal.Side,
+		&signal.Pattern, detectedAt, candleDate, &signal.Close, &signal.Volume,
+		&signal.Score, &signal.Entry, &signal.Stop, &signal.Target, &signal.RunID,
+		&signal.EMATrendValid, &signal.StochasticValid, &signal.MACDValid, &signal.PatternValid,
+		&signal.ValidationMessage, &signal.MarketCap, &signal.Probability, &signal.Features,
+		&signal.Exchange, &signal.Currency, &signal.Country, &signal.ISIN, &signal.StochRSIThreshold, &signal.StochRSILookback,
+		zoneColumn{&signal.Zone}, &signal.EMAStack, &signal.TimeStop,
+	}
+}
+
+// scanSignal reads a single signal row selected with sqliteSignalColumns
+func scanSignal(rows *sql.Rows) (Signal, error) {
+	var signal Signal
+	var detectedAt, candleDate string
+	if err := rows.Scan(signalScanTargets(&signal, &detectedAt, &candleDate)...); err != nil {
+		return Signal{}, fmt.Errorf("failed to read signal: %v", err)
+	}
+	signal.DetectedAt = parseSQLiteTime(detectedAt)
+	signal.CandleDate = parseSQLiteTime(candleDate)
+	return signal, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteSignalStore) Close() error {
+	return s.db.Close()
+}
+
+// formatSQLiteTime converts a timestamp into the fixed-width UTC layout used for storage
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+// parseSQLiteTime converts a stored timestamp back into a UTC time (zero time if malformed)
+func parseSQLiteTime(value string) time.Time {
+	t, err := time.Parse(sqliteTimeLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
@@ -0,0 +1,110 @@
+package watcher_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func TestSectionsShareOneSignalDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.db")
+	open := func(section string) *watcher.SQLiteSignalStore {
+		store, err := watcher.OpenSQLiteSignalStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.SetSection(section)
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+	legacy, us, crypto := open(""), open("us"), open("crypto")
+
+	detectedAt := time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC)
+	for store, symbol := range map[*watcher.SQLiteSignalStore]string{legacy: "MSFT", us: "AAPL", crypto: "BTCUSDT"} {
+		if _, err := store.RecordSignal(watcher.Signal{Symbol: symbol, Side: watcher.LongSide, DetectedAt: detectedAt}); err != nil {
+			t.Fatal(err)
+		}
+		id, err := store.StartRun(detectedAt, watcher.RunSettings{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.FinishRun(watcher.RunSummary{ID: id, FinishedAt: detectedAt.Add(time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for store, want := range map[*watcher.SQLiteSignalStore]string{legacy: "MSFT", us: "AAPL", crypto: "BTCUSDT"} {
+		signals, err := store.QuerySignals(watcher.SignalQuery{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(signals) != 1 || signals[0].Symbol != want {
+			t.Errorf("signals = %+v, want only %s", signals, want)
+		}
+		runs, err := store.QueryRuns(time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) != 1 {
+			t.Errorf("%s store sees %d runs, want its own run only", want, len(runs))
+		}
+	}
+}
+
+func TestSectionsKeepTheirOwnSectorStrengthAndRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signals.db")
+
+	// A database from before sections shared it keys sector strength by sector alone
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE sector_strength (sector TEXT PRIMARY KEY, strength REAL NOT NULL, updated_at TEXT NOT NULL);
+		INSERT INTO sector_strength VALUES ('Technology', 1.5, '2024-03-01T00:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	open := func(section string) *watcher.SQLiteSignalStore {
+		store, err := watcher.OpenSQLiteSignalStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.SetSection(section)
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+	legacy, us, crypto := open(""), open("us"), open("crypto")
+
+	updatedAt := time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC)
+	if err := us.SaveSectorStrength(map[string]float64{"Technology": 2}, updatedAt); err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.SaveSectorStrength(map[string]float64{"Technology": -1}, updatedAt); err != nil {
+		t.Fatal(err)
+	}
+	for store, want := range map[*watcher.SQLiteSignalStore]float64{legacy: 1.5, us: 2, crypto: -1} {
+		strength, err := store.SectorStrength()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(strength) != 1 || strength["Technology"] != want {
+			t.Errorf("sector strength = %v, want Technology at %.1f", strength, want)
+		}
+	}
+
+	id, err := us.StartRun(updatedAt, watcher.RunSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.Run(id); err != nil {
+		t.Errorf("the us section cannot load its own run: %v", err)
+	}
+	if _, err := crypto.Run(id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("the crypto section loaded run %d of the us section (err %v)", id, err)
+	}
+}
@@ -0,0 +1,317 @@
+// Package watcher provides watch list management functionality for the SAPAN strategy
+// This package handles thread-safe storage and retrieval of trading signals
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchListEntry is a single deduplicated watch list item for a symbol and side
+// The embedded Signal always holds the most recent detection; FirstDetected remembers when the setup first appeared
+type WatchListEntry struct {
+	Signal                  // Latest signal detected for this symbol and side
+	FirstDetected time.Time `json:"first_detected"` // Time the setup was first detected
+	Detections    int       `json:"detections"`     // Number of times the setup has been detected
+}
+
+// entryKey identifies a watch list entry by symbol and side
+type entryKey struct {
+	symbol string // Stock ticker symbol
+	side   string // Trading side (LongSide or ShortSide)
+}
+
+// WatchList stores detected signals and serves the deduplicated entries
+// *WatchListManager is the production implementation; sapantest.WatchList records signals for assertions
+type WatchList interface {
+	RecordSignal(signal Signal) error                    // Record a detected signal
+	GetEntries() []WatchListEntry                        // All entries, newest first
+	GetEntry(symbol, side string) (WatchListEntry, bool) // Entry for a symbol and side
+	GetCount() int                                       // Number of entries
+}
+
+// WatchListManager manages the watch list for trading signals
+// This struct provides thread-safe operations for storing and retrieving Long and Short trading setups
+type WatchListManager struct {
+	entries          map[entryKey]*WatchListEntry // Deduplicated setups keyed by symbol and side
+	store            SignalStore                  // Optional persistent signal store (nil when disabled)
+	runID            int64                        // Identifier of the current run stamped on new signals
+	subscribers      []subscription               // Callbacks notified about watch list changes
+	nextSubscriberID int                          // Identifier assigned to the next subscription
+	displayLocation  *time.Location               // Timezone used when printing timestamps (nil prints in local time)
+	mutex            sync.RWMutex                 // Read-write mutex for thread-safe operations
+}
+
+// NewWatchListManager creates a new watch list manager instance
+// This constructor initializes an empty watch list holding both Long and Short setups
+func NewWatchListManager() *WatchListManager {
+	return &WatchListManager{
+		entries: make(map[entryKey]*WatchListEntry), // Initialize the deduplicated entry map
+	}
+}
+
+// SetSignalStore attaches a persistent signal store to the watch list (thread-safe)
+// Every signal recorded through RecordSignal is also written to this store
+func (w *WatchListManager) SetSignalStore(store SignalStore) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.store = store
+}
+
+// SetRunID sets the run identifier stamped on signals recorded from now on (thread-safe)
+func (w *WatchListManager) SetRunID(runID int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.runID = runID
+}
+
+// SetDisplayLocation sets the timezone used when printing timestamps (thread-safe)
+// Stored timestamps stay in UTC; only rendering is affected
+func (w *WatchListManager) SetDisplayLocation(location *time.Location) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.displayLocation = location
+}
+
+// formatTimestamp renders a timestamp in the display timezone with its zone abbreviation
+func (w *WatchListManager) formatTimestamp(t time.Time) string {
+	w.mutex.RLock()
+	location := w.displayLocation
+	w.mutex.RUnlock()
+
+	if location == nil {
+		location = time.Local
+	}
+	return t.In(location).Format("2006-01-02 15:04:05 MST")
+}
+
+// RecordSignal adds a detected signal to the watch list and the signal store (thread-safe)
+// This method is the metadata-aware counterpart of AddToLongWatchList and AddToShortWatchList
+func (w *WatchListManager) RecordSignal(signal Signal) error {
+	if signal.Side != LongSide && signal.Side != ShortSide {
+		return fmt.Errorf("unknown signal side %q for %s", signal.Side, signal.Symbol)
+	}
+	if signal.DetectedAt.IsZero() {
+		signal.DetectedAt = time.Now().UTC() // Stamp signals that arrive without a detection time
+	}
+
+	w.mutex.RLock()
+	store := w.store
+	if signal.RunID == 0 {
+		signal.RunID = w.runID // Tag the signal with the current run
+	}
+	w.mutex.RUnlock()
+
+	// Store the signal first so the watch list entry carries its database identifier
+	var err error
+	if store != nil {
+		var id int64
+		if id, err = store.RecordSignal(signal); err == nil {
+			signal.ID = id
+		}
+	}
+
+	w.addEntry(signal)
+	return err
+}
+
+// addEntry inserts a new entry or refreshes the existing one for the signal's symbol and side
+// Re-detections update the timestamp and metadata instead of appending a duplicate
+func (w *WatchListManager) addEntry(signal Signal) {
+	w.mutex.Lock()
+	key := entryKey{symbol: signal.Symbol, side: signal.Side}
+	entry, exists := w.entries[key]
+	if exists {
+		entry.Signal = signal // Keep the latest detection and its metadata
+		entry.Detections++
+	} else {
+		entry = &WatchListEntry{
+			Signal:        signal,
+			FirstDetected: signal.DetectedAt,
+			Detections:    1,
+		}
+		w.entries[key] = entry
+	}
+	snapshot := *entry
+	w.mutex.Unlock()
+
+	if exists {
+		w.publish(EntryUpdated, snapshot)
+	} else {
+		w.publish(EntryAdded, snapshot)
+	}
+}
+
+// AddToLongWatchList adds a symbol to the long watch list (thread-safe)
+// This method stores a Long trading setup with the current timestamp
+func (w *WatchListManager) AddToLongWatchList(symbol string) {
+	w.addEntry(Signal{Symbol: symbol, Side: LongSide, DetectedAt: time.Now().UTC()})
+}
+
+// GetLongWatchList returns the current long watch list (thread-safe)
+// This method returns a copy of the Long watch list mapping each symbol to its last detection time
+func (w *WatchListManager) GetLongWatchList() map[string]time.Time {
+	return w.sideWatchList(LongSide)
+}
+
+// PrintWatchList prints the current watch list (thread-safe)
+// This method displays both Long and Short watch lists with timestamps
+func (w *WatchListManager) PrintWatchList() {
+	longEntries := w.GetEntriesBySide(LongSide)
+	shortEntries := w.GetEntriesBySide(ShortSide)
+
+	// Print Long Watch List
+	fmt.Println("Current Long Watch List:")
+	if len(longEntries) == 0 {
+		fmt.Println("  No valid SAPAN long setups found")
+	} else {
+		for _, entry := range longEntries {
+			fmt.Printf("  %s: %s\n", w.formatTimestamp(entry.DetectedAt), entry.Symbol)
+		}
+	}
+
+	// Print Short Watch List
+	fmt.Println("\nCurrent Short Watch List:")
+	if len(shortEntries) == 0 {
+		fmt.Println("  No valid SAPAN short setups found")
+	} else {
+		for _, entry := range shortEntries {
+			fmt.Printf("  %s: %s\n", w.formatTimestamp(entry.DetectedAt), entry.Symbol)
+		}
+	}
+}
+
+// AddToShortWatchList adds a symbol to the short watch list (thread-safe)
+// This method stores a Short trading setup with the current timestamp
+func (w *WatchListManager) AddToShortWatchList(symbol string) {
+	w.addEntry(Signal{Symbol: symbol, Side: ShortSide, DetectedAt: time.Now().UTC()})
+}
+
+// GetShortWatchList returns the current short watch list (thread-safe)
+// This method returns a copy of the Short watch list mapping each symbol to its last detection time
+func (w *WatchListManager) GetShortWatchList() map[string]time.Time {
+	return w.sideWatchList(ShortSide)
+}
+
+// sideWatchList builds a symbol-to-timestamp map for one side of the watch list
+// Symbols are unique per side, while setups detected in the same run can share a timestamp
+func (w *WatchListManager) sideWatchList(side string) map[string]time.Time {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	// Create a copy to avoid race conditions
+	result := make(map[string]time.Time)
+	for key, entry := range w.entries {
+		if key.side == side {
+			result[entry.Symbol] = entry.DetectedAt // Copy each entry to the result map
+		}
+	}
+	return result
+}
+
+// GetEntries returns a copy of every watch list entry sorted by last detection, newest first (thread-safe)
+func (w *WatchListManager) GetEntries() []WatchListEntry {
+	return w.GetEntriesBySide("")
+}
+
+// GetEntriesBySide returns copies of the entries for one side, newest first (thread-safe)
+// An empty side returns entries for both sides
+func (w *WatchListManager) GetEntriesBySide(side string) []WatchListEntry {
+	return w.Query(EntryFilter{Side: side})
+}
+
+// GetEntry returns the entry for a symbol on one side (thread-safe)
+// The boolean result is false when the setup is not on the watch list
+func (w *WatchListManager) GetEntry(symbol, side string) (WatchListEntry, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	entry, exists := w.entries[entryKey{symbol: symbol, side: side}]
+	if !exists {
+		return WatchListEntry{}, false
+	}
+	return *entry, true
+}
+
+// GetLatestEntry returns the most recently detected entry for a symbol across both sides (thread-safe)
+// The boolean result is false when the symbol is not on the watch list
+func (w *WatchListManager) GetLatestEntry(symbol string) (WatchListEntry, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	var latest *WatchListEntry
+	for key, entry := range w.entries {
+		if key.symbol != symbol {
+			continue
+		}
+		if latest == nil || entry.DetectedAt.After(latest.DetectedAt) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return WatchListEntry{}, false
+	}
+	return *latest, true
+}
+
+// GetLatestEntries returns the most recently detected entry for every listed symbol (thread-safe)
+// The result is keyed by symbol, so a symbol listed on both sides appears once with its newest setup
+func (w *WatchListManager) GetLatestEntries() map[string]WatchListEntry {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	result := make(map[string]WatchListEntry)
+	for key, entry := range w.entries {
+		current, exists := result[key.symbol]
+		if !exists || entry.DetectedAt.After(current.DetectedAt) {
+			result[key.symbol] = *entry
+		}
+	}
+	return result
+}
+
+// GetCount returns the total number of items in both watch lists (thread-safe)
+// This method provides the combined count of Long and Short setups
+func (w *WatchListManager) GetCount() int {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return len(w.entries) // Total count of all setups
+}
+
+// GetLongCount returns the number of long items in the watch list (thread-safe)
+// This method provides the count of Long trading setups
+func (w *WatchListManager) GetLongCount() int {
+	return w.countSide(LongSide) // Count of Long setups
+}
+
+// GetShortCount returns the number of short items in the watch list (thread-safe)
+// This method provides the count of Short trading setups
+func (w *WatchListManager) GetShortCount() int {
+	return w.countSide(ShortSide) // Count of Short setups
+}
+
+// countSide counts the entries for one side of the watch list
+func (w *WatchListManager) countSide(side string) int {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	count := 0
+	for key := range w.entries {
+		if key.side == side {
+			count++
+		}
+	}
+	return count
+}
+
+// sortEntriesNewestFirst orders entries by last detection time, newest first, with symbol as a tiebreaker
+func sortEntriesNewestFirst(entries []WatchListEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].DetectedAt.Equal(entries[j].DetectedAt) {
+			return entries[i].DetectedAt.After(entries[j].DetectedAt)
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+}
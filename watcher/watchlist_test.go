@@ -0,0 +1,28 @@
+package watcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erhankrygt/sapan/watcher"
+)
+
+func TestSideWatchListsKeepSetupsDetectedTogether(t *testing.T) {
+	detectedAt := time.Date(2024, 3, 8, 21, 0, 0, 0, time.UTC)
+	watchList := watcher.NewWatchListManager()
+	for _, signal := range []watcher.Signal{
+		{Symbol: "AAPL", Side: watcher.LongSide, DetectedAt: detectedAt},
+		{Symbol: "MSFT", Side: watcher.LongSide, DetectedAt: detectedAt},
+		{Symbol: "TSLA", Side: watcher.ShortSide, DetectedAt: detectedAt},
+	} {
+		watchList.RecordSignal(signal)
+	}
+
+	long := watchList.GetLongWatchList()
+	if len(long) != 2 || !long["AAPL"].Equal(detectedAt) || !long["MSFT"].Equal(detectedAt) {
+		t.Errorf("long watch list = %v, want AAPL and MSFT detected at %s", long, detectedAt)
+	}
+	if short := watchList.GetShortWatchList(); len(short) != 1 || !short["TSLA"].Equal(detectedAt) {
+		t.Errorf("short watch list = %v, want TSLA detected at %s", short, detectedAt)
+	}
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SignalZone describes the order block (supply or demand zone) the reversal candle of a signal traded into
+type SignalZone struct {
+	Kind       string    `json:"kind"`       // Demand or Supply
+	Low        float64   `json:"low"`        // Bottom of the zone
+	High       float64   `json:"high"`       // Top of the zone
+	Date       time.Time `json:"date"`       // Time of the candle the zone's impulsive move started from
+	Impulse    float64   `json:"impulse"`    // Size of the move away from the zone in multiples of the ATR
+	Confluence bool      `json:"confluence"` // Zone holds the EMA the pattern used as support or resistance
+}
+
+// zoneValue encodes a zone as JSON for the database (no zone is stored as an empty string)
+func zoneValue(zone *SignalZone) (string, error) {
+	if zone == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(zone)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// zoneColumn scans a zone stored by zoneValue into a signal
+type zoneColumn struct {
+	zone **SignalZone // Zone field of the signal being scanned
+}
+
+// Scan decodes the zone column, leaving no zone for an empty value
+func (c zoneColumn) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("unsupported zone value %T", src)
+	}
+	if len(data) == 0 {
+		*c.zone = nil
+		return nil
+	}
+	var zone SignalZone
+	if err := json.Unmarshal(data, &zone); err != nil {
+		return err
+	}
+	*c.zone = &zone
+	return nil
+}
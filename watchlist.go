@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/erhankrygt/sapan/internal/fsutil"
+	"github.com/erhankrygt/sapan/watcher"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runWatchListExport writes the persisted watch list as CSV or JSON to FILE, or to stdout when FILE is omitted or "-"
+// The format defaults to the file extension (.json selects JSON) and can be forced with --format
+func runWatchListExport(args []string) int {
+	format, args := takeFlag(args, "format")
+	path := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		path, args = args[0], args[1:]
+	}
+	if format == "" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = "json"
+		}
+	}
+	if format != "csv" && format != "json" {
+		log.Printf("Invalid export format %q (expected csv or json)", format)
+		return exitConfigError
+	}
+
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	watchListManager := watcher.NewWatchListManager()
+	if err := watchListManager.Load(cfg.WatchListFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Failed to load watch list from %s: %v", cfg.WatchListFile, err)
+		return exitFailure
+	}
+
+	entries := watchListManager.GetEntries()
+	var buffer bytes.Buffer
+	var err error
+	if format == "json" {
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(entries)
+	} else {
+		err = watchListManager.WriteCSV(&buffer)
+	}
+	if err != nil {
+		log.Printf("Failed to export watch list: %v", err)
+		return exitFailure
+	}
+
+	if path == "" || path == "-" {
+		os.Stdout.Write(buffer.Bytes())
+		return exitOK
+	}
+	if err := fsutil.WriteFileAtomic(path, buffer.Bytes()); err != nil {
+		log.Printf("Failed to export watch list: %v", err)
+		return exitFailure
+	}
+	log.Printf("📤 Exported %d watch list entries to %s", len(entries), path)
+	return exitOK
+}
+
+// takeFlag removes a command-specific --name value (or --name=value) from args before configuration parsing
+// When the flag is repeated the last value wins
+func takeFlag(args []string, name string) (string, []string) {
+	values, rest := takeFlags(args, name)
+	if len(values) == 0 {
+		return "", rest
+	}
+	return values[len(values)-1], rest
+}
+
+// takeFlags removes every occurrence of a repeatable command-specific --name flag from args, returning the values in order
+func takeFlags(args []string, name string) ([]string, []string) {
+	rest := make([]string, 0, len(args))
+	var values []string
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimLeft(args[i], "-")
+		switch {
+		case !strings.HasPrefix(args[i], "-"):
+			rest = append(rest, args[i])
+		case arg == name && i+1 < len(args):
+			values = append(values, args[i+1])
+			i++
+		case strings.HasPrefix(arg, name+"="):
+			values = append(values, strings.TrimPrefix(arg, name+"="))
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return values, rest
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"github.com/erhankrygt/sapan/internal/distributed"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runWorker answers candle fetch jobs of distributed scans until interrupted
+// Each worker uses its own ALPHA_VANTAGE_API_KEY and REQUEST_DELAY_SECONDS
+func runWorker(args []string) int {
+	cfg, code, ok := loadConfig(args)
+	if !ok {
+		return code
+	}
+	if cfg.QueueURL == "" {
+		log.Printf("QUEUE_URL is required to run a distributed scan worker")
+		return exitConfigError
+	}
+	queue, err := distributed.NewQueue(cfg.QueueURL, cfg.QueuePrefix)
+	if err != nil {
+		log.Printf("Failed to open distributed scan queue: %v", err)
+		return exitConfigError
+	}
+	defer queue.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("🛰️  SAPAN worker %s waiting for jobs on queue %s", cfg.WorkerName, cfg.QueuePrefix)
+	worker := distributed.NewWorker(queue, cfg.WorkerName, cfg.APIKey, cfg.APIURL, cfg.RequestDelay)
+	worker.SetAdjusted(cfg.AdjustedSeries, cfg.AdjustedPrices)
+	answered := worker.Run(ctx)
+	log.Printf("👋 SAPAN worker stopped after %d jobs", answered)
+	return exitOK
+}